@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"os"
 	"os/signal"
 	"syscall"
 
@@ -11,9 +12,35 @@ import (
 
 func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
+	err := run(ctx, os.Args[1:])
+	stop()
 
-	if err := app.Run(ctx); err != nil {
+	if err != nil {
 		log.Println("app exited with error:", err)
+		os.Exit(1)
 	}
 }
+
+func run(ctx context.Context, args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "token":
+			return app.RunTokenCommand(ctx, args[1:], os.Stdout)
+		case "org":
+			return app.RunOrgCommand(ctx, args[1:], os.Stdout)
+		case "github":
+			return app.RunGitHubCommand(ctx, args[1:], os.Stdout)
+		case "gitlab":
+			return app.RunGitLabCommand(ctx, args[1:], os.Stdout)
+		case "slack":
+			return app.RunSlackCommand(ctx, args[1:], os.Stdout)
+		case "email":
+			return app.RunEmailCommand(ctx, args[1:], os.Stdout)
+		case "outbox":
+			return app.RunOutboxCommand(ctx, args[1:], os.Stdout)
+		case "audit":
+			return app.RunAuditCommand(ctx, args[1:], os.Stdout)
+		}
+	}
+	return app.Run(ctx)
+}
@@ -0,0 +1,210 @@
+// Package bulk serves /admin/import and /admin/export, which load and dump
+// the teams and users of an organization in one go as JSON, YAML or CSV.
+package bulk
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+// maxBody bounds imported snapshots; a few thousand users fit easily.
+const maxBody = 10 << 20
+
+type Handler struct {
+	teams *usecase.TeamUsecase
+	log   *slog.Logger
+}
+
+func NewHandler(teams *usecase.TeamUsecase, logger *slog.Logger) *Handler {
+	return &Handler{teams: teams, log: logger}
+}
+
+type errorBody struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type importResponse struct {
+	DryRun  bool           `json:"dry_run"`
+	Summary importSummary  `json:"summary"`
+	Changes []importChange `json:"changes"`
+}
+
+type importSummary struct {
+	TeamsCreated   int `json:"teams_created"`
+	UsersCreated   int `json:"users_created"`
+	UsersUpdated   int `json:"users_updated"`
+	UsersMoved     int `json:"users_moved"`
+	UsersUnchanged int `json:"users_unchanged"`
+}
+
+type importChange struct {
+	Type     string `json:"type"`
+	ID       string `json:"id"`
+	Action   string `json:"action"`
+	FromTeam string `json:"from_team,omitempty"`
+	TeamName string `json:"team_name"`
+}
+
+// Import applies the snapshot in the body, whose format is given by the
+// format query parameter or the Content-Type, JSON by default. With
+// dry_run=true it only reports what it would change.
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "use POST")
+		return
+	}
+	f, ok := requestFormat(r.URL.Query().Get("format"), r.Header.Get("Content-Type"))
+	if !ok {
+		writeError(w, http.StatusUnsupportedMediaType, domain.ErrInvalid.Error(), "send JSON, YAML or CSV")
+		return
+	}
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, domain.ErrInvalid.Error(), "dry_run must be true or false")
+			return
+		}
+		dryRun = b
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBody))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, domain.ErrInvalid.Error(), "snapshot too large")
+		return
+	}
+	teams, err := decode(f, body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, domain.ErrInvalid.Error(), "bad "+string(f)+": "+err.Error())
+		return
+	}
+
+	report, err := h.teams.Import(r.Context(), teams, dryRun)
+	if err != nil {
+		h.writeUsecaseError(w, "import", err)
+		return
+	}
+	h.log.Info("bulk: import",
+		"org", domain.OrgFromContext(r.Context()), "format", f, "dry_run", dryRun,
+		"teams_created", report.Count(domain.ImportKindTeam, domain.ImportCreate),
+		"users_created", report.Count(domain.ImportKindUser, domain.ImportCreate),
+		"users_updated", report.Count(domain.ImportKindUser, domain.ImportUpdate),
+		"users_moved", report.Count(domain.ImportKindUser, domain.ImportMove))
+
+	resp := importResponse{
+		DryRun: report.DryRun,
+		Summary: importSummary{
+			TeamsCreated:   report.Count(domain.ImportKindTeam, domain.ImportCreate),
+			UsersCreated:   report.Count(domain.ImportKindUser, domain.ImportCreate),
+			UsersUpdated:   report.Count(domain.ImportKindUser, domain.ImportUpdate),
+			UsersMoved:     report.Count(domain.ImportKindUser, domain.ImportMove),
+			UsersUnchanged: report.Count(domain.ImportKindUser, domain.ImportUnchanged),
+		},
+		Changes: []importChange{},
+	}
+	for _, c := range report.Changes {
+		if c.Action == domain.ImportUnchanged {
+			continue
+		}
+		resp.Changes = append(resp.Changes, importChange{
+			Type:     c.Kind,
+			ID:       c.ID,
+			Action:   string(c.Action),
+			FromTeam: c.FromTeam,
+			TeamName: c.TeamName,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Export writes every team and its members in the format given by the
+// format query parameter or the Accept header, JSON by default. The output
+// can be imported as is.
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "use GET")
+		return
+	}
+	f, ok := responseFormat(r.URL.Query().Get("format"), r.Header.Get("Accept"))
+	if !ok {
+		writeError(w, http.StatusNotAcceptable, domain.ErrInvalid.Error(), "format must be json, yaml or csv")
+		return
+	}
+
+	teams, err := h.teams.Export(r.Context())
+	if err != nil {
+		h.writeUsecaseError(w, "export", err)
+		return
+	}
+	w.Header().Set("Content-Type", contentTypes[f])
+	w.Header().Set("Content-Disposition", `attachment; filename="teams.`+string(f)+`"`)
+	if err := encode(w, f, teams); err != nil {
+		h.log.Error("bulk: failed to write export", "err", err)
+	}
+}
+
+// requestFormat prefers an explicit format over the Content-Type; clients
+// such as curl send form types by default, which mean JSON here.
+func requestFormat(param, contentType string) (format, bool) {
+	if param != "" {
+		return formatOf(param)
+	}
+	if f, ok := formatOf(contentType); ok {
+		return f, true
+	}
+	if contentType == "" || strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		return formatJSON, true
+	}
+	return "", false
+}
+
+// responseFormat picks the first type in Accept the handler can produce.
+func responseFormat(param, accept string) (format, bool) {
+	if param != "" {
+		return formatOf(param)
+	}
+	for _, part := range strings.Split(accept, ",") {
+		if f, ok := formatOf(part); ok {
+			return f, true
+		}
+	}
+	return formatJSON, true
+}
+
+func (h *Handler) writeUsecaseError(w http.ResponseWriter, op string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalid):
+		writeError(w, http.StatusBadRequest, domain.ErrInvalid.Error(),
+			strings.TrimPrefix(err.Error(), domain.ErrInvalid.Error()+": "))
+	case errors.Is(err, domain.ErrForbidden):
+		writeError(w, http.StatusForbidden, domain.ErrForbidden.Error(), "only admins may "+op+" teams")
+	default:
+		h.log.Error("bulk: "+op+" failed", "err", err)
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal error")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	var body errorBody
+	body.Error.Code = code
+	body.Error.Message = msg
+	writeJSON(w, status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,153 @@
+package bulk_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/bulk"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+// newHandler seeds team "backend" with u1 and u2.
+func newHandler(t *testing.T) (*bulk.Handler, *memory.UserRepo) {
+	t.Helper()
+	ctx := context.Background()
+
+	s := memory.NewStore()
+	teams, users := memory.NewTeamRepo(s), memory.NewUserRepo(s)
+	if err := teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{
+		{UserID: "u1", Username: "alice", IsActive: true, Role: domain.RoleAdmin},
+		{UserID: "u2", Username: "bob", IsActive: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return bulk.NewHandler(usecase.NewTeamUsecase(teams, users), slog.New(slog.DiscardHandler)), users
+}
+
+func serve(h http.HandlerFunc, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h(w, r)
+	return w
+}
+
+type report struct {
+	DryRun  bool           `json:"dry_run"`
+	Summary map[string]int `json:"summary"`
+	Changes []struct {
+		Type     string `json:"type"`
+		ID       string `json:"id"`
+		Action   string `json:"action"`
+		FromTeam string `json:"from_team"`
+		TeamName string `json:"team_name"`
+	} `json:"changes"`
+}
+
+func TestImportCSV(t *testing.T) {
+	h, users := newHandler(t)
+	body := "team_name,user_id,username,is_active\n" +
+		"backend,u1,alice,true\n" +
+		"frontend,u2,bob,false\n" +
+		"frontend,u3,carol,true\n" +
+		"design,,,\n"
+
+	for _, dryRun := range []bool{true, false} {
+		url := "/admin/import"
+		if dryRun {
+			url += "?dry_run=true"
+		}
+		req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "text/csv")
+		w := serve(h.Import, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("dry run %v: got %d %s", dryRun, w.Code, w.Body)
+		}
+		var got report
+		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]int{"teams_created": 2, "users_created": 1, "users_updated": 0, "users_moved": 1, "users_unchanged": 1}
+		for k, v := range want {
+			if got.Summary[k] != v {
+				t.Fatalf("dry run %v: summary %v, want %v", dryRun, got.Summary, want)
+			}
+		}
+		if got.DryRun != dryRun || len(got.Changes) != 4 || got.Changes[1].ID != "u2" || got.Changes[1].Action != "move" ||
+			got.Changes[1].FromTeam != "backend" || got.Changes[1].TeamName != "frontend" {
+			t.Fatalf("dry run %v: got %+v", dryRun, got)
+		}
+
+		u2, err := users.GetByID(context.Background(), "u2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if moved := u2.TeamName == "frontend" && !u2.IsActive; moved == dryRun {
+			t.Fatalf("dry run %v: u2 is %+v", dryRun, u2)
+		}
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	h, _ := newHandler(t)
+
+	for _, tc := range []struct{ format, contentType, contains string }{
+		{"json", "application/json", `"user_id": "u1"`},
+		{"yaml", "application/yaml", "- user_id: u1"},
+		{"csv", "text/csv", "backend,u1,alice,true,admin"},
+	} {
+		w := serve(h.Export, httptest.NewRequest(http.MethodGet, "/admin/export?format="+tc.format, nil))
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != tc.contentType || !strings.Contains(w.Body.String(), tc.contains) {
+			t.Fatalf("%s: got %d %q\n%s", tc.format, w.Code, w.Header().Get("Content-Type"), w.Body)
+		}
+
+		// Importing an export changes nothing.
+		req := httptest.NewRequest(http.MethodPost, "/admin/import?dry_run=true", w.Body)
+		req.Header.Set("Content-Type", tc.contentType)
+		w = serve(h.Import, req)
+		var got report
+		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != http.StatusOK || len(got.Changes) != 0 || got.Summary["users_unchanged"] != 2 {
+			t.Fatalf("%s reimport: got %d %+v", tc.format, w.Code, got)
+		}
+	}
+}
+
+func TestImportRejects(t *testing.T) {
+	h, _ := newHandler(t)
+
+	for name, tc := range map[string]struct {
+		contentType, body string
+		ctx               context.Context
+		status            int
+	}{
+		"unknown column": {"text/csv", "team_name,user_id,username,is_active,age\n", context.Background(), http.StatusBadRequest},
+		"missing column": {"text/csv", "team_name,user_id,username\n", context.Background(), http.StatusBadRequest},
+		"unknown field":  {"application/json", `{"teams":[{"team_name":"x","size":1}]}`, context.Background(), http.StatusBadRequest},
+		"no is_active":   {"application/yaml", "teams:\n  - team_name: x\n    members:\n      - {user_id: u9, username: z}\n", context.Background(), http.StatusBadRequest},
+		"listed twice":   {"text/csv", "team_name,user_id,username,is_active\na,u9,z,true\nb,u9,z,true\n", context.Background(), http.StatusBadRequest},
+		"empty":          {"application/json", `{"teams":[]}`, context.Background(), http.StatusBadRequest},
+		"unsupported":    {"application/xml", "<teams/>", context.Background(), http.StatusUnsupportedMediaType},
+		"not an admin":   {"application/json", `{"teams":[{"team_name":"x"}]}`, domain.WithPrincipal(context.Background(), domain.Principal{UserID: "u2"}), http.StatusForbidden},
+		"admin may":      {"application/json", `{"teams":[{"team_name":"x"}]}`, domain.WithPrincipal(context.Background(), domain.Principal{UserID: "u1"}), http.StatusOK},
+	} {
+		req := httptest.NewRequestWithContext(tc.ctx, http.MethodPost, "/admin/import?dry_run=1", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", tc.contentType)
+		w := serve(h.Import, req)
+		if w.Code != tc.status {
+			b, _ := io.ReadAll(w.Body)
+			t.Errorf("%s: got %d %s, want %d", name, w.Code, b, tc.status)
+		}
+	}
+}
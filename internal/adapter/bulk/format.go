@@ -0,0 +1,252 @@
+package bulk
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// format is a serialization of a snapshot.
+type format string
+
+const (
+	formatJSON format = "json"
+	formatYAML format = "yaml"
+	formatCSV  format = "csv"
+)
+
+var contentTypes = map[format]string{
+	formatJSON: "application/json",
+	formatYAML: "application/yaml",
+	formatCSV:  "text/csv",
+}
+
+// formatOf maps a format name or a media type to a format.
+func formatOf(s string) (format, bool) {
+	if mt, _, err := mime.ParseMediaType(s); err == nil {
+		s = mt
+	}
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "json", "application/json":
+		return formatJSON, true
+	case "yaml", "yml", "application/yaml", "application/x-yaml", "text/yaml":
+		return formatYAML, true
+	case "csv", "text/csv":
+		return formatCSV, true
+	}
+	return "", false
+}
+
+// snapshot is the JSON and YAML form: teams with their members, as accepted
+// by /team/add.
+type snapshot struct {
+	Teams []team `json:"teams" yaml:"teams"`
+}
+
+type team struct {
+	TeamName string   `json:"team_name" yaml:"team_name"`
+	Members  []member `json:"members" yaml:"members"`
+}
+
+type member struct {
+	UserID    string `json:"user_id" yaml:"user_id"`
+	Username  string `json:"username" yaml:"username"`
+	IsActive  *bool  `json:"is_active" yaml:"is_active"`
+	Role      string `json:"role,omitempty" yaml:"role,omitempty"`
+	Email     string `json:"email,omitempty" yaml:"email,omitempty"`
+	Timezone  string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	ManagerID string `json:"manager_id,omitempty" yaml:"manager_id,omitempty"`
+}
+
+// csvColumns are the columns of the CSV form, one row per user. A row with
+// only team_name stands for a team without members.
+var csvColumns = []string{"team_name", "user_id", "username", "is_active", "role", "email", "timezone", "manager_id"}
+
+// csvRequired must be in the header of an imported CSV; other columns may
+// be left out and then keep the stored values.
+var csvRequired = []string{"team_name", "user_id", "username", "is_active"}
+
+func decode(f format, body []byte) ([]domain.Team, error) {
+	var s snapshot
+	switch f {
+	case formatJSON:
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&s); err != nil {
+			return nil, err
+		}
+	case formatYAML:
+		dec := yaml.NewDecoder(bytes.NewReader(body))
+		dec.KnownFields(true)
+		if err := dec.Decode(&s); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+	case formatCSV:
+		return decodeCSV(body)
+	}
+
+	teams := make([]domain.Team, 0, len(s.Teams))
+	for _, t := range s.Teams {
+		dt := domain.Team{TeamName: t.TeamName, Members: make([]domain.User, 0, len(t.Members))}
+		for _, m := range t.Members {
+			if m.IsActive == nil {
+				return nil, fmt.Errorf("team %q, user %q: is_active is required", t.TeamName, m.UserID)
+			}
+			dt.Members = append(dt.Members, domain.User{
+				UserID:    m.UserID,
+				Username:  m.Username,
+				IsActive:  *m.IsActive,
+				Role:      domain.Role(m.Role),
+				Email:     m.Email,
+				Timezone:  m.Timezone,
+				ManagerID: m.ManagerID,
+			})
+		}
+		teams = append(teams, dt)
+	}
+	return teams, nil
+}
+
+// decodeCSV groups rows into teams in the order the teams first appear.
+// Rows may leave out trailing empty cells.
+func decodeCSV(body []byte) ([]domain.Team, error) {
+	r := csv.NewReader(bytes.NewReader(body))
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	col := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if !slices.Contains(csvColumns, name) {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		if _, ok := col[name]; ok {
+			return nil, fmt.Errorf("column %q appears twice", name)
+		}
+		col[name] = i
+	}
+	for _, name := range csvRequired {
+		if _, ok := col[name]; !ok {
+			return nil, fmt.Errorf("column %q is required", name)
+		}
+	}
+
+	var teams []domain.Team
+	index := make(map[string]int)
+	for {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return teams, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := r.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := col[name]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+
+		teamName := field("team_name")
+		i, ok := index[teamName]
+		if !ok {
+			i = len(teams)
+			index[teamName] = i
+			teams = append(teams, domain.Team{TeamName: teamName})
+		}
+		if field("user_id") == "" && field("username") == "" && field("is_active") == "" {
+			continue
+		}
+		active, err := strconv.ParseBool(field("is_active"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: is_active must be true or false", line)
+		}
+		teams[i].Members = append(teams[i].Members, domain.User{
+			UserID:    field("user_id"),
+			Username:  field("username"),
+			IsActive:  active,
+			Role:      domain.Role(field("role")),
+			Email:     field("email"),
+			Timezone:  field("timezone"),
+			ManagerID: field("manager_id"),
+		})
+	}
+}
+
+func encode(w io.Writer, f format, teams []domain.Team) error {
+	if f == formatCSV {
+		return encodeCSV(w, teams)
+	}
+
+	s := snapshot{Teams: make([]team, 0, len(teams))}
+	for _, t := range teams {
+		st := team{TeamName: t.TeamName, Members: make([]member, 0, len(t.Members))}
+		for _, u := range t.Members {
+			st.Members = append(st.Members, member{
+				UserID:    u.UserID,
+				Username:  u.Username,
+				IsActive:  &u.IsActive,
+				Role:      string(u.Role),
+				Email:     u.Email,
+				Timezone:  u.Timezone,
+				ManagerID: u.ManagerID,
+			})
+		}
+		s.Teams = append(s.Teams, st)
+	}
+
+	if f == formatYAML {
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+func encodeCSV(w io.Writer, teams []domain.Team) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvColumns); err != nil {
+		return err
+	}
+	for _, t := range teams {
+		if len(t.Members) == 0 {
+			if err := cw.Write([]string{t.TeamName, "", "", "", "", "", "", ""}); err != nil {
+				return err
+			}
+		}
+		for _, u := range t.Members {
+			if err := cw.Write([]string{
+				t.TeamName, u.UserID, u.Username, strconv.FormatBool(u.IsActive),
+				string(u.Role), u.Email, u.Timezone, u.ManagerID,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
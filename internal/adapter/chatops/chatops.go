@@ -0,0 +1,85 @@
+// Package chatops serves slash commands such as /review queue from Slack and
+// Mattermost. It checks that a request comes from the chat platform, maps
+// the chat account to a user and hands the text to usecase.ChatOpsUsecase.
+package chatops
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+// maxBody bounds slash command payloads, which are a few hundred bytes.
+const maxBody = 64 << 10
+
+// scopes are what a chat user may do through commands; what they may do to
+// a given PR or user is decided by the usual authorization.
+var scopes = []string{domain.ScopeRead, domain.ScopeUsersWrite, domain.ScopePRsWrite}
+
+// reply is understood by both Slack and Mattermost. Ephemeral replies are
+// only shown to the caller.
+type reply struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+type errorBody struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "use POST")
+		return nil, false
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBody))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, "BAD_REQUEST", "payload too large")
+		return nil, false
+	}
+	return body, true
+}
+
+// run executes text as callerID in the organization in ctx and replies.
+// Chat platforms show any non-200 answer as a bare failure, so service
+// errors are logged and answered with a short message instead.
+func run(ctx context.Context, w http.ResponseWriter, cmds *usecase.ChatOpsUsecase, logger *slog.Logger, source, callerID, name, text string) {
+	ctx = domain.WithPrincipal(ctx, domain.Principal{
+		UserID: callerID,
+		OrgID:  domain.OrgFromContext(ctx),
+		Name:   source + ":" + name,
+		Scopes: scopes,
+	})
+	out, err := cmds.Run(ctx, callerID, text)
+	if err != nil {
+		logger.Error("chatops: command failed", "source", source, "user", callerID, "text", text, "err", err)
+		out = "Something went wrong, please try again later."
+	}
+	respond(w, out)
+}
+
+func respond(w http.ResponseWriter, text string) {
+	writeJSON(w, http.StatusOK, reply{ResponseType: "ephemeral", Text: text})
+}
+
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	var body errorBody
+	body.Error.Code = code
+	body.Error.Message = msg
+	writeJSON(w, status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
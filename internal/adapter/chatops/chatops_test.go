@@ -0,0 +1,161 @@
+package chatops_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/chatops"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+const secret = "s3cret"
+
+// newHandlers seeds team "backend" with u1..u3, where u1 authored pr-1 and
+// u2 and u3 review it. Slack members U1 and U2 are u1 and u2; Mattermost
+// users m2 ("robert") and m3 are u2 and u3.
+func newHandlers(t *testing.T) (slack, mattermost http.Handler) {
+	t.Helper()
+	ctx := context.Background()
+
+	s := memory.NewStore()
+	teams, users, prs := memory.NewTeamRepo(s), memory.NewUserRepo(s), memory.NewPRRepo(s)
+	if err := teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{
+		{UserID: "u1", Username: "alice", IsActive: true},
+		{UserID: "u2", Username: "bob", IsActive: true},
+		{UserID: "u3", Username: "carol", IsActive: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prs.CreatePRWithReviewers(ctx,
+		domain.PullRequest{ID: "pr-1", Name: "Fix login", AuthorID: "u1", Status: domain.StatusOpen}, []string{"u2", "u3"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	slackUC := usecase.NewSlackUsecase(memory.NewSlackRepo(s), users, nil, logger)
+	for id, slackID := range map[string]string{"u1": "U1", "u2": "U2"} {
+		if err := slackUC.SetUser(ctx, id, slackID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	userUC := usecase.NewUserUsecase(users, prs)
+	cmds := usecase.NewChatOpsUsecase(usecase.NewPRUsecase(users, prs), userUC, users, nil)
+	return chatops.NewSlack(cmds, slackUC, chatops.SlackConfig{SigningSecret: secret}, logger),
+		chatops.NewMattermost(cmds, chatops.MattermostConfig{
+			Token:     secret,
+			Users:     map[string]string{"m2": "u2", "m3": "u3"},
+			Usernames: map[string]string{"robert": "u2"},
+		}, logger)
+}
+
+func slackRequest(form url.Values, ts time.Time, key string) *http.Request {
+	body := form.Encode()
+	stamp := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte("v0:" + stamp + ":" + body))
+
+	req := httptest.NewRequest(http.MethodPost, "/chatops/slack", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", stamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func serve(t *testing.T, h http.Handler, req *http.Request, wantStatus int) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != wantStatus {
+		t.Fatalf("status: got %d, want %d: %s", rec.Code, wantStatus, rec.Body)
+	}
+	var out struct {
+		ResponseType string `json:"response_type"`
+		Text         string `json:"text"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	return out.Text
+}
+
+func TestSlackCommands(t *testing.T) {
+	slack, _ := newHandlers(t)
+	now := time.Now()
+
+	got := serve(t, slack, slackRequest(url.Values{"user_id": {"U2"}, "user_name": {"bob"}, "text": {"queue"}}, now, secret), http.StatusOK)
+	if !strings.Contains(got, "Open reviews for bob (1)") {
+		t.Fatalf("queue: %q", got)
+	}
+	// Escaped mentions are mapped back to users; the author may reassign,
+	// but the whole team is busy with the PR.
+	got = serve(t, slack, slackRequest(url.Values{"user_id": {"U1"}, "text": {"reassign pr-1 <@U2|bob>"}}, now, secret), http.StatusOK)
+	if got != "Nobody else in bob's team can take `pr-1`." {
+		t.Fatalf("reassign: %q", got)
+	}
+	got = serve(t, slack, slackRequest(url.Values{"user_id": {"U9"}, "text": {"queue"}}, now, secret), http.StatusOK)
+	if !strings.Contains(got, "not linked") {
+		t.Fatalf("unknown member: %q", got)
+	}
+
+	for name, req := range map[string]*http.Request{
+		"wrong secret": slackRequest(url.Values{"user_id": {"U2"}, "text": {"queue"}}, now, "other"),
+		"replayed":     slackRequest(url.Values{"user_id": {"U2"}, "text": {"queue"}}, now.Add(-10*time.Minute), secret),
+	} {
+		rec := httptest.NewRecorder()
+		slack.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: got %d, want %d", name, rec.Code, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestMattermostCommands(t *testing.T) {
+	_, mattermost := newHandlers(t)
+	post := func(form url.Values) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/chatops/mattermost", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	got := serve(t, mattermost, post(url.Values{"token": {secret}, "user_id": {"m3"}, "user_name": {"carol"}, "text": {"queue @robert"}}), http.StatusOK)
+	if !strings.Contains(got, "Open reviews for bob (1)") {
+		t.Fatalf("queue: %q", got)
+	}
+	got = serve(t, mattermost, post(url.Values{"token": {secret}, "user_id": {"m2"}, "user_name": {"robert"}, "text": {"away until 2000-01-01"}}), http.StatusOK)
+	if !strings.Contains(got, "not in the future") {
+		t.Fatalf("away: %q", got)
+	}
+	// The username is not trusted: a caller without a mapped user id is
+	// turned away even when the name matches a user.
+	for _, form := range []url.Values{
+		{"token": {secret}, "user_id": {"m9"}, "user_name": {"u2"}, "text": {"queue"}},
+		{"token": {secret}, "user_name": {"robert"}, "text": {"queue"}},
+	} {
+		if got := serve(t, mattermost, post(form), http.StatusOK); !strings.Contains(got, "not linked") {
+			t.Fatalf("unknown member %v: %q", form, got)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	mattermost.ServeHTTP(rec, post(url.Values{"token": {"guess"}, "user_name": {"robert"}, "text": {"queue"}}))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("wrong token: got %d", rec.Code)
+	}
+}
@@ -0,0 +1,77 @@
+package chatops
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+const SourceMattermost = "mattermost"
+
+type MattermostConfig struct {
+	// Token is the token Mattermost generated for the slash command.
+	Token string
+	// OrgID is the organization the commands are run in.
+	OrgID string
+	// Users maps Mattermost user ids, which unlike usernames cannot be
+	// changed, to user ids. Callers without an entry are turned away.
+	Users map[string]string
+	// Usernames maps Mattermost usernames to user ids for @mentions in
+	// commands. Names without an entry are taken to be user ids as they are.
+	Usernames map[string]string
+}
+
+// Mattermost handles slash commands sent to /chatops/mattermost.
+type Mattermost struct {
+	cmds *usecase.ChatOpsUsecase
+	cfg  MattermostConfig
+	log  *slog.Logger
+}
+
+func NewMattermost(cmds *usecase.ChatOpsUsecase, cfg MattermostConfig, logger *slog.Logger) *Mattermost {
+	if cfg.OrgID == "" {
+		cfg.OrgID = domain.DefaultOrg
+	}
+	return &Mattermost{cmds: cmds, cfg: cfg, log: logger}
+}
+
+func (h *Mattermost) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "malformed payload")
+		return
+	}
+	// Mattermost does not sign commands; it sends the command's token both
+	// in the payload and as "Authorization: Token <token>".
+	token := form.Get("token")
+	if h.cfg.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.cfg.Token)) != 1 {
+		writeError(w, http.StatusUnauthorized, domain.ErrUnauthorized.Error(), "invalid token")
+		return
+	}
+
+	callerID, ok := h.cfg.Users[form.Get("user_id")]
+	if !ok || form.Get("user_id") == "" {
+		respond(w, "Your Mattermost account is not linked to a pr-reviewer user; ask an admin to add it to MATTERMOST_USERS.")
+		return
+	}
+
+	args := strings.Fields(form.Get("text"))
+	for i, a := range args {
+		if name, ok := strings.CutPrefix(a, "@"); ok && name != "me" {
+			if id, ok := h.cfg.Usernames[name]; ok {
+				args[i] = "@" + id
+			}
+		}
+	}
+	ctx := domain.WithOrg(r.Context(), h.cfg.OrgID)
+	run(ctx, w, h.cmds, h.log, SourceMattermost, callerID, form.Get("user_name"), strings.Join(args, " "))
+}
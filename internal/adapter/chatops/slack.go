@@ -0,0 +1,114 @@
+package chatops
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+const SourceSlack = "slack"
+
+// maxSkew is how old a signed Slack request may be, as Slack recommends,
+// so that a captured request cannot be replayed later.
+const maxSkew = 5 * time.Minute
+
+type SlackConfig struct {
+	// SigningSecret is the signing secret of the Slack app.
+	SigningSecret string
+	// OrgID is the organization the commands are run in.
+	OrgID string
+}
+
+// Slack handles slash commands sent to /chatops/slack. Callers are mapped to
+// users with the Slack member ids set by pr-reviewer slack set-user.
+type Slack struct {
+	cmds  *usecase.ChatOpsUsecase
+	users *usecase.SlackUsecase
+	cfg   SlackConfig
+	log   *slog.Logger
+	now   func() time.Time
+}
+
+func NewSlack(cmds *usecase.ChatOpsUsecase, users *usecase.SlackUsecase, cfg SlackConfig, logger *slog.Logger) *Slack {
+	if cfg.OrgID == "" {
+		cfg.OrgID = domain.DefaultOrg
+	}
+	return &Slack{cmds: cmds, users: users, cfg: cfg, log: logger, now: time.Now}
+}
+
+// slackMention matches the escaped mentions Slack puts into command text,
+// such as <@U024BE7LH|bob>.
+var slackMention = regexp.MustCompile(`<@([A-Z0-9]+)(?:\|[^>]*)?>`)
+
+func (h *Slack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+	ts := r.Header.Get("X-Slack-Request-Timestamp")
+	if !validSlackSignature(h.cfg.SigningSecret, ts, r.Header.Get("X-Slack-Signature"), body, h.now()) {
+		writeError(w, http.StatusUnauthorized, domain.ErrUnauthorized.Error(), "invalid X-Slack-Signature")
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "malformed payload")
+		return
+	}
+
+	ctx := domain.WithOrg(r.Context(), h.cfg.OrgID)
+	callerID, err := h.users.UserBySlackID(ctx, form.Get("user_id"))
+	if errors.Is(err, domain.ErrNotFound) {
+		respond(w, "Your Slack account is not linked to a pr-reviewer user; ask an admin to run `pr-reviewer slack set-user`.")
+		return
+	}
+	if err != nil {
+		h.log.Error("chatops: slack user lookup failed", "err", err)
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal error")
+		return
+	}
+
+	text := slackMention.ReplaceAllStringFunc(form.Get("text"), func(m string) string {
+		slackID := slackMention.FindStringSubmatch(m)[1]
+		if id, err := h.users.UserBySlackID(ctx, slackID); err == nil {
+			return "@" + id
+		}
+		return "@" + slackID
+	})
+	run(ctx, w, h.cmds, h.log, SourceSlack, callerID, form.Get("user_name"), text)
+}
+
+// validSlackSignature checks the v0 signature: an HMAC-SHA256 over
+// "v0:<timestamp>:<body>" keyed with the signing secret.
+func validSlackSignature(secret, ts, header string, body []byte, now time.Time) bool {
+	sig, ok := strings.CutPrefix(header, "v0=")
+	if !ok || secret == "" {
+		return false
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+	if d := now.Sub(time.Unix(sec, 0)); d > maxSkew || d < -maxSkew {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + ts + ":"))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
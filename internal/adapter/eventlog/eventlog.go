@@ -0,0 +1,29 @@
+// Package eventlog is an event sink that writes every relayed event to the
+// service log, for debugging and for log-based pipelines.
+package eventlog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type Sink struct{ log *slog.Logger }
+
+func New(logger *slog.Logger) *Sink { return &Sink{log: logger} }
+
+func (s *Sink) Publish(ctx context.Context, e domain.Event) error {
+	attrs := []any{"id", e.ID, "type", e.Type, "org", e.OrgID, "key", e.Key(), "occurred_at", e.OccurredAt}
+	if pr := e.Data.PullRequest; pr != nil {
+		attrs = append(attrs, "pr", pr.ID, "status", pr.Status, "reviewers", pr.AssignedReviewers)
+	}
+	if e.Data.OldReviewerID != "" {
+		attrs = append(attrs, "old_reviewer", e.Data.OldReviewerID, "new_reviewer", e.Data.NewReviewerID)
+	}
+	if u := e.Data.User; u != nil {
+		attrs = append(attrs, "user", u.UserID, "active", u.IsActive)
+	}
+	s.log.InfoContext(ctx, "event", attrs...)
+	return nil
+}
@@ -0,0 +1,150 @@
+// Package github pushes reviewer assignments to the GitHub REST API.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+// PRID is the pull request id used for PRs that come from GitHub, e.g.
+// "octo-org/api#42".
+func PRID(repo string, number int) string {
+	return repo + "#" + strconv.Itoa(number)
+}
+
+// ParsePRID splits an id built by PRID into "owner/repo" and the number.
+func ParsePRID(id string) (string, int, bool) {
+	repo, num, ok := strings.Cut(id, "#")
+	if !ok || strings.Count(repo, "/") != 1 || strings.HasPrefix(repo, "/") || strings.HasSuffix(repo, "/") {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil || n <= 0 {
+		return "", 0, false
+	}
+	return repo, n, true
+}
+
+type Config struct {
+	// BaseURL is the API root, https://api.github.com or a GHES/stub URL.
+	BaseURL string
+	Token   string
+	// Logins maps GitHub logins to user ids, as for the webhook. Users
+	// without an entry are assumed to have their user id as login.
+	Logins  map[string]string
+	Timeout time.Duration
+}
+
+// Client implements usecase.ReviewerPusher.
+type Client struct {
+	base   string
+	token  string
+	logins map[string]string
+	http   *http.Client
+}
+
+var _ usecase.ReviewerPusher = (*Client)(nil)
+
+func New(cfg Config) *Client {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.github.com"
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	logins := make(map[string]string, len(cfg.Logins))
+	for login, userID := range cfg.Logins {
+		logins[userID] = login
+	}
+	return &Client{
+		base:   strings.TrimRight(cfg.BaseURL, "/"),
+		token:  cfg.Token,
+		logins: logins,
+		http:   &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+func (c *Client) Supports(prID string) bool {
+	_, _, ok := ParsePRID(prID)
+	return ok
+}
+
+// Push removes the old reviewers first so a reassignment never leaves the PR
+// with more requested reviewers than we assigned.
+func (c *Client) Push(ctx context.Context, s domain.ReviewerSync) error {
+	repo, number, ok := ParsePRID(s.PRID)
+	if !ok {
+		return fmt.Errorf("%w: %q is not a GitHub pull request", usecase.ErrPermanent, s.PRID)
+	}
+	path := fmt.Sprintf("/repos/%s/pulls/%d/requested_reviewers", repo, number)
+
+	if len(s.Remove) > 0 {
+		if err := c.do(ctx, http.MethodDelete, path, c.toLogins(s.Remove)); err != nil {
+			return fmt.Errorf("remove reviewers: %w", err)
+		}
+	}
+	if len(s.Add) > 0 {
+		if err := c.do(ctx, http.MethodPost, path, c.toLogins(s.Add)); err != nil {
+			return fmt.Errorf("request reviewers: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) toLogins(userIDs []string) []string {
+	out := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		if login, ok := c.logins[id]; ok {
+			out = append(out, login)
+		} else {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func (c *Client) do(ctx context.Context, method, path string, reviewers []string) error {
+	body, err := json.Marshal(map[string][]string{"reviewers": reviewers})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("%s %s: status %d: %s", method, path, resp.StatusCode, bytes.TrimSpace(msg))
+	// 404 and 422 mean the PR or a reviewer is unknown to GitHub; retrying
+	// the same request cannot succeed.
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity {
+		return fmt.Errorf("%w: %v", usecase.ErrPermanent, err)
+	}
+	return err
+}
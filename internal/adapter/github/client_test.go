@@ -0,0 +1,91 @@
+package github_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/github"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+type call struct {
+	method, path, auth string
+	reviewers          []string
+}
+
+func stub(t *testing.T, status int) (*httptest.Server, *[]call) {
+	t.Helper()
+	var calls []call
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Reviewers []string `json:"reviewers"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		calls = append(calls, call{r.Method, r.URL.Path, r.Header.Get("Authorization"), body.Reviewers})
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestParsePRID(t *testing.T) {
+	for id, ok := range map[string]bool{
+		"octo/api#42": true,
+		"octo/api#0":  false,
+		"octo/api":    false,
+		"api#1":       false,
+		"a/b/c#1":     false,
+		"grp/app!7":   false,
+	} {
+		if _, _, got := github.ParsePRID(id); got != ok {
+			t.Errorf("%q: got %v, want %v", id, got, ok)
+		}
+	}
+}
+
+func TestPushReassignment(t *testing.T) {
+	srv, calls := stub(t, http.StatusOK)
+	c := github.New(github.Config{BaseURL: srv.URL, Token: "tkn", Logins: map[string]string{"octocat": "u1"}})
+
+	err := c.Push(context.Background(), domain.ReviewerSync{PRID: "octo/api#42", Add: []string{"u1"}, Remove: []string{"u2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const path = "/repos/octo/api/pulls/42/requested_reviewers"
+	want := []call{
+		{http.MethodDelete, path, "Bearer tkn", []string{"u2"}},
+		{http.MethodPost, path, "Bearer tkn", []string{"octocat"}},
+	}
+	if !slices.EqualFunc(*calls, want, func(a, b call) bool {
+		return a.method == b.method && a.path == b.path && a.auth == b.auth && slices.Equal(a.reviewers, b.reviewers)
+	}) {
+		t.Fatalf("calls: got %+v, want %+v", *calls, want)
+	}
+}
+
+func TestPushErrors(t *testing.T) {
+	for status, permanent := range map[int]bool{
+		http.StatusUnprocessableEntity: true,
+		http.StatusNotFound:            true,
+		http.StatusTooManyRequests:     false,
+		http.StatusBadGateway:          false,
+	} {
+		srv, _ := stub(t, status)
+		err := github.New(github.Config{BaseURL: srv.URL}).
+			Push(context.Background(), domain.ReviewerSync{PRID: "octo/api#1", Add: []string{"u1"}})
+		if err == nil {
+			t.Fatalf("%d: no error", status)
+		}
+		if got := errors.Is(err, usecase.ErrPermanent); got != permanent {
+			t.Errorf("%d: permanent %v, want %v (%v)", status, got, permanent, err)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+// Package jwtauth authenticates requests carrying JWTs issued by an external
+// identity provider. Signing keys come from a JWKS document that is read from
+// a local file or fetched from a URL.
+package jwtauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+var algorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// minRefetch bounds how often an unknown key id or a failed fetch may
+// trigger a JWKS fetch.
+const minRefetch = time.Minute
+
+type Config struct {
+	JWKSFile string
+	JWKSURL  string
+	// Issuer and Audience are required: a key set may be shared by several
+	// issuers and a provider signs tokens for every application it serves.
+	Issuer   string
+	Audience string
+	// UserClaim names the claim holding our user_id, e.g. "sub" or "login".
+	UserClaim string
+	// ScopeClaim names a space separated string or string array of scopes.
+	ScopeClaim string
+	// OrgClaim names the claim binding the token to an organization. A token
+	// without the claim is bound to none, and requests made with it are
+	// refused.
+	OrgClaim string
+	// Org binds every token to one organization when OrgClaim is empty.
+	Org string
+	// DefaultScopes are granted to every valid token in addition to ScopeClaim.
+	DefaultScopes []string
+	// Refresh is how long a JWKS fetched from JWKSURL is trusted.
+	Refresh time.Duration
+	Leeway  time.Duration
+}
+
+type Verifier struct {
+	cfg    Config
+	client *http.Client
+	log    *slog.Logger
+	now    func() time.Time
+
+	// loadMu serializes JWKS loads; mu guards the fields below it only and
+	// is never held during a fetch, so verification with known keys does
+	// not wait for the provider.
+	loadMu sync.Mutex
+	mu     sync.Mutex
+	keys   jose.JSONWebKeySet
+	// fetchedAt is when keys were loaded, triedAt when a load was last
+	// attempted, successfully or not.
+	fetchedAt time.Time
+	triedAt   time.Time
+}
+
+// New loads the key set once so that misconfiguration fails at startup.
+// Later, a key set that cannot be refreshed keeps being used.
+func New(ctx context.Context, cfg Config, logger *slog.Logger) (*Verifier, error) {
+	if (cfg.JWKSFile == "") == (cfg.JWKSURL == "") {
+		return nil, errors.New("jwtauth: exactly one of JWKS file or URL must be set")
+	}
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, errors.New("jwtauth: issuer and audience must be set")
+	}
+	if cfg.UserClaim == "" {
+		cfg.UserClaim = "sub"
+	}
+	if cfg.Org == "" {
+		cfg.Org = domain.DefaultOrg
+	}
+	if cfg.Leeway == 0 {
+		cfg.Leeway = 30 * time.Second
+	}
+	if cfg.Refresh == 0 {
+		cfg.Refresh = 15 * time.Minute
+	}
+
+	v := &Verifier{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 5 * time.Second},
+		log:    logger,
+		now:    time.Now,
+	}
+
+	if err := v.load(ctx, time.Time{}); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Authenticate verifies the signature and the iss, aud, exp and nbf claims
+// and maps the configured claim to the principal's user id.
+func (v *Verifier) Authenticate(ctx context.Context, raw string) (domain.Principal, error) {
+	tok, err := jwt.ParseSigned(raw, algorithms)
+	if err != nil || len(tok.Headers) != 1 {
+		return domain.Principal{}, domain.ErrUnauthorized
+	}
+
+	key, err := v.key(ctx, tok.Headers[0].KeyID)
+	if err != nil {
+		return domain.Principal{}, err
+	}
+
+	var (
+		std    jwt.Claims
+		custom map[string]any
+	)
+	if err := tok.Claims(key, &std, &custom); err != nil {
+		return domain.Principal{}, fmt.Errorf("%w: %v", domain.ErrUnauthorized, err)
+	}
+
+	if std.Expiry == nil {
+		return domain.Principal{}, fmt.Errorf("%w: token has no exp claim", domain.ErrUnauthorized)
+	}
+	expected := jwt.Expected{Issuer: v.cfg.Issuer, AnyAudience: jwt.Audience{v.cfg.Audience}, Time: v.now()}
+	if err := std.ValidateWithLeeway(expected, v.cfg.Leeway); err != nil {
+		return domain.Principal{}, fmt.Errorf("%w: %v", domain.ErrUnauthorized, err)
+	}
+
+	userID, _ := custom[v.cfg.UserClaim].(string)
+	if userID == "" {
+		return domain.Principal{}, fmt.Errorf("%w: claim %q is missing", domain.ErrUnauthorized, v.cfg.UserClaim)
+	}
+
+	orgID := v.cfg.Org
+	if v.cfg.OrgClaim != "" {
+		orgID, _ = custom[v.cfg.OrgClaim].(string)
+	}
+
+	return domain.Principal{
+		UserID: userID,
+		OrgID:  orgID,
+		Name:   userID,
+		Scopes: append(append([]string{}, v.cfg.DefaultScopes...), scopes(custom[v.cfg.ScopeClaim])...),
+	}, nil
+}
+
+// key finds the key kid in the cached set, refreshing the set when it is
+// stale or lacks kid. A failed refresh keeps the cached set and is not
+// retried for minRefetch, so an unreachable provider neither fails tokens
+// signed with known keys nor stalls every request on a fetch.
+func (v *Verifier) key(ctx context.Context, kid string) (*jose.JSONWebKey, error) {
+	keys, fetchedAt, triedAt := v.snapshot()
+	if v.due(fetchedAt, triedAt, v.cfg.Refresh) {
+		if err := v.load(ctx, triedAt); err != nil {
+			v.log.Warn("jwtauth: jwks refresh failed, keeping the cached keys", "err", err)
+		}
+		keys, fetchedAt, triedAt = v.snapshot()
+	}
+
+	if k := find(keys, kid); k != nil {
+		return k, nil
+	}
+
+	// The provider may have rotated keys since the last fetch.
+	if v.due(fetchedAt, triedAt, minRefetch) {
+		if err := v.load(ctx, triedAt); err != nil {
+			v.log.Warn("jwtauth: jwks fetch for an unknown key failed", "kid", kid, "err", err)
+		}
+		keys, _, _ = v.snapshot()
+		if k := find(keys, kid); k != nil {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown signing key %q", domain.ErrUnauthorized, kid)
+}
+
+// due reports whether a key set fetched at fetchedAt is older than age and
+// may be fetched again: not within minRefetch of a failed attempt.
+func (v *Verifier) due(fetchedAt, triedAt time.Time, age time.Duration) bool {
+	if v.cfg.JWKSURL == "" {
+		return false
+	}
+	now := v.now()
+	if triedAt.After(fetchedAt) && now.Sub(triedAt) <= minRefetch {
+		return false
+	}
+	return now.Sub(fetchedAt) > age
+}
+
+func (v *Verifier) snapshot() (keys jose.JSONWebKeySet, fetchedAt, triedAt time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.keys, v.fetchedAt, v.triedAt
+}
+
+func find(set jose.JSONWebKeySet, kid string) *jose.JSONWebKey {
+	if kid == "" {
+		if len(set.Keys) == 1 {
+			return &set.Keys[0]
+		}
+		return nil
+	}
+	if keys := set.Key(kid); len(keys) > 0 {
+		return &keys[0]
+	}
+	return nil
+}
+
+// load replaces the key set unless another load has been attempted since
+// seen, the attempt time the caller based its decision on. A failed load
+// leaves the key set as it was.
+func (v *Verifier) load(ctx context.Context, seen time.Time) error {
+	v.loadMu.Lock()
+	defer v.loadMu.Unlock()
+	if _, _, triedAt := v.snapshot(); !triedAt.Equal(seen) {
+		return nil
+	}
+
+	set, err := v.read(ctx)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.triedAt = v.now()
+	if err != nil {
+		return err
+	}
+	v.keys = set
+	v.fetchedAt = v.triedAt
+	return nil
+}
+
+func (v *Verifier) read(ctx context.Context) (jose.JSONWebKeySet, error) {
+	var (
+		body []byte
+		err  error
+	)
+	if v.cfg.JWKSFile != "" {
+		body, err = os.ReadFile(v.cfg.JWKSFile)
+	} else {
+		body, err = v.fetch(ctx)
+	}
+	if err != nil {
+		return jose.JSONWebKeySet{}, fmt.Errorf("jwtauth: load jwks: %w", err)
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.Unmarshal(body, &set); err != nil {
+		return jose.JSONWebKeySet{}, fmt.Errorf("jwtauth: parse jwks: %w", err)
+	}
+	if len(set.Keys) == 0 {
+		return jose.JSONWebKeySet{}, errors.New("jwtauth: jwks contains no keys")
+	}
+	return set, nil
+}
+
+func (v *Verifier) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.cfg.JWKSURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+func scopes(claim any) []string {
+	switch c := claim.(type) {
+	case string:
+		return strings.Fields(c)
+	case []any:
+		out := make([]string, 0, len(c))
+		for _, s := range c {
+			if str, ok := s.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,278 @@
+package jwtauth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/jwtauth"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+var discard = slog.New(slog.DiscardHandler)
+
+type keyPair struct {
+	kid  string
+	priv *rsa.PrivateKey
+}
+
+func newKey(t *testing.T, kid string) keyPair {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyPair{kid: kid, priv: priv}
+}
+
+func jwks(t *testing.T, keys ...keyPair) []byte {
+	t.Helper()
+	var set jose.JSONWebKeySet
+	for _, k := range keys {
+		set.Keys = append(set.Keys, jose.JSONWebKey{Key: &k.priv.PublicKey, KeyID: k.kid, Algorithm: string(jose.RS256), Use: "sig"})
+	}
+	b, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func sign(t *testing.T, k keyPair, std jwt.Claims, custom map[string]any) string {
+	t.Helper()
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: jose.JSONWebKey{Key: k.priv, KeyID: k.kid}},
+		(&jose.SignerOptions{}).WithType("JWT"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := jwt.Signed(signer).Claims(std).Claims(custom).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func validClaims() jwt.Claims {
+	now := time.Now()
+	return jwt.Claims{
+		Issuer:   "https://idp.example",
+		Audience: jwt.Audience{"pr-reviewer"},
+		Subject:  "subject-1",
+		Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+		IssuedAt: jwt.NewNumericDate(now),
+	}
+}
+
+func fileVerifier(t *testing.T, keys ...keyPair) *jwtauth.Verifier {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks(t, keys...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	v, err := jwtauth.New(context.Background(), jwtauth.Config{
+		JWKSFile:      path,
+		Issuer:        "https://idp.example",
+		Audience:      "pr-reviewer",
+		UserClaim:     "login",
+		ScopeClaim:    "scope",
+		DefaultScopes: []string{domain.ScopeRead},
+	}, discard)
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	return v
+}
+
+func TestAuthenticateValidToken(t *testing.T) {
+	k := newKey(t, "k1")
+	v := fileVerifier(t, k)
+
+	raw := sign(t, k, validClaims(), map[string]any{"login": "u1", "scope": "prs:write teams:write"})
+	p, err := v.Authenticate(context.Background(), raw)
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if p.UserID != "u1" || p.OrgID != domain.DefaultOrg {
+		t.Fatalf("principal: got %+v", p)
+	}
+	want := []string{domain.ScopeRead, domain.ScopePRsWrite, domain.ScopeTeamsWrite}
+	if !slices.Equal(p.Scopes, want) {
+		t.Fatalf("scopes: got %v, want %v", p.Scopes, want)
+	}
+}
+
+func TestAuthenticateRejects(t *testing.T) {
+	k := newKey(t, "k1")
+	other := newKey(t, "k1")
+	v := fileVerifier(t, k)
+
+	expired := validClaims()
+	expired.Expiry = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+
+	noExp := validClaims()
+	noExp.Expiry = nil
+
+	wrongIss := validClaims()
+	wrongIss.Issuer = "https://evil.example"
+
+	wrongAud := validClaims()
+	wrongAud.Audience = jwt.Audience{"someone-else"}
+
+	noIss, noAud := validClaims(), validClaims()
+	noIss.Issuer, noAud.Audience = "", nil
+
+	user := map[string]any{"login": "u1"}
+	cases := map[string]string{
+		"expired":       sign(t, k, expired, user),
+		"no exp":        sign(t, k, noExp, user),
+		"wrong issuer":  sign(t, k, wrongIss, user),
+		"wrong aud":     sign(t, k, wrongAud, user),
+		"no issuer":     sign(t, k, noIss, user),
+		"no aud":        sign(t, k, noAud, user),
+		"bad signature": sign(t, other, validClaims(), user),
+		"unknown kid":   sign(t, newKey(t, "k2"), validClaims(), user),
+		"no user claim": sign(t, k, validClaims(), map[string]any{}),
+		"garbage":       "not-a-jwt",
+		"api token":     "prr_abcdef",
+	}
+	for name, raw := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := v.Authenticate(context.Background(), raw); !errors.Is(err, domain.ErrUnauthorized) {
+				t.Fatalf("got %v, want %v", err, domain.ErrUnauthorized)
+			}
+		})
+	}
+}
+
+func TestOrgClaim(t *testing.T) {
+	k := newKey(t, "k1")
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks(t, k), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	v, err := jwtauth.New(context.Background(), jwtauth.Config{
+		JWKSFile: path,
+		Issuer:   "https://idp.example",
+		Audience: "pr-reviewer",
+		OrgClaim: "org",
+	}, discard)
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+
+	// A token without the claim is not bound to the default organization;
+	// the tenant resolver refuses it.
+	for claims, want := range map[string]string{`{"org":"acme"}`: "acme", `{}`: ""} {
+		var custom map[string]any
+		if err := json.Unmarshal([]byte(claims), &custom); err != nil {
+			t.Fatal(err)
+		}
+		p, err := v.Authenticate(context.Background(), sign(t, k, validClaims(), custom))
+		if err != nil || p.OrgID != want {
+			t.Fatalf("%s: got %+v, %v", claims, p, err)
+		}
+	}
+}
+
+func TestJWKSFromURL(t *testing.T) {
+	k := newKey(t, "k1")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(jwks(t, k))
+	}))
+	t.Cleanup(srv.Close)
+
+	v, err := jwtauth.New(context.Background(), jwtauth.Config{
+		JWKSURL:  srv.URL,
+		Issuer:   "https://idp.example",
+		Audience: "pr-reviewer",
+	}, discard)
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+
+	p, err := v.Authenticate(context.Background(), sign(t, k, validClaims(), nil))
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if p.UserID != "subject-1" {
+		t.Fatalf("default user claim should be sub, got %q", p.UserID)
+	}
+}
+
+func TestJWKSOutage(t *testing.T) {
+	k1, k2 := newKey(t, "k1"), newKey(t, "k2")
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if fetches.Add(1) > 1 {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write(jwks(t, k1))
+	}))
+	t.Cleanup(srv.Close)
+
+	v, err := jwtauth.New(context.Background(), jwtauth.Config{
+		JWKSURL:  srv.URL,
+		Issuer:   "https://idp.example",
+		Audience: "pr-reviewer",
+		Refresh:  time.Millisecond,
+	}, discard)
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	// The stale key set is still used, and the failed refresh is not
+	// repeated by the next requests.
+	for i := range 3 {
+		if _, err := v.Authenticate(context.Background(), sign(t, k1, validClaims(), nil)); err != nil {
+			t.Fatalf("request %d with a cached key: %v", i, err)
+		}
+	}
+	if _, err := v.Authenticate(context.Background(), sign(t, k2, validClaims(), nil)); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Fatalf("unknown key: got %v, want %v", err, domain.ErrUnauthorized)
+	}
+	if n := fetches.Load(); n != 2 {
+		t.Fatalf("fetched %d times, want 2", n)
+	}
+}
+
+func TestNewRequiresSingleSource(t *testing.T) {
+	if _, err := jwtauth.New(context.Background(), jwtauth.Config{}, discard); err == nil {
+		t.Fatal("expected error without a JWKS source")
+	}
+	if _, err := jwtauth.New(context.Background(), jwtauth.Config{JWKSFile: "a", JWKSURL: "b"}, discard); err == nil {
+		t.Fatal("expected error with two JWKS sources")
+	}
+}
+
+func TestNewRequiresIssuerAndAudience(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks(t, newKey(t, "k1")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, cfg := range []jwtauth.Config{
+		{JWKSFile: path, Audience: "pr-reviewer"},
+		{JWKSFile: path, Issuer: "https://idp.example"},
+	} {
+		if _, err := jwtauth.New(context.Background(), cfg, discard); err == nil {
+			t.Fatalf("%+v: expected error", cfg)
+		}
+	}
+}
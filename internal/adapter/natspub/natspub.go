@@ -0,0 +1,104 @@
+// Package natspub publishes the service's events to NATS. Each event is one
+// message on <prefix>.<event type>, for example pr-reviewer.v1.pr.created,
+// whose body follows shared/api/pr/v1/events.schema.json.
+package natspub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+const (
+	// SchemaVersion is the major version of the message schema. It is part
+	// of DefaultPrefix so that a breaking change moves to new subjects.
+	SchemaVersion = "1"
+	DefaultPrefix = "pr-reviewer.v" + SchemaVersion
+
+	// MsgIDHeader lets JetStream streams drop messages the relay sends twice.
+	MsgIDHeader  = nats.MsgIdHdr
+	SchemaHeader = "Pr-Reviewer-Schema"
+	OrgHeader    = "Pr-Reviewer-Org"
+)
+
+type Config struct {
+	URL string
+	// Prefix defaults to DefaultPrefix.
+	Prefix string
+	// Timeout bounds the wait for the server to acknowledge a publish.
+	Timeout time.Duration
+}
+
+type Publisher struct {
+	nc      *nats.Conn
+	prefix  string
+	timeout time.Duration
+	owned   bool
+}
+
+// Connect dials the server and keeps reconnecting for as long as the
+// publisher is open; publishes fail while it is disconnected and are retried
+// by the outbox.
+func Connect(cfg Config) (*Publisher, error) {
+	nc, err := nats.Connect(cfg.URL, nats.Name("pr-reviewer"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("nats: connect %s: %w", cfg.URL, err)
+	}
+	p := New(nc, cfg)
+	p.owned = true
+	return p, nil
+}
+
+// New publishes over an existing connection, which Close leaves open.
+func New(nc *nats.Conn, cfg Config) *Publisher {
+	if cfg.Prefix == "" {
+		cfg.Prefix = DefaultPrefix
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	return &Publisher{nc: nc, prefix: cfg.Prefix, timeout: cfg.Timeout}
+}
+
+// Subject returns the subject events of type t are published on.
+func (p *Publisher) Subject(t domain.EventType) string {
+	return p.prefix + "." + string(t)
+}
+
+// Publish sends e and waits until the server has received it. Core NATS
+// only delivers to current subscribers; consumers that must not miss events
+// should read them from a JetStream stream bound to the subjects.
+func (p *Publisher) Publish(ctx context.Context, e domain.Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	msg := nats.NewMsg(p.Subject(e.Type))
+	msg.Data = body
+	msg.Header.Set(MsgIDHeader, e.ID)
+	msg.Header.Set(SchemaHeader, SchemaVersion)
+	msg.Header.Set(OrgHeader, e.OrgID)
+	if err := p.nc.PublishMsg(msg); err != nil {
+		return fmt.Errorf("nats: publish %s: %w", msg.Subject, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	if err := p.nc.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("nats: flush %s: %w", msg.Subject, err)
+	}
+	return nil
+}
+
+// Close drains the connection if Connect opened it.
+func (p *Publisher) Close() error {
+	if !p.owned {
+		return nil
+	}
+	return p.nc.Drain()
+}
@@ -0,0 +1,150 @@
+package natspub_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/natspub"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+func runServer(t *testing.T) *server.Server {
+	t.Helper()
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, NoSigs: true, NoLog: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ns.Start()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	t.Cleanup(ns.Shutdown)
+	return ns
+}
+
+func TestPublishesEventsThroughOutbox(t *testing.T) {
+	ns := runServer(t)
+	ctx := context.Background()
+
+	sub, err := nats.Connect(ns.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	msgs, err := sub.SubscribeSync(natspub.DefaultPrefix + ".>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sub.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	pub, err := natspub.Connect(natspub.Config{URL: ns.ClientURL()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = pub.Close() }()
+
+	s := memory.NewStore()
+	teams := memory.NewTeamRepo(s)
+	if err := teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{
+		{UserID: "u1", Username: "u1", IsActive: true},
+		{UserID: "u2", Username: "u2", IsActive: true},
+		{UserID: "u3", Username: "u3", IsActive: true},
+		{UserID: "u4", Username: "u4", IsActive: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	prs := usecase.NewPRUsecase(memory.NewUserRepo(s), memory.NewPRRepo(s))
+	users := usecase.NewUserUsecase(memory.NewUserRepo(s), memory.NewPRRepo(s))
+	relay := usecase.NewOutboxRelay(memory.NewOutboxRepo(s), usecase.RetryConfig{},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	relay.AddSink("nats", pub)
+
+	created, err := prs.CreatePR(ctx, "pr-1", "feat", "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := prs.Reassign(ctx, "pr-1", created.AssignedReviewers[0], "", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prs.Merge(ctx, "pr-1", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.SetActive(ctx, "u4", false); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		time.Sleep(time.Millisecond)
+		n, err := relay.RunOnce(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n == 0 {
+			break
+		}
+	}
+
+	got := make(map[domain.EventType]domain.Event)
+	var prOrder []domain.EventType
+	for range 4 {
+		m, err := msgs.NextMsg(time.Second)
+		if err != nil {
+			t.Fatalf("next message: %v", err)
+		}
+		var e domain.Event
+		if err := json.Unmarshal(m.Data, &e); err != nil {
+			t.Fatal(err)
+		}
+		if m.Subject != pub.Subject(e.Type) || m.Header.Get(natspub.MsgIDHeader) != e.ID ||
+			m.Header.Get(natspub.SchemaHeader) != natspub.SchemaVersion || m.Header.Get(natspub.OrgHeader) != domain.DefaultOrg {
+			t.Fatalf("message %s %v does not match event %+v", m.Subject, m.Header, e)
+		}
+		got[e.Type] = e
+		if e.Data.PullRequest != nil {
+			prOrder = append(prOrder, e.Type)
+		}
+	}
+
+	want := []domain.EventType{domain.EventPRCreated, domain.EventPRReassigned, domain.EventPRMerged}
+	if len(prOrder) != len(want) || prOrder[0] != want[0] || prOrder[1] != want[1] || prOrder[2] != want[2] {
+		t.Fatalf("pr events: got %v, want %v", prOrder, want)
+	}
+	if u := got[domain.EventUserActivityChanged].Data.User; u == nil || u.UserID != "u4" || u.IsActive {
+		t.Fatalf("user.activity_changed: got %+v", u)
+	}
+}
+
+func TestPublishFailsWithoutServer(t *testing.T) {
+	ns := runServer(t)
+
+	nc, err := nats.Connect(ns.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := natspub.New(nc, natspub.Config{Prefix: "test", Timeout: 100 * time.Millisecond})
+	if got := pub.Subject(domain.EventPRMerged); got != "test.pr.merged" {
+		t.Fatalf("subject: got %q", got)
+	}
+	nc.Close()
+
+	e := domain.Event{ID: "e1", Type: domain.EventPRMerged, OrgID: domain.DefaultOrg}
+	if err := pub.Publish(context.Background(), e); err == nil {
+		t.Fatal("published over a closed connection")
+	}
+	if err := pub.Close(); err != nil {
+		t.Fatalf("close of a borrowed connection: %v", err)
+	}
+}
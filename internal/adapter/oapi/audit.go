@@ -0,0 +1,87 @@
+package oapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-faster/jx"
+	ht "github.com/ogen-go/ogen/http"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+	pr "github.com/beachrockhotel/pr-reviewer/shared/pkg/openapi/pr/v1"
+)
+
+func mapAuditToSchema(e domain.AuditEntry) pr.AuditEntry {
+	// before and after are required; a created entity has no before.
+	raw := func(b []byte) jx.Raw {
+		if b == nil {
+			return jx.Raw("null")
+		}
+		return jx.Raw(b)
+	}
+	return pr.AuditEntry{
+		AuditID:    e.ID,
+		Action:     pr.AuditEntryAction(e.Action),
+		EntityType: pr.AuditEntityType(e.EntityType),
+		EntityID:   e.EntityID,
+		Actor:      e.Actor,
+		RequestID:  e.RequestID,
+		Before:     raw(e.Before),
+		After:      raw(e.After),
+		CreatedAt:  e.CreatedAt,
+		PrevHash:   e.PrevHash,
+		Hash:       e.Hash,
+	}
+}
+
+func (h *Handler) AuditListGet(ctx context.Context, params pr.AuditListGetParams) (pr.AuditListGetRes, error) {
+	f := domain.AuditFilter{
+		EntityID: params.EntityID.Or(""),
+		Actor:    params.Actor.Or(""),
+		From:     params.From.Or(time.Time{}),
+		To:       params.To.Or(time.Time{}),
+		BeforeID: params.BeforeID.Or(0),
+		Limit:    params.Limit.Or(50),
+	}
+	if t, ok := params.EntityType.Get(); ok {
+		f.EntityType = string(t)
+	}
+
+	list, err := h.audit.List(ctx, f)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalid) {
+			msg := strings.TrimPrefix(err.Error(), domain.ErrInvalid.Error()+": ")
+			er := makeError(pr.ErrorResponseErrorCodeINVALIDARGUMENT, msg)
+			return &er, nil
+		}
+		return nil, err
+	}
+
+	entries := make([]pr.AuditEntry, 0, len(list))
+	for _, e := range list {
+		entries = append(entries, mapAuditToSchema(e))
+	}
+	return &pr.AuditListGetOK{Entries: entries}, nil
+}
+
+func (h *Handler) AuditExportGet(ctx context.Context, params pr.AuditExportGetParams) (pr.AuditExportGetRes, error) {
+	exp, err := h.audit.ExportPR(ctx, params.PullRequestID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		e := notFoundError()
+		return &e, nil
+	case errors.Is(err, usecase.ErrNoSigningKey):
+		return nil, ht.ErrNotImplemented
+	case err != nil:
+		return nil, err
+	}
+	return &pr.SignedPRRecord{
+		Record:    jx.Raw(exp.Record),
+		KeyID:     exp.KeyID,
+		PublicKey: exp.PublicKey,
+		Signature: exp.Signature,
+	}, nil
+}
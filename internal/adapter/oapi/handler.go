@@ -6,6 +6,8 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/beachrockhotel/pr-reviewer/internal/domain"
 	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
@@ -15,18 +17,24 @@ import (
 type Handler struct {
 	pr.UnimplementedHandler
 
-	team *usecase.TeamUsecase
-	user *usecase.UserUsecase
-	prUC *usecase.PRUsecase
-	log  *slog.Logger
+	team  *usecase.TeamUsecase
+	user  *usecase.UserUsecase
+	prUC  *usecase.PRUsecase
+	subs  *usecase.SubscriptionUsecase
+	email *usecase.EmailUsecase
+	audit *usecase.AuditUsecase
+	log   *slog.Logger
 }
 
-func NewHandler(team *usecase.TeamUsecase, user *usecase.UserUsecase, prUC *usecase.PRUsecase, logger *slog.Logger) *Handler {
+func NewHandler(team *usecase.TeamUsecase, user *usecase.UserUsecase, prUC *usecase.PRUsecase, subs *usecase.SubscriptionUsecase, email *usecase.EmailUsecase, audit *usecase.AuditUsecase, logger *slog.Logger) *Handler {
 	return &Handler{
-		team: team,
-		user: user,
-		prUC: prUC,
-		log:  logger,
+		team:  team,
+		user:  user,
+		prUC:  prUC,
+		subs:  subs,
+		email: email,
+		audit: audit,
+		log:   logger,
 	}
 }
 
@@ -43,6 +51,71 @@ func notFoundError() pr.ErrorResponse {
 	return makeError(pr.ErrorResponseErrorCodeNOTFOUND, "resource not found")
 }
 
+func forbiddenError(msg string) pr.ErrorResponse {
+	return makeError(pr.ErrorResponseErrorCodeFORBIDDEN, msg)
+}
+
+func mapRole(r pr.OptRole) domain.Role {
+	if v, ok := r.Get(); ok {
+		return domain.Role(v)
+	}
+	return ""
+}
+
+// optString maps an empty string to an absent field.
+func optString(v string) pr.OptString {
+	if v == "" {
+		return pr.OptString{}
+	}
+	return pr.NewOptString(v)
+}
+
+func optTime(t *time.Time) pr.OptDateTime {
+	if t == nil {
+		return pr.OptDateTime{}
+	}
+	return pr.NewOptDateTime(*t)
+}
+
+func mapMemberToSchema(u domain.User) pr.TeamMember {
+	return pr.TeamMember{
+		UserID:    u.UserID,
+		Username:  u.Username,
+		IsActive:  u.IsActive,
+		Role:      pr.NewOptRole(pr.Role(u.Role)),
+		Email:     optString(u.Email),
+		Timezone:  optString(u.Timezone),
+		AwayUntil: optTime(u.AwayUntil),
+		ManagerID: optString(u.ManagerID),
+	}
+}
+
+func mapTeamToSchema(t domain.Team) pr.Team {
+	members := make([]pr.TeamMember, 0, len(t.Members))
+	for _, u := range t.Members {
+		members = append(members, mapMemberToSchema(u))
+	}
+	return pr.Team{
+		TeamName: t.TeamName,
+		FourEyes: pr.NewOptBool(t.FourEyes),
+		Members:  members,
+	}
+}
+
+func mapUserToSchema(u domain.User) pr.User {
+	return pr.User{
+		UserID:    u.UserID,
+		Username:  u.Username,
+		TeamName:  u.TeamName,
+		IsActive:  u.IsActive,
+		Role:      pr.NewOptRole(pr.Role(u.Role)),
+		Email:     optString(u.Email),
+		Timezone:  optString(u.Timezone),
+		AwayUntil: optTime(u.AwayUntil),
+		ManagerID: optString(u.ManagerID),
+	}
+}
+
 func mapPRToSchema(p domain.PullRequest) pr.PullRequest {
 	revs := make([]string, len(p.AssignedReviewers))
 	copy(revs, p.AssignedReviewers)
@@ -65,6 +138,7 @@ func mapPRToSchema(p domain.PullRequest) pr.PullRequest {
 		AssignedReviewers: revs,
 		CreatedAt:         created,
 		MergedAt:          merged,
+		Version:           p.Version,
 	}
 }
 
@@ -72,38 +146,39 @@ func (h *Handler) TeamAddPost(ctx context.Context, req *pr.Team) (pr.TeamAddPost
 	members := make([]domain.User, 0, len(req.Members))
 	for _, m := range req.Members {
 		members = append(members, domain.User{
-			UserID:   m.UserID,
-			Username: m.Username,
-			TeamName: req.TeamName,
-			IsActive: m.IsActive,
+			UserID:    m.UserID,
+			Username:  m.Username,
+			TeamName:  req.TeamName,
+			IsActive:  m.IsActive,
+			Role:      mapRole(m.Role),
+			Email:     m.Email.Or(""),
+			Timezone:  m.Timezone.Or(""),
+			ManagerID: m.ManagerID.Or(""),
 		})
 	}
 
 	team, err := h.team.CreateTeam(ctx, req.TeamName, members)
 	if err != nil {
-		if errors.Is(err, domain.ErrTeamExists) {
+		switch {
+		case errors.Is(err, domain.ErrTeamExists):
 			er := makeError(pr.ErrorResponseErrorCodeTEAMEXISTS, "team_name already exists")
-			return &er, nil
+			br := pr.TeamAddPostBadRequest(er)
+			return &br, nil
+		case errors.Is(err, domain.ErrForbidden):
+			er := forbiddenError("only team leads and admins may edit teams")
+			fb := pr.TeamAddPostForbidden(er)
+			return &fb, nil
+		case errors.Is(err, domain.ErrInvalid):
+			msg := strings.TrimPrefix(err.Error(), domain.ErrInvalid.Error()+": ")
+			br := pr.TeamAddPostBadRequest(makeError(pr.ErrorResponseErrorCodeINVALIDARGUMENT, msg))
+			return &br, nil
+		default:
+			return nil, err
 		}
-		return nil, err
-	}
-
-	outMembers := make([]pr.TeamMember, 0, len(team.Members))
-	for _, u := range team.Members {
-		outMembers = append(outMembers, pr.TeamMember{
-			UserID:   u.UserID,
-			Username: u.Username,
-			IsActive: u.IsActive,
-		})
-	}
-
-	teamSchema := pr.Team{
-		TeamName: team.TeamName,
-		Members:  outMembers,
 	}
 
 	return &pr.TeamAddPostCreated{
-		Team: pr.NewOptTeam(teamSchema),
+		Team: pr.NewOptTeam(mapTeamToSchema(team)),
 	}, nil
 }
 
@@ -117,23 +192,65 @@ func (h *Handler) TeamGetGet(ctx context.Context, params pr.TeamGetGetParams) (p
 		return nil, err
 	}
 
-	members := make([]pr.TeamMember, 0, len(team.Members))
-	for _, u := range team.Members {
-		members = append(members, pr.TeamMember{
-			UserID:   u.UserID,
-			Username: u.Username,
-			IsActive: u.IsActive,
-		})
+	t := mapTeamToSchema(team)
+	return &t, nil
+}
+
+func (h *Handler) UsersSetIsActivePost(ctx context.Context, req *pr.UsersSetIsActivePostReq) (pr.UsersSetIsActivePostRes, error) {
+	u, err := h.user.SetActive(ctx, req.UserID, req.IsActive)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			er := notFoundError()
+			nf := pr.UsersSetIsActivePostNotFound(er)
+			return &nf, nil
+		case errors.Is(err, domain.ErrForbidden):
+			er := forbiddenError("not allowed to change this user")
+			fb := pr.UsersSetIsActivePostForbidden(er)
+			return &fb, nil
+		default:
+			return nil, err
+		}
 	}
 
-	return &pr.Team{
-		TeamName: team.TeamName,
-		Members:  members,
+	return &pr.UsersSetIsActivePostOK{
+		User: pr.NewOptUser(mapUserToSchema(u)),
 	}, nil
 }
 
-func (h *Handler) UsersSetIsActivePost(ctx context.Context, req *pr.UsersSetIsActivePostReq) (pr.UsersSetIsActivePostRes, error) {
-	u, err := h.user.SetActive(ctx, req.UserID, req.IsActive)
+func (h *Handler) UsersSetAwayPost(ctx context.Context, req *pr.UsersSetAwayPostReq) (pr.UsersSetAwayPostRes, error) {
+	var until *time.Time
+	if t, ok := req.AwayUntil.Get(); ok {
+		until = &t
+	}
+	u, err := h.user.SetAway(ctx, req.UserID, until)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			nf := pr.UsersSetAwayPostNotFound(notFoundError())
+			return &nf, nil
+		case errors.Is(err, domain.ErrForbidden):
+			fb := pr.UsersSetAwayPostForbidden(forbiddenError("not allowed to change this user"))
+			return &fb, nil
+		default:
+			return nil, err
+		}
+	}
+	return &pr.UsersSetAwayPostOK{User: pr.NewOptUser(mapUserToSchema(u))}, nil
+}
+
+func mapPrefsToSchema(p domain.EmailPrefs) *pr.NotificationPrefs {
+	return &pr.NotificationPrefs{
+		UserID:      p.UserID,
+		Assigned:    p.Assigned,
+		Reassigned:  p.Reassigned,
+		SLABreach:   p.SLABreach,
+		DailyDigest: pr.NewOptBool(p.Digest),
+	}
+}
+
+func (h *Handler) UsersGetNotificationPrefsGet(ctx context.Context, params pr.UsersGetNotificationPrefsGetParams) (pr.UsersGetNotificationPrefsGetRes, error) {
+	p, err := h.email.Prefs(ctx, params.UserID)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
 			er := notFoundError()
@@ -141,17 +258,32 @@ func (h *Handler) UsersSetIsActivePost(ctx context.Context, req *pr.UsersSetIsAc
 		}
 		return nil, err
 	}
+	return mapPrefsToSchema(p), nil
+}
 
-	userSchema := pr.User{
-		UserID:   u.UserID,
-		Username: u.Username,
-		TeamName: u.TeamName,
-		IsActive: u.IsActive,
+func (h *Handler) UsersSetNotificationPrefsPost(ctx context.Context, req *pr.NotificationPrefs) (pr.UsersSetNotificationPrefsPostRes, error) {
+	p := domain.EmailPrefs{
+		UserID:     req.UserID,
+		Assigned:   req.Assigned,
+		Reassigned: req.Reassigned,
+		SLABreach:  req.SLABreach,
+		Digest:     req.DailyDigest.Or(true),
 	}
-
-	return &pr.UsersSetIsActivePostOK{
-		User: pr.NewOptUser(userSchema),
-	}, nil
+	if err := h.email.SetPrefs(ctx, p); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			er := notFoundError()
+			nf := pr.UsersSetNotificationPrefsPostNotFound(er)
+			return &nf, nil
+		case errors.Is(err, domain.ErrForbidden):
+			er := forbiddenError("not allowed to change this user")
+			fb := pr.UsersSetNotificationPrefsPostForbidden(er)
+			return &fb, nil
+		default:
+			return nil, err
+		}
+	}
+	return mapPrefsToSchema(p), nil
 }
 
 func (h *Handler) PullRequestCreatePost(ctx context.Context, req *pr.PullRequestCreatePostReq) (pr.PullRequestCreatePostRes, error) {
@@ -173,36 +305,81 @@ func (h *Handler) PullRequestCreatePost(ctx context.Context, req *pr.PullRequest
 
 	prSchema := mapPRToSchema(created)
 
-	return &pr.PullRequestCreatePostCreated{
-		Pr: pr.NewOptPullRequest(prSchema),
+	return &pr.PullRequestCreatePostCreatedHeaders{
+		ETag:     etag(created),
+		Response: pr.PullRequestCreatePostCreated{Pr: pr.NewOptPullRequest(prSchema)},
 	}, nil
 }
 
-func (h *Handler) PullRequestMergePost(ctx context.Context, req *pr.PullRequestMergePostReq) (pr.PullRequestMergePostRes, error) {
-	merged, err := h.prUC.Merge(ctx, req.PullRequestID)
+func (h *Handler) PullRequestMergePost(ctx context.Context, req *pr.PullRequestMergePostReq, params pr.PullRequestMergePostParams) (pr.PullRequestMergePostRes, error) {
+	var merged domain.PullRequest
+	versions, err := expectedVersions(params.IfMatch, req.Version)
+	if err == nil {
+		merged, err = h.prUC.Merge(ctx, req.PullRequestID, versions...)
+	}
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
+		switch {
+		case errors.Is(err, domain.ErrVersionMismatch):
+			pf := pr.PullRequestMergePostPreconditionFailed(versionMismatchError())
+			return &pf, nil
+		case errors.Is(err, domain.ErrInvalid):
+			msg := strings.TrimPrefix(err.Error(), domain.ErrInvalid.Error()+": ")
+			br := pr.PullRequestMergePostBadRequest(makeError(pr.ErrorResponseErrorCodeINVALIDARGUMENT, msg))
+			return &br, nil
+		case errors.Is(err, domain.ErrNotFound):
 			e := notFoundError()
-			return &e, nil
+			nf := pr.PullRequestMergePostNotFound(e)
+			return &nf, nil
+		case errors.Is(err, domain.ErrForbidden):
+			e := forbiddenError("not allowed to merge this PR")
+			fb := pr.PullRequestMergePostForbidden(e)
+			return &fb, nil
+		case errors.Is(err, domain.ErrFourEyes):
+			msg := strings.TrimPrefix(err.Error(), domain.ErrFourEyes.Error()+": ")
+			cf := pr.PullRequestMergePostConflict(makeError(pr.ErrorResponseErrorCodeFOUREYESVIOLATION, msg))
+			return &cf, nil
+		case errors.Is(err, domain.ErrPRClosed):
+			cf := pr.PullRequestMergePostConflict(makeError(pr.ErrorResponseErrorCodePRCLOSED, "cannot merge a closed PR"))
+			return &cf, nil
+		default:
+			return nil, err
 		}
-		return nil, err
 	}
 
 	prSchema := mapPRToSchema(merged)
 
-	return &pr.PullRequestMergePostOK{
-		Pr: pr.NewOptPullRequest(prSchema),
+	return &pr.PullRequestMergePostOKHeaders{
+		ETag:     etag(merged),
+		Response: pr.PullRequestMergePostOK{Pr: pr.NewOptPullRequest(prSchema)},
 	}, nil
 }
 
-func (h *Handler) PullRequestReassignPost(ctx context.Context, req *pr.PullRequestReassignPostReq) (pr.PullRequestReassignPostRes, error) {
-	updated, replacedBy, err := h.prUC.Reassign(ctx, req.PullRequestID, req.OldUserID)
+func (h *Handler) PullRequestReassignPost(ctx context.Context, req *pr.PullRequestReassignPostReq, params pr.PullRequestReassignPostParams) (pr.PullRequestReassignPostRes, error) {
+	var (
+		updated    domain.PullRequest
+		replacedBy string
+	)
+	versions, err := expectedVersions(params.IfMatch, req.Version)
+	if err == nil {
+		updated, replacedBy, err = h.prUC.Reassign(ctx, req.PullRequestID, req.OldUserID, req.Reason.Or(""), versions...)
+	}
 	if err != nil {
 		switch {
+		case errors.Is(err, domain.ErrVersionMismatch):
+			pf := pr.PullRequestReassignPostPreconditionFailed(versionMismatchError())
+			return &pf, nil
+		case errors.Is(err, domain.ErrInvalid):
+			msg := strings.TrimPrefix(err.Error(), domain.ErrInvalid.Error()+": ")
+			br := pr.PullRequestReassignPostBadRequest(makeError(pr.ErrorResponseErrorCodeINVALIDARGUMENT, msg))
+			return &br, nil
 		case errors.Is(err, domain.ErrPRMerged):
 			e := makeError(pr.ErrorResponseErrorCodePRMERGED, "cannot reassign on merged PR")
 			cf := pr.PullRequestReassignPostConflict(e)
 			return &cf, nil
+		case errors.Is(err, domain.ErrPRClosed):
+			e := makeError(pr.ErrorResponseErrorCodePRCLOSED, "cannot reassign on closed PR")
+			cf := pr.PullRequestReassignPostConflict(e)
+			return &cf, nil
 		case errors.Is(err, domain.ErrNotAssigned):
 			e := makeError(pr.ErrorResponseErrorCodeNOTASSIGNED, "reviewer is not assigned to this PR")
 			cf := pr.PullRequestReassignPostConflict(e)
@@ -215,6 +392,10 @@ func (h *Handler) PullRequestReassignPost(ctx context.Context, req *pr.PullReque
 			e := notFoundError()
 			nf := pr.PullRequestReassignPostNotFound(e)
 			return &nf, nil
+		case errors.Is(err, domain.ErrForbidden):
+			e := forbiddenError("not allowed to reassign on this PR")
+			fb := pr.PullRequestReassignPostForbidden(e)
+			return &fb, nil
 		default:
 			return nil, err
 		}
@@ -222,9 +403,12 @@ func (h *Handler) PullRequestReassignPost(ctx context.Context, req *pr.PullReque
 
 	prSchema := mapPRToSchema(updated)
 
-	return &pr.PullRequestReassignPostOK{
-		Pr:         prSchema,
-		ReplacedBy: replacedBy,
+	return &pr.PullRequestReassignPostOKHeaders{
+		ETag: etag(updated),
+		Response: pr.PullRequestReassignPostOK{
+			Pr:         prSchema,
+			ReplacedBy: replacedBy,
+		},
 	}, nil
 }
 
@@ -253,6 +437,7 @@ func (h *Handler) UsersGetReviewGet(ctx context.Context, params pr.UsersGetRevie
 type StatsResponse struct {
 	Open   int `json:"open"`
 	Merged int `json:"merged"`
+	Closed int `json:"closed"`
 }
 
 func (h *Handler) StatsHTTP(w http.ResponseWriter, r *http.Request) {
@@ -273,6 +458,7 @@ func (h *Handler) StatsHTTP(w http.ResponseWriter, r *http.Request) {
 	resp := StatsResponse{
 		Open:   stats[domain.StatusOpen],
 		Merged: stats[domain.StatusMerged],
+		Closed: stats[domain.StatusClosed],
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,51 @@
+package oapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	pr "github.com/beachrockhotel/pr-reviewer/shared/pkg/openapi/pr/v1"
+)
+
+func mapHistoryToSchema(e domain.Event) pr.PRHistoryItem {
+	item := pr.PRHistoryItem{
+		EventID:           e.ID,
+		Type:              pr.EventType(e.Type),
+		OccurredAt:        e.OccurredAt,
+		Actor:             e.Actor,
+		AssignedReviewers: []string{},
+		OldReviewerID:     optString(e.Data.OldReviewerID),
+		NewReviewerID:     optString(e.Data.NewReviewerID),
+		Reason:            optString(e.Data.Reason),
+	}
+	if r := e.Data.Review; r != nil {
+		item.ReviewerID = pr.NewOptString(r.ReviewerID)
+		item.Verdict = pr.NewOptReviewVerdict(pr.ReviewVerdict(r.Verdict))
+	}
+	if p := e.Data.PullRequest; p != nil {
+		item.Status = pr.PRHistoryItemStatus(p.Status)
+		item.AssignedReviewers = append(item.AssignedReviewers, p.AssignedReviewers...)
+	}
+	return item
+}
+
+func (h *Handler) PullRequestHistoryGet(ctx context.Context, params pr.PullRequestHistoryGetParams) (pr.PullRequestHistoryGetRes, error) {
+	events, err := h.prUC.History(ctx, params.PullRequestID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			e := notFoundError()
+			return &e, nil
+		}
+		return nil, err
+	}
+
+	items := make([]pr.PRHistoryItem, 0, len(events))
+	for _, e := range events {
+		items = append(items, mapHistoryToSchema(e))
+	}
+	return &pr.PullRequestHistoryGetOK{
+		PullRequestID: params.PullRequestID,
+		Events:        items,
+	}, nil
+}
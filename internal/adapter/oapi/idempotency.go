@@ -0,0 +1,177 @@
+package oapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+	pr "github.com/beachrockhotel/pr-reviewer/shared/pkg/openapi/pr/v1"
+)
+
+const (
+	// IdempotencyHeader makes a POST safe to retry.
+	IdempotencyHeader = "Idempotency-Key"
+	// ReplayedHeader marks a response replayed for an IdempotencyHeader.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKey  = 255
+	maxIdempotencyBody = 1 << 20
+)
+
+// replayedHeaders are the response headers, besides Content-Type, that a
+// replay repeats: those naming the resource a request created or changed.
+var replayedHeaders = []string{"ETag", "Location", "Content-Location", "Last-Modified"}
+
+// Idempotent stores the response to every POST that carries
+// IdempotencyHeader, with its replayedHeaders, and replays it when the
+// request is retried. Keys belong
+// to a principal: behind RequireScope the middleware uses the caller it
+// resolved, otherwise it authenticates the caller and hands the result on,
+// so that the generated server does not authenticate again. Requests it
+// cannot authenticate go on to next, which rejects them. Responses with a
+// 5xx status are not kept, so such a request can be retried with the same
+// key.
+func (s *Security) Idempotent(uc *usecase.IdempotencyUsecase, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyHeader)
+		if r.Method != http.MethodPost || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		token, ok := bearerToken(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx, err := s.authenticate(r.Context(), token, nil)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		r = r.WithContext(ctx)
+		if !validIdempotencyKey(key) {
+			writeErrorResponse(w, http.StatusBadRequest, makeError(pr.ErrorResponseErrorCodeINVALIDARGUMENT,
+				"Idempotency-Key must be 1 to 255 printable ASCII characters"))
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotencyBody))
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, makeError(pr.ErrorResponseErrorCodeINVALIDARGUMENT,
+				"request body is unreadable or too large"))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// The outcome is stored even if the client has gone away.
+		ctx = context.WithoutCancel(ctx)
+		rec, replay, err := uc.Begin(ctx, key, requestHash(r, body))
+		switch {
+		case errors.Is(err, domain.ErrIdempotencyKeyReused):
+			writeErrorResponse(w, http.StatusConflict, makeError(pr.ErrorResponseErrorCodeIDEMPOTENCYKEYREUSED,
+				"Idempotency-Key was already used with a different request"))
+			return
+		case errors.Is(err, domain.ErrIdempotencyInProgress):
+			writeErrorResponse(w, http.StatusConflict, makeError(pr.ErrorResponseErrorCodeIDEMPOTENCYINPROGRESS,
+				"a request with this Idempotency-Key is still in progress"))
+			return
+		case err != nil:
+			s.log.Error("idempotency: reserve failed", "key", key, "err", err)
+			ErrorHandler(ctx, w, r, err)
+			return
+		case replay:
+			if rec.ContentType != "" {
+				w.Header().Set("Content-Type", rec.ContentType)
+			}
+			for name, v := range rec.Header {
+				w.Header().Set(name, v)
+			}
+			w.Header().Set(ReplayedHeader, "true")
+			w.WriteHeader(rec.Status)
+			_, _ = w.Write(rec.Body)
+			return
+		}
+
+		rw := &recordingWriter{ResponseWriter: w}
+		stored := false
+		defer func() {
+			// Also runs when the handler panics.
+			if stored {
+				return
+			}
+			if err := uc.Release(ctx, rec); err != nil {
+				s.log.Error("idempotency: release failed", "key", key, "err", err)
+			}
+		}()
+		next.ServeHTTP(rw, r)
+
+		if rw.status == 0 {
+			rw.status = http.StatusOK
+		}
+		if rw.status >= http.StatusInternalServerError {
+			return
+		}
+		rec.Status, rec.ContentType, rec.Body = rw.status, rw.Header().Get("Content-Type"), rw.body.Bytes()
+		for _, name := range replayedHeaders {
+			if v := rw.Header().Get(name); v != "" {
+				if rec.Header == nil {
+					rec.Header = make(map[string]string)
+				}
+				rec.Header[name] = v
+			}
+		}
+		if err := uc.Complete(ctx, rec); err != nil {
+			s.log.Error("idempotency: storing response failed", "key", key, "err", err)
+			return
+		}
+		stored = true
+	})
+}
+
+func validIdempotencyKey(key string) bool {
+	if key == "" || len(key) > maxIdempotencyKey {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// requestHash covers what decides the response besides the caller, who is
+// part of the key.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	_, _ = io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	_, _ = h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter passes a response through and keeps a copy of it.
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,193 @@
+package oapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/oapi"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+// countingAuth accepts "ci" with the prs:write scope and counts its calls.
+type countingAuth struct{ calls atomic.Int32 }
+
+func (a *countingAuth) Authenticate(_ context.Context, token string) (domain.Principal, error) {
+	a.calls.Add(1)
+	if token != "ci" {
+		return domain.Principal{}, domain.ErrUnauthorized
+	}
+	return domain.Principal{TokenID: "ci", OrgID: "default", Scopes: []string{domain.ScopeRead, domain.ScopePRsWrite}}, nil
+}
+
+type ownOrg struct{}
+
+func (ownOrg) Resolve(_ context.Context, p domain.Principal, _ string) (string, error) {
+	return p.OrgID, nil
+}
+
+type idemEnv struct {
+	auth *countingAuth
+	sec  *oapi.Security
+	uc   *usecase.IdempotencyUsecase
+}
+
+func newIdemEnv(lease time.Duration) idemEnv {
+	auth := &countingAuth{}
+	return idemEnv{
+		auth: auth,
+		sec:  oapi.NewSecurity(slog.New(slog.DiscardHandler), ownOrg{}, auth),
+		uc:   usecase.NewIdempotencyUsecase(memory.NewIdempotencyRepo(memory.NewStore()), time.Hour, lease),
+	}
+}
+
+func post(t *testing.T, h http.Handler, key, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer ci")
+	req.Header.Set(oapi.IdempotencyHeader, key)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp struct {
+		Error struct{ Code string } `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode %s: %v", w.Body, err)
+	}
+	return resp.Error.Code
+}
+
+// created answers 201 with the request body and headers naming the run,
+// and counts its calls.
+func created(runs *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := runs.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", fmt.Sprintf(`"%d"`, n))
+		w.Header().Set("Location", fmt.Sprintf("/runs/%d", n))
+		w.Header().Set("X-Run", "not replayed")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"run":%d,"body":%s}`, n, body)
+	})
+}
+
+func TestIdempotentReplay(t *testing.T) {
+	e := newIdemEnv(time.Minute)
+	var runs atomic.Int32
+	h := e.sec.Idempotent(e.uc, created(&runs))
+
+	first := post(t, h, "k1", `{"id":"pr-1"}`)
+	if first.Code != http.StatusCreated || first.Header().Get(oapi.ReplayedHeader) != "" {
+		t.Fatalf("first: got %d %s", first.Code, first.Body)
+	}
+	retry := post(t, h, "k1", `{"id":"pr-1"}`)
+	if retry.Code != http.StatusCreated || retry.Header().Get(oapi.ReplayedHeader) != "true" ||
+		retry.Header().Get("Content-Type") != "application/json" || retry.Body.String() != first.Body.String() {
+		t.Fatalf("retry: got %d %v %s", retry.Code, retry.Header(), retry.Body)
+	}
+	if retry.Header().Get("ETag") != `"1"` || retry.Header().Get("Location") != "/runs/1" || retry.Header().Get("X-Run") != "" {
+		t.Fatalf("retry: got %d %v %s", retry.Code, retry.Header(), retry.Body)
+	}
+	if runs.Load() != 1 {
+		t.Fatalf("handler ran %d times, want 1", runs.Load())
+	}
+
+	other := post(t, h, "k1", `{"id":"pr-2"}`)
+	if other.Code != http.StatusConflict || errorCode(t, other) != "IDEMPOTENCY_KEY_REUSED" {
+		t.Fatalf("other body: got %d %s", other.Code, other.Body)
+	}
+	if w := post(t, h, "k2", `{"id":"pr-2"}`); w.Code != http.StatusCreated || runs.Load() != 2 {
+		t.Fatalf("other key: got %d, %d runs", w.Code, runs.Load())
+	}
+}
+
+func TestIdempotentInProgress(t *testing.T) {
+	e := newIdemEnv(time.Minute)
+	var runs atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	h := e.sec.Idempotent(e.uc, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if runs.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- post(t, h, "k1", `{}`) }()
+	<-started
+	if w := post(t, h, "k1", `{}`); w.Code != http.StatusConflict || errorCode(t, w) != "IDEMPOTENCY_IN_PROGRESS" {
+		t.Fatalf("concurrent retry: got %d %s", w.Code, w.Body)
+	}
+	close(release)
+	if w := <-done; w.Code != http.StatusCreated {
+		t.Fatalf("first: got %d %s", w.Code, w.Body)
+	}
+	if w := post(t, h, "k1", `{}`); w.Code != http.StatusCreated || w.Header().Get(oapi.ReplayedHeader) != "true" || runs.Load() != 1 {
+		t.Fatalf("after completion: got %d, %d runs", w.Code, runs.Load())
+	}
+}
+
+func TestIdempotentTakeover(t *testing.T) {
+	// With no lease, a request that is still running counts as abandoned.
+	e := newIdemEnv(0)
+	var runs atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	h := e.sec.Idempotent(e.uc, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := runs.Add(1)
+		if n == 1 {
+			close(started)
+			<-release
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, n)
+	}))
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- post(t, h, "k1", `{}`) }()
+	<-started
+	if w := post(t, h, "k1", `{}`); w.Code != http.StatusCreated || w.Body.String() != "2" {
+		t.Fatalf("takeover: got %d %s", w.Code, w.Body)
+	}
+	close(release)
+	<-done
+
+	// The stale request finished last but lost the key.
+	if w := post(t, h, "k1", `{}`); w.Header().Get(oapi.ReplayedHeader) != "true" || w.Body.String() != "2" {
+		t.Fatalf("replay: got %d %v %s", w.Code, w.Header(), w.Body)
+	}
+}
+
+func TestIdempotentAuthenticatesOnce(t *testing.T) {
+	e := newIdemEnv(time.Minute)
+	var runs atomic.Int32
+	var caller domain.Principal
+	inner := created(&runs)
+	h := e.sec.RequireScope(domain.ScopePRsWrite, e.sec.Idempotent(e.uc, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller, _ = domain.PrincipalFromContext(r.Context())
+		inner.ServeHTTP(w, r)
+	})))
+
+	if w := post(t, h, "k1", `{}`); w.Code != http.StatusCreated || caller.TokenID != "ci" {
+		t.Fatalf("got %d %s as %+v", w.Code, w.Body, caller)
+	}
+	if n := e.auth.calls.Load(); n != 1 {
+		t.Fatalf("authenticated %d times, want 1", n)
+	}
+}
@@ -0,0 +1,67 @@
+package oapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	pr "github.com/beachrockhotel/pr-reviewer/shared/pkg/openapi/pr/v1"
+)
+
+func (h *Handler) PullRequestReviewPost(ctx context.Context, req *pr.PullRequestReviewPostReq, params pr.PullRequestReviewPostParams) (pr.PullRequestReviewPostRes, error) {
+	var updated domain.PullRequest
+	versions, err := expectedVersions(params.IfMatch, req.Version)
+	if err == nil {
+		updated, err = h.prUC.Review(ctx, req.PullRequestID, req.ReviewerID, domain.ReviewVerdict(req.Verdict), versions...)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrVersionMismatch):
+			pf := pr.PullRequestReviewPostPreconditionFailed(versionMismatchError())
+			return &pf, nil
+		case errors.Is(err, domain.ErrInvalid):
+			msg := strings.TrimPrefix(err.Error(), domain.ErrInvalid.Error()+": ")
+			br := pr.PullRequestReviewPostBadRequest(makeError(pr.ErrorResponseErrorCodeINVALIDARGUMENT, msg))
+			return &br, nil
+		case errors.Is(err, domain.ErrNotFound):
+			nf := pr.PullRequestReviewPostNotFound(notFoundError())
+			return &nf, nil
+		case errors.Is(err, domain.ErrPRMerged):
+			cf := pr.PullRequestReviewPostConflict(makeError(pr.ErrorResponseErrorCodePRMERGED, "cannot review a merged PR"))
+			return &cf, nil
+		case errors.Is(err, domain.ErrPRClosed):
+			cf := pr.PullRequestReviewPostConflict(makeError(pr.ErrorResponseErrorCodePRCLOSED, "cannot review a closed PR"))
+			return &cf, nil
+		case errors.Is(err, domain.ErrNotAssigned):
+			cf := pr.PullRequestReviewPostConflict(makeError(pr.ErrorResponseErrorCodeNOTASSIGNED, "reviewer is not assigned to this PR"))
+			return &cf, nil
+		case errors.Is(err, domain.ErrForbidden):
+			fb := pr.PullRequestReviewPostForbidden(forbiddenError("users may only record their own review"))
+			return &fb, nil
+		default:
+			return nil, err
+		}
+	}
+	return &pr.PullRequestReviewPostOKHeaders{
+		ETag:     etag(updated),
+		Response: pr.PullRequestReviewPostOK{Pr: mapPRToSchema(updated)},
+	}, nil
+}
+
+func (h *Handler) TeamSetPolicyPost(ctx context.Context, req *pr.TeamSetPolicyPostReq) (pr.TeamSetPolicyPostRes, error) {
+	team, err := h.team.SetFourEyes(ctx, req.TeamName, req.FourEyes)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			nf := pr.TeamSetPolicyPostNotFound(notFoundError())
+			return &nf, nil
+		case errors.Is(err, domain.ErrForbidden):
+			fb := pr.TeamSetPolicyPostForbidden(forbiddenError("only the team's leads and admins may change its policy"))
+			return &fb, nil
+		default:
+			return nil, err
+		}
+	}
+	return &pr.TeamSetPolicyPostOK{Team: mapTeamToSchema(team)}, nil
+}
@@ -0,0 +1,250 @@
+package oapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-faster/jx"
+	"github.com/ogen-go/ogen/ogenerrors"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+	pr "github.com/beachrockhotel/pr-reviewer/shared/pkg/openapi/pr/v1"
+)
+
+// Authenticator resolves a bearer value to a principal and returns
+// domain.ErrUnauthorized for values it does not accept.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (domain.Principal, error)
+}
+
+var _ Authenticator = (*usecase.TokenUsecase)(nil)
+
+// TenantResolver picks the organization a request is scoped to from the
+// principal and the organization requested via OrgHeader.
+type TenantResolver interface {
+	Resolve(ctx context.Context, p domain.Principal, requested string) (string, error)
+}
+
+var _ TenantResolver = (*usecase.OrgUsecase)(nil)
+
+// OrgHeader picks the organization of a request made with a cross-org token.
+const OrgHeader = "X-Org-ID"
+
+type requestedOrgKey struct{}
+
+// TenantHeader passes OrgHeader to the security handler, which only sees the
+// request context.
+func TenantHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if org := r.Header.Get(OrgHeader); org != "" {
+			r = r.WithContext(context.WithValue(r.Context(), requestedOrgKey{}, org))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequestIDHeader carries the id under which a request's changes are
+// recorded in the audit log.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID adopts the caller's RequestIDHeader, or generates one, and
+// echoes it in the response.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			var b [8]byte
+			if _, err := rand.Read(b[:]); err != nil {
+				// crypto/rand does not fail on supported platforms.
+				panic(err)
+			}
+			id = hex.EncodeToString(b[:])
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(domain.WithRequestID(r.Context(), id)))
+	})
+}
+
+// validRequestID accepts ids that are safe to store and log as they are.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', strings.ContainsRune("-_.:", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Security implements pr.SecurityHandler. Authenticators are tried in order
+// until one accepts the bearer value; the scopes an operation requires come
+// from the spec and arrive in BearerAuth.Roles.
+type Security struct {
+	auths []Authenticator
+	orgs  TenantResolver
+	log   *slog.Logger
+}
+
+func NewSecurity(logger *slog.Logger, orgs TenantResolver, auths ...Authenticator) *Security {
+	return &Security{auths: auths, orgs: orgs, log: logger}
+}
+
+func (s *Security) HandleBearerAuth(ctx context.Context, operationName pr.OperationName, t pr.BearerAuth) (context.Context, error) {
+	ctx, err := s.authenticate(ctx, t.Token, t.Roles)
+	if err != nil {
+		s.log.Debug("auth: request rejected", "operation", operationName, "err", err)
+		return nil, err
+	}
+	return ctx, nil
+}
+
+// RequireScope protects handlers that are mounted next to the generated
+// server, such as /stats, with the same tokens and scopes.
+func (s *Security) RequireScope(scope string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			ErrorHandler(r.Context(), w, r, domain.ErrUnauthorized)
+			return
+		}
+
+		ctx, err := s.authenticate(r.Context(), token, []string{scope})
+		if err != nil {
+			s.log.Debug("auth: request rejected", "path", r.URL.Path, "err", err)
+			ErrorHandler(r.Context(), w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authenticated is the outcome of authenticating a request, kept in its
+// context so that middleware and the generated server resolve the caller
+// once.
+type authenticated struct {
+	token string
+	p     domain.Principal
+	org   string
+}
+
+type authenticatedKey struct{}
+
+func (s *Security) authenticate(ctx context.Context, token string, scopes []string) (context.Context, error) {
+	a, ok := ctx.Value(authenticatedKey{}).(authenticated)
+	if !ok || a.token != token {
+		p, err := s.principal(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		requested, _ := ctx.Value(requestedOrgKey{}).(string)
+		org, err := s.orgs.Resolve(ctx, p, requested)
+		if err != nil {
+			if errors.Is(err, domain.ErrForbidden) {
+				return nil, &tenantError{err: err}
+			}
+			return nil, err
+		}
+		a = authenticated{token: token, p: p, org: org}
+	}
+	for _, scope := range scopes {
+		if !a.p.HasScope(scope) {
+			return nil, &scopeError{scope: scope}
+		}
+	}
+	ctx = context.WithValue(ctx, authenticatedKey{}, a)
+	return domain.WithOrg(domain.WithPrincipal(ctx, a.p), a.org), nil
+}
+
+func (s *Security) principal(ctx context.Context, token string) (domain.Principal, error) {
+	err := domain.ErrUnauthorized
+	for _, a := range s.auths {
+		var p domain.Principal
+		p, err = a.Authenticate(ctx, token)
+		if err == nil {
+			return p, nil
+		}
+		if !errors.Is(err, domain.ErrUnauthorized) {
+			return domain.Principal{}, err
+		}
+	}
+	return domain.Principal{}, err
+}
+
+type scopeError struct{ scope string }
+
+func (e *scopeError) Error() string { return fmt.Sprintf("token lacks scope %q", e.scope) }
+
+func (e *scopeError) Unwrap() error { return domain.ErrForbidden }
+
+// tenantError keeps the reason an organization was refused readable after
+// ogen wraps it into a security error.
+type tenantError struct{ err error }
+
+func (e *tenantError) Error() string {
+	return strings.TrimPrefix(e.err.Error(), domain.ErrForbidden.Error()+": ")
+}
+
+func (e *tenantError) Unwrap() error { return e.err }
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+// ErrorHandler is the server error handler. Missing scopes are reported
+// like any other FORBIDDEN response, authentication failures as 401 with
+// ogen's error shape.
+func ErrorHandler(ctx context.Context, w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domain.ErrForbidden):
+		msg := "forbidden"
+		var (
+			se *scopeError
+			te *tenantError
+		)
+		switch {
+		case errors.As(err, &se):
+			msg = se.Error()
+		case errors.As(err, &te):
+			msg = te.Error()
+		}
+		writeErrorResponse(w, http.StatusForbidden, forbiddenError(msg))
+	case errors.Is(err, domain.ErrUnauthorized):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+
+		e := jx.GetEncoder()
+		defer jx.PutEncoder(e)
+		e.ObjStart()
+		e.FieldStart("error_message")
+		e.StrEscape(err.Error())
+		e.ObjEnd()
+		_, _ = w.Write(e.Bytes())
+	default:
+		ogenerrors.DefaultErrorHandler(ctx, w, r, err)
+	}
+}
+
+func writeErrorResponse(w http.ResponseWriter, status int, resp pr.ErrorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	e := jx.GetEncoder()
+	defer jx.PutEncoder(e)
+	resp.Encode(e)
+	_, _ = w.Write(e.Bytes())
+}
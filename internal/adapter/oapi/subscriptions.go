@@ -0,0 +1,113 @@
+package oapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	pr "github.com/beachrockhotel/pr-reviewer/shared/pkg/openapi/pr/v1"
+)
+
+func mapSubscriptionToSchema(s domain.Subscription) pr.Subscription {
+	types := make([]pr.EventType, 0, len(s.EventTypes))
+	for _, t := range s.EventTypes {
+		types = append(types, pr.EventType(t))
+	}
+	return pr.Subscription{
+		SubscriptionID: s.ID,
+		URL:            s.URL,
+		EventTypes:     types,
+		CreatedAt:      s.CreatedAt,
+	}
+}
+
+func mapDeliveryToSchema(d domain.EventDelivery) pr.EventDelivery {
+	return pr.EventDelivery{
+		DeliveryID:     d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      pr.EventType(d.EventType),
+		Status:         pr.EventDeliveryStatus(d.Status),
+		Attempts:       d.Attempts,
+		ResponseCode:   d.ResponseCode,
+		LastError:      d.LastError,
+		NextAttemptAt:  d.NextAttemptAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
+
+func (h *Handler) SubscriptionsCreatePost(ctx context.Context, req *pr.SubscriptionsCreatePostReq) (pr.SubscriptionsCreatePostRes, error) {
+	types := make([]domain.EventType, 0, len(req.EventTypes))
+	for _, t := range req.EventTypes {
+		types = append(types, domain.EventType(t))
+	}
+
+	sub, err := h.subs.Create(ctx, req.URL, req.Secret.Or(""), types)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalid) {
+			msg := strings.TrimPrefix(err.Error(), domain.ErrInvalid.Error()+": ")
+			er := makeError(pr.ErrorResponseErrorCodeINVALIDARGUMENT, msg)
+			return &er, nil
+		}
+		return nil, err
+	}
+
+	out := mapSubscriptionToSchema(sub)
+	out.Secret = pr.NewOptString(sub.Secret)
+	return &pr.SubscriptionsCreatePostCreated{Subscription: out}, nil
+}
+
+func (h *Handler) SubscriptionsListGet(ctx context.Context) (*pr.SubscriptionsListGetOK, error) {
+	list, err := h.subs.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make([]pr.Subscription, 0, len(list))
+	for _, s := range list {
+		subs = append(subs, mapSubscriptionToSchema(s))
+	}
+	return &pr.SubscriptionsListGetOK{Subscriptions: subs}, nil
+}
+
+func (h *Handler) SubscriptionsDeletePost(ctx context.Context, req *pr.SubscriptionsDeletePostReq) (pr.SubscriptionsDeletePostRes, error) {
+	if err := h.subs.Delete(ctx, req.SubscriptionID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			er := notFoundError()
+			return &er, nil
+		}
+		return nil, err
+	}
+	return &pr.SubscriptionsDeletePostNoContent{}, nil
+}
+
+func (h *Handler) SubscriptionsDeliveriesGet(ctx context.Context, params pr.SubscriptionsDeliveriesGetParams) (pr.SubscriptionsDeliveriesGetRes, error) {
+	list, err := h.subs.Deliveries(ctx, params.SubscriptionID, params.Limit.Or(50))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			er := notFoundError()
+			return &er, nil
+		}
+		return nil, err
+	}
+
+	deliveries := make([]pr.EventDelivery, 0, len(list))
+	for _, d := range list {
+		deliveries = append(deliveries, mapDeliveryToSchema(d))
+	}
+	return &pr.SubscriptionsDeliveriesGetOK{Deliveries: deliveries}, nil
+}
+
+func (h *Handler) SubscriptionsRedeliverPost(ctx context.Context, req *pr.SubscriptionsRedeliverPostReq) (pr.SubscriptionsRedeliverPostRes, error) {
+	d, err := h.subs.Redeliver(ctx, req.DeliveryID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			er := notFoundError()
+			return &er, nil
+		}
+		return nil, err
+	}
+	return &pr.SubscriptionsRedeliverPostAccepted{Delivery: mapDeliveryToSchema(d)}, nil
+}
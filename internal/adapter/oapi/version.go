@@ -0,0 +1,55 @@
+package oapi
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	pr "github.com/beachrockhotel/pr-reviewer/shared/pkg/openapi/pr/v1"
+)
+
+// etag is the strong entity tag of the PR's version.
+func etag(p domain.PullRequest) pr.OptString {
+	return pr.NewOptString(`"` + strconv.FormatInt(p.Version, 10) + `"`)
+}
+
+// expectedVersions returns the versions the client accepts the PR to be at,
+// from the If-Match header (RFC 9110, section 13.1.1) or the version field
+// of the body, and none when it sent neither or If-Match is "*". If-Match
+// may list several tags; it compares them strongly, so weak tags match no
+// version and a list of only weak tags fails with domain.ErrVersionMismatch.
+// If both are given, the body's version must be one of the listed ones.
+func expectedVersions(ifMatch pr.OptString, body pr.OptInt64) ([]int64, error) {
+	var versions []int64
+	listed := false
+	if h, ok := ifMatch.Get(); ok && strings.TrimSpace(h) != "*" {
+		listed = true
+		for tag := range strings.SplitSeq(h, ",") {
+			tag = strings.TrimSpace(tag)
+			if strings.HasPrefix(tag, "W/") {
+				continue
+			}
+			n, err := strconv.ParseInt(strings.Trim(tag, `"`), 10, 64)
+			if err != nil || n < 1 || len(tag) < 3 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+				return nil, fmt.Errorf(`%w: If-Match must be * or a list of quoted versions such as "3"`, domain.ErrInvalid)
+			}
+			versions = append(versions, n)
+		}
+		if len(versions) == 0 {
+			return nil, domain.ErrVersionMismatch
+		}
+	}
+	if v, ok := body.Get(); ok {
+		if listed && !slices.Contains(versions, v) {
+			return nil, fmt.Errorf("%w: If-Match and version disagree", domain.ErrInvalid)
+		}
+		versions = []int64{v}
+	}
+	return versions, nil
+}
+
+func versionMismatchError() pr.ErrorResponse {
+	return makeError(pr.ErrorResponseErrorCodeVERSIONMISMATCH, "pull request has changed; fetch it and retry")
+}
@@ -0,0 +1,94 @@
+package oapi_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/oapi"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+	pr "github.com/beachrockhotel/pr-reviewer/shared/pkg/openapi/pr/v1"
+)
+
+// newVersionHandler serves pr-1 by u1, reviewed by u2 and u3, at version 1.
+func newVersionHandler(t *testing.T) *oapi.Handler {
+	t.Helper()
+	ctx := context.Background()
+	s := memory.NewStore()
+	teams, users, prs := memory.NewTeamRepo(s), memory.NewUserRepo(s), memory.NewPRRepo(s)
+	if err := teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{
+		{UserID: "u1", Username: "u1", IsActive: true},
+		{UserID: "u2", Username: "u2", IsActive: true},
+		{UserID: "u3", Username: "u3", IsActive: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prs.CreatePRWithReviewers(ctx, domain.PullRequest{ID: "pr-1", Name: "x", AuthorID: "u1"}, []string{"u2", "u3"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	return oapi.NewHandler(usecase.NewTeamUsecase(teams, users), usecase.NewUserUsecase(users, prs),
+		usecase.NewPRUsecase(users, prs), nil, nil, nil, slog.New(slog.DiscardHandler))
+}
+
+func TestIfMatch(t *testing.T) {
+	h := newVersionHandler(t)
+	ctx := domain.WithPrincipal(context.Background(), domain.Principal{UserID: "u2"})
+
+	// Each accepted review changes the verdict, moving the PR on by one
+	// version.
+	verdicts := []pr.ReviewVerdict{pr.ReviewVerdictApproved, pr.ReviewVerdictChangesRequested}
+	accepted := 0
+	for _, tc := range []struct {
+		name    string
+		ifMatch string
+		version int64
+		want    string
+	}{
+		{"list", `"7", "1"`, 0, `"2"`},
+		{"weak", `W/"2"`, 0, "412"},
+		{"weak in list", `W/"2" , "2"`, 0, `"3"`},
+		{"stale list", `"1","2"`, 0, "412"},
+		{"star", ` * `, 0, `"4"`},
+		{"star and body", `*`, 4, `"5"`},
+		{"body in list", `"4", "5"`, 5, `"6"`},
+		{"body not in list", `"6", "7"`, 5, "400"},
+		{"unquoted", `"6", 7`, 0, "400"},
+		{"empty element", `"6",`, 0, "400"},
+		{"zero", `"0"`, 0, "400"},
+	} {
+		req := &pr.PullRequestReviewPostReq{PullRequestID: "pr-1", ReviewerID: "u2", Verdict: verdicts[accepted%2]}
+		if tc.version != 0 {
+			req.Version = pr.NewOptInt64(tc.version)
+		}
+		res, err := h.PullRequestReviewPost(ctx, req, pr.PullRequestReviewPostParams{IfMatch: pr.NewOptString(tc.ifMatch)})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		var got string
+		switch res := res.(type) {
+		case *pr.PullRequestReviewPostOKHeaders:
+			got = res.ETag.Or("")
+			accepted++
+		case *pr.PullRequestReviewPostPreconditionFailed:
+			got = "412"
+		case *pr.PullRequestReviewPostBadRequest:
+			got = "400"
+		default:
+			t.Fatalf("%s: got %T", tc.name, res)
+		}
+		if got != tc.want {
+			t.Errorf("%s: If-Match %s: got %s, want %s", tc.name, tc.ifMatch, got, tc.want)
+		}
+	}
+
+	res, err := h.PullRequestMergePost(ctx, &pr.PullRequestMergePostReq{PullRequestID: "pr-1"},
+		pr.PullRequestMergePostParams{IfMatch: pr.NewOptString(`"5", "6"`)})
+	if ok, _ := res.(*pr.PullRequestMergePostOKHeaders); err != nil || ok == nil || ok.ETag.Or("") != `"7"` {
+		t.Fatalf("merge: got %#v, %v", res, err)
+	}
+}
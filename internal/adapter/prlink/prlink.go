@@ -0,0 +1,38 @@
+// Package prlink builds web links to pull requests from their ids.
+package prlink
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/github"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/webhook"
+)
+
+// Builder knows the web roots of the forges PRs come from.
+type Builder struct {
+	github string
+	gitlab string
+}
+
+// New defaults empty roots to github.com and gitlab.com.
+func New(githubURL, gitlabURL string) Builder {
+	if githubURL == "" {
+		githubURL = "https://github.com"
+	}
+	if gitlabURL == "" {
+		gitlabURL = "https://gitlab.com"
+	}
+	return Builder{github: strings.TrimRight(githubURL, "/"), gitlab: strings.TrimRight(gitlabURL, "/")}
+}
+
+// Link returns "" for PRs that did not come from GitHub or GitLab.
+func (b Builder) Link(prID string) string {
+	if repo, n, ok := github.ParsePRID(prID); ok {
+		return b.github + "/" + repo + "/pull/" + strconv.Itoa(n)
+	}
+	if project, iid, ok := webhook.ParseGitLabPRID(prID); ok {
+		return b.gitlab + "/" + project + "/-/merge_requests/" + strconv.Itoa(iid)
+	}
+	return ""
+}
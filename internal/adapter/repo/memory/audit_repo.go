@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type AuditRepo struct{ s *Store }
+
+func NewAuditRepo(s *Store) *AuditRepo { return &AuditRepo{s: s} }
+
+// appendAudit must be called with the store lock held, by the write that
+// the entry describes; it links the entry to the last one of its
+// organization. Entries whose state did not change are dropped.
+func (s *Store) appendAudit(e domain.AuditEntry) {
+	if e.Before != nil && bytes.Equal(e.Before, e.After) {
+		return
+	}
+	for i := len(s.audit) - 1; i >= 0; i-- {
+		if s.audit[i].OrgID == e.OrgID {
+			e.PrevHash = s.audit[i].Hash
+			break
+		}
+	}
+	e.ID = int64(len(s.audit) + 1)
+	e.CreatedAt = s.now()
+	e.Hash = e.ChainHash()
+	s.audit = append(s.audit, e)
+}
+
+func (r *AuditRepo) ListAudit(ctx context.Context, f domain.AuditFilter) ([]domain.AuditEntry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	org := domain.OrgFromContext(ctx)
+	var out []domain.AuditEntry
+	for i := len(r.s.audit) - 1; i >= 0 && len(out) < f.Limit; i-- {
+		e := r.s.audit[i]
+		if e.OrgID == org && f.Match(e) {
+			e.Before, e.After = slices.Clone(e.Before), slices.Clone(e.After)
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (r *AuditRepo) ListAuditChain(ctx context.Context, afterID int64, limit int) ([]domain.AuditEntry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	org := domain.OrgFromContext(ctx)
+	var out []domain.AuditEntry
+	for _, e := range r.s.audit {
+		if len(out) == limit {
+			break
+		}
+		if e.OrgID == org && e.ID > afterID {
+			e.Before, e.After = slices.Clone(e.Before), slices.Clone(e.After)
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,31 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/repotest"
+)
+
+func TestContract(t *testing.T) {
+	repotest.Run(t, func(*testing.T) repotest.Repos {
+		s := memory.NewStore()
+		return repotest.Repos{
+			Teams:       memory.NewTeamRepo(s),
+			Users:       memory.NewUserRepo(s),
+			PRs:         memory.NewPRRepo(s),
+			Orgs:        memory.NewOrgRepo(s),
+			Deliveries:  memory.NewDeliveryRepo(s),
+			GitLab:      memory.NewGitLabProjectRepo(s),
+			Syncs:       memory.NewReviewerSyncRepo(s),
+			Subs:        memory.NewSubscriptionRepo(s),
+			Events:      memory.NewEventDeliveryRepo(s),
+			Outbox:      memory.NewOutboxRepo(s),
+			Slack:       memory.NewSlackRepo(s),
+			Email:       memory.NewEmailRepo(s),
+			Digest:      memory.NewDigestRepo(s),
+			Audit:       memory.NewAuditRepo(s),
+			Idempotency: memory.NewIdempotencyRepo(s),
+		}
+	})
+}
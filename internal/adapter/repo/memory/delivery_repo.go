@@ -0,0 +1,27 @@
+package memory
+
+import "context"
+
+type DeliveryRepo struct{ s *Store }
+
+func NewDeliveryRepo(s *Store) *DeliveryRepo { return &DeliveryRepo{s: s} }
+
+func (r *DeliveryRepo) ClaimDelivery(_ context.Context, source, deliveryID string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := key{org: source, id: deliveryID}
+	if _, ok := r.s.deliveries[k]; ok {
+		return false, nil
+	}
+	r.s.deliveries[k] = struct{}{}
+	return true, nil
+}
+
+func (r *DeliveryRepo) ReleaseDelivery(_ context.Context, source, deliveryID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.deliveries, key{org: source, id: deliveryID})
+	return nil
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type DigestRepo struct{ s *Store }
+
+func NewDigestRepo(s *Store) *DigestRepo { return &DigestRepo{s: s} }
+
+type digestKey struct {
+	key
+	day string
+}
+
+func (r *DigestRepo) ListDigestRecipients(_ context.Context) ([]domain.ReviewDigest, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	reviewing := make(map[key]bool)
+	for k, p := range r.s.prs {
+		if p.pr.Status != domain.StatusOpen {
+			continue
+		}
+		for _, id := range p.reviewers {
+			reviewing[key{org: k.org, id: id}] = true
+		}
+	}
+
+	var out []domain.ReviewDigest
+	for k, u := range r.s.users {
+		if u.Email == "" || !u.IsActive || !reviewing[k] {
+			continue
+		}
+		if p, ok := r.s.emailPrefs[k]; ok && !p.Digest {
+			continue
+		}
+		out = append(out, domain.ReviewDigest{OrgID: k.org, To: u})
+	}
+	slices.SortFunc(out, func(a, b domain.ReviewDigest) int {
+		return cmp.Or(cmp.Compare(a.OrgID, b.OrgID), cmp.Compare(a.To.UserID, b.To.UserID))
+	})
+	return out, nil
+}
+
+func (r *DigestRepo) ClaimDigest(ctx context.Context, userID, day string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := digestKey{keyOf(ctx, userID), day}
+	if _, ok := r.s.users[k.key]; !ok {
+		return false, domain.ErrNotFound
+	}
+	if _, ok := r.s.digests[k]; ok {
+		return false, nil
+	}
+	r.s.digests[k] = struct{}{}
+	return true, nil
+}
+
+func (r *DigestRepo) ReleaseDigest(ctx context.Context, userID, day string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.digests, digestKey{keyOf(ctx, userID), day})
+	return nil
+}
@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type EmailRepo struct{ s *Store }
+
+func NewEmailRepo(s *Store) *EmailRepo { return &EmailRepo{s: s} }
+
+func (r *EmailRepo) GetEmailPrefs(ctx context.Context, userID string) (domain.EmailPrefs, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p, ok := r.s.emailPrefs[keyOf(ctx, userID)]
+	if !ok {
+		return domain.EmailPrefs{}, domain.ErrNotFound
+	}
+	return p, nil
+}
+
+func (r *EmailRepo) SetEmailPrefs(ctx context.Context, p domain.EmailPrefs) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, p.UserID)
+	if _, ok := r.s.users[k]; !ok {
+		return domain.ErrNotFound
+	}
+	r.s.emailPrefs[k] = p
+	return nil
+}
+
+func (r *EmailRepo) EnqueueEmail(ctx context.Context, n domain.EmailNotification) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	org := domain.OrgFromContext(ctx)
+	if n.Kind == domain.EmailSLABreach && r.s.slaQueued(org, n.UserID, n.PR.ID) {
+		return nil
+	}
+	ts := r.s.now()
+	r.s.emails = append(r.s.emails, domain.EmailNotification{
+		ID:            int64(len(r.s.emails) + 1),
+		OrgID:         org,
+		UserID:        n.UserID,
+		Kind:          n.Kind,
+		PR:            clonePR(n.PR),
+		OldReviewerID: n.OldReviewerID,
+		Status:        domain.EmailPending,
+		NextAttemptAt: ts,
+		CreatedAt:     ts,
+		UpdatedAt:     ts,
+	})
+	return nil
+}
+
+// slaQueued must be called with the store lock held.
+func (s *Store) slaQueued(org, userID, prID string) bool {
+	for _, e := range s.emails {
+		if e.Kind == domain.EmailSLABreach && e.OrgID == org && e.UserID == userID && e.PR.ID == prID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *EmailRepo) ClaimEmails(_ context.Context, queuedBefore, now, leaseUntil time.Time, limit int) ([]domain.EmailNotification, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	// Pending items are in creation order, so users come out ordered by
+	// their oldest item.
+	var (
+		users   []key
+		pending = make(map[key][]int)
+	)
+	for i, e := range r.s.emails {
+		if e.Status != domain.EmailPending {
+			continue
+		}
+		k := key{org: e.OrgID, id: e.UserID}
+		if _, ok := pending[k]; !ok {
+			users = append(users, k)
+		}
+		pending[k] = append(pending[k], i)
+	}
+
+	var claimed []int
+	for _, k := range users {
+		if limit == 0 {
+			break
+		}
+		idx := pending[k]
+		if r.s.emails[idx[0]].CreatedAt.After(queuedBefore) ||
+			slices.ContainsFunc(idx, func(i int) bool { return r.s.emails[i].NextAttemptAt.After(now) }) {
+			continue
+		}
+		claimed = append(claimed, idx...)
+		limit--
+	}
+	slices.Sort(claimed)
+
+	out := make([]domain.EmailNotification, 0, len(claimed))
+	for _, i := range claimed {
+		r.s.emails[i].NextAttemptAt = leaseUntil
+		out = append(out, cloneEmail(r.s.emails[i]))
+	}
+	return out, nil
+}
+
+func (r *EmailRepo) SaveEmail(_ context.Context, n domain.EmailNotification) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if n.ID < 1 || n.ID > int64(len(r.s.emails)) {
+		return domain.ErrNotFound
+	}
+	stored := &r.s.emails[n.ID-1]
+	stored.Status = n.Status
+	stored.Attempts = n.Attempts
+	stored.LastError = n.LastError
+	stored.NextAttemptAt = n.NextAttemptAt
+	stored.UpdatedAt = r.s.now()
+	return nil
+}
+
+func (r *EmailRepo) ListEmails(_ context.Context, status domain.EmailStatus) ([]domain.EmailNotification, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var out []domain.EmailNotification
+	for _, e := range r.s.emails {
+		if status == "" || e.Status == status {
+			out = append(out, cloneEmail(e))
+		}
+	}
+	return out, nil
+}
+
+func (r *EmailRepo) ListSLABreaches(_ context.Context, createdBefore time.Time, limit int) ([]domain.EmailNotification, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var out []domain.EmailNotification
+	for k, p := range r.s.prs {
+		if p.pr.Status != domain.StatusOpen || p.pr.CreatedAt.After(createdBefore) {
+			continue
+		}
+		for _, rid := range p.reviewers {
+			uk := key{org: k.org, id: rid}
+			u := r.s.users[uk]
+			prefs, ok := r.s.emailPrefs[uk]
+			if !ok {
+				prefs = domain.DefaultEmailPrefs(rid)
+			}
+			if u.Email == "" || !u.IsActive || !prefs.SLABreach || r.s.slaQueued(k.org, rid, k.id) {
+				continue
+			}
+			created := *p.pr.CreatedAt
+			out = append(out, domain.EmailNotification{
+				OrgID:  k.org,
+				UserID: rid,
+				Kind:   domain.EmailSLABreach,
+				PR: domain.EventPR{
+					ID: k.id, Name: p.pr.Name, AuthorID: p.pr.AuthorID,
+					Status: domain.StatusOpen, CreatedAt: &created,
+				},
+				Status: domain.EmailPending,
+			})
+		}
+	}
+	slices.SortFunc(out, func(a, b domain.EmailNotification) int {
+		return cmp.Or(a.PR.CreatedAt.Compare(*b.PR.CreatedAt), cmp.Compare(a.OrgID, b.OrgID),
+			cmp.Compare(a.PR.ID, b.PR.ID), cmp.Compare(a.UserID, b.UserID))
+	})
+	return out[:min(limit, len(out))], nil
+}
+
+func cloneEmail(e domain.EmailNotification) domain.EmailNotification {
+	e.PR = clonePR(e.PR)
+	return e
+}
+
+func clonePR(pr domain.EventPR) domain.EventPR {
+	pr.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+	return pr
+}
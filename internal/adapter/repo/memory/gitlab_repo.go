@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type GitLabProjectRepo struct{ s *Store }
+
+func NewGitLabProjectRepo(s *Store) *GitLabProjectRepo { return &GitLabProjectRepo{s: s} }
+
+func (r *GitLabProjectRepo) SetGitLabProject(_ context.Context, p domain.GitLabProject) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.teams[key{org: p.OrgID, id: p.TeamName}]; !ok {
+		return domain.ErrNotFound
+	}
+	r.s.gitlabProjects[p.ProjectID] = p
+	return nil
+}
+
+func (r *GitLabProjectRepo) GetGitLabProject(_ context.Context, projectID int64) (domain.GitLabProject, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p, ok := r.s.gitlabProjects[projectID]
+	if !ok {
+		return domain.GitLabProject{}, domain.ErrNotFound
+	}
+	return p, nil
+}
+
+func (r *GitLabProjectRepo) DeleteGitLabProject(_ context.Context, projectID int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.gitlabProjects[projectID]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.s.gitlabProjects, projectID)
+	return nil
+}
+
+func (r *GitLabProjectRepo) ListGitLabProjects(_ context.Context) ([]domain.GitLabProject, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	out := make([]domain.GitLabProject, 0, len(r.s.gitlabProjects))
+	for _, p := range r.s.gitlabProjects {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ProjectID < out[j].ProjectID })
+	return out, nil
+}
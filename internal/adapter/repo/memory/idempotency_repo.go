@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type IdempotencyRepo struct{ s *Store }
+
+func NewIdempotencyRepo(s *Store) *IdempotencyRepo { return &IdempotencyRepo{s: s} }
+
+type idempotencyKey struct {
+	key
+	owner string
+}
+
+func idempotencyKeyOf(ctx context.Context, owner, k string) idempotencyKey {
+	return idempotencyKey{key: keyOf(ctx, k), owner: owner}
+}
+
+func (r *IdempotencyRepo) ReserveIdempotencyKey(ctx context.Context, rec domain.IdempotencyRecord, expiredBefore, staleBefore time.Time) (domain.IdempotencyRecord, bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for k, v := range r.s.idempotency {
+		if v.CreatedAt.Before(expiredBefore) {
+			delete(r.s.idempotency, k)
+		}
+	}
+	k := idempotencyKeyOf(ctx, rec.Owner, rec.Key)
+	got, ok := r.s.idempotency[k]
+	abandoned := ok && !got.Completed() && got.RequestHash == rec.RequestHash && got.CreatedAt.Before(staleBefore)
+	if ok && !abandoned {
+		got.Header, got.Body = maps.Clone(got.Header), slices.Clone(got.Body)
+		return got, false, nil
+	}
+	rec.Status, rec.ContentType, rec.Header, rec.Body = 0, "", nil, nil
+	rec.CreatedAt = r.s.now()
+	r.s.idempotency[k] = rec
+	return rec, true, nil
+}
+
+func (r *IdempotencyRepo) CompleteIdempotencyKey(ctx context.Context, rec domain.IdempotencyRecord) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := idempotencyKeyOf(ctx, rec.Owner, rec.Key)
+	stored, ok := r.s.idempotency[k]
+	if !ok || stored.Lease != rec.Lease {
+		return nil
+	}
+	stored.Status, stored.ContentType = rec.Status, rec.ContentType
+	stored.Header, stored.Body = maps.Clone(rec.Header), slices.Clone(rec.Body)
+	r.s.idempotency[k] = stored
+	return nil
+}
+
+func (r *IdempotencyRepo) ReleaseIdempotencyKey(ctx context.Context, owner, key, lease string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := idempotencyKeyOf(ctx, owner, key)
+	if stored, ok := r.s.idempotency[k]; ok && stored.Lease == lease {
+		delete(r.s.idempotency, k)
+	}
+	return nil
+}
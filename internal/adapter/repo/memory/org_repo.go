@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type OrgRepo struct{ s *Store }
+
+func NewOrgRepo(s *Store) *OrgRepo { return &OrgRepo{s: s} }
+
+func (r *OrgRepo) CreateOrg(_ context.Context, org domain.Organization) (domain.Organization, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.orgs[org.OrgID]; ok {
+		return domain.Organization{}, domain.ErrOrgExists
+	}
+	org.CreatedAt = r.s.now()
+	r.s.orgs[org.OrgID] = org
+	return org, nil
+}
+
+func (r *OrgRepo) GetOrg(_ context.Context, orgID string) (domain.Organization, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	org, ok := r.s.orgs[orgID]
+	if !ok {
+		return domain.Organization{}, domain.ErrNotFound
+	}
+	return org, nil
+}
+
+func (r *OrgRepo) ListOrgs(_ context.Context) ([]domain.Organization, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	out := make([]domain.Organization, 0, len(r.s.orgs))
+	for _, org := range r.s.orgs {
+		out = append(out, org)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].OrgID < out[j].OrgID })
+	return out, nil
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type OutboxRepo struct{ s *Store }
+
+func NewOutboxRepo(s *Store) *OutboxRepo { return &OutboxRepo{s: s} }
+
+// appendOutbox must be called with the store lock held, by the write that
+// the event describes.
+func (s *Store) appendOutbox(e domain.Event) {
+	ts := s.now()
+	s.outbox = append(s.outbox, domain.OutboxMessage{
+		ID:            int64(len(s.outbox) + 1),
+		Key:           e.Key(),
+		Event:         cloneEvent(e),
+		Status:        domain.OutboxPending,
+		NextAttemptAt: ts,
+		CreatedAt:     ts,
+		UpdatedAt:     ts,
+	})
+}
+
+func (r *OutboxRepo) ClaimOutbox(_ context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxMessage, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var out []domain.OutboxMessage
+	blocked := make(map[key]bool)
+	for i := range r.s.outbox {
+		m := &r.s.outbox[i]
+		if m.Status != domain.OutboxPending {
+			continue
+		}
+		k := key{org: m.Event.OrgID, id: m.Key}
+		if blocked[k] {
+			continue
+		}
+		blocked[k] = true
+		if m.NextAttemptAt.After(now) || len(out) == limit {
+			continue
+		}
+		m.NextAttemptAt = leaseUntil
+		out = append(out, cloneMessage(*m))
+	}
+	return out, nil
+}
+
+func (r *OutboxRepo) GetOutbox(_ context.Context, id int64) (domain.OutboxMessage, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if id < 1 || id > int64(len(r.s.outbox)) {
+		return domain.OutboxMessage{}, domain.ErrNotFound
+	}
+	return cloneMessage(r.s.outbox[id-1]), nil
+}
+
+func (r *OutboxRepo) SaveOutbox(_ context.Context, m domain.OutboxMessage) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if m.ID < 1 || m.ID > int64(len(r.s.outbox)) {
+		return domain.ErrNotFound
+	}
+	stored := &r.s.outbox[m.ID-1]
+	stored.Status = m.Status
+	stored.Attempts = m.Attempts
+	stored.LastError = m.LastError
+	stored.PublishedTo = slices.Clone(m.PublishedTo)
+	stored.NextAttemptAt = m.NextAttemptAt
+	stored.UpdatedAt = r.s.now()
+	return nil
+}
+
+func (r *OutboxRepo) ListOutbox(_ context.Context, status domain.OutboxStatus) ([]domain.OutboxMessage, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var out []domain.OutboxMessage
+	for _, m := range r.s.outbox {
+		if status == "" || m.Status == status {
+			out = append(out, cloneMessage(m))
+		}
+	}
+	return out, nil
+}
+
+func (r *OutboxRepo) ListOutboxAfter(ctx context.Context, afterID int64, limit int) ([]domain.OutboxMessage, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	org := domain.OrgFromContext(ctx)
+	var out []domain.OutboxMessage
+	for _, m := range r.s.outbox[min(max(afterID, 0), int64(len(r.s.outbox))):] {
+		if len(out) == limit {
+			break
+		}
+		if m.Event.OrgID == org {
+			out = append(out, cloneMessage(m))
+		}
+	}
+	return out, nil
+}
+
+func (r *OutboxRepo) LastOutboxID(ctx context.Context) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	org := domain.OrgFromContext(ctx)
+	for i := len(r.s.outbox) - 1; i >= 0; i-- {
+		if r.s.outbox[i].Event.OrgID == org {
+			return r.s.outbox[i].ID, nil
+		}
+	}
+	return 0, nil
+}
+
+func cloneMessage(m domain.OutboxMessage) domain.OutboxMessage {
+	m.Event = cloneEvent(m.Event)
+	m.PublishedTo = slices.Clone(m.PublishedTo)
+	return m
+}
+
+func cloneEvent(e domain.Event) domain.Event {
+	if pr := e.Data.PullRequest; pr != nil {
+		c := *pr
+		c.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+		e.Data.PullRequest = &c
+	}
+	if r := e.Data.Review; r != nil {
+		c := *r
+		e.Data.Review = &c
+	}
+	if u := e.Data.User; u != nil {
+		c := *u
+		e.Data.User = &c
+	}
+	return e
+}
@@ -0,0 +1,274 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type PRRepo struct{ s *Store }
+
+func NewPRRepo(s *Store) *PRRepo { return &PRRepo{s: s} }
+
+func (r *PRRepo) CreatePRWithReviewers(ctx context.Context, pr domain.PullRequest, reviewers []string, e *domain.Event) (domain.PullRequest, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, pr.ID)
+	if _, ok := r.s.prs[k]; ok {
+		return domain.PullRequest{}, domain.ErrPRExists
+	}
+
+	created := r.s.now()
+	r.s.prs[k] = &pullRequest{
+		pr: domain.PullRequest{
+			ID:        pr.ID,
+			Name:      pr.Name,
+			AuthorID:  pr.AuthorID,
+			Status:    domain.StatusOpen,
+			CreatedAt: &created,
+			Version:   1,
+		},
+		reviewers: slices.Clone(reviewers),
+	}
+	return r.commit(ctx, k, domain.AuditPRCreated, nil, e)
+}
+
+func (r *PRRepo) GetByIDForUpdate(ctx context.Context, id string) (domain.PullRequest, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.snapshot(keyOf(ctx, id))
+}
+
+func (r *PRRepo) GetAssignedReviewers(ctx context.Context, prID string) ([]string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p, ok := r.s.prs[keyOf(ctx, prID)]
+	if !ok {
+		return nil, nil
+	}
+	return slices.Clone(p.reviewers), nil
+}
+
+func (r *PRRepo) ReplaceReviewer(ctx context.Context, prID, oldID, newID string, version int64, e *domain.Event) (domain.PullRequest, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, prID)
+	p, ok := r.s.prs[k]
+	if !ok {
+		return domain.PullRequest{}, domain.ErrNotFound
+	}
+	if !p.pr.MatchesVersion(version) {
+		return domain.PullRequest{}, domain.ErrVersionMismatch
+	}
+	before, _ := r.snapshot(k)
+	if err := before.CanReplaceReviewer(oldID, newID); err != nil {
+		return domain.PullRequest{}, err
+	}
+	p.reviewers = slices.DeleteFunc(p.reviewers, func(id string) bool { return id == oldID })
+	p.reviewers = append(p.reviewers, newID)
+	p.pr.Version++
+	return r.commit(ctx, k, domain.AuditPRReassigned, &before, e)
+}
+
+func (r *PRRepo) SetMerged(ctx context.Context, prID string, version int64, e *domain.Event) (domain.PullRequest, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, prID)
+	p, ok := r.s.prs[k]
+	if !ok {
+		return domain.PullRequest{}, domain.ErrNotFound
+	}
+	if !p.pr.MatchesVersion(version) {
+		return domain.PullRequest{}, domain.ErrVersionMismatch
+	}
+	switch p.pr.Status {
+	case domain.StatusMerged:
+		return r.snapshot(k)
+	case domain.StatusClosed:
+		return domain.PullRequest{}, domain.ErrPRClosed
+	}
+	before, _ := r.snapshot(k)
+	p.pr.Status = domain.StatusMerged
+	p.pr.Version++
+	if p.pr.MergedAt == nil {
+		merged := r.s.now()
+		p.pr.MergedAt = &merged
+	}
+	return r.commit(ctx, k, domain.AuditPRMerged, &before, e)
+}
+
+func (r *PRRepo) SetClosed(ctx context.Context, prID string, closed bool, e *domain.Event) (domain.PullRequest, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, prID)
+	p, ok := r.s.prs[k]
+	if !ok {
+		return domain.PullRequest{}, domain.ErrNotFound
+	}
+	from, to, action := domain.StatusOpen, domain.StatusClosed, domain.AuditPRClosed
+	if !closed {
+		from, to, action = to, from, domain.AuditPRReopened
+	}
+	if p.pr.Status != from {
+		return r.snapshot(k)
+	}
+	before, _ := r.snapshot(k)
+	p.pr.Status = to
+	p.pr.Version++
+	return r.commit(ctx, k, action, &before, e)
+}
+
+// SetReview writes e only when the verdict changes.
+func (r *PRRepo) SetReview(ctx context.Context, prID string, rv domain.Review, version int64, e *domain.Event) (domain.PullRequest, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, prID)
+	p, ok := r.s.prs[k]
+	if !ok {
+		return domain.PullRequest{}, domain.ErrNotFound
+	}
+	if !p.pr.MatchesVersion(version) {
+		return domain.PullRequest{}, domain.ErrVersionMismatch
+	}
+	if err := p.pr.CheckOpen(); err != nil {
+		return domain.PullRequest{}, err
+	}
+	if _, ok := r.s.users[keyOf(ctx, rv.ReviewerID)]; !ok {
+		return domain.PullRequest{}, domain.ErrNotFound
+	}
+	var before *domain.Review
+	if prev, ok := p.reviews[rv.ReviewerID]; ok {
+		if prev.Verdict == rv.Verdict {
+			return r.snapshot(k)
+		}
+		before = &prev
+	}
+	if p.reviews == nil {
+		p.reviews = make(map[string]domain.Review)
+	}
+	rv.CreatedAt = r.s.now()
+	p.reviews[rv.ReviewerID] = rv
+	p.pr.Version++
+	r.s.appendAudit(domain.NewAuditEntry(ctx, domain.AuditPRReviewed, prID,
+		domain.AuditReview(prID, before), domain.AuditReview(prID, &rv)))
+	return r.commit(ctx, k, "", nil, e)
+}
+
+func (r *PRRepo) ListReviews(ctx context.Context, prID string) ([]domain.Review, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p, ok := r.s.prs[keyOf(ctx, prID)]
+	if !ok {
+		return nil, nil
+	}
+	out := slices.Collect(maps.Values(p.reviews))
+	slices.SortFunc(out, func(a, b domain.Review) int { return strings.Compare(a.ReviewerID, b.ReviewerID) })
+	return out, nil
+}
+
+func (r *PRRepo) ListByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequestShort, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	org := domain.OrgFromContext(ctx)
+	var matched []domain.PullRequest
+	for k, p := range r.s.prs {
+		if k.org == org && slices.Contains(p.reviewers, reviewerID) {
+			matched = append(matched, p.pr)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].CreatedAt.After(*matched[j].CreatedAt) })
+
+	var out []domain.PullRequestShort
+	for _, p := range matched {
+		out = append(out, domain.PullRequestShort{
+			ID:        p.ID,
+			Name:      p.Name,
+			AuthorID:  p.AuthorID,
+			Status:    p.Status,
+			CreatedAt: p.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+func (r *PRRepo) ListEvents(ctx context.Context, prID string) ([]domain.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	org, msgKey := domain.OrgFromContext(ctx), domain.PRKey(prID)
+	var out []domain.Event
+	for _, m := range r.s.outbox {
+		if m.Event.OrgID == org && m.Key == msgKey {
+			out = append(out, cloneEvent(m.Event))
+		}
+	}
+	return out, nil
+}
+
+func (r *PRRepo) StatsByStatus(ctx context.Context) (map[domain.PRStatus]int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	org := domain.OrgFromContext(ctx)
+	res := map[domain.PRStatus]int{
+		domain.StatusOpen:   0,
+		domain.StatusMerged: 0,
+		domain.StatusClosed: 0,
+	}
+	for k, p := range r.s.prs {
+		if k.org == org {
+			res[p.pr.Status]++
+		}
+	}
+	return res, nil
+}
+
+// commit is snapshot that also records the change from before in the audit
+// log if action is set and writes e, if set, to the outbox.
+func (r *PRRepo) commit(ctx context.Context, k key, action domain.AuditAction, before *domain.PullRequest, e *domain.Event) (domain.PullRequest, error) {
+	out, err := r.snapshot(k)
+	if err != nil {
+		return out, err
+	}
+	if action != "" {
+		r.s.appendAudit(domain.NewAuditEntry(ctx, action, k.id, domain.AuditPR(before), domain.AuditPR(&out)))
+	}
+	if e == nil {
+		return out, nil
+	}
+	e.Data.PullRequest = domain.NewEventPR(out)
+	r.s.appendOutbox(*e)
+	return out, nil
+}
+
+// snapshot must be called with the store lock held.
+func (r *PRRepo) snapshot(k key) (domain.PullRequest, error) {
+	p, ok := r.s.prs[k]
+	if !ok {
+		return domain.PullRequest{}, domain.ErrNotFound
+	}
+	out := p.pr
+	out.AssignedReviewers = slices.Clone(p.reviewers)
+	if p.pr.CreatedAt != nil {
+		c := *p.pr.CreatedAt
+		out.CreatedAt = &c
+	}
+	if p.pr.MergedAt != nil {
+		m := *p.pr.MergedAt
+		out.MergedAt = &m
+	}
+	return out, nil
+}
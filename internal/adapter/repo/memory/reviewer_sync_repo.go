@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type ReviewerSyncRepo struct{ s *Store }
+
+func NewReviewerSyncRepo(s *Store) *ReviewerSyncRepo { return &ReviewerSyncRepo{s: s} }
+
+func (r *ReviewerSyncRepo) EnqueueReviewerSync(ctx context.Context, s domain.ReviewerSync) (domain.ReviewerSync, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ts := r.s.now()
+	s.ID = int64(len(r.s.syncs) + 1)
+	s.OrgID = domain.OrgFromContext(ctx)
+	s.Add, s.Remove = slices.Clone(s.Add), slices.Clone(s.Remove)
+	s.CreatedAt, s.UpdatedAt = ts, ts
+	r.s.syncs = append(r.s.syncs, s)
+	return cloneSync(s), nil
+}
+
+func (r *ReviewerSyncRepo) ClaimReviewerSyncs(_ context.Context, now, leaseUntil time.Time, limit int) ([]domain.ReviewerSync, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var out []domain.ReviewerSync
+	blocked := make(map[key]bool)
+	for i := range r.s.syncs {
+		s := &r.s.syncs[i]
+		if s.Status != domain.SyncPending {
+			continue
+		}
+		pr := key{org: s.OrgID, id: s.PRID}
+		if blocked[pr] {
+			continue
+		}
+		blocked[pr] = true
+		if s.NextAttemptAt.After(now) || len(out) == limit {
+			continue
+		}
+		s.NextAttemptAt = leaseUntil
+		out = append(out, cloneSync(*s))
+	}
+	return out, nil
+}
+
+func (r *ReviewerSyncRepo) GetReviewerSync(_ context.Context, id int64) (domain.ReviewerSync, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if id < 1 || id > int64(len(r.s.syncs)) {
+		return domain.ReviewerSync{}, domain.ErrNotFound
+	}
+	return cloneSync(r.s.syncs[id-1]), nil
+}
+
+func (r *ReviewerSyncRepo) SaveReviewerSync(_ context.Context, s domain.ReviewerSync) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if s.ID < 1 || s.ID > int64(len(r.s.syncs)) {
+		return domain.ErrNotFound
+	}
+	stored := &r.s.syncs[s.ID-1]
+	stored.Status = s.Status
+	stored.Attempts = s.Attempts
+	stored.LastError = s.LastError
+	stored.NextAttemptAt = s.NextAttemptAt
+	stored.UpdatedAt = r.s.now()
+	return nil
+}
+
+func (r *ReviewerSyncRepo) ListReviewerSyncs(_ context.Context, status domain.SyncStatus) ([]domain.ReviewerSync, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var out []domain.ReviewerSync
+	for _, s := range r.s.syncs {
+		if status == "" || s.Status == status {
+			out = append(out, cloneSync(s))
+		}
+	}
+	return out, nil
+}
+
+func cloneSync(s domain.ReviewerSync) domain.ReviewerSync {
+	s.Add, s.Remove = slices.Clone(s.Add), slices.Clone(s.Remove)
+	return s
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type SlackRepo struct{ s *Store }
+
+func NewSlackRepo(s *Store) *SlackRepo { return &SlackRepo{s: s} }
+
+func (r *SlackRepo) SetSlackChannel(ctx context.Context, c domain.SlackChannel) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, c.TeamName)
+	if _, ok := r.s.teams[k]; !ok {
+		return domain.ErrNotFound
+	}
+	r.s.slackChannels[k] = c.WebhookURL
+	return nil
+}
+
+func (r *SlackRepo) GetSlackChannel(ctx context.Context, teamName string) (domain.SlackChannel, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.slackChannels[keyOf(ctx, teamName)]
+	if !ok {
+		return domain.SlackChannel{}, domain.ErrNotFound
+	}
+	return domain.SlackChannel{TeamName: teamName, WebhookURL: u}, nil
+}
+
+func (r *SlackRepo) DeleteSlackChannel(ctx context.Context, teamName string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, teamName)
+	if _, ok := r.s.slackChannels[k]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.s.slackChannels, k)
+	return nil
+}
+
+func (r *SlackRepo) ListSlackChannels(ctx context.Context) ([]domain.SlackChannel, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	org := domain.OrgFromContext(ctx)
+	var out []domain.SlackChannel
+	for k, u := range r.s.slackChannels {
+		if k.org == org {
+			out = append(out, domain.SlackChannel{TeamName: k.id, WebhookURL: u})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].TeamName < out[j].TeamName })
+	return out, nil
+}
+
+func (r *SlackRepo) SetSlackUser(ctx context.Context, u domain.SlackUser) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, u.UserID)
+	if _, ok := r.s.users[k]; !ok {
+		return domain.ErrNotFound
+	}
+	r.s.slackUsers[k] = u.SlackID
+	return nil
+}
+
+func (r *SlackRepo) DeleteSlackUser(ctx context.Context, userID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, userID)
+	if _, ok := r.s.slackUsers[k]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.s.slackUsers, k)
+	return nil
+}
+
+func (r *SlackRepo) ListSlackUsers(ctx context.Context) ([]domain.SlackUser, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	org := domain.OrgFromContext(ctx)
+	var out []domain.SlackUser
+	for k, id := range r.s.slackUsers {
+		if k.org == org {
+			out = append(out, domain.SlackUser{UserID: k.id, SlackID: id})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UserID < out[j].UserID })
+	return out, nil
+}
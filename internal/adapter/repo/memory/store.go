@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// Store is the shared in-process state behind the memory repositories.
+// It plays the role the connection pool plays for the postgres adapter.
+type Store struct {
+	mu sync.Mutex
+
+	orgs  map[string]domain.Organization
+	teams map[key]domain.Team
+	users map[key]domain.User
+	prs   map[key]*pullRequest
+
+	// deliveries is keyed by webhook source rather than organization.
+	deliveries     map[key]struct{}
+	gitlabProjects map[int64]domain.GitLabProject
+	// syncs is indexed by ReviewerSync.ID - 1.
+	syncs []domain.ReviewerSync
+
+	subscriptions   map[key]domain.Subscription
+	eventDeliveries map[int64]domain.EventDelivery
+	lastDeliveryID  int64
+
+	// outbox is indexed by OutboxMessage.ID - 1.
+	outbox []domain.OutboxMessage
+
+	// slackChannels and slackUsers hold webhook URLs and Slack member ids.
+	slackChannels map[key]string
+	slackUsers    map[key]string
+
+	emailPrefs map[key]domain.EmailPrefs
+	// emails is indexed by EmailNotification.ID - 1.
+	emails []domain.EmailNotification
+
+	// audit is indexed by AuditEntry.ID - 1.
+	audit []domain.AuditEntry
+
+	// digests holds the local days each user was sent a review digest.
+	digests map[digestKey]struct{}
+
+	idempotency map[idempotencyKey]domain.IdempotencyRecord
+
+	lastStamp time.Time
+}
+
+// key scopes an identifier to its organization, mirroring the composite
+// primary keys of the SQL backends.
+type key struct {
+	org string
+	id  string
+}
+
+func keyOf(ctx context.Context, id string) key {
+	return key{org: domain.OrgFromContext(ctx), id: id}
+}
+
+type pullRequest struct {
+	pr        domain.PullRequest
+	reviewers []string
+	// reviews holds the latest verdict by reviewer id.
+	reviews map[string]domain.Review
+}
+
+func NewStore() *Store {
+	s := &Store{
+		orgs:           make(map[string]domain.Organization),
+		teams:          make(map[key]domain.Team),
+		users:          make(map[key]domain.User),
+		prs:            make(map[key]*pullRequest),
+		deliveries:     make(map[key]struct{}),
+		gitlabProjects: make(map[int64]domain.GitLabProject),
+
+		subscriptions:   make(map[key]domain.Subscription),
+		eventDeliveries: make(map[int64]domain.EventDelivery),
+
+		slackChannels: make(map[key]string),
+		slackUsers:    make(map[key]string),
+
+		emailPrefs: make(map[key]domain.EmailPrefs),
+		digests:    make(map[digestKey]struct{}),
+
+		idempotency: make(map[idempotencyKey]domain.IdempotencyRecord),
+	}
+	s.orgs[domain.DefaultOrg] = domain.Organization{OrgID: domain.DefaultOrg, Name: "Default", CreatedAt: s.now()}
+	return s
+}
+
+// now returns a strictly increasing timestamp so that ordering by creation
+// time is stable even when calls land within the same clock tick.
+func (s *Store) now() time.Time {
+	t := time.Now().UTC()
+	if !t.After(s.lastStamp) {
+		t = s.lastStamp.Add(time.Microsecond)
+	}
+	s.lastStamp = t
+	return t
+}
@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type SubscriptionRepo struct{ s *Store }
+
+func NewSubscriptionRepo(s *Store) *SubscriptionRepo { return &SubscriptionRepo{s: s} }
+
+func (r *SubscriptionRepo) CreateSubscription(ctx context.Context, sub domain.Subscription) (domain.Subscription, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	sub.OrgID = domain.OrgFromContext(ctx)
+	sub.EventTypes = slices.Clone(sub.EventTypes)
+	sub.CreatedAt = r.s.now()
+	r.s.subscriptions[key{org: sub.OrgID, id: sub.ID}] = sub
+	return cloneSubscription(sub), nil
+}
+
+func (r *SubscriptionRepo) GetSubscription(ctx context.Context, id string) (domain.Subscription, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	sub, ok := r.s.subscriptions[keyOf(ctx, id)]
+	if !ok {
+		return domain.Subscription{}, domain.ErrNotFound
+	}
+	return cloneSubscription(sub), nil
+}
+
+func (r *SubscriptionRepo) ListSubscriptions(ctx context.Context) ([]domain.Subscription, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	org := domain.OrgFromContext(ctx)
+	var out []domain.Subscription
+	for k, sub := range r.s.subscriptions {
+		if k.org == org {
+			out = append(out, cloneSubscription(sub))
+		}
+	}
+	slices.SortFunc(out, func(a, b domain.Subscription) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return out, nil
+}
+
+func (r *SubscriptionRepo) DeleteSubscription(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, id)
+	if _, ok := r.s.subscriptions[k]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.s.subscriptions, k)
+	maps.DeleteFunc(r.s.eventDeliveries, func(_ int64, d domain.EventDelivery) bool {
+		return d.OrgID == k.org && d.SubscriptionID == k.id
+	})
+	return nil
+}
+
+func cloneSubscription(s domain.Subscription) domain.Subscription {
+	s.EventTypes = slices.Clone(s.EventTypes)
+	return s
+}
+
+type EventDeliveryRepo struct{ s *Store }
+
+func NewEventDeliveryRepo(s *Store) *EventDeliveryRepo { return &EventDeliveryRepo{s: s} }
+
+func (r *EventDeliveryRepo) EnqueueEventDelivery(ctx context.Context, d domain.EventDelivery) (domain.EventDelivery, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	d.OrgID = domain.OrgFromContext(ctx)
+	if _, ok := r.s.subscriptions[key{org: d.OrgID, id: d.SubscriptionID}]; !ok {
+		return domain.EventDelivery{}, domain.ErrNotFound
+	}
+	r.s.lastDeliveryID++
+	ts := r.s.now()
+	d.ID = r.s.lastDeliveryID
+	d.Payload = slices.Clone(d.Payload)
+	d.CreatedAt, d.UpdatedAt = ts, ts
+	r.s.eventDeliveries[d.ID] = d
+	return cloneDelivery(d), nil
+}
+
+func (r *EventDeliveryRepo) ClaimEventDeliveries(_ context.Context, now, leaseUntil time.Time, limit int) ([]domain.EventDelivery, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var out []domain.EventDelivery
+	for _, id := range slices.Sorted(maps.Keys(r.s.eventDeliveries)) {
+		d := r.s.eventDeliveries[id]
+		if d.Status != domain.DeliveryPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+		if len(out) == limit {
+			break
+		}
+		d.NextAttemptAt = leaseUntil
+		r.s.eventDeliveries[id] = d
+		out = append(out, cloneDelivery(d))
+	}
+	return out, nil
+}
+
+func (r *EventDeliveryRepo) GetEventDelivery(ctx context.Context, id int64) (domain.EventDelivery, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	d, ok := r.s.eventDeliveries[id]
+	if !ok || d.OrgID != domain.OrgFromContext(ctx) {
+		return domain.EventDelivery{}, domain.ErrNotFound
+	}
+	return cloneDelivery(d), nil
+}
+
+func (r *EventDeliveryRepo) SaveEventDelivery(_ context.Context, d domain.EventDelivery) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.eventDeliveries[d.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	stored.Status = d.Status
+	stored.Attempts = d.Attempts
+	stored.ResponseCode = d.ResponseCode
+	stored.LastError = d.LastError
+	stored.NextAttemptAt = d.NextAttemptAt
+	stored.UpdatedAt = r.s.now()
+	r.s.eventDeliveries[d.ID] = stored
+	return nil
+}
+
+func (r *EventDeliveryRepo) ListEventDeliveries(ctx context.Context, subscriptionID string, limit int) ([]domain.EventDelivery, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	org := domain.OrgFromContext(ctx)
+	var out []domain.EventDelivery
+	for _, d := range r.s.eventDeliveries {
+		if d.OrgID == org && d.SubscriptionID == subscriptionID {
+			out = append(out, cloneDelivery(d))
+		}
+	}
+	slices.SortFunc(out, func(a, b domain.EventDelivery) int { return cmp.Compare(b.ID, a.ID) })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func cloneDelivery(d domain.EventDelivery) domain.EventDelivery {
+	d.Payload = slices.Clone(d.Payload)
+	return d
+}
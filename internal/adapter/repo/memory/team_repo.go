@@ -0,0 +1,216 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type TeamRepo struct{ s *Store }
+
+func NewTeamRepo(s *Store) *TeamRepo { return &TeamRepo{s: s} }
+
+func (r *TeamRepo) CreateTeam(ctx context.Context, teamName string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, teamName)
+	if _, ok := r.s.teams[k]; ok {
+		return domain.ErrTeamExists
+	}
+	t := domain.Team{TeamName: teamName}
+	r.s.teams[k] = t
+	r.s.appendAudit(domain.NewAuditEntry(ctx, domain.AuditTeamCreated, teamName, nil, domain.AuditTeam(t)))
+	return nil
+}
+
+func (r *TeamRepo) GetTeamWithMembers(ctx context.Context, teamName string) (domain.Team, []domain.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, teamName)
+	t, ok := r.s.teams[k]
+	if !ok {
+		return domain.Team{}, nil, domain.ErrNotFound
+	}
+
+	var members []domain.User
+	for uk, u := range r.s.users {
+		if uk.org == k.org && u.TeamName == teamName {
+			members = append(members, u)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].UserID < members[j].UserID })
+
+	return t, members, nil
+}
+
+func (r *TeamRepo) SetFourEyes(ctx context.Context, teamName string, on bool) (domain.Team, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, teamName)
+	before, ok := r.s.teams[k]
+	if !ok {
+		return domain.Team{}, domain.ErrNotFound
+	}
+	if before.FourEyes == on {
+		return before, nil
+	}
+	after := before
+	after.FourEyes = on
+	r.s.teams[k] = after
+	r.s.appendAudit(domain.NewAuditEntry(ctx, domain.AuditTeamPolicyChanged, teamName,
+		domain.AuditTeam(before), domain.AuditTeam(after)))
+	return after, nil
+}
+
+func (r *TeamRepo) UpsertUsersToTeam(ctx context.Context, teamName string, users []domain.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if len(users) == 0 {
+		return nil
+	}
+	if _, ok := r.s.teams[keyOf(ctx, teamName)]; !ok {
+		return domain.ErrNotFound
+	}
+
+	for _, u := range users {
+		r.upsertUser(ctx, teamName, u)
+	}
+	return nil
+}
+
+// upsertUser must be called with the store locked.
+func (r *TeamRepo) upsertUser(ctx context.Context, teamName string, u domain.User) {
+	k := keyOf(ctx, u.UserID)
+	u.TeamName = teamName
+	if u.Role == "" {
+		u.Role = domain.RoleMember
+		if prev, ok := r.s.users[k]; ok {
+			u.Role = prev.Role
+		}
+	}
+	u.AwayUntil = nil
+	var before *domain.User
+	if prev, ok := r.s.users[k]; ok {
+		if u.Email == "" {
+			u.Email = prev.Email
+		}
+		if u.Timezone == "" {
+			u.Timezone = prev.Timezone
+		}
+		if u.ManagerID == "" {
+			u.ManagerID = prev.ManagerID
+		}
+		u.AwayUntil = prev.AwayUntil
+		before = &prev
+	}
+	r.s.users[k] = u
+	r.s.appendAudit(domain.NewAuditEntry(ctx, domain.AuditUserUpserted, u.UserID, domain.AuditUser(before), domain.AuditUser(&u)))
+}
+
+func (r *TeamRepo) ListTeams(ctx context.Context) ([]domain.Team, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	org := domain.OrgFromContext(ctx)
+	var out []domain.Team
+	for k, t := range r.s.teams {
+		if k.org == org {
+			out = append(out, t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].TeamName < out[j].TeamName })
+	return out, nil
+}
+
+// ImportTeams checks every change before making any, so a dry run leaves
+// the store as it was.
+func (r *TeamRepo) ImportTeams(ctx context.Context, teams []domain.Team, dryRun bool) (domain.ImportReport, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	report := domain.ImportReport{DryRun: dryRun}
+	for _, t := range teams {
+		action := domain.ImportUnchanged
+		if _, ok := r.s.teams[keyOf(ctx, t.TeamName)]; !ok {
+			action = domain.ImportCreate
+		}
+		report.Changes = append(report.Changes, domain.ImportChange{
+			Kind: domain.ImportKindTeam, ID: t.TeamName, Action: action, TeamName: t.TeamName,
+		})
+		for _, u := range t.Members {
+			var before *domain.User
+			if prev, ok := r.s.users[keyOf(ctx, u.UserID)]; ok {
+				before = &prev
+			}
+			c := domain.ImportChange{
+				Kind: domain.ImportKindUser, ID: u.UserID, TeamName: t.TeamName,
+				Action: domain.PlanUserImport(before, u, t.TeamName),
+			}
+			if c.Action == domain.ImportMove {
+				c.FromTeam = before.TeamName
+			}
+			report.Changes = append(report.Changes, c)
+		}
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	members := make(map[string]domain.User)
+	for _, t := range teams {
+		for _, u := range t.Members {
+			members[u.UserID] = u
+		}
+	}
+	for _, c := range report.Changes {
+		switch {
+		case c.Kind == domain.ImportKindTeam && c.Action == domain.ImportCreate:
+			t := domain.Team{TeamName: c.ID}
+			r.s.teams[keyOf(ctx, c.ID)] = t
+			r.s.appendAudit(domain.NewAuditEntry(ctx, domain.AuditTeamCreated, c.ID, nil, domain.AuditTeam(t)))
+		case c.Kind == domain.ImportKindUser && c.Action != domain.ImportUnchanged:
+			r.upsertUser(ctx, c.TeamName, members[c.ID])
+		}
+	}
+	return report, nil
+}
+
+func (r *TeamRepo) DeleteTeam(ctx context.Context, teamName, moveTo string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, teamName)
+	t, ok := r.s.teams[k]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if _, ok := r.s.teams[keyOf(ctx, moveTo)]; !ok {
+		return domain.ErrNotFound
+	}
+
+	var members []domain.User
+	for uk, u := range r.s.users {
+		if uk.org == k.org && u.TeamName == teamName {
+			members = append(members, u)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].UserID < members[j].UserID })
+	for _, u := range members {
+		r.upsertUser(ctx, moveTo, u)
+	}
+
+	delete(r.s.teams, k)
+	delete(r.s.slackChannels, k)
+	for id, p := range r.s.gitlabProjects {
+		if p.OrgID == k.org && p.TeamName == teamName {
+			delete(r.s.gitlabProjects, id)
+		}
+	}
+	r.s.appendAudit(domain.NewAuditEntry(ctx, domain.AuditTeamDeleted, teamName, domain.AuditTeam(t), nil))
+	return nil
+}
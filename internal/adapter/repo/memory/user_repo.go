@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type UserRepo struct{ s *Store }
+
+func NewUserRepo(s *Store) *UserRepo { return &UserRepo{s: s} }
+
+func (r *UserRepo) GetByID(ctx context.Context, id string) (domain.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[keyOf(ctx, id)]
+	if !ok {
+		return domain.User{}, domain.ErrNotFound
+	}
+	return u, nil
+}
+
+func (r *UserRepo) SetActive(ctx context.Context, id string, active bool, e *domain.Event) (domain.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, id)
+	u, ok := r.s.users[k]
+	if !ok {
+		return domain.User{}, domain.ErrNotFound
+	}
+	if u.IsActive == active {
+		return u, nil
+	}
+	before := u
+	u.IsActive = active
+	r.s.users[k] = u
+	r.s.appendAudit(domain.NewAuditEntry(ctx, domain.AuditUserActivityChanged, id, domain.AuditUser(&before), domain.AuditUser(&u)))
+	if e != nil {
+		e.Data.User = domain.NewEventUser(u)
+		r.s.appendOutbox(*e)
+	}
+	return u, nil
+}
+
+func (r *UserRepo) SetAway(ctx context.Context, id string, until *time.Time) (domain.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	k := keyOf(ctx, id)
+	u, ok := r.s.users[k]
+	if !ok {
+		return domain.User{}, domain.ErrNotFound
+	}
+	u.AwayUntil = nil
+	if until != nil {
+		t := until.UTC()
+		u.AwayUntil = &t
+	}
+	r.s.users[k] = u
+	return u, nil
+}
+
+func (r *UserRepo) ListActiveInTeamExcept(ctx context.Context, teamName string, excludeIDs []string, limit int) ([]domain.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	org := domain.OrgFromContext(ctx)
+	var out []domain.User
+	for k, u := range r.s.users {
+		if k.org != org || u.TeamName != teamName || !u.IsActive || slices.Contains(excludeIDs, u.UserID) {
+			continue
+		}
+		out = append(out, u)
+	}
+
+	//nolint:gosec // reviewer selection does not need a cryptographic source
+	rand.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type AuditRepo struct{ pool *pgxpool.Pool }
+
+func NewAuditRepo(pool *pgxpool.Pool) *AuditRepo { return &AuditRepo{pool: pool} }
+
+const auditColumns = `audit_id, org_id, action, entity_type, entity_id, actor, request_id, before, after, created_at,
+	prev_hash, hash`
+
+// insertAudit runs in the transaction of the change e describes and links e
+// to the last entry of its organization. The advisory lock, held until the
+// transaction ends, keeps concurrent writers of the organization from
+// linking to the same entry. Entries whose state did not change are
+// dropped.
+func insertAudit(ctx context.Context, q querier, e domain.AuditEntry) error {
+	if e.Before != nil && bytes.Equal(e.Before, e.After) {
+		return nil
+	}
+	if _, err := q.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtextextended('audit_log/' || $1, 0))`, e.OrgID); err != nil {
+		return err
+	}
+	err := q.QueryRow(ctx,
+		`SELECT hash FROM audit_log WHERE org_id = $1 ORDER BY audit_id DESC LIMIT 1`, e.OrgID,
+	).Scan(&e.PrevHash)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	// Stored with the precision of timestamptz so the hash survives the
+	// round trip.
+	e.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	e.Hash = e.ChainHash()
+	_, err = q.Exec(ctx, `
+		INSERT INTO audit_log (org_id, action, entity_type, entity_id, actor, request_id, before, after, created_at,
+			prev_hash, hash)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`,
+		e.OrgID, e.Action, e.EntityType, e.EntityID, e.Actor, e.RequestID, nullJSON(e.Before), nullJSON(e.After),
+		e.CreatedAt, e.PrevHash, e.Hash)
+	return err
+}
+
+func nullJSON(b []byte) any {
+	if b == nil {
+		return nil
+	}
+	return string(b)
+}
+
+func (r *AuditRepo) ListAudit(ctx context.Context, f domain.AuditFilter) ([]domain.AuditEntry, error) {
+	var from, to any
+	if !f.From.IsZero() {
+		from = f.From
+	}
+	if !f.To.IsZero() {
+		to = f.To
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+auditColumns+` FROM audit_log
+		WHERE org_id = $1
+		  AND ($2 = '' OR entity_type = $2)
+		  AND ($3 = '' OR entity_id = $3)
+		  AND ($4 = '' OR actor = $4)
+		  AND ($5::timestamptz IS NULL OR created_at >= $5)
+		  AND ($6::timestamptz IS NULL OR created_at < $6)
+		  AND ($7::bigint = 0 OR audit_id < $7)
+		ORDER BY audit_id DESC
+		LIMIT $8`,
+		domain.OrgFromContext(ctx), f.EntityType, f.EntityID, f.Actor, from, to, f.BeforeID, f.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.AuditEntry
+	for rows.Next() {
+		e, err := scanAudit(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (r *AuditRepo) ListAuditChain(ctx context.Context, afterID int64, limit int) ([]domain.AuditEntry, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+auditColumns+` FROM audit_log
+		WHERE org_id = $1 AND audit_id > $2
+		ORDER BY audit_id
+		LIMIT $3`, domain.OrgFromContext(ctx), afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.AuditEntry
+	for rows.Next() {
+		e, err := scanAudit(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func scanAudit(row pgx.Row) (domain.AuditEntry, error) {
+	var e domain.AuditEntry
+	err := row.Scan(&e.ID, &e.OrgID, &e.Action, &e.EntityType, &e.EntityID, &e.Actor, &e.RequestID,
+		&e.Before, &e.After, &e.CreatedAt, &e.PrevHash, &e.Hash)
+	return e, err
+}
@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DeliveryRepo struct{ pool *pgxpool.Pool }
+
+func NewDeliveryRepo(pool *pgxpool.Pool) *DeliveryRepo { return &DeliveryRepo{pool: pool} }
+
+func (r *DeliveryRepo) ClaimDelivery(ctx context.Context, source, deliveryID string) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `
+		INSERT INTO webhook_deliveries (source, delivery_id) VALUES ($1,$2)
+		ON CONFLICT DO NOTHING`, source, deliveryID)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() == 1, nil
+}
+
+func (r *DeliveryRepo) ReleaseDelivery(ctx context.Context, source, deliveryID string) error {
+	_, err := r.pool.Exec(ctx,
+		`DELETE FROM webhook_deliveries WHERE source=$1 AND delivery_id=$2`, source, deliveryID)
+	return err
+}
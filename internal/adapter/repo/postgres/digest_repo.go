@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type DigestRepo struct{ pool *pgxpool.Pool }
+
+func NewDigestRepo(pool *pgxpool.Pool) *DigestRepo { return &DigestRepo{pool: pool} }
+
+func (r *DigestRepo) ListDigestRecipients(ctx context.Context) ([]domain.ReviewDigest, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT u.org_id, u.user_id, u.username, u.team_name, u.is_active, u.role, u.email, u.timezone, u.away_until
+		FROM users u
+		LEFT JOIN email_prefs p ON p.org_id = u.org_id AND p.user_id = u.user_id
+		WHERE u.email <> '' AND u.is_active AND COALESCE(p.daily_digest, TRUE)
+		  AND EXISTS (
+			SELECT 1 FROM pr_reviewers r
+			JOIN pull_requests pr ON pr.org_id = r.org_id AND pr.pull_request_id = r.pull_request_id
+			WHERE r.org_id = u.org_id AND r.reviewer_id = u.user_id AND pr.status = 'OPEN')
+		ORDER BY u.org_id, u.user_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.ReviewDigest
+	for rows.Next() {
+		var d domain.ReviewDigest
+		u := &d.To
+		if err := rows.Scan(&d.OrgID, &u.UserID, &u.Username, &u.TeamName, &u.IsActive, &u.Role,
+			&u.Email, &u.Timezone, &u.AwayUntil); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (r *DigestRepo) ClaimDigest(ctx context.Context, userID, day string) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `
+		INSERT INTO review_digests (org_id, user_id, day) VALUES ($1,$2,$3)
+		ON CONFLICT DO NOTHING`, domain.OrgFromContext(ctx), userID, day)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return false, domain.ErrNotFound
+		}
+		return false, err
+	}
+	return ct.RowsAffected() == 1, nil
+}
+
+func (r *DigestRepo) ReleaseDigest(ctx context.Context, userID, day string) error {
+	_, err := r.pool.Exec(ctx, `
+		DELETE FROM review_digests WHERE org_id=$1 AND user_id=$2 AND day=$3`,
+		domain.OrgFromContext(ctx), userID, day)
+	return err
+}
@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type EmailRepo struct{ pool *pgxpool.Pool }
+
+func NewEmailRepo(pool *pgxpool.Pool) *EmailRepo { return &EmailRepo{pool: pool} }
+
+const emailColumns = `notification_id, org_id, user_id, kind, payload,
+	status, attempts, last_error, next_attempt_at, created_at, updated_at`
+
+// emailPayload is the JSON stored in email_notifications.payload.
+type emailPayload struct {
+	PR            domain.EventPR `json:"pull_request"`
+	OldReviewerID string         `json:"old_reviewer_id,omitempty"`
+}
+
+func (r *EmailRepo) GetEmailPrefs(ctx context.Context, userID string) (domain.EmailPrefs, error) {
+	p := domain.EmailPrefs{UserID: userID}
+	err := r.pool.QueryRow(ctx, `
+		SELECT assigned, reassigned, sla_breach, daily_digest FROM email_prefs
+		WHERE org_id=$1 AND user_id=$2`, domain.OrgFromContext(ctx), userID,
+	).Scan(&p.Assigned, &p.Reassigned, &p.SLABreach, &p.Digest)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.EmailPrefs{}, domain.ErrNotFound
+		}
+		return domain.EmailPrefs{}, err
+	}
+	return p, nil
+}
+
+func (r *EmailRepo) SetEmailPrefs(ctx context.Context, p domain.EmailPrefs) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO email_prefs (org_id, user_id, assigned, reassigned, sla_breach, daily_digest) VALUES ($1,$2,$3,$4,$5,$6)
+		ON CONFLICT (org_id, user_id) DO UPDATE
+		  SET assigned=EXCLUDED.assigned, reassigned=EXCLUDED.reassigned, sla_breach=EXCLUDED.sla_breach,
+		      daily_digest=EXCLUDED.daily_digest`,
+		domain.OrgFromContext(ctx), p.UserID, p.Assigned, p.Reassigned, p.SLABreach, p.Digest)
+	if isForeignKeyViolation(err) {
+		return domain.ErrNotFound
+	}
+	return err
+}
+
+func (r *EmailRepo) EnqueueEmail(ctx context.Context, n domain.EmailNotification) error {
+	payload, err := json.Marshal(emailPayload{PR: n.PR, OldReviewerID: n.OldReviewerID})
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO email_notifications (org_id, user_id, kind, pull_request_id, payload)
+		VALUES ($1,$2,$3,$4,$5)
+		ON CONFLICT DO NOTHING`,
+		domain.OrgFromContext(ctx), n.UserID, n.Kind, n.PR.ID, payload)
+	return err
+}
+
+// ClaimEmails relies on the row locks of the UPDATE: a replica that picked
+// the same user waits, then finds the rows leased and skips them.
+func (r *EmailRepo) ClaimEmails(ctx context.Context, queuedBefore, now, leaseUntil time.Time, limit int) ([]domain.EmailNotification, error) {
+	rows, err := r.pool.Query(ctx, `
+		UPDATE email_notifications SET next_attempt_at = $3, updated_at = now()
+		WHERE status = 'pending' AND next_attempt_at <= $2
+		  AND (org_id, user_id) IN (
+			SELECT org_id, user_id FROM email_notifications
+			WHERE status = 'pending'
+			GROUP BY org_id, user_id
+			HAVING MIN(created_at) <= $1 AND MAX(next_attempt_at) <= $2
+			ORDER BY MIN(created_at)
+			LIMIT $4)
+		RETURNING `+emailColumns, queuedBefore, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	out, err := collectEmails(rows)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(out, func(a, b domain.EmailNotification) int { return cmp.Compare(a.ID, b.ID) })
+	return out, nil
+}
+
+func (r *EmailRepo) SaveEmail(ctx context.Context, n domain.EmailNotification) error {
+	ct, err := r.pool.Exec(ctx, `
+		UPDATE email_notifications
+		SET status=$2, attempts=$3, last_error=$4, next_attempt_at=$5, updated_at=now()
+		WHERE notification_id=$1`, n.ID, n.Status, n.Attempts, n.LastError, n.NextAttemptAt)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *EmailRepo) ListEmails(ctx context.Context, status domain.EmailStatus) ([]domain.EmailNotification, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+emailColumns+` FROM email_notifications
+		WHERE $1 = '' OR status = $1
+		ORDER BY notification_id`, status)
+	if err != nil {
+		return nil, err
+	}
+	return collectEmails(rows)
+}
+
+func (r *EmailRepo) ListSLABreaches(ctx context.Context, createdBefore time.Time, limit int) ([]domain.EmailNotification, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT pr.org_id, r.reviewer_id, pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.created_at
+		FROM pull_requests pr
+		JOIN pr_reviewers r ON r.org_id = pr.org_id AND r.pull_request_id = pr.pull_request_id
+		JOIN users u ON u.org_id = r.org_id AND u.user_id = r.reviewer_id
+		LEFT JOIN email_prefs p ON p.org_id = u.org_id AND p.user_id = u.user_id
+		WHERE pr.status = 'OPEN' AND pr.created_at <= $1
+		  AND u.email <> '' AND u.is_active AND COALESCE(p.sla_breach, TRUE)
+		  AND NOT EXISTS (
+			SELECT 1 FROM email_notifications e
+			WHERE e.org_id = r.org_id AND e.user_id = r.reviewer_id
+			  AND e.pull_request_id = pr.pull_request_id AND e.kind = 'sla_breach')
+		ORDER BY pr.created_at, pr.org_id, pr.pull_request_id, r.reviewer_id
+		LIMIT $2`, createdBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.EmailNotification
+	for rows.Next() {
+		n := domain.EmailNotification{Kind: domain.EmailSLABreach, Status: domain.EmailPending}
+		var created time.Time
+		if err := rows.Scan(&n.OrgID, &n.UserID, &n.PR.ID, &n.PR.Name, &n.PR.AuthorID, &created); err != nil {
+			return nil, err
+		}
+		n.PR.Status = domain.StatusOpen
+		n.PR.CreatedAt = &created
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
+
+func collectEmails(rows pgx.Rows) ([]domain.EmailNotification, error) {
+	defer rows.Close()
+
+	var out []domain.EmailNotification
+	for rows.Next() {
+		var (
+			n       domain.EmailNotification
+			payload []byte
+		)
+		if err := rows.Scan(&n.ID, &n.OrgID, &n.UserID, &n.Kind, &payload,
+			&n.Status, &n.Attempts, &n.LastError, &n.NextAttemptAt, &n.CreatedAt, &n.UpdatedAt); err != nil {
+			return nil, err
+		}
+		var p emailPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, err
+		}
+		n.PR, n.OldReviewerID = p.PR, p.OldReviewerID
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
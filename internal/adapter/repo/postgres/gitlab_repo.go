@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type GitLabProjectRepo struct{ pool *pgxpool.Pool }
+
+func NewGitLabProjectRepo(pool *pgxpool.Pool) *GitLabProjectRepo {
+	return &GitLabProjectRepo{pool: pool}
+}
+
+func (r *GitLabProjectRepo) SetGitLabProject(ctx context.Context, p domain.GitLabProject) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO gitlab_projects (project_id, org_id, team_name) VALUES ($1,$2,$3)
+		ON CONFLICT (project_id) DO UPDATE
+		  SET org_id=EXCLUDED.org_id, team_name=EXCLUDED.team_name`,
+		p.ProjectID, p.OrgID, p.TeamName)
+	if isForeignKeyViolation(err) {
+		return domain.ErrNotFound
+	}
+	return err
+}
+
+func (r *GitLabProjectRepo) GetGitLabProject(ctx context.Context, projectID int64) (domain.GitLabProject, error) {
+	var p domain.GitLabProject
+	err := r.pool.QueryRow(ctx,
+		`SELECT project_id, org_id, team_name FROM gitlab_projects WHERE project_id=$1`, projectID,
+	).Scan(&p.ProjectID, &p.OrgID, &p.TeamName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.GitLabProject{}, domain.ErrNotFound
+		}
+		return domain.GitLabProject{}, err
+	}
+	return p, nil
+}
+
+func (r *GitLabProjectRepo) DeleteGitLabProject(ctx context.Context, projectID int64) error {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM gitlab_projects WHERE project_id=$1`, projectID)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *GitLabProjectRepo) ListGitLabProjects(ctx context.Context) ([]domain.GitLabProject, error) {
+	rows, err := r.pool.Query(ctx, `SELECT project_id, org_id, team_name FROM gitlab_projects ORDER BY project_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.GitLabProject
+	for rows.Next() {
+		var p domain.GitLabProject
+		if err := rows.Scan(&p.ProjectID, &p.OrgID, &p.TeamName); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type IdempotencyRepo struct{ pool *pgxpool.Pool }
+
+func NewIdempotencyRepo(pool *pgxpool.Pool) *IdempotencyRepo { return &IdempotencyRepo{pool: pool} }
+
+// ReserveIdempotencyKey also drops the expired keys of every organization;
+// the created_at index keeps that cheap.
+func (r *IdempotencyRepo) ReserveIdempotencyKey(ctx context.Context, rec domain.IdempotencyRecord, expiredBefore, staleBefore time.Time) (domain.IdempotencyRecord, bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("postgres: rollback failed in ReserveIdempotencyKey: %v", err)
+		}
+	}()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM idempotency_keys WHERE created_at < $1`, expiredBefore); err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	org := domain.OrgFromContext(ctx)
+	rec.Status, rec.ContentType, rec.Header, rec.Body = 0, "", nil, nil
+	err = tx.QueryRow(ctx, `
+		INSERT INTO idempotency_keys (org_id, owner, idempotency_key, request_hash, lease)
+		VALUES ($1,$2,$3,$4,$5)
+		ON CONFLICT DO NOTHING
+		RETURNING created_at`, org, rec.Owner, rec.Key, rec.RequestHash, rec.Lease,
+	).Scan(&rec.CreatedAt)
+	switch {
+	case err == nil:
+		return rec, true, tx.Commit(ctx)
+	case !errors.Is(err, pgx.ErrNoRows):
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	// The insert waited for a concurrent reservation of the key to commit,
+	// so these statements see it. Of concurrent takeovers the first wins;
+	// the others find created_at moved on.
+	err = tx.QueryRow(ctx, `
+		UPDATE idempotency_keys SET lease=$5, created_at=now()
+		WHERE org_id=$1 AND owner=$2 AND idempotency_key=$3
+		  AND request_hash=$4 AND status=0 AND created_at < $6
+		RETURNING created_at`, org, rec.Owner, rec.Key, rec.RequestHash, rec.Lease, staleBefore,
+	).Scan(&rec.CreatedAt)
+	switch {
+	case err == nil:
+		return rec, true, tx.Commit(ctx)
+	case !errors.Is(err, pgx.ErrNoRows):
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	var (
+		got    = domain.IdempotencyRecord{Owner: rec.Owner, Key: rec.Key}
+		header []byte
+	)
+	if err := tx.QueryRow(ctx, `
+		SELECT lease, request_hash, status, content_type, headers, body, created_at
+		FROM idempotency_keys
+		WHERE org_id=$1 AND owner=$2 AND idempotency_key=$3`, org, rec.Owner, rec.Key,
+	).Scan(&got.Lease, &got.RequestHash, &got.Status, &got.ContentType, &header, &got.Body, &got.CreatedAt); err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	if err := json.Unmarshal(header, &got.Header); err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	return got, false, tx.Commit(ctx)
+}
+
+func (r *IdempotencyRepo) CompleteIdempotencyKey(ctx context.Context, rec domain.IdempotencyRecord) error {
+	header, err := json.Marshal(rec.Header)
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx, `
+		UPDATE idempotency_keys SET status=$5, content_type=$6, headers=$7, body=$8
+		WHERE org_id=$1 AND owner=$2 AND idempotency_key=$3 AND lease=$4`,
+		domain.OrgFromContext(ctx), rec.Owner, rec.Key, rec.Lease, rec.Status, rec.ContentType, header, rec.Body)
+	return err
+}
+
+func (r *IdempotencyRepo) ReleaseIdempotencyKey(ctx context.Context, owner, key, lease string) error {
+	_, err := r.pool.Exec(ctx,
+		`DELETE FROM idempotency_keys WHERE org_id=$1 AND owner=$2 AND idempotency_key=$3 AND lease=$4`,
+		domain.OrgFromContext(ctx), owner, key, lease)
+	return err
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// outboxChannel is notified by the trigger of migration 0011.
+const outboxChannel = "outbox"
+
+// ListenOutbox calls notify with the organization of every outbox row
+// committed by any replica. It holds one pool connection and reconnects
+// after errors until ctx is done.
+func ListenOutbox(ctx context.Context, pool *pgxpool.Pool, notify func(orgID string), logger *slog.Logger) {
+	for ctx.Err() == nil {
+		err := listen(ctx, pool, notify)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warn("postgres: outbox listener stopped, reconnecting", "err", err)
+		// A notification may have been missed while disconnected.
+		notify("")
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func listen(ctx context.Context, pool *pgxpool.Pool, notify func(orgID string)) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The connection stays in LISTEN state, so it is taken out of the pool.
+	c := conn.Hijack()
+	defer func() { _ = c.Close(context.Background()) }()
+
+	if _, err := c.Exec(ctx, "LISTEN "+outboxChannel); err != nil {
+		return err
+	}
+	for {
+		n, err := c.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		notify(n.Payload)
+	}
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type OrgRepo struct{ pool *pgxpool.Pool }
+
+func NewOrgRepo(pool *pgxpool.Pool) *OrgRepo { return &OrgRepo{pool: pool} }
+
+func (r *OrgRepo) CreateOrg(ctx context.Context, org domain.Organization) (domain.Organization, error) {
+	var out domain.Organization
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO organizations (org_id, name) VALUES ($1,$2)
+		RETURNING org_id, name, created_at`, org.OrgID, org.Name).
+		Scan(&out.OrgID, &out.Name, &out.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.Organization{}, domain.ErrOrgExists
+		}
+		return domain.Organization{}, err
+	}
+	return out, nil
+}
+
+func (r *OrgRepo) GetOrg(ctx context.Context, orgID string) (domain.Organization, error) {
+	var out domain.Organization
+	err := r.pool.QueryRow(ctx, `
+		SELECT org_id, name, created_at FROM organizations WHERE org_id=$1`, orgID).
+		Scan(&out.OrgID, &out.Name, &out.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Organization{}, domain.ErrNotFound
+		}
+		return domain.Organization{}, err
+	}
+	return out, nil
+}
+
+func (r *OrgRepo) ListOrgs(ctx context.Context) ([]domain.Organization, error) {
+	rows, err := r.pool.Query(ctx, `SELECT org_id, name, created_at FROM organizations ORDER BY org_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Organization
+	for rows.Next() {
+		var o domain.Organization
+		if err := rows.Scan(&o.OrgID, &o.Name, &o.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
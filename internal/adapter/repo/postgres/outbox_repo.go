@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// querier is what the pool and a transaction have in common, so that reads
+// can be shared between plain calls and the writes that fill the outbox.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type OutboxRepo struct{ pool *pgxpool.Pool }
+
+func NewOutboxRepo(pool *pgxpool.Pool) *OutboxRepo { return &OutboxRepo{pool: pool} }
+
+const outboxColumns = `outbox_id, msg_key, payload, status, attempts, last_error,
+	published_to, next_attempt_at, created_at, updated_at`
+
+// insertOutbox must run inside the transaction that makes the change. The
+// advisory lock serializes outbox writes of an organization until commit,
+// so its IDs become visible in increasing order and stream readers that
+// page by ID never skip a row committed late.
+func insertOutbox(ctx context.Context, q querier, e *domain.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := q.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('outbox'), hashtext($1))`, e.OrgID); err != nil {
+		return err
+	}
+	_, err = q.Exec(ctx, `
+		INSERT INTO outbox (org_id, event_id, event_type, msg_key, payload)
+		VALUES ($1,$2,$3,$4,$5)`, e.OrgID, e.ID, e.Type, e.Key(), payload)
+	return err
+}
+
+// ClaimOutbox leases the oldest pending message of each key with SKIP
+// LOCKED, so relays on several replicas neither share a message nor publish
+// a key out of order.
+func (r *OutboxRepo) ClaimOutbox(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxMessage, error) {
+	rows, err := r.pool.Query(ctx, `
+		UPDATE outbox SET next_attempt_at = $2
+		WHERE outbox_id IN (
+			SELECT m.outbox_id FROM outbox m
+			WHERE m.status = 'pending' AND m.next_attempt_at <= $1
+			  AND NOT EXISTS (
+				SELECT 1 FROM outbox o
+				WHERE o.org_id = m.org_id AND o.msg_key = m.msg_key
+				  AND o.status = 'pending' AND o.outbox_id < m.outbox_id)
+			ORDER BY m.outbox_id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED)
+		RETURNING `+outboxColumns, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	out, err := collectOutbox(rows)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(out, func(a, b domain.OutboxMessage) int { return cmp.Compare(a.ID, b.ID) })
+	return out, nil
+}
+
+func (r *OutboxRepo) GetOutbox(ctx context.Context, id int64) (domain.OutboxMessage, error) {
+	return scanOutbox(r.pool.QueryRow(ctx, `SELECT `+outboxColumns+` FROM outbox WHERE outbox_id=$1`, id))
+}
+
+func (r *OutboxRepo) SaveOutbox(ctx context.Context, m domain.OutboxMessage) error {
+	if m.PublishedTo == nil {
+		m.PublishedTo = []string{}
+	}
+	ct, err := r.pool.Exec(ctx, `
+		UPDATE outbox
+		SET status=$2, attempts=$3, last_error=$4, published_to=$5, next_attempt_at=$6, updated_at=now()
+		WHERE outbox_id=$1`, m.ID, m.Status, m.Attempts, m.LastError, m.PublishedTo, m.NextAttemptAt)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *OutboxRepo) ListOutbox(ctx context.Context, status domain.OutboxStatus) ([]domain.OutboxMessage, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+outboxColumns+` FROM outbox
+		WHERE $1 = '' OR status = $1
+		ORDER BY outbox_id`, status)
+	if err != nil {
+		return nil, err
+	}
+	return collectOutbox(rows)
+}
+
+func (r *OutboxRepo) ListOutboxAfter(ctx context.Context, afterID int64, limit int) ([]domain.OutboxMessage, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+outboxColumns+` FROM outbox
+		WHERE org_id = $1 AND outbox_id > $2
+		ORDER BY outbox_id
+		LIMIT $3`, domain.OrgFromContext(ctx), afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return collectOutbox(rows)
+}
+
+func (r *OutboxRepo) LastOutboxID(ctx context.Context) (int64, error) {
+	var id int64
+	err := r.pool.QueryRow(ctx,
+		`SELECT COALESCE(MAX(outbox_id), 0) FROM outbox WHERE org_id = $1`, domain.OrgFromContext(ctx),
+	).Scan(&id)
+	return id, err
+}
+
+func collectOutbox(rows pgx.Rows) ([]domain.OutboxMessage, error) {
+	defer rows.Close()
+
+	var out []domain.OutboxMessage
+	for rows.Next() {
+		m, err := scanOutbox(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+func scanOutbox(row pgx.Row) (domain.OutboxMessage, error) {
+	var (
+		m       domain.OutboxMessage
+		payload []byte
+	)
+	err := row.Scan(&m.ID, &m.Key, &payload, &m.Status, &m.Attempts, &m.LastError,
+		&m.PublishedTo, &m.NextAttemptAt, &m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.OutboxMessage{}, domain.ErrNotFound
+		}
+		return domain.OutboxMessage{}, err
+	}
+	if err := json.Unmarshal(payload, &m.Event); err != nil {
+		return domain.OutboxMessage{}, err
+	}
+	return m, nil
+}
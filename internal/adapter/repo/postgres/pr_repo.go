@@ -2,7 +2,9 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"time"
 
@@ -16,10 +18,12 @@ type PRRepo struct{ pool *pgxpool.Pool }
 
 func NewPRRepo(pool *pgxpool.Pool) *PRRepo { return &PRRepo{pool: pool} }
 
-func (r *PRRepo) CreatePRWithReviewers(ctx context.Context, pr domain.PullRequest, reviewers []string) (domain.PullRequest, error) {
+func (r *PRRepo) CreatePRWithReviewers(ctx context.Context, pr domain.PullRequest, reviewers []string, e *domain.Event) (domain.PullRequest, error) {
+	org := domain.OrgFromContext(ctx)
+
 	var exists bool
 	if err := r.pool.QueryRow(ctx,
-		`SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id=$1)`, pr.ID,
+		`SELECT EXISTS(SELECT 1 FROM pull_requests WHERE org_id=$1 AND pull_request_id=$2)`, org, pr.ID,
 	).Scan(&exists); err != nil {
 		return domain.PullRequest{}, err
 	}
@@ -38,9 +42,9 @@ func (r *PRRepo) CreatePRWithReviewers(ctx context.Context, pr domain.PullReques
 	}()
 
 	_, err = tx.Exec(ctx, `
-	  INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status)
-	  VALUES ($1,$2,$3,'OPEN')`,
-		pr.ID, pr.Name, pr.AuthorID)
+	  INSERT INTO pull_requests (org_id, pull_request_id, pull_request_name, author_id, status)
+	  VALUES ($1,$2,$3,$4,'OPEN')`,
+		org, pr.ID, pr.Name, pr.AuthorID)
 	if err != nil {
 		if isUniqueViolation(err) {
 			return domain.PullRequest{}, domain.ErrPRExists
@@ -50,36 +54,57 @@ func (r *PRRepo) CreatePRWithReviewers(ctx context.Context, pr domain.PullReques
 
 	for _, rid := range reviewers {
 		if _, err := tx.Exec(ctx,
-			`INSERT INTO pr_reviewers (pull_request_id, reviewer_id) VALUES ($1,$2)`,
-			pr.ID, rid,
+			`INSERT INTO pr_reviewers (org_id, pull_request_id, reviewer_id) VALUES ($1,$2,$3)`,
+			org, pr.ID, rid,
 		); err != nil {
 			return domain.PullRequest{}, err
 		}
 	}
+	return commitWithEvent(ctx, tx, pr.ID, domain.AuditPRCreated, nil, e)
+}
 
+// commitWithEvent reads the PR back inside tx, records the change from
+// before in the audit log if action is set, writes e to the outbox if it is
+// set and commits.
+func commitWithEvent(ctx context.Context, tx pgx.Tx, prID string, action domain.AuditAction, before *domain.PullRequest, e *domain.Event) (domain.PullRequest, error) {
+	out, err := getPR(ctx, tx, prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	if action != "" {
+		if err := insertAudit(ctx, tx, domain.NewAuditEntry(ctx, action, prID, domain.AuditPR(before), domain.AuditPR(&out))); err != nil {
+			return domain.PullRequest{}, err
+		}
+	}
+	if e != nil {
+		e.Data.PullRequest = domain.NewEventPR(out)
+		if err := insertOutbox(ctx, tx, e); err != nil {
+			return domain.PullRequest{}, err
+		}
+	}
 	if err := tx.Commit(ctx); err != nil {
 		return domain.PullRequest{}, err
 	}
-	return r.getByID(ctx, pr.ID)
+	return out, nil
 }
 
 func (r *PRRepo) GetByIDForUpdate(ctx context.Context, id string) (domain.PullRequest, error) {
-	return r.getByID(ctx, id)
+	return getPR(ctx, r.pool, id)
 }
 
-func (r *PRRepo) getByID(ctx context.Context, id string) (domain.PullRequest, error) {
+func getPR(ctx context.Context, q querier, id string) (domain.PullRequest, error) {
 	var out domain.PullRequest
-	err := r.pool.QueryRow(ctx, `
-		SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
-		FROM pull_requests WHERE pull_request_id=$1`, id).
-		Scan(&out.ID, &out.Name, &out.AuthorID, &out.Status, &out.CreatedAt, &out.MergedAt)
+	err := q.QueryRow(ctx, `
+		SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, version
+		FROM pull_requests WHERE org_id=$1 AND pull_request_id=$2`, domain.OrgFromContext(ctx), id).
+		Scan(&out.ID, &out.Name, &out.AuthorID, &out.Status, &out.CreatedAt, &out.MergedAt, &out.Version)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.PullRequest{}, domain.ErrNotFound
 		}
 		return domain.PullRequest{}, err
 	}
-	revs, err := r.GetAssignedReviewers(ctx, id)
+	revs, err := assignedReviewers(ctx, q, id)
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
@@ -87,8 +112,25 @@ func (r *PRRepo) getByID(ctx context.Context, id string) (domain.PullRequest, er
 	return out, nil
 }
 
+// lockPR reads the PR inside tx and locks it until the transaction ends,
+// so that the state recorded as before is the one the change replaces.
+func lockPR(ctx context.Context, tx pgx.Tx, id string) (domain.PullRequest, error) {
+	if _, err := tx.Exec(ctx,
+		`SELECT 1 FROM pull_requests WHERE org_id=$1 AND pull_request_id=$2 FOR UPDATE`,
+		domain.OrgFromContext(ctx), id); err != nil {
+		return domain.PullRequest{}, err
+	}
+	return getPR(ctx, tx, id)
+}
+
 func (r *PRRepo) GetAssignedReviewers(ctx context.Context, prID string) ([]string, error) {
-	rows, err := r.pool.Query(ctx, `SELECT reviewer_id FROM pr_reviewers WHERE pull_request_id=$1`, prID)
+	return assignedReviewers(ctx, r.pool, prID)
+}
+
+func assignedReviewers(ctx context.Context, q querier, prID string) ([]string, error) {
+	rows, err := q.Query(ctx, `
+		SELECT reviewer_id FROM pr_reviewers
+		WHERE org_id=$1 AND pull_request_id=$2`, domain.OrgFromContext(ctx), prID)
 	if err != nil {
 		return nil, err
 	}
@@ -105,7 +147,9 @@ func (r *PRRepo) GetAssignedReviewers(ctx context.Context, prID string) ([]strin
 	return out, rows.Err()
 }
 
-func (r *PRRepo) ReplaceReviewer(ctx context.Context, prID, oldID, newID string) (domain.PullRequest, error) {
+func (r *PRRepo) ReplaceReviewer(ctx context.Context, prID, oldID, newID string, version int64, e *domain.Event) (domain.PullRequest, error) {
+	org := domain.OrgFromContext(ctx)
+
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return domain.PullRequest{}, err
@@ -116,36 +160,200 @@ func (r *PRRepo) ReplaceReviewer(ctx context.Context, prID, oldID, newID string)
 		}
 	}()
 
-	if _, err := tx.Exec(ctx, `DELETE FROM pr_reviewers WHERE pull_request_id=$1 AND reviewer_id=$2`, prID, oldID); err != nil {
+	before, err := lockPR(ctx, tx, prID)
+	if err != nil {
 		return domain.PullRequest{}, err
 	}
-	if _, err := tx.Exec(ctx, `INSERT INTO pr_reviewers (pull_request_id, reviewer_id) VALUES ($1,$2)`, prID, newID); err != nil {
+	if !before.MatchesVersion(version) {
+		return domain.PullRequest{}, domain.ErrVersionMismatch
+	}
+	if err := before.CanReplaceReviewer(oldID, newID); err != nil {
 		return domain.PullRequest{}, err
 	}
-	if err := tx.Commit(ctx); err != nil {
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM pr_reviewers WHERE org_id=$1 AND pull_request_id=$2 AND reviewer_id=$3`,
+		org, prID, oldID,
+	); err != nil {
 		return domain.PullRequest{}, err
 	}
-	return r.getByID(ctx, prID)
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO pr_reviewers (org_id, pull_request_id, reviewer_id) VALUES ($1,$2,$3)`,
+		org, prID, newID,
+	); err != nil {
+		return domain.PullRequest{}, err
+	}
+	if err := bumpVersion(ctx, tx, prID); err != nil {
+		return domain.PullRequest{}, err
+	}
+	return commitWithEvent(ctx, tx, prID, domain.AuditPRReassigned, &before, e)
 }
 
-func (r *PRRepo) SetMerged(ctx context.Context, prID string) (domain.PullRequest, error) {
-	_, err := r.pool.Exec(ctx, `
+// SetMerged writes e only when the PR was still open. A closed PR is
+// rejected rather than merged.
+func (r *PRRepo) SetMerged(ctx context.Context, prID string, version int64, e *domain.Event) (domain.PullRequest, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("postgres: rollback failed in SetMerged: %v", err)
+		}
+	}()
+
+	before, err := lockPR(ctx, tx, prID)
+	switch {
+	case err == nil && !before.MatchesVersion(version):
+		return domain.PullRequest{}, domain.ErrVersionMismatch
+	case err == nil && before.Status == domain.StatusClosed:
+		return domain.PullRequest{}, domain.ErrPRClosed
+	case err != nil && !errors.Is(err, domain.ErrNotFound):
+		return domain.PullRequest{}, err
+	}
+	ct, err := tx.Exec(ctx, `
 		UPDATE pull_requests
-		SET status='MERGED', merged_at = COALESCE(merged_at, $2)
-		WHERE pull_request_id=$1`, prID, time.Now().UTC())
+		SET status='MERGED', merged_at = COALESCE(merged_at, $3), version = version + 1
+		WHERE org_id=$1 AND pull_request_id=$2 AND status = 'OPEN'`,
+		domain.OrgFromContext(ctx), prID, time.Now().UTC())
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
-	return r.getByID(ctx, prID)
+	if ct.RowsAffected() == 0 {
+		return commitWithEvent(ctx, tx, prID, "", nil, nil)
+	}
+	return commitWithEvent(ctx, tx, prID, domain.AuditPRMerged, &before, e)
+}
+
+// SetClosed writes e only when the status changes.
+func (r *PRRepo) SetClosed(ctx context.Context, prID string, closed bool, e *domain.Event) (domain.PullRequest, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("postgres: rollback failed in SetClosed: %v", err)
+		}
+	}()
+
+	before, err := lockPR(ctx, tx, prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	from, to, action := domain.StatusOpen, domain.StatusClosed, domain.AuditPRClosed
+	if !closed {
+		from, to, action = to, from, domain.AuditPRReopened
+	}
+	if before.Status != from {
+		return commitWithEvent(ctx, tx, prID, "", nil, nil)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE pull_requests SET status=$3, version = version + 1
+		WHERE org_id=$1 AND pull_request_id=$2`,
+		domain.OrgFromContext(ctx), prID, string(to)); err != nil {
+		return domain.PullRequest{}, err
+	}
+	return commitWithEvent(ctx, tx, prID, action, &before, e)
+}
+
+// SetReview writes e only when the verdict changes.
+func (r *PRRepo) SetReview(ctx context.Context, prID string, rv domain.Review, version int64, e *domain.Event) (domain.PullRequest, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("postgres: rollback failed in SetReview: %v", err)
+		}
+	}()
+
+	pr, err := lockPR(ctx, tx, prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	if !pr.MatchesVersion(version) {
+		return domain.PullRequest{}, domain.ErrVersionMismatch
+	}
+	if err := pr.CheckOpen(); err != nil {
+		return domain.PullRequest{}, err
+	}
+	var before *domain.Review
+	prev, err := scanReview(tx.QueryRow(ctx, `
+		SELECT reviewer_id, verdict, created_at FROM pr_reviews
+		WHERE org_id=$1 AND pull_request_id=$2 AND reviewer_id=$3`, domain.OrgFromContext(ctx), prID, rv.ReviewerID))
+	switch {
+	case err == nil && prev.Verdict == rv.Verdict:
+		return commitWithEvent(ctx, tx, prID, "", nil, nil)
+	case err == nil:
+		before = &prev
+	case !errors.Is(err, pgx.ErrNoRows):
+		return domain.PullRequest{}, err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO pr_reviews (org_id, pull_request_id, reviewer_id, verdict)
+		VALUES ($1,$2,$3,$4)
+		ON CONFLICT (org_id, pull_request_id, reviewer_id) DO UPDATE
+		  SET verdict=EXCLUDED.verdict, created_at=now()`,
+		domain.OrgFromContext(ctx), prID, rv.ReviewerID, rv.Verdict)
+	if isForeignKeyViolation(err) {
+		return domain.PullRequest{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	if err := insertAudit(ctx, tx, domain.NewAuditEntry(ctx, domain.AuditPRReviewed, prID,
+		domain.AuditReview(prID, before), domain.AuditReview(prID, &rv))); err != nil {
+		return domain.PullRequest{}, err
+	}
+	if err := bumpVersion(ctx, tx, prID); err != nil {
+		return domain.PullRequest{}, err
+	}
+	return commitWithEvent(ctx, tx, prID, "", nil, e)
+}
+
+// bumpVersion records a change of the PR's reviewers or verdicts.
+func bumpVersion(ctx context.Context, tx pgx.Tx, prID string) error {
+	_, err := tx.Exec(ctx,
+		`UPDATE pull_requests SET version = version + 1 WHERE org_id=$1 AND pull_request_id=$2`,
+		domain.OrgFromContext(ctx), prID)
+	return err
+}
+
+func (r *PRRepo) ListReviews(ctx context.Context, prID string) ([]domain.Review, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT reviewer_id, verdict, created_at FROM pr_reviews
+		WHERE org_id=$1 AND pull_request_id=$2
+		ORDER BY reviewer_id`, domain.OrgFromContext(ctx), prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Review
+	for rows.Next() {
+		rv, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rv)
+	}
+	return out, rows.Err()
+}
+
+func scanReview(row pgx.Row) (domain.Review, error) {
+	var rv domain.Review
+	err := row.Scan(&rv.ReviewerID, &rv.Verdict, &rv.CreatedAt)
+	return rv, err
 }
 
 func (r *PRRepo) ListByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequestShort, error) {
 	rows, err := r.pool.Query(ctx, `
-		SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status
+		SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at
 		FROM pull_requests pr
-		JOIN pr_reviewers r ON r.pull_request_id = pr.pull_request_id
-		WHERE r.reviewer_id = $1
-		ORDER BY pr.created_at DESC`, reviewerID)
+		JOIN pr_reviewers r ON r.org_id = pr.org_id AND r.pull_request_id = pr.pull_request_id
+		WHERE r.org_id = $1 AND r.reviewer_id = $2
+		ORDER BY pr.created_at DESC`, domain.OrgFromContext(ctx), reviewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -154,7 +362,7 @@ func (r *PRRepo) ListByReviewer(ctx context.Context, reviewerID string) ([]domai
 	var out []domain.PullRequestShort
 	for rows.Next() {
 		var s domain.PullRequestShort
-		if err := rows.Scan(&s.ID, &s.Name, &s.AuthorID, &s.Status); err != nil {
+		if err := rows.Scan(&s.ID, &s.Name, &s.AuthorID, &s.Status, &s.CreatedAt); err != nil {
 			return nil, err
 		}
 		out = append(out, s)
@@ -162,11 +370,39 @@ func (r *PRRepo) ListByReviewer(ctx context.Context, reviewerID string) ([]domai
 	return out, rows.Err()
 }
 
+func (r *PRRepo) ListEvents(ctx context.Context, prID string) ([]domain.Event, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT payload FROM outbox
+		WHERE org_id = $1 AND msg_key = $2
+		ORDER BY outbox_id`, domain.OrgFromContext(ctx), domain.PRKey(prID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Event
+	for rows.Next() {
+		var (
+			payload []byte
+			e       domain.Event
+		)
+		if err := rows.Scan(&payload); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(payload, &e); err != nil {
+			return nil, fmt.Errorf("decode event of %s: %w", prID, err)
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
 func (r *PRRepo) StatsByStatus(ctx context.Context) (map[domain.PRStatus]int, error) {
 	rows, err := r.pool.Query(ctx, `
 		SELECT status, COUNT(*)
 		FROM pull_requests
-		GROUP BY status`,
+		WHERE org_id = $1
+		GROUP BY status`, domain.OrgFromContext(ctx),
 	)
 	if err != nil {
 		return nil, err
@@ -176,6 +412,7 @@ func (r *PRRepo) StatsByStatus(ctx context.Context) (map[domain.PRStatus]int, er
 	res := map[domain.PRStatus]int{
 		domain.StatusOpen:   0,
 		domain.StatusMerged: 0,
+		domain.StatusClosed: 0,
 	}
 
 	for rows.Next() {
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type ReviewerSyncRepo struct{ pool *pgxpool.Pool }
+
+func NewReviewerSyncRepo(pool *pgxpool.Pool) *ReviewerSyncRepo { return &ReviewerSyncRepo{pool: pool} }
+
+const syncColumns = `sync_id, org_id, pull_request_id, add_reviewers, remove_reviewers,
+	status, attempts, last_error, next_attempt_at, created_at, updated_at`
+
+func (r *ReviewerSyncRepo) EnqueueReviewerSync(ctx context.Context, s domain.ReviewerSync) (domain.ReviewerSync, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO reviewer_syncs (org_id, pull_request_id, add_reviewers, remove_reviewers, status, next_attempt_at)
+		VALUES ($1,$2,$3,$4,$5,$6)
+		RETURNING `+syncColumns,
+		domain.OrgFromContext(ctx), s.PRID, nonNil(s.Add), nonNil(s.Remove), s.Status, s.NextAttemptAt)
+	return scanSync(row)
+}
+
+// ClaimReviewerSyncs leases rows with SKIP LOCKED so that replicas running
+// the push worker never pick the same sync.
+func (r *ReviewerSyncRepo) ClaimReviewerSyncs(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.ReviewerSync, error) {
+	rows, err := r.pool.Query(ctx, `
+		UPDATE reviewer_syncs SET next_attempt_at = $2
+		WHERE sync_id IN (
+			SELECT s.sync_id FROM reviewer_syncs s
+			WHERE s.status = 'pending' AND s.next_attempt_at <= $1
+			  AND NOT EXISTS (
+				SELECT 1 FROM reviewer_syncs o
+				WHERE o.org_id = s.org_id AND o.pull_request_id = s.pull_request_id
+				  AND o.status = 'pending' AND o.sync_id < s.sync_id)
+			ORDER BY s.sync_id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED)
+		RETURNING `+syncColumns, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	out, err := collectSyncs(rows)
+	if err != nil {
+		return nil, err
+	}
+	sortSyncs(out)
+	return out, nil
+}
+
+func (r *ReviewerSyncRepo) GetReviewerSync(ctx context.Context, id int64) (domain.ReviewerSync, error) {
+	return scanSync(r.pool.QueryRow(ctx, `SELECT `+syncColumns+` FROM reviewer_syncs WHERE sync_id=$1`, id))
+}
+
+func (r *ReviewerSyncRepo) SaveReviewerSync(ctx context.Context, s domain.ReviewerSync) error {
+	ct, err := r.pool.Exec(ctx, `
+		UPDATE reviewer_syncs
+		SET status=$2, attempts=$3, last_error=$4, next_attempt_at=$5, updated_at=now()
+		WHERE sync_id=$1`, s.ID, s.Status, s.Attempts, s.LastError, s.NextAttemptAt)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ReviewerSyncRepo) ListReviewerSyncs(ctx context.Context, status domain.SyncStatus) ([]domain.ReviewerSync, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+syncColumns+` FROM reviewer_syncs
+		WHERE $1 = '' OR status = $1
+		ORDER BY sync_id`, status)
+	if err != nil {
+		return nil, err
+	}
+	return collectSyncs(rows)
+}
+
+func collectSyncs(rows pgx.Rows) ([]domain.ReviewerSync, error) {
+	defer rows.Close()
+
+	var out []domain.ReviewerSync
+	for rows.Next() {
+		s, err := scanSync(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func scanSync(row pgx.Row) (domain.ReviewerSync, error) {
+	var s domain.ReviewerSync
+	err := row.Scan(&s.ID, &s.OrgID, &s.PRID, &s.Add, &s.Remove,
+		&s.Status, &s.Attempts, &s.LastError, &s.NextAttemptAt, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ReviewerSync{}, domain.ErrNotFound
+		}
+		return domain.ReviewerSync{}, err
+	}
+	return s, nil
+}
+
+// sortSyncs restores id order, which UPDATE ... RETURNING does not keep.
+func sortSyncs(s []domain.ReviewerSync) {
+	slices.SortFunc(s, func(a, b domain.ReviewerSync) int { return cmp.Compare(a.ID, b.ID) })
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type SlackRepo struct{ pool *pgxpool.Pool }
+
+func NewSlackRepo(pool *pgxpool.Pool) *SlackRepo { return &SlackRepo{pool: pool} }
+
+func (r *SlackRepo) SetSlackChannel(ctx context.Context, c domain.SlackChannel) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO slack_channels (org_id, team_name, webhook_url) VALUES ($1,$2,$3)
+		ON CONFLICT (org_id, team_name) DO UPDATE SET webhook_url=EXCLUDED.webhook_url`,
+		domain.OrgFromContext(ctx), c.TeamName, c.WebhookURL)
+	if isForeignKeyViolation(err) {
+		return domain.ErrNotFound
+	}
+	return err
+}
+
+func (r *SlackRepo) GetSlackChannel(ctx context.Context, teamName string) (domain.SlackChannel, error) {
+	c := domain.SlackChannel{TeamName: teamName}
+	err := r.pool.QueryRow(ctx,
+		`SELECT webhook_url FROM slack_channels WHERE org_id=$1 AND team_name=$2`,
+		domain.OrgFromContext(ctx), teamName,
+	).Scan(&c.WebhookURL)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.SlackChannel{}, domain.ErrNotFound
+		}
+		return domain.SlackChannel{}, err
+	}
+	return c, nil
+}
+
+func (r *SlackRepo) DeleteSlackChannel(ctx context.Context, teamName string) error {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM slack_channels WHERE org_id=$1 AND team_name=$2`,
+		domain.OrgFromContext(ctx), teamName)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SlackRepo) ListSlackChannels(ctx context.Context) ([]domain.SlackChannel, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT team_name, webhook_url FROM slack_channels WHERE org_id=$1 ORDER BY team_name`,
+		domain.OrgFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.SlackChannel
+	for rows.Next() {
+		var c domain.SlackChannel
+		if err := rows.Scan(&c.TeamName, &c.WebhookURL); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (r *SlackRepo) SetSlackUser(ctx context.Context, u domain.SlackUser) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO slack_users (org_id, user_id, slack_id) VALUES ($1,$2,$3)
+		ON CONFLICT (org_id, user_id) DO UPDATE SET slack_id=EXCLUDED.slack_id`,
+		domain.OrgFromContext(ctx), u.UserID, u.SlackID)
+	if isForeignKeyViolation(err) {
+		return domain.ErrNotFound
+	}
+	return err
+}
+
+func (r *SlackRepo) DeleteSlackUser(ctx context.Context, userID string) error {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM slack_users WHERE org_id=$1 AND user_id=$2`,
+		domain.OrgFromContext(ctx), userID)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SlackRepo) ListSlackUsers(ctx context.Context) ([]domain.SlackUser, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT user_id, slack_id FROM slack_users WHERE org_id=$1 ORDER BY user_id`,
+		domain.OrgFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.SlackUser
+	for rows.Next() {
+		var u domain.SlackUser
+		if err := rows.Scan(&u.UserID, &u.SlackID); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type SubscriptionRepo struct{ pool *pgxpool.Pool }
+
+func NewSubscriptionRepo(pool *pgxpool.Pool) *SubscriptionRepo { return &SubscriptionRepo{pool: pool} }
+
+const subscriptionColumns = `subscription_id, org_id, url, secret, event_types, created_at`
+
+func (r *SubscriptionRepo) CreateSubscription(ctx context.Context, s domain.Subscription) (domain.Subscription, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO subscriptions (subscription_id, org_id, url, secret, event_types)
+		VALUES ($1,$2,$3,$4,$5)
+		RETURNING `+subscriptionColumns,
+		s.ID, domain.OrgFromContext(ctx), s.URL, s.Secret, eventTypeStrings(s.EventTypes))
+	return scanSubscription(row)
+}
+
+func (r *SubscriptionRepo) GetSubscription(ctx context.Context, id string) (domain.Subscription, error) {
+	return scanSubscription(r.pool.QueryRow(ctx, `
+		SELECT `+subscriptionColumns+` FROM subscriptions
+		WHERE org_id=$1 AND subscription_id=$2`, domain.OrgFromContext(ctx), id))
+}
+
+func (r *SubscriptionRepo) ListSubscriptions(ctx context.Context) ([]domain.Subscription, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+subscriptionColumns+` FROM subscriptions
+		WHERE org_id=$1 ORDER BY created_at, subscription_id`, domain.OrgFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Subscription
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (r *SubscriptionRepo) DeleteSubscription(ctx context.Context, id string) error {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM subscriptions WHERE org_id=$1 AND subscription_id=$2`,
+		domain.OrgFromContext(ctx), id)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func scanSubscription(row pgx.Row) (domain.Subscription, error) {
+	var (
+		s     domain.Subscription
+		types []string
+	)
+	if err := row.Scan(&s.ID, &s.OrgID, &s.URL, &s.Secret, &types, &s.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Subscription{}, domain.ErrNotFound
+		}
+		return domain.Subscription{}, err
+	}
+	for _, t := range types {
+		s.EventTypes = append(s.EventTypes, domain.EventType(t))
+	}
+	return s, nil
+}
+
+func eventTypeStrings(types []domain.EventType) []string {
+	out := make([]string, len(types))
+	for i, t := range types {
+		out[i] = string(t)
+	}
+	return out
+}
+
+type EventDeliveryRepo struct{ pool *pgxpool.Pool }
+
+func NewEventDeliveryRepo(pool *pgxpool.Pool) *EventDeliveryRepo {
+	return &EventDeliveryRepo{pool: pool}
+}
+
+const deliveryColumns = `delivery_id, org_id, subscription_id, event_id, event_type, payload,
+	status, attempts, response_code, last_error, next_attempt_at, created_at, updated_at`
+
+func (r *EventDeliveryRepo) EnqueueEventDelivery(ctx context.Context, d domain.EventDelivery) (domain.EventDelivery, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO event_deliveries (org_id, subscription_id, event_id, event_type, payload, status, next_attempt_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7)
+		RETURNING `+deliveryColumns,
+		domain.OrgFromContext(ctx), d.SubscriptionID, d.EventID, d.EventType, d.Payload, d.Status, d.NextAttemptAt)
+	d, err := scanDelivery(row)
+	if err != nil && isForeignKeyViolation(err) {
+		return domain.EventDelivery{}, domain.ErrNotFound
+	}
+	return d, err
+}
+
+func (r *EventDeliveryRepo) ClaimEventDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.EventDelivery, error) {
+	rows, err := r.pool.Query(ctx, `
+		UPDATE event_deliveries SET next_attempt_at = $2
+		WHERE delivery_id IN (
+			SELECT delivery_id FROM event_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY delivery_id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED)
+		RETURNING `+deliveryColumns, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	out, err := collectDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(out, func(a, b domain.EventDelivery) int { return cmp.Compare(a.ID, b.ID) })
+	return out, nil
+}
+
+func (r *EventDeliveryRepo) GetEventDelivery(ctx context.Context, id int64) (domain.EventDelivery, error) {
+	return scanDelivery(r.pool.QueryRow(ctx, `
+		SELECT `+deliveryColumns+` FROM event_deliveries
+		WHERE org_id=$1 AND delivery_id=$2`, domain.OrgFromContext(ctx), id))
+}
+
+func (r *EventDeliveryRepo) SaveEventDelivery(ctx context.Context, d domain.EventDelivery) error {
+	ct, err := r.pool.Exec(ctx, `
+		UPDATE event_deliveries
+		SET status=$2, attempts=$3, response_code=$4, last_error=$5, next_attempt_at=$6, updated_at=now()
+		WHERE delivery_id=$1`, d.ID, d.Status, d.Attempts, d.ResponseCode, d.LastError, d.NextAttemptAt)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *EventDeliveryRepo) ListEventDeliveries(ctx context.Context, subscriptionID string, limit int) ([]domain.EventDelivery, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+deliveryColumns+` FROM event_deliveries
+		WHERE org_id=$1 AND subscription_id=$2
+		ORDER BY delivery_id DESC
+		LIMIT $3`, domain.OrgFromContext(ctx), subscriptionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return collectDeliveries(rows)
+}
+
+func collectDeliveries(rows pgx.Rows) ([]domain.EventDelivery, error) {
+	defer rows.Close()
+
+	var out []domain.EventDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func scanDelivery(row pgx.Row) (domain.EventDelivery, error) {
+	var d domain.EventDelivery
+	err := row.Scan(&d.ID, &d.OrgID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload,
+		&d.Status, &d.Attempts, &d.ResponseCode, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.EventDelivery{}, domain.ErrNotFound
+		}
+		return domain.EventDelivery{}, err
+	}
+	return d, nil
+}
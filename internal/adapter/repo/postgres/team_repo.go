@@ -5,8 +5,8 @@ import (
 	"errors"
 	"log"
 
-	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/beachrockhotel/pr-reviewer/internal/domain"
@@ -21,9 +21,11 @@ func NewTeamRepo(pool *pgxpool.Pool) *TeamRepo {
 }
 
 func (r *TeamRepo) CreateTeam(ctx context.Context, teamName string) error {
+	org := domain.OrgFromContext(ctx)
+
 	var exists bool
 	if err := r.pool.QueryRow(ctx,
-		`SELECT EXISTS(SELECT 1 FROM teams WHERE team_name=$1)`, teamName,
+		`SELECT EXISTS(SELECT 1 FROM teams WHERE org_id=$1 AND team_name=$2)`, org, teamName,
 	).Scan(&exists); err != nil {
 		return err
 	}
@@ -31,29 +33,48 @@ func (r *TeamRepo) CreateTeam(ctx context.Context, teamName string) error {
 		return domain.ErrTeamExists
 	}
 
-	_, err := r.pool.Exec(ctx, `INSERT INTO teams (team_name) VALUES ($1)`, teamName)
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("postgres: rollback failed in CreateTeam: %v", err)
+		}
+	}()
+
+	_, err = tx.Exec(ctx, `INSERT INTO teams (org_id, team_name) VALUES ($1,$2)`, org, teamName)
 	if isUniqueViolation(err) {
 		return domain.ErrTeamExists
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	if err := insertAudit(ctx, tx, domain.NewAuditEntry(ctx, domain.AuditTeamCreated, teamName, nil, domain.AuditTeam(domain.Team{TeamName: teamName}))); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 func (r *TeamRepo) GetTeamWithMembers(ctx context.Context, teamName string) (domain.Team, []domain.User, error) {
-	var exists bool
-	if err := r.pool.QueryRow(ctx,
-		`SELECT EXISTS(SELECT 1 FROM teams WHERE team_name=$1)`, teamName,
-	).Scan(&exists); err != nil {
-		return domain.Team{}, nil, err
-	}
-	if !exists {
+	org := domain.OrgFromContext(ctx)
+
+	t := domain.Team{TeamName: teamName}
+	err := r.pool.QueryRow(ctx,
+		`SELECT four_eyes FROM teams WHERE org_id=$1 AND team_name=$2`, org, teamName,
+	).Scan(&t.FourEyes)
+	if errors.Is(err, pgx.ErrNoRows) {
 		return domain.Team{}, nil, domain.ErrNotFound
 	}
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
 
 	rows, err := r.pool.Query(ctx, `
-		SELECT user_id, username, is_active
+		SELECT `+userColumns+`
 		FROM users
-		WHERE team_name = $1
-		ORDER BY user_id`, teamName)
+		WHERE org_id = $1 AND team_name = $2
+		ORDER BY user_id`, org, teamName)
 	if err != nil {
 		return domain.Team{}, nil, err
 	}
@@ -61,52 +82,278 @@ func (r *TeamRepo) GetTeamWithMembers(ctx context.Context, teamName string) (dom
 
 	var members []domain.User
 	for rows.Next() {
-		var u domain.User
-		if err := rows.Scan(&u.UserID, &u.Username, &u.IsActive); err != nil {
+		u, err := scanUser(rows)
+		if err != nil {
 			return domain.Team{}, nil, err
 		}
-		u.TeamName = teamName
 		members = append(members, u)
 	}
 	if err := rows.Err(); err != nil {
 		return domain.Team{}, nil, err
 	}
 
-	return domain.Team{TeamName: teamName, Members: nil}, members, nil
+	return t, members, nil
+}
+
+func (r *TeamRepo) SetFourEyes(ctx context.Context, teamName string, on bool) (domain.Team, error) {
+	org := domain.OrgFromContext(ctx)
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.Team{}, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("postgres: rollback failed in SetFourEyes: %v", err)
+		}
+	}()
+
+	before := domain.Team{TeamName: teamName}
+	err = tx.QueryRow(ctx,
+		`SELECT four_eyes FROM teams WHERE org_id=$1 AND team_name=$2 FOR UPDATE`, org, teamName,
+	).Scan(&before.FourEyes)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Team{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Team{}, err
+	}
+	if before.FourEyes == on {
+		return before, nil
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE teams SET four_eyes=$3 WHERE org_id=$1 AND team_name=$2`, org, teamName, on,
+	); err != nil {
+		return domain.Team{}, err
+	}
+	after := before
+	after.FourEyes = on
+	if err := insertAudit(ctx, tx, domain.NewAuditEntry(ctx, domain.AuditTeamPolicyChanged, teamName,
+		domain.AuditTeam(before), domain.AuditTeam(after))); err != nil {
+		return domain.Team{}, err
+	}
+	return after, tx.Commit(ctx)
 }
 
+// UpsertUsersToTeam locks each existing user while it is replaced so that
+// the audit log sees the state the upsert overwrote.
 func (r *TeamRepo) UpsertUsersToTeam(ctx context.Context, teamName string, users []domain.User) error {
 	if len(users) == 0 {
 		return nil
 	}
 
-	b := &pgx.Batch{}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("postgres: rollback failed in UpsertUsersToTeam: %v", err)
+		}
+	}()
+
 	for _, u := range users {
-		b.Queue(`
-			INSERT INTO users (user_id, username, team_name, is_active)
-			VALUES ($1,$2,$3,$4)
-			ON CONFLICT (user_id) DO UPDATE
-			  SET username=EXCLUDED.username,
-			      team_name=EXCLUDED.team_name,
-			      is_active=EXCLUDED.is_active,
-			      updated_at=now()
-		`, u.UserID, u.Username, teamName, u.IsActive)
-	}
-
-	br := r.pool.SendBatch(ctx, b)
+		before, err := lockUser(ctx, tx, u.UserID)
+		if err != nil {
+			return err
+		}
+		if err := upsertUser(ctx, tx, teamName, before, u); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// lockUser locks the stored user for the rest of tx; it is nil when there
+// is none.
+func lockUser(ctx context.Context, tx pgx.Tx, userID string) (*domain.User, error) {
+	u, err := scanUser(tx.QueryRow(ctx, `
+		SELECT `+userColumns+`
+		FROM users WHERE org_id=$1 AND user_id=$2
+		FOR UPDATE`, domain.OrgFromContext(ctx), userID))
+	switch {
+	case err == nil:
+		return &u, nil
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// upsertUser stores u as a member of teamName and audits the change from
+// before, the locked user or nil.
+func upsertUser(ctx context.Context, tx pgx.Tx, teamName string, before *domain.User, u domain.User) error {
+	after, err := scanUser(tx.QueryRow(ctx, `
+		INSERT INTO users (org_id, user_id, username, team_name, is_active, role, email, timezone, manager_id)
+		VALUES ($1,$2,$3,$4,$5,COALESCE(NULLIF($6,''),'member'),$7,$8,$9)
+		ON CONFLICT (org_id, user_id) DO UPDATE
+		  SET username=EXCLUDED.username,
+		      team_name=EXCLUDED.team_name,
+		      is_active=EXCLUDED.is_active,
+		      role=CASE WHEN $6 = '' THEN users.role ELSE EXCLUDED.role END,
+		      email=CASE WHEN $7 = '' THEN users.email ELSE EXCLUDED.email END,
+		      timezone=CASE WHEN $8 = '' THEN users.timezone ELSE EXCLUDED.timezone END,
+		      manager_id=CASE WHEN $9 = '' THEN users.manager_id ELSE EXCLUDED.manager_id END,
+		      updated_at=now()
+		RETURNING `+userColumns,
+		domain.OrgFromContext(ctx), u.UserID, u.Username, teamName, u.IsActive, string(u.Role), u.Email, u.Timezone, u.ManagerID))
+	if err != nil {
+		return err
+	}
+	return insertAudit(ctx, tx, domain.NewAuditEntry(ctx, domain.AuditUserUpserted, u.UserID,
+		domain.AuditUser(before), domain.AuditUser(&after)))
+}
+
+func (r *TeamRepo) ListTeams(ctx context.Context) ([]domain.Team, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT team_name, four_eyes FROM teams WHERE org_id=$1 ORDER BY team_name`, domain.OrgFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Team
+	for rows.Next() {
+		var t domain.Team
+		if err := rows.Scan(&t.TeamName, &t.FourEyes); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (r *TeamRepo) ImportTeams(ctx context.Context, teams []domain.Team, dryRun bool) (domain.ImportReport, error) {
+	org := domain.OrgFromContext(ctx)
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("postgres: rollback failed in ImportTeams: %v", err)
+		}
+	}()
+
+	report := domain.ImportReport{DryRun: dryRun}
+	for _, t := range teams {
+		ct, err := tx.Exec(ctx,
+			`INSERT INTO teams (org_id, team_name) VALUES ($1,$2) ON CONFLICT DO NOTHING`, org, t.TeamName)
+		if err != nil {
+			return domain.ImportReport{}, err
+		}
+		action := domain.ImportUnchanged
+		if ct.RowsAffected() == 1 {
+			action = domain.ImportCreate
+			if err := insertAudit(ctx, tx, domain.NewAuditEntry(ctx, domain.AuditTeamCreated, t.TeamName,
+				nil, domain.AuditTeam(domain.Team{TeamName: t.TeamName}))); err != nil {
+				return domain.ImportReport{}, err
+			}
+		}
+		report.Changes = append(report.Changes, domain.ImportChange{
+			Kind: domain.ImportKindTeam, ID: t.TeamName, Action: action, TeamName: t.TeamName,
+		})
+
+		for _, u := range t.Members {
+			before, err := lockUser(ctx, tx, u.UserID)
+			if err != nil {
+				return domain.ImportReport{}, err
+			}
+			c := domain.ImportChange{
+				Kind: domain.ImportKindUser, ID: u.UserID, TeamName: t.TeamName,
+				Action: domain.PlanUserImport(before, u, t.TeamName),
+			}
+			if c.Action == domain.ImportMove {
+				c.FromTeam = before.TeamName
+			}
+			report.Changes = append(report.Changes, c)
+			if c.Action == domain.ImportUnchanged {
+				continue
+			}
+			if err := upsertUser(ctx, tx, t.TeamName, before, u); err != nil {
+				return domain.ImportReport{}, err
+			}
+		}
+	}
+
+	if dryRun {
+		return report, nil
+	}
+	return report, tx.Commit(ctx)
+}
+
+// DeleteTeam locks the team first so that no member joins it meanwhile.
+func (r *TeamRepo) DeleteTeam(ctx context.Context, teamName, moveTo string) error {
+	org := domain.OrgFromContext(ctx)
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
 	defer func() {
-		if err := br.Close(); err != nil {
-			log.Printf("postgres: batch close failed in UpsertUsersToTeam: %v", err)
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("postgres: rollback failed in DeleteTeam: %v", err)
 		}
 	}()
 
-	for range users {
-		if _, err := br.Exec(); err != nil {
+	t := domain.Team{TeamName: teamName}
+	err = tx.QueryRow(ctx,
+		`SELECT four_eyes FROM teams WHERE org_id=$1 AND team_name=$2 FOR UPDATE`, org, teamName,
+	).Scan(&t.FourEyes)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	var exists bool
+	if err := tx.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM teams WHERE org_id=$1 AND team_name=$2)`, org, moveTo,
+	).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrNotFound
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT `+userColumns+`
+		FROM users WHERE org_id=$1 AND team_name=$2
+		ORDER BY user_id
+		FOR UPDATE`, org, teamName)
+	if err != nil {
+		return err
+	}
+	var members []domain.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		members = append(members, u)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, u := range members {
+		if err := upsertUser(ctx, tx, moveTo, &u, u); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	// ON DELETE CASCADE takes the Slack channel and GitLab projects along.
+	if _, err := tx.Exec(ctx, `DELETE FROM teams WHERE org_id=$1 AND team_name=$2`, org, teamName); err != nil {
+		return err
+	}
+	if err := insertAudit(ctx, tx, domain.NewAuditEntry(ctx, domain.AuditTeamDeleted, teamName, domain.AuditTeam(t), nil)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 func isUniqueViolation(err error) bool {
@@ -116,3 +363,8 @@ func isUniqueViolation(err error) bool {
 	}
 	return false
 }
+
+func isForeignKeyViolation(err error) bool {
+	var pgerr *pgconn.PgError
+	return errors.As(err, &pgerr) && pgerr.Code == "23503"
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type TokenRepo struct{ pool *pgxpool.Pool }
+
+func NewTokenRepo(pool *pgxpool.Pool) *TokenRepo { return &TokenRepo{pool: pool} }
+
+const tokenColumns = `token_id, org_id, name, scopes, cross_org, created_at, revoked_at`
+
+func (r *TokenRepo) CreateToken(ctx context.Context, t domain.APIToken, hash string) (domain.APIToken, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO api_tokens (token_id, org_id, name, token_hash, scopes, cross_org)
+		VALUES ($1,$2,$3,$4,$5,$6)
+		RETURNING `+tokenColumns, t.ID, t.OrgID, t.Name, hash, t.Scopes, t.CrossOrg)
+	return scanToken(row)
+}
+
+func (r *TokenRepo) GetTokenByHash(ctx context.Context, hash string) (domain.APIToken, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+tokenColumns+` FROM api_tokens WHERE token_hash=$1`, hash)
+	return scanToken(row)
+}
+
+func (r *TokenRepo) RevokeToken(ctx context.Context, id string) error {
+	ct, err := r.pool.Exec(ctx, `
+		UPDATE api_tokens SET revoked_at = COALESCE(revoked_at, now())
+		WHERE token_id=$1`, id)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *TokenRepo) ListTokens(ctx context.Context) ([]domain.APIToken, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+tokenColumns+` FROM api_tokens ORDER BY created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.APIToken
+	for rows.Next() {
+		t, err := scanToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func scanToken(row pgx.Row) (domain.APIToken, error) {
+	var t domain.APIToken
+	if err := row.Scan(&t.ID, &t.OrgID, &t.Name, &t.Scopes, &t.CrossOrg, &t.CreatedAt, &t.RevokedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.APIToken{}, domain.ErrNotFound
+		}
+		return domain.APIToken{}, err
+	}
+	return t, nil
+}
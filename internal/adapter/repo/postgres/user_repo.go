@@ -3,6 +3,8 @@ package postgres
 import (
 	"context"
 	"errors"
+	"log"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -15,10 +17,15 @@ type UserRepo struct{ pool *pgxpool.Pool }
 func NewUserRepo(pool *pgxpool.Pool) *UserRepo { return &UserRepo{pool: pool} }
 
 func (r *UserRepo) GetByID(ctx context.Context, id string) (domain.User, error) {
-	var u domain.User
-	err := r.pool.QueryRow(ctx, `
-		SELECT user_id, username, team_name, is_active
-		FROM users WHERE user_id=$1`, id).Scan(&u.UserID, &u.Username, &u.TeamName, &u.IsActive)
+	return getUser(ctx, r.pool, id)
+}
+
+const userColumns = `user_id, username, team_name, is_active, role, email, timezone, away_until, manager_id`
+
+func getUser(ctx context.Context, q querier, id string) (domain.User, error) {
+	u, err := scanUser(q.QueryRow(ctx, `
+		SELECT `+userColumns+`
+		FROM users WHERE org_id=$1 AND user_id=$2`, domain.OrgFromContext(ctx), id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.User{}, domain.ErrNotFound
@@ -28,26 +35,76 @@ func (r *UserRepo) GetByID(ctx context.Context, id string) (domain.User, error)
 	return u, nil
 }
 
-func (r *UserRepo) SetActive(ctx context.Context, id string, active bool) (domain.User, error) {
-	ct, err := r.pool.Exec(ctx, `UPDATE users SET is_active=$2, updated_at=now() WHERE user_id=$1`, id, active)
+func scanUser(row pgx.Row) (domain.User, error) {
+	var u domain.User
+	err := row.Scan(&u.UserID, &u.Username, &u.TeamName, &u.IsActive, &u.Role, &u.Email, &u.Timezone, &u.AwayUntil, &u.ManagerID)
+	return u, err
+}
+
+func (r *UserRepo) SetActive(ctx context.Context, id string, active bool, e *domain.Event) (domain.User, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.User{}, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("postgres: rollback failed in SetActive: %v", err)
+		}
+	}()
+
+	ct, err := tx.Exec(ctx, `
+		UPDATE users SET is_active=$3, updated_at=now()
+		WHERE org_id=$1 AND user_id=$2 AND is_active <> $3`, domain.OrgFromContext(ctx), id, active)
+	if err != nil {
+		return domain.User{}, err
+	}
+	u, err := getUser(ctx, tx, id)
 	if err != nil {
 		return domain.User{}, err
 	}
-	if ct.RowsAffected() == 0 {
-		return domain.User{}, domain.ErrNotFound
+	if ct.RowsAffected() > 0 {
+		before := u
+		before.IsActive = !active
+		if err := insertAudit(ctx, tx, domain.NewAuditEntry(ctx, domain.AuditUserActivityChanged, id,
+			domain.AuditUser(&before), domain.AuditUser(&u))); err != nil {
+			return domain.User{}, err
+		}
+	}
+	if e != nil && ct.RowsAffected() > 0 {
+		e.Data.User = domain.NewEventUser(u)
+		if err := insertOutbox(ctx, tx, e); err != nil {
+			return domain.User{}, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return domain.User{}, err
 	}
-	return r.GetByID(ctx, id)
+	return u, nil
+}
+
+func (r *UserRepo) SetAway(ctx context.Context, id string, until *time.Time) (domain.User, error) {
+	u, err := scanUser(r.pool.QueryRow(ctx, `
+		UPDATE users SET away_until=$3, updated_at=now()
+		WHERE org_id=$1 AND user_id=$2
+		RETURNING `+userColumns, domain.OrgFromContext(ctx), id, until))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.User{}, domain.ErrNotFound
+		}
+		return domain.User{}, err
+	}
+	return u, nil
 }
 
 func (r *UserRepo) ListActiveInTeamExcept(ctx context.Context, teamName string, excludeIDs []string, limit int) ([]domain.User, error) {
 	rows, err := r.pool.Query(ctx, `
-		SELECT user_id, username, team_name, is_active
+		SELECT `+userColumns+`
 		FROM users
-		WHERE team_name=$1 AND is_active=TRUE
-		  AND NOT (user_id = ANY($2))
+		WHERE org_id=$1 AND team_name=$2 AND is_active=TRUE
+		  AND NOT (user_id = ANY($3))
 		ORDER BY random()
-		LIMIT $3
-	`, teamName, excludeIDs, limit)
+		LIMIT $4
+	`, domain.OrgFromContext(ctx), teamName, excludeIDs, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -55,8 +112,8 @@ func (r *UserRepo) ListActiveInTeamExcept(ctx context.Context, teamName string,
 
 	var out []domain.User
 	for rows.Next() {
-		var u domain.User
-		if err := rows.Scan(&u.UserID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
+		u, err := scanUser(rows)
+		if err != nil {
 			return nil, err
 		}
 		out = append(out, u)
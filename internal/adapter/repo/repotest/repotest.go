@@ -0,0 +1,2249 @@
+// Package repotest is a contract test suite for the storage interfaces in
+// usecase. Every backend runs the same suite from its own _test.go file so a
+// new implementation can prove it behaves like the existing ones.
+package repotest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+// Repos is one backend instance under test.
+type Repos struct {
+	Teams      usecase.TeamRepo
+	Users      usecase.UserRepo
+	PRs        usecase.PRRepo
+	Orgs       usecase.OrgRepo
+	Deliveries usecase.DeliveryRepo
+	GitLab     usecase.GitLabProjectRepo
+	Syncs      usecase.ReviewerSyncRepo
+	Subs       usecase.SubscriptionRepo
+	Events     usecase.EventDeliveryRepo
+	Outbox     usecase.OutboxRepo
+	Slack      usecase.SlackRepo
+	Email      usecase.EmailRepo
+	Digest     usecase.DigestRepo
+	Audit      usecase.AuditRepo
+
+	Idempotency usecase.IdempotencyRepo
+}
+
+// Factory returns repositories over an empty store. It is called once per
+// subtest; cleanup should be registered on t.
+type Factory func(t *testing.T) Repos
+
+// Run executes the whole contract against the backend produced by newRepos.
+func Run(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	t.Run("TeamRepo", func(t *testing.T) { RunTeamRepo(t, newRepos) })
+	t.Run("UserRepo", func(t *testing.T) { RunUserRepo(t, newRepos) })
+	t.Run("PRRepo", func(t *testing.T) { RunPRRepo(t, newRepos) })
+	t.Run("OrgRepo", func(t *testing.T) { RunOrgRepo(t, newRepos) })
+	t.Run("TenantIsolation", func(t *testing.T) { RunTenantIsolation(t, newRepos) })
+	t.Run("DeliveryRepo", func(t *testing.T) { RunDeliveryRepo(t, newRepos) })
+	t.Run("GitLabProjectRepo", func(t *testing.T) { RunGitLabProjectRepo(t, newRepos) })
+	t.Run("ReviewerSyncRepo", func(t *testing.T) { RunReviewerSyncRepo(t, newRepos) })
+	t.Run("SubscriptionRepo", func(t *testing.T) { RunSubscriptionRepo(t, newRepos) })
+	t.Run("OutboxRepo", func(t *testing.T) { RunOutboxRepo(t, newRepos) })
+	t.Run("SlackRepo", func(t *testing.T) { RunSlackRepo(t, newRepos) })
+	t.Run("EmailRepo", func(t *testing.T) { RunEmailRepo(t, newRepos) })
+	t.Run("DigestRepo", func(t *testing.T) { RunDigestRepo(t, newRepos) })
+	t.Run("AuditRepo", func(t *testing.T) { RunAuditRepo(t, newRepos) })
+	t.Run("IdempotencyRepo", func(t *testing.T) { RunIdempotencyRepo(t, newRepos) })
+}
+
+func RunTeamRepo(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		mustNoErr(t, r.Teams.CreateTeam(ctx, "backend"))
+		if err := r.Teams.CreateTeam(ctx, "backend"); !errors.Is(err, domain.ErrTeamExists) {
+			t.Fatalf("second CreateTeam: got %v, want %v", err, domain.ErrTeamExists)
+		}
+	})
+
+	t.Run("GetMissing", func(t *testing.T) {
+		r := newRepos(t)
+
+		_, _, err := r.Teams.GetTeamWithMembers(context.Background(), "nope")
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("GetEmpty", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		mustNoErr(t, r.Teams.CreateTeam(ctx, "backend"))
+		team, members, err := r.Teams.GetTeamWithMembers(ctx, "backend")
+		mustNoErr(t, err)
+		if team.TeamName != "backend" {
+			t.Fatalf("team name: got %q", team.TeamName)
+		}
+		if len(members) != 0 {
+			t.Fatalf("members: got %v, want none", members)
+		}
+	})
+
+	t.Run("MembersOrderedByID", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		seedTeam(t, r, "backend", user("u3", true), user("u1", true), user("u2", false))
+
+		_, members, err := r.Teams.GetTeamWithMembers(ctx, "backend")
+		mustNoErr(t, err)
+		if got := userIDs(members); !slices.Equal(got, []string{"u1", "u2", "u3"}) {
+			t.Fatalf("members: got %v", got)
+		}
+		for _, m := range members {
+			if m.TeamName != "backend" {
+				t.Fatalf("member %s: team %q", m.UserID, m.TeamName)
+			}
+		}
+		if members[1].IsActive {
+			t.Fatalf("u2 should be inactive")
+		}
+	})
+
+	t.Run("UpsertMovesAndUpdates", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true))
+		seedTeam(t, r, "frontend", domain.User{UserID: "u2", Username: "Bobby", IsActive: false})
+
+		_, backend, err := r.Teams.GetTeamWithMembers(ctx, "backend")
+		mustNoErr(t, err)
+		if got := userIDs(backend); !slices.Equal(got, []string{"u1"}) {
+			t.Fatalf("backend members: got %v", got)
+		}
+
+		u, err := r.Users.GetByID(ctx, "u2")
+		mustNoErr(t, err)
+		want := domain.User{UserID: "u2", Username: "Bobby", TeamName: "frontend", IsActive: false, Role: domain.RoleMember}
+		if u != want {
+			t.Fatalf("u2: got %+v, want %+v", u, want)
+		}
+	})
+
+	t.Run("UpsertRoles", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		lead := user("u1", true)
+		lead.Role = domain.RoleLead
+		seedTeam(t, r, "backend", lead, user("u2", true))
+
+		_, members, err := r.Teams.GetTeamWithMembers(ctx, "backend")
+		mustNoErr(t, err)
+		if members[0].Role != domain.RoleLead || members[1].Role != domain.RoleMember {
+			t.Fatalf("roles: got %+v", members)
+		}
+
+		// An empty role keeps what is stored, an explicit one replaces it.
+		admin := user("u2", true)
+		admin.Role = domain.RoleAdmin
+		mustNoErr(t, r.Teams.UpsertUsersToTeam(ctx, "backend", []domain.User{user("u1", false), admin}))
+
+		u1, err := r.Users.GetByID(ctx, "u1")
+		mustNoErr(t, err)
+		if u1.Role != domain.RoleLead || u1.IsActive {
+			t.Fatalf("u1: got %+v", u1)
+		}
+		u2, err := r.Users.GetByID(ctx, "u2")
+		mustNoErr(t, err)
+		if u2.Role != domain.RoleAdmin {
+			t.Fatalf("u2: got %+v", u2)
+		}
+	})
+
+	t.Run("UpsertEmails", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		u1 := user("u1", true)
+		u1.Email = "u1@example.com"
+		seedTeam(t, r, "backend", u1, user("u2", true))
+
+		// An empty email keeps what is stored.
+		mustNoErr(t, r.Teams.UpsertUsersToTeam(ctx, "backend", []domain.User{user("u1", false)}))
+		got, err := r.Users.GetByID(ctx, "u1")
+		mustNoErr(t, err)
+		if got.Email != "u1@example.com" {
+			t.Fatalf("u1: got %+v", got)
+		}
+		u1.Email = "new@example.com"
+		mustNoErr(t, r.Teams.UpsertUsersToTeam(ctx, "backend", []domain.User{u1}))
+		_, members, err := r.Teams.GetTeamWithMembers(ctx, "backend")
+		mustNoErr(t, err)
+		if members[0].Email != "new@example.com" || members[1].Email != "" {
+			t.Fatalf("members: got %+v", members)
+		}
+	})
+
+	t.Run("UpsertTimezoneKeepsAbsence", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		u1 := user("u1", true)
+		u1.Timezone = "Europe/Berlin"
+		seedTeam(t, r, "backend", u1)
+		until := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+		_, err := r.Users.SetAway(ctx, "u1", &until)
+		mustNoErr(t, err)
+
+		// An empty timezone keeps what is stored and upserts leave absence alone.
+		mustNoErr(t, r.Teams.UpsertUsersToTeam(ctx, "backend", []domain.User{user("u1", true)}))
+		_, members, err := r.Teams.GetTeamWithMembers(ctx, "backend")
+		mustNoErr(t, err)
+		if got := members[0]; got.Timezone != "Europe/Berlin" || got.AwayUntil == nil || !got.AwayUntil.Equal(until) {
+			t.Fatalf("u1: got %+v", got)
+		}
+		u1.Timezone = "Asia/Tokyo"
+		mustNoErr(t, r.Teams.UpsertUsersToTeam(ctx, "backend", []domain.User{u1}))
+		got, err := r.Users.GetByID(ctx, "u1")
+		mustNoErr(t, err)
+		if got.Timezone != "Asia/Tokyo" {
+			t.Fatalf("u1: got %+v", got)
+		}
+	})
+
+	t.Run("UpsertManager", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		u2 := user("u2", true)
+		u2.ManagerID = "u1"
+		seedTeam(t, r, "backend", user("u1", true), u2)
+
+		// An empty manager keeps what is stored.
+		mustNoErr(t, r.Teams.UpsertUsersToTeam(ctx, "backend", []domain.User{user("u2", true)}))
+		got, err := r.Users.GetByID(ctx, "u2")
+		mustNoErr(t, err)
+		if got.ManagerID != "u1" {
+			t.Fatalf("u2: got %+v", got)
+		}
+		u2.ManagerID = "u3"
+		mustNoErr(t, r.Teams.UpsertUsersToTeam(ctx, "backend", []domain.User{u2}))
+		_, members, err := r.Teams.GetTeamWithMembers(ctx, "backend")
+		mustNoErr(t, err)
+		if members[0].ManagerID != "" || members[1].ManagerID != "u3" {
+			t.Fatalf("members: got %+v", members)
+		}
+	})
+
+	t.Run("UpsertNothing", func(t *testing.T) {
+		r := newRepos(t)
+
+		mustNoErr(t, r.Teams.UpsertUsersToTeam(context.Background(), "backend", nil))
+	})
+
+	t.Run("FourEyes", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seedTeam(t, r, "backend", user("u1", true))
+
+		if _, err := r.Teams.SetFourEyes(ctx, "nope", true); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("missing team: got %v, want %v", err, domain.ErrNotFound)
+		}
+		team, _, err := r.Teams.GetTeamWithMembers(ctx, "backend")
+		mustNoErr(t, err)
+		if team.FourEyes {
+			t.Fatalf("new team: got %+v", team)
+		}
+		for _, on := range []bool{true, true, false} {
+			got, err := r.Teams.SetFourEyes(ctx, "backend", on)
+			mustNoErr(t, err)
+			if got.TeamName != "backend" || got.FourEyes != on {
+				t.Fatalf("set %v: got %+v", on, got)
+			}
+			team, _, err := r.Teams.GetTeamWithMembers(ctx, "backend")
+			mustNoErr(t, err)
+			if team.FourEyes != on {
+				t.Fatalf("get after %v: got %+v", on, team)
+			}
+		}
+	})
+
+	t.Run("ListTeams", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		got, err := r.Teams.ListTeams(ctx)
+		mustNoErr(t, err)
+		if len(got) != 0 {
+			t.Fatalf("empty: got %+v", got)
+		}
+		seedTeam(t, r, "payments")
+		seedTeam(t, r, "backend", user("u1", true))
+		_, err = r.Teams.SetFourEyes(ctx, "payments", true)
+		mustNoErr(t, err)
+
+		got, err = r.Teams.ListTeams(ctx)
+		mustNoErr(t, err)
+		if len(got) != 2 || got[0].TeamName != "backend" || got[0].FourEyes ||
+			got[1].TeamName != "payments" || !got[1].FourEyes {
+			t.Fatalf("teams: got %+v", got)
+		}
+	})
+
+	t.Run("ImportTeams", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		u1 := user("u1", true)
+		u1.Email = "u1@example.com"
+		seedTeam(t, r, "backend", u1, user("u2", true), user("u3", true))
+
+		renamed := user("u2", false)
+		renamed.Username = "bob"
+		snapshot := []domain.Team{
+			{TeamName: "backend", Members: []domain.User{user("u1", true), renamed}},
+			{TeamName: "frontend", Members: []domain.User{user("u3", true), user("u4", true)}},
+		}
+		want := []domain.ImportChange{
+			{Kind: domain.ImportKindTeam, ID: "backend", Action: domain.ImportUnchanged, TeamName: "backend"},
+			{Kind: domain.ImportKindUser, ID: "u1", Action: domain.ImportUnchanged, TeamName: "backend"},
+			{Kind: domain.ImportKindUser, ID: "u2", Action: domain.ImportUpdate, TeamName: "backend"},
+			{Kind: domain.ImportKindTeam, ID: "frontend", Action: domain.ImportCreate, TeamName: "frontend"},
+			{Kind: domain.ImportKindUser, ID: "u3", Action: domain.ImportMove, FromTeam: "backend", TeamName: "frontend"},
+			{Kind: domain.ImportKindUser, ID: "u4", Action: domain.ImportCreate, TeamName: "frontend"},
+		}
+
+		dry, err := r.Teams.ImportTeams(ctx, snapshot, true)
+		mustNoErr(t, err)
+		if !dry.DryRun || !slices.Equal(dry.Changes, want) {
+			t.Fatalf("dry run: got %+v", dry)
+		}
+		if _, _, err := r.Teams.GetTeamWithMembers(ctx, "frontend"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("dry run created a team: %v", err)
+		}
+		if got, err := r.Users.GetByID(ctx, "u3"); err != nil || got.TeamName != "backend" {
+			t.Fatalf("dry run moved u3: got %+v, %v", got, err)
+		}
+
+		report, err := r.Teams.ImportTeams(ctx, snapshot, false)
+		mustNoErr(t, err)
+		if report.DryRun || !slices.Equal(report.Changes, want) {
+			t.Fatalf("import: got %+v", report)
+		}
+		_, backend, err := r.Teams.GetTeamWithMembers(ctx, "backend")
+		mustNoErr(t, err)
+		if !slices.Equal(userIDs(backend), []string{"u1", "u2"}) || backend[0].Email != "u1@example.com" ||
+			backend[1].Username != "bob" || backend[1].IsActive {
+			t.Fatalf("backend: got %+v", backend)
+		}
+		_, frontend, err := r.Teams.GetTeamWithMembers(ctx, "frontend")
+		mustNoErr(t, err)
+		if !slices.Equal(userIDs(frontend), []string{"u3", "u4"}) {
+			t.Fatalf("frontend: got %+v", frontend)
+		}
+
+		again, err := r.Teams.ImportTeams(ctx, snapshot, false)
+		mustNoErr(t, err)
+		if n := again.Count(domain.ImportKindUser, domain.ImportUnchanged); n != 4 {
+			t.Fatalf("second import: got %+v", again)
+		}
+	})
+
+	t.Run("DeleteTeam", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		lead := user("u1", true)
+		lead.Role = domain.RoleLead
+		seedTeam(t, r, "backend", lead, user("u2", false))
+		seedTeam(t, r, "unassigned")
+		mustNoErr(t, r.Slack.SetSlackChannel(ctx, domain.SlackChannel{TeamName: "backend", WebhookURL: "https://hooks.example/b"}))
+		mustNoErr(t, r.GitLab.SetGitLabProject(ctx, domain.GitLabProject{ProjectID: 7, OrgID: domain.DefaultOrg, TeamName: "backend"}))
+
+		if err := r.Teams.DeleteTeam(ctx, "backend", "nope"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("missing target: got %v, want %v", err, domain.ErrNotFound)
+		}
+		if err := r.Teams.DeleteTeam(ctx, "nope", "unassigned"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("missing team: got %v, want %v", err, domain.ErrNotFound)
+		}
+
+		mustNoErr(t, r.Teams.DeleteTeam(ctx, "backend", "unassigned"))
+		if _, _, err := r.Teams.GetTeamWithMembers(ctx, "backend"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("team still there: %v", err)
+		}
+		_, moved, err := r.Teams.GetTeamWithMembers(ctx, "unassigned")
+		mustNoErr(t, err)
+		if !slices.Equal(userIDs(moved), []string{"u1", "u2"}) || moved[0].Role != domain.RoleLead || moved[1].IsActive {
+			t.Fatalf("moved: got %+v", moved)
+		}
+		if _, err := r.Slack.GetSlackChannel(ctx, "backend"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("slack channel kept: %v", err)
+		}
+		if _, err := r.GitLab.GetGitLabProject(ctx, 7); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("gitlab project kept: %v", err)
+		}
+		entries, err := r.Audit.ListAudit(ctx, domain.AuditFilter{EntityType: domain.EntityTeam, EntityID: "backend", Limit: 1})
+		mustNoErr(t, err)
+		if len(entries) != 1 || entries[0].Action != domain.AuditTeamDeleted || entries[0].After != nil {
+			t.Fatalf("audit: got %+v", entries)
+		}
+	})
+}
+
+func RunUserRepo(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	t.Run("GetMissing", func(t *testing.T) {
+		r := newRepos(t)
+
+		if _, err := r.Users.GetByID(context.Background(), "ghost"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("SetActive", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		seedTeam(t, r, "backend", user("u1", true))
+
+		u, err := r.Users.SetActive(ctx, "u1", false, nil)
+		mustNoErr(t, err)
+		if u.IsActive || u.TeamName != "backend" {
+			t.Fatalf("after deactivate: got %+v", u)
+		}
+
+		u, err = r.Users.SetActive(ctx, "u1", true, nil)
+		mustNoErr(t, err)
+		if !u.IsActive {
+			t.Fatalf("after activate: got %+v", u)
+		}
+	})
+
+	t.Run("SetAway", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		seedTeam(t, r, "backend", user("u1", true))
+
+		until := time.Date(2030, 1, 2, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+		u, err := r.Users.SetAway(ctx, "u1", &until)
+		mustNoErr(t, err)
+		if u.AwayUntil == nil || !u.AwayUntil.Equal(until) || u.TeamName != "backend" {
+			t.Fatalf("after set: got %+v", u)
+		}
+		u, err = r.Users.GetByID(ctx, "u1")
+		mustNoErr(t, err)
+		if !u.Away(until.Add(-time.Minute)) || u.Away(until) {
+			t.Fatalf("stored: got %+v", u)
+		}
+
+		u, err = r.Users.SetAway(ctx, "u1", nil)
+		mustNoErr(t, err)
+		if u.AwayUntil != nil {
+			t.Fatalf("after clear: got %+v", u)
+		}
+		if _, err := r.Users.SetAway(ctx, "ghost", nil); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("missing: got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("SetActiveMissing", func(t *testing.T) {
+		r := newRepos(t)
+
+		if _, err := r.Users.SetActive(context.Background(), "ghost", true, nil); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("ListActiveInTeamExcept", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true), user("u3", false), user("u4", true))
+		seedTeam(t, r, "frontend", user("f1", true))
+
+		got, err := r.Users.ListActiveInTeamExcept(ctx, "backend", []string{"u1"}, 10)
+		mustNoErr(t, err)
+		ids := userIDs(got)
+		slices.Sort(ids)
+		if !slices.Equal(ids, []string{"u2", "u4"}) {
+			t.Fatalf("candidates: got %v", ids)
+		}
+
+		got, err = r.Users.ListActiveInTeamExcept(ctx, "backend", []string{"u1"}, 1)
+		mustNoErr(t, err)
+		if len(got) != 1 || got[0].UserID == "u1" || got[0].UserID == "u3" {
+			t.Fatalf("limited candidates: got %v", userIDs(got))
+		}
+
+		got, err = r.Users.ListActiveInTeamExcept(ctx, "backend", []string{"u1", "u2", "u4"}, 10)
+		mustNoErr(t, err)
+		if len(got) != 0 {
+			t.Fatalf("expected no candidates, got %v", userIDs(got))
+		}
+	})
+}
+
+func RunPRRepo(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true), user("u3", true))
+
+		created, err := r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u1"), []string{"u2", "u3"}, nil)
+		mustNoErr(t, err)
+		if created.ID != "pr-1" || created.Name != "pr-1 name" || created.AuthorID != "u1" {
+			t.Fatalf("created: got %+v", created)
+		}
+		if created.Status != domain.StatusOpen {
+			t.Fatalf("status: got %q", created.Status)
+		}
+		if created.CreatedAt == nil || created.MergedAt != nil {
+			t.Fatalf("timestamps: created=%v merged=%v", created.CreatedAt, created.MergedAt)
+		}
+		assertReviewers(t, created.AssignedReviewers, "u2", "u3")
+
+		got, err := r.PRs.GetByIDForUpdate(ctx, "pr-1")
+		mustNoErr(t, err)
+		assertReviewers(t, got.AssignedReviewers, "u2", "u3")
+
+		revs, err := r.PRs.GetAssignedReviewers(ctx, "pr-1")
+		mustNoErr(t, err)
+		assertReviewers(t, revs, "u2", "u3")
+	})
+
+	t.Run("CreateWithoutReviewers", func(t *testing.T) {
+		r := newRepos(t)
+		seedTeam(t, r, "backend", user("u1", true))
+
+		created, err := r.PRs.CreatePRWithReviewers(context.Background(), openPR("pr-1", "u1"), nil, nil)
+		mustNoErr(t, err)
+		if len(created.AssignedReviewers) != 0 {
+			t.Fatalf("reviewers: got %v", created.AssignedReviewers)
+		}
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true))
+
+		_, err := r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u1"), []string{"u2"}, nil)
+		mustNoErr(t, err)
+		_, err = r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u1"), nil, nil)
+		if !errors.Is(err, domain.ErrPRExists) {
+			t.Fatalf("got %v, want %v", err, domain.ErrPRExists)
+		}
+
+		got, err := r.PRs.GetByIDForUpdate(ctx, "pr-1")
+		mustNoErr(t, err)
+		assertReviewers(t, got.AssignedReviewers, "u2")
+	})
+
+	t.Run("GetMissing", func(t *testing.T) {
+		r := newRepos(t)
+
+		if _, err := r.PRs.GetByIDForUpdate(context.Background(), "nope"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("ReplaceReviewer", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true), user("u3", true), user("u4", true))
+
+		_, err := r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u1"), []string{"u2", "u3"}, nil)
+		mustNoErr(t, err)
+
+		updated, err := r.PRs.ReplaceReviewer(ctx, "pr-1", "u2", "u4", 0, nil)
+		mustNoErr(t, err)
+		assertReviewers(t, updated.AssignedReviewers, "u3", "u4")
+
+		list, err := r.PRs.ListByReviewer(ctx, "u2")
+		mustNoErr(t, err)
+		if len(list) != 0 {
+			t.Fatalf("old reviewer still sees %v", list)
+		}
+		list, err = r.PRs.ListByReviewer(ctx, "u4")
+		mustNoErr(t, err)
+		if len(list) != 1 || list[0].ID != "pr-1" {
+			t.Fatalf("new reviewer sees %v", list)
+		}
+
+		// Callers that read the PR before a concurrent change lose.
+		if _, err := r.PRs.ReplaceReviewer(ctx, "pr-1", "u2", "u1", 0, nil); !errors.Is(err, domain.ErrNotAssigned) {
+			t.Fatalf("replaced again: got %v, want %v", err, domain.ErrNotAssigned)
+		}
+		if _, err := r.PRs.ReplaceReviewer(ctx, "pr-1", "u3", "u4", 0, nil); !errors.Is(err, domain.ErrNoCandidate) {
+			t.Fatalf("new reviewer taken: got %v, want %v", err, domain.ErrNoCandidate)
+		}
+		_, err = r.PRs.SetMerged(ctx, "pr-1", 0, nil)
+		mustNoErr(t, err)
+		if _, err := r.PRs.ReplaceReviewer(ctx, "pr-1", "u3", "u2", 0, nil); !errors.Is(err, domain.ErrPRMerged) {
+			t.Fatalf("merged: got %v, want %v", err, domain.ErrPRMerged)
+		}
+		got, err := r.PRs.GetByIDForUpdate(ctx, "pr-1")
+		mustNoErr(t, err)
+		assertReviewers(t, got.AssignedReviewers, "u3", "u4")
+	})
+
+	t.Run("MergeIsIdempotent", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true))
+
+		_, err := r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u1"), []string{"u2"}, nil)
+		mustNoErr(t, err)
+
+		first, err := r.PRs.SetMerged(ctx, "pr-1", 0, nil)
+		mustNoErr(t, err)
+		if first.Status != domain.StatusMerged || first.MergedAt == nil {
+			t.Fatalf("first merge: got %+v", first)
+		}
+		assertReviewers(t, first.AssignedReviewers, "u2")
+
+		second, err := r.PRs.SetMerged(ctx, "pr-1", 0, nil)
+		mustNoErr(t, err)
+		if second.Status != domain.StatusMerged || second.MergedAt == nil {
+			t.Fatalf("second merge: got %+v", second)
+		}
+		if !second.MergedAt.Equal(*first.MergedAt) {
+			t.Fatalf("merged_at changed: %v -> %v", first.MergedAt, second.MergedAt)
+		}
+	})
+
+	t.Run("CloseAndReopen", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true), user("u3", true))
+
+		_, err := r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u1"), []string{"u2"}, nil)
+		mustNoErr(t, err)
+
+		closed, err := r.PRs.SetClosed(ctx, "pr-1", true, nil)
+		mustNoErr(t, err)
+		if closed.Status != domain.StatusClosed || closed.Version != 2 {
+			t.Fatalf("close: got %+v", closed)
+		}
+		assertReviewers(t, closed.AssignedReviewers, "u2")
+		again, err := r.PRs.SetClosed(ctx, "pr-1", true, nil)
+		mustNoErr(t, err)
+		if again.Status != domain.StatusClosed || again.Version != 2 {
+			t.Fatalf("second close: got %+v", again)
+		}
+
+		// A closed PR cannot be merged, reviewed or reassigned until it is
+		// reopened.
+		if _, err := r.PRs.SetMerged(ctx, "pr-1", 0, nil); !errors.Is(err, domain.ErrPRClosed) {
+			t.Fatalf("merge closed: got %v, want %v", err, domain.ErrPRClosed)
+		}
+		review := domain.Review{ReviewerID: "u2", Verdict: domain.VerdictApproved}
+		if _, err := r.PRs.SetReview(ctx, "pr-1", review, 0, nil); !errors.Is(err, domain.ErrPRClosed) {
+			t.Fatalf("review closed: got %v, want %v", err, domain.ErrPRClosed)
+		}
+		if _, err := r.PRs.ReplaceReviewer(ctx, "pr-1", "u2", "u3", 0, nil); !errors.Is(err, domain.ErrPRClosed) {
+			t.Fatalf("reassign closed: got %v, want %v", err, domain.ErrPRClosed)
+		}
+		got, err := r.PRs.GetByIDForUpdate(ctx, "pr-1")
+		mustNoErr(t, err)
+		if got.Status != domain.StatusClosed || got.Version != 2 {
+			t.Fatalf("after rejected changes: got %+v", got)
+		}
+		assertReviewers(t, got.AssignedReviewers, "u2")
+		reviews, err := r.PRs.ListReviews(ctx, "pr-1")
+		mustNoErr(t, err)
+		if len(reviews) != 0 {
+			t.Fatalf("reviews of closed PR: %v", reviews)
+		}
+
+		reopened, err := r.PRs.SetClosed(ctx, "pr-1", false, nil)
+		mustNoErr(t, err)
+		if reopened.Status != domain.StatusOpen || reopened.Version != 3 {
+			t.Fatalf("reopen: got %+v", reopened)
+		}
+		assertReviewers(t, reopened.AssignedReviewers, "u2")
+
+		_, err = r.PRs.SetMerged(ctx, "pr-1", 0, nil)
+		mustNoErr(t, err)
+		merged, err := r.PRs.SetClosed(ctx, "pr-1", true, nil)
+		mustNoErr(t, err)
+		if merged.Status != domain.StatusMerged {
+			t.Fatalf("close after merge: got %+v", merged)
+		}
+		if _, err := r.PRs.SetClosed(ctx, "nope", true, nil); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("missing: got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("Reviews", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true), user("u3", true))
+
+		_, err := r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u1"), []string{"u2", "u3"}, nil)
+		mustNoErr(t, err)
+		if _, err := r.PRs.SetReview(ctx, "nope", domain.Review{ReviewerID: "u2", Verdict: domain.VerdictApproved}, 0, nil); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("missing PR: got %v, want %v", err, domain.ErrNotFound)
+		}
+		if _, err := r.PRs.SetReview(ctx, "pr-1", domain.Review{ReviewerID: "ghost", Verdict: domain.VerdictApproved}, 0, nil); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("missing user: got %v, want %v", err, domain.ErrNotFound)
+		}
+
+		got, err := r.PRs.ListReviews(ctx, "pr-1")
+		mustNoErr(t, err)
+		if len(got) != 0 {
+			t.Fatalf("no reviews yet: got %+v", got)
+		}
+		for _, rv := range []domain.Review{
+			{ReviewerID: "u3", Verdict: domain.VerdictChangesRequested},
+			{ReviewerID: "u2", Verdict: domain.VerdictApproved},
+			{ReviewerID: "u3", Verdict: domain.VerdictApproved},
+		} {
+			pr, err := r.PRs.SetReview(ctx, "pr-1", rv, 0, nil)
+			mustNoErr(t, err)
+			if pr.ID != "pr-1" {
+				t.Fatalf("review %+v: got %+v", rv, pr)
+			}
+		}
+		got, err = r.PRs.ListReviews(ctx, "pr-1")
+		mustNoErr(t, err)
+		if len(got) != 2 || got[0].ReviewerID != "u2" || got[1].ReviewerID != "u3" ||
+			got[0].Verdict != domain.VerdictApproved || got[1].Verdict != domain.VerdictApproved || got[1].CreatedAt.IsZero() {
+			t.Fatalf("reviews: got %+v", got)
+		}
+	})
+
+	t.Run("MergeMissing", func(t *testing.T) {
+		r := newRepos(t)
+
+		if _, err := r.PRs.SetMerged(context.Background(), "nope", 0, nil); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("Versions", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true), user("u3", true), user("u4", true))
+
+		created, err := r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u1"), []string{"u2", "u3"}, nil)
+		mustNoErr(t, err)
+		if created.Version != 1 {
+			t.Fatalf("created: got version %d, want 1", created.Version)
+		}
+
+		reassigned, err := r.PRs.ReplaceReviewer(ctx, "pr-1", "u2", "u4", 1, nil)
+		mustNoErr(t, err)
+		if reassigned.Version != 2 {
+			t.Fatalf("reassigned: got version %d, want 2", reassigned.Version)
+		}
+		if _, err := r.PRs.ReplaceReviewer(ctx, "pr-1", "u3", "u2", 1, nil); !errors.Is(err, domain.ErrVersionMismatch) {
+			t.Fatalf("stale reassign: got %v, want %v", err, domain.ErrVersionMismatch)
+		}
+
+		review := domain.Review{ReviewerID: "u4", Verdict: domain.VerdictApproved}
+		reviewed, err := r.PRs.SetReview(ctx, "pr-1", review, 2, nil)
+		mustNoErr(t, err)
+		if reviewed.Version != 3 {
+			t.Fatalf("reviewed: got version %d, want 3", reviewed.Version)
+		}
+		// The same verdict again changes nothing.
+		again, err := r.PRs.SetReview(ctx, "pr-1", review, 0, nil)
+		mustNoErr(t, err)
+		if again.Version != 3 {
+			t.Fatalf("same verdict: got version %d, want 3", again.Version)
+		}
+		if _, err := r.PRs.SetReview(ctx, "pr-1", review, 2, nil); !errors.Is(err, domain.ErrVersionMismatch) {
+			t.Fatalf("stale review: got %v, want %v", err, domain.ErrVersionMismatch)
+		}
+
+		if _, err := r.PRs.SetMerged(ctx, "pr-1", 2, nil); !errors.Is(err, domain.ErrVersionMismatch) {
+			t.Fatalf("stale merge: got %v, want %v", err, domain.ErrVersionMismatch)
+		}
+		merged, err := r.PRs.SetMerged(ctx, "pr-1", 3, nil)
+		mustNoErr(t, err)
+		if merged.Version != 4 {
+			t.Fatalf("merged: got version %d, want 4", merged.Version)
+		}
+		got, err := r.PRs.GetByIDForUpdate(ctx, "pr-1")
+		mustNoErr(t, err)
+		if got.Version != 4 {
+			t.Fatalf("get: got version %d, want 4", got.Version)
+		}
+	})
+
+	t.Run("ListByReviewerNewestFirst", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true), user("u3", true))
+
+		for _, id := range []string{"pr-1", "pr-2", "pr-3"} {
+			_, err := r.PRs.CreatePRWithReviewers(ctx, openPR(id, "u1"), []string{"u2"}, nil)
+			mustNoErr(t, err)
+		}
+		_, err := r.PRs.CreatePRWithReviewers(ctx, openPR("pr-other", "u1"), []string{"u3"}, nil)
+		mustNoErr(t, err)
+		_, err = r.PRs.SetMerged(ctx, "pr-2", 0, nil)
+		mustNoErr(t, err)
+
+		list, err := r.PRs.ListByReviewer(ctx, "u2")
+		mustNoErr(t, err)
+
+		var ids []string
+		for _, s := range list {
+			ids = append(ids, s.ID)
+			if s.AuthorID != "u1" || s.Name != s.ID+" name" || s.CreatedAt == nil {
+				t.Fatalf("short PR: got %+v", s)
+			}
+		}
+		if !slices.Equal(ids, []string{"pr-3", "pr-2", "pr-1"}) {
+			t.Fatalf("order: got %v", ids)
+		}
+		if list[1].Status != domain.StatusMerged || list[0].Status != domain.StatusOpen {
+			t.Fatalf("statuses: got %+v", list)
+		}
+
+		list, err = r.PRs.ListByReviewer(ctx, "nobody")
+		mustNoErr(t, err)
+		if len(list) != 0 {
+			t.Fatalf("unknown reviewer: got %v", list)
+		}
+	})
+
+	t.Run("StatsByStatus", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		stats, err := r.PRs.StatsByStatus(ctx)
+		mustNoErr(t, err)
+		if stats[domain.StatusOpen] != 0 || stats[domain.StatusMerged] != 0 || stats[domain.StatusClosed] != 0 {
+			t.Fatalf("empty stats: got %v", stats)
+		}
+
+		seedTeam(t, r, "backend", user("u1", true))
+		for _, id := range []string{"pr-1", "pr-2", "pr-3"} {
+			_, err := r.PRs.CreatePRWithReviewers(ctx, openPR(id, "u1"), nil, nil)
+			mustNoErr(t, err)
+		}
+		_, err = r.PRs.SetMerged(ctx, "pr-1", 0, nil)
+		mustNoErr(t, err)
+		_, err = r.PRs.SetClosed(ctx, "pr-2", true, nil)
+		mustNoErr(t, err)
+
+		stats, err = r.PRs.StatsByStatus(ctx)
+		mustNoErr(t, err)
+		if stats[domain.StatusOpen] != 1 || stats[domain.StatusMerged] != 1 || stats[domain.StatusClosed] != 1 {
+			t.Fatalf("stats: got %v", stats)
+		}
+	})
+}
+
+func RunOrgRepo(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	t.Run("DefaultExists", func(t *testing.T) {
+		r := newRepos(t)
+
+		org, err := r.Orgs.GetOrg(context.Background(), domain.DefaultOrg)
+		mustNoErr(t, err)
+		if org.OrgID != domain.DefaultOrg {
+			t.Fatalf("org id: got %q", org.OrgID)
+		}
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		org, err := r.Orgs.CreateOrg(ctx, domain.Organization{OrgID: "acme", Name: "Acme"})
+		mustNoErr(t, err)
+		if org.OrgID != "acme" || org.Name != "Acme" || org.CreatedAt.IsZero() {
+			t.Fatalf("created org: got %+v", org)
+		}
+		if _, err := r.Orgs.CreateOrg(ctx, domain.Organization{OrgID: "acme", Name: "Again"}); !errors.Is(err, domain.ErrOrgExists) {
+			t.Fatalf("second CreateOrg: got %v, want %v", err, domain.ErrOrgExists)
+		}
+	})
+
+	t.Run("GetMissing", func(t *testing.T) {
+		r := newRepos(t)
+
+		if _, err := r.Orgs.GetOrg(context.Background(), "nope"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("ListOrderedByID", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		for _, id := range []string{"zeta", "acme"} {
+			_, err := r.Orgs.CreateOrg(ctx, domain.Organization{OrgID: id, Name: id})
+			mustNoErr(t, err)
+		}
+
+		orgs, err := r.Orgs.ListOrgs(ctx)
+		mustNoErr(t, err)
+		var got []string
+		for _, o := range orgs {
+			got = append(got, o.OrgID)
+		}
+		if want := []string{"acme", domain.DefaultOrg, "zeta"}; !slices.Equal(got, want) {
+			t.Fatalf("orgs: got %v, want %v", got, want)
+		}
+	})
+}
+
+// RunTenantIsolation checks that identical team, user and PR ids live side by
+// side in different organizations without seeing each other.
+func RunTenantIsolation(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	r := newRepos(t)
+	acme := domain.WithOrg(context.Background(), "acme")
+	globex := domain.WithOrg(context.Background(), "globex")
+
+	for _, ctx := range []context.Context{acme, globex} {
+		_, err := r.Orgs.CreateOrg(ctx, domain.Organization{OrgID: domain.OrgFromContext(ctx), Name: "x"})
+		mustNoErr(t, err)
+		mustNoErr(t, r.Teams.CreateTeam(ctx, "backend"))
+		mustNoErr(t, r.Teams.UpsertUsersToTeam(ctx, "backend", []domain.User{user("u1", true), user("u2", true)}))
+		_, err = r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u1"), []string{"u2"}, nil)
+		mustNoErr(t, err)
+	}
+
+	t.Run("DefaultOrgSeesNothing", func(t *testing.T) {
+		ctx := context.Background()
+
+		if _, _, err := r.Teams.GetTeamWithMembers(ctx, "backend"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("GetTeamWithMembers: got %v, want %v", err, domain.ErrNotFound)
+		}
+		if _, err := r.Users.GetByID(ctx, "u1"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("GetByID: got %v, want %v", err, domain.ErrNotFound)
+		}
+		if _, err := r.PRs.GetByIDForUpdate(ctx, "pr-1"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("GetByIDForUpdate: got %v, want %v", err, domain.ErrNotFound)
+		}
+		stats, err := r.PRs.StatsByStatus(ctx)
+		mustNoErr(t, err)
+		if stats[domain.StatusOpen] != 0 {
+			t.Fatalf("stats: got %v, want no PRs", stats)
+		}
+	})
+
+	t.Run("MutationsStayInOrg", func(t *testing.T) {
+		_, err := r.Users.SetActive(acme, "u2", false, nil)
+		mustNoErr(t, err)
+		_, err = r.PRs.SetMerged(acme, "pr-1", 0, nil)
+		mustNoErr(t, err)
+
+		u, err := r.Users.GetByID(globex, "u2")
+		mustNoErr(t, err)
+		if !u.IsActive {
+			t.Fatal("deactivating u2 in acme leaked into globex")
+		}
+		pr, err := r.PRs.GetByIDForUpdate(globex, "pr-1")
+		mustNoErr(t, err)
+		if pr.Status != domain.StatusOpen {
+			t.Fatalf("globex pr-1 status: got %s, want %s", pr.Status, domain.StatusOpen)
+		}
+
+		active, err := r.Users.ListActiveInTeamExcept(globex, "backend", nil, 10)
+		mustNoErr(t, err)
+		got := userIDs(active)
+		slices.Sort(got)
+		if !slices.Equal(got, []string{"u1", "u2"}) {
+			t.Fatalf("globex active: got %v", got)
+		}
+		prs, err := r.PRs.ListByReviewer(globex, "u2")
+		mustNoErr(t, err)
+		if len(prs) != 1 || prs[0].Status != domain.StatusOpen {
+			t.Fatalf("globex reviews of u2: got %+v", prs)
+		}
+	})
+}
+
+func RunDeliveryRepo(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	t.Run("ClaimOnce", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		for i, want := range []bool{true, false} {
+			first, err := r.Deliveries.ClaimDelivery(ctx, "github", "d-1")
+			mustNoErr(t, err)
+			if first != want {
+				t.Fatalf("claim #%d: got %v, want %v", i+1, first, want)
+			}
+		}
+		first, err := r.Deliveries.ClaimDelivery(ctx, "gitlab", "d-1")
+		mustNoErr(t, err)
+		if !first {
+			t.Fatal("same delivery id from another source reported as duplicate")
+		}
+	})
+
+	t.Run("ReleaseAllowsRetry", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		_, err := r.Deliveries.ClaimDelivery(ctx, "github", "d-1")
+		mustNoErr(t, err)
+		mustNoErr(t, r.Deliveries.ReleaseDelivery(ctx, "github", "d-1"))
+		first, err := r.Deliveries.ClaimDelivery(ctx, "github", "d-1")
+		mustNoErr(t, err)
+		if !first {
+			t.Fatal("released delivery still reported as duplicate")
+		}
+	})
+}
+
+func RunGitLabProjectRepo(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	t.Run("UnknownTeam", func(t *testing.T) {
+		r := newRepos(t)
+
+		err := r.GitLab.SetGitLabProject(context.Background(),
+			domain.GitLabProject{ProjectID: 1, OrgID: domain.DefaultOrg, TeamName: "nope"})
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("SetReplaceDelete", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		seedTeam(t, r, "backend")
+		seedTeam(t, r, "mobile")
+		for _, p := range []domain.GitLabProject{
+			{ProjectID: 20, OrgID: domain.DefaultOrg, TeamName: "backend"},
+			{ProjectID: 10, OrgID: domain.DefaultOrg, TeamName: "backend"},
+			{ProjectID: 20, OrgID: domain.DefaultOrg, TeamName: "mobile"},
+		} {
+			mustNoErr(t, r.GitLab.SetGitLabProject(ctx, p))
+		}
+
+		got, err := r.GitLab.GetGitLabProject(ctx, 20)
+		mustNoErr(t, err)
+		if got.TeamName != "mobile" || got.OrgID != domain.DefaultOrg {
+			t.Fatalf("project 20: got %+v", got)
+		}
+
+		list, err := r.GitLab.ListGitLabProjects(ctx)
+		mustNoErr(t, err)
+		if len(list) != 2 || list[0].ProjectID != 10 || list[1].ProjectID != 20 {
+			t.Fatalf("list: got %+v", list)
+		}
+
+		mustNoErr(t, r.GitLab.DeleteGitLabProject(ctx, 20))
+		if _, err := r.GitLab.GetGitLabProject(ctx, 20); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("get deleted: got %v, want %v", err, domain.ErrNotFound)
+		}
+		if err := r.GitLab.DeleteGitLabProject(ctx, 20); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("delete twice: got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+}
+
+func RunReviewerSyncRepo(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	seed := func(t *testing.T, r Repos) {
+		t.Helper()
+		ctx := context.Background()
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true), user("u3", true))
+		for _, id := range []string{"pr-1", "pr-2"} {
+			_, err := r.PRs.CreatePRWithReviewers(ctx, openPR(id, "u1"), []string{"u2"}, nil)
+			mustNoErr(t, err)
+		}
+	}
+	enqueue := func(t *testing.T, r Repos, prID string) domain.ReviewerSync {
+		t.Helper()
+		s, err := r.Syncs.EnqueueReviewerSync(context.Background(), domain.ReviewerSync{
+			PRID: prID, Add: []string{"u3"}, Remove: []string{"u2"},
+			Status: domain.SyncPending, NextAttemptAt: now,
+		})
+		mustNoErr(t, err)
+		return s
+	}
+	ids := func(syncs []domain.ReviewerSync) []int64 {
+		out := make([]int64, 0, len(syncs))
+		for _, s := range syncs {
+			out = append(out, s.ID)
+		}
+		return out
+	}
+
+	t.Run("Enqueue", func(t *testing.T) {
+		r := newRepos(t)
+		seed(t, r)
+
+		s := enqueue(t, r, "pr-1")
+		if s.ID == 0 || s.OrgID != domain.DefaultOrg || s.Status != domain.SyncPending || s.Attempts != 0 {
+			t.Fatalf("enqueued: got %+v", s)
+		}
+		if !slices.Equal(s.Add, []string{"u3"}) || !slices.Equal(s.Remove, []string{"u2"}) {
+			t.Fatalf("reviewers: got +%v -%v", s.Add, s.Remove)
+		}
+		if _, err := r.Syncs.GetReviewerSync(context.Background(), s.ID+100); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("get missing: got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("ClaimOnePerPRAndLease", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seed(t, r)
+
+		first, second, other := enqueue(t, r, "pr-1"), enqueue(t, r, "pr-1"), enqueue(t, r, "pr-2")
+		lease := now.Add(time.Minute)
+
+		got, err := r.Syncs.ClaimReviewerSyncs(ctx, now, lease, 10)
+		mustNoErr(t, err)
+		if want := []int64{first.ID, other.ID}; !slices.Equal(ids(got), want) {
+			t.Fatalf("first claim: got %v, want %v", ids(got), want)
+		}
+
+		got, err = r.Syncs.ClaimReviewerSyncs(ctx, now, lease, 10)
+		mustNoErr(t, err)
+		if len(got) != 0 {
+			t.Fatalf("leased syncs claimed again: %v", ids(got))
+		}
+
+		first.Status, first.Attempts = domain.SyncDone, 1
+		mustNoErr(t, r.Syncs.SaveReviewerSync(ctx, first))
+
+		got, err = r.Syncs.ClaimReviewerSyncs(ctx, now, lease, 10)
+		mustNoErr(t, err)
+		if want := []int64{second.ID}; !slices.Equal(ids(got), want) {
+			t.Fatalf("after first is done: got %v, want %v", ids(got), want)
+		}
+
+		got, err = r.Syncs.ClaimReviewerSyncs(ctx, lease, lease.Add(time.Minute), 10)
+		mustNoErr(t, err)
+		if want := []int64{second.ID, other.ID}; !slices.Equal(ids(got), want) {
+			t.Fatalf("after lease expiry: got %v, want %v", ids(got), want)
+		}
+	})
+
+	t.Run("SaveAndList", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seed(t, r)
+
+		a, b := enqueue(t, r, "pr-1"), enqueue(t, r, "pr-2")
+		b.Status, b.Attempts, b.LastError = domain.SyncFailed, 3, "boom"
+		mustNoErr(t, r.Syncs.SaveReviewerSync(ctx, b))
+
+		got, err := r.Syncs.GetReviewerSync(ctx, b.ID)
+		mustNoErr(t, err)
+		if got.Status != domain.SyncFailed || got.Attempts != 3 || got.LastError != "boom" {
+			t.Fatalf("saved: got %+v", got)
+		}
+
+		failed, err := r.Syncs.ListReviewerSyncs(ctx, domain.SyncFailed)
+		mustNoErr(t, err)
+		if !slices.Equal(ids(failed), []int64{b.ID}) {
+			t.Fatalf("failed: got %v", ids(failed))
+		}
+		all, err := r.Syncs.ListReviewerSyncs(ctx, "")
+		mustNoErr(t, err)
+		if !slices.Equal(ids(all), []int64{a.ID, b.ID}) {
+			t.Fatalf("all: got %v", ids(all))
+		}
+	})
+}
+
+func RunSubscriptionRepo(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	subscribe := func(t *testing.T, r Repos, ctx context.Context, id string) domain.Subscription {
+		t.Helper()
+		s, err := r.Subs.CreateSubscription(ctx, domain.Subscription{
+			ID: id, URL: "https://example.com/" + id, Secret: "s3cret",
+			EventTypes: []domain.EventType{domain.EventPRCreated, domain.EventPRMerged},
+		})
+		mustNoErr(t, err)
+		return s
+	}
+	enqueue := func(t *testing.T, r Repos, ctx context.Context, subID string) domain.EventDelivery {
+		t.Helper()
+		d, err := r.Events.EnqueueEventDelivery(ctx, domain.EventDelivery{
+			SubscriptionID: subID, EventID: "evt", EventType: domain.EventPRCreated,
+			Payload: []byte(`{"id":"evt"}`), Status: domain.DeliveryPending, NextAttemptAt: now,
+		})
+		mustNoErr(t, err)
+		return d
+	}
+	ids := func(ds []domain.EventDelivery) []int64 {
+		out := make([]int64, 0, len(ds))
+		for _, d := range ds {
+			out = append(out, d.ID)
+		}
+		return out
+	}
+
+	t.Run("CreateGetListDelete", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		a := subscribe(t, r, ctx, "sub-a")
+		if a.OrgID != domain.DefaultOrg || a.URL != "https://example.com/sub-a" || a.Secret != "s3cret" || a.CreatedAt.IsZero() {
+			t.Fatalf("created: got %+v", a)
+		}
+		if !a.Wants(domain.EventPRMerged) || a.Wants(domain.EventPRReassigned) {
+			t.Fatalf("event types: got %v", a.EventTypes)
+		}
+		subscribe(t, r, ctx, "sub-b")
+
+		list, err := r.Subs.ListSubscriptions(ctx)
+		mustNoErr(t, err)
+		if len(list) != 2 || list[0].ID != "sub-a" || list[1].ID != "sub-b" {
+			t.Fatalf("list: got %+v", list)
+		}
+
+		mustNoErr(t, r.Subs.DeleteSubscription(ctx, "sub-a"))
+		if _, err := r.Subs.GetSubscription(ctx, "sub-a"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("get deleted: got %v, want %v", err, domain.ErrNotFound)
+		}
+		if err := r.Subs.DeleteSubscription(ctx, "sub-a"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("delete twice: got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("ScopedByOrg", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		_, err := r.Orgs.CreateOrg(ctx, domain.Organization{OrgID: "acme", Name: "Acme"})
+		mustNoErr(t, err)
+		acme := domain.WithOrg(ctx, "acme")
+
+		subscribe(t, r, ctx, "sub-a")
+		d := enqueue(t, r, ctx, "sub-a")
+
+		if _, err := r.Subs.GetSubscription(acme, "sub-a"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("get from other org: got %v, want %v", err, domain.ErrNotFound)
+		}
+		if list, err := r.Subs.ListSubscriptions(acme); err != nil || len(list) != 0 {
+			t.Fatalf("list from other org: got %v, %v", list, err)
+		}
+		if _, err := r.Events.GetEventDelivery(acme, d.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("delivery from other org: got %v, want %v", err, domain.ErrNotFound)
+		}
+		if _, err := r.Events.EnqueueEventDelivery(acme, domain.EventDelivery{
+			SubscriptionID: "sub-a", EventID: "evt", EventType: domain.EventPRCreated,
+			Payload: []byte(`{}`), Status: domain.DeliveryPending, NextAttemptAt: now,
+		}); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("enqueue for other org's subscription: got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("DeliveryLog", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		subscribe(t, r, ctx, "sub-a")
+		subscribe(t, r, ctx, "sub-b")
+
+		first, second, other := enqueue(t, r, ctx, "sub-a"), enqueue(t, r, ctx, "sub-a"), enqueue(t, r, ctx, "sub-b")
+		if string(first.Payload) != `{"id":"evt"}` || first.Status != domain.DeliveryPending || first.OrgID != domain.DefaultOrg {
+			t.Fatalf("enqueued: got %+v", first)
+		}
+
+		lease := now.Add(time.Minute)
+		got, err := r.Events.ClaimEventDeliveries(ctx, now, lease, 2)
+		mustNoErr(t, err)
+		if want := []int64{first.ID, second.ID}; !slices.Equal(ids(got), want) {
+			t.Fatalf("claim: got %v, want %v", ids(got), want)
+		}
+		got, err = r.Events.ClaimEventDeliveries(ctx, now, lease, 10)
+		mustNoErr(t, err)
+		if want := []int64{other.ID}; !slices.Equal(ids(got), want) {
+			t.Fatalf("second claim: got %v, want %v", ids(got), want)
+		}
+
+		first.Status, first.Attempts, first.ResponseCode, first.LastError = domain.DeliveryFailed, 2, 500, "status 500"
+		mustNoErr(t, r.Events.SaveEventDelivery(ctx, first))
+		saved, err := r.Events.GetEventDelivery(ctx, first.ID)
+		mustNoErr(t, err)
+		if saved.Status != domain.DeliveryFailed || saved.Attempts != 2 || saved.ResponseCode != 500 || saved.LastError != "status 500" {
+			t.Fatalf("saved: got %+v", saved)
+		}
+
+		log, err := r.Events.ListEventDeliveries(ctx, "sub-a", 10)
+		mustNoErr(t, err)
+		if want := []int64{second.ID, first.ID}; !slices.Equal(ids(log), want) {
+			t.Fatalf("log: got %v, want %v", ids(log), want)
+		}
+		log, err = r.Events.ListEventDeliveries(ctx, "sub-a", 1)
+		mustNoErr(t, err)
+		if want := []int64{second.ID}; !slices.Equal(ids(log), want) {
+			t.Fatalf("limited log: got %v, want %v", ids(log), want)
+		}
+
+		mustNoErr(t, r.Subs.DeleteSubscription(ctx, "sub-a"))
+		if _, err := r.Events.GetEventDelivery(ctx, first.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("delivery of deleted subscription: got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+}
+
+func RunOutboxRepo(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	seq := 0
+	event := func(typ domain.EventType) *domain.Event {
+		seq++
+		return &domain.Event{
+			ID:         fmt.Sprintf("evt-%d", seq),
+			Type:       typ,
+			OrgID:      domain.DefaultOrg,
+			OccurredAt: time.Now().UTC().Truncate(time.Millisecond),
+		}
+	}
+	pending := func(t *testing.T, r Repos) []domain.OutboxMessage {
+		t.Helper()
+		got, err := r.Outbox.ListOutbox(context.Background(), domain.OutboxPending)
+		mustNoErr(t, err)
+		return got
+	}
+	ids := func(msgs []domain.OutboxMessage) []int64 {
+		out := make([]int64, 0, len(msgs))
+		for _, m := range msgs {
+			out = append(out, m.ID)
+		}
+		return out
+	}
+
+	t.Run("EventsOfPR", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true), user("u3", true))
+
+		about := func(typ domain.EventType, prID string) *domain.Event {
+			e := event(typ)
+			e.Actor = "u1"
+			e.Data.PullRequest = &domain.EventPR{ID: prID}
+			return e
+		}
+		created := about(domain.EventPRCreated, "pr-1")
+		_, err := r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u1"), []string{"u2"}, created)
+		mustNoErr(t, err)
+		_, err = r.PRs.CreatePRWithReviewers(ctx, openPR("pr-2", "u1"), []string{"u2"}, about(domain.EventPRCreated, "pr-2"))
+		mustNoErr(t, err)
+		reassigned := about(domain.EventPRReassigned, "pr-1")
+		reassigned.Data.Reason = "on vacation"
+		_, err = r.PRs.ReplaceReviewer(ctx, "pr-1", "u2", "u3", 0, reassigned)
+		mustNoErr(t, err)
+
+		got, err := r.PRs.ListEvents(ctx, "pr-1")
+		mustNoErr(t, err)
+		if len(got) != 2 || got[0].ID != created.ID || got[1].ID != reassigned.ID {
+			t.Fatalf("events: got %+v", got)
+		}
+		if got[1].Actor != "u1" || got[1].Data.Reason != "on vacation" {
+			t.Fatalf("reassign event: got %+v", got[1])
+		}
+
+		got, err = r.PRs.ListEvents(ctx, "nope")
+		mustNoErr(t, err)
+		if len(got) != 0 {
+			t.Fatalf("unknown PR: got %+v", got)
+		}
+	})
+
+	t.Run("WrittenOnlyWithChanges", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true), user("u3", true))
+
+		_, err := r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u1"), []string{"u2"}, event(domain.EventPRCreated))
+		mustNoErr(t, err)
+		if _, err := r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u1"), nil, event(domain.EventPRCreated)); !errors.Is(err, domain.ErrPRExists) {
+			t.Fatalf("duplicate: got %v, want %v", err, domain.ErrPRExists)
+		}
+		_, err = r.PRs.ReplaceReviewer(ctx, "pr-1", "u2", "u3", 0, event(domain.EventPRReassigned))
+		mustNoErr(t, err)
+		for range 2 {
+			_, err = r.PRs.SetMerged(ctx, "pr-1", 0, event(domain.EventPRMerged))
+			mustNoErr(t, err)
+		}
+		for range 2 {
+			_, err = r.Users.SetActive(ctx, "u3", false, event(domain.EventUserActivityChanged))
+			mustNoErr(t, err)
+		}
+		if _, err := r.Users.SetActive(ctx, "nope", false, event(domain.EventUserActivityChanged)); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("missing user: got %v, want %v", err, domain.ErrNotFound)
+		}
+
+		got := pending(t, r)
+		want := []domain.EventType{domain.EventPRCreated, domain.EventPRReassigned, domain.EventPRMerged, domain.EventUserActivityChanged}
+		if len(got) != len(want) {
+			t.Fatalf("outbox: got %d messages, want %d", len(got), len(want))
+		}
+		for i, m := range got {
+			if m.Event.Type != want[i] || m.Attempts != 0 {
+				t.Fatalf("message %d: got %+v, want %s", i, m, want[i])
+			}
+		}
+
+		created := got[0].Event.Data.PullRequest
+		if got[0].Key != "pr/pr-1" || created == nil || created.Status != domain.StatusOpen {
+			t.Fatalf("created: key %q, pr %+v", got[0].Key, created)
+		}
+		assertReviewers(t, created.AssignedReviewers, "u2")
+		assertReviewers(t, got[1].Event.Data.PullRequest.AssignedReviewers, "u3")
+		if merged := got[2].Event.Data.PullRequest; merged.Status != domain.StatusMerged || merged.MergedAt == nil {
+			t.Fatalf("merged: got %+v", merged)
+		}
+		if u := got[3].Event.Data.User; got[3].Key != "user/u3" || u == nil || u.IsActive || u.Username != "name-u3" {
+			t.Fatalf("deactivated: key %q, user %+v", got[3].Key, u)
+		}
+	})
+
+	t.Run("ClaimOnePerKeyAndLease", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true), user("u3", true))
+
+		_, err := r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u1"), []string{"u2"}, event(domain.EventPRCreated))
+		mustNoErr(t, err)
+		_, err = r.PRs.CreatePRWithReviewers(ctx, openPR("pr-2", "u1"), []string{"u2"}, event(domain.EventPRCreated))
+		mustNoErr(t, err)
+		_, err = r.PRs.SetMerged(ctx, "pr-1", 0, event(domain.EventPRMerged))
+		mustNoErr(t, err)
+		msgs := pending(t, r)
+		first, other, second := msgs[0], msgs[1], msgs[2]
+
+		now := time.Now().UTC().Add(time.Second)
+		lease := now.Add(time.Minute)
+		got, err := r.Outbox.ClaimOutbox(ctx, now, lease, 10)
+		mustNoErr(t, err)
+		if want := []int64{first.ID, other.ID}; !slices.Equal(ids(got), want) {
+			t.Fatalf("first claim: got %v, want %v", ids(got), want)
+		}
+
+		got, err = r.Outbox.ClaimOutbox(ctx, now, lease, 10)
+		mustNoErr(t, err)
+		if len(got) != 0 {
+			t.Fatalf("leased messages claimed again: %v", ids(got))
+		}
+
+		first.Status, first.Attempts = domain.OutboxPublished, 1
+		mustNoErr(t, r.Outbox.SaveOutbox(ctx, first))
+
+		got, err = r.Outbox.ClaimOutbox(ctx, now, lease, 10)
+		mustNoErr(t, err)
+		if want := []int64{second.ID}; !slices.Equal(ids(got), want) {
+			t.Fatalf("after first is published: got %v, want %v", ids(got), want)
+		}
+
+		got, err = r.Outbox.ClaimOutbox(ctx, lease, lease.Add(time.Minute), 1)
+		mustNoErr(t, err)
+		if want := []int64{other.ID}; !slices.Equal(ids(got), want) {
+			t.Fatalf("after lease expiry with limit 1: got %v, want %v", ids(got), want)
+		}
+	})
+
+	t.Run("SaveGetAndList", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true))
+
+		_, err := r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u1"), nil, event(domain.EventPRCreated))
+		mustNoErr(t, err)
+		_, err = r.Users.SetActive(ctx, "u2", false, event(domain.EventUserActivityChanged))
+		mustNoErr(t, err)
+		msgs := pending(t, r)
+
+		if len(msgs[0].PublishedTo) != 0 {
+			t.Fatalf("new message published to %v", msgs[0].PublishedTo)
+		}
+		b := msgs[1]
+		b.Status, b.Attempts, b.LastError = domain.OutboxFailed, 3, "boom"
+		b.PublishedTo = []string{"webhooks", "log"}
+		mustNoErr(t, r.Outbox.SaveOutbox(ctx, b))
+
+		got, err := r.Outbox.GetOutbox(ctx, b.ID)
+		mustNoErr(t, err)
+		if got.Status != domain.OutboxFailed || got.Attempts != 3 || got.LastError != "boom" || got.Event.ID != b.Event.ID ||
+			!slices.Equal(got.PublishedTo, b.PublishedTo) {
+			t.Fatalf("saved: got %+v", got)
+		}
+		if _, err := r.Outbox.GetOutbox(ctx, b.ID+100); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("get missing: got %v, want %v", err, domain.ErrNotFound)
+		}
+		b.ID += 100
+		if err := r.Outbox.SaveOutbox(ctx, b); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("save missing: got %v, want %v", err, domain.ErrNotFound)
+		}
+
+		failed, err := r.Outbox.ListOutbox(ctx, domain.OutboxFailed)
+		mustNoErr(t, err)
+		if !slices.Equal(ids(failed), []int64{msgs[1].ID}) {
+			t.Fatalf("failed: got %v", ids(failed))
+		}
+		all, err := r.Outbox.ListOutbox(ctx, "")
+		mustNoErr(t, err)
+		if !slices.Equal(ids(all), ids(msgs)) {
+			t.Fatalf("all: got %v", ids(all))
+		}
+	})
+
+	t.Run("ListAfterAndLast", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		_, err := r.Orgs.CreateOrg(ctx, domain.Organization{OrgID: "acme", Name: "Acme"})
+		mustNoErr(t, err)
+		acme := domain.WithOrg(ctx, "acme")
+
+		last, err := r.Outbox.LastOutboxID(ctx)
+		mustNoErr(t, err)
+		if last != 0 {
+			t.Fatalf("last of empty outbox: got %d, want 0", last)
+		}
+
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true))
+		mustNoErr(t, r.Teams.CreateTeam(acme, "backend"))
+		mustNoErr(t, r.Teams.UpsertUsersToTeam(acme, "backend", []domain.User{user("u1", true)}))
+		for _, id := range []string{"pr-1", "pr-2", "pr-3"} {
+			_, err := r.PRs.CreatePRWithReviewers(ctx, openPR(id, "u1"), nil, event(domain.EventPRCreated))
+			mustNoErr(t, err)
+			e := event(domain.EventPRCreated)
+			e.OrgID = "acme"
+			_, err = r.PRs.CreatePRWithReviewers(acme, openPR(id, "u1"), nil, e)
+			mustNoErr(t, err)
+		}
+		// The relay lists every organization.
+		msgs := slices.DeleteFunc(pending(t, r), func(m domain.OutboxMessage) bool { return m.Event.OrgID != domain.DefaultOrg })
+		if len(msgs) != 3 {
+			t.Fatalf("default org messages: got %d, want 3", len(msgs))
+		}
+		// Delivered messages stay in the log.
+		msgs[0].Status = domain.OutboxPublished
+		mustNoErr(t, r.Outbox.SaveOutbox(ctx, msgs[0]))
+
+		got, err := r.Outbox.ListOutboxAfter(ctx, 0, 2)
+		mustNoErr(t, err)
+		if want := ids(msgs[:2]); !slices.Equal(ids(got), want) {
+			t.Fatalf("first page: got %v, want %v", ids(got), want)
+		}
+		got, err = r.Outbox.ListOutboxAfter(ctx, msgs[1].ID, 2)
+		mustNoErr(t, err)
+		if want := ids(msgs[2:]); !slices.Equal(ids(got), want) || got[0].Event.Data.PullRequest.ID != "pr-3" {
+			t.Fatalf("second page: got %v, want %v", ids(got), want)
+		}
+		last, err = r.Outbox.LastOutboxID(ctx)
+		mustNoErr(t, err)
+		if last != msgs[2].ID {
+			t.Fatalf("last: got %d, want %d", last, msgs[2].ID)
+		}
+
+		other, err := r.Outbox.ListOutboxAfter(acme, 0, 10)
+		mustNoErr(t, err)
+		if len(other) != 3 || slices.ContainsFunc(other, func(m domain.OutboxMessage) bool { return slices.Contains(ids(msgs), m.ID) }) {
+			t.Fatalf("other org: got %v", ids(other))
+		}
+	})
+}
+
+func RunSlackRepo(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	t.Run("UnknownTeamOrUser", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		if err := r.Slack.SetSlackChannel(ctx, domain.SlackChannel{TeamName: "nope", WebhookURL: "https://x"}); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("channel: got %v, want %v", err, domain.ErrNotFound)
+		}
+		if err := r.Slack.SetSlackUser(ctx, domain.SlackUser{UserID: "nope", SlackID: "U1"}); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("user: got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("SetReplaceDelete", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true))
+		seedTeam(t, r, "mobile")
+		for _, c := range []domain.SlackChannel{
+			{TeamName: "mobile", WebhookURL: "https://hooks.example/m"},
+			{TeamName: "backend", WebhookURL: "https://hooks.example/old"},
+			{TeamName: "backend", WebhookURL: "https://hooks.example/b"},
+		} {
+			mustNoErr(t, r.Slack.SetSlackChannel(ctx, c))
+		}
+		mustNoErr(t, r.Slack.SetSlackUser(ctx, domain.SlackUser{UserID: "u2", SlackID: "U2"}))
+		mustNoErr(t, r.Slack.SetSlackUser(ctx, domain.SlackUser{UserID: "u1", SlackID: "U0"}))
+		mustNoErr(t, r.Slack.SetSlackUser(ctx, domain.SlackUser{UserID: "u1", SlackID: "U1"}))
+
+		got, err := r.Slack.GetSlackChannel(ctx, "backend")
+		mustNoErr(t, err)
+		if got.WebhookURL != "https://hooks.example/b" {
+			t.Fatalf("backend: got %+v", got)
+		}
+		channels, err := r.Slack.ListSlackChannels(ctx)
+		mustNoErr(t, err)
+		if len(channels) != 2 || channels[0] != got || channels[1].TeamName != "mobile" {
+			t.Fatalf("channels: got %+v", channels)
+		}
+		users, err := r.Slack.ListSlackUsers(ctx)
+		mustNoErr(t, err)
+		if want := []domain.SlackUser{{UserID: "u1", SlackID: "U1"}, {UserID: "u2", SlackID: "U2"}}; !slices.Equal(users, want) {
+			t.Fatalf("users: got %+v, want %+v", users, want)
+		}
+
+		mustNoErr(t, r.Slack.DeleteSlackChannel(ctx, "backend"))
+		if _, err := r.Slack.GetSlackChannel(ctx, "backend"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("get deleted: got %v, want %v", err, domain.ErrNotFound)
+		}
+		if err := r.Slack.DeleteSlackChannel(ctx, "backend"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("delete channel twice: got %v, want %v", err, domain.ErrNotFound)
+		}
+		mustNoErr(t, r.Slack.DeleteSlackUser(ctx, "u1"))
+		if err := r.Slack.DeleteSlackUser(ctx, "u1"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("delete user twice: got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("ScopedByOrg", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		_, err := r.Orgs.CreateOrg(ctx, domain.Organization{OrgID: "acme", Name: "Acme"})
+		mustNoErr(t, err)
+		acme := domain.WithOrg(ctx, "acme")
+
+		seedTeam(t, r, "backend", user("u1", true))
+		mustNoErr(t, r.Slack.SetSlackChannel(ctx, domain.SlackChannel{TeamName: "backend", WebhookURL: "https://hooks.example/b"}))
+		mustNoErr(t, r.Slack.SetSlackUser(ctx, domain.SlackUser{UserID: "u1", SlackID: "U1"}))
+
+		if err := r.Slack.SetSlackChannel(acme, domain.SlackChannel{TeamName: "backend", WebhookURL: "https://x"}); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("set for other org's team: got %v, want %v", err, domain.ErrNotFound)
+		}
+		if _, err := r.Slack.GetSlackChannel(acme, "backend"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("get from other org: got %v, want %v", err, domain.ErrNotFound)
+		}
+		if list, err := r.Slack.ListSlackUsers(acme); err != nil || len(list) != 0 {
+			t.Fatalf("users of other org: got %v, %v", list, err)
+		}
+	})
+}
+
+func RunEmailRepo(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	withEmail := func(id string, active bool) domain.User {
+		u := user(id, active)
+		u.Email = id + "@example.com"
+		return u
+	}
+	ids := func(items []domain.EmailNotification) []int64 {
+		out := make([]int64, 0, len(items))
+		for _, n := range items {
+			out = append(out, n.ID)
+		}
+		return out
+	}
+
+	t.Run("Prefs", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seedTeam(t, r, "backend", user("u1", true))
+
+		if _, err := r.Email.GetEmailPrefs(ctx, "u1"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("unset: got %v, want %v", err, domain.ErrNotFound)
+		}
+		if err := r.Email.SetEmailPrefs(ctx, domain.DefaultEmailPrefs("nope")); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("unknown user: got %v, want %v", err, domain.ErrNotFound)
+		}
+		mustNoErr(t, r.Email.SetEmailPrefs(ctx, domain.DefaultEmailPrefs("u1")))
+		want := domain.EmailPrefs{UserID: "u1", Reassigned: true}
+		mustNoErr(t, r.Email.SetEmailPrefs(ctx, want))
+		got, err := r.Email.GetEmailPrefs(ctx, "u1")
+		mustNoErr(t, err)
+		if got != want {
+			t.Fatalf("got %+v, want %+v", got, want)
+		}
+
+		_, err = r.Orgs.CreateOrg(ctx, domain.Organization{OrgID: "acme", Name: "Acme"})
+		mustNoErr(t, err)
+		if _, err := r.Email.GetEmailPrefs(domain.WithOrg(ctx, "acme"), "u1"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("other org: got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("ClaimPerUserAndLease", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		seedTeam(t, r, "backend", withEmail("u1", true), withEmail("u2", true))
+
+		pr := domain.EventPR{ID: "pr-1", Name: "Fix", AuthorID: "u3", Status: domain.StatusOpen, AssignedReviewers: []string{"u1", "u2"}}
+		for _, n := range []domain.EmailNotification{
+			{UserID: "u1", Kind: domain.EmailAssigned, PR: pr},
+			{UserID: "u2", Kind: domain.EmailAssigned, PR: pr},
+			{UserID: "u1", Kind: domain.EmailReassigned, PR: pr, OldReviewerID: "u3"},
+			{UserID: "u2", Kind: domain.EmailSLABreach, PR: pr},
+			{UserID: "u2", Kind: domain.EmailSLABreach, PR: pr},
+		} {
+			mustNoErr(t, r.Email.EnqueueEmail(ctx, n))
+		}
+		all, err := r.Email.ListEmails(ctx, domain.EmailPending)
+		mustNoErr(t, err)
+		if len(all) != 4 {
+			t.Fatalf("queued %d, want 4 (one SLA breach per user and PR)", len(all))
+		}
+		if n := all[2]; n.OrgID != domain.DefaultOrg || n.OldReviewerID != "u3" || n.PR.Name != "Fix" ||
+			!slices.Equal(n.PR.AssignedReviewers, pr.AssignedReviewers) || n.Attempts != 0 {
+			t.Fatalf("round trip: got %+v", n)
+		}
+
+		now := time.Now().UTC().Add(time.Second)
+		lease := now.Add(time.Minute)
+		got, err := r.Email.ClaimEmails(ctx, now.Add(-time.Hour), now, lease, 10)
+		mustNoErr(t, err)
+		if len(got) != 0 {
+			t.Fatalf("inside the batching window: claimed %v", ids(got))
+		}
+
+		got, err = r.Email.ClaimEmails(ctx, now, now, lease, 1)
+		mustNoErr(t, err)
+		if want := []int64{all[0].ID, all[2].ID}; !slices.Equal(ids(got), want) {
+			t.Fatalf("first claim: got %v, want %v", ids(got), want)
+		}
+		if !got[0].NextAttemptAt.Equal(lease) {
+			t.Fatalf("lease: got %v, want %v", got[0].NextAttemptAt, lease)
+		}
+		u2, err := r.Email.ClaimEmails(ctx, now, now, lease, 10)
+		mustNoErr(t, err)
+		if want := []int64{all[1].ID, all[3].ID}; !slices.Equal(ids(u2), want) {
+			t.Fatalf("second claim: got %v, want %v", ids(u2), want)
+		}
+		if got, err := r.Email.ClaimEmails(ctx, now, now, lease, 10); err != nil || len(got) != 0 {
+			t.Fatalf("everything leased: got %v, %v", ids(got), err)
+		}
+
+		for _, n := range got {
+			n.Status, n.Attempts = domain.EmailSent, 1
+			mustNoErr(t, r.Email.SaveEmail(ctx, n))
+		}
+		// One backing-off item holds back the rest of the user's email.
+		retry := u2[0]
+		retry.Attempts, retry.LastError, retry.NextAttemptAt = 1, "421 busy", lease.Add(time.Hour)
+		mustNoErr(t, r.Email.SaveEmail(ctx, retry))
+		later := lease.Add(time.Second)
+		if got, err := r.Email.ClaimEmails(ctx, later, later, later.Add(time.Minute), 10); err != nil || len(got) != 0 {
+			t.Fatalf("backing off: got %v, %v", ids(got), err)
+		}
+		later = lease.Add(2 * time.Hour)
+		got, err = r.Email.ClaimEmails(ctx, later, later, later.Add(time.Minute), 10)
+		mustNoErr(t, err)
+		if !slices.Equal(ids(got), ids(u2)) || got[0].LastError != "421 busy" || got[0].Attempts != 1 {
+			t.Fatalf("after backoff: got %+v", got)
+		}
+
+		sent, err := r.Email.ListEmails(ctx, domain.EmailSent)
+		mustNoErr(t, err)
+		if !slices.Equal(ids(sent), []int64{all[0].ID, all[2].ID}) {
+			t.Fatalf("sent: got %v", ids(sent))
+		}
+		if err := r.Email.SaveEmail(ctx, domain.EmailNotification{ID: 9999, Status: domain.EmailSent}); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("save missing: got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("SLABreaches", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		_, err := r.Orgs.CreateOrg(ctx, domain.Organization{OrgID: "acme", Name: "Acme"})
+		mustNoErr(t, err)
+		acme := domain.WithOrg(ctx, "acme")
+
+		seedTeam(t, r, "backend", withEmail("u1", true), withEmail("u2", true), user("u3", true), withEmail("u4", false))
+		mustNoErr(t, r.Email.SetEmailPrefs(ctx, domain.EmailPrefs{UserID: "u2", Assigned: true}))
+		_, err = r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u3"), []string{"u1", "u2", "u3", "u4"}, nil)
+		mustNoErr(t, err)
+		_, err = r.PRs.CreatePRWithReviewers(ctx, openPR("pr-2", "u3"), []string{"u1"}, nil)
+		mustNoErr(t, err)
+		_, err = r.PRs.SetMerged(ctx, "pr-2", 0, nil)
+		mustNoErr(t, err)
+
+		mustNoErr(t, r.Teams.CreateTeam(acme, "backend"))
+		mustNoErr(t, r.Teams.UpsertUsersToTeam(acme, "backend", []domain.User{withEmail("a1", true), user("a2", true)}))
+		_, err = r.PRs.CreatePRWithReviewers(acme, openPR("pr-1", "a2"), []string{"a1"}, nil)
+		mustNoErr(t, err)
+
+		got, err := r.Email.ListSLABreaches(ctx, time.Now().UTC().Add(-time.Hour), 10)
+		mustNoErr(t, err)
+		if len(got) != 0 {
+			t.Fatalf("nothing is overdue yet: got %+v", got)
+		}
+
+		now := time.Now().UTC().Add(time.Second)
+		got, err = r.Email.ListSLABreaches(ctx, now, 10)
+		mustNoErr(t, err)
+		if len(got) != 2 {
+			t.Fatalf("got %+v, want u1 and a1", got)
+		}
+		n := got[0]
+		if n.OrgID != domain.DefaultOrg || n.UserID != "u1" || n.Kind != domain.EmailSLABreach ||
+			n.PR.ID != "pr-1" || n.PR.Name != "pr-1 name" || n.PR.AuthorID != "u3" || n.PR.CreatedAt == nil {
+			t.Fatalf("first: got %+v", n)
+		}
+		if got[1].OrgID != "acme" || got[1].UserID != "a1" {
+			t.Fatalf("second: got %+v", got[1])
+		}
+		if first, err := r.Email.ListSLABreaches(ctx, now, 1); err != nil || len(first) != 1 || first[0].UserID != "u1" {
+			t.Fatalf("limit: got %+v, %v", first, err)
+		}
+
+		mustNoErr(t, r.Email.EnqueueEmail(ctx, n))
+		got, err = r.Email.ListSLABreaches(ctx, now, 10)
+		mustNoErr(t, err)
+		if len(got) != 1 || got[0].UserID != "a1" {
+			t.Fatalf("after queueing u1: got %+v", got)
+		}
+	})
+}
+
+func RunDigestRepo(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	withEmail := func(id string, active bool) domain.User {
+		u := user(id, active)
+		u.Email = id + "@example.com"
+		return u
+	}
+
+	t.Run("Recipients", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		_, err := r.Orgs.CreateOrg(ctx, domain.Organization{OrgID: "acme", Name: "Acme"})
+		mustNoErr(t, err)
+		acme := domain.WithOrg(ctx, "acme")
+
+		// u1 reviews an open PR; u2 turned the digest off; u3 has no address;
+		// u4 is inactive; u5 only reviews a merged PR.
+		u1 := withEmail("u1", true)
+		u1.Timezone = "Asia/Tokyo"
+		seedTeam(t, r, "backend", u1, withEmail("u2", true), user("u3", true), withEmail("u4", false), withEmail("u5", true))
+		prefs := domain.DefaultEmailPrefs("u2")
+		prefs.Digest = false
+		mustNoErr(t, r.Email.SetEmailPrefs(ctx, prefs))
+		_, err = r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u5"), []string{"u1", "u2", "u3", "u4"}, nil)
+		mustNoErr(t, err)
+		_, err = r.PRs.CreatePRWithReviewers(ctx, openPR("pr-2", "u1"), []string{"u5"}, nil)
+		mustNoErr(t, err)
+		_, err = r.PRs.SetMerged(ctx, "pr-2", 0, nil)
+		mustNoErr(t, err)
+
+		mustNoErr(t, r.Teams.CreateTeam(acme, "backend"))
+		mustNoErr(t, r.Teams.UpsertUsersToTeam(acme, "backend", []domain.User{withEmail("a1", true), user("a2", true)}))
+		_, err = r.PRs.CreatePRWithReviewers(acme, openPR("pr-1", "a2"), []string{"a1"}, nil)
+		mustNoErr(t, err)
+
+		got, err := r.Digest.ListDigestRecipients(ctx)
+		mustNoErr(t, err)
+		if len(got) != 2 {
+			t.Fatalf("got %+v, want a1 and u1", got)
+		}
+		if got[0].OrgID != "acme" || got[0].To.UserID != "a1" {
+			t.Fatalf("first: got %+v", got[0])
+		}
+		if d := got[1]; d.OrgID != domain.DefaultOrg || d.To.UserID != "u1" || d.To.Email != "u1@example.com" ||
+			d.To.Timezone != "Asia/Tokyo" || d.To.Username != "name-u1" {
+			t.Fatalf("second: got %+v", d)
+		}
+	})
+
+	t.Run("ClaimOncePerDay", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		_, err := r.Orgs.CreateOrg(ctx, domain.Organization{OrgID: "acme", Name: "Acme"})
+		mustNoErr(t, err)
+		seedTeam(t, r, "backend", withEmail("u1", true))
+		acme := domain.WithOrg(ctx, "acme")
+		mustNoErr(t, r.Teams.CreateTeam(acme, "backend"))
+		mustNoErr(t, r.Teams.UpsertUsersToTeam(acme, "backend", []domain.User{withEmail("u1", true)}))
+
+		claim := func(ctx context.Context, day string, want bool) {
+			t.Helper()
+			ok, err := r.Digest.ClaimDigest(ctx, "u1", day)
+			mustNoErr(t, err)
+			if ok != want {
+				t.Fatalf("claim %s: got %v, want %v", day, ok, want)
+			}
+		}
+		claim(ctx, "2026-03-01", true)
+		claim(ctx, "2026-03-01", false)
+		claim(ctx, "2026-03-02", true)
+		claim(acme, "2026-03-01", true)
+
+		mustNoErr(t, r.Digest.ReleaseDigest(ctx, "u1", "2026-03-01"))
+		claim(ctx, "2026-03-01", true)
+		mustNoErr(t, r.Digest.ReleaseDigest(ctx, "u1", "2000-01-01"))
+
+		if _, err := r.Digest.ClaimDigest(ctx, "ghost", "2026-03-01"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("unknown user: got %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+}
+
+func RunAuditRepo(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	list := func(t *testing.T, r Repos, ctx context.Context, f domain.AuditFilter) []domain.AuditEntry {
+		t.Helper()
+		if f.Limit == 0 {
+			f.Limit = 100
+		}
+		got, err := r.Audit.ListAudit(ctx, f)
+		mustNoErr(t, err)
+		return got
+	}
+	actions := func(entries []domain.AuditEntry) []domain.AuditAction {
+		out := make([]domain.AuditAction, 0, len(entries))
+		for _, e := range entries {
+			out = append(out, e.Action)
+		}
+		return out
+	}
+
+	t.Run("RecordsChanges", func(t *testing.T) {
+		r := newRepos(t)
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true), user("u3", true))
+		ctx := domain.WithRequestID(domain.WithPrincipal(context.Background(), domain.Principal{UserID: "u1"}), "req-1")
+
+		_, err := r.Users.SetActive(ctx, "u3", false, nil)
+		mustNoErr(t, err)
+		_, err = r.Users.SetActive(ctx, "u3", false, nil)
+		mustNoErr(t, err)
+		u2 := user("u2", true)
+		mustNoErr(t, r.Teams.UpsertUsersToTeam(ctx, "backend", []domain.User{user("u1", true), u2}))
+		u2.Email = "u2@example.com"
+		mustNoErr(t, r.Teams.UpsertUsersToTeam(ctx, "backend", []domain.User{u2}))
+		_, err = r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u1"), []string{"u2"}, nil)
+		mustNoErr(t, err)
+		_, err = r.PRs.ReplaceReviewer(ctx, "pr-1", "u2", "u3", 0, nil)
+		mustNoErr(t, err)
+		_, err = r.PRs.SetMerged(ctx, "pr-1", 0, nil)
+		mustNoErr(t, err)
+		_, err = r.PRs.SetMerged(ctx, "pr-1", 0, nil)
+		mustNoErr(t, err)
+
+		got := list(t, r, context.Background(), domain.AuditFilter{})
+		want := []domain.AuditAction{
+			domain.AuditPRMerged, domain.AuditPRReassigned, domain.AuditPRCreated,
+			domain.AuditUserUpserted, domain.AuditUserActivityChanged,
+			domain.AuditUserUpserted, domain.AuditUserUpserted, domain.AuditUserUpserted, domain.AuditTeamCreated,
+		}
+		if !slices.Equal(actions(got), want) {
+			t.Fatalf("actions: got %v, want %v", actions(got), want)
+		}
+		for i, e := range got {
+			if i > 0 && e.ID >= got[i-1].ID {
+				t.Fatalf("not newest first: %d after %d", e.ID, got[i-1].ID)
+			}
+			if e.OrgID != domain.DefaultOrg || e.CreatedAt.IsZero() {
+				t.Fatalf("entry %d: %+v", i, e)
+			}
+		}
+
+		reassigned := got[1]
+		if reassigned.EntityType != domain.EntityPullRequest || reassigned.EntityID != "pr-1" ||
+			reassigned.Actor != "u1" || reassigned.RequestID != "req-1" {
+			t.Fatalf("reassigned: %+v", reassigned)
+		}
+		var before, after struct {
+			Status    domain.PRStatus `json:"status"`
+			Reviewers []string        `json:"assigned_reviewers"`
+		}
+		mustNoErr(t, json.Unmarshal(reassigned.Before, &before))
+		mustNoErr(t, json.Unmarshal(reassigned.After, &after))
+		assertReviewers(t, before.Reviewers, "u2")
+		assertReviewers(t, after.Reviewers, "u3")
+		if merged := got[0]; !strings.Contains(string(merged.Before), `"OPEN"`) || !strings.Contains(string(merged.After), `"MERGED"`) {
+			t.Fatalf("merged: before %s, after %s", merged.Before, merged.After)
+		}
+		if created := got[2]; created.Before != nil || created.After == nil {
+			t.Fatalf("created: before %s, after %s", created.Before, created.After)
+		}
+		if upserted := got[3]; upserted.EntityID != "u2" || strings.Contains(string(upserted.Before), "u2@example.com") ||
+			!strings.Contains(string(upserted.After), "u2@example.com") {
+			t.Fatalf("upserted: %+v", upserted)
+		}
+		if seeded := got[len(got)-1]; seeded.EntityType != domain.EntityTeam || seeded.EntityID != "backend" ||
+			seeded.Actor != "" || seeded.RequestID != "" {
+			t.Fatalf("team created: %+v", seeded)
+		}
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		_, err := r.Orgs.CreateOrg(ctx, domain.Organization{OrgID: "acme", Name: "Acme"})
+		mustNoErr(t, err)
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true))
+		mustNoErr(t, r.Teams.CreateTeam(domain.WithOrg(ctx, "acme"), "backend"))
+
+		bot := domain.WithPrincipal(ctx, domain.Principal{TokenID: "t1", Name: "ci"})
+		_, err = r.PRs.CreatePRWithReviewers(bot, openPR("pr-1", "u1"), []string{"u2"}, nil)
+		mustNoErr(t, err)
+		_, err = r.Users.SetActive(bot, "u2", false, nil)
+		mustNoErr(t, err)
+
+		all := list(t, r, ctx, domain.AuditFilter{})
+		if len(all) != 5 {
+			t.Fatalf("all: got %v", actions(all))
+		}
+		if got := list(t, r, ctx, domain.AuditFilter{EntityType: domain.EntityUser, EntityID: "u2"}); !slices.Equal(actions(got),
+			[]domain.AuditAction{domain.AuditUserActivityChanged, domain.AuditUserUpserted}) {
+			t.Fatalf("u2: got %v", actions(got))
+		}
+		if got := list(t, r, ctx, domain.AuditFilter{Actor: "token:t1"}); len(got) != 2 {
+			t.Fatalf("by token: got %v", actions(got))
+		}
+		if got := list(t, r, ctx, domain.AuditFilter{Limit: 2}); len(got) != 2 || got[0].ID != all[0].ID {
+			t.Fatalf("limit: got %v", actions(got))
+		}
+		if got := list(t, r, ctx, domain.AuditFilter{BeforeID: all[1].ID}); len(got) != 3 || got[0].ID != all[2].ID {
+			t.Fatalf("before id: got %v", actions(got))
+		}
+		if got := list(t, r, ctx, domain.AuditFilter{From: all[1].CreatedAt, To: all[0].CreatedAt}); len(got) != 1 || got[0].ID != all[1].ID {
+			t.Fatalf("time window: got %v", actions(got))
+		}
+		if got := list(t, r, domain.WithOrg(ctx, "acme"), domain.AuditFilter{}); len(got) != 1 || got[0].Action != domain.AuditTeamCreated {
+			t.Fatalf("acme: got %v", actions(got))
+		}
+	})
+
+	t.Run("Chain", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		acme := domain.WithOrg(ctx, "acme")
+		_, err := r.Orgs.CreateOrg(ctx, domain.Organization{OrgID: "acme", Name: "Acme"})
+		mustNoErr(t, err)
+		seedTeam(t, r, "backend", user("u1", true), user("u2", true), user("u3", true))
+		mustNoErr(t, r.Teams.CreateTeam(acme, "backend"))
+		_, err = r.PRs.CreatePRWithReviewers(ctx, openPR("pr-1", "u1"), []string{"u2"}, nil)
+		mustNoErr(t, err)
+		_, err = r.PRs.ReplaceReviewer(ctx, "pr-1", "u2", "u3", 0, nil)
+		mustNoErr(t, err)
+		_, err = r.PRs.SetMerged(ctx, "pr-1", 0, nil)
+		mustNoErr(t, err)
+
+		var (
+			report domain.AuditChainReport
+			after  int64
+		)
+		for {
+			page, err := r.Audit.ListAuditChain(ctx, after, 2)
+			mustNoErr(t, err)
+			if len(page) == 0 {
+				break
+			}
+			for _, e := range page {
+				if e.ID <= after {
+					t.Fatalf("not oldest first: %d after %d", e.ID, after)
+				}
+				report.Next(e)
+				after = e.ID
+			}
+		}
+		if report.Broken != nil || report.Checked != 7 || report.Unchained != 0 {
+			t.Fatalf("default chain: %+v, broken %+v", report, report.Broken)
+		}
+		newest := list(t, r, ctx, domain.AuditFilter{Limit: 1})
+		if report.HeadID != newest[0].ID || report.Head != newest[0].Hash {
+			t.Fatalf("head: got %d %s, want %d %s", report.HeadID, report.Head, newest[0].ID, newest[0].Hash)
+		}
+
+		page, err := r.Audit.ListAuditChain(acme, 0, 10)
+		mustNoErr(t, err)
+		if len(page) != 1 || page[0].PrevHash != "" || page[0].Hash != page[0].ChainHash() {
+			t.Fatalf("acme chain: %+v", page)
+		}
+	})
+}
+
+func seedTeam(t *testing.T, r Repos, teamName string, members ...domain.User) {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := r.Teams.CreateTeam(ctx, teamName); err != nil {
+		t.Fatalf("create team %s: %v", teamName, err)
+	}
+	if err := r.Teams.UpsertUsersToTeam(ctx, teamName, members); err != nil {
+		t.Fatalf("upsert members of %s: %v", teamName, err)
+	}
+}
+
+func user(id string, active bool) domain.User {
+	return domain.User{UserID: id, Username: "name-" + id, IsActive: active}
+}
+
+func openPR(id, authorID string) domain.PullRequest {
+	return domain.PullRequest{ID: id, Name: id + " name", AuthorID: authorID, Status: domain.StatusOpen}
+}
+
+func userIDs(users []domain.User) []string {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.UserID)
+	}
+	return ids
+}
+
+func assertReviewers(t *testing.T, got []string, want ...string) {
+	t.Helper()
+
+	sorted := slices.Clone(got)
+	slices.Sort(sorted)
+	slices.Sort(want)
+	if !slices.Equal(sorted, want) {
+		t.Fatalf("reviewers: got %v, want %v", got, want)
+	}
+}
+
+func mustNoErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func RunIdempotencyRepo(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	t.Run("ReserveCompleteRelease", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		expired, stale := time.Now().Add(-time.Hour), time.Now().Add(-time.Hour)
+		rec := domain.IdempotencyRecord{Owner: "token:t1", Key: "k1", Lease: "l1", RequestHash: "h1"}
+
+		got, fresh, err := r.Idempotency.ReserveIdempotencyKey(ctx, rec, expired, stale)
+		mustNoErr(t, err)
+		if !fresh || got.Completed() || got.CreatedAt.IsZero() || got.Lease != "l1" {
+			t.Fatalf("first reserve: got %+v, %v", got, fresh)
+		}
+		second := rec
+		second.Lease, second.RequestHash = "l2", "h2"
+		got, fresh, err = r.Idempotency.ReserveIdempotencyKey(ctx, second, expired, stale)
+		mustNoErr(t, err)
+		if fresh || got.RequestHash != "h1" || got.Lease != "l1" || got.Completed() {
+			t.Fatalf("in progress: got %+v, %v", got, fresh)
+		}
+
+		rec.Status, rec.ContentType, rec.Body = 201, "application/json", []byte(`{"ok":true}`)
+		rec.Header = map[string]string{"ETag": `"1"`, "Location": "/Users/u1"}
+		mustNoErr(t, r.Idempotency.CompleteIdempotencyKey(ctx, rec))
+		got, fresh, err = r.Idempotency.ReserveIdempotencyKey(ctx, second, expired, stale)
+		mustNoErr(t, err)
+		if fresh || got.Status != 201 || got.ContentType != "application/json" || string(got.Body) != string(rec.Body) ||
+			!maps.Equal(got.Header, rec.Header) {
+			t.Fatalf("completed: got %+v, %v", got, fresh)
+		}
+
+		// The same key of another owner or organization is another record.
+		other := domain.IdempotencyRecord{Owner: "u1", Key: "k1", Lease: "l3", RequestHash: "h3"}
+		_, fresh, err = r.Idempotency.ReserveIdempotencyKey(ctx, other, expired, stale)
+		mustNoErr(t, err)
+		if !fresh {
+			t.Fatal("other owner: key already taken")
+		}
+		_, fresh, err = r.Idempotency.ReserveIdempotencyKey(domain.WithOrg(ctx, "acme"), second, expired, stale)
+		mustNoErr(t, err)
+		if !fresh {
+			t.Fatal("other org: key already taken")
+		}
+
+		// Only the holder of the lease releases the key.
+		mustNoErr(t, r.Idempotency.ReleaseIdempotencyKey(ctx, "token:t1", "k1", "l2"))
+		if _, fresh, _ = r.Idempotency.ReserveIdempotencyKey(ctx, second, expired, stale); fresh {
+			t.Fatal("released with another lease")
+		}
+		mustNoErr(t, r.Idempotency.ReleaseIdempotencyKey(ctx, "token:t1", "k1", "l1"))
+		got, fresh, err = r.Idempotency.ReserveIdempotencyKey(ctx, second, expired, stale)
+		mustNoErr(t, err)
+		if !fresh || got.RequestHash != "h2" {
+			t.Fatalf("after release: got %+v, %v", got, fresh)
+		}
+	})
+
+	t.Run("Takeover", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		expired := time.Now().Add(-time.Hour)
+		rec := domain.IdempotencyRecord{Owner: "token:t1", Key: "k1", Lease: "l1", RequestHash: "h1"}
+
+		_, _, err := r.Idempotency.ReserveIdempotencyKey(ctx, rec, expired, expired)
+		mustNoErr(t, err)
+		stale := time.Now().Add(time.Second)
+
+		// Another request under the key waits, however old the first one.
+		other := domain.IdempotencyRecord{Owner: "token:t1", Key: "k1", Lease: "l2", RequestHash: "h2"}
+		got, fresh, err := r.Idempotency.ReserveIdempotencyKey(ctx, other, expired, stale)
+		mustNoErr(t, err)
+		if fresh || got.Lease != "l1" {
+			t.Fatalf("other request: got %+v, %v", got, fresh)
+		}
+
+		retry := rec
+		retry.Lease = "l3"
+		got, fresh, err = r.Idempotency.ReserveIdempotencyKey(ctx, retry, expired, stale)
+		mustNoErr(t, err)
+		if !fresh || got.Lease != "l3" {
+			t.Fatalf("takeover: got %+v, %v", got, fresh)
+		}
+
+		// The request that lost its lease no longer writes.
+		rec.Status, rec.Body = 500, []byte("late")
+		mustNoErr(t, r.Idempotency.CompleteIdempotencyKey(ctx, rec))
+		mustNoErr(t, r.Idempotency.ReleaseIdempotencyKey(ctx, "token:t1", "k1", "l1"))
+		retry.Status, retry.Body = 201, []byte("ok")
+		mustNoErr(t, r.Idempotency.CompleteIdempotencyKey(ctx, retry))
+		got, fresh, err = r.Idempotency.ReserveIdempotencyKey(ctx, rec, expired, stale)
+		mustNoErr(t, err)
+		if fresh || got.Status != 201 || string(got.Body) != "ok" {
+			t.Fatalf("after takeover: got %+v, %v", got, fresh)
+		}
+	})
+
+	t.Run("Expiry", func(t *testing.T) {
+		r := newRepos(t)
+		ctx := context.Background()
+		stale := time.Now().Add(-time.Hour)
+		rec := domain.IdempotencyRecord{Owner: "token:t1", Key: "k1", Lease: "l1", RequestHash: "h1"}
+
+		_, _, err := r.Idempotency.ReserveIdempotencyKey(ctx, rec, time.Now().Add(-time.Hour), stale)
+		mustNoErr(t, err)
+		rec.Status = 200
+		mustNoErr(t, r.Idempotency.CompleteIdempotencyKey(ctx, rec))
+		rec.Lease, rec.RequestHash = "l2", "h2"
+		got, fresh, err := r.Idempotency.ReserveIdempotencyKey(ctx, rec, time.Now().Add(time.Second), stale)
+		mustNoErr(t, err)
+		if !fresh || got.RequestHash != "h2" {
+			t.Fatalf("expired key: got %+v, %v", got, fresh)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type AuditRepo struct{ db *sql.DB }
+
+func NewAuditRepo(db *sql.DB) *AuditRepo { return &AuditRepo{db: db} }
+
+const auditColumns = `audit_id, org_id, action, entity_type, entity_id, actor, request_id, before, after, created_at,
+	prev_hash, hash`
+
+// insertAudit runs in the transaction of the change e describes and links e
+// to the last entry of its organization; the single connection keeps other
+// writers out until the transaction ends. Entries whose state did not
+// change are dropped.
+func insertAudit(ctx context.Context, q querier, e domain.AuditEntry) error {
+	if e.Before != nil && bytes.Equal(e.Before, e.After) {
+		return nil
+	}
+	err := q.QueryRowContext(ctx,
+		`SELECT hash FROM audit_log WHERE org_id = ? ORDER BY audit_id DESC LIMIT 1`, e.OrgID,
+	).Scan(&e.PrevHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	e.CreatedAt = time.Now().UTC()
+	e.Hash = e.ChainHash()
+	_, err = q.ExecContext(ctx, `
+		INSERT INTO audit_log (org_id, action, entity_type, entity_id, actor, request_id, before, after, created_at,
+			prev_hash, hash)
+		VALUES (?,?,?,?,?,?,?,?,?,?,?)`,
+		e.OrgID, e.Action, e.EntityType, e.EntityID, e.Actor, e.RequestID, nullJSON(e.Before), nullJSON(e.After),
+		formatTime(e.CreatedAt), e.PrevHash, e.Hash)
+	return err
+}
+
+func nullJSON(b []byte) any {
+	if b == nil {
+		return nil
+	}
+	return string(b)
+}
+
+func (r *AuditRepo) ListAudit(ctx context.Context, f domain.AuditFilter) ([]domain.AuditEntry, error) {
+	var from, to string
+	if !f.From.IsZero() {
+		from = formatTime(f.From)
+	}
+	if !f.To.IsZero() {
+		to = formatTime(f.To)
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+auditColumns+` FROM audit_log
+		WHERE org_id = ?1
+		  AND (?2 = '' OR entity_type = ?2)
+		  AND (?3 = '' OR entity_id = ?3)
+		  AND (?4 = '' OR actor = ?4)
+		  AND (?5 = '' OR created_at >= ?5)
+		  AND (?6 = '' OR created_at < ?6)
+		  AND (?7 = 0 OR audit_id < ?7)
+		ORDER BY audit_id DESC
+		LIMIT ?8`,
+		domain.OrgFromContext(ctx), f.EntityType, f.EntityID, f.Actor, from, to, f.BeforeID, f.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []domain.AuditEntry
+	for rows.Next() {
+		e, err := scanAudit(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (r *AuditRepo) ListAuditChain(ctx context.Context, afterID int64, limit int) ([]domain.AuditEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+auditColumns+` FROM audit_log
+		WHERE org_id = ? AND audit_id > ?
+		ORDER BY audit_id
+		LIMIT ?`, domain.OrgFromContext(ctx), afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []domain.AuditEntry
+	for rows.Next() {
+		e, err := scanAudit(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func scanAudit(row scanner) (domain.AuditEntry, error) {
+	var (
+		e             domain.AuditEntry
+		before, after sql.NullString
+		created       string
+	)
+	if err := row.Scan(&e.ID, &e.OrgID, &e.Action, &e.EntityType, &e.EntityID, &e.Actor, &e.RequestID,
+		&before, &after, &created, &e.PrevHash, &e.Hash); err != nil {
+		return domain.AuditEntry{}, err
+	}
+	if before.Valid {
+		e.Before = []byte(before.String)
+	}
+	if after.Valid {
+		e.After = []byte(after.String)
+	}
+	t, err := parseTime(created)
+	if err != nil {
+		return domain.AuditEntry{}, err
+	}
+	e.CreatedAt = *t
+	return e, nil
+}
@@ -0,0 +1,40 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/repotest"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/sqlite"
+)
+
+func TestContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repos {
+		t.Helper()
+
+		db, err := sqlite.Connect(context.Background(), filepath.Join(t.TempDir(), "pr.db"))
+		if err != nil {
+			t.Fatalf("connect: %v", err)
+		}
+		t.Cleanup(func() { _ = db.Close() })
+
+		return repotest.Repos{
+			Teams:       sqlite.NewTeamRepo(db),
+			Users:       sqlite.NewUserRepo(db),
+			PRs:         sqlite.NewPRRepo(db),
+			Orgs:        sqlite.NewOrgRepo(db),
+			Deliveries:  sqlite.NewDeliveryRepo(db),
+			GitLab:      sqlite.NewGitLabProjectRepo(db),
+			Syncs:       sqlite.NewReviewerSyncRepo(db),
+			Subs:        sqlite.NewSubscriptionRepo(db),
+			Events:      sqlite.NewEventDeliveryRepo(db),
+			Outbox:      sqlite.NewOutboxRepo(db),
+			Slack:       sqlite.NewSlackRepo(db),
+			Email:       sqlite.NewEmailRepo(db),
+			Digest:      sqlite.NewDigestRepo(db),
+			Audit:       sqlite.NewAuditRepo(db),
+			Idempotency: sqlite.NewIdempotencyRepo(db),
+		}
+	})
+}
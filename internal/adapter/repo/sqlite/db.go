@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+//go:embed migrations/*.up.sql
+var migrations embed.FS
+
+// timeLayout is fixed width so that text comparison orders timestamps.
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// Connect opens the database file and applies pending migrations.
+//
+// SQLite allows a single writer, so the pool is limited to one connection:
+// requests queue in database/sql instead of failing with SQLITE_BUSY, and
+// ":memory:" databases stay alive between calls.
+func Connect(ctx context.Context, dsn string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxIdleTime(0)
+	db.SetConnMaxLifetime(0)
+
+	ctxInit, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	for _, pragma := range []string{
+		`PRAGMA foreign_keys = ON`,
+		`PRAGMA journal_mode = WAL`,
+		`PRAGMA busy_timeout = 5000`,
+	} {
+		if _, err := db.ExecContext(ctxInit, pragma); err != nil {
+			closeDB(db)
+			return nil, fmt.Errorf("sqlite: %s: %w", pragma, err)
+		}
+	}
+
+	if err := migrate(ctxInit, db); err != nil {
+		closeDB(db)
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    TEXT PRIMARY KEY,
+			applied_at TEXT NOT NULL
+		)`); err != nil {
+		return err
+	}
+
+	names, err := fs.Glob(migrations, "migrations/*.up.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		version := strings.TrimSuffix(strings.TrimPrefix(name, "migrations/"), ".up.sql")
+		if err := applyMigration(ctx, db, name, version); err != nil {
+			return fmt.Errorf("sqlite: migration %s: %w", version, err)
+		}
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, name, version string) error {
+	body, err := migrations.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx, "applyMigration")
+
+	var applied bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version=?)`, version,
+	).Scan(&applied); err != nil {
+		return err
+	}
+	if applied {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, string(body)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, version, now(),
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func now() string {
+	return formatTime(time.Now())
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func parseTime(s string) (*time.Time, error) {
+	t, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func rollback(tx *sql.Tx, op string) {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		log.Printf("sqlite: rollback failed in %s: %v", op, err)
+	}
+}
+
+func closeDB(db *sql.DB) {
+	if err := db.Close(); err != nil {
+		log.Printf("sqlite: close failed: %v", err)
+	}
+}
+
+func isUniqueViolation(err error) bool {
+	var serr *sqlite.Error
+	if errors.As(err, &serr) {
+		code := serr.Code()
+		return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	}
+	return false
+}
+
+func isForeignKeyViolation(err error) bool {
+	var serr *sqlite.Error
+	return errors.As(err, &serr) && serr.Code() == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY
+}
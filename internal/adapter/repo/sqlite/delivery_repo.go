@@ -0,0 +1,30 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+)
+
+type DeliveryRepo struct{ db *sql.DB }
+
+func NewDeliveryRepo(db *sql.DB) *DeliveryRepo { return &DeliveryRepo{db: db} }
+
+func (r *DeliveryRepo) ClaimDelivery(ctx context.Context, source, deliveryID string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (source, delivery_id, received_at) VALUES (?,?,?)
+		ON CONFLICT DO NOTHING`, source, deliveryID, now())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *DeliveryRepo) ReleaseDelivery(ctx context.Context, source, deliveryID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM webhook_deliveries WHERE source=? AND delivery_id=?`, source, deliveryID)
+	return err
+}
@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type DigestRepo struct{ db *sql.DB }
+
+func NewDigestRepo(db *sql.DB) *DigestRepo { return &DigestRepo{db: db} }
+
+func (r *DigestRepo) ListDigestRecipients(ctx context.Context) ([]domain.ReviewDigest, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.org_id, u.user_id, u.username, u.team_name, u.is_active, u.role, u.email, u.timezone, u.away_until
+		FROM users u
+		LEFT JOIN email_prefs p ON p.org_id = u.org_id AND p.user_id = u.user_id
+		WHERE u.email <> '' AND u.is_active AND COALESCE(p.daily_digest, 1)
+		  AND EXISTS (
+			SELECT 1 FROM pr_reviewers r
+			JOIN pull_requests pr ON pr.org_id = r.org_id AND pr.pull_request_id = r.pull_request_id
+			WHERE r.org_id = u.org_id AND r.reviewer_id = u.user_id AND pr.status = 'OPEN')
+		ORDER BY u.org_id, u.user_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []domain.ReviewDigest
+	for rows.Next() {
+		var (
+			d    domain.ReviewDigest
+			away sql.NullString
+		)
+		u := &d.To
+		if err := rows.Scan(&d.OrgID, &u.UserID, &u.Username, &u.TeamName, &u.IsActive, &u.Role,
+			&u.Email, &u.Timezone, &away); err != nil {
+			return nil, err
+		}
+		if away.Valid {
+			if u.AwayUntil, err = parseTime(away.String); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (r *DigestRepo) ClaimDigest(ctx context.Context, userID, day string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO review_digests (org_id, user_id, day, sent_at) VALUES (?,?,?,?)
+		ON CONFLICT DO NOTHING`, domain.OrgFromContext(ctx), userID, day, now())
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return false, domain.ErrNotFound
+		}
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *DigestRepo) ReleaseDigest(ctx context.Context, userID, day string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM review_digests WHERE org_id=? AND user_id=? AND day=?`,
+		domain.OrgFromContext(ctx), userID, day)
+	return err
+}
@@ -0,0 +1,203 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type EmailRepo struct{ db *sql.DB }
+
+func NewEmailRepo(db *sql.DB) *EmailRepo { return &EmailRepo{db: db} }
+
+const emailColumns = `notification_id, org_id, user_id, kind, payload,
+	status, attempts, last_error, next_attempt_at, created_at, updated_at`
+
+// emailPayload is the JSON stored in email_notifications.payload.
+type emailPayload struct {
+	PR            domain.EventPR `json:"pull_request"`
+	OldReviewerID string         `json:"old_reviewer_id,omitempty"`
+}
+
+func (r *EmailRepo) GetEmailPrefs(ctx context.Context, userID string) (domain.EmailPrefs, error) {
+	p := domain.EmailPrefs{UserID: userID}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT assigned, reassigned, sla_breach, daily_digest FROM email_prefs
+		WHERE org_id=? AND user_id=?`, domain.OrgFromContext(ctx), userID,
+	).Scan(&p.Assigned, &p.Reassigned, &p.SLABreach, &p.Digest)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.EmailPrefs{}, domain.ErrNotFound
+		}
+		return domain.EmailPrefs{}, err
+	}
+	return p, nil
+}
+
+func (r *EmailRepo) SetEmailPrefs(ctx context.Context, p domain.EmailPrefs) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO email_prefs (org_id, user_id, assigned, reassigned, sla_breach, daily_digest) VALUES (?,?,?,?,?,?)
+		ON CONFLICT (org_id, user_id) DO UPDATE
+		  SET assigned=excluded.assigned, reassigned=excluded.reassigned, sla_breach=excluded.sla_breach,
+		      daily_digest=excluded.daily_digest`,
+		domain.OrgFromContext(ctx), p.UserID, p.Assigned, p.Reassigned, p.SLABreach, p.Digest)
+	if isForeignKeyViolation(err) {
+		return domain.ErrNotFound
+	}
+	return err
+}
+
+func (r *EmailRepo) EnqueueEmail(ctx context.Context, n domain.EmailNotification) error {
+	payload, err := json.Marshal(emailPayload{PR: n.PR, OldReviewerID: n.OldReviewerID})
+	if err != nil {
+		return err
+	}
+	ts := now()
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO email_notifications (org_id, user_id, kind, pull_request_id, payload, next_attempt_at, created_at, updated_at)
+		VALUES (?,?,?,?,?,?,?,?)
+		ON CONFLICT DO NOTHING`,
+		domain.OrgFromContext(ctx), n.UserID, n.Kind, n.PR.ID, string(payload), ts, ts, ts)
+	return err
+}
+
+func (r *EmailRepo) ClaimEmails(ctx context.Context, queuedBefore, now, leaseUntil time.Time, limit int) ([]domain.EmailNotification, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rollback(tx, "ClaimEmails")
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+emailColumns+` FROM email_notifications
+		WHERE status = 'pending'
+		  AND (org_id, user_id) IN (
+			SELECT org_id, user_id FROM email_notifications
+			WHERE status = 'pending'
+			GROUP BY org_id, user_id
+			HAVING MIN(created_at) <= ?1 AND MAX(next_attempt_at) <= ?2
+			ORDER BY MIN(created_at)
+			LIMIT ?3)
+		ORDER BY notification_id`, formatTime(queuedBefore), formatTime(now), limit)
+	if err != nil {
+		return nil, err
+	}
+	out, err := collectEmails(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	lease := formatTime(leaseUntil)
+	for i := range out {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE email_notifications SET next_attempt_at=? WHERE notification_id=?`, lease, out[i].ID,
+		); err != nil {
+			return nil, err
+		}
+		out[i].NextAttemptAt = leaseUntil.UTC()
+	}
+	return out, tx.Commit()
+}
+
+func (r *EmailRepo) SaveEmail(ctx context.Context, n domain.EmailNotification) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE email_notifications
+		SET status=?, attempts=?, last_error=?, next_attempt_at=?, updated_at=?
+		WHERE notification_id=?`, n.Status, n.Attempts, n.LastError, formatTime(n.NextAttemptAt), now(), n.ID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *EmailRepo) ListEmails(ctx context.Context, status domain.EmailStatus) ([]domain.EmailNotification, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+emailColumns+` FROM email_notifications
+		WHERE ?1 = '' OR status = ?1
+		ORDER BY notification_id`, status)
+	if err != nil {
+		return nil, err
+	}
+	return collectEmails(rows)
+}
+
+func (r *EmailRepo) ListSLABreaches(ctx context.Context, createdBefore time.Time, limit int) ([]domain.EmailNotification, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT pr.org_id, r.reviewer_id, pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.created_at
+		FROM pull_requests pr
+		JOIN pr_reviewers r ON r.org_id = pr.org_id AND r.pull_request_id = pr.pull_request_id
+		JOIN users u ON u.org_id = r.org_id AND u.user_id = r.reviewer_id
+		LEFT JOIN email_prefs p ON p.org_id = u.org_id AND p.user_id = u.user_id
+		WHERE pr.status = 'OPEN' AND pr.created_at <= ?
+		  AND u.email <> '' AND u.is_active = 1 AND COALESCE(p.sla_breach, 1) = 1
+		  AND NOT EXISTS (
+			SELECT 1 FROM email_notifications e
+			WHERE e.org_id = r.org_id AND e.user_id = r.reviewer_id
+			  AND e.pull_request_id = pr.pull_request_id AND e.kind = 'sla_breach')
+		ORDER BY pr.created_at, pr.org_id, pr.pull_request_id, r.reviewer_id
+		LIMIT ?`, formatTime(createdBefore), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []domain.EmailNotification
+	for rows.Next() {
+		n := domain.EmailNotification{Kind: domain.EmailSLABreach, Status: domain.EmailPending}
+		var created string
+		if err := rows.Scan(&n.OrgID, &n.UserID, &n.PR.ID, &n.PR.Name, &n.PR.AuthorID, &created); err != nil {
+			return nil, err
+		}
+		if n.PR.CreatedAt, err = parseTime(created); err != nil {
+			return nil, err
+		}
+		n.PR.Status = domain.StatusOpen
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
+
+func collectEmails(rows *sql.Rows) ([]domain.EmailNotification, error) {
+	defer closeRows(rows)
+
+	var out []domain.EmailNotification
+	for rows.Next() {
+		var (
+			n                      domain.EmailNotification
+			payload                string
+			next, created, updated string
+		)
+		if err := rows.Scan(&n.ID, &n.OrgID, &n.UserID, &n.Kind, &payload,
+			&n.Status, &n.Attempts, &n.LastError, &next, &created, &updated); err != nil {
+			return nil, err
+		}
+		var p emailPayload
+		if err := json.Unmarshal([]byte(payload), &p); err != nil {
+			return nil, err
+		}
+		n.PR, n.OldReviewerID = p.PR, p.OldReviewerID
+		for _, f := range []struct {
+			src string
+			dst *time.Time
+		}{{next, &n.NextAttemptAt}, {created, &n.CreatedAt}, {updated, &n.UpdatedAt}} {
+			t, err := parseTime(f.src)
+			if err != nil {
+				return nil, err
+			}
+			*f.dst = *t
+		}
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
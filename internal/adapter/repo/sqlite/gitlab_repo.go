@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type GitLabProjectRepo struct{ db *sql.DB }
+
+func NewGitLabProjectRepo(db *sql.DB) *GitLabProjectRepo { return &GitLabProjectRepo{db: db} }
+
+func (r *GitLabProjectRepo) SetGitLabProject(ctx context.Context, p domain.GitLabProject) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO gitlab_projects (project_id, org_id, team_name) VALUES (?,?,?)
+		ON CONFLICT (project_id) DO UPDATE
+		  SET org_id=excluded.org_id, team_name=excluded.team_name`,
+		p.ProjectID, p.OrgID, p.TeamName)
+	if isForeignKeyViolation(err) {
+		return domain.ErrNotFound
+	}
+	return err
+}
+
+func (r *GitLabProjectRepo) GetGitLabProject(ctx context.Context, projectID int64) (domain.GitLabProject, error) {
+	var p domain.GitLabProject
+	err := r.db.QueryRowContext(ctx,
+		`SELECT project_id, org_id, team_name FROM gitlab_projects WHERE project_id=?`, projectID,
+	).Scan(&p.ProjectID, &p.OrgID, &p.TeamName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.GitLabProject{}, domain.ErrNotFound
+		}
+		return domain.GitLabProject{}, err
+	}
+	return p, nil
+}
+
+func (r *GitLabProjectRepo) DeleteGitLabProject(ctx context.Context, projectID int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM gitlab_projects WHERE project_id=?`, projectID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *GitLabProjectRepo) ListGitLabProjects(ctx context.Context) ([]domain.GitLabProject, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT project_id, org_id, team_name FROM gitlab_projects ORDER BY project_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []domain.GitLabProject
+	for rows.Next() {
+		var p domain.GitLabProject
+		if err := rows.Scan(&p.ProjectID, &p.OrgID, &p.TeamName); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type IdempotencyRepo struct{ db *sql.DB }
+
+func NewIdempotencyRepo(db *sql.DB) *IdempotencyRepo { return &IdempotencyRepo{db: db} }
+
+// ReserveIdempotencyKey also drops the expired keys of every organization;
+// the created_at index keeps that cheap.
+func (r *IdempotencyRepo) ReserveIdempotencyKey(ctx context.Context, rec domain.IdempotencyRecord, expiredBefore, staleBefore time.Time) (domain.IdempotencyRecord, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	defer rollback(tx, "ReserveIdempotencyKey")
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE created_at < ?`, formatTime(expiredBefore)); err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	org := domain.OrgFromContext(ctx)
+	rec.Status, rec.ContentType, rec.Header, rec.Body = 0, "", nil, nil
+	rec.CreatedAt = time.Now().UTC()
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (org_id, owner, idempotency_key, request_hash, lease, created_at)
+		VALUES (?,?,?,?,?,?)
+		ON CONFLICT DO NOTHING`, org, rec.Owner, rec.Key, rec.RequestHash, rec.Lease, formatTime(rec.CreatedAt))
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	if n == 1 {
+		return rec, true, tx.Commit()
+	}
+
+	res, err = tx.ExecContext(ctx, `
+		UPDATE idempotency_keys SET lease=?, created_at=?
+		WHERE org_id=? AND owner=? AND idempotency_key=?
+		  AND request_hash=? AND status=0 AND created_at < ?`,
+		rec.Lease, formatTime(rec.CreatedAt), org, rec.Owner, rec.Key, rec.RequestHash, formatTime(staleBefore))
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	if n, err = res.RowsAffected(); err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	if n == 1 {
+		return rec, true, tx.Commit()
+	}
+
+	var (
+		got     = domain.IdempotencyRecord{Owner: rec.Owner, Key: rec.Key}
+		header  string
+		created string
+	)
+	if err := tx.QueryRowContext(ctx, `
+		SELECT lease, request_hash, status, content_type, headers, body, created_at
+		FROM idempotency_keys
+		WHERE org_id=? AND owner=? AND idempotency_key=?`, org, rec.Owner, rec.Key,
+	).Scan(&got.Lease, &got.RequestHash, &got.Status, &got.ContentType, &header, &got.Body, &created); err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	t, err := parseTime(created)
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	got.CreatedAt = *t
+	if err := json.Unmarshal([]byte(header), &got.Header); err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	return got, false, tx.Commit()
+}
+
+func (r *IdempotencyRepo) CompleteIdempotencyKey(ctx context.Context, rec domain.IdempotencyRecord) error {
+	header, err := json.Marshal(rec.Header)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE idempotency_keys SET status=?, content_type=?, headers=?, body=?
+		WHERE org_id=? AND owner=? AND idempotency_key=? AND lease=?`,
+		rec.Status, rec.ContentType, string(header), rec.Body, domain.OrgFromContext(ctx), rec.Owner, rec.Key, rec.Lease)
+	return err
+}
+
+func (r *IdempotencyRepo) ReleaseIdempotencyKey(ctx context.Context, owner, key, lease string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE org_id=? AND owner=? AND idempotency_key=? AND lease=?`,
+		domain.OrgFromContext(ctx), owner, key, lease)
+	return err
+}
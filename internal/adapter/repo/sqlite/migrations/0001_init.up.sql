@@ -0,0 +1,37 @@
+-- SQLite has no enum types: pr_status is a CHECK constraint instead.
+-- Timestamps are stored as fixed-width UTC text so they sort correctly.
+
+CREATE TABLE IF NOT EXISTS teams (
+    team_name TEXT PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    user_id    TEXT PRIMARY KEY,
+    username   TEXT NOT NULL,
+    team_name  TEXT NOT NULL REFERENCES teams(team_name) ON DELETE RESTRICT,
+    is_active  INTEGER NOT NULL DEFAULT 1 CHECK (is_active IN (0, 1)),
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_team_active ON users(team_name, is_active);
+
+CREATE TABLE IF NOT EXISTS pull_requests (
+    pull_request_id   TEXT PRIMARY KEY,
+    pull_request_name TEXT NOT NULL,
+    author_id         TEXT NOT NULL REFERENCES users(user_id),
+    status            TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'MERGED')),
+    created_at        TEXT NOT NULL,
+    merged_at         TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_author ON pull_requests(author_id);
+CREATE INDEX IF NOT EXISTS idx_pr_status ON pull_requests(status);
+
+CREATE TABLE IF NOT EXISTS pr_reviewers (
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id     TEXT NOT NULL REFERENCES users(user_id),
+    PRIMARY KEY (pull_request_id, reviewer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_reviewer_reviewer ON pr_reviewers(reviewer_id);
@@ -0,0 +1,9 @@
+-- scopes holds a JSON array of strings.
+CREATE TABLE IF NOT EXISTS api_tokens (
+    token_id   TEXT PRIMARY KEY,
+    name       TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    scopes     TEXT NOT NULL DEFAULT '[]',
+    created_at TEXT NOT NULL,
+    revoked_at TEXT
+);
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN role TEXT NOT NULL DEFAULT 'member'
+        CHECK (role IN ('admin', 'lead', 'member'));
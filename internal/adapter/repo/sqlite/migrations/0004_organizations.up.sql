@@ -0,0 +1,85 @@
+-- Organizations partition teams, users and pull requests. SQLite cannot change
+-- primary keys in place, so the tables are rebuilt with (org_id, <id>) keys and
+-- existing rows move to the 'default' organization.
+
+CREATE TABLE organizations (
+    org_id     TEXT PRIMARY KEY,
+    name       TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+INSERT INTO organizations (org_id, name, created_at)
+VALUES ('default', 'Default', strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now'));
+
+CREATE TABLE old_teams AS SELECT * FROM teams;
+CREATE TABLE old_users AS SELECT * FROM users;
+CREATE TABLE old_pull_requests AS SELECT * FROM pull_requests;
+CREATE TABLE old_pr_reviewers AS SELECT * FROM pr_reviewers;
+
+DROP TABLE pr_reviewers;
+DROP TABLE pull_requests;
+DROP TABLE users;
+DROP TABLE teams;
+
+CREATE TABLE teams (
+    org_id    TEXT NOT NULL REFERENCES organizations(org_id),
+    team_name TEXT NOT NULL,
+    PRIMARY KEY (org_id, team_name)
+);
+
+CREATE TABLE users (
+    org_id     TEXT NOT NULL,
+    user_id    TEXT NOT NULL,
+    username   TEXT NOT NULL,
+    team_name  TEXT NOT NULL,
+    is_active  INTEGER NOT NULL DEFAULT 1 CHECK (is_active IN (0, 1)),
+    role       TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('admin', 'lead', 'member')),
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (org_id, user_id),
+    FOREIGN KEY (org_id, team_name) REFERENCES teams(org_id, team_name) ON DELETE RESTRICT
+);
+
+CREATE TABLE pull_requests (
+    org_id            TEXT NOT NULL,
+    pull_request_id   TEXT NOT NULL,
+    pull_request_name TEXT NOT NULL,
+    author_id         TEXT NOT NULL,
+    status            TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'MERGED')),
+    created_at        TEXT NOT NULL,
+    merged_at         TEXT,
+    PRIMARY KEY (org_id, pull_request_id),
+    FOREIGN KEY (org_id, author_id) REFERENCES users(org_id, user_id)
+);
+
+CREATE TABLE pr_reviewers (
+    org_id          TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    reviewer_id     TEXT NOT NULL,
+    PRIMARY KEY (org_id, pull_request_id, reviewer_id),
+    FOREIGN KEY (org_id, pull_request_id) REFERENCES pull_requests(org_id, pull_request_id) ON DELETE CASCADE,
+    FOREIGN KEY (org_id, reviewer_id) REFERENCES users(org_id, user_id)
+);
+
+INSERT INTO teams (org_id, team_name)
+SELECT 'default', team_name FROM old_teams;
+INSERT INTO users (org_id, user_id, username, team_name, is_active, role, created_at, updated_at)
+SELECT 'default', user_id, username, team_name, is_active, role, created_at, updated_at FROM old_users;
+INSERT INTO pull_requests (org_id, pull_request_id, pull_request_name, author_id, status, created_at, merged_at)
+SELECT 'default', pull_request_id, pull_request_name, author_id, status, created_at, merged_at FROM old_pull_requests;
+INSERT INTO pr_reviewers (org_id, pull_request_id, reviewer_id)
+SELECT 'default', pull_request_id, reviewer_id FROM old_pr_reviewers;
+
+DROP TABLE old_pr_reviewers;
+DROP TABLE old_pull_requests;
+DROP TABLE old_users;
+DROP TABLE old_teams;
+
+CREATE INDEX idx_users_team_active ON users(org_id, team_name, is_active);
+CREATE INDEX idx_pr_author ON pull_requests(org_id, author_id);
+CREATE INDEX idx_pr_status ON pull_requests(org_id, status);
+CREATE INDEX idx_pr_reviewer_reviewer ON pr_reviewers(org_id, reviewer_id);
+
+-- SQLite rejects REFERENCES on an added column with a non-NULL default, so
+-- token organizations are checked by the application only.
+ALTER TABLE api_tokens ADD COLUMN org_id TEXT NOT NULL DEFAULT 'default';
@@ -0,0 +1,14 @@
+-- Daily review digests. review_digests records the local day each user got
+-- (or is being sent) a digest, so that it is sent once.
+ALTER TABLE users ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN away_until TEXT;
+ALTER TABLE email_prefs ADD COLUMN daily_digest INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS review_digests (
+    org_id   TEXT NOT NULL,
+    user_id  TEXT NOT NULL,
+    day      TEXT NOT NULL,
+    sent_at  TEXT NOT NULL,
+    PRIMARY KEY (org_id, user_id, day),
+    FOREIGN KEY (org_id, user_id) REFERENCES users(org_id, user_id) ON DELETE CASCADE
+);
@@ -0,0 +1,29 @@
+-- Who changed what: one row per change of a team, user or pull request,
+-- written in the same transaction as the change. Rows are never updated or
+-- deleted; the triggers below reject attempts to.
+CREATE TABLE IF NOT EXISTS audit_log (
+    audit_id    INTEGER PRIMARY KEY AUTOINCREMENT,
+    org_id      TEXT NOT NULL,
+    action      TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id   TEXT NOT NULL,
+    actor       TEXT NOT NULL DEFAULT '',
+    request_id  TEXT NOT NULL DEFAULT '',
+    before      TEXT,
+    after       TEXT,
+    created_at  TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(org_id, entity_type, entity_id, audit_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(org_id, actor, audit_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_time ON audit_log(org_id, created_at);
+
+CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
+
+CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
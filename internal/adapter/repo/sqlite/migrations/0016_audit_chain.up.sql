@@ -0,0 +1,5 @@
+-- Chains audit_log rows of each organization: hash covers the row and the
+-- hash of the previous row, so an edited or deleted row breaks the chain.
+-- Rows written before this migration keep empty hashes.
+ALTER TABLE audit_log ADD COLUMN prev_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE audit_log ADD COLUMN hash TEXT NOT NULL DEFAULT '';
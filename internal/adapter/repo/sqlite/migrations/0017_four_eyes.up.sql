@@ -0,0 +1,15 @@
+-- Four-eyes merges: the per-team switch, the reporting line of users and
+-- the latest review verdict of every reviewer of a pull request.
+ALTER TABLE teams ADD COLUMN four_eyes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN manager_id TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS pr_reviews (
+    org_id          TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    reviewer_id     TEXT NOT NULL,
+    verdict         TEXT NOT NULL,
+    created_at      TEXT NOT NULL,
+    PRIMARY KEY (org_id, pull_request_id, reviewer_id),
+    FOREIGN KEY (org_id, pull_request_id) REFERENCES pull_requests(org_id, pull_request_id) ON DELETE CASCADE,
+    FOREIGN KEY (org_id, reviewer_id) REFERENCES users(org_id, user_id)
+);
@@ -0,0 +1,3 @@
+-- Tokens are bound to their organization; cross-org admin tokens may pick
+-- another one per request with X-Org-ID.
+ALTER TABLE api_tokens ADD COLUMN cross_org INTEGER NOT NULL DEFAULT 0;
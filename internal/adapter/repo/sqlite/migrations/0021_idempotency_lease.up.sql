@@ -0,0 +1,3 @@
+-- The reservation a request holds while it runs. A reservation older than
+-- the lease period is taken over by a retry, which sets a new lease.
+ALTER TABLE idempotency_keys ADD COLUMN lease TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,38 @@
+-- Pull requests closed without a merge, or turned back into drafts. SQLite
+-- cannot change a CHECK constraint in place, so pull_requests is rebuilt.
+-- Dropping it cascades to the tables that reference it, whose rows are
+-- kept aside and restored; those tables refer to pull_requests by name and
+-- pick up the new one.
+
+CREATE TABLE new_pull_requests (
+    org_id            TEXT NOT NULL,
+    pull_request_id   TEXT NOT NULL,
+    pull_request_name TEXT NOT NULL,
+    author_id         TEXT NOT NULL,
+    status            TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'MERGED', 'CLOSED')),
+    created_at        TEXT NOT NULL,
+    merged_at         TEXT,
+    version           INTEGER NOT NULL DEFAULT 1,
+    PRIMARY KEY (org_id, pull_request_id),
+    FOREIGN KEY (org_id, author_id) REFERENCES users(org_id, user_id)
+);
+INSERT INTO new_pull_requests (org_id, pull_request_id, pull_request_name, author_id, status, created_at, merged_at, version)
+SELECT org_id, pull_request_id, pull_request_name, author_id, status, created_at, merged_at, version FROM pull_requests;
+
+CREATE TABLE old_pr_reviewers AS SELECT * FROM pr_reviewers;
+CREATE TABLE old_pr_reviews AS SELECT * FROM pr_reviews;
+CREATE TABLE old_reviewer_syncs AS SELECT * FROM reviewer_syncs;
+
+DROP TABLE pull_requests;
+ALTER TABLE new_pull_requests RENAME TO pull_requests;
+
+INSERT INTO pr_reviewers SELECT * FROM old_pr_reviewers;
+INSERT INTO pr_reviews SELECT * FROM old_pr_reviews;
+INSERT INTO reviewer_syncs SELECT * FROM old_reviewer_syncs;
+
+DROP TABLE old_pr_reviewers;
+DROP TABLE old_pr_reviews;
+DROP TABLE old_reviewer_syncs;
+
+CREATE INDEX idx_pr_author ON pull_requests(org_id, author_id);
+CREATE INDEX idx_pr_status ON pull_requests(org_id, status);
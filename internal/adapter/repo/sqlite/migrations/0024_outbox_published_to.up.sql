@@ -0,0 +1,3 @@
+-- Sinks that have published a message already, as a JSON array. A retry
+-- after a failure goes only to the other sinks.
+ALTER TABLE outbox ADD COLUMN published_to TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type OrgRepo struct{ db *sql.DB }
+
+func NewOrgRepo(db *sql.DB) *OrgRepo { return &OrgRepo{db: db} }
+
+func (r *OrgRepo) CreateOrg(ctx context.Context, org domain.Organization) (domain.Organization, error) {
+	if _, err := r.db.ExecContext(ctx,
+		`INSERT INTO organizations (org_id, name, created_at) VALUES (?,?,?)`,
+		org.OrgID, org.Name, now(),
+	); err != nil {
+		if isUniqueViolation(err) {
+			return domain.Organization{}, domain.ErrOrgExists
+		}
+		return domain.Organization{}, err
+	}
+	return r.GetOrg(ctx, org.OrgID)
+}
+
+func (r *OrgRepo) GetOrg(ctx context.Context, orgID string) (domain.Organization, error) {
+	return scanOrg(r.db.QueryRowContext(ctx,
+		`SELECT org_id, name, created_at FROM organizations WHERE org_id=?`, orgID))
+}
+
+func (r *OrgRepo) ListOrgs(ctx context.Context) ([]domain.Organization, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT org_id, name, created_at FROM organizations ORDER BY org_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []domain.Organization
+	for rows.Next() {
+		o, err := scanOrg(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
+
+func scanOrg(row scanner) (domain.Organization, error) {
+	var (
+		o       domain.Organization
+		created string
+	)
+	if err := row.Scan(&o.OrgID, &o.Name, &created); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Organization{}, domain.ErrNotFound
+		}
+		return domain.Organization{}, err
+	}
+	createdAt, err := parseTime(created)
+	if err != nil {
+		return domain.Organization{}, err
+	}
+	o.CreatedAt = *createdAt
+	return o, nil
+}
@@ -0,0 +1,182 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// querier lets reads run either on the database or inside the transaction
+// that also writes the outbox; the single connection rules out using the
+// database while a transaction is open.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type OutboxRepo struct{ db *sql.DB }
+
+func NewOutboxRepo(db *sql.DB) *OutboxRepo { return &OutboxRepo{db: db} }
+
+const outboxColumns = `outbox_id, msg_key, payload, status, attempts, last_error,
+	published_to, next_attempt_at, created_at, updated_at`
+
+func insertOutbox(ctx context.Context, q querier, e *domain.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	ts := now()
+	_, err = q.ExecContext(ctx, `
+		INSERT INTO outbox (org_id, event_id, event_type, msg_key, payload, next_attempt_at, created_at, updated_at)
+		VALUES (?,?,?,?,?,?,?,?)`, e.OrgID, e.ID, e.Type, e.Key(), string(payload), ts, ts, ts)
+	return err
+}
+
+func (r *OutboxRepo) ClaimOutbox(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxMessage, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rollback(tx, "ClaimOutbox")
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+outboxColumns+` FROM outbox m
+		WHERE m.status = 'pending' AND m.next_attempt_at <= ?
+		  AND NOT EXISTS (
+			SELECT 1 FROM outbox o
+			WHERE o.org_id = m.org_id AND o.msg_key = m.msg_key
+			  AND o.status = 'pending' AND o.outbox_id < m.outbox_id)
+		ORDER BY m.outbox_id
+		LIMIT ?`, formatTime(now), limit)
+	if err != nil {
+		return nil, err
+	}
+	out, err := collectOutbox(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	lease := formatTime(leaseUntil)
+	for i := range out {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE outbox SET next_attempt_at=? WHERE outbox_id=?`, lease, out[i].ID,
+		); err != nil {
+			return nil, err
+		}
+		out[i].NextAttemptAt = leaseUntil.UTC()
+	}
+	return out, tx.Commit()
+}
+
+func (r *OutboxRepo) GetOutbox(ctx context.Context, id int64) (domain.OutboxMessage, error) {
+	return scanOutbox(r.db.QueryRowContext(ctx, `SELECT `+outboxColumns+` FROM outbox WHERE outbox_id=?`, id))
+}
+
+func (r *OutboxRepo) SaveOutbox(ctx context.Context, m domain.OutboxMessage) error {
+	if m.PublishedTo == nil {
+		m.PublishedTo = []string{}
+	}
+	sinks, err := json.Marshal(m.PublishedTo)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE outbox
+		SET status=?, attempts=?, last_error=?, published_to=?, next_attempt_at=?, updated_at=?
+		WHERE outbox_id=?`, m.Status, m.Attempts, m.LastError, string(sinks), formatTime(m.NextAttemptAt), now(), m.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *OutboxRepo) ListOutbox(ctx context.Context, status domain.OutboxStatus) ([]domain.OutboxMessage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+outboxColumns+` FROM outbox
+		WHERE ?1 = '' OR status = ?1
+		ORDER BY outbox_id`, status)
+	if err != nil {
+		return nil, err
+	}
+	return collectOutbox(rows)
+}
+
+func (r *OutboxRepo) ListOutboxAfter(ctx context.Context, afterID int64, limit int) ([]domain.OutboxMessage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+outboxColumns+` FROM outbox
+		WHERE org_id = ? AND outbox_id > ?
+		ORDER BY outbox_id
+		LIMIT ?`, domain.OrgFromContext(ctx), afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return collectOutbox(rows)
+}
+
+func (r *OutboxRepo) LastOutboxID(ctx context.Context) (int64, error) {
+	var id int64
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(outbox_id), 0) FROM outbox WHERE org_id = ?`, domain.OrgFromContext(ctx),
+	).Scan(&id)
+	return id, err
+}
+
+func collectOutbox(rows *sql.Rows) ([]domain.OutboxMessage, error) {
+	defer closeRows(rows)
+
+	var out []domain.OutboxMessage
+	for rows.Next() {
+		m, err := scanOutbox(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+func scanOutbox(row scanner) (domain.OutboxMessage, error) {
+	var (
+		m                      domain.OutboxMessage
+		payload, sinks         string
+		next, created, updated string
+	)
+	err := row.Scan(&m.ID, &m.Key, &payload, &m.Status, &m.Attempts, &m.LastError,
+		&sinks, &next, &created, &updated)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.OutboxMessage{}, domain.ErrNotFound
+		}
+		return domain.OutboxMessage{}, err
+	}
+	if err := json.Unmarshal([]byte(payload), &m.Event); err != nil {
+		return domain.OutboxMessage{}, err
+	}
+	if err := json.Unmarshal([]byte(sinks), &m.PublishedTo); err != nil {
+		return domain.OutboxMessage{}, err
+	}
+	for _, f := range []struct {
+		src string
+		dst *time.Time
+	}{{next, &m.NextAttemptAt}, {created, &m.CreatedAt}, {updated, &m.UpdatedAt}} {
+		t, err := parseTime(f.src)
+		if err != nil {
+			return domain.OutboxMessage{}, err
+		}
+		*f.dst = *t
+	}
+	return m, nil
+}
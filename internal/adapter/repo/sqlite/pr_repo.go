@@ -0,0 +1,429 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type PRRepo struct{ db *sql.DB }
+
+func NewPRRepo(db *sql.DB) *PRRepo { return &PRRepo{db: db} }
+
+func (r *PRRepo) CreatePRWithReviewers(ctx context.Context, pr domain.PullRequest, reviewers []string, e *domain.Event) (domain.PullRequest, error) {
+	org := domain.OrgFromContext(ctx)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	defer rollback(tx, "CreatePRWithReviewers")
+
+	_, err = tx.ExecContext(ctx, `
+	  INSERT INTO pull_requests (org_id, pull_request_id, pull_request_name, author_id, status, created_at)
+	  VALUES (?,?,?,?,'OPEN',?)`,
+		org, pr.ID, pr.Name, pr.AuthorID, now())
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.PullRequest{}, domain.ErrPRExists
+		}
+		return domain.PullRequest{}, err
+	}
+
+	for _, rid := range reviewers {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO pr_reviewers (org_id, pull_request_id, reviewer_id) VALUES (?,?,?)`,
+			org, pr.ID, rid,
+		); err != nil {
+			return domain.PullRequest{}, err
+		}
+	}
+	return commitWithEvent(ctx, tx, pr.ID, domain.AuditPRCreated, nil, e)
+}
+
+// commitWithEvent reads the PR back inside tx, records the change from
+// before in the audit log if action is set, writes e to the outbox if it is
+// set and commits.
+func commitWithEvent(ctx context.Context, tx *sql.Tx, prID string, action domain.AuditAction, before *domain.PullRequest, e *domain.Event) (domain.PullRequest, error) {
+	out, err := getPR(ctx, tx, prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	if action != "" {
+		if err := insertAudit(ctx, tx, domain.NewAuditEntry(ctx, action, prID, domain.AuditPR(before), domain.AuditPR(&out))); err != nil {
+			return domain.PullRequest{}, err
+		}
+	}
+	if e != nil {
+		e.Data.PullRequest = domain.NewEventPR(out)
+		if err := insertOutbox(ctx, tx, e); err != nil {
+			return domain.PullRequest{}, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return domain.PullRequest{}, err
+	}
+	return out, nil
+}
+
+func (r *PRRepo) GetByIDForUpdate(ctx context.Context, id string) (domain.PullRequest, error) {
+	return getPR(ctx, r.db, id)
+}
+
+func getPR(ctx context.Context, q querier, id string) (domain.PullRequest, error) {
+	var (
+		out      domain.PullRequest
+		created  string
+		mergedAt sql.NullString
+	)
+	err := q.QueryRowContext(ctx, `
+		SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, version
+		FROM pull_requests WHERE org_id=? AND pull_request_id=?`, domain.OrgFromContext(ctx), id).
+		Scan(&out.ID, &out.Name, &out.AuthorID, &out.Status, &created, &mergedAt, &out.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.PullRequest{}, domain.ErrNotFound
+		}
+		return domain.PullRequest{}, err
+	}
+
+	if out.CreatedAt, err = parseTime(created); err != nil {
+		return domain.PullRequest{}, err
+	}
+	if mergedAt.Valid {
+		if out.MergedAt, err = parseTime(mergedAt.String); err != nil {
+			return domain.PullRequest{}, err
+		}
+	}
+
+	revs, err := assignedReviewers(ctx, q, id)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	out.AssignedReviewers = revs
+	return out, nil
+}
+
+func (r *PRRepo) GetAssignedReviewers(ctx context.Context, prID string) ([]string, error) {
+	return assignedReviewers(ctx, r.db, prID)
+}
+
+func assignedReviewers(ctx context.Context, q querier, prID string) ([]string, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT reviewer_id FROM pr_reviewers
+		WHERE org_id=? AND pull_request_id=?`, domain.OrgFromContext(ctx), prID)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+func (r *PRRepo) ReplaceReviewer(ctx context.Context, prID, oldID, newID string, version int64, e *domain.Event) (domain.PullRequest, error) {
+	org := domain.OrgFromContext(ctx)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	defer rollback(tx, "ReplaceReviewer")
+
+	before, err := getPR(ctx, tx, prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	if !before.MatchesVersion(version) {
+		return domain.PullRequest{}, domain.ErrVersionMismatch
+	}
+	if err := before.CanReplaceReviewer(oldID, newID); err != nil {
+		return domain.PullRequest{}, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM pr_reviewers WHERE org_id=? AND pull_request_id=? AND reviewer_id=?`,
+		org, prID, oldID,
+	); err != nil {
+		return domain.PullRequest{}, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO pr_reviewers (org_id, pull_request_id, reviewer_id) VALUES (?,?,?)`,
+		org, prID, newID,
+	); err != nil {
+		return domain.PullRequest{}, err
+	}
+	if err := bumpVersion(ctx, tx, prID); err != nil {
+		return domain.PullRequest{}, err
+	}
+	return commitWithEvent(ctx, tx, prID, domain.AuditPRReassigned, &before, e)
+}
+
+// SetMerged writes e only when the PR was still open. A closed PR is
+// rejected rather than merged.
+func (r *PRRepo) SetMerged(ctx context.Context, prID string, version int64, e *domain.Event) (domain.PullRequest, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	defer rollback(tx, "SetMerged")
+
+	before, err := getPR(ctx, tx, prID)
+	switch {
+	case err == nil && !before.MatchesVersion(version):
+		return domain.PullRequest{}, domain.ErrVersionMismatch
+	case err == nil && before.Status == domain.StatusClosed:
+		return domain.PullRequest{}, domain.ErrPRClosed
+	case err != nil && !errors.Is(err, domain.ErrNotFound):
+		return domain.PullRequest{}, err
+	}
+	res, err := tx.ExecContext(ctx, `
+		UPDATE pull_requests
+		SET status='MERGED', merged_at = COALESCE(merged_at, ?), version = version + 1
+		WHERE org_id=? AND pull_request_id=? AND status = 'OPEN'`, now(), domain.OrgFromContext(ctx), prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	if n == 0 {
+		return commitWithEvent(ctx, tx, prID, "", nil, nil)
+	}
+	return commitWithEvent(ctx, tx, prID, domain.AuditPRMerged, &before, e)
+}
+
+// SetClosed writes e only when the status changes.
+func (r *PRRepo) SetClosed(ctx context.Context, prID string, closed bool, e *domain.Event) (domain.PullRequest, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	defer rollback(tx, "SetClosed")
+
+	before, err := getPR(ctx, tx, prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	from, to, action := domain.StatusOpen, domain.StatusClosed, domain.AuditPRClosed
+	if !closed {
+		from, to, action = to, from, domain.AuditPRReopened
+	}
+	if before.Status != from {
+		return commitWithEvent(ctx, tx, prID, "", nil, nil)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE pull_requests SET status=?, version = version + 1
+		WHERE org_id=? AND pull_request_id=?`,
+		to, domain.OrgFromContext(ctx), prID); err != nil {
+		return domain.PullRequest{}, err
+	}
+	return commitWithEvent(ctx, tx, prID, action, &before, e)
+}
+
+// SetReview writes e only when the verdict changes.
+func (r *PRRepo) SetReview(ctx context.Context, prID string, rv domain.Review, version int64, e *domain.Event) (domain.PullRequest, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	defer rollback(tx, "SetReview")
+
+	pr, err := getPR(ctx, tx, prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	if !pr.MatchesVersion(version) {
+		return domain.PullRequest{}, domain.ErrVersionMismatch
+	}
+	if err := pr.CheckOpen(); err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	var before *domain.Review
+	prev, err := getReview(ctx, tx, prID, rv.ReviewerID)
+	switch {
+	case err == nil && prev.Verdict == rv.Verdict:
+		return commitWithEvent(ctx, tx, prID, "", nil, nil)
+	case err == nil:
+		before = &prev
+	case !errors.Is(err, domain.ErrNotFound):
+		return domain.PullRequest{}, err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO pr_reviews (org_id, pull_request_id, reviewer_id, verdict, created_at)
+		VALUES (?,?,?,?,?)
+		ON CONFLICT (org_id, pull_request_id, reviewer_id) DO UPDATE
+		  SET verdict=excluded.verdict, created_at=excluded.created_at`,
+		domain.OrgFromContext(ctx), prID, rv.ReviewerID, rv.Verdict, now())
+	if isForeignKeyViolation(err) {
+		return domain.PullRequest{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	if err := insertAudit(ctx, tx, domain.NewAuditEntry(ctx, domain.AuditPRReviewed, prID,
+		domain.AuditReview(prID, before), domain.AuditReview(prID, &rv))); err != nil {
+		return domain.PullRequest{}, err
+	}
+	if err := bumpVersion(ctx, tx, prID); err != nil {
+		return domain.PullRequest{}, err
+	}
+	return commitWithEvent(ctx, tx, prID, "", nil, e)
+}
+
+// bumpVersion records a change of the PR's reviewers or verdicts.
+func bumpVersion(ctx context.Context, tx *sql.Tx, prID string) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE pull_requests SET version = version + 1 WHERE org_id=? AND pull_request_id=?`,
+		domain.OrgFromContext(ctx), prID)
+	return err
+}
+
+func getReview(ctx context.Context, q querier, prID, reviewerID string) (domain.Review, error) {
+	rv, err := scanReview(q.QueryRowContext(ctx, `
+		SELECT reviewer_id, verdict, created_at FROM pr_reviews
+		WHERE org_id=? AND pull_request_id=? AND reviewer_id=?`, domain.OrgFromContext(ctx), prID, reviewerID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Review{}, domain.ErrNotFound
+	}
+	return rv, err
+}
+
+func (r *PRRepo) ListReviews(ctx context.Context, prID string) ([]domain.Review, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT reviewer_id, verdict, created_at FROM pr_reviews
+		WHERE org_id=? AND pull_request_id=?
+		ORDER BY reviewer_id`, domain.OrgFromContext(ctx), prID)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []domain.Review
+	for rows.Next() {
+		rv, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rv)
+	}
+	return out, rows.Err()
+}
+
+func scanReview(row scanner) (domain.Review, error) {
+	var (
+		rv      domain.Review
+		created string
+	)
+	if err := row.Scan(&rv.ReviewerID, &rv.Verdict, &created); err != nil {
+		return domain.Review{}, err
+	}
+	t, err := parseTime(created)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	rv.CreatedAt = *t
+	return rv, nil
+}
+
+func (r *PRRepo) ListByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequestShort, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at
+		FROM pull_requests pr
+		JOIN pr_reviewers r ON r.org_id = pr.org_id AND r.pull_request_id = pr.pull_request_id
+		WHERE r.org_id = ? AND r.reviewer_id = ?
+		ORDER BY pr.created_at DESC`, domain.OrgFromContext(ctx), reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []domain.PullRequestShort
+	for rows.Next() {
+		var (
+			s       domain.PullRequestShort
+			created string
+		)
+		if err := rows.Scan(&s.ID, &s.Name, &s.AuthorID, &s.Status, &created); err != nil {
+			return nil, err
+		}
+		if s.CreatedAt, err = parseTime(created); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (r *PRRepo) ListEvents(ctx context.Context, prID string) ([]domain.Event, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT payload FROM outbox
+		WHERE org_id = ? AND msg_key = ?
+		ORDER BY outbox_id`, domain.OrgFromContext(ctx), domain.PRKey(prID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []domain.Event
+	for rows.Next() {
+		var (
+			payload string
+			e       domain.Event
+		)
+		if err := rows.Scan(&payload); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(payload), &e); err != nil {
+			return nil, fmt.Errorf("decode event of %s: %w", prID, err)
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (r *PRRepo) StatsByStatus(ctx context.Context) (map[domain.PRStatus]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT status, COUNT(*)
+		FROM pull_requests
+		WHERE org_id = ?
+		GROUP BY status`, domain.OrgFromContext(ctx),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	res := map[domain.PRStatus]int{
+		domain.StatusOpen:   0,
+		domain.StatusMerged: 0,
+		domain.StatusClosed: 0,
+	}
+
+	for rows.Next() {
+		var status domain.PRStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		res[status] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
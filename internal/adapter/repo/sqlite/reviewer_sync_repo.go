@@ -0,0 +1,168 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type ReviewerSyncRepo struct{ db *sql.DB }
+
+func NewReviewerSyncRepo(db *sql.DB) *ReviewerSyncRepo { return &ReviewerSyncRepo{db: db} }
+
+const syncColumns = `sync_id, org_id, pull_request_id, add_reviewers, remove_reviewers,
+	status, attempts, last_error, next_attempt_at, created_at, updated_at`
+
+func (r *ReviewerSyncRepo) EnqueueReviewerSync(ctx context.Context, s domain.ReviewerSync) (domain.ReviewerSync, error) {
+	add, err := jsonList(s.Add)
+	if err != nil {
+		return domain.ReviewerSync{}, err
+	}
+	remove, err := jsonList(s.Remove)
+	if err != nil {
+		return domain.ReviewerSync{}, err
+	}
+
+	ts := now()
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO reviewer_syncs
+		  (org_id, pull_request_id, add_reviewers, remove_reviewers, status, next_attempt_at, created_at, updated_at)
+		VALUES (?,?,?,?,?,?,?,?)`,
+		domain.OrgFromContext(ctx), s.PRID, add, remove, s.Status, formatTime(s.NextAttemptAt), ts, ts)
+	if err != nil {
+		return domain.ReviewerSync{}, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.ReviewerSync{}, err
+	}
+	return r.GetReviewerSync(ctx, id)
+}
+
+func (r *ReviewerSyncRepo) ClaimReviewerSyncs(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.ReviewerSync, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rollback(tx, "ClaimReviewerSyncs")
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+syncColumns+` FROM reviewer_syncs s
+		WHERE s.status = 'pending' AND s.next_attempt_at <= ?
+		  AND NOT EXISTS (
+			SELECT 1 FROM reviewer_syncs o
+			WHERE o.org_id = s.org_id AND o.pull_request_id = s.pull_request_id
+			  AND o.status = 'pending' AND o.sync_id < s.sync_id)
+		ORDER BY s.sync_id
+		LIMIT ?`, formatTime(now), limit)
+	if err != nil {
+		return nil, err
+	}
+	out, err := collectSyncs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	lease := formatTime(leaseUntil)
+	for i := range out {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE reviewer_syncs SET next_attempt_at=? WHERE sync_id=?`, lease, out[i].ID,
+		); err != nil {
+			return nil, err
+		}
+		out[i].NextAttemptAt = leaseUntil.UTC()
+	}
+	return out, tx.Commit()
+}
+
+func (r *ReviewerSyncRepo) GetReviewerSync(ctx context.Context, id int64) (domain.ReviewerSync, error) {
+	return scanSync(r.db.QueryRowContext(ctx, `SELECT `+syncColumns+` FROM reviewer_syncs WHERE sync_id=?`, id))
+}
+
+func (r *ReviewerSyncRepo) SaveReviewerSync(ctx context.Context, s domain.ReviewerSync) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE reviewer_syncs
+		SET status=?, attempts=?, last_error=?, next_attempt_at=?, updated_at=?
+		WHERE sync_id=?`, s.Status, s.Attempts, s.LastError, formatTime(s.NextAttemptAt), now(), s.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ReviewerSyncRepo) ListReviewerSyncs(ctx context.Context, status domain.SyncStatus) ([]domain.ReviewerSync, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+syncColumns+` FROM reviewer_syncs
+		WHERE ?1 = '' OR status = ?1
+		ORDER BY sync_id`, status)
+	if err != nil {
+		return nil, err
+	}
+	return collectSyncs(rows)
+}
+
+func collectSyncs(rows *sql.Rows) ([]domain.ReviewerSync, error) {
+	defer closeRows(rows)
+
+	var out []domain.ReviewerSync
+	for rows.Next() {
+		s, err := scanSync(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func scanSync(row scanner) (domain.ReviewerSync, error) {
+	var (
+		s                      domain.ReviewerSync
+		add, remove            string
+		next, created, updated string
+	)
+	err := row.Scan(&s.ID, &s.OrgID, &s.PRID, &add, &remove,
+		&s.Status, &s.Attempts, &s.LastError, &next, &created, &updated)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ReviewerSync{}, domain.ErrNotFound
+		}
+		return domain.ReviewerSync{}, err
+	}
+	if err := json.Unmarshal([]byte(add), &s.Add); err != nil {
+		return domain.ReviewerSync{}, err
+	}
+	if err := json.Unmarshal([]byte(remove), &s.Remove); err != nil {
+		return domain.ReviewerSync{}, err
+	}
+	for _, f := range []struct {
+		src string
+		dst *time.Time
+	}{{next, &s.NextAttemptAt}, {created, &s.CreatedAt}, {updated, &s.UpdatedAt}} {
+		t, err := parseTime(f.src)
+		if err != nil {
+			return domain.ReviewerSync{}, err
+		}
+		*f.dst = *t
+	}
+	return s, nil
+}
+
+func jsonList(s []string) (string, error) {
+	if s == nil {
+		s = []string{}
+	}
+	b, err := json.Marshal(s)
+	return string(b), err
+}
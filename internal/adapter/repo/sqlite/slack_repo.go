@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type SlackRepo struct{ db *sql.DB }
+
+func NewSlackRepo(db *sql.DB) *SlackRepo { return &SlackRepo{db: db} }
+
+func (r *SlackRepo) SetSlackChannel(ctx context.Context, c domain.SlackChannel) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO slack_channels (org_id, team_name, webhook_url) VALUES (?,?,?)
+		ON CONFLICT (org_id, team_name) DO UPDATE SET webhook_url=excluded.webhook_url`,
+		domain.OrgFromContext(ctx), c.TeamName, c.WebhookURL)
+	if isForeignKeyViolation(err) {
+		return domain.ErrNotFound
+	}
+	return err
+}
+
+func (r *SlackRepo) GetSlackChannel(ctx context.Context, teamName string) (domain.SlackChannel, error) {
+	c := domain.SlackChannel{TeamName: teamName}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT webhook_url FROM slack_channels WHERE org_id=? AND team_name=?`,
+		domain.OrgFromContext(ctx), teamName,
+	).Scan(&c.WebhookURL)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.SlackChannel{}, domain.ErrNotFound
+		}
+		return domain.SlackChannel{}, err
+	}
+	return c, nil
+}
+
+func (r *SlackRepo) DeleteSlackChannel(ctx context.Context, teamName string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM slack_channels WHERE org_id=? AND team_name=?`,
+		domain.OrgFromContext(ctx), teamName)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SlackRepo) ListSlackChannels(ctx context.Context) ([]domain.SlackChannel, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT team_name, webhook_url FROM slack_channels WHERE org_id=? ORDER BY team_name`,
+		domain.OrgFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []domain.SlackChannel
+	for rows.Next() {
+		var c domain.SlackChannel
+		if err := rows.Scan(&c.TeamName, &c.WebhookURL); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (r *SlackRepo) SetSlackUser(ctx context.Context, u domain.SlackUser) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO slack_users (org_id, user_id, slack_id) VALUES (?,?,?)
+		ON CONFLICT (org_id, user_id) DO UPDATE SET slack_id=excluded.slack_id`,
+		domain.OrgFromContext(ctx), u.UserID, u.SlackID)
+	if isForeignKeyViolation(err) {
+		return domain.ErrNotFound
+	}
+	return err
+}
+
+func (r *SlackRepo) DeleteSlackUser(ctx context.Context, userID string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM slack_users WHERE org_id=? AND user_id=?`,
+		domain.OrgFromContext(ctx), userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SlackRepo) ListSlackUsers(ctx context.Context) ([]domain.SlackUser, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT user_id, slack_id FROM slack_users WHERE org_id=? ORDER BY user_id`,
+		domain.OrgFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []domain.SlackUser
+	for rows.Next() {
+		var u domain.SlackUser
+		if err := rows.Scan(&u.UserID, &u.SlackID); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
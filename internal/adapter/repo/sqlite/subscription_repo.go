@@ -0,0 +1,233 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type SubscriptionRepo struct{ db *sql.DB }
+
+func NewSubscriptionRepo(db *sql.DB) *SubscriptionRepo { return &SubscriptionRepo{db: db} }
+
+const subscriptionColumns = `subscription_id, org_id, url, secret, event_types, created_at`
+
+func (r *SubscriptionRepo) CreateSubscription(ctx context.Context, s domain.Subscription) (domain.Subscription, error) {
+	types, err := json.Marshal(s.EventTypes)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	if _, err := r.db.ExecContext(ctx, `
+		INSERT INTO subscriptions (subscription_id, org_id, url, secret, event_types, created_at)
+		VALUES (?,?,?,?,?,?)`,
+		s.ID, domain.OrgFromContext(ctx), s.URL, s.Secret, string(types), now(),
+	); err != nil {
+		return domain.Subscription{}, err
+	}
+	return r.GetSubscription(ctx, s.ID)
+}
+
+func (r *SubscriptionRepo) GetSubscription(ctx context.Context, id string) (domain.Subscription, error) {
+	return scanSubscription(r.db.QueryRowContext(ctx, `
+		SELECT `+subscriptionColumns+` FROM subscriptions
+		WHERE org_id=? AND subscription_id=?`, domain.OrgFromContext(ctx), id))
+}
+
+func (r *SubscriptionRepo) ListSubscriptions(ctx context.Context) ([]domain.Subscription, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+subscriptionColumns+` FROM subscriptions
+		WHERE org_id=? ORDER BY created_at, subscription_id`, domain.OrgFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []domain.Subscription
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (r *SubscriptionRepo) DeleteSubscription(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM subscriptions WHERE org_id=? AND subscription_id=?`,
+		domain.OrgFromContext(ctx), id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func scanSubscription(row scanner) (domain.Subscription, error) {
+	var (
+		s              domain.Subscription
+		types, created string
+	)
+	if err := row.Scan(&s.ID, &s.OrgID, &s.URL, &s.Secret, &types, &created); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Subscription{}, domain.ErrNotFound
+		}
+		return domain.Subscription{}, err
+	}
+	if err := json.Unmarshal([]byte(types), &s.EventTypes); err != nil {
+		return domain.Subscription{}, err
+	}
+	createdAt, err := parseTime(created)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	s.CreatedAt = *createdAt
+	return s, nil
+}
+
+type EventDeliveryRepo struct{ db *sql.DB }
+
+func NewEventDeliveryRepo(db *sql.DB) *EventDeliveryRepo { return &EventDeliveryRepo{db: db} }
+
+const deliveryColumns = `delivery_id, org_id, subscription_id, event_id, event_type, payload,
+	status, attempts, response_code, last_error, next_attempt_at, created_at, updated_at`
+
+func (r *EventDeliveryRepo) EnqueueEventDelivery(ctx context.Context, d domain.EventDelivery) (domain.EventDelivery, error) {
+	ts := now()
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO event_deliveries
+		  (org_id, subscription_id, event_id, event_type, payload, status, next_attempt_at, created_at, updated_at)
+		VALUES (?,?,?,?,?,?,?,?,?)`,
+		domain.OrgFromContext(ctx), d.SubscriptionID, d.EventID, d.EventType, d.Payload, d.Status,
+		formatTime(d.NextAttemptAt), ts, ts)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.EventDelivery{}, domain.ErrNotFound
+		}
+		return domain.EventDelivery{}, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.EventDelivery{}, err
+	}
+	return r.GetEventDelivery(ctx, id)
+}
+
+func (r *EventDeliveryRepo) ClaimEventDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.EventDelivery, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rollback(tx, "ClaimEventDeliveries")
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+deliveryColumns+` FROM event_deliveries
+		WHERE status = 'pending' AND next_attempt_at <= ?
+		ORDER BY delivery_id
+		LIMIT ?`, formatTime(now), limit)
+	if err != nil {
+		return nil, err
+	}
+	out, err := collectDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	lease := formatTime(leaseUntil)
+	for i := range out {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE event_deliveries SET next_attempt_at=? WHERE delivery_id=?`, lease, out[i].ID,
+		); err != nil {
+			return nil, err
+		}
+		out[i].NextAttemptAt = leaseUntil.UTC()
+	}
+	return out, tx.Commit()
+}
+
+func (r *EventDeliveryRepo) GetEventDelivery(ctx context.Context, id int64) (domain.EventDelivery, error) {
+	return scanDelivery(r.db.QueryRowContext(ctx, `
+		SELECT `+deliveryColumns+` FROM event_deliveries
+		WHERE org_id=? AND delivery_id=?`, domain.OrgFromContext(ctx), id))
+}
+
+func (r *EventDeliveryRepo) SaveEventDelivery(ctx context.Context, d domain.EventDelivery) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE event_deliveries
+		SET status=?, attempts=?, response_code=?, last_error=?, next_attempt_at=?, updated_at=?
+		WHERE delivery_id=?`,
+		d.Status, d.Attempts, d.ResponseCode, d.LastError, formatTime(d.NextAttemptAt), now(), d.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *EventDeliveryRepo) ListEventDeliveries(ctx context.Context, subscriptionID string, limit int) ([]domain.EventDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+deliveryColumns+` FROM event_deliveries
+		WHERE org_id=? AND subscription_id=?
+		ORDER BY delivery_id DESC
+		LIMIT ?`, domain.OrgFromContext(ctx), subscriptionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return collectDeliveries(rows)
+}
+
+func collectDeliveries(rows *sql.Rows) ([]domain.EventDelivery, error) {
+	defer closeRows(rows)
+
+	var out []domain.EventDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func scanDelivery(row scanner) (domain.EventDelivery, error) {
+	var (
+		d                      domain.EventDelivery
+		next, created, updated string
+	)
+	err := row.Scan(&d.ID, &d.OrgID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload,
+		&d.Status, &d.Attempts, &d.ResponseCode, &d.LastError, &next, &created, &updated)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.EventDelivery{}, domain.ErrNotFound
+		}
+		return domain.EventDelivery{}, err
+	}
+	for _, f := range []struct {
+		src string
+		dst *time.Time
+	}{{next, &d.NextAttemptAt}, {created, &d.CreatedAt}, {updated, &d.UpdatedAt}} {
+		t, err := parseTime(f.src)
+		if err != nil {
+			return domain.EventDelivery{}, err
+		}
+		*f.dst = *t
+	}
+	return d, nil
+}
@@ -0,0 +1,310 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type TeamRepo struct {
+	db *sql.DB
+}
+
+func NewTeamRepo(db *sql.DB) *TeamRepo {
+	return &TeamRepo{db: db}
+}
+
+func (r *TeamRepo) CreateTeam(ctx context.Context, teamName string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx, "CreateTeam")
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO teams (org_id, team_name) VALUES (?,?)`, domain.OrgFromContext(ctx), teamName)
+	if isUniqueViolation(err) {
+		return domain.ErrTeamExists
+	}
+	if err != nil {
+		return err
+	}
+	if err := insertAudit(ctx, tx, domain.NewAuditEntry(ctx, domain.AuditTeamCreated, teamName, nil, domain.AuditTeam(domain.Team{TeamName: teamName}))); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *TeamRepo) GetTeamWithMembers(ctx context.Context, teamName string) (domain.Team, []domain.User, error) {
+	org := domain.OrgFromContext(ctx)
+
+	t, err := getTeam(ctx, r.db, teamName)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+userColumns+`
+		FROM users
+		WHERE org_id = ? AND team_name = ?
+		ORDER BY user_id`, org, teamName)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+	defer closeRows(rows)
+
+	var members []domain.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return domain.Team{}, nil, err
+		}
+		members = append(members, u)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.Team{}, nil, err
+	}
+
+	return t, members, nil
+}
+
+func getTeam(ctx context.Context, q querier, teamName string) (domain.Team, error) {
+	t := domain.Team{TeamName: teamName}
+	err := q.QueryRowContext(ctx,
+		`SELECT four_eyes FROM teams WHERE org_id=? AND team_name=?`, domain.OrgFromContext(ctx), teamName,
+	).Scan(&t.FourEyes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Team{}, domain.ErrNotFound
+	}
+	return t, err
+}
+
+func (r *TeamRepo) SetFourEyes(ctx context.Context, teamName string, on bool) (domain.Team, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Team{}, err
+	}
+	defer rollback(tx, "SetFourEyes")
+
+	before, err := getTeam(ctx, tx, teamName)
+	if err != nil {
+		return domain.Team{}, err
+	}
+	if before.FourEyes == on {
+		return before, nil
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE teams SET four_eyes=? WHERE org_id=? AND team_name=?`, on, domain.OrgFromContext(ctx), teamName,
+	); err != nil {
+		return domain.Team{}, err
+	}
+	after := before
+	after.FourEyes = on
+	if err := insertAudit(ctx, tx, domain.NewAuditEntry(ctx, domain.AuditTeamPolicyChanged, teamName,
+		domain.AuditTeam(before), domain.AuditTeam(after))); err != nil {
+		return domain.Team{}, err
+	}
+	return after, tx.Commit()
+}
+
+func (r *TeamRepo) UpsertUsersToTeam(ctx context.Context, teamName string, users []domain.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx, "UpsertUsersToTeam")
+
+	ts := now()
+	for _, u := range users {
+		before, err := lookupUser(ctx, tx, u.UserID)
+		if err != nil {
+			return err
+		}
+		if err := upsertUser(ctx, tx, teamName, before, u, ts); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// lookupUser is getUser with nil for a missing user.
+func lookupUser(ctx context.Context, tx *sql.Tx, userID string) (*domain.User, error) {
+	u, err := getUser(ctx, tx, userID)
+	switch {
+	case err == nil:
+		return &u, nil
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// upsertUser stores u as a member of teamName and audits the change from
+// before, the stored user or nil.
+func upsertUser(ctx context.Context, tx *sql.Tx, teamName string, before *domain.User, u domain.User, ts string) error {
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO users (org_id, user_id, username, team_name, is_active, role, email, timezone, manager_id, created_at, updated_at)
+		VALUES (?1,?2,?3,?4,?5,COALESCE(NULLIF(?6,''),'member'),?8,?9,?10,?7,?7)
+		ON CONFLICT (org_id, user_id) DO UPDATE
+		  SET username=excluded.username,
+		      team_name=excluded.team_name,
+		      is_active=excluded.is_active,
+		      role=CASE WHEN ?6 = '' THEN users.role ELSE excluded.role END,
+		      email=CASE WHEN ?8 = '' THEN users.email ELSE excluded.email END,
+		      timezone=CASE WHEN ?9 = '' THEN users.timezone ELSE excluded.timezone END,
+		      manager_id=CASE WHEN ?10 = '' THEN users.manager_id ELSE excluded.manager_id END,
+		      updated_at=excluded.updated_at
+	`, domain.OrgFromContext(ctx), u.UserID, u.Username, teamName, u.IsActive, string(u.Role), ts, u.Email, u.Timezone, u.ManagerID); err != nil {
+		return err
+	}
+
+	after, err := getUser(ctx, tx, u.UserID)
+	if err != nil {
+		return err
+	}
+	return insertAudit(ctx, tx, domain.NewAuditEntry(ctx, domain.AuditUserUpserted, u.UserID,
+		domain.AuditUser(before), domain.AuditUser(&after)))
+}
+
+func (r *TeamRepo) ListTeams(ctx context.Context) ([]domain.Team, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT team_name, four_eyes FROM teams WHERE org_id=? ORDER BY team_name`, domain.OrgFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []domain.Team
+	for rows.Next() {
+		var t domain.Team
+		if err := rows.Scan(&t.TeamName, &t.FourEyes); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (r *TeamRepo) ImportTeams(ctx context.Context, teams []domain.Team, dryRun bool) (domain.ImportReport, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+	defer rollback(tx, "ImportTeams")
+
+	report := domain.ImportReport{DryRun: dryRun}
+	org, ts := domain.OrgFromContext(ctx), now()
+	for _, t := range teams {
+		action := domain.ImportUnchanged
+		_, err := getTeam(ctx, tx, t.TeamName)
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			action = domain.ImportCreate
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO teams (org_id, team_name) VALUES (?,?)`, org, t.TeamName); err != nil {
+				return domain.ImportReport{}, err
+			}
+			if err := insertAudit(ctx, tx, domain.NewAuditEntry(ctx, domain.AuditTeamCreated, t.TeamName,
+				nil, domain.AuditTeam(domain.Team{TeamName: t.TeamName}))); err != nil {
+				return domain.ImportReport{}, err
+			}
+		case err != nil:
+			return domain.ImportReport{}, err
+		}
+		report.Changes = append(report.Changes, domain.ImportChange{
+			Kind: domain.ImportKindTeam, ID: t.TeamName, Action: action, TeamName: t.TeamName,
+		})
+
+		for _, u := range t.Members {
+			before, err := lookupUser(ctx, tx, u.UserID)
+			if err != nil {
+				return domain.ImportReport{}, err
+			}
+			c := domain.ImportChange{
+				Kind: domain.ImportKindUser, ID: u.UserID, TeamName: t.TeamName,
+				Action: domain.PlanUserImport(before, u, t.TeamName),
+			}
+			if c.Action == domain.ImportMove {
+				c.FromTeam = before.TeamName
+			}
+			report.Changes = append(report.Changes, c)
+			if c.Action == domain.ImportUnchanged {
+				continue
+			}
+			if err := upsertUser(ctx, tx, t.TeamName, before, u, ts); err != nil {
+				return domain.ImportReport{}, err
+			}
+		}
+	}
+
+	if dryRun {
+		return report, nil
+	}
+	return report, tx.Commit()
+}
+
+func (r *TeamRepo) DeleteTeam(ctx context.Context, teamName, moveTo string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx, "DeleteTeam")
+
+	t, err := getTeam(ctx, tx, teamName)
+	if err != nil {
+		return err
+	}
+	if _, err := getTeam(ctx, tx, moveTo); err != nil {
+		return err
+	}
+
+	org, ts := domain.OrgFromContext(ctx), now()
+	rows, err := tx.QueryContext(ctx,
+		`SELECT `+userColumns+` FROM users WHERE org_id=? AND team_name=? ORDER BY user_id`, org, teamName)
+	if err != nil {
+		return err
+	}
+	var members []domain.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			closeRows(rows)
+			return err
+		}
+		members = append(members, u)
+	}
+	closeRows(rows)
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, u := range members {
+		if err := upsertUser(ctx, tx, moveTo, &u, u, ts); err != nil {
+			return err
+		}
+	}
+
+	// Slack channels and GitLab projects go with the team by cascade.
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM teams WHERE org_id=? AND team_name=?`, org, teamName); err != nil {
+		return err
+	}
+	if err := insertAudit(ctx, tx, domain.NewAuditEntry(ctx, domain.AuditTeamDeleted, teamName, domain.AuditTeam(t), nil)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Printf("sqlite: rows close failed: %v", err)
+	}
+}
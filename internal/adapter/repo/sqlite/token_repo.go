@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type TokenRepo struct{ db *sql.DB }
+
+func NewTokenRepo(db *sql.DB) *TokenRepo { return &TokenRepo{db: db} }
+
+const tokenColumns = `token_id, org_id, name, scopes, cross_org, created_at, revoked_at`
+
+func (r *TokenRepo) CreateToken(ctx context.Context, t domain.APIToken, hash string) (domain.APIToken, error) {
+	scopes, err := json.Marshal(t.Scopes)
+	if err != nil {
+		return domain.APIToken{}, err
+	}
+	if _, err := r.db.ExecContext(ctx, `
+		INSERT INTO api_tokens (token_id, org_id, name, token_hash, scopes, cross_org, created_at)
+		VALUES (?,?,?,?,?,?,?)`, t.ID, t.OrgID, t.Name, hash, string(scopes), t.CrossOrg, now()); err != nil {
+		return domain.APIToken{}, err
+	}
+	return scanToken(r.db.QueryRowContext(ctx, `SELECT `+tokenColumns+` FROM api_tokens WHERE token_id=?`, t.ID))
+}
+
+func (r *TokenRepo) GetTokenByHash(ctx context.Context, hash string) (domain.APIToken, error) {
+	return scanToken(r.db.QueryRowContext(ctx, `SELECT `+tokenColumns+` FROM api_tokens WHERE token_hash=?`, hash))
+}
+
+func (r *TokenRepo) RevokeToken(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE api_tokens SET revoked_at = COALESCE(revoked_at, ?)
+		WHERE token_id=?`, now(), id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *TokenRepo) ListTokens(ctx context.Context) ([]domain.APIToken, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+tokenColumns+` FROM api_tokens ORDER BY created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []domain.APIToken
+	for rows.Next() {
+		t, err := scanToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanToken(row scanner) (domain.APIToken, error) {
+	var (
+		t               domain.APIToken
+		scopes, created string
+		revoked         sql.NullString
+	)
+	if err := row.Scan(&t.ID, &t.OrgID, &t.Name, &scopes, &t.CrossOrg, &created, &revoked); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.APIToken{}, domain.ErrNotFound
+		}
+		return domain.APIToken{}, err
+	}
+	if err := json.Unmarshal([]byte(scopes), &t.Scopes); err != nil {
+		return domain.APIToken{}, err
+	}
+	createdAt, err := parseTime(created)
+	if err != nil {
+		return domain.APIToken{}, err
+	}
+	t.CreatedAt = *createdAt
+	if revoked.Valid {
+		if t.RevokedAt, err = parseTime(revoked.String); err != nil {
+			return domain.APIToken{}, err
+		}
+	}
+	return t, nil
+}
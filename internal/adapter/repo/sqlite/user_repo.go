@@ -0,0 +1,149 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type UserRepo struct{ db *sql.DB }
+
+func NewUserRepo(db *sql.DB) *UserRepo { return &UserRepo{db: db} }
+
+func (r *UserRepo) GetByID(ctx context.Context, id string) (domain.User, error) {
+	return getUser(ctx, r.db, id)
+}
+
+const userColumns = `user_id, username, team_name, is_active, role, email, timezone, away_until, manager_id`
+
+func getUser(ctx context.Context, q querier, id string) (domain.User, error) {
+	u, err := scanUser(q.QueryRowContext(ctx, `
+		SELECT `+userColumns+`
+		FROM users WHERE org_id=? AND user_id=?`, domain.OrgFromContext(ctx), id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.User{}, domain.ErrNotFound
+		}
+		return domain.User{}, err
+	}
+	return u, nil
+}
+
+func scanUser(row scanner) (domain.User, error) {
+	var (
+		u    domain.User
+		away sql.NullString
+	)
+	if err := row.Scan(&u.UserID, &u.Username, &u.TeamName, &u.IsActive, &u.Role, &u.Email, &u.Timezone, &away, &u.ManagerID); err != nil {
+		return domain.User{}, err
+	}
+	if away.Valid {
+		t, err := parseTime(away.String)
+		if err != nil {
+			return domain.User{}, err
+		}
+		u.AwayUntil = t
+	}
+	return u, nil
+}
+
+func (r *UserRepo) SetActive(ctx context.Context, id string, active bool, e *domain.Event) (domain.User, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.User{}, err
+	}
+	defer rollback(tx, "SetActive")
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE users SET is_active=?, updated_at=?
+		WHERE org_id=? AND user_id=? AND is_active <> ?`, active, now(), domain.OrgFromContext(ctx), id, active)
+	if err != nil {
+		return domain.User{}, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return domain.User{}, err
+	}
+	u, err := getUser(ctx, tx, id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if n > 0 {
+		before := u
+		before.IsActive = !active
+		if err := insertAudit(ctx, tx, domain.NewAuditEntry(ctx, domain.AuditUserActivityChanged, id,
+			domain.AuditUser(&before), domain.AuditUser(&u))); err != nil {
+			return domain.User{}, err
+		}
+	}
+	if e != nil && n > 0 {
+		e.Data.User = domain.NewEventUser(u)
+		if err := insertOutbox(ctx, tx, e); err != nil {
+			return domain.User{}, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return domain.User{}, err
+	}
+	return u, nil
+}
+
+func (r *UserRepo) SetAway(ctx context.Context, id string, until *time.Time) (domain.User, error) {
+	var away any
+	if until != nil {
+		away = formatTime(*until)
+	}
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE users SET away_until=?, updated_at=?
+		WHERE org_id=? AND user_id=?`, away, now(), domain.OrgFromContext(ctx), id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return domain.User{}, err
+	}
+	if n == 0 {
+		return domain.User{}, domain.ErrNotFound
+	}
+	return getUser(ctx, r.db, id)
+}
+
+// ListActiveInTeamExcept passes the exclusion list as a JSON array and
+// expands it with json_each, which stands in for Postgres' ANY($2).
+func (r *UserRepo) ListActiveInTeamExcept(ctx context.Context, teamName string, excludeIDs []string, limit int) ([]domain.User, error) {
+	if excludeIDs == nil {
+		excludeIDs = []string{}
+	}
+	exclude, err := json.Marshal(excludeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+userColumns+`
+		FROM users
+		WHERE org_id=? AND team_name=? AND is_active=1
+		  AND user_id NOT IN (SELECT value FROM json_each(?))
+		ORDER BY random()
+		LIMIT ?
+	`, domain.OrgFromContext(ctx), teamName, string(exclude), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []domain.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
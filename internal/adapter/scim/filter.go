@@ -0,0 +1,394 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// filter is a parsed SCIM filter (RFC 7644, section 3.4.2.2). It is matched
+// against a resource in its JSON form, decoded into a map.
+type filter interface {
+	match(obj map[string]any) bool
+}
+
+type (
+	orFilter  struct{ l, r filter }
+	andFilter struct{ l, r filter }
+	notFilter struct{ f filter }
+	// valuePathFilter matches if an element of the multi-valued attribute
+	// matches f, as in emails[type eq "work"].
+	valuePathFilter struct {
+		attr string
+		f    filter
+	}
+	presentFilter struct{ path []string }
+	compareFilter struct {
+		path  []string
+		op    string
+		value any
+	}
+)
+
+func (f orFilter) match(obj map[string]any) bool  { return f.l.match(obj) || f.r.match(obj) }
+func (f andFilter) match(obj map[string]any) bool { return f.l.match(obj) && f.r.match(obj) }
+func (f notFilter) match(obj map[string]any) bool { return !f.f.match(obj) }
+
+func (f valuePathFilter) match(obj map[string]any) bool {
+	for _, v := range asList(lookup(obj, f.attr)) {
+		if m, ok := v.(map[string]any); ok && f.f.match(m) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f presentFilter) match(obj map[string]any) bool {
+	for _, v := range values(obj, f.path) {
+		if s, ok := v.(string); !ok || s != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (f compareFilter) match(obj map[string]any) bool {
+	vals := values(obj, f.path)
+	if f.op == "ne" {
+		return !(compareFilter{path: f.path, op: "eq", value: f.value}).match(obj)
+	}
+	if f.value == nil {
+		return f.op == "eq" && len(vals) == 0
+	}
+	exact := caseExact[strings.ToLower(f.path[len(f.path)-1])]
+	for _, v := range vals {
+		if compare(v, f.op, f.value, exact) {
+			return true
+		}
+	}
+	return false
+}
+
+// caseExact lists the attributes whose strings compare case-sensitively;
+// the rest, like userName, do not.
+var caseExact = map[string]bool{"id": true, "value": true, "externalid": true}
+
+func compare(attr any, op string, value any, exact bool) bool {
+	switch v := value.(type) {
+	case string:
+		a, ok := attr.(string)
+		if !ok {
+			return false
+		}
+		if !exact {
+			a, v = strings.ToLower(a), strings.ToLower(v)
+		}
+		switch op {
+		case "eq":
+			return a == v
+		case "co":
+			return strings.Contains(a, v)
+		case "sw":
+			return strings.HasPrefix(a, v)
+		case "ew":
+			return strings.HasSuffix(a, v)
+		case "gt":
+			return a > v
+		case "ge":
+			return a >= v
+		case "lt":
+			return a < v
+		case "le":
+			return a <= v
+		}
+	case bool:
+		a, ok := attr.(bool)
+		return ok && op == "eq" && a == v
+	case float64:
+		a, ok := attr.(float64)
+		if !ok {
+			return false
+		}
+		switch op {
+		case "eq":
+			return a == v
+		case "gt":
+			return a > v
+		case "ge":
+			return a >= v
+		case "lt":
+			return a < v
+		case "le":
+			return a <= v
+		}
+	}
+	return false
+}
+
+// values resolves an attribute path such as ["emails", "value"]. Elements
+// of a multi-valued attribute contribute their sub-attribute, or their
+// "value" when the path names none.
+func values(obj map[string]any, path []string) []any {
+	var out []any
+	for _, v := range asList(lookup(obj, path[0])) {
+		m, ok := v.(map[string]any)
+		switch {
+		case !ok:
+			if len(path) == 1 {
+				out = append(out, v)
+			}
+		case len(path) > 1:
+			out = append(out, values(m, path[1:])...)
+		default:
+			if x := lookup(m, "value"); x != nil {
+				out = append(out, x)
+			}
+		}
+	}
+	return out
+}
+
+// lookup finds an attribute by name, ignoring case as SCIM does.
+func lookup(obj map[string]any, name string) any {
+	if v, ok := obj[name]; ok {
+		return v
+	}
+	for k, v := range obj {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+func asList(v any) []any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []any:
+		return v
+	default:
+		return []any{v}
+	}
+}
+
+// attrPath splits an attribute name into its path. Attributes of the core
+// schemas may carry the schema URN; other URNs, like that of the enterprise
+// extension, stay the first element since resources nest their attributes
+// under it.
+func attrPath(s string) []string {
+	if !strings.HasPrefix(strings.ToLower(s), "urn:") {
+		return strings.Split(s, ".")
+	}
+	i := strings.LastIndex(s, ":")
+	urn, rest := s[:i], s[i+1:]
+	if strings.EqualFold(urn, schemaUser) || strings.EqualFold(urn, schemaGroup) {
+		return strings.Split(rest, ".")
+	}
+	return append([]string{urn}, strings.Split(rest, ".")...)
+}
+
+// parseFilter parses a filter expression. Precedence is "not" over "and"
+// over "or"; parentheses group.
+func parseFilter(s string) (filter, error) {
+	p := &filterParser{tokens: tokenize(s)}
+	f, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := p.peek(); ok {
+		return nil, fmt.Errorf("unexpected %q", t)
+	}
+	return f, nil
+}
+
+type filterParser struct {
+	tokens []string
+	pos    int
+}
+
+var compareOps = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true,
+	"gt": true, "ge": true, "lt": true, "le": true,
+}
+
+func (p *filterParser) peek() (string, bool) {
+	if p.pos >= len(p.tokens) {
+		return "", false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *filterParser) next() (string, error) {
+	t, ok := p.peek()
+	if !ok {
+		return "", fmt.Errorf("unexpected end of filter")
+	}
+	p.pos++
+	return t, nil
+}
+
+func (p *filterParser) expect(want string) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t != want {
+		return fmt.Errorf("expected %q, got %q", want, t)
+	}
+	return nil
+}
+
+func (p *filterParser) keyword(word string) bool {
+	if t, ok := p.peek(); ok && strings.EqualFold(t, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) or() (filter, error) {
+	l, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		r, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l = orFilter{l, r}
+	}
+	return l, nil
+}
+
+func (p *filterParser) and() (filter, error) {
+	l, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		r, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		l = andFilter{l, r}
+	}
+	return l, nil
+}
+
+func (p *filterParser) unary() (filter, error) {
+	if p.keyword("not") {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		f, err := p.group(")")
+		return notFilter{f}, err
+	}
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if t == "(" {
+		return p.group(")")
+	}
+	if strings.ContainsAny(t, `()[]"`) {
+		return nil, fmt.Errorf("expected an attribute, got %q", t)
+	}
+
+	if p.keyword("[") {
+		f, err := p.group("]")
+		return valuePathFilter{attr: t, f: f}, err
+	}
+	path := attrPath(t)
+	op, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	op = strings.ToLower(op)
+	if op == "pr" {
+		return presentFilter{path: path}, nil
+	}
+	if !compareOps[op] {
+		return nil, fmt.Errorf("unknown operator %q", op)
+	}
+	lit, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	value, err := literal(lit)
+	if err != nil {
+		return nil, err
+	}
+	return compareFilter{path: path, op: op, value: value}, nil
+}
+
+func (p *filterParser) group(closing string) (filter, error) {
+	f, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	return f, p.expect(closing)
+}
+
+// literal decodes a comparison value: a JSON string, number, boolean or
+// null.
+func literal(t string) (any, error) {
+	switch strings.ToLower(t) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if strings.HasPrefix(t, `"`) {
+		var s string
+		if err := json.Unmarshal([]byte(t), &s); err != nil {
+			return nil, fmt.Errorf("bad string %s", t)
+		}
+		return s, nil
+	}
+	n, err := strconv.ParseFloat(t, 64)
+	if err != nil {
+		return nil, fmt.Errorf("bad value %q", t)
+	}
+	return n, nil
+}
+
+// tokenize splits a filter into parentheses, brackets, quoted strings and
+// words. An unterminated string becomes a token of its own, which the
+// parser then rejects.
+func tokenize(s string) []string {
+	var out []string
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.IndexByte("()[]", c) >= 0:
+			out = append(out, string(c))
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(s))
+			out = append(out, s[i:j])
+			i = j
+		default:
+			j := i
+			for j < len(s) && strings.IndexByte(" \t\n\r()[]\"", s[j]) < 0 {
+				j++
+			}
+			out = append(out, s[i:j])
+			i = j
+		}
+	}
+	return out
+}
@@ -0,0 +1,407 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+const (
+	schemaUser       = "urn:ietf:params:scim:schemas:core:2.0:User"
+	schemaGroup      = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	schemaEnterprise = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+	schemaConfig     = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	schemaList       = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	schemaPatch      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	schemaError      = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// userResource is a user as SCIM sees it: id is user_id, userName is
+// username, and the one group is the user's team.
+type userResource struct {
+	Schemas    []string     `json:"schemas"`
+	ID         string       `json:"id"`
+	UserName   string       `json:"userName"`
+	Active     bool         `json:"active"`
+	Emails     []multiValue `json:"emails,omitempty"`
+	Timezone   string       `json:"timezone,omitempty"`
+	Groups     []multiValue `json:"groups,omitempty"`
+	Enterprise *enterprise  `json:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User,omitempty"`
+	Meta       meta         `json:"meta"`
+}
+
+// groupResource is a team: both id and displayName are the team name.
+type groupResource struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id"`
+	DisplayName string       `json:"displayName"`
+	Members     []multiValue `json:"members"`
+	Meta        meta         `json:"meta"`
+}
+
+type multiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+type enterprise struct {
+	Manager *managerRef `json:"manager,omitempty"`
+}
+
+type managerRef struct {
+	Value string `json:"value"`
+}
+
+type meta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location"`
+}
+
+func toUser(u domain.User, base string) userResource {
+	res := userResource{
+		Schemas:  []string{schemaUser},
+		ID:       u.UserID,
+		UserName: u.Username,
+		Active:   u.IsActive,
+		Timezone: u.Timezone,
+		Groups: []multiValue{{
+			Value: u.TeamName, Display: u.TeamName, Ref: base + "/Groups/" + url.PathEscape(u.TeamName),
+		}},
+		Meta: meta{ResourceType: "User", Location: base + "/Users/" + url.PathEscape(u.UserID)},
+	}
+	if u.Email != "" {
+		res.Emails = []multiValue{{Value: u.Email, Type: "work", Primary: true}}
+	}
+	if u.ManagerID != "" {
+		res.Schemas = append(res.Schemas, schemaEnterprise)
+		res.Enterprise = &enterprise{Manager: &managerRef{Value: u.ManagerID}}
+	}
+	return res
+}
+
+func toGroup(t domain.Team, base string) groupResource {
+	res := groupResource{
+		Schemas:     []string{schemaGroup},
+		ID:          t.TeamName,
+		DisplayName: t.TeamName,
+		Members:     make([]multiValue, 0, len(t.Members)),
+		Meta:        meta{ResourceType: "Group", Location: base + "/Groups/" + url.PathEscape(t.TeamName)},
+	}
+	for _, m := range t.Members {
+		res.Members = append(res.Members, multiValue{
+			Value: m.UserID, Display: m.Username, Ref: base + "/Users/" + url.PathEscape(m.UserID),
+		})
+	}
+	return res
+}
+
+// userInput is a posted user. Attributes the service does not store, such
+// as name or locale, are accepted and dropped.
+type userInput struct {
+	UserName   string       `json:"userName"`
+	ExternalID string       `json:"externalId"`
+	Active     *bool        `json:"active"`
+	Emails     []multiValue `json:"emails"`
+	Timezone   string       `json:"timezone"`
+	Enterprise *enterprise  `json:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"`
+}
+
+// user maps a posted user. The id is the externalId the provider sent, or
+// the userName without one, so that it matches the subject of the
+// provider's tokens.
+func (in userInput) user() domain.User {
+	u := domain.User{
+		UserID:   in.ExternalID,
+		Username: in.UserName,
+		IsActive: in.Active == nil || *in.Active,
+		Email:    primaryEmail(in.Emails),
+		Timezone: in.Timezone,
+	}
+	if u.UserID == "" {
+		u.UserID = in.UserName
+	}
+	if in.Enterprise != nil && in.Enterprise.Manager != nil {
+		u.ManagerID = in.Enterprise.Manager.Value
+	}
+	return u
+}
+
+func primaryEmail(emails []multiValue) string {
+	for _, e := range emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(emails) > 0 {
+		return emails[0].Value
+	}
+	return ""
+}
+
+type groupInput struct {
+	DisplayName string       `json:"displayName"`
+	Members     []multiValue `json:"members"`
+}
+
+type patchRequest struct {
+	Schemas    []string  `json:"schemas"`
+	Operations []patchOp `json:"Operations"`
+}
+
+type patchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// patchError is a PATCH the service cannot apply; scimType is its SCIM
+// error type.
+type patchError struct {
+	scimType string
+	detail   string
+}
+
+func (e *patchError) Error() string { return e.detail }
+
+func invalidValue(format string, args ...any) error {
+	return &patchError{scimType: "invalidValue", detail: fmt.Sprintf(format, args...)}
+}
+
+// patchPath normalizes an operation path for matching: lower case, with
+// the core schema URN and any value filter dropped. Since a user has one
+// email, emails[type eq "work"].value is emails.value.
+func patchPath(p string) string {
+	if i, j := strings.IndexByte(p, '['), strings.LastIndexByte(p, ']'); i >= 0 && j > i {
+		p = p[:i] + p[j+1:]
+	}
+	return strings.ToLower(strings.Join(attrPath(p), "."))
+}
+
+var managerPath = strings.ToLower(schemaEnterprise) + ".manager"
+
+// applyUserPatch applies the operations to u in order. Attributes the
+// service does not store are ignored. Stored attributes other than active
+// cannot be removed, only replaced, because an empty value keeps the
+// stored one.
+func applyUserPatch(u *domain.User, ops []patchOp) error {
+	for _, op := range ops {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+			if op.Path != "" {
+				if err := setUserAttr(u, op.Path, op.Value); err != nil {
+					return err
+				}
+				continue
+			}
+			var attrs map[string]json.RawMessage
+			if err := json.Unmarshal(op.Value, &attrs); err != nil {
+				return invalidValue("a %s without a path needs an object value", op.Op)
+			}
+			for name, v := range attrs {
+				if err := setUserAttr(u, name, v); err != nil {
+					return err
+				}
+			}
+		case "remove":
+			switch patchPath(op.Path) {
+			case "":
+				return &patchError{scimType: "noTarget", detail: "remove needs a path"}
+			case "active", "username", "emails", "emails.value", "timezone", managerPath, managerPath + ".value":
+				return &patchError{scimType: "mutability", detail: op.Path + " cannot be removed"}
+			}
+		default:
+			return invalidValue("unknown op %q", op.Op)
+		}
+	}
+	return nil
+}
+
+func setUserAttr(u *domain.User, path string, raw json.RawMessage) error {
+	if strings.EqualFold(path, schemaEnterprise) {
+		var attrs map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &attrs); err != nil {
+			return invalidValue("%s must be an object", path)
+		}
+		for name, v := range attrs {
+			if err := setUserAttr(u, schemaEnterprise+":"+name, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var err error
+	switch patchPath(path) {
+	case "active":
+		u.IsActive, err = boolValue(raw)
+	case "username":
+		err = json.Unmarshal(raw, &u.Username)
+	case "timezone":
+		err = json.Unmarshal(raw, &u.Timezone)
+	case "emails.value":
+		err = json.Unmarshal(raw, &u.Email)
+	case "emails":
+		var emails []multiValue
+		if err = json.Unmarshal(raw, &emails); err == nil {
+			u.Email = primaryEmail(emails)
+		}
+	case managerPath:
+		// Some providers send the manager's id rather than an object.
+		var ref managerRef
+		if err = json.Unmarshal(raw, &ref); err != nil {
+			err = json.Unmarshal(raw, &ref.Value)
+		}
+		u.ManagerID = ref.Value
+	case managerPath + ".value":
+		err = json.Unmarshal(raw, &u.ManagerID)
+	case "id", "groups":
+		return &patchError{scimType: "mutability", detail: path + " is read-only"}
+	}
+	if err != nil {
+		return invalidValue("bad value for %s", path)
+	}
+	return nil
+}
+
+// boolValue also accepts "True" and "False" as strings, which some
+// providers send.
+func boolValue(raw json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(s)
+}
+
+// applyGroupPatch applies the operations to the member ids of team in
+// order. The name of a team cannot change.
+func applyGroupPatch(team domain.Team, ops []patchOp) ([]string, error) {
+	members := make([]string, 0, len(team.Members))
+	for _, m := range team.Members {
+		members = append(members, m.UserID)
+	}
+	add := func(ids []string) {
+		for _, id := range ids {
+			if !slices.Contains(members, id) {
+				members = append(members, id)
+			}
+		}
+	}
+	setName := func(raw json.RawMessage) error {
+		var name string
+		if err := json.Unmarshal(raw, &name); err != nil {
+			return invalidValue("displayName must be a string")
+		}
+		if name != team.TeamName {
+			return &patchError{scimType: "mutability", detail: "teams cannot be renamed"}
+		}
+		return nil
+	}
+
+	for _, op := range ops {
+		kind := strings.ToLower(op.Op)
+		path := patchPath(op.Path)
+		switch {
+		case (kind == "add" || kind == "replace") && path == "":
+			var attrs map[string]json.RawMessage
+			if err := json.Unmarshal(op.Value, &attrs); err != nil {
+				return nil, invalidValue("a %s without a path needs an object value", op.Op)
+			}
+			for name, v := range attrs {
+				switch patchPath(name) {
+				case "displayname":
+					if err := setName(v); err != nil {
+						return nil, err
+					}
+				case "members":
+					ids, err := memberIDs(v)
+					if err != nil {
+						return nil, err
+					}
+					if kind == "replace" {
+						members = members[:0]
+					}
+					add(ids)
+				}
+			}
+		case (kind == "add" || kind == "replace") && path == "displayname":
+			if err := setName(op.Value); err != nil {
+				return nil, err
+			}
+		case (kind == "add" || kind == "replace") && path == "members":
+			ids, err := memberIDs(op.Value)
+			if err != nil {
+				return nil, err
+			}
+			if kind == "replace" {
+				members = members[:0]
+			}
+			add(ids)
+		case kind == "remove" && path == "members":
+			drop, err := removedMembers(op, members)
+			if err != nil {
+				return nil, err
+			}
+			members = slices.DeleteFunc(members, func(id string) bool { return slices.Contains(drop, id) })
+		case kind == "remove" && path == "displayname":
+			return nil, &patchError{scimType: "mutability", detail: "displayName cannot be removed"}
+		case kind != "add" && kind != "replace" && kind != "remove":
+			return nil, invalidValue("unknown op %q", op.Op)
+		}
+	}
+	return members, nil
+}
+
+// removedMembers resolves a remove of members: a value filter such as
+// members[value eq "u1"], a list of members in the value, or, with
+// neither, every member.
+func removedMembers(op patchOp, members []string) ([]string, error) {
+	if i := strings.IndexByte(op.Path, '['); i >= 0 {
+		j := strings.LastIndexByte(op.Path, ']')
+		if j < i {
+			return nil, &patchError{scimType: "invalidPath", detail: "bad path " + op.Path}
+		}
+		f, err := parseFilter(op.Path[i+1 : j])
+		if err != nil {
+			return nil, &patchError{scimType: "invalidFilter", detail: err.Error()}
+		}
+		var out []string
+		for _, id := range members {
+			if f.match(map[string]any{"value": id}) {
+				out = append(out, id)
+			}
+		}
+		return out, nil
+	}
+	if len(op.Value) == 0 || string(op.Value) == "null" {
+		return slices.Clone(members), nil
+	}
+	return memberIDs(op.Value)
+}
+
+func memberIDs(raw json.RawMessage) ([]string, error) {
+	var list []multiValue
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, invalidValue("members must be a list of {\"value\": user id}")
+	}
+	ids := make([]string, 0, len(list))
+	for _, m := range list {
+		if m.Value == "" {
+			return nil, invalidValue("a member has no value")
+		}
+		ids = append(ids, m.Value)
+	}
+	return ids, nil
+}
@@ -0,0 +1,392 @@
+// Package scim serves /scim/v2, the SCIM 2.0 API (RFC 7643, RFC 7644)
+// identity providers use to provision users and teams. Users map onto
+// users and groups onto teams; deleting a user deactivates it.
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+// Prefix is where the handler is mounted.
+const Prefix = "/scim/v2"
+
+const (
+	// maxBody bounds requests; a group with a few thousand members fits.
+	maxBody = 1 << 20
+	// maxCount bounds a page of a list; a larger count is lowered to it.
+	maxCount = 1000
+)
+
+type Handler struct {
+	dir *usecase.DirectoryUsecase
+	log *slog.Logger
+	mux *http.ServeMux
+}
+
+func New(dir *usecase.DirectoryUsecase, logger *slog.Logger) *Handler {
+	h := &Handler{dir: dir, log: logger, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET "+Prefix+"/ServiceProviderConfig", h.config)
+	h.mux.HandleFunc("GET "+Prefix+"/Users", h.listUsers)
+	h.mux.HandleFunc("POST "+Prefix+"/Users", h.createUser)
+	h.mux.HandleFunc("GET "+Prefix+"/Users/{id}", h.getUser)
+	h.mux.HandleFunc("PATCH "+Prefix+"/Users/{id}", h.patchUser)
+	h.mux.HandleFunc("DELETE "+Prefix+"/Users/{id}", h.deleteUser)
+	h.mux.HandleFunc("GET "+Prefix+"/Groups", h.listGroups)
+	h.mux.HandleFunc("POST "+Prefix+"/Groups", h.createGroup)
+	h.mux.HandleFunc("GET "+Prefix+"/Groups/{id}", h.getGroup)
+	h.mux.HandleFunc("PATCH "+Prefix+"/Groups/{id}", h.patchGroup)
+	h.mux.HandleFunc("DELETE "+Prefix+"/Groups/{id}", h.deleteGroup)
+	h.mux.HandleFunc(Prefix+"/", func(w http.ResponseWriter, _ *http.Request) {
+		writeError(w, http.StatusNotFound, "", "no such resource")
+	})
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+type errorBody struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+type listResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+func (h *Handler) config(w http.ResponseWriter, r *http.Request) {
+	supported := func(on bool) map[string]bool { return map[string]bool{"supported": on} }
+	writeJSON(w, http.StatusOK, map[string]any{
+		"schemas":        []string{schemaConfig},
+		"patch":          supported(true),
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": maxCount},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]string{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "An API token with the admin scope",
+		}},
+		"meta": meta{ResourceType: "ServiceProviderConfig", Location: baseURL(r) + "/ServiceProviderConfig"},
+	})
+}
+
+func (h *Handler) listUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.dir.ListUsers(r.Context())
+	if err != nil {
+		h.writeUsecaseError(w, "list users", err)
+		return
+	}
+	base := baseURL(r)
+	resources := make([]any, 0, len(users))
+	for _, u := range users {
+		resources = append(resources, toUser(u, base))
+	}
+	h.writeList(w, r, resources)
+}
+
+func (h *Handler) getUser(w http.ResponseWriter, r *http.Request) {
+	u, err := h.dir.GetUser(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeUsecaseError(w, "get user", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toUser(u, baseURL(r)))
+}
+
+func (h *Handler) createUser(w http.ResponseWriter, r *http.Request) {
+	var in userInput
+	if !decode(w, r, &in) {
+		return
+	}
+	u, err := h.dir.CreateUser(r.Context(), in.user())
+	if err != nil {
+		h.writeUsecaseError(w, "create user", err)
+		return
+	}
+	h.log.Info("scim: user created", "org", domain.OrgFromContext(r.Context()), "user_id", u.UserID, "team", u.TeamName)
+	res := toUser(u, baseURL(r))
+	w.Header().Set("Location", res.Meta.Location)
+	writeJSON(w, http.StatusCreated, res)
+}
+
+func (h *Handler) patchUser(w http.ResponseWriter, r *http.Request) {
+	var req patchRequest
+	if !decodePatch(w, r, &req) {
+		return
+	}
+	u, err := h.dir.GetUser(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeUsecaseError(w, "patch user", err)
+		return
+	}
+	wasActive := u.IsActive
+	if err := applyUserPatch(&u, req.Operations); err != nil {
+		writePatchError(w, err)
+		return
+	}
+	u, err = h.dir.UpdateUser(r.Context(), u)
+	if err != nil {
+		h.writeUsecaseError(w, "patch user", err)
+		return
+	}
+	if wasActive != u.IsActive {
+		h.log.Info("scim: user activity changed", "org", domain.OrgFromContext(r.Context()), "user_id", u.UserID, "active", u.IsActive)
+	}
+	writeJSON(w, http.StatusOK, toUser(u, baseURL(r)))
+}
+
+// deleteUser deactivates the user rather than deleting it, so the user
+// is still listed afterwards, with active false.
+func (h *Handler) deleteUser(w http.ResponseWriter, r *http.Request) {
+	u, err := h.dir.DeactivateUser(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeUsecaseError(w, "delete user", err)
+		return
+	}
+	h.log.Info("scim: user deprovisioned", "org", domain.OrgFromContext(r.Context()), "user_id", u.UserID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) listGroups(w http.ResponseWriter, r *http.Request) {
+	teams, err := h.dir.ListTeams(r.Context())
+	if err != nil {
+		h.writeUsecaseError(w, "list groups", err)
+		return
+	}
+	base := baseURL(r)
+	resources := make([]any, 0, len(teams))
+	for _, t := range teams {
+		resources = append(resources, toGroup(t, base))
+	}
+	h.writeList(w, r, resources)
+}
+
+func (h *Handler) getGroup(w http.ResponseWriter, r *http.Request) {
+	t, err := h.dir.GetTeam(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeUsecaseError(w, "get group", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toGroup(t, baseURL(r)))
+}
+
+func (h *Handler) createGroup(w http.ResponseWriter, r *http.Request) {
+	var in groupInput
+	if !decode(w, r, &in) {
+		return
+	}
+	ids := make([]string, 0, len(in.Members))
+	for _, m := range in.Members {
+		ids = append(ids, m.Value)
+	}
+	t, err := h.dir.CreateTeam(r.Context(), in.DisplayName, ids)
+	if err != nil {
+		h.writeUsecaseError(w, "create group", err)
+		return
+	}
+	h.log.Info("scim: group created", "org", domain.OrgFromContext(r.Context()), "team", t.TeamName, "members", len(t.Members))
+	res := toGroup(t, baseURL(r))
+	w.Header().Set("Location", res.Meta.Location)
+	writeJSON(w, http.StatusCreated, res)
+}
+
+func (h *Handler) patchGroup(w http.ResponseWriter, r *http.Request) {
+	var req patchRequest
+	if !decodePatch(w, r, &req) {
+		return
+	}
+	t, err := h.dir.GetTeam(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeUsecaseError(w, "patch group", err)
+		return
+	}
+	members, err := applyGroupPatch(t, req.Operations)
+	if err != nil {
+		writePatchError(w, err)
+		return
+	}
+	var add, remove []string
+	for _, id := range members {
+		if !slices.ContainsFunc(t.Members, func(m domain.User) bool { return m.UserID == id }) {
+			add = append(add, id)
+		}
+	}
+	for _, m := range t.Members {
+		if !slices.Contains(members, m.UserID) {
+			remove = append(remove, m.UserID)
+		}
+	}
+	t, err = h.dir.ChangeMembers(r.Context(), t.TeamName, add, remove)
+	if err != nil {
+		h.writeUsecaseError(w, "patch group", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toGroup(t, baseURL(r)))
+}
+
+// deleteGroup deletes the team; its members move to the default team.
+func (h *Handler) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("id")
+	if err := h.dir.DeleteTeam(r.Context(), name); err != nil {
+		h.writeUsecaseError(w, "delete group", err)
+		return
+	}
+	h.log.Info("scim: group deleted", "org", domain.OrgFromContext(r.Context()), "team", name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeList filters the resources by the filter query parameter and writes
+// the page given by startIndex, which counts from 1, and count.
+func (h *Handler) writeList(w http.ResponseWriter, r *http.Request, resources []any) {
+	q := r.URL.Query()
+	start, count := 1, maxCount
+	if v := q.Get("startIndex"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalidValue", "startIndex must be a number")
+			return
+		}
+		start = max(n, 1)
+	}
+	if v := q.Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalidValue", "count must be a number")
+			return
+		}
+		count = min(max(n, 0), maxCount)
+	}
+
+	if expr := q.Get("filter"); expr != "" {
+		f, err := parseFilter(expr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalidFilter", err.Error())
+			return
+		}
+		matched := resources[:0]
+		for _, res := range resources {
+			obj, err := asObject(res)
+			if err != nil {
+				h.log.Error("scim: failed to encode resource", "err", err)
+				writeError(w, http.StatusInternalServerError, "", "internal error")
+				return
+			}
+			if f.match(obj) {
+				matched = append(matched, res)
+			}
+		}
+		resources = matched
+	}
+
+	page := resources[min(start-1, len(resources)):]
+	page = page[:min(count, len(page))]
+	writeJSON(w, http.StatusOK, listResponse{
+		Schemas:      []string{schemaList},
+		TotalResults: len(resources),
+		StartIndex:   start,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	})
+}
+
+// asObject turns a resource into the form filters are matched against.
+func asObject(res any) (map[string]any, error) {
+	b, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]any
+	return obj, json.Unmarshal(b, &obj)
+}
+
+// baseURL is the absolute URL of the API root, for meta.location. Behind a
+// TLS-terminating proxy the scheme comes from X-Forwarded-Proto.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + Prefix
+}
+
+// decodePatch also checks that the body is a PatchOp message.
+func decodePatch(w http.ResponseWriter, r *http.Request, req *patchRequest) bool {
+	if !decode(w, r, req) {
+		return false
+	}
+	if !slices.Contains(req.Schemas, schemaPatch) {
+		writeError(w, http.StatusBadRequest, "invalidSyntax", "schemas must list "+schemaPatch)
+		return false
+	}
+	return true
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody)).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalidSyntax", "bad JSON: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func (h *Handler) writeUsecaseError(w http.ResponseWriter, op string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		writeError(w, http.StatusNotFound, "", "no such resource")
+	case errors.Is(err, domain.ErrUserExists):
+		writeError(w, http.StatusConflict, "uniqueness", "a user with this id exists")
+	case errors.Is(err, domain.ErrTeamExists):
+		writeError(w, http.StatusConflict, "uniqueness", "a team with this name exists")
+	case errors.Is(err, domain.ErrInvalid):
+		writeError(w, http.StatusBadRequest, "invalidValue",
+			strings.TrimPrefix(err.Error(), domain.ErrInvalid.Error()+": "))
+	case errors.Is(err, domain.ErrForbidden):
+		writeError(w, http.StatusForbidden, "", "only admins may provision users and teams")
+	default:
+		h.log.Error("scim: "+op+" failed", "err", err)
+		writeError(w, http.StatusInternalServerError, "", "internal error")
+	}
+}
+
+func writePatchError(w http.ResponseWriter, err error) {
+	var pe *patchError
+	if errors.As(err, &pe) {
+		writeError(w, http.StatusBadRequest, pe.scimType, pe.detail)
+		return
+	}
+	writeError(w, http.StatusBadRequest, "invalidValue", err.Error())
+}
+
+func writeError(w http.ResponseWriter, status int, scimType, detail string) {
+	writeJSON(w, status, errorBody{
+		Schemas:  []string{schemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,228 @@
+package scim_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/scim"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+type env struct {
+	h      *scim.Handler
+	users  *memory.UserRepo
+	outbox *memory.OutboxRepo
+}
+
+// newEnv seeds team "backend" with u1 (alice, an admin) and u2 (bob), and
+// team "frontend" with u3 (carol).
+func newEnv(t *testing.T) env {
+	t.Helper()
+	ctx := context.Background()
+
+	s := memory.NewStore()
+	teams, users := memory.NewTeamRepo(s), memory.NewUserRepo(s)
+	for team, members := range map[string][]domain.User{
+		"backend": {
+			{UserID: "u1", Username: "alice", IsActive: true, Role: domain.RoleAdmin, Email: "alice@example.com"},
+			{UserID: "u2", Username: "bob", IsActive: true},
+		},
+		"frontend": {{UserID: "u3", Username: "carol", IsActive: true}},
+	} {
+		if err := teams.CreateTeam(ctx, team); err != nil {
+			t.Fatal(err)
+		}
+		if err := teams.UpsertUsersToTeam(ctx, team, members); err != nil {
+			t.Fatal(err)
+		}
+	}
+	userUC := usecase.NewUserUsecase(users, memory.NewPRRepo(s))
+	dir := usecase.NewDirectoryUsecase(teams, users, userUC, "unassigned")
+	return env{h: scim.New(dir, slog.New(slog.DiscardHandler)), users: users, outbox: memory.NewOutboxRepo(s)}
+}
+
+func (e env) do(t *testing.T, method, target, body string, want int) map[string]any {
+	t.Helper()
+	return e.doAs(t, context.Background(), method, target, body, want)
+}
+
+func (e env) doAs(t *testing.T, ctx context.Context, method, target, body string, want int) map[string]any {
+	t.Helper()
+	req := httptest.NewRequestWithContext(ctx, method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/scim+json")
+	w := httptest.NewRecorder()
+	e.h.ServeHTTP(w, req)
+	if w.Code != want {
+		t.Fatalf("%s %s: got %d %s, want %d", method, target, w.Code, w.Body, want)
+	}
+	if w.Code == http.StatusNoContent {
+		return nil
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/scim+json" {
+		t.Fatalf("%s %s: content type %q", method, target, ct)
+	}
+	var got map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func ids(list map[string]any, key string) []string {
+	var out []string
+	for _, r := range list["Resources"].([]any) {
+		out = append(out, r.(map[string]any)[key].(string))
+	}
+	return out
+}
+
+func TestListUsersFilter(t *testing.T) {
+	e := newEnv(t)
+
+	for _, tc := range []struct{ filter, want string }{
+		{`userName eq "Alice"`, "u1"},
+		{`userName sw "b" or userName ew "ol"`, "u2,u3"},
+		{`emails[type eq "work" and value co "example"]`, "u1"},
+		{`groups.value eq "backend" and not (id eq "u1")`, "u2"},
+		{`emails pr`, "u1"},
+		{`active eq true and userName gt "alice"`, "u2,u3"},
+		{`urn:ietf:params:scim:schemas:core:2.0:User:id ne "u2"`, "u1,u3"},
+	} {
+		got := e.do(t, http.MethodGet, "/scim/v2/Users?filter="+url.QueryEscape(tc.filter), "", http.StatusOK)
+		if s := strings.Join(ids(got, "id"), ","); s != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.filter, s, tc.want)
+		}
+	}
+
+	page := e.do(t, http.MethodGet, "/scim/v2/Users?startIndex=2&count=1", "", http.StatusOK)
+	if page["totalResults"] != 3.0 || page["itemsPerPage"] != 1.0 || ids(page, "id")[0] != "u2" {
+		t.Fatalf("page: got %v", page)
+	}
+
+	bad := e.do(t, http.MethodGet, "/scim/v2/Users?filter="+url.QueryEscape(`userName eq`), "", http.StatusBadRequest)
+	if bad["scimType"] != "invalidFilter" {
+		t.Fatalf("bad filter: got %v", bad)
+	}
+}
+
+func TestUserLifecycle(t *testing.T) {
+	e := newEnv(t)
+	ctx := context.Background()
+
+	created := e.do(t, http.MethodPost, "/scim/v2/Users", `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"userName": "dave", "externalId": "u4", "name": {"givenName": "Dave"},
+		"emails": [{"value": "dave@example.com", "primary": true}]
+	}`, http.StatusCreated)
+	if created["id"] != "u4" || created["active"] != true || created["groups"].([]any)[0].(map[string]any)["value"] != "unassigned" {
+		t.Fatalf("create: got %v", created)
+	}
+	e.do(t, http.MethodPost, "/scim/v2/Users", `{"userName": "dave", "externalId": "u4"}`, http.StatusConflict)
+
+	patched := e.do(t, http.MethodPatch, "/scim/v2/Users/u4", `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [
+			{"op": "Replace", "path": "emails[type eq \"work\"].value", "value": "d@example.com"},
+			{"op": "replace", "value": {"timezone": "Europe/Berlin", "title": "Engineer",
+				"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:manager": "u1"}}
+		]
+	}`, http.StatusOK)
+	u4, err := e.users.GetByID(ctx, "u4")
+	if err != nil || u4.Email != "d@example.com" || u4.Timezone != "Europe/Berlin" || u4.ManagerID != "u1" || !u4.IsActive {
+		t.Fatalf("patch: got %+v, %v (%v)", u4, err, patched)
+	}
+
+	// Deprovisioning in the provider, as a PATCH or a DELETE, deactivates.
+	e.do(t, http.MethodPatch, "/scim/v2/Users/u4", `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "replace", "path": "active", "value": "False"}]
+	}`, http.StatusOK)
+	e.do(t, http.MethodPatch, "/scim/v2/Users/u4", `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "replace", "value": {"active": true}}]
+	}`, http.StatusOK)
+	e.do(t, http.MethodDelete, "/scim/v2/Users/u4", "", http.StatusNoContent)
+	if got := e.do(t, http.MethodGet, "/scim/v2/Users/u4", "", http.StatusOK); got["active"] != false {
+		t.Fatalf("after delete: got %v", got)
+	}
+	msgs, err := e.outbox.ListOutbox(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changes int
+	for _, m := range msgs {
+		if m.Event.Type == domain.EventUserActivityChanged && m.Event.Data.User.UserID == "u4" {
+			changes++
+		}
+	}
+	if changes != 3 {
+		t.Fatalf("got %d user.activity_changed events, want 3", changes)
+	}
+
+	e.do(t, http.MethodDelete, "/scim/v2/Users/ghost", "", http.StatusNotFound)
+	e.do(t, http.MethodPatch, "/scim/v2/Users/u4", `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "remove", "path": "timezone"}]
+	}`, http.StatusBadRequest)
+	e.do(t, http.MethodPatch, "/scim/v2/Users/u4", `{"Operations": []}`, http.StatusBadRequest)
+	e.doAs(t, domain.WithPrincipal(ctx, domain.Principal{UserID: "u2"}), http.MethodDelete, "/scim/v2/Users/u3", "", http.StatusForbidden)
+}
+
+func TestGroups(t *testing.T) {
+	e := newEnv(t)
+	ctx := context.Background()
+	patch := func(ops string) string {
+		return `{"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"], "Operations": ` + ops + `}`
+	}
+	teamOf := func(id string) string {
+		t.Helper()
+		u, err := e.users.GetByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u.TeamName
+	}
+
+	list := e.do(t, http.MethodGet, "/scim/v2/Groups?filter="+url.QueryEscape(`members[value eq "u3"]`), "", http.StatusOK)
+	if s := strings.Join(ids(list, "displayName"), ","); s != "frontend" {
+		t.Fatalf("filter by member: got %q", s)
+	}
+
+	created := e.do(t, http.MethodPost, "/scim/v2/Groups", `{"displayName": "platform", "members": [{"value": "u2"}]}`, http.StatusCreated)
+	if created["id"] != "platform" || teamOf("u2") != "platform" {
+		t.Fatalf("create: got %v", created)
+	}
+	e.do(t, http.MethodPost, "/scim/v2/Groups", `{"displayName": "platform"}`, http.StatusConflict)
+	e.do(t, http.MethodPost, "/scim/v2/Groups", `{"displayName": "ops", "members": [{"value": "ghost"}]}`, http.StatusBadRequest)
+
+	e.do(t, http.MethodPatch, "/scim/v2/Groups/platform", patch(`[
+		{"op": "add", "path": "members", "value": [{"value": "u3"}]},
+		{"op": "remove", "path": "members[value eq \"u2\"]"}
+	]`), http.StatusOK)
+	if teamOf("u3") != "platform" || teamOf("u2") != "unassigned" {
+		t.Fatalf("patch members: u2 in %s, u3 in %s", teamOf("u2"), teamOf("u3"))
+	}
+	replaced := e.do(t, http.MethodPatch, "/scim/v2/Groups/platform", patch(`[
+		{"op": "replace", "value": {"displayName": "platform", "members": [{"value": "u1"}]}}
+	]`), http.StatusOK)
+	if len(replaced["members"].([]any)) != 1 || teamOf("u1") != "platform" || teamOf("u3") != "unassigned" {
+		t.Fatalf("replace members: got %v", replaced)
+	}
+	e.do(t, http.MethodPatch, "/scim/v2/Groups/platform", patch(`[{"op": "replace", "path": "displayName", "value": "infra"}]`), http.StatusBadRequest)
+
+	e.do(t, http.MethodDelete, "/scim/v2/Groups/platform", "", http.StatusNoContent)
+	e.do(t, http.MethodGet, "/scim/v2/Groups/platform", "", http.StatusNotFound)
+	if teamOf("u1") != "unassigned" {
+		t.Fatalf("after delete: u1 in %s", teamOf("u1"))
+	}
+	e.do(t, http.MethodDelete, "/scim/v2/Groups/unassigned", "", http.StatusBadRequest)
+	e.do(t, http.MethodGet, "/scim/v2/Nope", "", http.StatusNotFound)
+}
@@ -0,0 +1,194 @@
+// Package slack posts reviewer notifications to Slack incoming webhooks, or
+// to any chat that accepts the same {"text": ...} payload.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/prlink"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+// DefaultBaseURL is the host Slack issues incoming webhook URLs on.
+const DefaultBaseURL = "https://hooks.slack.com"
+
+// DefaultTemplates render messages in Slack mrkdwn. A template is looked up
+// by event type; "pr" formats the linked PR name.
+const DefaultTemplates = `
+{{- define "pr"}}{{if .Link}}<{{.Link}}|{{.Name}}>{{else}}*{{.Name}}*{{end}}{{end}}
+{{- define "pr.created" -}}
+:eyes: {{join .New ", "}}, please review {{template "pr" .}} by {{.Author}}.
+Reviewers: {{join .Reviewers ", "}}
+{{- end}}
+{{- define "pr.reassigned" -}}
+:arrows_counterclockwise: {{join .New ", "}}, please review {{template "pr" .}} by {{.Author}} instead of {{.Old}}.
+Reviewers: {{join .Reviewers ", "}}
+{{- end}}`
+
+type Config struct {
+	// BaseURL replaces DefaultBaseURL in channel webhook URLs, for example
+	// to post to a local stub. Webhooks on other hosts are used as stored.
+	BaseURL string
+	// Templates is an optional text/template file. Its definitions replace
+	// the DefaultTemplates of the same name.
+	Templates string
+	// Links builds the PR links; PRs it has no link for are named only.
+	Links   prlink.Builder
+	Timeout time.Duration
+}
+
+// Client implements usecase.SlackPoster.
+type Client struct {
+	base  *url.URL
+	tmpl  *template.Template
+	links prlink.Builder
+	http  *http.Client
+}
+
+var _ usecase.SlackPoster = (*Client)(nil)
+
+func New(cfg Config) (*Client, error) {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	if cfg.Links == (prlink.Builder{}) {
+		cfg.Links = prlink.New("", "")
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	base, err := url.Parse(cfg.BaseURL)
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("slack: base url %q is not absolute", cfg.BaseURL)
+	}
+
+	tmpl := template.Must(template.New("slack").Funcs(template.FuncMap{"join": strings.Join}).Parse(DefaultTemplates))
+	if cfg.Templates != "" {
+		if tmpl, err = tmpl.ParseFiles(cfg.Templates); err != nil {
+			return nil, fmt.Errorf("slack: templates: %w", err)
+		}
+	}
+	return &Client{
+		base:  base,
+		tmpl:  tmpl,
+		links: cfg.Links,
+		http:  &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+// message is the data templates are executed with. Names and mentions are
+// already escaped for mrkdwn.
+type message struct {
+	Type   string
+	Team   string
+	Name   string
+	Link   string
+	Author string
+	// New are the newly assigned reviewers of Team, Reviewers all of them.
+	New       []string
+	Reviewers []string
+	// Old is the replaced reviewer of pr.reassigned.
+	Old string
+}
+
+// Post sends nothing for event types without a template.
+func (c *Client) Post(ctx context.Context, webhookURL string, m usecase.SlackMessage) error {
+	pr := m.Event.Data.PullRequest
+	if pr == nil || c.tmpl.Lookup(string(m.Event.Type)) == nil {
+		return nil
+	}
+	mention := func(id string) string {
+		if sid, ok := m.SlackIDs[id]; ok {
+			return "<@" + sid + ">"
+		}
+		if name := m.Usernames[id]; name != "" {
+			return escape(name)
+		}
+		return escape(id)
+	}
+	mentions := func(ids []string) []string {
+		out := make([]string, 0, len(ids))
+		for _, id := range ids {
+			out = append(out, mention(id))
+		}
+		return out
+	}
+	data := message{
+		Type:      string(m.Event.Type),
+		Team:      escape(m.TeamName),
+		Name:      escape(pr.Name),
+		Link:      c.links.Link(pr.ID),
+		Author:    mention(pr.AuthorID),
+		New:       mentions(m.NewReviewers),
+		Reviewers: mentions(pr.AssignedReviewers),
+	}
+	if m.Event.Data.OldReviewerID != "" {
+		data.Old = mention(m.Event.Data.OldReviewerID)
+	}
+
+	var text strings.Builder
+	if err := c.tmpl.ExecuteTemplate(&text, data.Type, data); err != nil {
+		return fmt.Errorf("%w: slack: render %s: %v", usecase.ErrPermanent, data.Type, err)
+	}
+	return c.send(ctx, webhookURL, text.String())
+}
+
+// send never puts webhookURL into errors: the URL is the channel's secret.
+func (c *Client) send(ctx context.Context, webhookURL, text string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return fmt.Errorf("%w: slack: invalid webhook url", usecase.ErrPermanent)
+	}
+	if u.Scheme+"://"+u.Host == DefaultBaseURL {
+		u.Scheme, u.Host = c.base.Scheme, c.base.Host
+		u.Path = strings.TrimRight(c.base.Path, "/") + u.Path
+	}
+
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		// Drop the URL the client quotes.
+		if uerr := (*url.Error)(nil); errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("slack: post to %s: %w", u.Host, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("slack: status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	// Slack answers 4xx for a payload it rejects and for a removed webhook
+	// or archived channel; only rate limiting is worth retrying.
+	if resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("%w: %v", usecase.ErrPermanent, err)
+	}
+	return err
+}
+
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// escape makes text safe to embed in mrkdwn.
+func escape(s string) string { return escaper.Replace(s) }
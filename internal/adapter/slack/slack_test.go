@@ -0,0 +1,138 @@
+package slack_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/slack"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+type post struct {
+	path string
+	text string
+}
+
+func stub(t *testing.T, status int) (*httptest.Server, *[]post) {
+	t.Helper()
+	var posts []post
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Text string `json:"text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		posts = append(posts, post{path: r.URL.Path, text: body.Text})
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("no_service"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &posts
+}
+
+func reassigned() usecase.SlackMessage {
+	return usecase.SlackMessage{
+		Event: domain.Event{
+			Type: domain.EventPRReassigned,
+			Data: domain.EventData{
+				PullRequest: &domain.EventPR{
+					ID: "octo/api#42", Name: "Fix <script> & co", AuthorID: "u1",
+					AssignedReviewers: []string{"u2", "u3"},
+				},
+				OldReviewerID: "u4",
+				NewReviewerID: "u3",
+			},
+		},
+		TeamName:     "backend",
+		NewReviewers: []string{"u3"},
+		Usernames:    map[string]string{"u1": "alice", "u2": "bob", "u3": "carol"},
+		SlackIDs:     map[string]string{"u3": "U03"},
+	}
+}
+
+func TestPostRendersAndUsesBaseURL(t *testing.T) {
+	srv, posts := stub(t, http.StatusOK)
+	c, err := slack.New(slack.Config{BaseURL: srv.URL + "/stub"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Post(context.Background(), slack.DefaultBaseURL+"/services/T1/B1/x", reassigned()); err != nil {
+		t.Fatal(err)
+	}
+	if len(*posts) != 1 || (*posts)[0].path != "/stub/services/T1/B1/x" {
+		t.Fatalf("posts: %+v", *posts)
+	}
+	const want = ":arrows_counterclockwise: <@U03>, please review " +
+		"<https://github.com/octo/api/pull/42|Fix &lt;script&gt; &amp; co> by alice instead of u4.\n" +
+		"Reviewers: bob, <@U03>"
+	if got := (*posts)[0].text; got != want {
+		t.Fatalf("text:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Webhooks of other chats are not redirected.
+	if err := c.Post(context.Background(), srv.URL+"/hooks/mattermost", reassigned()); err != nil {
+		t.Fatal(err)
+	}
+	if (*posts)[1].path != "/hooks/mattermost" {
+		t.Fatalf("other chat posted to %s", (*posts)[1].path)
+	}
+}
+
+func TestPostCustomTemplates(t *testing.T) {
+	srv, posts := stub(t, http.StatusOK)
+	file := filepath.Join(t.TempDir(), "slack.tmpl")
+	tmpl := `{{define "pr.reassigned"}}{{.Team}}: {{template "pr" .}} -> {{join .New " "}}{{end}}`
+	if err := os.WriteFile(file, []byte(tmpl), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := slack.New(slack.Config{Templates: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := reassigned()
+	m.Event.Data.PullRequest.ID = "grp/app!7"
+	if err := c.Post(context.Background(), srv.URL, m); err != nil {
+		t.Fatal(err)
+	}
+	// No template, no message.
+	m.Event.Type = domain.EventPRMerged
+	if err := c.Post(context.Background(), srv.URL, m); err != nil {
+		t.Fatal(err)
+	}
+	const want = "backend: <https://gitlab.com/grp/app/-/merge_requests/7|Fix &lt;script&gt; &amp; co> -> <@U03>"
+	if len(*posts) != 1 || (*posts)[0].text != want {
+		t.Fatalf("posts: %+v", *posts)
+	}
+}
+
+func TestPostRejectedIsPermanent(t *testing.T) {
+	srv, _ := stub(t, http.StatusNotFound)
+	c, err := slack.New(slack.Config{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.Post(context.Background(), slack.DefaultBaseURL+"/services/secret", reassigned())
+	if !errors.Is(err, usecase.ErrPermanent) {
+		t.Fatalf("got %v, want a permanent error", err)
+	}
+
+	srv, _ = stub(t, http.StatusTooManyRequests)
+	c, err = slack.New(slack.Config{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.Post(context.Background(), slack.DefaultBaseURL+"/services/secret", reassigned())
+	if err == nil || errors.Is(err, usecase.ErrPermanent) {
+		t.Fatalf("rate limited: got %v, want a retryable error", err)
+	}
+}
@@ -0,0 +1,364 @@
+// Package smtpmail sends reviewer notification emails and daily review
+// digests over SMTP as multipart/alternative messages with a plain text and
+// an HTML part.
+package smtpmail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/prlink"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+// Template file names. Subject and plain text use text/template, the HTML
+// part html/template.
+const (
+	SubjectTemplate = "email.subject.tmpl"
+	TextTemplate    = "email.txt.tmpl"
+	HTMLTemplate    = "email.html.tmpl"
+
+	DigestSubjectTemplate = "digest.subject.tmpl"
+	DigestTextTemplate    = "digest.txt.tmpl"
+	DigestHTMLTemplate    = "digest.html.tmpl"
+)
+
+var (
+	notificationTemplates = [3]string{SubjectTemplate, TextTemplate, HTMLTemplate}
+	digestTemplates       = [3]string{DigestSubjectTemplate, DigestTextTemplate, DigestHTMLTemplate}
+)
+
+//go:embed templates/*.tmpl
+var defaults embed.FS
+
+type Config struct {
+	// Addr is the host:port of the SMTP server. STARTTLS is used when the
+	// server offers it.
+	Addr string
+	// Username and Password enable PLAIN auth, which net/smtp only sends
+	// over TLS or to localhost.
+	Username string
+	Password string
+	From     string
+	// Templates is an optional directory; the template files found there
+	// replace the defaults of the same name.
+	Templates string
+	Links     prlink.Builder
+	Timeout   time.Duration
+}
+
+// Mailer implements usecase.Mailer and usecase.DigestSender.
+type Mailer struct {
+	cfg  Config
+	from *mail.Address
+	text *template.Template
+	html *htmltemplate.Template
+}
+
+var (
+	_ usecase.Mailer       = (*Mailer)(nil)
+	_ usecase.DigestSender = (*Mailer)(nil)
+)
+
+func New(cfg Config) (*Mailer, error) {
+	if _, _, err := net.SplitHostPort(cfg.Addr); err != nil {
+		return nil, fmt.Errorf("smtpmail: addr %q: %w", cfg.Addr, err)
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("smtpmail: from %q: %w", cfg.From, err)
+	}
+	if cfg.Links == (prlink.Builder{}) {
+		cfg.Links = prlink.New("", "")
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+
+	text, err := template.ParseFS(defaults, "templates/*.subject.tmpl", "templates/*.txt.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmltemplate.ParseFS(defaults, "templates/*.html.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Templates != "" {
+		for _, name := range append(notificationTemplates[:], digestTemplates[:]...) {
+			file := filepath.Join(cfg.Templates, name)
+			if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if strings.HasSuffix(name, ".html.tmpl") {
+				_, err = html.ParseFiles(file)
+			} else {
+				_, err = text.ParseFiles(file)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("smtpmail: templates: %w", err)
+			}
+		}
+	}
+	return &Mailer{cfg: cfg, from: from, text: text, html: html}, nil
+}
+
+// digest is the data templates are executed with.
+type digest struct {
+	Name  string
+	Items []item
+}
+
+type item struct {
+	Kind   string
+	Name   string
+	Link   string
+	Author string
+	// Old is the replaced reviewer of a reassignment.
+	Old string
+	// Opened is when the PR was created, set for SLA breaches.
+	Opened string
+}
+
+func (m *Mailer) Send(ctx context.Context, d usecase.EmailDigest) error {
+	name := func(id string) string {
+		if n := d.Usernames[id]; n != "" {
+			return n
+		}
+		return id
+	}
+	data := digest{Name: name(d.To.UserID)}
+	for _, n := range d.Items {
+		it := item{
+			Kind:   string(n.Kind),
+			Name:   n.PR.Name,
+			Link:   m.cfg.Links.Link(n.PR.ID),
+			Author: name(n.PR.AuthorID),
+		}
+		if it.Name == "" {
+			it.Name = n.PR.ID
+		}
+		if n.OldReviewerID != "" {
+			it.Old = name(n.OldReviewerID)
+		}
+		if n.PR.CreatedAt != nil {
+			it.Opened = n.PR.CreatedAt.UTC().Format("2006-01-02 15:04 UTC")
+		}
+		data.Items = append(data.Items, it)
+	}
+
+	msg, err := m.render(d.To.Email, notificationTemplates, data)
+	if err != nil {
+		return fmt.Errorf("%w: smtpmail: %v", usecase.ErrPermanent, err)
+	}
+	return m.send(ctx, d.To.Email, msg)
+}
+
+// dailyDigest is the data the digest templates are executed with.
+type dailyDigest struct {
+	Name string
+	// Day is the reviewer's local date, as 2006-01-02.
+	Day     string
+	PRs     []review
+	Overdue int
+}
+
+type review struct {
+	Name   string
+	Link   string
+	Author string
+	// Age is how long the PR has been open, such as "2d 3h".
+	Age     string
+	Overdue bool
+}
+
+func (m *Mailer) SendDigest(ctx context.Context, d domain.ReviewDigest) error {
+	name := func(id string) string {
+		if n := d.Usernames[id]; n != "" {
+			return n
+		}
+		return id
+	}
+	data := dailyDigest{Name: name(d.To.UserID), Day: d.Day}
+	for _, pr := range d.PRs {
+		r := review{
+			Name:    pr.Name,
+			Link:    m.cfg.Links.Link(pr.ID),
+			Author:  name(pr.AuthorID),
+			Age:     age(pr.Age),
+			Overdue: pr.Overdue,
+		}
+		if r.Name == "" {
+			r.Name = pr.ID
+		}
+		if r.Overdue {
+			data.Overdue++
+		}
+		data.PRs = append(data.PRs, r)
+	}
+
+	msg, err := m.render(d.To.Email, digestTemplates, data)
+	if err != nil {
+		return fmt.Errorf("%w: smtpmail: %v", usecase.ErrPermanent, err)
+	}
+	return m.send(ctx, d.To.Email, msg)
+}
+
+// age rounds d down to days and hours, or minutes under an hour.
+func age(d time.Duration) string {
+	switch days, hours := int(d/(24*time.Hour)), int(d%(24*time.Hour)/time.Hour); {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	}
+}
+
+// render executes the subject, text and HTML templates named by names.
+func (m *Mailer) render(to string, names [3]string, data any) ([]byte, error) {
+	var subject, text, html bytes.Buffer
+	if err := m.text.ExecuteTemplate(&subject, names[0], data); err != nil {
+		return nil, err
+	}
+	if err := m.text.ExecuteTemplate(&text, names[1], data); err != nil {
+		return nil, err
+	}
+	if err := m.html.ExecuteTemplate(&html, names[2], data); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{{"text/plain; charset=utf-8", text.Bytes()}, {"text/html; charset=utf-8", html.Bytes()}} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write(part.content); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	for _, h := range [][2]string{
+		{"From", m.from.String()},
+		{"To", (&mail.Address{Address: to}).String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(subject.String()), " "))},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", messageID(m.from.Address)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + mw.Boundary()},
+	} {
+		fmt.Fprintf(&msg, "%s: %s\r\n", h[0], h[1])
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+func (m *Mailer) send(ctx context.Context, to string, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", m.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("smtpmail: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	host, _, _ := net.SplitHostPort(m.cfg.Addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return classify(err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return classify(err)
+		}
+	}
+	if m.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, host)); err != nil {
+			return classify(err)
+		}
+	}
+	if err := c.Mail(m.from.Address); err != nil {
+		return classify(err)
+	}
+	if err := c.Rcpt(to); err != nil {
+		return classify(err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return classify(err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return classify(err)
+	}
+	if err := w.Close(); err != nil {
+		return classify(err)
+	}
+	// The message is accepted; a failed QUIT must not make it go out twice.
+	_ = c.Quit()
+	return nil
+}
+
+// classify marks 5xx replies permanent: the server will not take the
+// message however often it is offered.
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	var perr *textproto.Error
+	if errors.As(err, &perr) && perr.Code/100 == 5 {
+		return fmt.Errorf("%w: smtpmail: %v", usecase.ErrPermanent, err)
+	}
+	return fmt.Errorf("smtpmail: %w", err)
+}
+
+func messageID(from string) string {
+	b := make([]byte, 12)
+	_, _ = io.ReadFull(rand.Reader, b)
+	domain := from[strings.LastIndexByte(from, '@')+1:]
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
@@ -0,0 +1,251 @@
+package smtpmail_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/smtpmail"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+// fakeSMTP accepts one connection at a time and answers RCPT with rcpt.
+// Received messages are sent on the returned channel.
+func fakeSMTP(t *testing.T, rcpt string) (string, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	msgs := make(chan string, 4)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			serveSMTP(conn, rcpt, msgs)
+		}
+	}()
+	return ln.Addr().String(), msgs
+}
+
+func serveSMTP(conn net.Conn, rcpt string, msgs chan<- string) {
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+	reply := func(s string) { _, _ = io.WriteString(conn, s+"\r\n") }
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		switch cmd := strings.ToUpper(strings.Fields(line)[0]); cmd {
+		case "EHLO", "HELO", "MAIL":
+			reply("250 OK")
+		case "RCPT":
+			reply(rcpt)
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			msgs <- data.String()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func digest() usecase.EmailDigest {
+	created := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	return usecase.EmailDigest{
+		To: domain.User{UserID: "u2", Email: "bob@example.com"},
+		Items: []domain.EmailNotification{
+			{Kind: domain.EmailReassigned, OldReviewerID: "u3", PR: domain.EventPR{ID: "octo/api#42", Name: "Fix <script>", AuthorID: "u1"}},
+			{Kind: domain.EmailSLABreach, PR: domain.EventPR{ID: "pr-7", Name: "Docs", AuthorID: "u9", CreatedAt: &created}},
+		},
+		Usernames: map[string]string{"u1": "alice", "u2": "bob", "u3": "carol"},
+	}
+}
+
+// parts returns the decoded text and HTML bodies of a message.
+func parts(t *testing.T, raw string) (*mail.Message, string, string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bodies := make(map[string]string)
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := mr.NextRawPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(quotedprintable.NewReader(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		bodies[ct] = strings.ReplaceAll(string(b), "\r\n", "\n")
+	}
+	return msg, bodies["text/plain"], bodies["text/html"]
+}
+
+func TestSendRendersBothParts(t *testing.T) {
+	addr, msgs := fakeSMTP(t, "250 OK")
+	m, err := smtpmail.New(smtpmail.Config{Addr: addr, From: "PR Reviewer <noreply@example.com>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Send(context.Background(), digest()); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, text, html := parts(t, <-msgs)
+	if got := msg.Header.Get("To"); got != "<bob@example.com>" {
+		t.Fatalf("to: %q", got)
+	}
+	if got := msg.Header.Get("Subject"); got != "2 review notifications" {
+		t.Fatalf("subject: %q", got)
+	}
+	const wantText = `Hi bob,
+
+You took over the review of "Fix <script>" by alice from carol.
+https://github.com/octo/api/pull/42
+
+"Docs" by u9 has been waiting for review since 2026-03-01 09:30 UTC.
+
+` + "-- \npr-reviewer\n"
+	if text != wantText {
+		t.Fatalf("text:\n%s\nwant:\n%s", text, wantText)
+	}
+	for _, want := range []string{
+		`<a href="https://github.com/octo/api/pull/42">Fix &lt;script&gt;</a> by alice from carol.`,
+		`<b>Docs</b> by u9 has been waiting`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("html lacks %q:\n%s", want, html)
+		}
+	}
+}
+
+func TestSendCustomTemplates(t *testing.T) {
+	addr, msgs := fakeSMTP(t, "250 OK")
+	dir := t.TempDir()
+	tmpl := `Heads up: {{(index .Items 0).Name}}`
+	if err := os.WriteFile(filepath.Join(dir, smtpmail.SubjectTemplate), []byte(tmpl), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m, err := smtpmail.New(smtpmail.Config{Addr: addr, From: "noreply@example.com", Templates: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Send(context.Background(), digest()); err != nil {
+		t.Fatal(err)
+	}
+	msg, text, _ := parts(t, <-msgs)
+	if got := msg.Header.Get("Subject"); got != "Heads up: Fix <script>" {
+		t.Fatalf("subject: %q", got)
+	}
+	if !strings.HasPrefix(text, "Hi bob,") {
+		t.Fatalf("default text template not kept:\n%s", text)
+	}
+}
+
+func TestSendRejectedIsPermanent(t *testing.T) {
+	for rcpt, permanent := range map[string]bool{
+		"550 no such user":    true,
+		"451 try again later": false,
+	} {
+		addr, _ := fakeSMTP(t, rcpt)
+		m, err := smtpmail.New(smtpmail.Config{Addr: addr, From: "noreply@example.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = m.Send(context.Background(), digest())
+		if err == nil || errors.Is(err, usecase.ErrPermanent) != permanent {
+			t.Errorf("%s: got %v, want permanent=%v", rcpt, err, permanent)
+		}
+	}
+}
+
+func TestSendDigest(t *testing.T) {
+	addr, msgs := fakeSMTP(t, "250 OK")
+	m, err := smtpmail.New(smtpmail.Config{Addr: addr, From: "noreply@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := domain.ReviewDigest{
+		To:  domain.User{UserID: "u2", Email: "bob@example.com"},
+		Day: "2026-03-02",
+		PRs: []domain.DigestPR{
+			{PullRequestShort: domain.PullRequestShort{ID: "octo/api#42", Name: "Fix <script>", AuthorID: "u1"}, Age: 50 * time.Hour, Overdue: true},
+			{PullRequestShort: domain.PullRequestShort{ID: "pr-7", AuthorID: "u9"}, Age: 5 * time.Minute},
+		},
+		Usernames: map[string]string{"u1": "alice", "u2": "bob"},
+	}
+	if err := m.SendDigest(context.Background(), d); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, text, html := parts(t, <-msgs)
+	if got := msg.Header.Get("Subject"); got != "2 open reviews for 2026-03-02, 1 overdue" {
+		t.Fatalf("subject: %q", got)
+	}
+	const wantText = `Hi bob,
+
+These reviews are waiting for you on 2026-03-02:
+
+- "Fix <script>" by alice, open for 2d 2h (overdue)
+  https://github.com/octo/api/pull/42
+- "pr-7" by u9, open for 5m
+
+` + "-- \npr-reviewer\n"
+	if text != wantText {
+		t.Fatalf("text:\n%s\nwant:\n%s", text, wantText)
+	}
+	for _, want := range []string{
+		`<a href="https://github.com/octo/api/pull/42">Fix &lt;script&gt;</a> by alice, open for 2d 2h <b>(overdue)</b>`,
+		`<b>pr-7</b> by u9, open for 5m</li>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("html lacks %q:\n%s", want, html)
+		}
+	}
+}
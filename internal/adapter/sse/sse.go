@@ -0,0 +1,146 @@
+// Package sse serves the event stream of usecase.EventStreamUsecase as
+// Server-Sent Events.
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+// retryMillis is sent to clients as the reconnection delay.
+const retryMillis = 3000
+
+type Handler struct {
+	base context.Context
+	uc   *usecase.EventStreamUsecase
+	log  *slog.Logger
+}
+
+// New returns a handler whose streams also end when base is done, since
+// http.Server.Shutdown does not cancel running requests.
+func New(base context.Context, uc *usecase.EventStreamUsecase, logger *slog.Logger) *Handler {
+	return &Handler{base: base, uc: uc, log: logger}
+}
+
+// ServeHTTP streams events filtered by the team_name and user_id query
+// parameters. A client resumes after the ID in the Last-Event-ID header,
+// which browsers send on reconnect, or in the last_event_id parameter; a new
+// client only gets events from now on.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "use GET")
+		return
+	}
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	defer context.AfterFunc(h.base, cancel)()
+
+	q := r.URL.Query()
+	filter := usecase.StreamFilter{TeamName: q.Get("team_name"), UserID: q.Get("user_id")}
+
+	raw := r.Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = q.Get("last_event_id")
+	}
+	var after int64
+	if raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id < 0 {
+			writeError(w, http.StatusBadRequest, domain.ErrInvalid.Error(), "Last-Event-ID must be an event id")
+			return
+		}
+		after = id
+	} else {
+		id, err := h.uc.Start(ctx)
+		if err != nil {
+			h.log.Error("sse: start failed", "err", err)
+			writeError(w, http.StatusInternalServerError, "INTERNAL", "internal error")
+			return
+		}
+		after = id
+	}
+
+	// Streams outlive the server's write timeout.
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		h.log.Warn("sse: cannot lift write deadline", "err", err)
+	}
+
+	sw := &writer{w: w, rc: rc}
+	err := h.uc.Stream(ctx, filter, after, sw)
+	switch {
+	case err == nil || ctx.Err() != nil:
+	case sw.started:
+		h.log.Debug("sse: stream ended", "err", err)
+	case errors.Is(err, domain.ErrNotFound):
+		writeError(w, http.StatusNotFound, domain.ErrNotFound.Error(), "team not found")
+	default:
+		h.log.Error("sse: stream failed", "err", err)
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal error")
+	}
+}
+
+type writer struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	started bool
+}
+
+func (s *writer) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+	h := s.w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no")
+	s.w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprintf(s.w, "retry: %d\n\n", retryMillis)
+}
+
+func (s *writer) Event(m domain.OutboxMessage) error {
+	s.start()
+	data, err := json.Marshal(m.Event)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.w, "id: %d\nevent: %s\ndata: %s\n\n", m.ID, m.Event.Type, data); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+func (s *writer) KeepAlive() error {
+	s.start()
+	if _, err := fmt.Fprint(s.w, ": keep-alive\n\n"); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+type errorBody struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	var body errorBody
+	body.Error.Code = code
+	body.Error.Message = msg
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
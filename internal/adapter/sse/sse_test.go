@@ -0,0 +1,110 @@
+package sse_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/sse"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+func newServer(t *testing.T) (*httptest.Server, *usecase.PRUsecase) {
+	t.Helper()
+	ctx := context.Background()
+
+	s := memory.NewStore()
+	teams := memory.NewTeamRepo(s)
+	if err := teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{
+		{UserID: "u1", Username: "u1", IsActive: true},
+		{UserID: "u2", Username: "u2", IsActive: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	streams := usecase.NewEventStreamUsecase(memory.NewOutboxRepo(s), teams, usecase.NewEventHub(), 10*time.Millisecond)
+	srv := httptest.NewServer(sse.New(ctx, streams, slog.New(slog.NewTextHandler(io.Discard, nil))))
+	t.Cleanup(srv.Close)
+	return srv, usecase.NewPRUsecase(memory.NewUserRepo(s), memory.NewPRRepo(s))
+}
+
+func TestStreamResumesFromLastEventID(t *testing.T) {
+	srv, prs := newServer(t)
+	if _, err := prs.CreatePR(context.Background(), "pr-1", "feat", "u1"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?team_name=backend", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Last-Event-ID", "0")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if ct := resp.Header.Get("Content-Type"); resp.StatusCode != http.StatusOK || ct != "text/event-stream" {
+		t.Fatalf("status %d, content type %q", resp.StatusCode, ct)
+	}
+
+	fields := make(map[string]string)
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() && (sc.Text() != "" || fields["event"] == "") {
+		if k, v, ok := strings.Cut(sc.Text(), ": "); ok && k != "" {
+			fields[k] = v
+		}
+	}
+	if fields["id"] != "1" || fields["event"] != string(domain.EventPRCreated) {
+		t.Fatalf("event fields: %v", fields)
+	}
+	var e domain.Event
+	if err := json.Unmarshal([]byte(fields["data"]), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Data.PullRequest == nil || e.Data.PullRequest.ID != "pr-1" {
+		t.Fatalf("data: %+v", e)
+	}
+}
+
+func TestStreamRejectsBadRequests(t *testing.T) {
+	srv, _ := newServer(t)
+
+	for name, tc := range map[string]struct {
+		query, lastID string
+		want          int
+	}{
+		"bad last event id": {"", "abc", http.StatusBadRequest},
+		"unknown team":      {"?team_name=nope", "", http.StatusNotFound},
+	} {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+tc.query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.lastID != "" {
+			req.Header.Set("Last-Event-ID", tc.lastID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("%s: status %d, want %d", name, resp.StatusCode, tc.want)
+		}
+	}
+}
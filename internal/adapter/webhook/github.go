@@ -0,0 +1,160 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/github"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+const SourceGitHub = "github"
+
+type GitHubConfig struct {
+	// Secret is the webhook secret configured on the GitHub side.
+	Secret string
+	// OrgID is the organization the events are applied to.
+	OrgID string
+	// Logins maps GitHub logins to user ids. Pull requests of logins
+	// without an entry are rejected.
+	Logins map[string]string
+}
+
+// GitHub handles pull_request events sent to /webhooks/github.
+type GitHub struct {
+	forge *usecase.ForgeUsecase
+	cfg   GitHubConfig
+	log   *slog.Logger
+}
+
+func NewGitHub(forge *usecase.ForgeUsecase, cfg GitHubConfig, logger *slog.Logger) *GitHub {
+	if cfg.OrgID == "" {
+		cfg.OrgID = domain.DefaultOrg
+	}
+	return &GitHub{forge: forge, cfg: cfg, log: logger}
+}
+
+type githubPullRequestEvent struct {
+	Action      string `json:"action"`
+	PullRequest struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		Draft  bool   `json:"draft"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		MergedBy *struct {
+			Login string `json:"login"`
+		} `json:"merged_by"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+func (h *GitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+	if !validGitHubSignature(h.cfg.Secret, r.Header.Get("X-Hub-Signature-256"), body) {
+		writeError(w, http.StatusUnauthorized, domain.ErrUnauthorized.Error(), "invalid X-Hub-Signature-256")
+		return
+	}
+
+	if event := r.Header.Get("X-GitHub-Event"); event != "pull_request" {
+		// ping and any other subscribed event are acknowledged and dropped.
+		writeJSON(w, http.StatusOK, map[string]string{"result": string(usecase.ForgeUnchanged)})
+		return
+	}
+
+	var payload githubPullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Repository.FullName == "" || payload.PullRequest.Number == 0 {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "malformed pull_request payload")
+		return
+	}
+
+	ev := domain.ForgeEvent{
+		Source:     SourceGitHub,
+		DeliveryID: r.Header.Get("X-GitHub-Delivery"),
+		Action:     githubAction(payload),
+		PRID:       github.PRID(payload.Repository.FullName, payload.PullRequest.Number),
+		PRName:     payload.PullRequest.Title,
+	}
+	// The author opens the PR here and the merger is checked against the
+	// merge policy, so neither may be an account nobody mapped.
+	mapped := true
+	switch ev.Action {
+	case domain.ForgeOpened:
+		ev.AuthorID, mapped = h.userID(w, payload.PullRequest.User.Login)
+	case domain.ForgeMerged:
+		by := payload.PullRequest.MergedBy
+		if by == nil {
+			writeError(w, http.StatusBadRequest, "BAD_REQUEST", "merged pull_request without merged_by")
+			return
+		}
+		ev.MergerID, mapped = h.userID(w, by.Login)
+	}
+	if !mapped {
+		return
+	}
+
+	ctx := domain.WithOrg(r.Context(), h.cfg.OrgID)
+	res, err := h.forge.Ingest(ctx, ev)
+	h.log.Debug("webhook: github delivery",
+		"delivery", ev.DeliveryID, "action", payload.Action, "pr", ev.PRID, "result", res, "err", err)
+	writeResult(w, h.log, SourceGitHub, res, err)
+}
+
+// githubAction maps a pull_request event. As for GitLab, a PR is in review
+// while it is open and not a draft.
+func githubAction(e githubPullRequestEvent) domain.ForgeAction {
+	switch e.Action {
+	case "opened", "reopened":
+		if e.PullRequest.Draft {
+			return domain.ForgeIgnored
+		}
+		return domain.ForgeOpened
+	case "ready_for_review":
+		return domain.ForgeOpened
+	case "converted_to_draft":
+		return domain.ForgeClosed
+	case "closed":
+		if e.PullRequest.Merged {
+			return domain.ForgeMerged
+		}
+		return domain.ForgeClosed
+	}
+	return domain.ForgeIgnored
+}
+
+// userID looks login up in the mapping and answers 422 if it has no entry.
+func (h *GitHub) userID(w http.ResponseWriter, login string) (string, bool) {
+	id, ok := h.cfg.Logins[login]
+	if !ok {
+		writeUnmapped(w, fmt.Sprintf("GitHub login %q", login))
+	}
+	return id, ok
+}
+
+func validGitHubSignature(secret, header string, body []byte) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok || secret == "" {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,253 @@
+package webhook_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/webhook"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+const secret = "s3cret"
+
+type env struct {
+	prs     usecase.PRRepo
+	forge   *usecase.ForgeUsecase
+	handler http.Handler
+	gitlab  http.Handler
+}
+
+// newEnv seeds team "backend", with the four-eyes policy on, with u1..u3
+// and team "mobile" with m1..m2. GitHub login "octocat" and GitLab user 1001
+// are u1, GitLab user 1002 is u2; GitLab project 100 is reviewed by "mobile".
+func newEnv(t *testing.T) env {
+	t.Helper()
+	ctx := context.Background()
+
+	s := memory.NewStore()
+	teams, users, prs := memory.NewTeamRepo(s), memory.NewUserRepo(s), memory.NewPRRepo(s)
+	if err := teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{
+		{UserID: "u1", Username: "u1", IsActive: true},
+		{UserID: "u2", Username: "u2", IsActive: true},
+		{UserID: "u3", Username: "u3", IsActive: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := teams.SetFourEyes(ctx, "backend", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.CreateTeam(ctx, "mobile"); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.UpsertUsersToTeam(ctx, "mobile", []domain.User{
+		{UserID: "m1", Username: "m1", IsActive: true},
+		{UserID: "m2", Username: "m2", IsActive: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	prUC := usecase.NewPRUsecase(users, prs)
+	prUC.SetMergePolicy(usecase.NewFourEyesPolicy(users, teams, prs, logger))
+	forge := usecase.NewForgeUsecase(prUC, memory.NewDeliveryRepo(s), memory.NewGitLabProjectRepo(s), logger)
+	if err := forge.MapGitLabProject(ctx, 100, domain.DefaultOrg, "mobile"); err != nil {
+		t.Fatal(err)
+	}
+	return env{
+		prs:   prs,
+		forge: forge,
+		gitlab: webhook.NewGitLab(forge, webhook.GitLabConfig{
+			Token: secret,
+			Users: map[string]string{"1001": "u1", "1002": "u2"},
+		}, logger),
+		handler: webhook.NewGitHub(forge, webhook.GitHubConfig{
+			Secret: secret,
+			Logins: map[string]string{"octocat": "u1"},
+		}, logger),
+	}
+}
+
+func githubRequest(delivery, event, body string, sign bool) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewBufferString(body))
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-GitHub-Delivery", delivery)
+	if sign {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	return req
+}
+
+func serve(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+const (
+	openedPayload = `{"action":"opened","pull_request":{"number":7,"title":"Add cache","draft":false,"user":{"login":"octocat"}},"repository":{"full_name":"acme/api"}}`
+	mergedPayload = `{"action":"closed","pull_request":{"number":7,"title":"Add cache","merged":true,"user":{"login":"octocat"},"merged_by":{"login":"octocat"}},"repository":{"full_name":"acme/api"}}`
+)
+
+func TestGitHubRejectsBadSignature(t *testing.T) {
+	e := newEnv(t)
+
+	req := githubRequest("d-1", "pull_request", openedPayload, false)
+	req.Header.Set("X-Hub-Signature-256", "sha256=00")
+	if rec := serve(e.handler, req); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status: got %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if _, err := e.prs.GetByIDForUpdate(context.Background(), "acme/api#7"); err == nil {
+		t.Fatal("PR created from an unsigned delivery")
+	}
+}
+
+func TestGitHubLifecycle(t *testing.T) {
+	e := newEnv(t)
+	ctx := context.Background()
+
+	rec := serve(e.handler, githubRequest("d-1", "pull_request", openedPayload, true))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("opened: status %d: %s", rec.Code, rec.Body)
+	}
+	pr, err := e.prs.GetByIDForUpdate(ctx, "acme/api#7")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if pr.AuthorID != "u1" || pr.Name != "Add cache" || len(pr.AssignedReviewers) != 2 {
+		t.Fatalf("created PR: got %+v", pr)
+	}
+
+	// A redelivery must not reassign reviewers or fail with PR_EXISTS.
+	rec = serve(e.handler, githubRequest("d-1", "pull_request", openedPayload, true))
+	if rec.Code != http.StatusOK || !bytes.Contains(rec.Body.Bytes(), []byte(`"duplicate"`)) {
+		t.Fatalf("redelivery: status %d: %s", rec.Code, rec.Body)
+	}
+
+	// The author merged without an approval, which breaks the team's
+	// four-eyes policy; the merge has happened on GitHub all the same.
+	rec = serve(e.handler, githubRequest("d-2", "pull_request", mergedPayload, true))
+	if rec.Code != http.StatusOK || !bytes.Contains(rec.Body.Bytes(), []byte(`"merged_in_breach"`)) {
+		t.Fatalf("merged: status %d: %s", rec.Code, rec.Body)
+	}
+	if pr, _ = e.prs.GetByIDForUpdate(ctx, "acme/api#7"); pr.Status != domain.StatusMerged {
+		t.Fatalf("status: got %s, want %s", pr.Status, domain.StatusMerged)
+	}
+	events, err := e.prs.ListEvents(ctx, "acme/api#7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := events[len(events)-1]; last.Type != domain.EventPRMerged || last.Actor != "u1" || !strings.Contains(last.Data.Reason, "author cannot merge") {
+		t.Fatalf("merge event: got %+v", last)
+	}
+}
+
+func TestGitHubIgnoredEvents(t *testing.T) {
+	e := newEnv(t)
+
+	draft := `{"action":"opened","pull_request":{"number":8,"title":"WIP","draft":true,"user":{"login":"octocat"}},"repository":{"full_name":"acme/api"}}`
+	for name, req := range map[string]*http.Request{
+		"ping":  githubRequest("d-1", "ping", `{"zen":"Keep it logically awesome."}`, true),
+		"draft": githubRequest("d-2", "pull_request", draft, true),
+	} {
+		if rec := serve(e.handler, req); rec.Code != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", name, rec.Code, rec.Body)
+		}
+	}
+	if _, err := e.prs.GetByIDForUpdate(context.Background(), "acme/api#8"); err == nil {
+		t.Fatal("draft PR was created")
+	}
+}
+
+func TestGitHubCloseAndReopen(t *testing.T) {
+	e := newEnv(t)
+	ctx := context.Background()
+
+	event := func(action string, draft bool) string {
+		return fmt.Sprintf(`{"action":%q,"pull_request":{"number":7,"title":"Add cache","draft":%t,"user":{"login":"octocat"}},`+
+			`"repository":{"full_name":"acme/api"}}`, action, draft)
+	}
+	steps := []struct {
+		name, body, result string
+		status             domain.PRStatus
+	}{
+		{"opened", openedPayload, "created", domain.StatusOpen},
+		{"closed", event("closed", false), "closed", domain.StatusClosed},
+		{"reopened", event("reopened", false), "reopened", domain.StatusOpen},
+		{"converted_to_draft", event("converted_to_draft", true), "closed", domain.StatusClosed},
+		{"reopened as draft", event("reopened", true), "unchanged", domain.StatusClosed},
+		{"ready_for_review", event("ready_for_review", false), "reopened", domain.StatusOpen},
+	}
+	for i, step := range steps {
+		rec := serve(e.handler, githubRequest(fmt.Sprintf("d-%d", i), "pull_request", step.body, true))
+		if want := fmt.Sprintf(`{"result":%q}`, step.result); rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != want {
+			t.Fatalf("%s: status %d: %s, want %s", step.name, rec.Code, rec.Body, want)
+		}
+		pr, err := e.prs.GetByIDForUpdate(ctx, "acme/api#7")
+		if err != nil || pr.Status != step.status || len(pr.AssignedReviewers) != 2 {
+			t.Fatalf("%s: got %+v, %v", step.name, pr, err)
+		}
+	}
+}
+
+func TestGitHubUnmappedMerger(t *testing.T) {
+	e := newEnv(t)
+	ctx := context.Background()
+
+	if rec := serve(e.handler, githubRequest("d-1", "pull_request", openedPayload, true)); rec.Code != http.StatusOK {
+		t.Fatalf("opened: status %d: %s", rec.Code, rec.Body)
+	}
+	// u3 is a user id, but not the login of anyone here. The merge is not
+	// recorded, least of all as a breach by nobody, until it is mapped.
+	merged := strings.Replace(mergedPayload, `"merged_by":{"login":"octocat"}`, `"merged_by":{"login":"u3"}`, 1)
+	rec := serve(e.handler, githubRequest("d-2", "pull_request", merged, true))
+	if rec.Code != http.StatusUnprocessableEntity || !bytes.Contains(rec.Body.Bytes(), []byte(`\"u3\" is not mapped`)) {
+		t.Fatalf("merged: status %d: %s", rec.Code, rec.Body)
+	}
+	if pr, err := e.prs.GetByIDForUpdate(ctx, "acme/api#7"); err != nil || pr.Status != domain.StatusOpen {
+		t.Fatalf("after unmapped merge: got %+v, %v", pr, err)
+	}
+	// The delivery was not claimed.
+	if rec := serve(e.handler, githubRequest("d-2", "pull_request", mergedPayload, true)); rec.Code != http.StatusOK {
+		t.Fatalf("redelivery: status %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestGitHubUnknownAuthorCanBeRedelivered(t *testing.T) {
+	e := newEnv(t)
+
+	// u2 is a user id, but not a login anyone mapped.
+	stranger := `{"action":"opened","pull_request":{"number":9,"title":"x","user":{"login":"u2"}},"repository":{"full_name":"acme/api"}}`
+	rec := serve(e.handler, githubRequest("d-1", "pull_request", stranger, true))
+	if rec.Code != http.StatusUnprocessableEntity || !bytes.Contains(rec.Body.Bytes(), []byte(`\"u2\" is not mapped`)) {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	if _, err := e.prs.GetByIDForUpdate(context.Background(), "acme/api#9"); err == nil {
+		t.Fatal("PR created for an unmapped login")
+	}
+
+	// The failed delivery was not recorded, so a redelivery is processed again.
+	res, err := e.forge.Ingest(context.Background(), domain.ForgeEvent{
+		Source: webhook.SourceGitHub, DeliveryID: "d-1", Action: domain.ForgeOpened,
+		PRID: "acme/api#9", PRName: "x", AuthorID: "u2",
+	})
+	if err != nil || res != usecase.ForgeCreated {
+		t.Fatalf("redelivery: got %v, %v", res, err)
+	}
+}
@@ -0,0 +1,178 @@
+package webhook
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+const SourceGitLab = "gitlab"
+
+type GitLabConfig struct {
+	// Token is the secret token configured on the GitLab webhook.
+	Token string
+	// Users maps GitLab user ids, which unlike usernames cannot be changed,
+	// to user ids. Merge requests of users without an entry are rejected.
+	Users map[string]string
+}
+
+// GitLab handles Merge Request Hook events sent to /webhooks/gitlab. The
+// organization and the reviewer team come from the project mapping.
+type GitLab struct {
+	forge *usecase.ForgeUsecase
+	cfg   GitLabConfig
+	log   *slog.Logger
+}
+
+func NewGitLab(forge *usecase.ForgeUsecase, cfg GitLabConfig, logger *slog.Logger) *GitLab {
+	return &GitLab{forge: forge, cfg: cfg, log: logger}
+}
+
+type gitlabMergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		ID int64 `json:"id"`
+	} `json:"user"`
+	Project struct {
+		ID                int64  `json:"id"`
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID      int    `json:"iid"`
+		Title    string `json:"title"`
+		AuthorID int64  `json:"author_id"`
+		Action   string `json:"action"`
+		Draft    bool   `json:"draft"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *struct {
+			Current bool `json:"current"`
+		} `json:"draft"`
+	} `json:"changes"`
+}
+
+func (h *GitLab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+	token := r.Header.Get("X-Gitlab-Token")
+	if h.cfg.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.cfg.Token)) != 1 {
+		writeError(w, http.StatusUnauthorized, domain.ErrUnauthorized.Error(), "invalid X-Gitlab-Token")
+		return
+	}
+
+	var payload gitlabMergeRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "malformed payload")
+		return
+	}
+	if payload.ObjectKind != "merge_request" {
+		writeJSON(w, http.StatusOK, map[string]string{"result": string(usecase.ForgeUnchanged)})
+		return
+	}
+	if payload.Project.ID == 0 || payload.ObjectAttributes.IID == 0 {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "malformed merge_request payload")
+		return
+	}
+
+	project, err := h.forge.GitLabProject(r.Context(), payload.Project.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			err = fmt.Errorf("project %d is not mapped to a team: %w", payload.Project.ID, err)
+		}
+		writeResult(w, h.log, SourceGitLab, "", err)
+		return
+	}
+
+	ev := domain.ForgeEvent{
+		Source:     SourceGitLab,
+		DeliveryID: r.Header.Get("X-Gitlab-Event-UUID"),
+		Action:     gitlabAction(payload),
+		PRID:       GitLabPRID(payload.Project.PathWithNamespace, payload.ObjectAttributes.IID),
+		PRName:     payload.ObjectAttributes.Title,
+		TeamName:   project.TeamName,
+	}
+	// As for GitHub, neither the author nor the merger may be unmapped.
+	mapped := true
+	switch ev.Action {
+	case domain.ForgeOpened:
+		ev.AuthorID, mapped = h.userID(w, payload.ObjectAttributes.AuthorID)
+	case domain.ForgeMerged:
+		// The acting user of a merge event is the one who merged.
+		ev.MergerID, mapped = h.userID(w, payload.User.ID)
+	}
+	if !mapped {
+		return
+	}
+
+	ctx := domain.WithOrg(r.Context(), project.OrgID)
+	res, err := h.forge.Ingest(ctx, ev)
+	h.log.Debug("webhook: gitlab delivery",
+		"delivery", ev.DeliveryID, "action", payload.ObjectAttributes.Action, "pr", ev.PRID, "result", res, "err", err)
+	writeResult(w, h.log, SourceGitLab, res, err)
+}
+
+// GitLabPRID is the pull request id used for merge requests that arrive from
+// GitLab, e.g. "group/api!12".
+func GitLabPRID(project string, iid int) string {
+	return fmt.Sprintf("%s!%d", project, iid)
+}
+
+// ParseGitLabPRID splits an id built by GitLabPRID into the project path and
+// the merge request iid.
+func ParseGitLabPRID(id string) (string, int, bool) {
+	i := strings.LastIndexByte(id, '!')
+	if i <= 0 {
+		return "", 0, false
+	}
+	iid, err := strconv.Atoi(id[i+1:])
+	if err != nil || iid <= 0 {
+		return "", 0, false
+	}
+	return id[:i], iid, true
+}
+
+// gitlabAction maps a merge request event. A merge request is in review
+// while it is open and not a draft: closing it or turning it back into a
+// draft takes it out, reopening or undrafting it puts it back.
+func gitlabAction(e gitlabMergeRequestEvent) domain.ForgeAction {
+	attrs := e.ObjectAttributes
+	switch attrs.Action {
+	case "open", "reopen":
+		if !attrs.Draft {
+			return domain.ForgeOpened
+		}
+	case "update":
+		if d := e.Changes.Draft; d != nil {
+			if d.Current {
+				return domain.ForgeClosed
+			}
+			return domain.ForgeOpened
+		}
+	case "close":
+		return domain.ForgeClosed
+	case "merge":
+		return domain.ForgeMerged
+	}
+	return domain.ForgeIgnored
+}
+
+// userID looks gitlabID up in the mapping and answers 422 if it has no
+// entry.
+func (h *GitLab) userID(w http.ResponseWriter, gitlabID int64) (string, bool) {
+	id, ok := h.cfg.Users[strconv.FormatInt(gitlabID, 10)]
+	if !ok || gitlabID == 0 {
+		writeUnmapped(w, fmt.Sprintf("GitLab user %d", gitlabID))
+		return "", false
+	}
+	return id, true
+}
@@ -0,0 +1,177 @@
+package webhook_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/webhook"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+func gitlabRequest(uuid, token, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/gitlab", bytes.NewBufferString(body))
+	req.Header.Set("X-Gitlab-Event", "Merge Request Hook")
+	req.Header.Set("X-Gitlab-Event-UUID", uuid)
+	req.Header.Set("X-Gitlab-Token", token)
+	return req
+}
+
+// mergeRequestHook is an event on MR 3 of project projectID, authored by
+// GitLab user 1001 and sent for an action of user 1002.
+func mergeRequestHook(projectID int64, action string, draft bool, changes string) string {
+	return mergeRequestHookBy(projectID, 1001, 1002, action, draft, changes)
+}
+
+func mergeRequestHookBy(projectID, authorID, userID int64, action string, draft bool, changes string) string {
+	return fmt.Sprintf(`{"object_kind":"merge_request","user":{"id":%d,"username":"bob"},`+
+		`"project":{"id":%d,"path_with_namespace":"group/app"},`+
+		`"object_attributes":{"iid":3,"title":"Dark mode","author_id":%d,"action":%q,"draft":%t},"changes":{%s}}`,
+		userID, projectID, authorID, action, draft, changes)
+}
+
+func TestGitLabRejectsBadToken(t *testing.T) {
+	e := newEnv(t)
+
+	rec := serve(e.gitlab, gitlabRequest("e-1", "wrong", mergeRequestHook(100, "open", false, "")))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status: got %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestGitLabDraftLifecycle(t *testing.T) {
+	e := newEnv(t)
+	ctx := context.Background()
+
+	ready := `"draft":{"previous":true,"current":false}`
+	toDraft := `"draft":{"previous":false,"current":true}`
+	steps := []struct {
+		name, body string
+		result     string
+		status     domain.PRStatus
+	}{
+		{"open draft", mergeRequestHook(100, "open", true, ""), "unchanged", ""},
+		{"close draft", mergeRequestHook(100, "close", true, ""), "unchanged", ""},
+		{"mark ready", mergeRequestHook(100, "update", false, ready), "created", domain.StatusOpen},
+		{"back to draft", mergeRequestHook(100, "update", true, toDraft), "closed", domain.StatusClosed},
+		{"ready again", mergeRequestHook(100, "update", false, ready), "reopened", domain.StatusOpen},
+		{"close", mergeRequestHook(100, "close", false, ""), "closed", domain.StatusClosed},
+		{"close again", mergeRequestHook(100, "close", false, ""), "unchanged", domain.StatusClosed},
+		{"reopen", mergeRequestHook(100, "reopen", false, ""), "reopened", domain.StatusOpen},
+		// u1's team "backend" wants an approval nobody gave.
+		{"merge", mergeRequestHook(100, "merge", false, ""), "merged_in_breach", domain.StatusMerged},
+	}
+	for i, step := range steps {
+		rec := serve(e.gitlab, gitlabRequest(fmt.Sprintf("e-%d", i), secret, step.body))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", step.name, rec.Code, rec.Body)
+		}
+		if want := fmt.Sprintf(`{"result":%q}`, step.result); strings.TrimSpace(rec.Body.String()) != want {
+			t.Fatalf("%s: got %s, want %s", step.name, rec.Body, want)
+		}
+
+		pr, err := e.prs.GetByIDForUpdate(ctx, "group/app!3")
+		if step.status == "" {
+			if err == nil {
+				t.Fatalf("%s: draft MR created a PR", step.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: get: %v", step.name, err)
+		}
+		if pr.Status != step.status {
+			t.Fatalf("%s: status %s, want %s", step.name, pr.Status, step.status)
+		}
+		// Reviewers come from the mapped team, not from the author's team,
+		// and stay assigned while the MR is closed.
+		slices.Sort(pr.AssignedReviewers)
+		if pr.AuthorID != "u1" || !slices.Equal(pr.AssignedReviewers, []string{"m1", "m2"}) {
+			t.Fatalf("%s: got %+v", step.name, pr)
+		}
+	}
+	// The merge is by the acting user, the closes and reopens by GitLab.
+	events, err := e.prs.ListEvents(ctx, "group/app!3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actors []string
+	for _, ev := range events {
+		actors = append(actors, string(ev.Type)+" "+ev.Actor)
+	}
+	want := []string{
+		"pr.created gitlab", "pr.closed gitlab", "pr.reopened gitlab",
+		"pr.closed gitlab", "pr.reopened gitlab", "pr.merged u2",
+	}
+	if !slices.Equal(actors, want) {
+		t.Fatalf("events: got %q, want %q", actors, want)
+	}
+}
+
+func TestGitLabUnmappedAuthor(t *testing.T) {
+	e := newEnv(t)
+
+	body := mergeRequestHookBy(100, 4242, 1002, "open", false, "")
+	rec := serve(e.gitlab, gitlabRequest("e-1", secret, body))
+	if rec.Code != http.StatusUnprocessableEntity || !bytes.Contains(rec.Body.Bytes(), []byte("4242 is not mapped")) {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	if _, err := e.prs.GetByIDForUpdate(context.Background(), "group/app!3"); err == nil {
+		t.Fatal("PR created for an unmapped author")
+	}
+	// The delivery was not claimed, so it goes through once the author is
+	// mapped; here another author stands in for that.
+	rec = serve(e.gitlab, gitlabRequest("e-1", secret, mergeRequestHook(100, "open", false, "")))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("redelivery: status %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestGitLabUnmappedMerger(t *testing.T) {
+	e := newEnv(t)
+	ctx := context.Background()
+
+	if rec := serve(e.gitlab, gitlabRequest("e-1", secret, mergeRequestHook(100, "open", false, ""))); rec.Code != http.StatusOK {
+		t.Fatalf("open: status %d: %s", rec.Code, rec.Body)
+	}
+	rec := serve(e.gitlab, gitlabRequest("e-2", secret, mergeRequestHookBy(100, 1001, 4242, "merge", false, "")))
+	if rec.Code != http.StatusUnprocessableEntity || !bytes.Contains(rec.Body.Bytes(), []byte("4242 is not mapped")) {
+		t.Fatalf("merge: status %d: %s", rec.Code, rec.Body)
+	}
+	if pr, err := e.prs.GetByIDForUpdate(ctx, "group/app!3"); err != nil || pr.Status != domain.StatusOpen {
+		t.Fatalf("after unmapped merge: got %+v, %v", pr, err)
+	}
+	// The delivery was not claimed, so it goes through once the merger is
+	// mapped; here a mapped merger stands in for that.
+	if rec := serve(e.gitlab, gitlabRequest("e-2", secret, mergeRequestHook(100, "merge", false, ""))); rec.Code != http.StatusOK {
+		t.Fatalf("redelivery: status %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestGitLabUnmappedProject(t *testing.T) {
+	e := newEnv(t)
+
+	rec := serve(e.gitlab, gitlabRequest("e-1", secret, mergeRequestHook(999, "open", false, "")))
+	if rec.Code != http.StatusUnprocessableEntity || !bytes.Contains(rec.Body.Bytes(), []byte("not mapped")) {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestParseGitLabPRID(t *testing.T) {
+	for id, ok := range map[string]bool{
+		"grp/sub/app!7": true,
+		"grp/app!0":     false,
+		"grp/app":       false,
+		"!7":            false,
+		"octo/api#42":   false,
+	} {
+		if _, _, got := webhook.ParseGitLabPRID(id); got != ok {
+			t.Errorf("%q: got %v, want %v", id, got, ok)
+		}
+	}
+}
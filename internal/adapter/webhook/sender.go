@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+// Headers of outgoing event notifications. SignatureHeader carries
+// "sha256=" and the hex HMAC-SHA256 of the body keyed with the subscription
+// secret, the same scheme GitHub uses.
+const (
+	EventHeader     = "X-PR-Reviewer-Event"
+	DeliveryHeader  = "X-PR-Reviewer-Delivery"
+	SignatureHeader = "X-PR-Reviewer-Signature-256"
+)
+
+// Sender POSTs event deliveries to subscriber URLs.
+type Sender struct {
+	http *http.Client
+}
+
+var _ usecase.EventSender = (*Sender)(nil)
+
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{http: &http.Client{Timeout: timeout}}
+}
+
+// Send treats any 2xx answer as delivered; everything else is an error.
+func (s *Sender) Send(ctx context.Context, sub domain.Subscription, d domain.EventDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pr-reviewer")
+	req.Header.Set(EventHeader, string(d.EventType))
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, d.Payload))
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode/100 != 2 {
+		return resp.StatusCode, fmt.Errorf("subscriber answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the SignatureHeader value for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,56 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/webhook"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+func TestSenderSignsPayload(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	sub := domain.Subscription{ID: "s1", URL: srv.URL, Secret: "k"}
+	d := domain.EventDelivery{ID: 7, EventType: domain.EventPRMerged, Payload: []byte(`{"id":"e1"}`)}
+	code, err := webhook.NewSender(time.Second).Send(context.Background(), sub, d)
+	if err != nil || code != http.StatusNoContent {
+		t.Fatalf("send: %d %v", code, err)
+	}
+
+	if string(body) != `{"id":"e1"}` {
+		t.Fatalf("body: %s", body)
+	}
+	// printf '{"id":"e1"}' | openssl dgst -sha256 -hmac k
+	const want = "sha256=a94ba871760457a968c4f0c7f6e96ab70a1cd59eb43f97c738a7dae2bd8722fd"
+	if sig := got.Header.Get(webhook.SignatureHeader); sig != want {
+		t.Fatalf("signature: got %s, want %s", sig, want)
+	}
+	if got.Header.Get(webhook.EventHeader) != "pr.merged" || got.Header.Get(webhook.DeliveryHeader) != "7" {
+		t.Fatalf("headers: %v", got.Header)
+	}
+}
+
+func TestSenderFailsOnNon2xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer srv.Close()
+
+	code, err := webhook.NewSender(time.Second).Send(context.Background(),
+		domain.Subscription{URL: srv.URL}, domain.EventDelivery{Payload: []byte(`{}`)})
+	if err == nil || code != http.StatusGone {
+		t.Fatalf("send: %d %v", code, err)
+	}
+}
@@ -0,0 +1,77 @@
+// Package webhook receives pull request events from code hosting platforms
+// and hands them to usecase.ForgeUsecase. It also sends the service's own
+// events, signed, to subscribers.
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+// maxBody bounds webhook payloads; GitHub and GitLab stay well below it for
+// pull request events.
+const maxBody = 5 << 20
+
+type errorBody struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "use POST")
+		return nil, false
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBody))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, "BAD_REQUEST", "payload too large")
+		return nil, false
+	}
+	return body, true
+}
+
+// writeResult reports the outcome of ForgeUsecase.Ingest. Unknown authors
+// and PRs are the sender's configuration problem and are answered with 422
+// so they show up as failed deliveries that can be redelivered once fixed.
+func writeResult(w http.ResponseWriter, logger *slog.Logger, source string, res usecase.ForgeResult, err error) {
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, map[string]string{"result": string(res)})
+	case errors.Is(err, domain.ErrNotFound):
+		writeError(w, http.StatusUnprocessableEntity, domain.ErrNotFound.Error(), err.Error())
+	case errors.Is(err, domain.ErrPRClosed):
+		writeError(w, http.StatusConflict, domain.ErrPRClosed.Error(), err.Error())
+	default:
+		logger.Error("webhook: ingest failed", "source", source, "err", err)
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal error")
+	}
+}
+
+// writeUnmapped rejects a delivery whose author or merger has no user. Like
+// writeResult, it answers 422 so the platform can redeliver once the
+// account is mapped.
+func writeUnmapped(w http.ResponseWriter, account string) {
+	writeError(w, http.StatusUnprocessableEntity, domain.ErrNotFound.Error(), account+" is not mapped to a user")
+}
+
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	var body errorBody
+	body.Error.Code = code
+	body.Error.Message = msg
+	writeJSON(w, status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
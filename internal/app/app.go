@@ -2,10 +2,20 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/bulk"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/chatops"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/eventlog"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/jwtauth"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/natspub"
 	oapiadapter "github.com/beachrockhotel/pr-reviewer/internal/adapter/oapi"
-	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/postgres"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/scim"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/sse"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/webhook"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
 	"github.com/beachrockhotel/pr-reviewer/internal/platform/config"
 	"github.com/beachrockhotel/pr-reviewer/internal/platform/httpserver"
 	"github.com/beachrockhotel/pr-reviewer/internal/platform/log"
@@ -17,30 +27,157 @@ func Run(ctx context.Context) error {
 	cfg := config.Load()
 	logger := log.New(cfg.LogLevel)
 
-	pool, err := postgres.Connect(ctx, cfg.DB.DSN)
+	store, err := openStorage(ctx, cfg)
 	if err != nil {
 		return err
 	}
-	defer pool.Close()
+	defer store.close()
 
-	teamRepo := postgres.NewTeamRepo(pool)
-	userRepo := postgres.NewUserRepo(pool)
-	prRepo := postgres.NewPRRepo(pool)
+	teamUC := usecase.NewTeamUsecase(store.teams, store.users)
+	userUC := usecase.NewUserUsecase(store.users, store.prs)
+	prUC := usecase.NewPRUsecase(store.users, store.prs)
+	prUC.SetMergePolicy(usecase.NewFourEyesPolicy(store.users, store.teams, store.prs, logger))
+	tokenUC := usecase.NewTokenUsecase(store.tokens)
+	orgUC := usecase.NewOrgUsecase(store.orgs)
 
-	teamUC := usecase.NewTeamUsecase(teamRepo)
-	userUC := usecase.NewUserUsecase(userRepo, prRepo)
-	prUC := usecase.NewPRUsecase(userRepo, prRepo)
+	subsUC := usecase.NewSubscriptionUsecase(store.subs, store.events,
+		webhook.NewSender(cfg.Delivery.Timeout), usecase.RetryConfig{
+			MaxAttempts: cfg.Delivery.MaxAttempts,
+			Backoff:     cfg.Delivery.Backoff,
+		}, logger)
+	go subsUC.Run(ctx, cfg.Delivery.Interval)
 
-	h := oapiadapter.NewHandler(teamUC, userUC, prUC, logger)
+	sinks := map[string]usecase.EventSink{
+		"webhooks": subsUC,
+		"log":      eventlog.New(logger),
+	}
+	if cfg.NATS.URL != "" {
+		pub, err := natspub.Connect(natspub.Config{
+			URL:     cfg.NATS.URL,
+			Prefix:  cfg.NATS.SubjectPrefix,
+			Timeout: cfg.NATS.Timeout,
+		})
+		if err != nil {
+			return err
+		}
+		defer func() { _ = pub.Close() }()
+		sinks["nats"] = pub
+	}
+	slackUC, err := newSlackUsecase(cfg, store, logger)
+	if err != nil {
+		return err
+	}
+	sinks["slack"] = slackUC
+	emailUC, err := newEmailUsecase(cfg, store, logger)
+	if err != nil {
+		return err
+	}
+	if cfg.Email.SMTPAddr != "" {
+		sinks["email"] = emailUC
+		go emailUC.Run(ctx, cfg.Email.Interval)
+	}
+	if cfg.Email.SMTPAddr != "" && cfg.Digest.At != "" {
+		digestUC, err := newDigestUsecase(cfg, store, logger)
+		if err != nil {
+			return err
+		}
+		go digestUC.Run(ctx, cfg.Digest.Interval)
+	}
+	relay := newOutboxRelay(cfg, store, logger)
+	if err := addSinks(relay, cfg.Outbox.Sinks, sinks); err != nil {
+		return err
+	}
+	go relay.Run(ctx, cfg.Outbox.Interval)
 
-	apiSrv, err := prapi.NewServer(h)
+	hub := usecase.NewEventHub()
+	if store.watchOutbox != nil {
+		go store.watchOutbox(ctx, hub.Notify, logger)
+	}
+	streamUC := usecase.NewEventStreamUsecase(store.outbox, store.teams, hub, cfg.EventsStreamPoll)
+
+	if cfg.GitHub.Token != "" {
+		syncUC := newGitHubSync(cfg, store, logger)
+		prUC.AddReviewerListener(syncUC)
+		go syncUC.Run(ctx, cfg.GitHub.SyncInterval)
+	}
+
+	auditUC, err := newAuditUsecase(cfg, store)
+	if err != nil {
+		return err
+	}
+	h := oapiadapter.NewHandler(teamUC, userUC, prUC, subsUC, emailUC, auditUC, logger)
+	auths := []oapiadapter.Authenticator{tokenUC}
+	if cfg.JWTEnabled() {
+		verifier, err := jwtauth.New(ctx, jwtauth.Config{
+			JWKSFile:      cfg.JWT.JWKSFile,
+			JWKSURL:       cfg.JWT.JWKSURL,
+			Issuer:        cfg.JWT.Issuer,
+			Audience:      cfg.JWT.Audience,
+			UserClaim:     cfg.JWT.UserClaim,
+			ScopeClaim:    cfg.JWT.ScopeClaim,
+			OrgClaim:      cfg.JWT.OrgClaim,
+			Org:           cfg.JWT.Org,
+			DefaultScopes: cfg.JWT.DefaultScopes,
+			Refresh:       cfg.JWT.Refresh,
+		}, logger)
+		if err != nil {
+			return err
+		}
+		auths = append(auths, verifier)
+	}
+	sec := oapiadapter.NewSecurity(logger, orgUC, auths...)
+
+	apiSrv, err := prapi.NewServer(h, sec, prapi.WithErrorHandler(oapiadapter.ErrorHandler))
 	if err != nil {
 		return err
 	}
 
+	idemUC := usecase.NewIdempotencyUsecase(store.idempotency, cfg.Idempotency.TTL, cfg.Idempotency.Lease)
+
 	mux := http.NewServeMux()
-	mux.Handle("/stats", http.HandlerFunc(h.StatsHTTP))
-	mux.Handle("/", apiSrv)
+	mux.Handle("/stats", sec.RequireScope(domain.ScopeRead, http.HandlerFunc(h.StatsHTTP)))
+	mux.Handle("/events/stream", sec.RequireScope(domain.ScopeRead, sse.New(ctx, streamUC, logger)))
+	bulkH := bulk.NewHandler(teamUC, logger)
+	mux.Handle("/admin/import", sec.RequireScope(domain.ScopeAdmin, sec.Idempotent(idemUC, http.HandlerFunc(bulkH.Import))))
+	mux.Handle("/admin/export", sec.RequireScope(domain.ScopeAdmin, http.HandlerFunc(bulkH.Export)))
+	dirUC := usecase.NewDirectoryUsecase(store.teams, store.users, userUC, cfg.SCIM.DefaultTeam)
+	mux.Handle(scim.Prefix+"/", sec.RequireScope(domain.ScopeAdmin, sec.Idempotent(idemUC, scim.New(dirUC, logger))))
+	forgeUC := usecase.NewForgeUsecase(prUC, store.deliveries, store.gitlab, logger)
+	if cfg.GitHub.WebhookSecret != "" {
+		mux.Handle("/webhooks/github", webhook.NewGitHub(forgeUC, webhook.GitHubConfig{
+			Secret: cfg.GitHub.WebhookSecret,
+			OrgID:  cfg.GitHub.OrgID,
+			Logins: cfg.GitHub.Logins,
+		}, logger))
+	}
+	if cfg.ChatOps.SlackSigningSecret != "" || cfg.ChatOps.MattermostToken != "" {
+		loc, err := time.LoadLocation(cfg.Digest.Timezone)
+		if err != nil {
+			return fmt.Errorf("DIGEST_TIMEZONE: %w", err)
+		}
+		chatUC := usecase.NewChatOpsUsecase(prUC, userUC, store.users, loc)
+		if cfg.ChatOps.SlackSigningSecret != "" {
+			mux.Handle("/chatops/slack", chatops.NewSlack(chatUC, slackUC, chatops.SlackConfig{
+				SigningSecret: cfg.ChatOps.SlackSigningSecret,
+				OrgID:         cfg.ChatOps.OrgID,
+			}, logger))
+		}
+		if cfg.ChatOps.MattermostToken != "" {
+			mux.Handle("/chatops/mattermost", chatops.NewMattermost(chatUC, chatops.MattermostConfig{
+				Token:     cfg.ChatOps.MattermostToken,
+				OrgID:     cfg.ChatOps.OrgID,
+				Users:     cfg.ChatOps.MattermostUsers,
+				Usernames: cfg.ChatOps.MattermostNames,
+			}, logger))
+		}
+	}
+	if cfg.GitLab.WebhookToken != "" {
+		mux.Handle("/webhooks/gitlab", webhook.NewGitLab(forgeUC, webhook.GitLabConfig{
+			Token: cfg.GitLab.WebhookToken,
+			Users: cfg.GitLab.Users,
+		}, logger))
+	}
+	mux.Handle("/", sec.Idempotent(idemUC, apiSrv))
 
-	return httpserver.New(cfg.HTTPPort, mux, logger).Run(ctx)
+	return httpserver.New(cfg.HTTPPort, oapiadapter.RequestID(oapiadapter.TenantHeader(mux)), logger).Run(ctx)
 }
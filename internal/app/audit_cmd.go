@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/platform/config"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+const auditUsage = `usage:
+  pr-reviewer audit verify [-org ORG_ID]
+  pr-reviewer audit keygen
+  pr-reviewer audit check-export -file EXPORT.json -public-key BASE64`
+
+// RunAuditCommand checks the audit log hash chain and signed exports.
+func RunAuditCommand(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(auditUsage)
+	}
+
+	switch args[0] {
+	case "verify":
+		fs := flag.NewFlagSet("audit verify", flag.ContinueOnError)
+		org := fs.String("org", "", "only verify this organization")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		return verifyAudit(ctx, *org, out)
+
+	case "keygen":
+		pub, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "AUDIT_SIGNING_KEY=%s\npublic key: %s\nkey id: %s\n",
+			base64.StdEncoding.EncodeToString(key.Seed()),
+			base64.StdEncoding.EncodeToString(pub), usecase.SigningKeyID(pub))
+		return err
+
+	case "check-export":
+		fs := flag.NewFlagSet("audit check-export", flag.ContinueOnError)
+		file := fs.String("file", "", "export saved from /audit/export")
+		pubKey := fs.String("public-key", "", "trusted public key printed by keygen")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		pub, err := base64.StdEncoding.DecodeString(*pubKey)
+		if err != nil || len(pub) != ed25519.PublicKeySize {
+			return fmt.Errorf("-public-key: want %d base64 bytes", ed25519.PublicKeySize)
+		}
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return err
+		}
+		rec, err := usecase.VerifyExport(data, pub)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "ok: %s/%s, %d audit entries, chain head %d %s, exported %s\n",
+			rec.OrgID, rec.PullRequestID, len(rec.Entries), rec.ChainHead.ID, rec.ChainHead.Hash,
+			rec.ExportedAt.Format(time.RFC3339))
+		return err
+
+	default:
+		return errors.New(auditUsage)
+	}
+}
+
+// verifyAudit walks the chain of org, or of every organization if org is
+// empty, and fails if any chain is broken.
+func verifyAudit(ctx context.Context, org string, out io.Writer) error {
+	cfg := config.Load()
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.close()
+
+	orgIDs := []string{org}
+	if org == "" {
+		list, err := store.orgs.ListOrgs(ctx)
+		if err != nil {
+			return err
+		}
+		orgIDs = orgIDs[:0]
+		for _, o := range list {
+			orgIDs = append(orgIDs, o.OrgID)
+		}
+	}
+
+	audit := usecase.NewAuditUsecase(store.audit, store.prs, nil)
+	broken := 0
+	for _, id := range orgIDs {
+		report, err := audit.Verify(domain.WithOrg(ctx, id))
+		if err != nil {
+			return fmt.Errorf("verify %s: %w", id, err)
+		}
+		if b := report.Broken; b != nil {
+			broken++
+			_, _ = fmt.Fprintf(out, "%s: BROKEN at entry %d: %s (%d entries verified before it)\n",
+				id, b.ID, b.Reason, report.Checked)
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "%s: ok, %d entries, head %d %s", id, report.Checked, report.HeadID, report.Head)
+		if report.Unchained > 0 {
+			_, _ = fmt.Fprintf(out, ", %d older entries without hashes", report.Unchained)
+		}
+		_, _ = fmt.Fprintln(out)
+	}
+	if broken > 0 {
+		return fmt.Errorf("audit chain broken in %d organization(s)", broken)
+	}
+	return nil
+}
+
+func newAuditUsecase(cfg config.Config, store storage) (*usecase.AuditUsecase, error) {
+	var key ed25519.PrivateKey
+	if cfg.Audit.SigningKey != "" {
+		seed, err := base64.StdEncoding.DecodeString(cfg.Audit.SigningKey)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("AUDIT_SIGNING_KEY: want %d base64 bytes, see pr-reviewer audit keygen", ed25519.SeedSize)
+		}
+		key = ed25519.NewKeyFromSeed(seed)
+	}
+	return usecase.NewAuditUsecase(store.audit, store.prs, key), nil
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"text/tabwriter"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/prlink"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/smtpmail"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/platform/config"
+	"github.com/beachrockhotel/pr-reviewer/internal/platform/log"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+const emailUsage = `usage:
+  pr-reviewer email list [-status pending|sent|failed]`
+
+// RunEmailCommand inspects the queue of email notifications.
+func RunEmailCommand(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "list" {
+		return errors.New(emailUsage)
+	}
+	fs := flag.NewFlagSet("email list", flag.ContinueOnError)
+	status := fs.String("status", "", "only list notifications with this status")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	cfg := config.Load()
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.close()
+
+	emailUC, err := newEmailUsecase(cfg, store, log.New(cfg.LogLevel))
+	if err != nil {
+		return err
+	}
+	list, err := emailUC.List(ctx, domain.EmailStatus(*status))
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tORG\tUSER\tKIND\tPR\tSTATUS\tATTEMPTS\tQUEUED\tLAST ERROR")
+	for _, n := range list {
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			n.ID, n.OrgID, n.UserID, n.Kind, n.PR.ID,
+			n.Status, n.Attempts, n.CreatedAt.Format(time.RFC3339), n.LastError)
+	}
+	return tw.Flush()
+}
+
+func newMailer(cfg config.Config) (*smtpmail.Mailer, error) {
+	return smtpmail.New(smtpmail.Config{
+		Addr:      cfg.Email.SMTPAddr,
+		Username:  cfg.Email.SMTPUsername,
+		Password:  cfg.Email.SMTPPassword,
+		From:      cfg.Email.From,
+		Templates: cfg.Email.Templates,
+		Links:     prlink.New(cfg.GitHub.WebURL, cfg.GitLab.WebURL),
+		Timeout:   cfg.Email.Timeout,
+	})
+}
+
+// newEmailUsecase returns a usecase without a mailer when SMTP_ADDR is not
+// set: preferences can still be managed, but nothing may be sent.
+func newEmailUsecase(cfg config.Config, store storage, logger *slog.Logger) (*usecase.EmailUsecase, error) {
+	var mailer usecase.Mailer
+	if cfg.Email.SMTPAddr != "" {
+		m, err := newMailer(cfg)
+		if err != nil {
+			return nil, err
+		}
+		mailer = m
+	}
+	return usecase.NewEmailUsecase(store.email, store.users, mailer, usecase.EmailConfig{
+		Window: cfg.Email.BatchWindow,
+		SLA:    cfg.Email.SLA,
+		Retry: usecase.RetryConfig{
+			MaxAttempts: cfg.Email.MaxAttempts,
+			Backoff:     cfg.Email.Backoff,
+		},
+	}, logger), nil
+}
+
+// newDigestUsecase needs SMTP_ADDR and DIGEST_AT to be set.
+func newDigestUsecase(cfg config.Config, store storage, logger *slog.Logger) (*usecase.DigestUsecase, error) {
+	at, err := time.Parse("15:04", cfg.Digest.At)
+	if err != nil {
+		return nil, fmt.Errorf("DIGEST_AT %q: want HH:MM", cfg.Digest.At)
+	}
+	loc, err := time.LoadLocation(cfg.Digest.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("DIGEST_TIMEZONE: %w", err)
+	}
+	mailer, err := newMailer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return usecase.NewDigestUsecase(store.digests, store.prs, store.users, mailer, usecase.DigestConfig{
+		At:       time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute,
+		Location: loc,
+		SLA:      cfg.Email.SLA,
+	}, logger), nil
+}
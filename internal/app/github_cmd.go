@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/github"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/platform/config"
+	"github.com/beachrockhotel/pr-reviewer/internal/platform/log"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+const githubUsage = `usage:
+  pr-reviewer github syncs [-status pending|done|failed]
+  pr-reviewer github retry -id SYNC_ID`
+
+// RunGitHubCommand inspects and retries reviewer pushes to GitHub.
+func RunGitHubCommand(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(githubUsage)
+	}
+
+	cfg := config.Load()
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.close()
+
+	syncs := newGitHubSync(cfg, store, log.New(cfg.LogLevel))
+
+	switch args[0] {
+	case "syncs":
+		fs := flag.NewFlagSet("github syncs", flag.ContinueOnError)
+		status := fs.String("status", "", "only list syncs with this status")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		list, err := syncs.List(ctx, domain.SyncStatus(*status))
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "ID\tORG\tPR\tADD\tREMOVE\tSTATUS\tATTEMPTS\tNEXT ATTEMPT\tLAST ERROR")
+		for _, s := range list {
+			_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+				s.ID, s.OrgID, s.PRID, strings.Join(s.Add, ","), strings.Join(s.Remove, ","),
+				s.Status, s.Attempts, s.NextAttemptAt.Format(time.RFC3339), s.LastError)
+		}
+		return tw.Flush()
+
+	case "retry":
+		fs := flag.NewFlagSet("github retry", flag.ContinueOnError)
+		id := fs.Int64("id", 0, "sync id")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if _, err := syncs.Retry(ctx, *id); err != nil {
+			return fmt.Errorf("retry sync %d: %w", *id, err)
+		}
+		_, err := fmt.Fprintf(out, "sync %d scheduled\n", *id)
+		return err
+
+	default:
+		return errors.New(githubUsage)
+	}
+}
+
+func newGitHubSync(cfg config.Config, store storage, logger *slog.Logger) *usecase.ReviewerSyncUsecase {
+	client := github.New(github.Config{
+		BaseURL: cfg.GitHub.APIURL,
+		Token:   cfg.GitHub.Token,
+		Logins:  cfg.GitHub.Logins,
+	})
+	return usecase.NewReviewerSyncUsecase(store.syncs, client, usecase.RetryConfig{
+		MaxAttempts: cfg.GitHub.SyncMaxAttempts,
+		Backoff:     cfg.GitHub.SyncBackoff,
+	}, logger)
+}
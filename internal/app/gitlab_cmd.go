@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/platform/config"
+	"github.com/beachrockhotel/pr-reviewer/internal/platform/log"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+const gitlabUsage = `usage:
+  pr-reviewer gitlab map -project PROJECT_ID -team TEAM [-org ORG_ID]
+  pr-reviewer gitlab unmap -project PROJECT_ID
+  pr-reviewer gitlab list`
+
+// RunGitLabCommand manages the routing of GitLab projects to teams.
+func RunGitLabCommand(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(gitlabUsage)
+	}
+
+	cfg := config.Load()
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.close()
+
+	prUC := usecase.NewPRUsecase(store.users, store.prs)
+	forge := usecase.NewForgeUsecase(prUC, store.deliveries, store.gitlab, log.New(cfg.LogLevel))
+
+	switch args[0] {
+	case "map":
+		fs := flag.NewFlagSet("gitlab map", flag.ContinueOnError)
+		project := fs.Int64("project", 0, "GitLab project id")
+		team := fs.String("team", "", "team whose members review the project's merge requests")
+		org := fs.String("org", domain.DefaultOrg, "organization of the team")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if err := forge.MapGitLabProject(ctx, *project, *org, *team); err != nil {
+			return fmt.Errorf("map project %d to %s/%s: %w", *project, *org, *team, err)
+		}
+		_, err := fmt.Fprintf(out, "project %d -> %s/%s\n", *project, *org, *team)
+		return err
+
+	case "unmap":
+		fs := flag.NewFlagSet("gitlab unmap", flag.ContinueOnError)
+		project := fs.Int64("project", 0, "GitLab project id")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if err := forge.UnmapGitLabProject(ctx, *project); err != nil {
+			return fmt.Errorf("unmap project %d: %w", *project, err)
+		}
+		_, err := fmt.Fprintf(out, "unmapped %d\n", *project)
+		return err
+
+	case "list":
+		list, err := forge.ListGitLabProjects(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "PROJECT\tORG\tTEAM")
+		for _, p := range list {
+			_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\n", p.ProjectID, p.OrgID, p.TeamName)
+		}
+		return tw.Flush()
+
+	default:
+		return errors.New(gitlabUsage)
+	}
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/platform/config"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+const orgUsage = `usage:
+  pr-reviewer org create -id ORG_ID [-name NAME]
+  pr-reviewer org list`
+
+// RunOrgCommand manages organizations directly in the configured storage.
+func RunOrgCommand(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(orgUsage)
+	}
+
+	cfg := config.Load()
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.close()
+
+	orgs := usecase.NewOrgUsecase(store.orgs)
+
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("org create", flag.ContinueOnError)
+		id := fs.String("id", "", "organization id used in X-Org-ID and tokens")
+		name := fs.String("name", "", "display name, defaults to the id")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		org, err := orgs.Create(ctx, *id, *name)
+		if err != nil {
+			return fmt.Errorf("create %q: %w", *id, err)
+		}
+		_, err = fmt.Fprintf(out, "created %s (%s)\n", org.OrgID, org.Name)
+		return err
+
+	case "list":
+		list, err := orgs.List(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "ID\tNAME\tCREATED")
+		for _, o := range list {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", o.OrgID, o.Name, o.CreatedAt.Format(time.RFC3339))
+		}
+		return tw.Flush()
+
+	default:
+		return errors.New(orgUsage)
+	}
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/platform/config"
+	"github.com/beachrockhotel/pr-reviewer/internal/platform/log"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+const outboxUsage = `usage:
+  pr-reviewer outbox list [-status pending|published|failed]
+  pr-reviewer outbox retry -id MESSAGE_ID`
+
+// RunOutboxCommand inspects the event outbox and retries failed messages.
+func RunOutboxCommand(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(outboxUsage)
+	}
+
+	cfg := config.Load()
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.close()
+
+	relay := newOutboxRelay(cfg, store, log.New(cfg.LogLevel))
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("outbox list", flag.ContinueOnError)
+		status := fs.String("status", "", "only list messages with this status")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		list, err := relay.List(ctx, domain.OutboxStatus(*status))
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "ID\tORG\tTYPE\tKEY\tEVENT\tSTATUS\tATTEMPTS\tNEXT ATTEMPT\tLAST ERROR")
+		for _, m := range list {
+			_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+				m.ID, m.Event.OrgID, m.Event.Type, m.Key, m.Event.ID,
+				m.Status, m.Attempts, m.NextAttemptAt.Format(time.RFC3339), m.LastError)
+		}
+		return tw.Flush()
+
+	case "retry":
+		fs := flag.NewFlagSet("outbox retry", flag.ContinueOnError)
+		id := fs.Int64("id", 0, "message id")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if _, err := relay.Retry(ctx, *id); err != nil {
+			return fmt.Errorf("retry message %d: %w", *id, err)
+		}
+		_, err := fmt.Fprintf(out, "message %d scheduled\n", *id)
+		return err
+
+	default:
+		return errors.New(outboxUsage)
+	}
+}
+
+func newOutboxRelay(cfg config.Config, store storage, logger *slog.Logger) *usecase.OutboxRelay {
+	return usecase.NewOutboxRelay(store.outbox, usecase.RetryConfig{
+		MaxAttempts: cfg.Outbox.MaxAttempts,
+		Backoff:     cfg.Outbox.Backoff,
+	}, logger)
+}
+
+// addSinks attaches the sinks named in OUTBOX_SINKS, in that order.
+func addSinks(relay *usecase.OutboxRelay, names []string, available map[string]usecase.EventSink) error {
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		sink, ok := available[name]
+		if !ok {
+			return fmt.Errorf("OUTBOX_SINKS: sink %q is unknown or not configured", name)
+		}
+		relay.AddSink(name, sink)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"text/tabwriter"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/prlink"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/slack"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/platform/config"
+	"github.com/beachrockhotel/pr-reviewer/internal/platform/log"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+const slackUsage = `usage:
+  pr-reviewer slack set-channel -team TEAM -url WEBHOOK_URL [-org ORG_ID]
+  pr-reviewer slack delete-channel -team TEAM [-org ORG_ID]
+  pr-reviewer slack set-user -user USER_ID -slack SLACK_MEMBER_ID [-org ORG_ID]
+  pr-reviewer slack delete-user -user USER_ID [-org ORG_ID]
+  pr-reviewer slack list [-org ORG_ID]`
+
+// RunSlackCommand manages team channels and Slack member ids.
+func RunSlackCommand(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(slackUsage)
+	}
+
+	cfg := config.Load()
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.close()
+
+	// Nothing is posted from here.
+	uc := usecase.NewSlackUsecase(store.slack, store.users, nil, log.New(cfg.LogLevel))
+
+	fs := flag.NewFlagSet("slack "+args[0], flag.ContinueOnError)
+	org := fs.String("org", domain.DefaultOrg, "organization")
+	team := fs.String("team", "", "team name")
+	hook := fs.String("url", "", "incoming webhook URL")
+	user := fs.String("user", "", "user id")
+	slackID := fs.String("slack", "", "Slack member id, e.g. U024BE7LH")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if _, err := store.orgs.GetOrg(ctx, *org); err != nil {
+		return fmt.Errorf("organization %q: %w", *org, err)
+	}
+	ctx = domain.WithOrg(ctx, *org)
+
+	switch args[0] {
+	case "set-channel":
+		if err := uc.SetChannel(ctx, *team, *hook); err != nil {
+			return fmt.Errorf("set channel of %s/%s: %w", *org, *team, err)
+		}
+		_, err := fmt.Fprintf(out, "%s/%s -> %s\n", *org, *team, maskWebhook(*hook))
+		return err
+
+	case "delete-channel":
+		if err := uc.DeleteChannel(ctx, *team); err != nil {
+			return fmt.Errorf("delete channel of %s/%s: %w", *org, *team, err)
+		}
+		_, err := fmt.Fprintf(out, "deleted channel of %s/%s\n", *org, *team)
+		return err
+
+	case "set-user":
+		if err := uc.SetUser(ctx, *user, *slackID); err != nil {
+			return fmt.Errorf("map %s/%s: %w", *org, *user, err)
+		}
+		_, err := fmt.Fprintf(out, "%s/%s -> %s\n", *org, *user, *slackID)
+		return err
+
+	case "delete-user":
+		if err := uc.DeleteUser(ctx, *user); err != nil {
+			return fmt.Errorf("unmap %s/%s: %w", *org, *user, err)
+		}
+		_, err := fmt.Fprintf(out, "unmapped %s/%s\n", *org, *user)
+		return err
+
+	case "list":
+		channels, err := uc.Channels(ctx)
+		if err != nil {
+			return err
+		}
+		users, err := uc.Users(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "TEAM\tWEBHOOK")
+		for _, c := range channels {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\n", c.TeamName, maskWebhook(c.WebhookURL))
+		}
+		_, _ = fmt.Fprintln(tw, "\nUSER\tSLACK ID")
+		for _, u := range users {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\n", u.UserID, u.SlackID)
+		}
+		return tw.Flush()
+
+	default:
+		return errors.New(slackUsage)
+	}
+}
+
+// maskWebhook hides the path of a webhook URL, which is its secret.
+func maskWebhook(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "***"
+	}
+	return u.Scheme + "://" + u.Host + "/***"
+}
+
+func newSlackUsecase(cfg config.Config, store storage, logger *slog.Logger) (*usecase.SlackUsecase, error) {
+	client, err := slack.New(slack.Config{
+		BaseURL:   cfg.Slack.BaseURL,
+		Templates: cfg.Slack.Templates,
+		Links:     prlink.New(cfg.GitHub.WebURL, cfg.GitLab.WebURL),
+		Timeout:   cfg.Slack.Timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return usecase.NewSlackUsecase(store.slack, store.users, client, logger), nil
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/postgres"
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/sqlite"
+	"github.com/beachrockhotel/pr-reviewer/internal/platform/config"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+type storage struct {
+	teams       usecase.TeamRepo
+	users       usecase.UserRepo
+	prs         usecase.PRRepo
+	tokens      usecase.TokenRepo
+	orgs        usecase.OrgRepo
+	deliveries  usecase.DeliveryRepo
+	gitlab      usecase.GitLabProjectRepo
+	syncs       usecase.ReviewerSyncRepo
+	subs        usecase.SubscriptionRepo
+	events      usecase.EventDeliveryRepo
+	outbox      usecase.OutboxRepo
+	slack       usecase.SlackRepo
+	email       usecase.EmailRepo
+	digests     usecase.DigestRepo
+	audit       usecase.AuditRepo
+	idempotency usecase.IdempotencyRepo
+	// watchOutbox, if set, reports outbox writes of every replica until
+	// ctx is done.
+	watchOutbox func(ctx context.Context, notify func(orgID string), logger *slog.Logger)
+	close       func()
+}
+
+func openStorage(ctx context.Context, cfg config.Config) (storage, error) {
+	switch cfg.DB.Driver {
+	case "postgres":
+		pool, err := postgres.Connect(ctx, cfg.DB.DSN)
+		if err != nil {
+			return storage{}, err
+		}
+		return storage{
+			teams:       postgres.NewTeamRepo(pool),
+			users:       postgres.NewUserRepo(pool),
+			prs:         postgres.NewPRRepo(pool),
+			tokens:      postgres.NewTokenRepo(pool),
+			orgs:        postgres.NewOrgRepo(pool),
+			deliveries:  postgres.NewDeliveryRepo(pool),
+			gitlab:      postgres.NewGitLabProjectRepo(pool),
+			syncs:       postgres.NewReviewerSyncRepo(pool),
+			subs:        postgres.NewSubscriptionRepo(pool),
+			events:      postgres.NewEventDeliveryRepo(pool),
+			outbox:      postgres.NewOutboxRepo(pool),
+			slack:       postgres.NewSlackRepo(pool),
+			email:       postgres.NewEmailRepo(pool),
+			digests:     postgres.NewDigestRepo(pool),
+			audit:       postgres.NewAuditRepo(pool),
+			idempotency: postgres.NewIdempotencyRepo(pool),
+			watchOutbox: func(ctx context.Context, notify func(string), logger *slog.Logger) {
+				postgres.ListenOutbox(ctx, pool, notify, logger)
+			},
+			close: pool.Close,
+		}, nil
+	case "sqlite":
+		db, err := sqlite.Connect(ctx, cfg.DB.DSN)
+		if err != nil {
+			return storage{}, err
+		}
+		return storage{
+			teams:       sqlite.NewTeamRepo(db),
+			users:       sqlite.NewUserRepo(db),
+			prs:         sqlite.NewPRRepo(db),
+			tokens:      sqlite.NewTokenRepo(db),
+			orgs:        sqlite.NewOrgRepo(db),
+			deliveries:  sqlite.NewDeliveryRepo(db),
+			gitlab:      sqlite.NewGitLabProjectRepo(db),
+			syncs:       sqlite.NewReviewerSyncRepo(db),
+			subs:        sqlite.NewSubscriptionRepo(db),
+			events:      sqlite.NewEventDeliveryRepo(db),
+			outbox:      sqlite.NewOutboxRepo(db),
+			slack:       sqlite.NewSlackRepo(db),
+			email:       sqlite.NewEmailRepo(db),
+			digests:     sqlite.NewDigestRepo(db),
+			audit:       sqlite.NewAuditRepo(db),
+			idempotency: sqlite.NewIdempotencyRepo(db),
+			close:       func() { _ = db.Close() },
+		}, nil
+	default:
+		return storage{}, fmt.Errorf("unsupported DB_DRIVER %q", cfg.DB.Driver)
+	}
+}
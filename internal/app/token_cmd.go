@@ -0,0 +1,111 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	oapiadapter "github.com/beachrockhotel/pr-reviewer/internal/adapter/oapi"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/platform/config"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+const tokenUsage = `usage:
+  pr-reviewer token issue [-org ORG_ID] [-cross-org] -name NAME -scopes read,prs:write
+  pr-reviewer token revoke -id TOKEN_ID
+  pr-reviewer token list`
+
+// RunTokenCommand manages API tokens directly in the configured storage. It
+// is how the first admin token is bootstrapped.
+func RunTokenCommand(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(tokenUsage)
+	}
+
+	cfg := config.Load()
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.close()
+
+	tokens := usecase.NewTokenUsecase(store.tokens)
+
+	switch args[0] {
+	case "issue":
+		fs := flag.NewFlagSet("token issue", flag.ContinueOnError)
+		org := fs.String("org", domain.DefaultOrg, "organization the token is bound to")
+		name := fs.String("name", "", "human readable token name")
+		scopes := fs.String("scopes", "", "comma-separated scopes: read, teams:write, users:write, prs:write, admin")
+		crossOrg := fs.Bool("cross-org", false, "let an admin token pick any organization with "+oapiadapter.OrgHeader)
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		if _, err := store.orgs.GetOrg(ctx, *org); err != nil {
+			return fmt.Errorf("organization %q: %w", *org, err)
+		}
+		plain, tok, err := tokens.Issue(ctx, *org, *name, splitList(*scopes), *crossOrg)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "id:     %s\norg:    %s\nname:   %s\nscopes: %s\ntoken:  %s\n\nStore the token now, it cannot be shown again.\n",
+			tok.ID, tokenOrg(tok), tok.Name, strings.Join(tok.Scopes, ","), plain)
+		return err
+
+	case "revoke":
+		fs := flag.NewFlagSet("token revoke", flag.ContinueOnError)
+		id := fs.String("id", "", "token id")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if err := tokens.Revoke(ctx, *id); err != nil {
+			return fmt.Errorf("revoke %q: %w", *id, err)
+		}
+		_, err := fmt.Fprintf(out, "revoked %s\n", *id)
+		return err
+
+	case "list":
+		list, err := tokens.List(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "ID\tORG\tNAME\tSCOPES\tCREATED\tREVOKED")
+		for _, t := range list {
+			revoked := "-"
+			if t.RevokedAt != nil {
+				revoked = t.RevokedAt.Format(time.RFC3339)
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				t.ID, tokenOrg(t), t.Name, strings.Join(t.Scopes, ","), t.CreatedAt.Format(time.RFC3339), revoked)
+		}
+		return tw.Flush()
+
+	default:
+		return errors.New(tokenUsage)
+	}
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func tokenOrg(t domain.APIToken) string {
+	if t.CrossOrg {
+		return t.OrgID + " (cross-org)"
+	}
+	return t.OrgID
+}
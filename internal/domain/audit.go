@@ -0,0 +1,376 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AuditAction names a recorded change. The part before the dot is the kind
+// of entity it changed.
+type AuditAction string
+
+const (
+	AuditTeamCreated         AuditAction = "team.created"
+	AuditTeamPolicyChanged   AuditAction = "team.policy_changed"
+	AuditTeamDeleted         AuditAction = "team.deleted"
+	AuditUserUpserted        AuditAction = "user.upserted"
+	AuditUserActivityChanged AuditAction = "user.activity_changed"
+	AuditPRCreated           AuditAction = "pr.created"
+	AuditPRReassigned        AuditAction = "pr.reassigned"
+	AuditPRReviewed          AuditAction = "pr.reviewed"
+	AuditPRMerged            AuditAction = "pr.merged"
+	AuditPRClosed            AuditAction = "pr.closed"
+	AuditPRReopened          AuditAction = "pr.reopened"
+)
+
+// Audited entity kinds.
+const (
+	EntityTeam        = "team"
+	EntityUser        = "user"
+	EntityPullRequest = "pr"
+)
+
+var AuditEntities = []string{EntityTeam, EntityUser, EntityPullRequest}
+
+func (a AuditAction) Entity() string {
+	entity, _, _ := strings.Cut(string(a), ".")
+	return entity
+}
+
+// AuditEntry records one change: who made it, in which request, and the
+// entity's state before and after as JSON. Before is nil for entities that
+// were created. Entries are never changed or deleted.
+//
+// The entries of an organization form a hash chain: PrevHash is the Hash of
+// the entry before, and Hash is ChainHash. Entries written before the chain
+// existed have neither.
+type AuditEntry struct {
+	ID         int64
+	OrgID      string
+	Action     AuditAction
+	EntityType string
+	EntityID   string
+	Actor      string
+	RequestID  string
+	Before     json.RawMessage
+	After      json.RawMessage
+	CreatedAt  time.Time
+	PrevHash   string
+	Hash       string
+}
+
+// NewAuditEntry describes a change made in ctx; the repository assigns ID
+// and CreatedAt when it stores the entry.
+func NewAuditEntry(ctx context.Context, action AuditAction, entityID string, before, after json.RawMessage) AuditEntry {
+	var actor string
+	if p, ok := PrincipalFromContext(ctx); ok {
+		actor = p.Actor()
+	}
+	return AuditEntry{
+		OrgID:      OrgFromContext(ctx),
+		Action:     action,
+		EntityType: action.Entity(),
+		EntityID:   entityID,
+		Actor:      actor,
+		RequestID:  RequestIDFromContext(ctx),
+		Before:     before,
+		After:      after,
+	}
+}
+
+// ChainHash returns the hex SHA-256 of PrevHash and every recorded field
+// but ID, which the database assigns after the hash is taken. The JSON
+// states are hashed compacted with sorted keys because postgres stores them
+// as JSONB and returns them re-encoded.
+func (e AuditEntry) ChainHash() string {
+	h := sha256.New()
+	for _, f := range [][]byte{
+		[]byte(e.PrevHash), []byte(e.OrgID), []byte(e.Action), []byte(e.EntityType), []byte(e.EntityID),
+		[]byte(e.Actor), []byte(e.RequestID), CanonicalJSON(e.Before), CanonicalJSON(e.After),
+		[]byte(e.CreatedAt.UTC().Format(time.RFC3339Nano)),
+	} {
+		// Length prefixes keep adjacent fields from running into each other;
+		// -1 tells a missing state from an empty one.
+		n := len(f)
+		if f == nil {
+			n = -1
+		}
+		_, _ = fmt.Fprintf(h, "%d:", n)
+		_, _ = h.Write(f)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CanonicalJSON returns b with insignificant whitespace removed and object
+// keys sorted, or b itself if it is not valid JSON.
+func CanonicalJSON(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return b
+	}
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return b
+	}
+	return bytes.TrimSuffix(out.Bytes(), []byte("\n"))
+}
+
+// AuditChainReport is the result of walking the chain of an organization.
+// Unchained counts the leading entries written before the chain existed.
+// HeadID and Head identify the last checked entry: kept elsewhere, they
+// also reveal entries cut off the end.
+type AuditChainReport struct {
+	Checked   int
+	Unchained int
+	HeadID    int64
+	Head      string
+	// Broken is the first entry that does not continue the chain, if any.
+	Broken *AuditChainBreak
+}
+
+type AuditChainBreak struct {
+	ID     int64
+	Reason string
+}
+
+// Next checks that e continues the chain walked so far and adds it to the
+// report; once the chain is broken it does nothing.
+func (r *AuditChainReport) Next(e AuditEntry) {
+	if r.Broken != nil {
+		return
+	}
+	switch {
+	case e.Hash == "" && r.Checked == 0:
+		r.Unchained++
+		return
+	case e.Hash == "":
+		r.Broken = &AuditChainBreak{ID: e.ID, Reason: "entry has no hash"}
+	case e.PrevHash != r.Head:
+		r.Broken = &AuditChainBreak{ID: e.ID, Reason: "prev_hash does not match the previous entry's hash"}
+	case e.ChainHash() != e.Hash:
+		r.Broken = &AuditChainBreak{ID: e.ID, Reason: "hash does not match the entry's contents"}
+	default:
+		r.Checked++
+		r.HeadID, r.Head = e.ID, e.Hash
+	}
+}
+
+// AuditFilter selects entries of the organization in the context. Empty
+// fields match everything; From is inclusive and To exclusive. BeforeID
+// pages backwards: only entries with a smaller ID match.
+type AuditFilter struct {
+	EntityType string
+	EntityID   string
+	Actor      string
+	From       time.Time
+	To         time.Time
+	BeforeID   int64
+	Limit      int
+}
+
+// Match reports whether e passes every condition of f but the limit.
+func (f AuditFilter) Match(e AuditEntry) bool {
+	return (f.EntityType == "" || e.EntityType == f.EntityType) &&
+		(f.EntityID == "" || e.EntityID == f.EntityID) &&
+		(f.Actor == "" || e.Actor == f.Actor) &&
+		(f.From.IsZero() || !e.CreatedAt.Before(f.From)) &&
+		(f.To.IsZero() || e.CreatedAt.Before(f.To)) &&
+		(f.BeforeID == 0 || e.ID < f.BeforeID)
+}
+
+// The audit log keeps entities in these shapes rather than the API's, so
+// entries stay readable when the API changes.
+
+type auditTeam struct {
+	TeamName string `json:"team_name"`
+	FourEyes bool   `json:"four_eyes"`
+}
+
+type auditUser struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+	Role     Role   `json:"role"`
+	Email    string `json:"email,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+	Manager  string `json:"manager_id,omitempty"`
+}
+
+type auditPR struct {
+	ID                string     `json:"pull_request_id"`
+	Name              string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	Status            PRStatus   `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	MergedAt          *time.Time `json:"merged_at,omitempty"`
+}
+
+func AuditTeam(t Team) json.RawMessage {
+	return auditJSON(auditTeam{TeamName: t.TeamName, FourEyes: t.FourEyes})
+}
+
+// AuditUser returns nil for a nil user.
+func AuditUser(u *User) json.RawMessage {
+	if u == nil {
+		return nil
+	}
+	return auditJSON(auditUser{
+		UserID:   u.UserID,
+		Username: u.Username,
+		TeamName: u.TeamName,
+		IsActive: u.IsActive,
+		Role:     u.Role,
+		Email:    u.Email,
+		Timezone: u.Timezone,
+		Manager:  u.ManagerID,
+	})
+}
+
+// AuditPR returns nil for a nil PR. Reviewers are sorted so that equal
+// states compare equal.
+func AuditPR(pr *PullRequest) json.RawMessage {
+	if pr == nil {
+		return nil
+	}
+	revs := make([]string, len(pr.AssignedReviewers))
+	copy(revs, pr.AssignedReviewers)
+	slices.Sort(revs)
+	var merged *time.Time
+	if pr.MergedAt != nil {
+		m := pr.MergedAt.UTC()
+		merged = &m
+	}
+	return auditJSON(auditPR{
+		ID:                pr.ID,
+		Name:              pr.Name,
+		AuthorID:          pr.AuthorID,
+		Status:            pr.Status,
+		AssignedReviewers: revs,
+		MergedAt:          merged,
+	})
+}
+
+type auditReview struct {
+	PRID       string        `json:"pull_request_id"`
+	ReviewerID string        `json:"reviewer_id"`
+	Verdict    ReviewVerdict `json:"verdict"`
+}
+
+// AuditReview returns nil for a nil review.
+func AuditReview(prID string, r *Review) json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return auditJSON(auditReview{PRID: prID, ReviewerID: r.ReviewerID, Verdict: r.Verdict})
+}
+
+func auditJSON(v any) json.RawMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		// The audit shapes hold only strings, bools and times.
+		panic(err)
+	}
+	return b
+}
+
+// PRRecord is the review record of a pull request exported for auditors:
+// its audit entries, oldest first, with everything needed to recompute
+// their hashes, its events, and the head of the organization's chain at the
+// time of the export.
+type PRRecord struct {
+	OrgID         string            `json:"org_id"`
+	PullRequestID string            `json:"pull_request_id"`
+	ExportedAt    time.Time         `json:"exported_at"`
+	PullRequest   json.RawMessage   `json:"pull_request"`
+	Entries       []PRRecordEntry   `json:"audit_entries"`
+	History       []Event           `json:"history"`
+	ChainHead     PRRecordChainHead `json:"chain_head"`
+}
+
+type PRRecordEntry struct {
+	ID         int64           `json:"audit_id"`
+	Action     AuditAction     `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Actor      string          `json:"actor"`
+	RequestID  string          `json:"request_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	CreatedAt  time.Time       `json:"created_at"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+}
+
+type PRRecordChainHead struct {
+	ID   int64  `json:"audit_id"`
+	Hash string `json:"hash"`
+}
+
+func NewPRRecordEntry(e AuditEntry) PRRecordEntry {
+	return PRRecordEntry{
+		ID:         e.ID,
+		Action:     e.Action,
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID,
+		Actor:      e.Actor,
+		RequestID:  e.RequestID,
+		Before:     e.Before,
+		After:      e.After,
+		CreatedAt:  e.CreatedAt,
+		PrevHash:   e.PrevHash,
+		Hash:       e.Hash,
+	}
+}
+
+// AuditEntry returns the entry of the organization orgID that r describes.
+func (r PRRecordEntry) AuditEntry(orgID string) AuditEntry {
+	// A missing state comes back from JSON as null.
+	state := func(b json.RawMessage) json.RawMessage {
+		if string(b) == "null" {
+			return nil
+		}
+		return b
+	}
+	return AuditEntry{
+		ID:         r.ID,
+		OrgID:      orgID,
+		Action:     r.Action,
+		EntityType: r.EntityType,
+		EntityID:   r.EntityID,
+		Actor:      r.Actor,
+		RequestID:  r.RequestID,
+		Before:     state(r.Before),
+		After:      state(r.After),
+		CreatedAt:  r.CreatedAt,
+		PrevHash:   r.PrevHash,
+		Hash:       r.Hash,
+	}
+}
+
+type requestIDKey struct{}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the id of the request being served, empty
+// outside of one.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// Token scopes. ScopeAdmin implies every other scope.
+const (
+	ScopeRead       = "read"
+	ScopeTeamsWrite = "teams:write"
+	ScopeUsersWrite = "users:write"
+	ScopePRsWrite   = "prs:write"
+	ScopeAdmin      = "admin"
+)
+
+var Scopes = []string{ScopeRead, ScopeTeamsWrite, ScopeUsersWrite, ScopePRsWrite, ScopeAdmin}
+
+// APIToken is bound to OrgID. A CrossOrg token, which must carry the admin
+// scope, may also pick another organization per request.
+type APIToken struct {
+	ID        string
+	OrgID     string
+	Name      string
+	Scopes    []string
+	CrossOrg  bool
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+// Principal is the authenticated caller of a request. UserID is set when the
+// caller is a person (JWT); API tokens identify services and leave it empty.
+// OrgID is the tenant the credential is bound to, empty if it is not bound;
+// CrossOrg marks admin tokens that may act in any organization.
+type Principal struct {
+	UserID   string
+	OrgID    string
+	TokenID  string
+	Name     string
+	Scopes   []string
+	CrossOrg bool
+}
+
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, ScopeAdmin) || slices.Contains(p.Scopes, scope)
+}
+
+// Actor names the principal in the audit log: the user id for people,
+// "token:<id>" for API tokens and Name for other callers.
+func (p Principal) Actor() string {
+	switch {
+	case p.UserID != "":
+		return p.UserID
+	case p.TokenID != "":
+		return "token:" + p.TokenID
+	default:
+		return p.Name
+	}
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// DigestPR is an open review in a daily digest.
+type DigestPR struct {
+	PullRequestShort
+	// Age is how long the PR has been open when the digest is made.
+	Age time.Duration
+	// Overdue is set when Age exceeds the review SLA.
+	Overdue bool
+}
+
+// ReviewDigest is the daily summary of one reviewer's open reviews.
+type ReviewDigest struct {
+	OrgID string
+	To    User
+	// Day is the reviewer's local date, as 2006-01-02.
+	Day string
+	PRs []DigestPR
+	// Usernames is keyed by user id and covers the authors of PRs.
+	Usernames map[string]string
+}
@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+// EmailKind is what an email notification is about.
+type EmailKind string
+
+const (
+	// EmailAssigned tells a reviewer of a new PR about it.
+	EmailAssigned EmailKind = "assigned"
+	// EmailReassigned tells a reviewer they took over from another one.
+	EmailReassigned EmailKind = "reassigned"
+	// EmailSLABreach tells a reviewer a PR has been open longer than the SLA.
+	EmailSLABreach EmailKind = "sla_breach"
+)
+
+// EmailPrefs are the kinds of email a user wants. Users who never set them
+// get every kind.
+type EmailPrefs struct {
+	UserID     string
+	Assigned   bool
+	Reassigned bool
+	SLABreach  bool
+	// Digest is the daily summary of the user's open reviews.
+	Digest bool
+}
+
+func DefaultEmailPrefs(userID string) EmailPrefs {
+	return EmailPrefs{UserID: userID, Assigned: true, Reassigned: true, SLABreach: true, Digest: true}
+}
+
+func (p EmailPrefs) Wants(k EmailKind) bool {
+	switch k {
+	case EmailAssigned:
+		return p.Assigned
+	case EmailReassigned:
+		return p.Reassigned
+	case EmailSLABreach:
+		return p.SLABreach
+	default:
+		return false
+	}
+}
+
+type EmailStatus string
+
+const (
+	EmailPending EmailStatus = "pending"
+	EmailSent    EmailStatus = "sent"
+	EmailFailed  EmailStatus = "failed"
+)
+
+// EmailNotification is one item of an email to UserID. Pending items of a
+// user are sent together, so that a burst of changes makes one email.
+type EmailNotification struct {
+	ID     int64
+	OrgID  string
+	UserID string
+	Kind   EmailKind
+	// PR is the pull request as it was when the item was queued.
+	PR EventPR
+	// OldReviewerID is who UserID took over from, for EmailReassigned.
+	OldReviewerID string
+
+	Status        EmailStatus
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
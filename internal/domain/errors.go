@@ -4,9 +4,23 @@ import "errors"
 
 var (
 	ErrTeamExists  = errors.New("TEAM_EXISTS")
+	ErrUserExists  = errors.New("USER_EXISTS")
 	ErrPRExists    = errors.New("PR_EXISTS")
 	ErrPRMerged    = errors.New("PR_MERGED")
+	ErrPRClosed    = errors.New("PR_CLOSED")
 	ErrNotAssigned = errors.New("NOT_ASSIGNED")
 	ErrNoCandidate = errors.New("NO_CANDIDATE")
 	ErrNotFound    = errors.New("NOT_FOUND")
+	ErrOrgExists   = errors.New("ORG_EXISTS")
+	ErrInvalid     = errors.New("INVALID_ARGUMENT")
+	ErrFourEyes    = errors.New("FOUR_EYES_VIOLATION")
+	// ErrVersionMismatch means the PR changed since the version the caller
+	// expected.
+	ErrVersionMismatch = errors.New("VERSION_MISMATCH")
+
+	ErrIdempotencyKeyReused  = errors.New("IDEMPOTENCY_KEY_REUSED")
+	ErrIdempotencyInProgress = errors.New("IDEMPOTENCY_IN_PROGRESS")
+
+	ErrUnauthorized = errors.New("UNAUTHORIZED")
+	ErrForbidden    = errors.New("FORBIDDEN")
 )
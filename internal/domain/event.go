@@ -0,0 +1,106 @@
+package domain
+
+import "time"
+
+type EventType string
+
+const (
+	EventPRCreated           EventType = "pr.created"
+	EventPRReassigned        EventType = "pr.reassigned"
+	EventPRReviewed          EventType = "pr.reviewed"
+	EventPRMerged            EventType = "pr.merged"
+	EventPRClosed            EventType = "pr.closed"
+	EventPRReopened          EventType = "pr.reopened"
+	EventUserActivityChanged EventType = "user.activity_changed"
+)
+
+var EventTypes = []EventType{
+	EventPRCreated, EventPRReassigned, EventPRReviewed, EventPRMerged, EventPRClosed, EventPRReopened,
+	EventUserActivityChanged,
+}
+
+// Event is a change other systems may want to react to. It is serialized
+// as is into every notification, so its JSON form is part of the API.
+type Event struct {
+	ID         string    `json:"id"`
+	Type       EventType `json:"type"`
+	OrgID      string    `json:"org_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	// Actor caused the event, in the form of Principal.Actor; empty for the
+	// CLI and for events written before it was recorded.
+	Actor string    `json:"actor,omitempty"`
+	Data  EventData `json:"data"`
+}
+
+// EventData holds PullRequest for pr.* events, OldReviewerID,
+// NewReviewerID and the optional Reason for pr.reassigned, and Review for
+// pr.reviewed; User is set for user.* events and carries the new state.
+// Reason of pr.merged names the merge policy a platform merge broke.
+type EventData struct {
+	PullRequest   *EventPR     `json:"pull_request,omitempty"`
+	OldReviewerID string       `json:"old_reviewer_id,omitempty"`
+	NewReviewerID string       `json:"new_reviewer_id,omitempty"`
+	Reason        string       `json:"reason,omitempty"`
+	Review        *EventReview `json:"review,omitempty"`
+	User          *EventUser   `json:"user,omitempty"`
+}
+
+type EventReview struct {
+	ReviewerID string        `json:"reviewer_id"`
+	Verdict    ReviewVerdict `json:"verdict"`
+}
+
+type EventPR struct {
+	ID                string     `json:"pull_request_id"`
+	Name              string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	Status            PRStatus   `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	MergedAt          *time.Time `json:"merged_at,omitempty"`
+	Version           int64      `json:"version,omitempty"`
+}
+
+type EventUser struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+}
+
+func NewEventPR(pr PullRequest) *EventPR {
+	revs := make([]string, len(pr.AssignedReviewers))
+	copy(revs, pr.AssignedReviewers)
+	return &EventPR{
+		ID:                pr.ID,
+		Name:              pr.Name,
+		AuthorID:          pr.AuthorID,
+		Status:            pr.Status,
+		AssignedReviewers: revs,
+		CreatedAt:         pr.CreatedAt,
+		MergedAt:          pr.MergedAt,
+		Version:           pr.Version,
+	}
+}
+
+func NewEventUser(u User) *EventUser {
+	return &EventUser{UserID: u.UserID, Username: u.Username, TeamName: u.TeamName, IsActive: u.IsActive}
+}
+
+// Key groups events that must be published in the order they happened:
+// those of one pull request, or of one user.
+func (e Event) Key() string {
+	switch {
+	case e.Data.PullRequest != nil:
+		return PRKey(e.Data.PullRequest.ID)
+	case e.Data.User != nil:
+		return "user/" + e.Data.User.UserID
+	default:
+		return ""
+	}
+}
+
+// PRKey is the Key of the events of pull request prID.
+func PRKey(prID string) string {
+	return "pr/" + prID
+}
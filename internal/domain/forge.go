@@ -0,0 +1,41 @@
+package domain
+
+// ForgeAction is what happened to a pull request on a code hosting platform
+// (GitHub, GitLab), reduced to the transitions this service understands.
+type ForgeAction string
+
+const (
+	// ForgeOpened means the PR is open and ready for review: opened as a
+	// non-draft, reopened or marked ready.
+	ForgeOpened ForgeAction = "opened"
+	ForgeMerged ForgeAction = "merged"
+	// ForgeClosed means the PR no longer awaits review: closed without a
+	// merge or turned back into a draft.
+	ForgeClosed ForgeAction = "closed"
+	// ForgeIgnored covers events without a matching transition, such as a
+	// draft being opened.
+	ForgeIgnored ForgeAction = "ignored"
+)
+
+// ForgeEvent is a webhook delivery translated to our identifiers.
+type ForgeEvent struct {
+	Source     string
+	DeliveryID string
+	Action     ForgeAction
+	PRID       string
+	PRName     string
+	AuthorID   string
+	// MergerID is the user who merged, for merge events. Webhooks reject a
+	// merge by an account that is not mapped to a user.
+	MergerID string
+	// TeamName overrides the author's team as the source of reviewers.
+	TeamName string
+}
+
+// GitLabProject routes merge requests of a GitLab project to a team of an
+// organization; reviewers are drawn from that team.
+type GitLabProject struct {
+	ProjectID int64
+	OrgID     string
+	TeamName  string
+}
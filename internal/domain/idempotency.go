@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord is a request sent with an Idempotency-Key and, once it
+// has completed, the response it got.
+type IdempotencyRecord struct {
+	// Owner is the Principal.Actor of the caller, so that a key reused by
+	// another caller is a different record.
+	Owner string
+	Key   string
+	// Lease identifies the reservation the request holds while it runs.
+	// Only its holder may complete or release the key, so a request that
+	// was taken over cannot overwrite the one that took over.
+	Lease string
+	// RequestHash identifies the method, path and body of the request.
+	RequestHash string
+	// Status is zero while the first request is still in progress.
+	Status      int
+	ContentType string
+	// Header holds the other response headers a replay repeats, such as
+	// ETag and Location.
+	Header map[string]string
+	Body   []byte
+	// CreatedAt is when the key was reserved or last taken over.
+	CreatedAt time.Time
+}
+
+// Completed reports whether the response has been stored.
+func (r IdempotencyRecord) Completed() bool { return r.Status != 0 }
@@ -0,0 +1,64 @@
+package domain
+
+// ImportAction is what a bulk import does to a team or a user.
+type ImportAction string
+
+const (
+	ImportCreate    ImportAction = "create"
+	ImportUpdate    ImportAction = "update"
+	ImportMove      ImportAction = "move"
+	ImportUnchanged ImportAction = "unchanged"
+)
+
+// Kinds of entities in an ImportReport.
+const (
+	ImportKindTeam = "team"
+	ImportKindUser = "user"
+)
+
+// ImportChange is the outcome of importing one team or user. FromTeam is the
+// user's previous team when it moves.
+type ImportChange struct {
+	Kind     string
+	ID       string
+	Action   ImportAction
+	FromTeam string
+	TeamName string
+}
+
+// ImportReport lists every team and user of an import in snapshot order. A
+// dry run reports the same changes but does not store them.
+type ImportReport struct {
+	DryRun  bool
+	Changes []ImportChange
+}
+
+// Count returns how many entities of kind the import gave action.
+func (r ImportReport) Count(kind string, action ImportAction) int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Kind == kind && c.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// PlanUserImport tells what upserting u into teamName does to the stored
+// user before, nil when there is none. As with every upsert, an empty Role,
+// Email, Timezone or ManagerID keeps the stored value.
+func PlanUserImport(before *User, u User, teamName string) ImportAction {
+	keeps := func(in, stored string) bool { return in == "" || in == stored }
+	switch {
+	case before == nil:
+		return ImportCreate
+	case before.TeamName != teamName:
+		return ImportMove
+	case before.Username != u.Username || before.IsActive != u.IsActive ||
+		!keeps(string(u.Role), string(before.Role)) || !keeps(u.Email, before.Email) ||
+		!keeps(u.Timezone, before.Timezone) || !keeps(u.ManagerID, before.ManagerID):
+		return ImportUpdate
+	default:
+		return ImportUnchanged
+	}
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultOrg owns all data created before organizations existed and is used
+// when a request does not name a tenant.
+const DefaultOrg = "default"
+
+type Organization struct {
+	OrgID     string
+	Name      string
+	CreatedAt time.Time
+}
+
+type orgKey struct{}
+
+func WithOrg(ctx context.Context, orgID string) context.Context {
+	return context.WithValue(ctx, orgKey{}, orgID)
+}
+
+// OrgFromContext returns the tenant every repository query is scoped to.
+func OrgFromContext(ctx context.Context) string {
+	if org, ok := ctx.Value(orgKey{}).(string); ok && org != "" {
+		return org
+	}
+	return DefaultOrg
+}
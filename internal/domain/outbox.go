@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending"
+	OutboxPublished OutboxStatus = "published"
+	OutboxFailed    OutboxStatus = "failed"
+)
+
+// OutboxMessage is an event stored in the same transaction as the change it
+// describes, waiting to be, or already, published. Messages with the same
+// Key are published in ID order.
+type OutboxMessage struct {
+	ID        int64
+	Key       string
+	Event     Event
+	Status    OutboxStatus
+	Attempts  int
+	LastError string
+	// PublishedTo names the sinks that have the event already; retries
+	// skip them.
+	PublishedTo   []string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
@@ -1,12 +1,19 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"slices"
+	"time"
+)
 
 type PRStatus string
 
 const (
 	StatusOpen   PRStatus = "OPEN"
 	StatusMerged PRStatus = "MERGED"
+	// StatusClosed is a PR taken out of review without a merge: closed or
+	// turned back into a draft on a code hosting platform.
+	StatusClosed PRStatus = "CLOSED"
 )
 
 type PullRequest struct {
@@ -17,11 +24,67 @@ type PullRequest struct {
 	AssignedReviewers []string
 	CreatedAt         *time.Time
 	MergedAt          *time.Time
+	// Version starts at 1 and grows with every stored change of the PR, its
+	// reviewers or their verdicts.
+	Version int64
+}
+
+// MatchesVersion reports whether the PR is at version, the one a caller
+// last saw. Zero matches any version.
+func (p PullRequest) MatchesVersion(version int64) bool {
+	return version == 0 || p.Version == version
+}
+
+// CheckOpen fails with ErrPRMerged or ErrPRClosed unless the PR is open.
+func (p PullRequest) CheckOpen() error {
+	switch p.Status {
+	case StatusMerged:
+		return ErrPRMerged
+	case StatusClosed:
+		return ErrPRClosed
+	}
+	return nil
+}
+
+// CanReplaceReviewer fails unless the PR is open and has oldID but not
+// newID among its reviewers. Stores check it again once they hold the PR,
+// since a concurrent change may have merged or closed it or replaced either
+// reviewer after the caller read it.
+func (p PullRequest) CanReplaceReviewer(oldID, newID string) error {
+	if err := p.CheckOpen(); err != nil {
+		return err
+	}
+	switch {
+	case !slices.Contains(p.AssignedReviewers, oldID):
+		return ErrNotAssigned
+	case slices.Contains(p.AssignedReviewers, newID):
+		return fmt.Errorf("%w: %s was assigned meanwhile", ErrNoCandidate, newID)
+	}
+	return nil
+}
+
+type ReviewVerdict string
+
+const (
+	VerdictApproved         ReviewVerdict = "approved"
+	VerdictChangesRequested ReviewVerdict = "changes_requested"
+)
+
+func (v ReviewVerdict) Valid() bool {
+	return v == VerdictApproved || v == VerdictChangesRequested
+}
+
+// Review is the latest verdict of a reviewer on a pull request.
+type Review struct {
+	ReviewerID string
+	Verdict    ReviewVerdict
+	CreatedAt  time.Time
 }
 
 type PullRequestShort struct {
-	ID       string
-	Name     string
-	AuthorID string
-	Status   PRStatus
+	ID        string
+	Name      string
+	AuthorID  string
+	Status    PRStatus
+	CreatedAt *time.Time
 }
@@ -0,0 +1,14 @@
+package domain
+
+// SlackChannel is where notifications for a team's reviewers are posted: a
+// Slack incoming webhook, or a webhook of any chat that accepts its payload.
+type SlackChannel struct {
+	TeamName   string
+	WebhookURL string
+}
+
+// SlackUser maps a user to the Slack member id used to mention them.
+type SlackUser struct {
+	UserID  string
+	SlackID string
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Subscription asks for events of the listed types to be POSTed to URL,
+// signed with Secret.
+type Subscription struct {
+	ID         string
+	OrgID      string
+	URL        string
+	Secret     string
+	EventTypes []EventType
+	CreatedAt  time.Time
+}
+
+func (s Subscription) Wants(t EventType) bool {
+	return slices.Contains(s.EventTypes, t)
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// EventDelivery is one event sent, or to be sent, to one subscription.
+// Payload is the exact request body so redeliveries are byte-identical.
+// ResponseCode is the HTTP status of the last attempt, 0 if none arrived.
+type EventDelivery struct {
+	ID             int64
+	OrgID          string
+	SubscriptionID string
+	EventID        string
+	EventType      EventType
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	ResponseCode   int
+	LastError      string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
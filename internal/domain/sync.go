@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+type SyncStatus string
+
+const (
+	SyncPending SyncStatus = "pending"
+	SyncDone    SyncStatus = "done"
+	SyncFailed  SyncStatus = "failed"
+)
+
+// ReviewerSync is one change of a PR's reviewers that still has to be, or
+// was, pushed to the code hosting platform. Add and Remove hold user ids.
+type ReviewerSync struct {
+	ID            int64
+	OrgID         string
+	PRID          string
+	Add           []string
+	Remove        []string
+	Status        SyncStatus
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
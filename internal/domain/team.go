@@ -2,5 +2,9 @@ package domain
 
 type Team struct {
 	TeamName string
+	// FourEyes makes merging a PR authored in the team require an approval
+	// from a reviewer outside the author's reporting line, and a merger
+	// other than the author.
+	FourEyes bool
 	Members  []User
 }
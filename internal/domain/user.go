@@ -1,8 +1,51 @@
 package domain
 
+import (
+	"fmt"
+	"time"
+)
+
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleLead   Role = "lead"
+	RoleMember Role = "member"
+)
+
 type User struct {
 	UserID   string
 	Username string
 	TeamName string
 	IsActive bool
+	// Role is empty when an upsert should keep the stored role.
+	Role Role
+	// Email is where notifications go. As with Role, an upsert with an empty
+	// Email keeps the stored address.
+	Email string
+	// Timezone is an IANA name such as Europe/Berlin; empty means the
+	// service default. An upsert with an empty Timezone keeps the stored one.
+	Timezone string
+	// AwayUntil is set while the user is absent. Upserts do not change it.
+	AwayUntil *time.Time
+	// ManagerID is the user's manager; following it gives the reporting
+	// line. An upsert with an empty ManagerID keeps the stored one.
+	ManagerID string
+}
+
+// Away reports whether the user is absent at t.
+func (u User) Away(t time.Time) bool {
+	return u.AwayUntil != nil && t.Before(*u.AwayUntil)
+}
+
+// Location resolves Timezone, falling back to def when it is empty.
+func (u User) Location(def *time.Location) (*time.Location, error) {
+	if u.Timezone == "" {
+		return def, nil
+	}
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalid, u.Timezone)
+	}
+	return loc, nil
 }
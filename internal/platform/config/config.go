@@ -1,14 +1,133 @@
 package config
 
-import "github.com/caarlos0/env/v10"
+import (
+	"time"
+
+	"github.com/caarlos0/env/v10"
+)
 
 type Config struct {
 	HTTPPort string `env:"HTTP_PORT,notEmpty" envDefault:"8080"`
 	DB       struct {
-		DSN string `env:"DB_DSN"`
-		URL string `env:"DATABASE_URL"`
+		Driver string `env:"DB_DRIVER" envDefault:"postgres"`
+		DSN    string `env:"DB_DSN"`
+		URL    string `env:"DATABASE_URL"`
 	}
 	LogLevel string `env:"LOG_LEVEL" envDefault:"info"`
+	JWT      struct {
+		JWKSFile      string        `env:"JWT_JWKS_FILE"`
+		JWKSURL       string        `env:"JWT_JWKS_URL"`
+		Issuer        string        `env:"JWT_ISSUER"`
+		Audience      string        `env:"JWT_AUDIENCE"`
+		UserClaim     string        `env:"JWT_USER_CLAIM" envDefault:"sub"`
+		ScopeClaim    string        `env:"JWT_SCOPE_CLAIM" envDefault:"scope"`
+		OrgClaim      string        `env:"JWT_ORG_CLAIM"`
+		Org           string        `env:"JWT_ORG" envDefault:"default"`
+		DefaultScopes []string      `env:"JWT_DEFAULT_SCOPES" envDefault:"read" envSeparator:","`
+		Refresh       time.Duration `env:"JWT_JWKS_REFRESH" envDefault:"15m"`
+	}
+	GitHub struct {
+		// WebURL is the root notifications link GitHub PRs to.
+		WebURL        string            `env:"GITHUB_WEB_URL" envDefault:"https://github.com"`
+		WebhookSecret string            `env:"GITHUB_WEBHOOK_SECRET"`
+		OrgID         string            `env:"GITHUB_WEBHOOK_ORG" envDefault:"default"`
+		Logins        map[string]string `env:"GITHUB_LOGINS" envSeparator:"," envKeyValSeparator:":"`
+		// Token enables pushing reviewer changes back to GitHub.
+		Token           string        `env:"GITHUB_TOKEN"`
+		APIURL          string        `env:"GITHUB_API_URL" envDefault:"https://api.github.com"`
+		SyncInterval    time.Duration `env:"GITHUB_SYNC_INTERVAL" envDefault:"5s"`
+		SyncMaxAttempts int           `env:"GITHUB_SYNC_MAX_ATTEMPTS" envDefault:"8"`
+		SyncBackoff     time.Duration `env:"GITHUB_SYNC_BACKOFF" envDefault:"10s"`
+	}
+	// Delivery of events to webhook subscriptions.
+	Delivery struct {
+		Interval    time.Duration `env:"WEBHOOK_DELIVERY_INTERVAL" envDefault:"2s"`
+		Timeout     time.Duration `env:"WEBHOOK_DELIVERY_TIMEOUT" envDefault:"10s"`
+		MaxAttempts int           `env:"WEBHOOK_DELIVERY_MAX_ATTEMPTS" envDefault:"8"`
+		Backoff     time.Duration `env:"WEBHOOK_DELIVERY_BACKOFF" envDefault:"10s"`
+	}
+	// Outbox relays stored events to the listed sinks: webhooks, log, nats,
+	// slack, email.
+	Outbox struct {
+		Sinks       []string      `env:"OUTBOX_SINKS" envDefault:"webhooks" envSeparator:","`
+		Interval    time.Duration `env:"OUTBOX_INTERVAL" envDefault:"1s"`
+		MaxAttempts int           `env:"OUTBOX_MAX_ATTEMPTS" envDefault:"8"`
+		Backoff     time.Duration `env:"OUTBOX_BACKOFF" envDefault:"5s"`
+	}
+	// EventsStreamPoll bounds how late /events/stream sees an event when no
+	// notification arrives; Postgres notifies replicas at once.
+	EventsStreamPoll time.Duration `env:"EVENTS_STREAM_POLL" envDefault:"1s"`
+	NATS             struct {
+		URL           string        `env:"NATS_URL"`
+		SubjectPrefix string        `env:"NATS_SUBJECT_PREFIX" envDefault:"pr-reviewer.v1"`
+		Timeout       time.Duration `env:"NATS_TIMEOUT" envDefault:"5s"`
+	}
+	// Slack notifies reviewers in team channels when the outbox sink
+	// "slack" is enabled; channels are set with pr-reviewer slack.
+	Slack struct {
+		BaseURL   string        `env:"SLACK_BASE_URL" envDefault:"https://hooks.slack.com"`
+		Templates string        `env:"SLACK_TEMPLATES"`
+		Timeout   time.Duration `env:"SLACK_TIMEOUT" envDefault:"10s"`
+	}
+	// Email sends reviewer notifications over SMTP when the outbox sink
+	// "email" is enabled.
+	Email struct {
+		SMTPAddr     string        `env:"SMTP_ADDR"`
+		SMTPUsername string        `env:"SMTP_USERNAME"`
+		SMTPPassword string        `env:"SMTP_PASSWORD"`
+		From         string        `env:"EMAIL_FROM"`
+		Templates    string        `env:"EMAIL_TEMPLATES"`
+		BatchWindow  time.Duration `env:"EMAIL_BATCH_WINDOW" envDefault:"5m"`
+		SLA          time.Duration `env:"EMAIL_SLA" envDefault:"24h"`
+		Interval     time.Duration `env:"EMAIL_INTERVAL" envDefault:"30s"`
+		Timeout      time.Duration `env:"EMAIL_TIMEOUT" envDefault:"30s"`
+		MaxAttempts  int           `env:"EMAIL_MAX_ATTEMPTS" envDefault:"8"`
+		Backoff      time.Duration `env:"EMAIL_BACKOFF" envDefault:"1m"`
+	}
+	// Digest emails every reviewer a daily list of their open reviews from
+	// At (15:04, local to the reviewer) on; it needs SMTP_ADDR. Timezone is
+	// used for users who did not set one.
+	Digest struct {
+		At       string        `env:"DIGEST_AT"`
+		Timezone string        `env:"DIGEST_TIMEZONE" envDefault:"UTC"`
+		Interval time.Duration `env:"DIGEST_INTERVAL" envDefault:"1m"`
+	}
+	// ChatOps serves slash commands at /chatops/slack when the signing
+	// secret is set and at /chatops/mattermost when the token is set.
+	ChatOps struct {
+		OrgID              string            `env:"CHATOPS_ORG" envDefault:"default"`
+		SlackSigningSecret string            `env:"SLACK_SIGNING_SECRET"`
+		MattermostToken    string            `env:"MATTERMOST_TOKEN"`
+		MattermostUsers    map[string]string `env:"MATTERMOST_USERS" envSeparator:"," envKeyValSeparator:":"`
+		MattermostNames    map[string]string `env:"MATTERMOST_USERNAMES" envSeparator:"," envKeyValSeparator:":"`
+	}
+	// Audit.SigningKey is the base64 Ed25519 seed that signs review record
+	// exports; without it exports are disabled.
+	Audit struct {
+		SigningKey string `env:"AUDIT_SIGNING_KEY"`
+	}
+	// Idempotency keeps responses to requests sent with an Idempotency-Key
+	// for TTL; a retry after that runs the request again. A request still
+	// unfinished after Lease is taken to have crashed and a retry runs it.
+	Idempotency struct {
+		TTL   time.Duration `env:"IDEMPOTENCY_TTL" envDefault:"24h"`
+		Lease time.Duration `env:"IDEMPOTENCY_LEASE" envDefault:"1m"`
+	}
+	// SCIM puts users an identity provider creates, and members it removes
+	// from a team, into DefaultTeam.
+	SCIM struct {
+		DefaultTeam string `env:"SCIM_DEFAULT_TEAM" envDefault:"unassigned"`
+	}
+	GitLab struct {
+		WebURL       string            `env:"GITLAB_WEB_URL" envDefault:"https://gitlab.com"`
+		WebhookToken string            `env:"GITLAB_WEBHOOK_TOKEN"`
+		Users        map[string]string `env:"GITLAB_USERS" envSeparator:"," envKeyValSeparator:":"`
+	}
+}
+
+// JWTEnabled reports whether a JWKS source is configured.
+func (c Config) JWTEnabled() bool {
+	return c.JWT.JWKSFile != "" || c.JWT.JWKSURL != ""
 }
 
 func Load() Config {
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// ErrNoSigningKey is returned by ExportPR when no signing key is set.
+var ErrNoSigningKey = errors.New("audit signing key is not configured")
+
+// AuditUsecase reads the audit log, checks its hash chain and exports
+// signed review records. Entries are written by the repositories together
+// with the changes they describe.
+type AuditUsecase struct {
+	audit AuditRepo
+	prs   PRRepo
+	// key signs exports; nil disables them.
+	key ed25519.PrivateKey
+	now func() time.Time
+}
+
+func NewAuditUsecase(audit AuditRepo, prs PRRepo, key ed25519.PrivateKey) *AuditUsecase {
+	return &AuditUsecase{audit: audit, prs: prs, key: key, now: time.Now}
+}
+
+// List returns the entries matching f, newest first. A limit outside 1..100
+// is taken as 100.
+func (u *AuditUsecase) List(ctx context.Context, f domain.AuditFilter) ([]domain.AuditEntry, error) {
+	if f.EntityType != "" && !slices.Contains(domain.AuditEntities, f.EntityType) {
+		return nil, fmt.Errorf("%w: unknown entity type %q", domain.ErrInvalid, f.EntityType)
+	}
+	if f.EntityID != "" && f.EntityType == "" {
+		return nil, fmt.Errorf("%w: entity_id requires entity_type", domain.ErrInvalid)
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return nil, fmt.Errorf("%w: from must be before to", domain.ErrInvalid)
+	}
+	if f.Limit <= 0 || f.Limit > 100 {
+		f.Limit = 100
+	}
+	return u.audit.ListAudit(ctx, f)
+}
+
+// Verify walks the hash chain of the organization in ctx and reports the
+// first entry that does not continue it.
+func (u *AuditUsecase) Verify(ctx context.Context) (domain.AuditChainReport, error) {
+	var (
+		report domain.AuditChainReport
+		after  int64
+	)
+	for report.Broken == nil {
+		page, err := u.audit.ListAuditChain(ctx, after, 500)
+		if err != nil {
+			return report, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, e := range page {
+			report.Next(e)
+		}
+		after = page[len(page)-1].ID
+	}
+	return report, nil
+}
+
+// SignedExport carries a review record and its Ed25519 signature over the
+// record in domain.CanonicalJSON form, so reformatting the export does not
+// invalidate it.
+type SignedExport struct {
+	Record    json.RawMessage `json:"record"`
+	KeyID     string          `json:"key_id"`
+	PublicKey string          `json:"public_key"`
+	Signature string          `json:"signature"`
+}
+
+// ExportPR returns the signed review record of the PR.
+func (u *AuditUsecase) ExportPR(ctx context.Context, prID string) (SignedExport, error) {
+	if u.key == nil {
+		return SignedExport{}, ErrNoSigningKey
+	}
+	pr, err := u.prs.GetByIDForUpdate(ctx, prID)
+	if err != nil {
+		return SignedExport{}, err
+	}
+	// Everything but the PR itself is read after it, so the record is at
+	// least as new as the state it shows.
+	var entries []domain.AuditEntry
+	f := domain.AuditFilter{EntityType: domain.EntityPullRequest, EntityID: prID, Limit: 100}
+	for {
+		page, err := u.audit.ListAudit(ctx, f)
+		if err != nil {
+			return SignedExport{}, err
+		}
+		entries = append(entries, page...)
+		if len(page) < f.Limit {
+			break
+		}
+		f.BeforeID = page[len(page)-1].ID
+	}
+	slices.Reverse(entries)
+	history, err := u.prs.ListEvents(ctx, prID)
+	if err != nil {
+		return SignedExport{}, err
+	}
+	head, err := u.audit.ListAudit(ctx, domain.AuditFilter{Limit: 1})
+	if err != nil {
+		return SignedExport{}, err
+	}
+
+	rec := domain.PRRecord{
+		OrgID:         domain.OrgFromContext(ctx),
+		PullRequestID: prID,
+		ExportedAt:    u.now().UTC(),
+		PullRequest:   domain.AuditPR(&pr),
+		Entries:       make([]domain.PRRecordEntry, 0, len(entries)),
+		History:       history,
+	}
+	if rec.History == nil {
+		rec.History = []domain.Event{}
+	}
+	for _, e := range entries {
+		rec.Entries = append(rec.Entries, domain.NewPRRecordEntry(e))
+	}
+	if len(head) > 0 {
+		rec.ChainHead = domain.PRRecordChainHead{ID: head[0].ID, Hash: head[0].Hash}
+	}
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return SignedExport{}, err
+	}
+	raw = domain.CanonicalJSON(raw)
+	pub := u.key.Public().(ed25519.PublicKey)
+	return SignedExport{
+		Record:    raw,
+		KeyID:     SigningKeyID(pub),
+		PublicKey: base64.StdEncoding.EncodeToString(pub),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(u.key, raw)),
+	}, nil
+}
+
+// SigningKeyID names a public key by the first 8 bytes of its SHA-256.
+func SigningKeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// VerifyExport checks an export against the trusted public key pub: the
+// signature over the record and the hash of every entry in it. Links
+// between the entries are not checked, as the record holds only the
+// entries of one PR; Verify checks them in the database.
+func VerifyExport(data []byte, pub ed25519.PublicKey) (domain.PRRecord, error) {
+	var (
+		exp SignedExport
+		rec domain.PRRecord
+	)
+	if err := json.Unmarshal(data, &exp); err != nil {
+		return rec, fmt.Errorf("decode export: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(exp.Signature)
+	if err != nil {
+		return rec, fmt.Errorf("decode signature: %w", err)
+	}
+	if !ed25519.Verify(pub, domain.CanonicalJSON(exp.Record), sig) {
+		return rec, fmt.Errorf("signature does not match key %s", SigningKeyID(pub))
+	}
+	if err := json.Unmarshal(exp.Record, &rec); err != nil {
+		return rec, fmt.Errorf("decode record: %w", err)
+	}
+	for _, r := range rec.Entries {
+		if e := r.AuditEntry(rec.OrgID); e.ChainHash() != e.Hash {
+			return rec, fmt.Errorf("audit entry %d: hash does not match its contents", e.ID)
+		}
+	}
+	return rec, nil
+}
@@ -0,0 +1,153 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+func TestAuditShowsWhoReassigned(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStore()
+	teams, users, prs := memory.NewTeamRepo(s), memory.NewUserRepo(s), memory.NewPRRepo(s)
+	if err := teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{
+		{UserID: "u1", Username: "alice", IsActive: true},
+		{UserID: "u2", Username: "bob", IsActive: true},
+		{UserID: "u3", Username: "carol", IsActive: true},
+		{UserID: "u4", Username: "dave", IsActive: true, Role: domain.RoleLead},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prs.CreatePRWithReviewers(ctx,
+		domain.PullRequest{ID: "pr-1", Name: "Fix login", AuthorID: "u1", Status: domain.StatusOpen}, []string{"u2"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	lead := domain.WithRequestID(domain.WithPrincipal(ctx, domain.Principal{UserID: "u4"}), "req-7")
+	if _, _, err := usecase.NewPRUsecase(users, prs).Reassign(lead, "pr-1", "u2", "", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	uc := usecase.NewAuditUsecase(memory.NewAuditRepo(s), prs, nil)
+	got, err := uc.List(ctx, domain.AuditFilter{EntityType: domain.EntityPullRequest, EntityID: "pr-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Action != domain.AuditPRReassigned || got[0].Actor != "u4" || got[0].RequestID != "req-7" {
+		t.Fatalf("pr-1 history: %+v", got)
+	}
+	if got, err := uc.List(ctx, domain.AuditFilter{Actor: "u4"}); err != nil || len(got) != 1 {
+		t.Fatalf("by actor: %+v, %v", got, err)
+	}
+
+	for name, f := range map[string]domain.AuditFilter{
+		"unknown entity": {EntityType: "robot"},
+		"id alone":       {EntityID: "pr-1"},
+		"empty window":   {From: got[0].CreatedAt, To: got[0].CreatedAt},
+	} {
+		if _, err := uc.List(ctx, f); !errors.Is(err, domain.ErrInvalid) {
+			t.Errorf("%s: got %v, want %v", name, err, domain.ErrInvalid)
+		}
+	}
+}
+
+// tamperedAudit rewrites the after state of one entry as it is read.
+type tamperedAudit struct {
+	usecase.AuditRepo
+	id int64
+}
+
+func (r tamperedAudit) ListAuditChain(ctx context.Context, afterID int64, limit int) ([]domain.AuditEntry, error) {
+	list, err := r.AuditRepo.ListAuditChain(ctx, afterID, limit)
+	for i := range list {
+		if list[i].ID == r.id {
+			list[i].After = bytes.ReplaceAll(list[i].After, []byte(`"MERGED"`), []byte(`"OPEN"`))
+		}
+	}
+	return list, err
+}
+
+func TestAuditChainAndSignedExport(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStore()
+	teams, users, prs := memory.NewTeamRepo(s), memory.NewUserRepo(s), memory.NewPRRepo(s)
+	if err := teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{
+		{UserID: "u1", Username: "alice", IsActive: true},
+		{UserID: "u2", Username: "bob", IsActive: true},
+		{UserID: "u3", Username: "carol", IsActive: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	author := domain.WithPrincipal(ctx, domain.Principal{UserID: "u1"})
+	pruc := usecase.NewPRUsecase(users, prs)
+	if _, err := pruc.CreatePR(author, "pr-1", "Fix login", "u1"); err != nil {
+		t.Fatal(err)
+	}
+	merged, err := pruc.Merge(author, "pr-1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	audit := memory.NewAuditRepo(s)
+	uc := usecase.NewAuditUsecase(audit, prs, key)
+	report, err := uc.Verify(ctx)
+	if err != nil || report.Broken != nil || report.Checked != 6 {
+		t.Fatalf("verify: %+v, %v", report, err)
+	}
+
+	mergeEntry, err := audit.ListAudit(ctx, domain.AuditFilter{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err = usecase.NewAuditUsecase(tamperedAudit{audit, mergeEntry[0].ID}, prs, nil).Verify(ctx)
+	if err != nil || report.Broken == nil || report.Broken.ID != mergeEntry[0].ID || report.Checked != 5 {
+		t.Fatalf("tampered verify: %+v, %v", report, err)
+	}
+
+	exp, err := uc.ExportPR(ctx, "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.MarshalIndent(exp, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := usecase.VerifyExport(data, pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.Entries) != 2 || rec.Entries[1].Action != domain.AuditPRMerged || rec.Entries[1].Actor != "u1" ||
+		len(rec.History) != 2 || rec.ChainHead.Hash != mergeEntry[0].Hash || merged.Status != domain.StatusMerged {
+		t.Fatalf("record: %+v", rec)
+	}
+
+	forged := bytes.Replace(data, []byte(`"actor": "u1"`), []byte(`"actor": "u3"`), 1)
+	if _, err := usecase.VerifyExport(forged, pub); err == nil {
+		t.Fatal("forged export verified")
+	}
+	other, _, _ := ed25519.GenerateKey(rand.Reader)
+	if _, err := usecase.VerifyExport(data, other); err == nil {
+		t.Fatal("export verified with another key")
+	}
+	if _, err := usecase.NewAuditUsecase(audit, prs, nil).ExportPR(ctx, "pr-1"); !errors.Is(err, usecase.ErrNoSigningKey) {
+		t.Fatalf("no key: got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// actor resolves the calling user. ok is false when the request carries no
+// user: API tokens are service accounts authorised by scopes alone, and
+// in-process callers such as the CLI have no principal at all.
+func actor(ctx context.Context, users UserRepo) (domain.User, bool, error) {
+	p, found := domain.PrincipalFromContext(ctx)
+	if !found || p.UserID == "" {
+		return domain.User{}, false, nil
+	}
+
+	u, err := users.GetByID(ctx, p.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.User{}, true, domain.ErrForbidden
+		}
+		return domain.User{}, true, err
+	}
+	return u, true, nil
+}
+
+// authorizePRChange lets the author, an assigned reviewer, the lead of the
+// author's team or an admin change a PR.
+func authorizePRChange(ctx context.Context, users UserRepo, pr domain.PullRequest, reviewers []string) error {
+	who, ok, err := actor(ctx, users)
+	if err != nil || !ok {
+		return err
+	}
+
+	if who.Role == domain.RoleAdmin || who.UserID == pr.AuthorID || slices.Contains(reviewers, who.UserID) {
+		return nil
+	}
+	if who.Role == domain.RoleLead {
+		author, err := users.GetByID(ctx, pr.AuthorID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+		if err == nil && author.TeamName == who.TeamName {
+			return nil
+		}
+	}
+	return domain.ErrForbidden
+}
+
+// authorizeTeamEdit lets leads and admins change team membership. Leads may
+// only list new users and members of their own team, and may neither hand
+// out the admin role nor change the role of an admin.
+func authorizeTeamEdit(ctx context.Context, users UserRepo, members []domain.User) error {
+	who, ok, err := actor(ctx, users)
+	if err != nil || !ok {
+		return err
+	}
+
+	switch who.Role {
+	case domain.RoleAdmin:
+		return nil
+	case domain.RoleLead:
+		for _, m := range members {
+			if m.Role == domain.RoleAdmin {
+				return domain.ErrForbidden
+			}
+			stored, err := users.GetByID(ctx, m.UserID)
+			if errors.Is(err, domain.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if stored.TeamName != who.TeamName || stored.Role == domain.RoleAdmin && m.Role != "" {
+				return domain.ErrForbidden
+			}
+		}
+		return nil
+	default:
+		return domain.ErrForbidden
+	}
+}
+
+// authorizeAdmin lets only admins through.
+func authorizeAdmin(ctx context.Context, users UserRepo) error {
+	who, ok, err := actor(ctx, users)
+	if err != nil || !ok {
+		return err
+	}
+	if who.Role != domain.RoleAdmin {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// authorizeAdminScope is authorizeAdmin for changes that tokens must not make
+// on an ordinary write scope: tokens need the admin scope.
+func authorizeAdminScope(ctx context.Context, users UserRepo) error {
+	if p, ok := domain.PrincipalFromContext(ctx); ok && p.UserID == "" && !p.HasScope(domain.ScopeAdmin) {
+		return domain.ErrForbidden
+	}
+	return authorizeAdmin(ctx, users)
+}
+
+// authorizeUserChange lets users change themselves, leads change members of
+// their own team and admins change anyone.
+func authorizeUserChange(ctx context.Context, users UserRepo, target domain.User) error {
+	who, ok, err := actor(ctx, users)
+	if err != nil || !ok {
+		return err
+	}
+
+	switch {
+	case who.Role == domain.RoleAdmin, who.UserID == target.UserID:
+		return nil
+	case who.Role == domain.RoleLead && who.TeamName == target.TeamName:
+		return nil
+	default:
+		return domain.ErrForbidden
+	}
+}
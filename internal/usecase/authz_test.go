@@ -0,0 +1,198 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+type fixture struct {
+	teams *usecase.TeamUsecase
+	users *usecase.UserUsecase
+	prs   *usecase.PRUsecase
+}
+
+// newFixture seeds team "backend" with lead l1, members u1..u4 and an
+// inactive admin, team "frontend" with lead l2 and member f1, and an admin.
+// PR pr-1 is authored by u1 and reviewed by u2 and u3.
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+	ctx := context.Background()
+
+	s := memory.NewStore()
+	teams, users, prs := memory.NewTeamRepo(s), memory.NewUserRepo(s), memory.NewPRRepo(s)
+
+	seed := map[string][]domain.User{
+		"backend": {
+			{UserID: "l1", Username: "lead", IsActive: true, Role: domain.RoleLead},
+			{UserID: "u1", Username: "u1", IsActive: true},
+			{UserID: "u2", Username: "u2", IsActive: true},
+			{UserID: "u3", Username: "u3", IsActive: true},
+			{UserID: "u4", Username: "u4", IsActive: true},
+			{UserID: "boss", Username: "boss", IsActive: false, Role: domain.RoleAdmin},
+		},
+		"frontend": {
+			{UserID: "l2", Username: "lead2", IsActive: false, Role: domain.RoleLead},
+			{UserID: "f1", Username: "f1", IsActive: true},
+			{UserID: "root", Username: "root", IsActive: false, Role: domain.RoleAdmin},
+		},
+	}
+	for team, members := range seed {
+		if err := teams.CreateTeam(ctx, team); err != nil {
+			t.Fatal(err)
+		}
+		if err := teams.UpsertUsersToTeam(ctx, team, members); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := prs.CreatePRWithReviewers(ctx, domain.PullRequest{ID: "pr-1", Name: "x", AuthorID: "u1"}, []string{"u2", "u3"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return fixture{
+		teams: usecase.NewTeamUsecase(teams, users),
+		users: usecase.NewUserUsecase(users, prs),
+		prs:   usecase.NewPRUsecase(users, prs),
+	}
+}
+
+func as(userID string) context.Context {
+	return domain.WithPrincipal(context.Background(), domain.Principal{UserID: userID, Scopes: []string{domain.ScopeAdmin}})
+}
+
+func TestReassignAuthorization(t *testing.T) {
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"author", as("u1"), nil},
+		{"assigned reviewer", as("u3"), nil},
+		{"lead of author's team", as("l1"), nil},
+		{"admin", as("root"), nil},
+		{"service token", domain.WithPrincipal(context.Background(), domain.Principal{TokenID: "t1"}), nil},
+		{"unrelated member", as("u4"), domain.ErrForbidden},
+		{"lead of another team", as("l2"), domain.ErrForbidden},
+		{"unknown user", as("ghost"), domain.ErrForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t)
+			_, _, err := f.prs.Reassign(tc.ctx, "pr-1", "u2", "", 0)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestMergeAuthorization(t *testing.T) {
+	f := newFixture(t)
+
+	if _, err := f.prs.Merge(as("f1"), "pr-1", 0); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("outsider merge: got %v", err)
+	}
+	merged, err := f.prs.Merge(as("u2"), "pr-1", 0)
+	if err != nil {
+		t.Fatalf("reviewer merge: %v", err)
+	}
+	if merged.Status != domain.StatusMerged {
+		t.Fatalf("status: got %s", merged.Status)
+	}
+}
+
+func TestCloseAndReopen(t *testing.T) {
+	f := newFixture(t)
+
+	if _, _, err := f.prs.Close(as("f1"), "pr-1"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("outsider close: got %v", err)
+	}
+	for _, step := range []struct {
+		name    string
+		op      func(context.Context, string) (domain.PullRequest, bool, error)
+		status  domain.PRStatus
+		changed bool
+	}{
+		{"close", f.prs.Close, domain.StatusClosed, true},
+		{"close again", f.prs.Close, domain.StatusClosed, false},
+		{"reopen", f.prs.Reopen, domain.StatusOpen, true},
+		{"reopen again", f.prs.Reopen, domain.StatusOpen, false},
+	} {
+		pr, changed, err := step.op(as("u1"), "pr-1")
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if pr.Status != step.status || changed != step.changed || len(pr.AssignedReviewers) != 2 {
+			t.Fatalf("%s: got %+v, changed %v", step.name, pr, changed)
+		}
+	}
+
+	if _, _, err := f.prs.Close(as("u1"), "pr-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.prs.Merge(as("u2"), "pr-1", 0); !errors.Is(err, domain.ErrPRClosed) {
+		t.Fatalf("merge closed: got %v", err)
+	}
+	if _, _, err := f.prs.RecordMerge(as("u2"), "pr-1", "u2"); !errors.Is(err, domain.ErrPRClosed) {
+		t.Fatalf("record merge of closed: got %v", err)
+	}
+	if _, err := f.prs.Review(as("u2"), "pr-1", "u2", domain.VerdictApproved, 0); !errors.Is(err, domain.ErrPRClosed) {
+		t.Fatalf("review closed: got %v", err)
+	}
+	if _, _, err := f.prs.Reassign(as("u1"), "pr-1", "u2", "", 0); !errors.Is(err, domain.ErrPRClosed) {
+		t.Fatalf("reassign closed: got %v", err)
+	}
+}
+
+func TestTeamEditAuthorization(t *testing.T) {
+	f := newFixture(t)
+	members := []domain.User{{UserID: "n1", Username: "n1", IsActive: true}}
+
+	if _, err := f.teams.CreateTeam(as("u1"), "mobile", members); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("member: got %v", err)
+	}
+
+	escalate := []domain.User{{UserID: "n1", Username: "n1", IsActive: true, Role: domain.RoleAdmin}}
+	if _, err := f.teams.CreateTeam(as("l1"), "mobile", escalate); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("lead granting admin: got %v", err)
+	}
+
+	poach := []domain.User{{UserID: "f1", Username: "f1", IsActive: true}}
+	if _, err := f.teams.CreateTeam(as("l1"), "mobile", poach); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("lead moving another team's member: got %v", err)
+	}
+
+	demote := []domain.User{{UserID: "boss", Username: "boss", Role: domain.RoleMember}}
+	if _, err := f.teams.CreateTeam(as("l1"), "mobile", demote); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("lead demoting an admin: got %v", err)
+	}
+
+	own := append(members, domain.User{UserID: "u4", Username: "u4", IsActive: true})
+	if _, err := f.teams.CreateTeam(as("l1"), "mobile", own); err != nil {
+		t.Fatalf("lead: %v", err)
+	}
+	if _, err := f.teams.CreateTeam(as("root"), "data", escalate); err != nil {
+		t.Fatalf("admin: %v", err)
+	}
+}
+
+func TestSetActiveAuthorization(t *testing.T) {
+	f := newFixture(t)
+
+	if _, err := f.users.SetActive(as("u4"), "u4", false); err != nil {
+		t.Fatalf("self: %v", err)
+	}
+	if _, err := f.users.SetActive(as("u1"), "u2", false); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("peer: got %v", err)
+	}
+	if _, err := f.users.SetActive(as("l1"), "u2", false); err != nil {
+		t.Fatalf("lead of team: %v", err)
+	}
+	if _, err := f.users.SetActive(as("l1"), "f1", false); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("lead of other team: got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// ChatOpsHelp lists the commands ChatOpsUsecase understands.
+const ChatOpsHelp = "Commands:\n" +
+	"• `reassign <pr> @me [reason]` or `reassign <pr> @user [reason]` hands a review to someone else in the reviewer's team\n" +
+	"• `queue` or `queue @user` lists open reviews\n" +
+	"• `away until YYYY-MM-DD` pauses the daily digest until that day, `back` resumes it"
+
+// ChatOpsUsecase runs slash commands typed in chat on behalf of the chat
+// user. Mentions reach it as @<user id>, with @me for the caller; mapping
+// chat accounts to users is up to the adapter. The caller must be the
+// principal in ctx so that the usual authorization applies.
+type ChatOpsUsecase struct {
+	prs   *PRUsecase
+	user  *UserUsecase
+	users UserRepo
+	// loc is the timezone of users who did not set one.
+	loc *time.Location
+	now func() time.Time
+}
+
+func NewChatOpsUsecase(prs *PRUsecase, user *UserUsecase, users UserRepo, loc *time.Location) *ChatOpsUsecase {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &ChatOpsUsecase{prs: prs, user: user, users: users, loc: loc, now: time.Now}
+}
+
+// Run executes text, the command line without the slash command, for
+// callerID and returns the reply. Mistakes of the caller are explained in
+// the reply; only failures of the service are returned as errors.
+func (u *ChatOpsUsecase) Run(ctx context.Context, callerID, text string) (string, error) {
+	caller, err := u.users.GetByID(ctx, callerID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return "Your chat account is not linked to a pr-reviewer user.", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	args := strings.Fields(text)
+	if len(args) == 0 {
+		return ChatOpsHelp, nil
+	}
+	switch cmd, args := strings.ToLower(args[0]), args[1:]; {
+	case cmd == "help":
+		return ChatOpsHelp, nil
+	case cmd == "reassign" && len(args) >= 2:
+		return u.reassign(ctx, caller, args[0], args[1], strings.Join(args[2:], " "))
+	case cmd == "queue" && len(args) <= 1:
+		who := "@me"
+		if len(args) == 1 {
+			who = args[0]
+		}
+		return u.queue(ctx, caller, who)
+	case cmd == "away" && len(args) == 2 && strings.EqualFold(args[0], "until"):
+		return u.away(ctx, caller, args[1])
+	case cmd == "back" && len(args) == 0:
+		if _, err := u.user.SetAway(ctx, caller.UserID, nil); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Welcome back, %s.", caller.Username), nil
+	default:
+		return "Sorry, I did not get that.\n" + ChatOpsHelp, nil
+	}
+}
+
+// mention resolves @me and @<user id>.
+func (u *ChatOpsUsecase) mention(ctx context.Context, caller domain.User, arg string) (domain.User, string, error) {
+	id, ok := strings.CutPrefix(arg, "@")
+	if !ok || id == "" {
+		return domain.User{}, fmt.Sprintf("Expected a user like @me, got %q.", arg), nil
+	}
+	if id == "me" || id == caller.UserID {
+		return caller, "", nil
+	}
+	user, err := u.users.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.User{}, fmt.Sprintf("I do not know %s.", arg), nil
+	}
+	return user, "", err
+}
+
+func (u *ChatOpsUsecase) reassign(ctx context.Context, caller domain.User, prID, who, reason string) (string, error) {
+	old, reply, err := u.mention(ctx, caller, who)
+	if reply != "" || err != nil {
+		return reply, err
+	}
+
+	_, next, err := u.prs.Reassign(ctx, prID, old.UserID, reason, 0)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return fmt.Sprintf("PR `%s` does not exist.", prID), nil
+	case errors.Is(err, domain.ErrPRMerged):
+		return fmt.Sprintf("PR `%s` is already merged.", prID), nil
+	case errors.Is(err, domain.ErrPRClosed):
+		return fmt.Sprintf("PR `%s` is closed.", prID), nil
+	case errors.Is(err, domain.ErrNotAssigned):
+		return fmt.Sprintf("%s is not a reviewer of `%s`.", old.Username, prID), nil
+	case errors.Is(err, domain.ErrNoCandidate):
+		return fmt.Sprintf("Nobody else in %s's team can take `%s`.", old.Username, prID), nil
+	case errors.Is(err, domain.ErrForbidden):
+		return fmt.Sprintf("You may not reassign reviews of `%s`.", prID), nil
+	case err != nil:
+		return "", err
+	}
+	return fmt.Sprintf("Reassigned `%s` from %s to %s.", prID, old.Username, u.username(ctx, next)), nil
+}
+
+func (u *ChatOpsUsecase) queue(ctx context.Context, caller domain.User, who string) (string, error) {
+	user, reply, err := u.mention(ctx, caller, who)
+	if reply != "" || err != nil {
+		return reply, err
+	}
+	list, err := u.user.GetReviews(ctx, user.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	n := 0
+	now := u.now()
+	// Oldest first: ListByReviewer returns the newest first.
+	for i := len(list) - 1; i >= 0; i-- {
+		pr := list[i]
+		if pr.Status != domain.StatusOpen {
+			continue
+		}
+		n++
+		fmt.Fprintf(&b, "\n• `%s` %s by %s", pr.ID, pr.Name, u.username(ctx, pr.AuthorID))
+		if pr.CreatedAt != nil {
+			fmt.Fprintf(&b, ", open for %s", age(now.Sub(*pr.CreatedAt)))
+		}
+	}
+	if n == 0 {
+		return fmt.Sprintf("No open reviews for %s.", user.Username), nil
+	}
+	return fmt.Sprintf("Open reviews for %s (%d):%s", user.Username, n, b.String()), nil
+}
+
+func (u *ChatOpsUsecase) away(ctx context.Context, caller domain.User, date string) (string, error) {
+	loc, err := caller.Location(u.loc)
+	if err != nil {
+		loc = u.loc
+	}
+	until, err := time.ParseInLocation(time.DateOnly, date, loc)
+	if err != nil {
+		return fmt.Sprintf("Expected a date like %s, got %q.", u.now().In(loc).Format(time.DateOnly), date), nil
+	}
+	if !until.After(u.now()) {
+		return fmt.Sprintf("%s is not in the future.", date), nil
+	}
+	if _, err := u.user.SetAway(ctx, caller.UserID, &until); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Got it, %s: no daily digest until %s (%s). Say `back` when you return earlier.",
+		caller.Username, date, loc), nil
+}
+
+// username falls back to the id of users that no longer exist.
+func (u *ChatOpsUsecase) username(ctx context.Context, id string) string {
+	if user, err := u.users.GetByID(ctx, id); err == nil {
+		return user.Username
+	}
+	return id
+}
+
+// age rounds d down to days and hours, or minutes under an hour.
+func age(d time.Duration) string {
+	days, hours := int(d/(24*time.Hour)), int(d%(24*time.Hour)/time.Hour)
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	}
+}
@@ -0,0 +1,84 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+func TestChatOpsCommands(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStore()
+	teams, users, prs := memory.NewTeamRepo(s), memory.NewUserRepo(s), memory.NewPRRepo(s)
+	for team, members := range map[string][]domain.User{
+		"backend": {
+			{UserID: "u1", Username: "alice", IsActive: true},
+			{UserID: "u2", Username: "bob", IsActive: true, Timezone: "Europe/Berlin"},
+			{UserID: "u3", Username: "carol", IsActive: true},
+			{UserID: "u4", Username: "dave", IsActive: true},
+		},
+		"mobile": {{UserID: "m1", Username: "mia", IsActive: true}},
+	} {
+		if err := teams.CreateTeam(ctx, team); err != nil {
+			t.Fatal(err)
+		}
+		if err := teams.UpsertUsersToTeam(ctx, team, members); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := prs.CreatePRWithReviewers(ctx,
+		domain.PullRequest{ID: "pr-1", Name: "Fix login", AuthorID: "u1", Status: domain.StatusOpen}, []string{"u2", "u3"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	userUC := usecase.NewUserUsecase(users, prs)
+	uc := usecase.NewChatOpsUsecase(usecase.NewPRUsecase(users, prs), userUC, users, nil)
+	run := func(caller, text string, want ...string) {
+		t.Helper()
+		ctx := domain.WithPrincipal(ctx, domain.Principal{UserID: caller})
+		got, err := uc.Run(ctx, caller, text)
+		if err != nil {
+			t.Fatalf("%s: %v", text, err)
+		}
+		for _, w := range want {
+			if !strings.Contains(got, w) {
+				t.Fatalf("%s: got %q, want it to contain %q", text, got, w)
+			}
+		}
+	}
+
+	run("ghost", "queue", "not linked")
+	run("u2", "", "Commands:")
+	run("u2", "dance", "did not get that")
+	run("u2", "queue", "Open reviews for bob (1):", "`pr-1` Fix login by alice, open for 0m")
+	run("u2", "queue @u4", "No open reviews for dave.")
+	run("u2", "queue @nobody", "I do not know @nobody.")
+
+	run("m1", "reassign pr-1 @u2", "You may not reassign reviews of `pr-1`.")
+	run("u2", "reassign pr-9 @me", "PR `pr-9` does not exist.")
+	run("u2", "reassign pr-1 @u4", "dave is not a reviewer of `pr-1`.")
+	run("u2", "reassign pr-1 @me", "Reassigned `pr-1` from bob to dave.")
+	run("u3", "reassign pr-1 @me", "Reassigned `pr-1` from carol to bob.")
+
+	run("u2", "away until yesterday", "Expected a date like")
+	run("u2", "away until 2000-01-01", "not in the future")
+	next := time.Now().AddDate(0, 0, 7).Format(time.DateOnly)
+	run("u2", "away until "+next, "no daily digest until "+next+" (Europe/Berlin)")
+	u2, err := users.GetByID(ctx, "u2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	if want, _ := time.ParseInLocation(time.DateOnly, next, berlin); u2.AwayUntil == nil || !u2.AwayUntil.Equal(want) {
+		t.Fatalf("away until: got %v, want %v", u2.AwayUntil, want)
+	}
+	run("u2", "back", "Welcome back, bob.")
+	if u2, _ = users.GetByID(ctx, "u2"); u2.AwayUntil != nil {
+		t.Fatalf("still away: %v", u2.AwayUntil)
+	}
+}
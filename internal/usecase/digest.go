@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// DigestSender renders and sends a daily digest. Errors wrapping
+// ErrPermanent are not retried that day.
+type DigestSender interface {
+	SendDigest(ctx context.Context, d domain.ReviewDigest) error
+}
+
+type DigestConfig struct {
+	// At is the local time of day, as an offset from midnight, from which a
+	// reviewer's digest is due.
+	At time.Duration
+	// Location is the timezone of users who did not set one.
+	Location *time.Location
+	// SLA marks PRs open longer than it as overdue; zero marks none.
+	SLA time.Duration
+}
+
+// DigestUsecase sends every reviewer with open reviews one email a day
+// listing them, in the reviewer's timezone and not while they are away.
+type DigestUsecase struct {
+	digests DigestRepo
+	prs     PRRepo
+	users   UserRepo
+	sender  DigestSender
+	cfg     DigestConfig
+	log     *slog.Logger
+	now     func() time.Time
+}
+
+func NewDigestUsecase(digests DigestRepo, prs PRRepo, users UserRepo, sender DigestSender, cfg DigestConfig, logger *slog.Logger) *DigestUsecase {
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	return &DigestUsecase{digests: digests, prs: prs, users: users, sender: sender, cfg: cfg, log: logger, now: time.Now}
+}
+
+// RunOnce sends the digests that are due and returns how many went out. A
+// digest that fails temporarily is tried again on the next run.
+func (u *DigestUsecase) RunOnce(ctx context.Context) (int, error) {
+	recipients, err := u.digests.ListDigestRecipients(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, d := range recipients {
+		now := u.now()
+		loc, err := d.To.Location(u.cfg.Location)
+		if err != nil {
+			u.log.Warn("digest: skipping user", "org", d.OrgID, "user", d.To.UserID, "err", err)
+			continue
+		}
+		local := now.In(loc)
+		midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		if local.Before(midnight.Add(u.cfg.At)) || d.To.Away(now) {
+			continue
+		}
+		d.Day = local.Format(time.DateOnly)
+
+		ctx := domain.WithOrg(ctx, d.OrgID)
+		ok, err := u.digests.ClaimDigest(ctx, d.To.UserID, d.Day)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return sent, fmt.Errorf("claim digest of %s: %w", d.To.UserID, err)
+		}
+		if !ok {
+			continue
+		}
+
+		sendErr := u.send(ctx, now, d)
+		switch {
+		case errors.Is(sendErr, errNothingToSend):
+		case sendErr == nil:
+			sent++
+		case errors.Is(sendErr, ErrPermanent):
+			u.log.Warn("digest: send failed", "org", d.OrgID, "user", d.To.UserID, "day", d.Day, "err", sendErr)
+		default:
+			u.log.Warn("digest: send failed, will retry", "org", d.OrgID, "user", d.To.UserID, "day", d.Day, "err", sendErr)
+			if err := u.digests.ReleaseDigest(ctx, d.To.UserID, d.Day); err != nil {
+				return sent, fmt.Errorf("release digest of %s: %w", d.To.UserID, err)
+			}
+		}
+	}
+	return sent, nil
+}
+
+var errNothingToSend = errors.New("no open reviews")
+
+func (u *DigestUsecase) send(ctx context.Context, now time.Time, d domain.ReviewDigest) error {
+	list, err := u.prs.ListByReviewer(ctx, d.To.UserID)
+	if err != nil {
+		return err
+	}
+	d.Usernames = map[string]string{d.To.UserID: d.To.Username}
+	for _, pr := range list {
+		if pr.Status != domain.StatusOpen {
+			continue
+		}
+		item := domain.DigestPR{PullRequestShort: pr}
+		if pr.CreatedAt != nil {
+			item.Age = now.Sub(*pr.CreatedAt)
+			item.Overdue = u.cfg.SLA > 0 && item.Age > u.cfg.SLA
+		}
+		d.PRs = append(d.PRs, item)
+
+		if _, ok := d.Usernames[pr.AuthorID]; ok {
+			continue
+		}
+		author, err := u.users.GetByID(ctx, pr.AuthorID)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		d.Usernames[pr.AuthorID] = author.Username
+	}
+	if len(d.PRs) == 0 {
+		return errNothingToSend
+	}
+	// Oldest first: those are the ones waiting longest.
+	slices.SortStableFunc(d.PRs, func(a, b domain.DigestPR) int { return cmp.Compare(b.Age, a.Age) })
+	return u.sender.SendDigest(ctx, d)
+}
+
+// Run sends due digests every interval until ctx is done.
+func (u *DigestUsecase) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		if _, err := u.RunOnce(ctx); err != nil {
+			u.log.Error("digest: run failed", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+type fakeDigestSender struct {
+	errs map[string]error // by user id
+	sent []domain.ReviewDigest
+}
+
+func (f *fakeDigestSender) SendDigest(_ context.Context, d domain.ReviewDigest) error {
+	if err := f.errs[d.To.UserID]; err != nil {
+		return err
+	}
+	f.sent = append(f.sent, d)
+	return nil
+}
+
+func TestDigestOncePerDaySkippingAbsentUsers(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStore()
+	teams := memory.NewTeamRepo(s)
+	users := memory.NewUserRepo(s)
+	prs := memory.NewPRRepo(s)
+	if err := teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{
+		{UserID: "u1", Username: "alice", Email: "alice@example.com", Timezone: "Asia/Tokyo", IsActive: true},
+		{UserID: "u2", Username: "bob", Email: "bob@example.com", IsActive: true},
+		{UserID: "u3", Username: "carol", Email: "carol@example.com", IsActive: true},
+		{UserID: "u4", Username: "dave", IsActive: true},
+		{UserID: "u5", Username: "erin", Email: "erin@example.com", IsActive: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	userUC := usecase.NewUserUsecase(users, prs)
+	tomorrow := time.Now().Add(24 * time.Hour)
+	if _, err := userUC.SetAway(ctx, "u2", &tomorrow); err != nil {
+		t.Fatal(err)
+	}
+	if err := memory.NewEmailRepo(s).SetEmailPrefs(ctx, domain.EmailPrefs{UserID: "u3", Assigned: true}); err != nil {
+		t.Fatal(err)
+	}
+	for _, pr := range []struct {
+		id        string
+		reviewers []string
+	}{
+		{"pr-1", []string{"u1", "u2", "u3", "u5"}},
+		{"pr-2", []string{"u1"}},
+		{"pr-3", []string{"u1"}},
+	} {
+		if _, err := prs.CreatePRWithReviewers(ctx,
+			domain.PullRequest{ID: pr.id, Name: pr.id, AuthorID: "u4", Status: domain.StatusOpen}, pr.reviewers, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := prs.SetMerged(ctx, "pr-3", 0, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &fakeDigestSender{}
+	newUC := func(cfg usecase.DigestConfig) *usecase.DigestUsecase {
+		return usecase.NewDigestUsecase(memory.NewDigestRepo(s), prs, users, sender, cfg,
+			slog.New(slog.NewTextHandler(io.Discard, nil)))
+	}
+
+	// Nobody's local day has reached 24:00.
+	if n, err := newUC(usecase.DigestConfig{At: 24 * time.Hour}).RunOnce(ctx); err != nil || n != 0 {
+		t.Fatalf("not due: sent %d, %v", n, err)
+	}
+
+	// u2 is away, u3 turned the digest off and u4 has no address; u5 fails
+	// temporarily.
+	uc := newUC(usecase.DigestConfig{SLA: time.Nanosecond})
+	sender.errs = map[string]error{"u5": errors.New("421 try again")}
+	before := time.Now().In(mustLoad(t, "Asia/Tokyo")).Format(time.DateOnly)
+	if n, err := uc.RunOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("run: sent %d, %v", n, err)
+	}
+	after := time.Now().In(mustLoad(t, "Asia/Tokyo")).Format(time.DateOnly)
+	d := sender.sent[0]
+	if d.To.UserID != "u1" || (d.Day != before && d.Day != after) || len(d.PRs) != 2 ||
+		d.PRs[0].ID != "pr-1" || d.PRs[1].ID != "pr-2" || !d.PRs[0].Overdue || d.PRs[0].Age < d.PRs[1].Age ||
+		d.Usernames["u4"] != "dave" {
+		t.Fatalf("digest: %+v", d)
+	}
+
+	sender.errs = nil
+	if n, err := uc.RunOnce(ctx); err != nil || n != 1 || sender.sent[1].To.UserID != "u5" {
+		t.Fatalf("retry: sent %d, %v, %+v", n, err, sender.sent)
+	}
+
+	if _, err := userUC.SetAway(ctx, "u2", nil); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := uc.RunOnce(ctx); err != nil || n != 1 || sender.sent[2].To.UserID != "u2" {
+		t.Fatalf("back from absence: sent %d, %v, %+v", n, err, sender.sent)
+	}
+	if n, err := uc.RunOnce(ctx); err != nil || n != 0 {
+		t.Fatalf("already sent today: sent %d, %v", n, err)
+	}
+}
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
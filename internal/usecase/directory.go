@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// DirectoryUsecase keeps users and teams in line with an identity provider.
+// Every user belongs to a team, so users the provider creates and members
+// it removes from a team go to the default team, which is created when
+// first needed. Only admins may call it.
+type DirectoryUsecase struct {
+	teams       TeamRepo
+	users       UserRepo
+	userUC      *UserUsecase
+	defaultTeam string
+}
+
+func NewDirectoryUsecase(teams TeamRepo, users UserRepo, userUC *UserUsecase, defaultTeam string) *DirectoryUsecase {
+	return &DirectoryUsecase{teams: teams, users: users, userUC: userUC, defaultTeam: defaultTeam}
+}
+
+// ListUsers returns every user, ordered by team name and user id.
+func (u *DirectoryUsecase) ListUsers(ctx context.Context) ([]domain.User, error) {
+	teams, err := u.ListTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []domain.User
+	for _, t := range teams {
+		out = append(out, t.Members...)
+	}
+	return out, nil
+}
+
+func (u *DirectoryUsecase) GetUser(ctx context.Context, id string) (domain.User, error) {
+	if err := authorizeAdmin(ctx, u.users); err != nil {
+		return domain.User{}, err
+	}
+	return u.users.GetByID(ctx, id)
+}
+
+// CreateUser adds the user to the default team. It fails with
+// domain.ErrUserExists if the id is taken.
+func (u *DirectoryUsecase) CreateUser(ctx context.Context, user domain.User) (domain.User, error) {
+	if err := authorizeAdmin(ctx, u.users); err != nil {
+		return domain.User{}, err
+	}
+	if err := validateDirectoryUser(user); err != nil {
+		return domain.User{}, err
+	}
+	_, err := u.users.GetByID(ctx, user.UserID)
+	switch {
+	case err == nil:
+		return domain.User{}, domain.ErrUserExists
+	case !errors.Is(err, domain.ErrNotFound):
+		return domain.User{}, err
+	}
+
+	if _, err := u.teams.ImportTeams(ctx, []domain.Team{
+		{TeamName: u.defaultTeam, Members: []domain.User{user}},
+	}, false); err != nil {
+		return domain.User{}, err
+	}
+	return u.users.GetByID(ctx, user.UserID)
+}
+
+// UpdateUser stores the username, email, timezone and manager of user;
+// empty ones keep the stored values, and the team and role are kept as
+// well. A change of IsActive goes through UserUsecase.SetActive, so that
+// deprovisioning is announced like any other deactivation.
+func (u *DirectoryUsecase) UpdateUser(ctx context.Context, user domain.User) (domain.User, error) {
+	if err := authorizeAdmin(ctx, u.users); err != nil {
+		return domain.User{}, err
+	}
+	if err := validateDirectoryUser(user); err != nil {
+		return domain.User{}, err
+	}
+	stored, err := u.users.GetByID(ctx, user.UserID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	changed := func(v, was string) bool { return v != "" && v != was }
+	if user.Username != stored.Username || changed(user.Email, stored.Email) ||
+		changed(user.Timezone, stored.Timezone) || changed(user.ManagerID, stored.ManagerID) {
+		upd := user
+		upd.IsActive, upd.Role = stored.IsActive, stored.Role
+		if err := u.teams.UpsertUsersToTeam(ctx, stored.TeamName, []domain.User{upd}); err != nil {
+			return domain.User{}, err
+		}
+	}
+	if user.IsActive != stored.IsActive {
+		return u.userUC.SetActive(ctx, user.UserID, user.IsActive)
+	}
+	return u.users.GetByID(ctx, user.UserID)
+}
+
+// DeactivateUser is what deleting a user in the provider does here: the
+// user stays, inactive, so that their pull requests and reviews are kept.
+func (u *DirectoryUsecase) DeactivateUser(ctx context.Context, id string) (domain.User, error) {
+	if err := authorizeAdmin(ctx, u.users); err != nil {
+		return domain.User{}, err
+	}
+	return u.userUC.SetActive(ctx, id, false)
+}
+
+// ListTeams returns every team with its members, ordered by team name and
+// user id.
+func (u *DirectoryUsecase) ListTeams(ctx context.Context) ([]domain.Team, error) {
+	if err := authorizeAdmin(ctx, u.users); err != nil {
+		return nil, err
+	}
+	return listTeamsWithMembers(ctx, u.teams)
+}
+
+func (u *DirectoryUsecase) GetTeam(ctx context.Context, name string) (domain.Team, error) {
+	if err := authorizeAdmin(ctx, u.users); err != nil {
+		return domain.Team{}, err
+	}
+	return u.getTeam(ctx, name)
+}
+
+// CreateTeam creates the team and moves the listed users into it, in one
+// transaction.
+func (u *DirectoryUsecase) CreateTeam(ctx context.Context, name string, memberIDs []string) (domain.Team, error) {
+	if err := authorizeAdmin(ctx, u.users); err != nil {
+		return domain.Team{}, err
+	}
+	if name == "" {
+		return domain.Team{}, fmt.Errorf("%w: team name is required", domain.ErrInvalid)
+	}
+	_, _, err := u.teams.GetTeamWithMembers(ctx, name)
+	switch {
+	case err == nil:
+		return domain.Team{}, domain.ErrTeamExists
+	case !errors.Is(err, domain.ErrNotFound):
+		return domain.Team{}, err
+	}
+	members, err := u.storedUsers(ctx, memberIDs)
+	if err != nil {
+		return domain.Team{}, err
+	}
+
+	if _, err := u.teams.ImportTeams(ctx, []domain.Team{{TeamName: name, Members: members}}, false); err != nil {
+		return domain.Team{}, err
+	}
+	return u.getTeam(ctx, name)
+}
+
+// ChangeMembers moves the users in add into the team and its members in
+// remove to the default team, in one transaction. Users in remove who are
+// not members are skipped.
+func (u *DirectoryUsecase) ChangeMembers(ctx context.Context, name string, add, remove []string) (domain.Team, error) {
+	if err := authorizeAdmin(ctx, u.users); err != nil {
+		return domain.Team{}, err
+	}
+	team, err := u.getTeam(ctx, name)
+	if err != nil {
+		return domain.Team{}, err
+	}
+	added, err := u.storedUsers(ctx, add)
+	if err != nil {
+		return domain.Team{}, err
+	}
+	var removed []domain.User
+	for _, m := range team.Members {
+		if slices.Contains(remove, m.UserID) && !slices.Contains(add, m.UserID) {
+			removed = append(removed, m)
+		}
+	}
+	if len(removed) > 0 && name == u.defaultTeam {
+		return domain.Team{}, fmt.Errorf("%w: members cannot be removed from the default team %q", domain.ErrInvalid, name)
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		return team, nil
+	}
+
+	snapshot := []domain.Team{{TeamName: name, Members: added}}
+	if len(removed) > 0 {
+		snapshot = append(snapshot, domain.Team{TeamName: u.defaultTeam, Members: removed})
+	}
+	if _, err := u.teams.ImportTeams(ctx, snapshot, false); err != nil {
+		return domain.Team{}, err
+	}
+	return u.getTeam(ctx, name)
+}
+
+// DeleteTeam moves the members to the default team and deletes the team.
+func (u *DirectoryUsecase) DeleteTeam(ctx context.Context, name string) error {
+	if err := authorizeAdmin(ctx, u.users); err != nil {
+		return err
+	}
+	if name == u.defaultTeam {
+		return fmt.Errorf("%w: the default team %q cannot be deleted", domain.ErrInvalid, name)
+	}
+	if err := u.teams.CreateTeam(ctx, u.defaultTeam); err != nil && !errors.Is(err, domain.ErrTeamExists) {
+		return err
+	}
+	return u.teams.DeleteTeam(ctx, name, u.defaultTeam)
+}
+
+func (u *DirectoryUsecase) getTeam(ctx context.Context, name string) (domain.Team, error) {
+	team, members, err := u.teams.GetTeamWithMembers(ctx, name)
+	if err != nil {
+		return domain.Team{}, err
+	}
+	team.Members = members
+	return team, nil
+}
+
+// storedUsers loads the users with the given ids, each once.
+func (u *DirectoryUsecase) storedUsers(ctx context.Context, ids []string) ([]domain.User, error) {
+	var out []domain.User
+	for i, id := range ids {
+		if slices.Contains(ids[:i], id) {
+			continue
+		}
+		m, err := u.users.GetByID(ctx, id)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%w: unknown user %q", domain.ErrInvalid, id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+func validateDirectoryUser(user domain.User) error {
+	if user.UserID == "" || user.Username == "" {
+		return fmt.Errorf("%w: user id and username are required", domain.ErrInvalid)
+	}
+	_, err := user.Location(time.UTC)
+	return err
+}
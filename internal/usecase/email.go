@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// EmailDigest is one email: every pending item of a user, oldest first.
+type EmailDigest struct {
+	To    domain.User
+	Items []domain.EmailNotification
+	// Usernames is keyed by user id and covers the authors, reviewers and
+	// replaced reviewers of the items as far as they are known.
+	Usernames map[string]string
+}
+
+// Mailer renders and sends a digest. Errors wrapping ErrPermanent are not
+// retried.
+type Mailer interface {
+	Send(ctx context.Context, d EmailDigest) error
+}
+
+type EmailConfig struct {
+	// Window is how long the first item of an email waits for more, so that
+	// a burst of assignments makes one email.
+	Window time.Duration
+	// SLA is how long a PR may stay open before its reviewers are reminded;
+	// zero turns the reminders off.
+	SLA   time.Duration
+	Retry RetryConfig
+}
+
+// EmailUsecase queues email notifications for reviewers and sends them in
+// batches. It is an EventSink for assignments; SLA breaches are found by Run.
+type EmailUsecase struct {
+	emails EmailRepo
+	users  UserRepo
+	mailer Mailer
+	cfg    EmailConfig
+	log    *slog.Logger
+	now    func() time.Time
+}
+
+var _ EventSink = (*EmailUsecase)(nil)
+
+func NewEmailUsecase(emails EmailRepo, users UserRepo, mailer Mailer, cfg EmailConfig, logger *slog.Logger) *EmailUsecase {
+	cfg.Retry = cfg.Retry.withDefaults()
+	return &EmailUsecase{emails: emails, users: users, mailer: mailer, cfg: cfg, log: logger, now: time.Now}
+}
+
+// Prefs returns the defaults for users who never set preferences.
+func (u *EmailUsecase) Prefs(ctx context.Context, userID string) (domain.EmailPrefs, error) {
+	if _, err := u.users.GetByID(ctx, userID); err != nil {
+		return domain.EmailPrefs{}, err
+	}
+	return u.prefs(ctx, userID)
+}
+
+func (u *EmailUsecase) prefs(ctx context.Context, userID string) (domain.EmailPrefs, error) {
+	p, err := u.emails.GetEmailPrefs(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.DefaultEmailPrefs(userID), nil
+	}
+	return p, err
+}
+
+func (u *EmailUsecase) SetPrefs(ctx context.Context, p domain.EmailPrefs) error {
+	target, err := u.users.GetByID(ctx, p.UserID)
+	if err != nil {
+		return err
+	}
+	if err := authorizeUserChange(ctx, u.users, target); err != nil {
+		return err
+	}
+	return u.emails.SetEmailPrefs(ctx, p)
+}
+
+func (u *EmailUsecase) List(ctx context.Context, status domain.EmailStatus) ([]domain.EmailNotification, error) {
+	return u.emails.ListEmails(ctx, status)
+}
+
+// Publish queues an item for every reviewer that pr.created assigns and for
+// the new reviewer of pr.reassigned.
+func (u *EmailUsecase) Publish(ctx context.Context, e domain.Event) error {
+	pr := e.Data.PullRequest
+	if pr == nil {
+		return nil
+	}
+	switch e.Type {
+	case domain.EventPRCreated:
+		for _, id := range pr.AssignedReviewers {
+			n := domain.EmailNotification{UserID: id, Kind: domain.EmailAssigned, PR: *pr}
+			if err := u.enqueue(ctx, n); err != nil {
+				return err
+			}
+		}
+	case domain.EventPRReassigned:
+		n := domain.EmailNotification{
+			UserID:        e.Data.NewReviewerID,
+			Kind:          domain.EmailReassigned,
+			PR:            *pr,
+			OldReviewerID: e.Data.OldReviewerID,
+		}
+		return u.enqueue(ctx, n)
+	}
+	return nil
+}
+
+// enqueue skips users without an address, inactive users and users who do
+// not want the kind.
+func (u *EmailUsecase) enqueue(ctx context.Context, n domain.EmailNotification) error {
+	user, err := u.users.GetByID(ctx, n.UserID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.Email == "" || !user.IsActive {
+		return nil
+	}
+	p, err := u.prefs(ctx, n.UserID)
+	if err != nil {
+		return err
+	}
+	if !p.Wants(n.Kind) {
+		return nil
+	}
+	return u.emails.EnqueueEmail(ctx, n)
+}
+
+// RunOnce queues SLA breaches, then sends the emails of one batch of users
+// whose window has passed. It returns how many users were tried.
+func (u *EmailUsecase) RunOnce(ctx context.Context) (int, error) {
+	if u.cfg.SLA > 0 {
+		breaches, err := u.emails.ListSLABreaches(ctx, u.now().UTC().Add(-u.cfg.SLA), 100)
+		if err != nil {
+			return 0, err
+		}
+		for _, n := range breaches {
+			if err := u.emails.EnqueueEmail(domain.WithOrg(ctx, n.OrgID), n); err != nil {
+				return 0, fmt.Errorf("queue sla breach of %s: %w", n.PR.ID, err)
+			}
+		}
+	}
+
+	now := u.now().UTC()
+	batch, err := u.emails.ClaimEmails(ctx, now.Add(-u.cfg.Window), now, now.Add(u.cfg.Retry.Lease), 20)
+	if err != nil {
+		return 0, err
+	}
+	type recipient struct{ org, user string }
+	var order []recipient
+	byUser := make(map[recipient][]domain.EmailNotification)
+	for _, n := range batch {
+		r := recipient{n.OrgID, n.UserID}
+		if _, ok := byUser[r]; !ok {
+			order = append(order, r)
+		}
+		byUser[r] = append(byUser[r], n)
+	}
+
+	for _, r := range order {
+		items := byUser[r]
+		sendErr := u.send(domain.WithOrg(ctx, r.org), r.user, items)
+		if sendErr != nil {
+			u.log.Warn("email: send failed", "org", r.org, "user", r.user, "items", len(items), "err", sendErr)
+		}
+		for _, n := range items {
+			n.Attempts++
+			switch {
+			case sendErr == nil:
+				n.Status, n.LastError = domain.EmailSent, ""
+			case errors.Is(sendErr, ErrPermanent), n.Attempts >= u.cfg.Retry.MaxAttempts:
+				n.Status, n.LastError = domain.EmailFailed, sendErr.Error()
+			default:
+				n.LastError = sendErr.Error()
+				n.NextAttemptAt = u.now().UTC().Add(u.cfg.Retry.backoff(n.Attempts))
+			}
+			if err := u.emails.SaveEmail(ctx, n); err != nil {
+				return 0, fmt.Errorf("save email notification %d: %w", n.ID, err)
+			}
+		}
+	}
+	return len(order), nil
+}
+
+func (u *EmailUsecase) send(ctx context.Context, userID string, items []domain.EmailNotification) error {
+	to, err := u.users.GetByID(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("%w: user no longer exists", ErrPermanent)
+	}
+	if err != nil {
+		return err
+	}
+	if to.Email == "" {
+		return fmt.Errorf("%w: user has no email address", ErrPermanent)
+	}
+
+	usernames := map[string]string{to.UserID: to.Username}
+	for _, n := range items {
+		for _, id := range append([]string{n.PR.AuthorID, n.OldReviewerID}, n.PR.AssignedReviewers...) {
+			if _, ok := usernames[id]; ok || id == "" {
+				continue
+			}
+			user, err := u.users.GetByID(ctx, id)
+			if errors.Is(err, domain.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			usernames[id] = user.Username
+		}
+	}
+	return u.mailer.Send(ctx, EmailDigest{To: to, Items: items, Usernames: usernames})
+}
+
+// Run sends due emails every interval until ctx is done.
+func (u *EmailUsecase) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		for {
+			n, err := u.RunOnce(ctx)
+			if err != nil {
+				u.log.Error("email: batch failed", "err", err)
+			}
+			if err != nil || n == 0 {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
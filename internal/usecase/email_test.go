@@ -0,0 +1,133 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+type fakeMailer struct {
+	errs map[string]error // by user id
+	sent []usecase.EmailDigest
+}
+
+func (f *fakeMailer) Send(_ context.Context, d usecase.EmailDigest) error {
+	if err := f.errs[d.To.UserID]; err != nil {
+		return err
+	}
+	f.sent = append(f.sent, d)
+	return nil
+}
+
+func kinds(d usecase.EmailDigest) []domain.EmailKind {
+	out := make([]domain.EmailKind, 0, len(d.Items))
+	for _, n := range d.Items {
+		out = append(out, n.Kind)
+	}
+	return out
+}
+
+func TestEmailBatchesPerUserAndHonoursPrefs(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStore()
+	teams := memory.NewTeamRepo(s)
+	if err := teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{
+		{UserID: "u1", Username: "alice", Email: "alice@example.com", IsActive: true},
+		{UserID: "u2", Username: "bob", Email: "bob@example.com", IsActive: true},
+		{UserID: "u3", Username: "carol", IsActive: true},
+		{UserID: "u4", Username: "dave", Email: "dave@example.com", IsActive: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	mailer := &fakeMailer{}
+	newUC := func(cfg usecase.EmailConfig) *usecase.EmailUsecase {
+		cfg.Retry = usecase.RetryConfig{MaxAttempts: 2, Backoff: time.Nanosecond}
+		return usecase.NewEmailUsecase(memory.NewEmailRepo(s), memory.NewUserRepo(s), mailer, cfg,
+			slog.New(slog.NewTextHandler(io.Discard, nil)))
+	}
+	uc := newUC(usecase.EmailConfig{})
+
+	if p, err := uc.Prefs(ctx, "u4"); err != nil || p != domain.DefaultEmailPrefs("u4") {
+		t.Fatalf("default prefs: got %+v, %v", p, err)
+	}
+	if _, err := uc.Prefs(ctx, "nope"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown user: got %v, want %v", err, domain.ErrNotFound)
+	}
+	if err := uc.SetPrefs(ctx, domain.EmailPrefs{UserID: "u4", Reassigned: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// u3 has no address and u4 does not want assignments; u2 gets both
+	// items in one email.
+	pr := domain.EventPR{ID: "pr-1", Name: "Fix", AuthorID: "u1", AssignedReviewers: []string{"u2", "u3", "u4"}}
+	for _, e := range []domain.Event{
+		{ID: "e1", Type: domain.EventPRCreated, Data: domain.EventData{PullRequest: &pr}},
+		{ID: "e2", Type: domain.EventPRReassigned, Data: domain.EventData{PullRequest: &pr, OldReviewerID: "u3", NewReviewerID: "u2"}},
+		{ID: "e3", Type: domain.EventPRReassigned, Data: domain.EventData{PullRequest: &pr, OldReviewerID: "u3", NewReviewerID: "u4"}},
+		{ID: "e4", Type: domain.EventPRMerged, Data: domain.EventData{PullRequest: &pr}},
+	} {
+		if err := uc.Publish(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n, err := newUC(usecase.EmailConfig{Window: time.Hour}).RunOnce(ctx); err != nil || n != 0 {
+		t.Fatalf("inside the window: sent to %d users, %v", n, err)
+	}
+
+	mailer.errs = map[string]error{"u4": fmt.Errorf("%w: 550 no such user", usecase.ErrPermanent)}
+	if n, err := uc.RunOnce(ctx); err != nil || n != 2 {
+		t.Fatalf("run: sent to %d users, %v", n, err)
+	}
+	if len(mailer.sent) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(mailer.sent))
+	}
+	d := mailer.sent[0]
+	if d.To.Email != "bob@example.com" || len(d.Items) != 2 ||
+		kinds(d)[0] != domain.EmailAssigned || kinds(d)[1] != domain.EmailReassigned ||
+		d.Items[1].OldReviewerID != "u3" || d.Usernames["u1"] != "alice" || d.Usernames["u3"] != "carol" {
+		t.Fatalf("digest: %+v", d)
+	}
+	failed, err := uc.List(ctx, domain.EmailFailed)
+	if err != nil || len(failed) != 1 || failed[0].UserID != "u4" || failed[0].Attempts != 1 {
+		t.Fatalf("failed: got %+v, %v", failed, err)
+	}
+
+	// A PR open longer than the SLA reminds its reviewers once; a temporary
+	// failure is retried.
+	if _, err := memory.NewPRRepo(s).CreatePRWithReviewers(ctx,
+		domain.PullRequest{ID: "pr-2", Name: "Old", AuthorID: "u4", Status: domain.StatusOpen}, []string{"u1", "u2"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	mailer.sent = nil
+	mailer.errs = map[string]error{"u1": errors.New("421 try again")}
+	sla := newUC(usecase.EmailConfig{SLA: time.Nanosecond})
+	if _, err := sla.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0].To.UserID != "u2" || kinds(mailer.sent[0])[0] != domain.EmailSLABreach {
+		t.Fatalf("sla: sent %+v", mailer.sent)
+	}
+	mailer.errs = nil
+	if _, err := sla.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(mailer.sent) != 2 || mailer.sent[1].To.UserID != "u1" || len(mailer.sent[1].Items) != 1 {
+		t.Fatalf("sla retry: sent %+v", mailer.sent)
+	}
+	if pending, err := uc.List(ctx, domain.EmailPending); err != nil || len(pending) != 0 {
+		t.Fatalf("pending: got %+v, %v", pending, err)
+	}
+}
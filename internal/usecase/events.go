@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// newEvent prepares an event for the outbox; the repository fills in the
+// stored PR or user when it writes the change.
+func newEvent(ctx context.Context, typ domain.EventType, data domain.EventData) *domain.Event {
+	id, err := randomString(16, hex.EncodeToString)
+	if err != nil {
+		// crypto/rand does not fail on supported platforms.
+		panic(err)
+	}
+	e := &domain.Event{
+		ID:         id,
+		Type:       typ,
+		OrgID:      domain.OrgFromContext(ctx),
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+	if p, ok := domain.PrincipalFromContext(ctx); ok {
+		e.Actor = p.Actor()
+	}
+	return e
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// ForgeResult describes what an ingested webhook delivery changed.
+type ForgeResult string
+
+const (
+	ForgeCreated   ForgeResult = "created"
+	ForgeMerged    ForgeResult = "merged"
+	ForgeClosed    ForgeResult = "closed"
+	ForgeReopened  ForgeResult = "reopened"
+	ForgeUnchanged ForgeResult = "unchanged"
+	ForgeDuplicate ForgeResult = "duplicate"
+	// ForgeBreach means the PR was merged although the merge broke the
+	// team's merge policy; the pr.merged event carries the violation.
+	ForgeBreach ForgeResult = "merged_in_breach"
+)
+
+// ForgeUsecase applies pull request events coming from code hosting
+// platforms through the same PRUsecase operations the API uses.
+type ForgeUsecase struct {
+	prs        *PRUsecase
+	deliveries DeliveryRepo
+	gitlab     GitLabProjectRepo
+	log        *slog.Logger
+}
+
+func NewForgeUsecase(prs *PRUsecase, deliveries DeliveryRepo, gitlab GitLabProjectRepo, logger *slog.Logger) *ForgeUsecase {
+	return &ForgeUsecase{prs: prs, deliveries: deliveries, gitlab: gitlab, log: logger}
+}
+
+// Ingest applies ev at most once per delivery id. A failed delivery is
+// forgotten again so the platform's redelivery can retry it.
+func (u *ForgeUsecase) Ingest(ctx context.Context, ev domain.ForgeEvent) (ForgeResult, error) {
+	if _, ok := domain.PrincipalFromContext(ctx); !ok {
+		// The audit log shows the platform as the actor.
+		ctx = domain.WithPrincipal(ctx, domain.Principal{OrgID: domain.OrgFromContext(ctx), Name: ev.Source})
+	}
+	if ev.DeliveryID != "" {
+		first, err := u.deliveries.ClaimDelivery(ctx, ev.Source, ev.DeliveryID)
+		if err != nil {
+			return "", err
+		}
+		if !first {
+			return ForgeDuplicate, nil
+		}
+	}
+
+	res, err := u.apply(ctx, ev)
+	if err != nil && ev.DeliveryID != "" {
+		if relErr := u.deliveries.ReleaseDelivery(ctx, ev.Source, ev.DeliveryID); relErr != nil {
+			u.log.Error("forge: release delivery failed", "source", ev.Source, "delivery", ev.DeliveryID, "err", relErr)
+		}
+	}
+	return res, err
+}
+
+func (u *ForgeUsecase) apply(ctx context.Context, ev domain.ForgeEvent) (ForgeResult, error) {
+	switch ev.Action {
+	case domain.ForgeOpened:
+		_, err := u.prs.CreatePRInTeam(ctx, ev.PRID, ev.PRName, ev.AuthorID, ev.TeamName)
+		if errors.Is(err, domain.ErrPRExists) {
+			// Reopened, or marked ready again after going back to draft.
+			return u.setClosed(ctx, ev.PRID, false)
+		}
+		if err != nil {
+			return "", fmt.Errorf("create %s: %w", ev.PRID, err)
+		}
+		return ForgeCreated, nil
+
+	case domain.ForgeClosed:
+		return u.setClosed(ctx, ev.PRID, true)
+
+	case domain.ForgeMerged:
+		// The platform has merged already; keeping the PR open here would
+		// only hide that, so a policy violation is recorded instead.
+		_, breach, err := u.prs.RecordMerge(ctx, ev.PRID, ev.MergerID)
+		if err != nil {
+			return "", fmt.Errorf("merge %s: %w", ev.PRID, err)
+		}
+		if breach != "" {
+			u.log.Warn("forge: merge broke the merge policy", "source", ev.Source, "pr", ev.PRID, "merger", ev.MergerID, "reason", breach)
+			return ForgeBreach, nil
+		}
+		return ForgeMerged, nil
+
+	default:
+		return ForgeUnchanged, nil
+	}
+}
+
+// setClosed closes or reopens a PR the platform knows, reporting
+// ForgeUnchanged when it was already in that state or was never created
+// here, like a PR that stayed a draft until it was closed.
+func (u *ForgeUsecase) setClosed(ctx context.Context, prID string, closed bool) (ForgeResult, error) {
+	op, res := u.prs.Reopen, ForgeReopened
+	if closed {
+		op, res = u.prs.Close, ForgeClosed
+	}
+	_, changed, err := op(ctx, prID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return ForgeUnchanged, nil
+	case err != nil:
+		return "", fmt.Errorf("%s %s: %w", res, prID, err)
+	case !changed:
+		return ForgeUnchanged, nil
+	}
+	return res, nil
+}
+
+// MapGitLabProject routes merge requests of projectID to teamName in orgID.
+func (u *ForgeUsecase) MapGitLabProject(ctx context.Context, projectID int64, orgID, teamName string) error {
+	if projectID <= 0 {
+		return errors.New("project id must be positive")
+	}
+	if orgID == "" {
+		orgID = domain.DefaultOrg
+	}
+	return u.gitlab.SetGitLabProject(ctx, domain.GitLabProject{ProjectID: projectID, OrgID: orgID, TeamName: teamName})
+}
+
+func (u *ForgeUsecase) UnmapGitLabProject(ctx context.Context, projectID int64) error {
+	return u.gitlab.DeleteGitLabProject(ctx, projectID)
+}
+
+func (u *ForgeUsecase) ListGitLabProjects(ctx context.Context) ([]domain.GitLabProject, error) {
+	return u.gitlab.ListGitLabProjects(ctx)
+}
+
+// GitLabProject returns the routing of projectID, or domain.ErrNotFound if
+// the project was never mapped.
+func (u *ForgeUsecase) GitLabProject(ctx context.Context, projectID int64) (domain.GitLabProject, error) {
+	return u.gitlab.GetGitLabProject(ctx, projectID)
+}
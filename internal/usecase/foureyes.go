@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// maxReportingDepth bounds the walk up a manager chain so that a cycle or a
+// very deep hierarchy cannot stall a merge.
+const maxReportingDepth = 32
+
+// FourEyesPolicy enforces separation of duties on merges for teams that
+// enable it: the merger must be a user other than the author, and at least
+// one reviewer who is still assigned and outside the author's reporting line
+// must have approved. The team is the author's team.
+type FourEyesPolicy struct {
+	users UserRepo
+	teams TeamRepo
+	prs   PRRepo
+	log   *slog.Logger
+}
+
+func NewFourEyesPolicy(users UserRepo, teams TeamRepo, prs PRRepo, logger *slog.Logger) *FourEyesPolicy {
+	return &FourEyesPolicy{users: users, teams: teams, prs: prs, log: logger}
+}
+
+// CheckMerge logs every decision it makes for a team with the policy on and
+// returns an error wrapping domain.ErrFourEyes for a violation.
+func (p *FourEyesPolicy) CheckMerge(ctx context.Context, pr domain.PullRequest) error {
+	author, err := p.users.GetByID(ctx, pr.AuthorID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	team, _, err := p.teams.GetTeamWithMembers(ctx, author.TeamName)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !team.FourEyes {
+		return nil
+	}
+
+	merger := ""
+	if pl, ok := domain.PrincipalFromContext(ctx); ok {
+		merger = pl.Actor()
+	}
+	attrs := []any{"org", domain.OrgFromContext(ctx), "pr", pr.ID, "team", team.TeamName, "author", pr.AuthorID, "merger", merger}
+
+	reason, approver, err := p.violation(ctx, pr)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		p.log.Warn("four-eyes: merge rejected", append(attrs, "reason", reason)...)
+		return fmt.Errorf("%w: %s", domain.ErrFourEyes, reason)
+	}
+	p.log.Info("four-eyes: merge allowed", append(attrs, "approver", approver)...)
+	return nil
+}
+
+// violation returns why merging pr breaks the policy, or the reviewer whose
+// approval satisfies it.
+func (p *FourEyesPolicy) violation(ctx context.Context, pr domain.PullRequest) (reason, approver string, err error) {
+	pl, ok := domain.PrincipalFromContext(ctx)
+	switch {
+	case !ok || pl.UserID == "":
+		return "the merger must be a user", "", nil
+	case pl.UserID == pr.AuthorID:
+		return "the author cannot merge their own pull request", "", nil
+	}
+
+	reviews, err := p.prs.ListReviews(ctx, pr.ID)
+	if err != nil {
+		return "", "", err
+	}
+	for _, r := range reviews {
+		// Verdicts of replaced reviewers are kept for the history but no
+		// longer count.
+		if r.Verdict != domain.VerdictApproved || r.ReviewerID == pr.AuthorID || !slices.Contains(pr.AssignedReviewers, r.ReviewerID) {
+			continue
+		}
+		related, err := p.reportingLine(ctx, pr.AuthorID, r.ReviewerID)
+		if err != nil {
+			return "", "", err
+		}
+		if !related {
+			return "", r.ReviewerID, nil
+		}
+	}
+	return "no approval from a reviewer outside the author's reporting line", "", nil
+}
+
+// reportingLine reports whether one of a and b manages the other, directly
+// or through a chain of managers.
+func (p *FourEyesPolicy) reportingLine(ctx context.Context, a, b string) (bool, error) {
+	up, err := p.reportsTo(ctx, a, b)
+	if err != nil || up {
+		return up, err
+	}
+	return p.reportsTo(ctx, b, a)
+}
+
+// reportsTo reports whether target appears in the manager chain of userID.
+func (p *FourEyesPolicy) reportsTo(ctx context.Context, userID, target string) (bool, error) {
+	seen := map[string]bool{userID: true}
+	for range maxReportingDepth {
+		u, err := p.users.GetByID(ctx, userID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case u.ManagerID == "":
+			return false, nil
+		case u.ManagerID == target:
+			return true, nil
+		case seen[u.ManagerID]:
+			return false, nil
+		}
+		seen[u.ManagerID] = true
+		userID = u.ManagerID
+	}
+	return false, nil
+}
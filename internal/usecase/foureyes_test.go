@@ -0,0 +1,152 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+func TestFourEyesMergePolicy(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStore()
+	teams, users, prs := memory.NewTeamRepo(s), memory.NewUserRepo(s), memory.NewPRRepo(s)
+	if err := teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	// u2 manages u1, who manages u3: both possible reviewers are in the
+	// author's reporting line.
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{
+		{UserID: "u1", Username: "alice", IsActive: true, ManagerID: "u2"},
+		{UserID: "u2", Username: "bob", IsActive: true},
+		{UserID: "u3", Username: "carol", IsActive: true, ManagerID: "u1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var logs bytes.Buffer
+	uc := usecase.NewPRUsecase(users, prs)
+	uc.SetMergePolicy(usecase.NewFourEyesPolicy(users, teams, prs, slog.New(slog.NewTextHandler(&logs, nil))))
+	teamUC := usecase.NewTeamUsecase(teams, users)
+	as := func(id string) context.Context {
+		return domain.WithPrincipal(ctx, domain.Principal{UserID: id})
+	}
+
+	if _, err := uc.CreatePR(ctx, "pr-1", "Fix login", "u1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.CreatePR(ctx, "pr-2", "Fix logout", "u1"); err != nil {
+		t.Fatal(err)
+	}
+	// Without the policy the author may merge an unreviewed PR.
+	if _, err := uc.Merge(as("u1"), "pr-2", 0); err != nil {
+		t.Fatalf("policy off: %v", err)
+	}
+
+	token := func(scopes ...string) context.Context {
+		return domain.WithPrincipal(ctx, domain.Principal{TokenID: "t1", Scopes: scopes})
+	}
+	for name, sctx := range map[string]context.Context{
+		"member": as("u3"),
+		"token":  token(domain.ScopeTeamsWrite, domain.ScopePRsWrite),
+	} {
+		if _, err := teamUC.SetFourEyes(sctx, "backend", true); !errors.Is(err, domain.ErrForbidden) {
+			t.Fatalf("%s sets policy: got %v, want %v", name, err, domain.ErrForbidden)
+		}
+	}
+	team, err := teamUC.SetFourEyes(token(domain.ScopeAdmin), "backend", true)
+	if err != nil || !team.FourEyes || len(team.Members) != 3 {
+		t.Fatalf("set policy: got %+v, %v", team, err)
+	}
+
+	if _, err := uc.Review(as("u2"), "pr-1", "u3", domain.VerdictApproved, 0); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("review for someone else: got %v, want %v", err, domain.ErrForbidden)
+	}
+	if _, err := uc.Review(token(domain.ScopePRsWrite), "pr-1", "u3", domain.VerdictApproved, 0); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("token reviews: got %v, want %v", err, domain.ErrForbidden)
+	}
+	if _, err := uc.Review(as("u1"), "pr-1", "u1", domain.VerdictApproved, 0); !errors.Is(err, domain.ErrNotAssigned) {
+		t.Fatalf("author reviews: got %v, want %v", err, domain.ErrNotAssigned)
+	}
+	if _, err := uc.Review(as("u2"), "pr-1", "u2", "lgtm", 0); !errors.Is(err, domain.ErrInvalid) {
+		t.Fatalf("unknown verdict: got %v, want %v", err, domain.ErrInvalid)
+	}
+	for _, id := range []string{"u2", "u3"} {
+		if _, err := uc.Review(as(id), "pr-1", id, domain.VerdictApproved, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, mctx := range map[string]context.Context{
+		"author":           as("u1"),
+		"token":            domain.WithPrincipal(ctx, domain.Principal{TokenID: "t1"}),
+		"reporting line":   as("u2"),
+		"no principal":     ctx,
+		"reporting line 2": as("u3"),
+	} {
+		if _, err := uc.Merge(mctx, "pr-1", 0); !errors.Is(err, domain.ErrFourEyes) {
+			t.Fatalf("%s: got %v, want %v", name, err, domain.ErrFourEyes)
+		}
+	}
+
+	// u4 is outside the reporting line and takes over from u3.
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{{UserID: "u4", Username: "dave", IsActive: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, next, err := uc.Reassign(as("u3"), "pr-1", "u3", "", 0); err != nil || next != "u4" {
+		t.Fatalf("reassign: got %q, %v", next, err)
+	}
+	if _, err := uc.Review(as("u4"), "pr-1", "u4", domain.VerdictChangesRequested, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.Merge(as("u2"), "pr-1", 0); !errors.Is(err, domain.ErrFourEyes) {
+		t.Fatalf("changes requested: got %v, want %v", err, domain.ErrFourEyes)
+	}
+	if _, err := uc.Review(as("u4"), "pr-1", "u4", domain.VerdictApproved, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.Merge(as("u1"), "pr-1", 0); !errors.Is(err, domain.ErrFourEyes) {
+		t.Fatalf("author merges approved PR: got %v, want %v", err, domain.ErrFourEyes)
+	}
+	// Once u4 is replaced, their approval no longer counts; it does again
+	// when they are assigned back.
+	if _, next, err := uc.Reassign(as("u4"), "pr-1", "u4", "", 0); err != nil || next != "u3" {
+		t.Fatalf("reassign: got %q, %v", next, err)
+	}
+	if _, err := uc.Merge(as("u2"), "pr-1", 0); !errors.Is(err, domain.ErrFourEyes) {
+		t.Fatalf("approval of a replaced reviewer: got %v, want %v", err, domain.ErrFourEyes)
+	}
+	if _, next, err := uc.Reassign(as("u3"), "pr-1", "u3", "", 0); err != nil || next != "u4" {
+		t.Fatalf("reassign back: got %q, %v", next, err)
+	}
+	merged, err := uc.Merge(as("u2"), "pr-1", 0)
+	if err != nil || merged.Status != domain.StatusMerged {
+		t.Fatalf("approved merge: got %+v, %v", merged, err)
+	}
+
+	// A platform merge cannot be refused, so the violation is recorded.
+	if _, err := uc.CreatePR(ctx, "pr-3", "Fix signup", "u1"); err != nil {
+		t.Fatal(err)
+	}
+	merged, breach, err := uc.RecordMerge(ctx, "pr-3", "")
+	if err != nil || merged.Status != domain.StatusMerged || !strings.Contains(breach, "the merger must be a user") {
+		t.Fatalf("platform merge: got %+v, %q, %v", merged, breach, err)
+	}
+	events, err := prs.ListEvents(ctx, "pr-3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := events[len(events)-1]; last.Type != domain.EventPRMerged || last.Data.Reason != breach {
+		t.Fatalf("merge event: got %+v", last)
+	}
+
+	out := logs.String()
+	if !strings.Contains(out, "four-eyes: merge rejected") || !strings.Contains(out, "four-eyes: merge allowed") || !strings.Contains(out, "approver=u4") {
+		t.Errorf("decisions not logged:\n%s", out)
+	}
+}
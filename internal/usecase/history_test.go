@@ -0,0 +1,87 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+func TestHistoryKeepsEveryChange(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStore()
+	teams, users, prs := memory.NewTeamRepo(s), memory.NewUserRepo(s), memory.NewPRRepo(s)
+	if err := teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{
+		{UserID: "u1", Username: "alice", IsActive: true},
+		{UserID: "u2", Username: "bob", IsActive: true},
+		{UserID: "u3", Username: "carol", IsActive: true},
+		{UserID: "u4", Username: "dave", IsActive: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	uc := usecase.NewPRUsecase(users, prs)
+	as := func(id string) context.Context {
+		return domain.WithPrincipal(ctx, domain.Principal{UserID: id})
+	}
+
+	created, err := uc.CreatePR(as("u1"), "pr-1", "Fix login", "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := created.AssignedReviewers[0]
+	_, second, err := uc.Reassign(as(first), "pr-1", first, " on vacation ", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reassigned, _, err := uc.Reassign(as(second), "pr-1", second, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reviewer := reassigned.AssignedReviewers[0]
+	if _, err := uc.Review(as(reviewer), "pr-1", reviewer, domain.VerdictChangesRequested); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.Merge(as("u1"), "pr-1", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := uc.History(ctx, "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := make([]domain.EventType, 0, len(got))
+	for _, e := range got {
+		types = append(types, e.Type)
+	}
+	want := []domain.EventType{domain.EventPRCreated, domain.EventPRReassigned, domain.EventPRReassigned, domain.EventPRReviewed, domain.EventPRMerged}
+	if !slices.Equal(types, want) {
+		t.Fatalf("types: got %v, want %v", types, want)
+	}
+	if !slices.Equal(got[0].Data.PullRequest.AssignedReviewers, created.AssignedReviewers) {
+		t.Errorf("initial reviewers: got %v, want %v", got[0].Data.PullRequest.AssignedReviewers, created.AssignedReviewers)
+	}
+	if r := got[1]; r.Actor != first || r.Data.OldReviewerID != first || r.Data.NewReviewerID != second || r.Data.Reason != "on vacation" {
+		t.Errorf("first reassignment: %+v", r)
+	}
+	if r := got[2]; r.Actor != second || r.Data.OldReviewerID != second || r.Data.Reason != "" {
+		t.Errorf("second reassignment: %+v", r)
+	}
+	if r := got[3]; r.Actor != reviewer || r.Data.Review == nil ||
+		*r.Data.Review != (domain.EventReview{ReviewerID: reviewer, Verdict: domain.VerdictChangesRequested}) {
+		t.Errorf("verdict: %+v", r)
+	}
+	if got[4].Actor != "u1" || got[4].Data.PullRequest.Status != domain.StatusMerged {
+		t.Errorf("merge: %+v", got[4])
+	}
+
+	if _, err := uc.History(ctx, "pr-9"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown PR: got %v, want %v", err, domain.ErrNotFound)
+	}
+}
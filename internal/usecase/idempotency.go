@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// IdempotencyUsecase lets clients retry a request with the same
+// Idempotency-Key and get the original response instead of running it
+// again. Keys belong to the calling principal in its organization.
+type IdempotencyUsecase struct {
+	repo  IdempotencyRepo
+	ttl   time.Duration
+	lease time.Duration
+}
+
+// NewIdempotencyUsecase keeps responses for ttl. A request that has held
+// its key for longer than lease is taken to have crashed, and a retry takes
+// the key over.
+func NewIdempotencyUsecase(repo IdempotencyRepo, ttl, lease time.Duration) *IdempotencyUsecase {
+	return &IdempotencyUsecase{repo: repo, ttl: ttl, lease: lease}
+}
+
+// Begin reserves key for a request with requestHash. Unless replay is set,
+// the caller runs the request and then completes or releases rec;
+// otherwise rec holds the response to replay. A key used with another
+// request fails with domain.ErrIdempotencyKeyReused, a key whose first
+// request is still running with domain.ErrIdempotencyInProgress.
+func (u *IdempotencyUsecase) Begin(ctx context.Context, key, requestHash string) (rec domain.IdempotencyRecord, replay bool, err error) {
+	lease, err := randomString(16, hex.EncodeToString)
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	now := time.Now()
+	got, fresh, err := u.repo.ReserveIdempotencyKey(ctx, domain.IdempotencyRecord{
+		Owner:       idempotencyOwner(ctx),
+		Key:         key,
+		Lease:       lease,
+		RequestHash: requestHash,
+	}, now.Add(-u.ttl), now.Add(-u.lease))
+	switch {
+	case err != nil:
+		return domain.IdempotencyRecord{}, false, err
+	case fresh:
+		return got, false, nil
+	case got.RequestHash != requestHash:
+		return domain.IdempotencyRecord{}, false, domain.ErrIdempotencyKeyReused
+	case !got.Completed():
+		return domain.IdempotencyRecord{}, false, domain.ErrIdempotencyInProgress
+	default:
+		return got, true, nil
+	}
+}
+
+// Complete stores the response in rec to replay for its key.
+func (u *IdempotencyUsecase) Complete(ctx context.Context, rec domain.IdempotencyRecord) error {
+	if rec.Status == 0 {
+		return errors.New("idempotency: response status is required")
+	}
+	return u.repo.CompleteIdempotencyKey(ctx, rec)
+}
+
+// Release forgets the key of rec, for requests that failed in a way a retry
+// may fix.
+func (u *IdempotencyUsecase) Release(ctx context.Context, rec domain.IdempotencyRecord) error {
+	return u.repo.ReleaseIdempotencyKey(ctx, rec.Owner, rec.Key, rec.Lease)
+}
+
+func idempotencyOwner(ctx context.Context) string {
+	if p, ok := domain.PrincipalFromContext(ctx); ok {
+		return p.Actor()
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+func TestIdempotencyReplaysPerCaller(t *testing.T) {
+	uc := usecase.NewIdempotencyUsecase(memory.NewIdempotencyRepo(memory.NewStore()), time.Hour, time.Hour)
+	ci := domain.WithPrincipal(context.Background(), domain.Principal{TokenID: "ci"})
+	bot := domain.WithPrincipal(context.Background(), domain.Principal{TokenID: "bot"})
+
+	rec, replay, err := uc.Begin(ci, "k1", "create pr-1")
+	if err != nil || replay {
+		t.Fatalf("first request: got %+v, %v, %v", rec, replay, err)
+	}
+	if _, _, err := uc.Begin(ci, "k1", "create pr-1"); !errors.Is(err, domain.ErrIdempotencyInProgress) {
+		t.Fatalf("concurrent retry: got %v, want %v", err, domain.ErrIdempotencyInProgress)
+	}
+	rec.Status, rec.ContentType, rec.Body = 201, "application/json", []byte(`{"pr":{}}`)
+	if err := uc.Complete(ci, rec); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, replay, err = uc.Begin(ci, "k1", "create pr-1")
+	if err != nil || !replay || rec.Status != 201 || string(rec.Body) != `{"pr":{}}` {
+		t.Fatalf("retry: got %+v, %v, %v", rec, replay, err)
+	}
+	if _, _, err := uc.Begin(ci, "k1", "create pr-2"); !errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		t.Fatalf("other body: got %v, want %v", err, domain.ErrIdempotencyKeyReused)
+	}
+	rec, replay, err = uc.Begin(bot, "k1", "create pr-2")
+	if err != nil || replay {
+		t.Fatalf("other caller: got %+v, %v, %v", rec, replay, err)
+	}
+
+	// A released key runs again.
+	if err := uc.Release(bot, rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec, replay, err := uc.Begin(bot, "k1", "create pr-3"); err != nil || replay {
+		t.Fatalf("after release: got %+v, %v, %v", rec, replay, err)
+	}
+}
+
+func TestIdempotencyTakesOverAbandonedKey(t *testing.T) {
+	uc := usecase.NewIdempotencyUsecase(memory.NewIdempotencyRepo(memory.NewStore()), time.Hour, 0)
+	ctx := domain.WithPrincipal(context.Background(), domain.Principal{TokenID: "ci"})
+
+	crashed, _, err := uc.Begin(ctx, "k1", "create pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// With no lease to wait out, a retry of the same request takes the key
+	// over; one with another body still may not.
+	if _, _, err := uc.Begin(ctx, "k1", "create pr-2"); !errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		t.Fatalf("other body: got %v, want %v", err, domain.ErrIdempotencyKeyReused)
+	}
+	retry, replay, err := uc.Begin(ctx, "k1", "create pr-1")
+	if err != nil || replay || retry.Lease == crashed.Lease {
+		t.Fatalf("takeover: got %+v, %v, %v", retry, replay, err)
+	}
+
+	// The first request comes back late: it no longer holds the key.
+	crashed.Status, crashed.Body = 500, []byte("late")
+	if err := uc.Complete(ctx, crashed); err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.Release(ctx, crashed); err != nil {
+		t.Fatal(err)
+	}
+	retry.Status, retry.Body = 201, []byte("ok")
+	if err := uc.Complete(ctx, retry); err != nil {
+		t.Fatal(err)
+	}
+	got, replay, err := uc.Begin(ctx, "k1", "create pr-1")
+	if err != nil || !replay || string(got.Body) != "ok" {
+		t.Fatalf("replay: got %+v, %v, %v", got, replay, err)
+	}
+}
@@ -2,28 +2,244 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/beachrockhotel/pr-reviewer/internal/domain"
 )
 
+// TeamRepo, UserRepo and PRRepo record every change they make in the audit
+// log, in the same transaction, with the actor and request id from ctx.
+// Calls that change nothing are not recorded.
 type TeamRepo interface {
 	CreateTeam(ctx context.Context, teamName string) error
 	GetTeamWithMembers(ctx context.Context, teamName string) (domain.Team, []domain.User, error)
 	UpsertUsersToTeam(ctx context.Context, teamName string, users []domain.User) error
+	// SetFourEyes turns the four-eyes merge policy of the team on or off.
+	SetFourEyes(ctx context.Context, teamName string, on bool) (domain.Team, error)
+	// ListTeams returns the teams ordered by name, without members.
+	ListTeams(ctx context.Context) ([]domain.Team, error)
+	// ImportTeams creates the missing teams and upserts their members in a
+	// single transaction, leaving users it would not change untouched. A dry
+	// run rolls the transaction back, so its report is what an import would
+	// do at that moment.
+	ImportTeams(ctx context.Context, teams []domain.Team, dryRun bool) (domain.ImportReport, error)
+	// DeleteTeam moves the members of the team to moveTo, which must exist,
+	// and deletes the team with its Slack channel and GitLab projects, in a
+	// single transaction.
+	DeleteTeam(ctx context.Context, teamName, moveTo string) error
 }
 
 type UserRepo interface {
 	GetByID(ctx context.Context, userID string) (domain.User, error)
-	SetActive(ctx context.Context, userID string, isActive bool) (domain.User, error)
+	// SetActive stores the flag. If e is not nil and the flag changes, e is
+	// completed with the stored user and written to the outbox in the same
+	// transaction.
+	SetActive(ctx context.Context, userID string, isActive bool, e *domain.Event) (domain.User, error)
+	// SetAway marks the user absent until the given time; nil clears it.
+	SetAway(ctx context.Context, userID string, until *time.Time) (domain.User, error)
 	ListActiveInTeamExcept(ctx context.Context, teamName string, excludeIDs []string, limit int) ([]domain.User, error)
 }
 
+// PRRepo writes an outbox event together with a change: when e is not nil
+// and the call changes the pull request, e.Data.PullRequest is set to the
+// stored PR and e is written to the outbox in the same transaction.
 type PRRepo interface {
-	CreatePRWithReviewers(ctx context.Context, pr domain.PullRequest, reviewers []string) (domain.PullRequest, error)
+	CreatePRWithReviewers(ctx context.Context, pr domain.PullRequest, reviewers []string, e *domain.Event) (domain.PullRequest, error)
 	GetByIDForUpdate(ctx context.Context, prID string) (domain.PullRequest, error)
 	GetAssignedReviewers(ctx context.Context, prID string) ([]string, error)
-	ReplaceReviewer(ctx context.Context, prID, oldID, newID string) (domain.PullRequest, error)
-	SetMerged(ctx context.Context, prID string) (domain.PullRequest, error)
+	// ReplaceReviewer, SetMerged and SetReview fail with
+	// domain.ErrVersionMismatch unless the PR is at version; zero skips the
+	// check. A call that changes the PR increments its version.
+	// ReplaceReviewer also checks domain.PullRequest.CanReplaceReviewer
+	// while it holds the PR. SetMerged fails with domain.ErrPRClosed on a
+	// closed PR and SetReview with domain.PullRequest.CheckOpen.
+	ReplaceReviewer(ctx context.Context, prID, oldID, newID string, version int64, e *domain.Event) (domain.PullRequest, error)
+	SetMerged(ctx context.Context, prID string, version int64, e *domain.Event) (domain.PullRequest, error)
+	// SetClosed closes an open PR or, with closed false, reopens a closed
+	// one. Merged PRs and PRs already in that state stay as they are.
+	SetClosed(ctx context.Context, prID string, closed bool, e *domain.Event) (domain.PullRequest, error)
+	// SetReview stores r as the reviewer's latest verdict on the PR.
+	SetReview(ctx context.Context, prID string, r domain.Review, version int64, e *domain.Event) (domain.PullRequest, error)
+	// ListReviews returns the latest verdict of every reviewer, ordered by
+	// reviewer id.
+	ListReviews(ctx context.Context, prID string) ([]domain.Review, error)
 	ListByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequestShort, error)
+	// ListEvents returns the events written for the PR, oldest first.
+	ListEvents(ctx context.Context, prID string) ([]domain.Event, error)
 	StatsByStatus(ctx context.Context) (map[domain.PRStatus]int, error)
 }
+
+type TokenRepo interface {
+	CreateToken(ctx context.Context, token domain.APIToken, hash string) (domain.APIToken, error)
+	GetTokenByHash(ctx context.Context, hash string) (domain.APIToken, error)
+	RevokeToken(ctx context.Context, id string) error
+	ListTokens(ctx context.Context) ([]domain.APIToken, error)
+}
+
+type OrgRepo interface {
+	CreateOrg(ctx context.Context, org domain.Organization) (domain.Organization, error)
+	GetOrg(ctx context.Context, orgID string) (domain.Organization, error)
+	ListOrgs(ctx context.Context) ([]domain.Organization, error)
+}
+
+// AuditRepo reads the audit log of the organization in ctx.
+type AuditRepo interface {
+	// ListAudit returns up to f.Limit matching entries, newest first.
+	ListAudit(ctx context.Context, f domain.AuditFilter) ([]domain.AuditEntry, error)
+	// ListAuditChain returns up to limit entries after afterID, oldest
+	// first, for walking the hash chain.
+	ListAuditChain(ctx context.Context, afterID int64, limit int) ([]domain.AuditEntry, error)
+}
+
+// DeliveryRepo remembers processed webhook deliveries so that redeliveries
+// are not applied twice.
+type DeliveryRepo interface {
+	// ClaimDelivery records the delivery and reports false if it was already
+	// recorded.
+	ClaimDelivery(ctx context.Context, source, deliveryID string) (bool, error)
+	ReleaseDelivery(ctx context.Context, source, deliveryID string) error
+}
+
+// IdempotencyRepo stores requests sent with an Idempotency-Key and their
+// responses. Keys are scoped to the organization in ctx and to the caller.
+type IdempotencyRepo interface {
+	// ReserveIdempotencyKey stores rec as in progress and reports true,
+	// unless a record created after expiredBefore exists for its owner and
+	// key: that one is returned with false. Records created before
+	// expiredBefore are dropped. An in-progress record of the same request
+	// created before staleBefore has been abandoned: rec takes it over and
+	// true is reported.
+	ReserveIdempotencyKey(ctx context.Context, rec domain.IdempotencyRecord, expiredBefore, staleBefore time.Time) (domain.IdempotencyRecord, bool, error)
+	// CompleteIdempotencyKey stores the response in rec, provided rec.Lease
+	// still holds the key.
+	CompleteIdempotencyKey(ctx context.Context, rec domain.IdempotencyRecord) error
+	// ReleaseIdempotencyKey forgets a key so that the request can be
+	// retried, provided lease still holds it.
+	ReleaseIdempotencyKey(ctx context.Context, owner, key, lease string) error
+}
+
+// SlackRepo stores the Slack settings of the organization in ctx.
+type SlackRepo interface {
+	// SetSlackChannel creates or replaces the team's channel; the team must
+	// exist.
+	SetSlackChannel(ctx context.Context, c domain.SlackChannel) error
+	GetSlackChannel(ctx context.Context, teamName string) (domain.SlackChannel, error)
+	DeleteSlackChannel(ctx context.Context, teamName string) error
+	ListSlackChannels(ctx context.Context) ([]domain.SlackChannel, error)
+	// SetSlackUser creates or replaces the mapping; the user must exist.
+	SetSlackUser(ctx context.Context, u domain.SlackUser) error
+	DeleteSlackUser(ctx context.Context, userID string) error
+	ListSlackUsers(ctx context.Context) ([]domain.SlackUser, error)
+}
+
+// EmailRepo stores email preferences of the organization in ctx and queues
+// notifications, which the sender works through across organizations.
+type EmailRepo interface {
+	// GetEmailPrefs returns domain.ErrNotFound if the user never set any.
+	GetEmailPrefs(ctx context.Context, userID string) (domain.EmailPrefs, error)
+	// SetEmailPrefs creates or replaces the preferences; the user must exist.
+	SetEmailPrefs(ctx context.Context, p domain.EmailPrefs) error
+	// EnqueueEmail queues n for its user in the organization in ctx. An
+	// EmailSLABreach for a user and PR that was queued before is ignored.
+	EnqueueEmail(ctx context.Context, n domain.EmailNotification) error
+	// ClaimEmails leases, until leaseUntil, every pending notification of up
+	// to limit users whose oldest pending one was queued at or before
+	// queuedBefore and none of which is leased or backing off at now.
+	ClaimEmails(ctx context.Context, queuedBefore, now, leaseUntil time.Time, limit int) ([]domain.EmailNotification, error)
+	SaveEmail(ctx context.Context, n domain.EmailNotification) error
+	ListEmails(ctx context.Context, status domain.EmailStatus) ([]domain.EmailNotification, error)
+	// ListSLABreaches returns, across organizations, an EmailSLABreach for
+	// every reviewer of an open PR created at or before createdBefore who
+	// has an email address, wants the notification and was not queued one
+	// for the PR yet. PR carries the id, name, author and creation time.
+	ListSLABreaches(ctx context.Context, createdBefore time.Time, limit int) ([]domain.EmailNotification, error)
+}
+
+// DigestRepo finds the recipients of daily review digests across
+// organizations and records who got one on which day.
+type DigestRepo interface {
+	// ListDigestRecipients returns, with OrgID and To set, every active user
+	// with an email address who reviews an open PR and did not turn the
+	// digest off.
+	ListDigestRecipients(ctx context.Context) ([]domain.ReviewDigest, error)
+	// ClaimDigest records the digest of the user in the organization in ctx
+	// for day and reports false if it was recorded before.
+	ClaimDigest(ctx context.Context, userID, day string) (bool, error)
+	// ReleaseDigest undoes a claim whose digest could not be sent.
+	ReleaseDigest(ctx context.Context, userID, day string) error
+}
+
+// GitLabProjectRepo stores project routing. Projects are looked up across
+// organizations because a webhook arrives before the tenant is known.
+type GitLabProjectRepo interface {
+	// SetGitLabProject creates or replaces the mapping; the team must exist.
+	SetGitLabProject(ctx context.Context, p domain.GitLabProject) error
+	GetGitLabProject(ctx context.Context, projectID int64) (domain.GitLabProject, error)
+	DeleteGitLabProject(ctx context.Context, projectID int64) error
+	ListGitLabProjects(ctx context.Context) ([]domain.GitLabProject, error)
+}
+
+// ReviewerSyncRepo is the queue of reviewer changes to push to the code
+// hosting platform. Apart from EnqueueReviewerSync it works across
+// organizations because the push worker serves all of them.
+type ReviewerSyncRepo interface {
+	EnqueueReviewerSync(ctx context.Context, s domain.ReviewerSync) (domain.ReviewerSync, error)
+	// ClaimReviewerSyncs returns up to limit pending syncs due at now, oldest
+	// first and at most one per PR, and hides them from other claims until
+	// leaseUntil.
+	ClaimReviewerSyncs(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.ReviewerSync, error)
+	GetReviewerSync(ctx context.Context, id int64) (domain.ReviewerSync, error)
+	// SaveReviewerSync stores Status, Attempts, LastError and NextAttemptAt.
+	SaveReviewerSync(ctx context.Context, s domain.ReviewerSync) error
+	// ListReviewerSyncs lists syncs with the given status, all if it is empty.
+	ListReviewerSyncs(ctx context.Context, status domain.SyncStatus) ([]domain.ReviewerSync, error)
+}
+
+// SubscriptionRepo stores the webhook subscriptions of the organization in
+// the context.
+type SubscriptionRepo interface {
+	CreateSubscription(ctx context.Context, s domain.Subscription) (domain.Subscription, error)
+	GetSubscription(ctx context.Context, id string) (domain.Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]domain.Subscription, error)
+	// DeleteSubscription also deletes the subscription's deliveries.
+	DeleteSubscription(ctx context.Context, id string) error
+}
+
+// EventDeliveryRepo is the delivery log of subscriptions. Claim and Save
+// work across organizations for the delivery worker, the rest is scoped to
+// the organization in the context.
+type EventDeliveryRepo interface {
+	EnqueueEventDelivery(ctx context.Context, d domain.EventDelivery) (domain.EventDelivery, error)
+	// ClaimEventDeliveries returns up to limit pending deliveries due at now,
+	// oldest first, and hides them from other claims until leaseUntil.
+	ClaimEventDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.EventDelivery, error)
+	GetEventDelivery(ctx context.Context, id int64) (domain.EventDelivery, error)
+	// SaveEventDelivery stores Status, Attempts, ResponseCode, LastError and
+	// NextAttemptAt.
+	SaveEventDelivery(ctx context.Context, d domain.EventDelivery) error
+	// ListEventDeliveries returns the latest deliveries of a subscription,
+	// newest first.
+	ListEventDeliveries(ctx context.Context, subscriptionID string, limit int) ([]domain.EventDelivery, error)
+}
+
+// OutboxRepo reads the events written by PRRepo and UserRepo. It works
+// across organizations because the relay serves all of them.
+type OutboxRepo interface {
+	// ClaimOutbox returns up to limit pending messages due at now, oldest
+	// first and at most one per key, and hides them from other claims until
+	// leaseUntil.
+	ClaimOutbox(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxMessage, error)
+	GetOutbox(ctx context.Context, id int64) (domain.OutboxMessage, error)
+	// SaveOutbox stores Status, Attempts, LastError and NextAttemptAt.
+	SaveOutbox(ctx context.Context, m domain.OutboxMessage) error
+	// ListOutbox lists messages with the given status, all if it is empty.
+	ListOutbox(ctx context.Context, status domain.OutboxStatus) ([]domain.OutboxMessage, error)
+	// ListOutboxAfter returns up to limit messages of the organization in
+	// ctx with an ID above afterID, in ID order, whatever their status. IDs
+	// of one organization are assigned in commit order, so a reader that
+	// remembers the last ID it saw misses nothing.
+	ListOutboxAfter(ctx context.Context, afterID int64, limit int) ([]domain.OutboxMessage, error)
+	// LastOutboxID returns the highest message ID of the organization in
+	// ctx, 0 if there is none.
+	LastOutboxID(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+type OrgUsecase struct {
+	orgs OrgRepo
+}
+
+func NewOrgUsecase(orgs OrgRepo) *OrgUsecase {
+	return &OrgUsecase{orgs: orgs}
+}
+
+func (u *OrgUsecase) Create(ctx context.Context, orgID, name string) (domain.Organization, error) {
+	if orgID == "" {
+		return domain.Organization{}, errors.New("organization id is required")
+	}
+	if name == "" {
+		name = orgID
+	}
+	return u.orgs.CreateOrg(ctx, domain.Organization{OrgID: orgID, Name: name})
+}
+
+func (u *OrgUsecase) List(ctx context.Context) ([]domain.Organization, error) {
+	return u.orgs.ListOrgs(ctx)
+}
+
+// Resolve picks the tenant of a request: the organization the credential is
+// bound to. Only cross-org admin tokens may request another one; credentials
+// bound to none, such as a JWT without the organization claim, are refused.
+func (u *OrgUsecase) Resolve(ctx context.Context, p domain.Principal, requested string) (string, error) {
+	org := p.OrgID
+	switch {
+	case p.CrossOrg && p.HasScope(domain.ScopeAdmin):
+		if requested != "" {
+			org = requested
+		}
+	case org == "":
+		return "", fmt.Errorf("%w: credential is not bound to an organization", domain.ErrForbidden)
+	case requested != "" && requested != org:
+		return "", fmt.Errorf("%w: credential is bound to organization %q", domain.ErrForbidden, org)
+	}
+
+	if _, err := u.orgs.GetOrg(ctx, org); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", fmt.Errorf("%w: unknown organization %q", domain.ErrForbidden, org)
+		}
+		return "", err
+	}
+	return org, nil
+}
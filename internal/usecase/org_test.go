@@ -0,0 +1,51 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+func TestOrgResolve(t *testing.T) {
+	ctx := context.Background()
+	uc := usecase.NewOrgUsecase(memory.NewOrgRepo(memory.NewStore()))
+	if _, err := uc.Create(ctx, "acme", ""); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	bound := domain.Principal{OrgID: "acme"}
+	crossOrg := domain.Principal{OrgID: domain.DefaultOrg, Scopes: []string{domain.ScopeAdmin}, CrossOrg: true}
+	cases := []struct {
+		name      string
+		p         domain.Principal
+		requested string
+		want      string
+		wantErr   error
+	}{
+		{"unbound without header", domain.Principal{UserID: "u1"}, "", "", domain.ErrForbidden},
+		{"unbound with header", domain.Principal{UserID: "u1"}, "acme", "", domain.ErrForbidden},
+		{"cross-org without header", crossOrg, "", domain.DefaultOrg, nil},
+		{"cross-org with header", crossOrg, "acme", "acme", nil},
+		{"cross-org unknown org", crossOrg, "nope", "", domain.ErrForbidden},
+		{"cross-org without admin", domain.Principal{OrgID: "acme", Scopes: []string{domain.ScopeRead}, CrossOrg: true}, domain.DefaultOrg, "", domain.ErrForbidden},
+		{"bound without header", bound, "", "acme", nil},
+		{"bound matching header", bound, "acme", "acme", nil},
+		{"bound other header", bound, domain.DefaultOrg, "", domain.ErrForbidden},
+		{"bound to deleted org", domain.Principal{OrgID: "gone"}, "", "", domain.ErrForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := uc.Resolve(ctx, tc.p, tc.requested)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err: got %v, want %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Fatalf("org: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// EventSink receives the events relayed from the outbox. Publish runs with
+// the event's organization in ctx. A sink that fails on an event gets it
+// again on the next attempt; the sinks that took it do not.
+type EventSink interface {
+	Publish(ctx context.Context, e domain.Event) error
+}
+
+type namedSink struct {
+	name string
+	sink EventSink
+}
+
+// OutboxRelay publishes the events that PRRepo and UserRepo write to the
+// outbox. Every event reaches every sink at least once, and events of one
+// pull request or user are published in the order they were written.
+// Progress is kept per sink, so one failing sink does not repeat an event
+// on the others.
+type OutboxRelay struct {
+	outbox OutboxRepo
+	sinks  []namedSink
+	cfg    RetryConfig
+	log    *slog.Logger
+	now    func() time.Time
+}
+
+func NewOutboxRelay(outbox OutboxRepo, cfg RetryConfig, logger *slog.Logger) *OutboxRelay {
+	return &OutboxRelay{outbox: outbox, cfg: cfg.withDefaults(), log: logger, now: time.Now}
+}
+
+// AddSink must be called before the relay runs. The name appears in errors
+// and logs.
+func (u *OutboxRelay) AddSink(name string, s EventSink) {
+	u.sinks = append(u.sinks, namedSink{name: name, sink: s})
+}
+
+// RunOnce publishes one batch of due messages and returns how many were
+// tried.
+func (u *OutboxRelay) RunOnce(ctx context.Context) (int, error) {
+	now := u.now().UTC()
+	batch, err := u.outbox.ClaimOutbox(ctx, now, now.Add(u.cfg.Lease), 20)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, m := range batch {
+		pubErr := u.publish(ctx, &m)
+		m.Attempts++
+		switch {
+		case pubErr == nil:
+			m.Status, m.LastError = domain.OutboxPublished, ""
+		case m.Attempts >= u.cfg.MaxAttempts:
+			m.Status, m.LastError = domain.OutboxFailed, pubErr.Error()
+		default:
+			m.LastError = pubErr.Error()
+			m.NextAttemptAt = u.now().UTC().Add(u.cfg.backoff(m.Attempts))
+		}
+		if pubErr != nil {
+			u.log.Warn("outbox: publish failed",
+				"id", m.ID, "event", m.Event.ID, "attempt", m.Attempts, "status", m.Status, "err", pubErr)
+		}
+		if err := u.outbox.SaveOutbox(ctx, m); err != nil {
+			return 0, fmt.Errorf("save outbox message %d: %w", m.ID, err)
+		}
+	}
+	return len(batch), nil
+}
+
+// publish hands m to the sinks it has not reached yet and adds the ones
+// that take it to m.PublishedTo.
+func (u *OutboxRelay) publish(ctx context.Context, m *domain.OutboxMessage) error {
+	ctx = domain.WithOrg(ctx, m.Event.OrgID)
+	var errs []error
+	for _, s := range u.sinks {
+		if slices.Contains(m.PublishedTo, s.name) {
+			continue
+		}
+		if err := s.sink.Publish(ctx, m.Event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		m.PublishedTo = append(m.PublishedTo, s.name)
+	}
+	return errors.Join(errs...)
+}
+
+// Run publishes due messages every interval until ctx is done.
+func (u *OutboxRelay) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		for {
+			n, err := u.RunOnce(ctx)
+			if err != nil {
+				u.log.Error("outbox: batch failed", "err", err)
+			}
+			if err != nil || n == 0 {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Retry schedules a failed message for an immediate new round of attempts.
+func (u *OutboxRelay) Retry(ctx context.Context, id int64) (domain.OutboxMessage, error) {
+	m, err := u.outbox.GetOutbox(ctx, id)
+	if err != nil {
+		return domain.OutboxMessage{}, err
+	}
+	if m.Status != domain.OutboxFailed {
+		return domain.OutboxMessage{}, fmt.Errorf("message %d is %s, only failed messages can be retried", id, m.Status)
+	}
+	m.Status = domain.OutboxPending
+	m.Attempts = 0
+	m.NextAttemptAt = u.now().UTC()
+	if err := u.outbox.SaveOutbox(ctx, m); err != nil {
+		return domain.OutboxMessage{}, err
+	}
+	return m, nil
+}
+
+func (u *OutboxRelay) List(ctx context.Context, status domain.OutboxStatus) ([]domain.OutboxMessage, error) {
+	return u.outbox.ListOutbox(ctx, status)
+}
@@ -0,0 +1,167 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+type fakeSink struct {
+	errs []error // returned by successive publishes, nil once exhausted
+	got  []domain.Event
+}
+
+func (f *fakeSink) Publish(ctx context.Context, e domain.Event) error {
+	if domain.OrgFromContext(ctx) != e.OrgID {
+		return errors.New("published outside the event's organization")
+	}
+	f.got = append(f.got, e)
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func newRelayEnv(t *testing.T, sinks ...*fakeSink) (*usecase.PRUsecase, *usecase.OutboxRelay) {
+	t.Helper()
+	ctx := context.Background()
+
+	s := memory.NewStore()
+	teams := memory.NewTeamRepo(s)
+	if err := teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{
+		{UserID: "u1", Username: "u1", IsActive: true},
+		{UserID: "u2", Username: "u2", IsActive: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	relay := usecase.NewOutboxRelay(memory.NewOutboxRepo(s), usecase.RetryConfig{
+		MaxAttempts: 2,
+		Backoff:     time.Nanosecond,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	for i, sink := range sinks {
+		relay.AddSink(string(rune('a'+i)), sink)
+	}
+	return usecase.NewPRUsecase(memory.NewUserRepo(s), memory.NewPRRepo(s)), relay
+}
+
+func relayOnce(t *testing.T, relay *usecase.OutboxRelay) int {
+	t.Helper()
+	time.Sleep(time.Millisecond)
+	n, err := relay.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	return n
+}
+
+func TestOutboxRelayKeepsOrderPerPR(t *testing.T) {
+	ctx := context.Background()
+	ok := &fakeSink{}
+	flaky := &fakeSink{errs: []error{errors.New("broker down")}}
+	prs, relay := newRelayEnv(t, ok, flaky)
+
+	if _, err := prs.CreatePR(ctx, "pr-1", "feat", "u1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prs.Merge(ctx, "pr-1", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prs.Merge(ctx, "pr-1", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// The failed pr.created holds back pr.merged and is published again,
+	// only to the sink that failed.
+	if n := relayOnce(t, relay); n != 1 {
+		t.Fatalf("first run claimed %d", n)
+	}
+	for relayOnce(t, relay) > 0 {
+	}
+
+	for name, tc := range map[string]struct {
+		sink *fakeSink
+		want []domain.EventType
+	}{
+		"ok":    {ok, []domain.EventType{domain.EventPRCreated, domain.EventPRMerged}},
+		"flaky": {flaky, []domain.EventType{domain.EventPRCreated, domain.EventPRCreated, domain.EventPRMerged}},
+	} {
+		if len(tc.sink.got) != len(tc.want) {
+			t.Fatalf("%s got %d events, want %d", name, len(tc.sink.got), len(tc.want))
+		}
+		for i, e := range tc.sink.got {
+			if e.Type != tc.want[i] {
+				t.Fatalf("%s event %d is %s, want %s", name, i, e.Type, tc.want[i])
+			}
+		}
+	}
+	if flaky.got[0].ID != flaky.got[1].ID {
+		t.Fatal("retry changed the event id")
+	}
+	merged := ok.got[1].Data.PullRequest
+	if merged == nil || merged.Status != domain.StatusMerged || merged.MergedAt == nil {
+		t.Fatalf("merged payload: %+v", merged)
+	}
+
+	published, err := relay.List(ctx, domain.OutboxPublished)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(published) != 2 || published[0].Attempts != 2 || published[1].Attempts != 1 ||
+		!slices.Equal(published[0].PublishedTo, []string{"a", "b"}) {
+		t.Fatalf("published: %+v", published)
+	}
+}
+
+func TestOutboxRelayFailsThenRetries(t *testing.T) {
+	ctx := context.Background()
+	sink := &fakeSink{errs: []error{errors.New("down"), errors.New("down")}}
+	prs, relay := newRelayEnv(t, sink)
+
+	if _, err := prs.CreatePR(ctx, "pr-1", "feat", "u1"); err != nil {
+		t.Fatal(err)
+	}
+	for relayOnce(t, relay) > 0 {
+	}
+
+	failed, err := relay.List(ctx, domain.OutboxFailed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 1 || failed[0].Attempts != 2 || failed[0].LastError == "" {
+		t.Fatalf("failed: %+v", failed)
+	}
+	if _, err := relay.Retry(ctx, 999); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("retry unknown: got %v", err)
+	}
+
+	if _, err := relay.Retry(ctx, failed[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := relay.Retry(ctx, failed[0].ID); err == nil {
+		t.Fatal("retried a pending message")
+	}
+	if n := relayOnce(t, relay); n != 1 {
+		t.Fatalf("after retry claimed %d", n)
+	}
+	m, err := relay.List(ctx, domain.OutboxPublished)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 1 || len(sink.got) != 3 {
+		t.Fatalf("published %+v after %d attempts", m, len(sink.got))
+	}
+}
@@ -3,20 +3,58 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/beachrockhotel/pr-reviewer/internal/domain"
 )
 
 type PRUsecase struct {
-	users UserRepo
-	prs   PRRepo
+	users     UserRepo
+	prs       PRRepo
+	listeners []ReviewerListener
+	policy    MergePolicy
+}
+
+// ReviewerListener is told about reviewer changes after they are stored.
+type ReviewerListener interface {
+	ReviewersChanged(ctx context.Context, prID string, added, removed []string)
 }
 
 func NewPRUsecase(users UserRepo, prs PRRepo) *PRUsecase {
 	return &PRUsecase{users: users, prs: prs}
 }
 
+// AddReviewerListener must be called before the usecase serves requests.
+func (u *PRUsecase) AddReviewerListener(l ReviewerListener) {
+	u.listeners = append(u.listeners, l)
+}
+
+// MergePolicy decides whether the caller may merge pr. A rejection should
+// wrap a domain error so that callers can map it.
+type MergePolicy interface {
+	CheckMerge(ctx context.Context, pr domain.PullRequest) error
+}
+
+// SetMergePolicy must be called before the usecase serves requests.
+func (u *PRUsecase) SetMergePolicy(p MergePolicy) {
+	u.policy = p
+}
+
+func (u *PRUsecase) reviewersChanged(ctx context.Context, prID string, added, removed []string) {
+	for _, l := range u.listeners {
+		l.ReviewersChanged(ctx, prID, added, removed)
+	}
+}
+
 func (u *PRUsecase) CreatePR(ctx context.Context, prID, name, authorID string) (domain.PullRequest, error) {
+	return u.CreatePRInTeam(ctx, prID, name, authorID, "")
+}
+
+// CreatePRInTeam is CreatePR with reviewers drawn from teamName instead of
+// the author's team; an empty teamName means the author's team.
+func (u *PRUsecase) CreatePRInTeam(ctx context.Context, prID, name, authorID, teamName string) (domain.PullRequest, error) {
 	author, err := u.users.GetByID(ctx, authorID)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
@@ -24,9 +62,12 @@ func (u *PRUsecase) CreatePR(ctx context.Context, prID, name, authorID string) (
 		}
 		return domain.PullRequest{}, err
 	}
+	if teamName == "" {
+		teamName = author.TeamName
+	}
 
 	exclude := []string{author.UserID}
-	cands, err := u.users.ListActiveInTeamExcept(ctx, author.TeamName, exclude, 2)
+	cands, err := u.users.ListActiveInTeamExcept(ctx, teamName, exclude, 2)
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
@@ -43,10 +84,18 @@ func (u *PRUsecase) CreatePR(ctx context.Context, prID, name, authorID string) (
 		Status:   domain.StatusOpen,
 	}
 
-	return u.prs.CreatePRWithReviewers(ctx, pr, revs)
+	created, err := u.prs.CreatePRWithReviewers(ctx, pr, revs, newEvent(ctx, domain.EventPRCreated, domain.EventData{}))
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	u.reviewersChanged(ctx, created.ID, created.AssignedReviewers, nil)
+	return created, nil
 }
 
-func (u *PRUsecase) Reassign(ctx context.Context, prID, oldUserID string) (domain.PullRequest, string, error) {
+// Reassign replaces oldUserID with another active member of their team. The
+// reason, which may be empty, is kept in the pr.reassigned event. The PR
+// must be at one of versions, as for Merge and Review.
+func (u *PRUsecase) Reassign(ctx context.Context, prID, oldUserID, reason string, versions ...int64) (domain.PullRequest, string, error) {
 	pr, err := u.prs.GetByIDForUpdate(ctx, prID)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
@@ -54,9 +103,13 @@ func (u *PRUsecase) Reassign(ctx context.Context, prID, oldUserID string) (domai
 		}
 		return domain.PullRequest{}, "", err
 	}
+	version, err := matchVersion(pr, versions)
+	if err != nil {
+		return domain.PullRequest{}, "", err
+	}
 
-	if pr.Status == domain.StatusMerged {
-		return domain.PullRequest{}, "", domain.ErrPRMerged
+	if err := pr.CheckOpen(); err != nil {
+		return domain.PullRequest{}, "", err
 	}
 
 	assigned, err := u.prs.GetAssignedReviewers(ctx, prID)
@@ -75,6 +128,10 @@ func (u *PRUsecase) Reassign(ctx context.Context, prID, oldUserID string) (domai
 		return domain.PullRequest{}, "", domain.ErrNotAssigned
 	}
 
+	if err := authorizePRChange(ctx, u.users, pr, assigned); err != nil {
+		return domain.PullRequest{}, "", err
+	}
+
 	oldUser, err := u.users.GetByID(ctx, oldUserID)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
@@ -113,12 +170,158 @@ func (u *PRUsecase) Reassign(ctx context.Context, prID, oldUserID string) (domai
 		return domain.PullRequest{}, "", domain.ErrNoCandidate
 	}
 
-	updated, err := u.prs.ReplaceReviewer(ctx, prID, oldUserID, next)
-	return updated, next, err
+	updated, err := u.prs.ReplaceReviewer(ctx, prID, oldUserID, next, version, newEvent(ctx, domain.EventPRReassigned, domain.EventData{
+		OldReviewerID: oldUserID,
+		NewReviewerID: next,
+		Reason:        strings.TrimSpace(reason),
+	}))
+	if err != nil {
+		return domain.PullRequest{}, "", err
+	}
+	u.reviewersChanged(ctx, prID, []string{next}, []string{oldUserID})
+	return updated, next, nil
+}
+
+// Merge marks the PR merged. Given versions, it fails with
+// domain.ErrVersionMismatch unless the PR is still at one of them, so a
+// client cannot merge changes it has not seen. A closed PR fails with
+// domain.ErrPRClosed until it is reopened.
+func (u *PRUsecase) Merge(ctx context.Context, prID string, versions ...int64) (domain.PullRequest, error) {
+	pr, err := u.prs.GetByIDForUpdate(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	version, err := matchVersion(pr, versions)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	if pr.Status == domain.StatusClosed {
+		return domain.PullRequest{}, domain.ErrPRClosed
+	}
+	if err := authorizePRChange(ctx, u.users, pr, pr.AssignedReviewers); err != nil {
+		return domain.PullRequest{}, err
+	}
+	if u.policy != nil && pr.Status != domain.StatusMerged {
+		if err := u.policy.CheckMerge(ctx, pr); err != nil {
+			return domain.PullRequest{}, err
+		}
+	}
+
+	return u.prs.SetMerged(ctx, prID, version, newEvent(ctx, domain.EventPRMerged, domain.EventData{}))
 }
 
-func (u *PRUsecase) Merge(ctx context.Context, prID string) (domain.PullRequest, error) {
-	return u.prs.SetMerged(ctx, prID)
+// RecordMerge marks a PR merged on a code hosting platform, which already
+// authorized the merge. The merge policy is checked with mergerID as the
+// merger, but the merge cannot be undone here: a violation does not stop
+// it, becomes the reason of the pr.merged event and is returned as breach.
+func (u *PRUsecase) RecordMerge(ctx context.Context, prID, mergerID string) (pr domain.PullRequest, breach string, err error) {
+	p, _ := domain.PrincipalFromContext(ctx)
+	p.UserID = mergerID
+	ctx = domain.WithPrincipal(ctx, p)
+
+	stored, err := u.prs.GetByIDForUpdate(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, "", err
+	}
+	if stored.Status == domain.StatusClosed {
+		return domain.PullRequest{}, "", domain.ErrPRClosed
+	}
+	if u.policy != nil && stored.Status != domain.StatusMerged {
+		err := u.policy.CheckMerge(ctx, stored)
+		if err != nil && !errors.Is(err, domain.ErrFourEyes) {
+			return domain.PullRequest{}, "", err
+		}
+		if err != nil {
+			breach = err.Error()
+		}
+	}
+
+	pr, err = u.prs.SetMerged(ctx, prID, 0, newEvent(ctx, domain.EventPRMerged, domain.EventData{Reason: breach}))
+	return pr, breach, err
+}
+
+// Close takes an open PR out of review, as when it is closed or turned back
+// into a draft on a code hosting platform. Its reviewers stay assigned for
+// when it is reopened. A merged or already closed PR is returned as it is,
+// with changed unset.
+func (u *PRUsecase) Close(ctx context.Context, prID string) (pr domain.PullRequest, changed bool, err error) {
+	return u.setClosed(ctx, prID, true, domain.EventPRClosed)
+}
+
+// Reopen puts a closed PR back into review with the reviewers it had; other
+// PRs are returned as they are.
+func (u *PRUsecase) Reopen(ctx context.Context, prID string) (pr domain.PullRequest, changed bool, err error) {
+	return u.setClosed(ctx, prID, false, domain.EventPRReopened)
+}
+
+func (u *PRUsecase) setClosed(ctx context.Context, prID string, closed bool, t domain.EventType) (domain.PullRequest, bool, error) {
+	before, err := u.prs.GetByIDForUpdate(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, false, err
+	}
+	if err := authorizePRChange(ctx, u.users, before, before.AssignedReviewers); err != nil {
+		return domain.PullRequest{}, false, err
+	}
+	pr, err := u.prs.SetClosed(ctx, prID, closed, newEvent(ctx, t, domain.EventData{}))
+	if err != nil {
+		return domain.PullRequest{}, false, err
+	}
+	return pr, pr.Version != before.Version, nil
+}
+
+// Review records the verdict of an assigned reviewer. Only the reviewer may
+// record it: the merge policy relies on verdicts, so tokens and other
+// callers without a user cannot vouch for one.
+func (u *PRUsecase) Review(ctx context.Context, prID, reviewerID string, verdict domain.ReviewVerdict, versions ...int64) (domain.PullRequest, error) {
+	if !verdict.Valid() {
+		return domain.PullRequest{}, fmt.Errorf("%w: unknown verdict %q", domain.ErrInvalid, verdict)
+	}
+	pr, err := u.prs.GetByIDForUpdate(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	version, err := matchVersion(pr, versions)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	if err := pr.CheckOpen(); err != nil {
+		return domain.PullRequest{}, err
+	}
+	if !slices.Contains(pr.AssignedReviewers, reviewerID) {
+		return domain.PullRequest{}, domain.ErrNotAssigned
+	}
+	if p, ok := domain.PrincipalFromContext(ctx); !ok || p.UserID != reviewerID {
+		return domain.PullRequest{}, domain.ErrForbidden
+	}
+
+	return u.prs.SetReview(ctx, prID, domain.Review{ReviewerID: reviewerID, Verdict: verdict}, version,
+		newEvent(ctx, domain.EventPRReviewed, domain.EventData{
+			Review: &domain.EventReview{ReviewerID: reviewerID, Verdict: verdict},
+		}))
+}
+
+// matchVersion returns the one of versions, the ones a caller last saw, that
+// pr is at; the write then fails if the PR changes meanwhile. No versions,
+// or a zero one, accept any and return zero.
+func matchVersion(pr domain.PullRequest, versions []int64) (int64, error) {
+	if len(versions) == 0 || slices.Contains(versions, 0) {
+		return 0, nil
+	}
+	if !slices.Contains(versions, pr.Version) {
+		return 0, domain.ErrVersionMismatch
+	}
+	return pr.Version, nil
+}
+
+// History returns the events of the PR, oldest first: its creation, every
+// reassignment, reviewer verdict and the merge, with who made each. It is
+// built from the stored events rather than the current reviewers, so
+// replaced reviewers and their verdicts stay in it.
+func (u *PRUsecase) History(ctx context.Context, prID string) ([]domain.Event, error) {
+	if _, err := u.prs.GetByIDForUpdate(ctx, prID); err != nil {
+		return nil, err
+	}
+	return u.prs.ListEvents(ctx, prID)
 }
 
 func (u *PRUsecase) StatsByStatus(ctx context.Context) (map[domain.PRStatus]int, error) {
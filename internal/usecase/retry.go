@@ -0,0 +1,39 @@
+package usecase
+
+import "time"
+
+// RetryConfig is the retry policy of the background workers.
+type RetryConfig struct {
+	// MaxAttempts is how many tries are made before giving up.
+	MaxAttempts int
+	// Backoff is the delay after the first failed attempt; it doubles with
+	// every further attempt up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Lease is how long a claimed item is hidden from other workers.
+	Lease time.Duration
+}
+
+func (c RetryConfig) withDefaults() RetryConfig {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 8
+	}
+	if c.Backoff <= 0 {
+		c.Backoff = 10 * time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = time.Hour
+	}
+	if c.Lease <= 0 {
+		c.Lease = time.Minute
+	}
+	return c
+}
+
+func (c RetryConfig) backoff(attempts int) time.Duration {
+	d := c.Backoff
+	for i := 1; i < attempts && d < c.MaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, c.MaxBackoff)
+}
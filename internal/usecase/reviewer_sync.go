@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// ErrPermanent marks push failures that retrying cannot fix, such as a user
+// the platform refuses to request as a reviewer. Such syncs fail at once.
+var ErrPermanent = errors.New("permanent failure")
+
+// ReviewerPusher applies a reviewer change on a code hosting platform.
+type ReviewerPusher interface {
+	// Supports reports whether prID names a PR of the platform.
+	Supports(prID string) bool
+	Push(ctx context.Context, s domain.ReviewerSync) error
+}
+
+// ReviewerSyncUsecase queues reviewer changes made by PRUsecase and pushes
+// them to the platform in the background, keeping every attempt's outcome so
+// failures can be inspected and retried.
+type ReviewerSyncUsecase struct {
+	syncs  ReviewerSyncRepo
+	pusher ReviewerPusher
+	cfg    RetryConfig
+	log    *slog.Logger
+	now    func() time.Time
+}
+
+func NewReviewerSyncUsecase(syncs ReviewerSyncRepo, pusher ReviewerPusher, cfg RetryConfig, logger *slog.Logger) *ReviewerSyncUsecase {
+	return &ReviewerSyncUsecase{syncs: syncs, pusher: pusher, cfg: cfg.withDefaults(), log: logger, now: time.Now}
+}
+
+var _ ReviewerListener = (*ReviewerSyncUsecase)(nil)
+
+// ReviewersChanged queues the change. The PR change is already stored, so a
+// failure to queue is logged rather than returned.
+func (u *ReviewerSyncUsecase) ReviewersChanged(ctx context.Context, prID string, added, removed []string) {
+	if !u.pusher.Supports(prID) || len(added)+len(removed) == 0 {
+		return
+	}
+	_, err := u.syncs.EnqueueReviewerSync(ctx, domain.ReviewerSync{
+		PRID:          prID,
+		Add:           added,
+		Remove:        removed,
+		Status:        domain.SyncPending,
+		NextAttemptAt: u.now().UTC(),
+	})
+	if err != nil {
+		u.log.Error("reviewer sync: enqueue failed", "pr", prID, "err", err)
+	}
+}
+
+// RunOnce pushes one batch of due syncs and returns how many were tried.
+func (u *ReviewerSyncUsecase) RunOnce(ctx context.Context) (int, error) {
+	now := u.now().UTC()
+	batch, err := u.syncs.ClaimReviewerSyncs(ctx, now, now.Add(u.cfg.Lease), 20)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, s := range batch {
+		pushErr := u.pusher.Push(ctx, s)
+		s.Attempts++
+		switch {
+		case pushErr == nil:
+			s.Status, s.LastError = domain.SyncDone, ""
+		case errors.Is(pushErr, ErrPermanent) || s.Attempts >= u.cfg.MaxAttempts:
+			s.Status, s.LastError = domain.SyncFailed, pushErr.Error()
+		default:
+			s.LastError = pushErr.Error()
+			s.NextAttemptAt = u.now().UTC().Add(u.cfg.backoff(s.Attempts))
+		}
+		if pushErr != nil {
+			u.log.Warn("reviewer sync: push failed",
+				"id", s.ID, "pr", s.PRID, "attempt", s.Attempts, "status", s.Status, "err", pushErr)
+		}
+		if err := u.syncs.SaveReviewerSync(ctx, s); err != nil {
+			return 0, fmt.Errorf("save sync %d: %w", s.ID, err)
+		}
+	}
+	return len(batch), nil
+}
+
+// Run pushes due syncs every interval until ctx is done.
+func (u *ReviewerSyncUsecase) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		for {
+			n, err := u.RunOnce(ctx)
+			if err != nil {
+				u.log.Error("reviewer sync: batch failed", "err", err)
+			}
+			if err != nil || n == 0 {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Retry schedules a failed sync for an immediate new round of attempts.
+func (u *ReviewerSyncUsecase) Retry(ctx context.Context, id int64) (domain.ReviewerSync, error) {
+	s, err := u.syncs.GetReviewerSync(ctx, id)
+	if err != nil {
+		return domain.ReviewerSync{}, err
+	}
+	if s.Status != domain.SyncFailed {
+		return domain.ReviewerSync{}, fmt.Errorf("sync %d is %s, only failed syncs can be retried", id, s.Status)
+	}
+	s.Status = domain.SyncPending
+	s.Attempts = 0
+	s.NextAttemptAt = u.now().UTC()
+	if err := u.syncs.SaveReviewerSync(ctx, s); err != nil {
+		return domain.ReviewerSync{}, err
+	}
+	return s, nil
+}
+
+func (u *ReviewerSyncUsecase) List(ctx context.Context, status domain.SyncStatus) ([]domain.ReviewerSync, error) {
+	return u.syncs.ListReviewerSyncs(ctx, status)
+}
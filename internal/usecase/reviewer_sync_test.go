@@ -0,0 +1,132 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+type fakePusher struct {
+	errs   []error // returned by successive pushes, nil once exhausted
+	pushed []domain.ReviewerSync
+}
+
+func (p *fakePusher) Supports(prID string) bool { return prID != "local" }
+
+func (p *fakePusher) Push(_ context.Context, s domain.ReviewerSync) error {
+	p.pushed = append(p.pushed, s)
+	if len(p.errs) == 0 {
+		return nil
+	}
+	err := p.errs[0]
+	p.errs = p.errs[1:]
+	return err
+}
+
+func newSyncEnv(t *testing.T, pusher *fakePusher) (*usecase.PRUsecase, *usecase.ReviewerSyncUsecase) {
+	t.Helper()
+	ctx := context.Background()
+
+	s := memory.NewStore()
+	teams := memory.NewTeamRepo(s)
+	if err := teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{
+		{UserID: "u1", Username: "u1", IsActive: true},
+		{UserID: "u2", Username: "u2", IsActive: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A nanosecond backoff makes a rescheduled sync due on the next run.
+	syncs := usecase.NewReviewerSyncUsecase(memory.NewReviewerSyncRepo(s), pusher, usecase.RetryConfig{
+		MaxAttempts: 2,
+		Backoff:     time.Nanosecond,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	prs := usecase.NewPRUsecase(memory.NewUserRepo(s), memory.NewPRRepo(s))
+	prs.AddReviewerListener(syncs)
+	return prs, syncs
+}
+
+func runOnce(t *testing.T, uc *usecase.ReviewerSyncUsecase) int {
+	t.Helper()
+	time.Sleep(time.Millisecond)
+	n, err := uc.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	return n
+}
+
+func TestReviewerSyncRetriesThenFails(t *testing.T) {
+	ctx := context.Background()
+	pusher := &fakePusher{errs: []error{errors.New("502"), errors.New("502")}}
+	prs, syncs := newSyncEnv(t, pusher)
+
+	if _, err := prs.CreatePR(ctx, "o/r#1", "feat", "u1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prs.CreatePR(ctx, "local", "feat", "u1"); err != nil {
+		t.Fatal(err)
+	}
+
+	runOnce(t, syncs)
+	runOnce(t, syncs)
+	if n := runOnce(t, syncs); n != 0 {
+		t.Fatalf("failed sync was claimed again")
+	}
+
+	failed, err := syncs.List(ctx, domain.SyncFailed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 1 || failed[0].PRID != "o/r#1" || failed[0].Attempts != 2 || failed[0].LastError != "502" {
+		t.Fatalf("failed syncs: %+v", failed)
+	}
+	if !slices.Equal(failed[0].Add, []string{"u2"}) {
+		t.Fatalf("add: %v", failed[0].Add)
+	}
+
+	if _, err := syncs.Retry(ctx, failed[0].ID); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	runOnce(t, syncs)
+	done, err := syncs.List(ctx, domain.SyncDone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 1 || len(pusher.pushed) != 3 {
+		t.Fatalf("done %d, pushes %d", len(done), len(pusher.pushed))
+	}
+	if _, err := syncs.Retry(ctx, failed[0].ID); err == nil {
+		t.Fatal("retried a sync that is done")
+	}
+}
+
+func TestReviewerSyncPermanentFailure(t *testing.T) {
+	ctx := context.Background()
+	pusher := &fakePusher{errs: []error{usecase.ErrPermanent}}
+	prs, syncs := newSyncEnv(t, pusher)
+
+	if _, err := prs.CreatePR(ctx, "o/r#1", "feat", "u1"); err != nil {
+		t.Fatal(err)
+	}
+	runOnce(t, syncs)
+
+	failed, err := syncs.List(ctx, domain.SyncFailed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 1 || failed[0].Attempts != 1 {
+		t.Fatalf("failed syncs: %+v", failed)
+	}
+}
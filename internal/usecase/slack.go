@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/url"
+	"slices"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// SlackMessage is a notification about one event for one team's channel.
+type SlackMessage struct {
+	Event    domain.Event
+	TeamName string
+	// NewReviewers are the reviewers from TeamName that the event assigned.
+	NewReviewers []string
+	// Usernames and SlackIDs are keyed by user id and cover the author and
+	// reviewers of the PR as far as they are known.
+	Usernames map[string]string
+	SlackIDs  map[string]string
+}
+
+// SlackPoster renders a message and posts it to a channel's webhook. Errors
+// wrapping ErrPermanent are not retried.
+type SlackPoster interface {
+	Post(ctx context.Context, webhookURL string, m SlackMessage) error
+}
+
+// SlackUsecase pings reviewers in their team's channel when they are
+// assigned. It is an EventSink; teams without a channel are skipped.
+type SlackUsecase struct {
+	slack  SlackRepo
+	users  UserRepo
+	poster SlackPoster
+	log    *slog.Logger
+}
+
+var _ EventSink = (*SlackUsecase)(nil)
+
+func NewSlackUsecase(slack SlackRepo, users UserRepo, poster SlackPoster, logger *slog.Logger) *SlackUsecase {
+	return &SlackUsecase{slack: slack, users: users, poster: poster, log: logger}
+}
+
+func (u *SlackUsecase) SetChannel(ctx context.Context, teamName, webhookURL string) error {
+	if p, err := url.Parse(webhookURL); err != nil || (p.Scheme != "http" && p.Scheme != "https") || p.Host == "" {
+		return fmt.Errorf("%w: webhook url must be an absolute http(s) URL", domain.ErrInvalid)
+	}
+	return u.slack.SetSlackChannel(ctx, domain.SlackChannel{TeamName: teamName, WebhookURL: webhookURL})
+}
+
+func (u *SlackUsecase) DeleteChannel(ctx context.Context, teamName string) error {
+	return u.slack.DeleteSlackChannel(ctx, teamName)
+}
+
+func (u *SlackUsecase) Channels(ctx context.Context) ([]domain.SlackChannel, error) {
+	return u.slack.ListSlackChannels(ctx)
+}
+
+// SetUser maps userID to a Slack member id such as U024BE7LH.
+func (u *SlackUsecase) SetUser(ctx context.Context, userID, slackID string) error {
+	if slackID == "" {
+		return fmt.Errorf("%w: slack id is required", domain.ErrInvalid)
+	}
+	return u.slack.SetSlackUser(ctx, domain.SlackUser{UserID: userID, SlackID: slackID})
+}
+
+func (u *SlackUsecase) DeleteUser(ctx context.Context, userID string) error {
+	return u.slack.DeleteSlackUser(ctx, userID)
+}
+
+func (u *SlackUsecase) Users(ctx context.Context) ([]domain.SlackUser, error) {
+	return u.slack.ListSlackUsers(ctx)
+}
+
+// UserBySlackID returns the id of the user mapped to a Slack member id, or
+// domain.ErrNotFound.
+func (u *SlackUsecase) UserBySlackID(ctx context.Context, slackID string) (string, error) {
+	list, err := u.slack.ListSlackUsers(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, su := range list {
+		if su.SlackID == slackID {
+			return su.UserID, nil
+		}
+	}
+	return "", domain.ErrNotFound
+}
+
+// Publish posts pr.created and pr.reassigned to the channels of the teams
+// the new reviewers belong to. If one channel fails the event is posted to
+// all of them again, so the others may see the message twice.
+func (u *SlackUsecase) Publish(ctx context.Context, e domain.Event) error {
+	pr := e.Data.PullRequest
+	if pr == nil {
+		return nil
+	}
+	var assigned []string
+	switch e.Type {
+	case domain.EventPRCreated:
+		assigned = pr.AssignedReviewers
+	case domain.EventPRReassigned:
+		assigned = []string{e.Data.NewReviewerID}
+	}
+	if len(assigned) == 0 {
+		return nil
+	}
+
+	usernames := make(map[string]string)
+	byTeam := make(map[string][]string)
+	for _, id := range append([]string{pr.AuthorID, e.Data.OldReviewerID}, pr.AssignedReviewers...) {
+		if _, ok := usernames[id]; ok || id == "" {
+			continue
+		}
+		user, err := u.users.GetByID(ctx, id)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		usernames[id] = user.Username
+		if slices.Contains(assigned, id) {
+			byTeam[user.TeamName] = append(byTeam[user.TeamName], id)
+		}
+	}
+
+	mapped, err := u.slack.ListSlackUsers(ctx)
+	if err != nil {
+		return err
+	}
+	slackIDs := make(map[string]string, len(mapped))
+	for _, m := range mapped {
+		slackIDs[m.UserID] = m.SlackID
+	}
+
+	var errs []error
+	for _, team := range slices.Sorted(maps.Keys(byTeam)) {
+		ch, err := u.slack.GetSlackChannel(ctx, team)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		err = u.poster.Post(ctx, ch.WebhookURL, SlackMessage{
+			Event:        e,
+			TeamName:     team,
+			NewReviewers: byTeam[team],
+			Usernames:    usernames,
+			SlackIDs:     slackIDs,
+		})
+		switch {
+		case errors.Is(err, ErrPermanent):
+			u.log.WarnContext(ctx, "slack: message dropped", "team", team, "event", e.ID, "err", err)
+		case err != nil:
+			errs = append(errs, fmt.Errorf("team %s: %w", team, err))
+		}
+	}
+	return errors.Join(errs...)
+}
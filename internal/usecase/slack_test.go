@@ -0,0 +1,107 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+type fakeSlack struct {
+	errs  map[string]error // by webhook URL
+	posts map[string]usecase.SlackMessage
+}
+
+func (f *fakeSlack) Post(_ context.Context, webhookURL string, m usecase.SlackMessage) error {
+	if err := f.errs[webhookURL]; err != nil {
+		return err
+	}
+	f.posts[webhookURL] = m
+	return nil
+}
+
+func TestSlackPingsNewReviewersInTheirTeamChannel(t *testing.T) {
+	ctx := context.Background()
+
+	s := memory.NewStore()
+	teams := memory.NewTeamRepo(s)
+	for team, ids := range map[string][]string{"backend": {"u1", "u2"}, "mobile": {"m1"}, "qa": {"q1"}} {
+		if err := teams.CreateTeam(ctx, team); err != nil {
+			t.Fatal(err)
+		}
+		var users []domain.User
+		for _, id := range ids {
+			users = append(users, domain.User{UserID: id, Username: "name-" + id, IsActive: true})
+		}
+		if err := teams.UpsertUsersToTeam(ctx, team, users); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	poster := &fakeSlack{posts: make(map[string]usecase.SlackMessage)}
+	uc := usecase.NewSlackUsecase(memory.NewSlackRepo(s), memory.NewUserRepo(s), poster,
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if err := uc.SetChannel(ctx, "backend", "hooks.slack.com/x"); !errors.Is(err, domain.ErrInvalid) {
+		t.Fatalf("relative url: got %v, want %v", err, domain.ErrInvalid)
+	}
+	for team, hook := range map[string]string{"backend": "https://b", "mobile": "https://m"} {
+		if err := uc.SetChannel(ctx, team, hook); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := uc.SetUser(ctx, "u2", "U02"); err != nil {
+		t.Fatal(err)
+	}
+
+	// u2 and m1 are assigned: one message per team with a channel, none for qa.
+	created := domain.Event{ID: "e1", Type: domain.EventPRCreated, Data: domain.EventData{
+		PullRequest: &domain.EventPR{ID: "pr-1", AuthorID: "u1", AssignedReviewers: []string{"u2", "m1", "q1"}},
+	}}
+	if err := uc.Publish(ctx, created); err != nil {
+		t.Fatal(err)
+	}
+	if len(poster.posts) != 2 {
+		t.Fatalf("posted to %d channels, want 2", len(poster.posts))
+	}
+	b := poster.posts["https://b"]
+	if b.TeamName != "backend" || !slices.Equal(b.NewReviewers, []string{"u2"}) ||
+		b.SlackIDs["u2"] != "U02" || b.Usernames["u1"] != "name-u1" || b.Usernames["m1"] != "name-m1" {
+		t.Fatalf("backend message: %+v", b)
+	}
+	if m := poster.posts["https://m"]; !slices.Equal(m.NewReviewers, []string{"m1"}) {
+		t.Fatalf("mobile message: %+v", m)
+	}
+
+	// A reassignment pings only the new reviewer; a rejected webhook is
+	// dropped while a failing one makes the event retry.
+	clear(poster.posts)
+	poster.errs = map[string]error{"https://b": fmt.Errorf("%w: 404", usecase.ErrPermanent)}
+	reassigned := domain.Event{ID: "e2", Type: domain.EventPRReassigned, Data: domain.EventData{
+		PullRequest:   &domain.EventPR{ID: "pr-1", AuthorID: "m1", AssignedReviewers: []string{"u2", "u1"}},
+		OldReviewerID: "q1",
+		NewReviewerID: "u1",
+	}}
+	if err := uc.Publish(ctx, reassigned); err != nil {
+		t.Fatalf("permanent failure: got %v", err)
+	}
+	poster.errs["https://b"] = errors.New("503")
+	if err := uc.Publish(ctx, reassigned); err == nil {
+		t.Fatal("temporary failure was not returned")
+	}
+	if len(poster.posts) != 0 {
+		t.Fatalf("posted %+v", poster.posts)
+	}
+
+	merged := domain.Event{ID: "e3", Type: domain.EventPRMerged, Data: created.Data}
+	if err := uc.Publish(ctx, merged); err != nil || len(poster.posts) != 0 {
+		t.Fatalf("merge posted %+v, %v", poster.posts, err)
+	}
+}
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// EventHub wakes waiting streams when new events may have been stored.
+type EventHub struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+func NewEventHub() *EventHub {
+	return &EventHub{ch: make(chan struct{})}
+}
+
+// Notify wakes every current waiter. The argument is the organization the
+// events belong to, or empty if unknown; it is accepted so that Notify fits
+// postgres.ListenOutbox, and ignored because streams recheck cheaply.
+func (h *EventHub) Notify(string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	close(h.ch)
+	h.ch = make(chan struct{})
+}
+
+// wait returns a channel closed by the next Notify.
+func (h *EventHub) wait() <-chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.ch
+}
+
+// StreamFilter narrows a stream to pull requests involving a user, as author
+// or reviewer, or a member of a team. Empty fields do not filter.
+type StreamFilter struct {
+	TeamName string
+	UserID   string
+}
+
+// StreamWriter receives a stream. Both methods end the stream on error.
+type StreamWriter interface {
+	Event(m domain.OutboxMessage) error
+	// KeepAlive is called once the stream is accepted and then after every
+	// quiet period, so that proxies keep the connection open.
+	KeepAlive() error
+}
+
+// EventStreamUsecase streams pull request events from the outbox, which
+// doubles as the event log that lets clients resume where they left off.
+type EventStreamUsecase struct {
+	outbox    OutboxRepo
+	teams     TeamRepo
+	hub       *EventHub
+	poll      time.Duration
+	keepAlive time.Duration
+}
+
+// NewEventStreamUsecase checks the log on every hub notification and at
+// least every poll interval, which is all single-process backends rely on.
+func NewEventStreamUsecase(outbox OutboxRepo, teams TeamRepo, hub *EventHub, poll time.Duration) *EventStreamUsecase {
+	if poll <= 0 {
+		poll = time.Second
+	}
+	return &EventStreamUsecase{outbox: outbox, teams: teams, hub: hub, poll: poll, keepAlive: 15 * time.Second}
+}
+
+// Start returns the ID a new stream without Last-Event-ID starts after: the
+// current end of the log.
+func (u *EventStreamUsecase) Start(ctx context.Context) (int64, error) {
+	return u.outbox.LastOutboxID(ctx)
+}
+
+// Stream writes the events of the organization in ctx stored after afterID
+// that match f, then keeps writing new ones until ctx is done or w fails.
+func (u *EventStreamUsecase) Stream(ctx context.Context, f StreamFilter, afterID int64, w StreamWriter) error {
+	if f.TeamName != "" {
+		if _, _, err := u.teams.GetTeamWithMembers(ctx, f.TeamName); err != nil {
+			return err
+		}
+	}
+	if err := w.KeepAlive(); err != nil {
+		return err
+	}
+
+	poll := time.NewTicker(u.poll)
+	defer poll.Stop()
+	quiet := time.NewTimer(u.keepAlive)
+	defer quiet.Stop()
+
+	for {
+		woken := u.hub.wait()
+		for {
+			batch, err := u.outbox.ListOutboxAfter(ctx, afterID, 100)
+			if err != nil {
+				return err
+			}
+			members, err := u.members(ctx, f.TeamName, batch)
+			if err != nil {
+				return err
+			}
+			for _, m := range batch {
+				afterID = m.ID
+				if !matches(m.Event, f, members) {
+					continue
+				}
+				if err := w.Event(m); err != nil {
+					return err
+				}
+				quiet.Reset(u.keepAlive)
+			}
+			if len(batch) < 100 {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-woken:
+		case <-poll.C:
+		case <-quiet.C:
+			if err := w.KeepAlive(); err != nil {
+				return err
+			}
+			quiet.Reset(u.keepAlive)
+		}
+	}
+}
+
+// members reads the team fresh for every batch that has events, so that
+// membership changes apply to open streams.
+func (u *EventStreamUsecase) members(ctx context.Context, team string, batch []domain.OutboxMessage) ([]string, error) {
+	if team == "" || len(batch) == 0 {
+		return nil, nil
+	}
+	_, users, err := u.teams.GetTeamWithMembers(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(users))
+	for _, m := range users {
+		ids = append(ids, m.UserID)
+	}
+	return ids, nil
+}
+
+func matches(e domain.Event, f StreamFilter, teamMembers []string) bool {
+	pr := e.Data.PullRequest
+	if pr == nil {
+		return false
+	}
+	involved := append([]string{pr.AuthorID, e.Data.OldReviewerID}, pr.AssignedReviewers...)
+	if f.UserID != "" && !slices.Contains(involved, f.UserID) {
+		return false
+	}
+	if f.TeamName != "" && !slices.ContainsFunc(involved, func(id string) bool {
+		return id != "" && slices.Contains(teamMembers, id)
+	}) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+// streamWriter collects events and stops the stream once it has want of them.
+type streamWriter struct {
+	want   int
+	cancel context.CancelFunc
+	got    []domain.OutboxMessage
+}
+
+func (w *streamWriter) Event(m domain.OutboxMessage) error {
+	w.got = append(w.got, m)
+	if len(w.got) == w.want {
+		w.cancel()
+	}
+	return nil
+}
+
+func (w *streamWriter) KeepAlive() error { return nil }
+
+func (w *streamWriter) prIDs() []string {
+	out := make([]string, 0, len(w.got))
+	for _, m := range w.got {
+		out = append(out, m.Event.Data.PullRequest.ID)
+	}
+	return out
+}
+
+func TestEventStreamFiltersAndResumes(t *testing.T) {
+	ctx := context.Background()
+
+	s := memory.NewStore()
+	teams := memory.NewTeamRepo(s)
+	for team, ids := range map[string][]string{"backend": {"u1", "u2"}, "frontend": {"f1", "f2"}} {
+		if err := teams.CreateTeam(ctx, team); err != nil {
+			t.Fatal(err)
+		}
+		var users []domain.User
+		for _, id := range ids {
+			users = append(users, domain.User{UserID: id, Username: id, IsActive: true})
+		}
+		if err := teams.UpsertUsersToTeam(ctx, team, users); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hub := usecase.NewEventHub()
+	streams := usecase.NewEventStreamUsecase(memory.NewOutboxRepo(s), teams, hub, time.Hour)
+	prs := usecase.NewPRUsecase(memory.NewUserRepo(s), memory.NewPRRepo(s))
+
+	if _, err := prs.CreatePR(ctx, "pr-b", "feat", "u1"); err != nil {
+		t.Fatal(err)
+	}
+	start, err := streams.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prs.CreatePR(ctx, "pr-f", "feat", "f1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// From the beginning, only the frontend PR matches.
+	sctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	w := &streamWriter{want: 1, cancel: cancel}
+	if err := streams.Stream(sctx, usecase.StreamFilter{TeamName: "frontend"}, 0, w); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(w.prIDs(), []string{"pr-f"}) {
+		t.Fatalf("team filter: got %v", w.prIDs())
+	}
+
+	// Resumed after pr-b was created, a stream for u1 sees only its live merge.
+	sctx, cancel = context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	w = &streamWriter{want: 1, cancel: cancel}
+	done := make(chan error, 1)
+	go func() { done <- streams.Stream(sctx, usecase.StreamFilter{UserID: "u1"}, start, w) }()
+	if _, err := prs.Merge(ctx, "pr-f", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prs.Merge(ctx, "pr-b", 0); err != nil {
+		t.Fatal(err)
+	}
+	hub.Notify("")
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(w.prIDs(), []string{"pr-b"}) || w.got[0].Event.Type != domain.EventPRMerged || w.got[0].ID <= start {
+		t.Fatalf("user filter after resume: got %v", w.prIDs())
+	}
+
+	err = streams.Stream(ctx, usecase.StreamFilter{TeamName: "nope"}, 0, w)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown team: got %v, want %v", err, domain.ErrNotFound)
+	}
+}
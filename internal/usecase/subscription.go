@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// EventSender POSTs a delivery's payload to a subscriber and returns the
+// HTTP status, 0 if no response arrived. Any error is retried.
+type EventSender interface {
+	Send(ctx context.Context, sub domain.Subscription, d domain.EventDelivery) (int, error)
+}
+
+// SubscriptionUsecase manages webhook subscriptions and delivers published
+// events to them in the background, logging every delivery.
+type SubscriptionUsecase struct {
+	subs       SubscriptionRepo
+	deliveries EventDeliveryRepo
+	sender     EventSender
+	cfg        RetryConfig
+	log        *slog.Logger
+	now        func() time.Time
+}
+
+func NewSubscriptionUsecase(subs SubscriptionRepo, deliveries EventDeliveryRepo, sender EventSender, cfg RetryConfig, logger *slog.Logger) *SubscriptionUsecase {
+	return &SubscriptionUsecase{
+		subs:       subs,
+		deliveries: deliveries,
+		sender:     sender,
+		cfg:        cfg.withDefaults(),
+		log:        logger,
+		now:        time.Now,
+	}
+}
+
+var _ EventSink = (*SubscriptionUsecase)(nil)
+
+// Create subscribes rawURL to the given event types. An empty secret is
+// replaced by a generated one; the caller must keep it to verify signatures.
+func (u *SubscriptionUsecase) Create(ctx context.Context, rawURL, secret string, types []domain.EventType) (domain.Subscription, error) {
+	if p, err := url.Parse(rawURL); err != nil || (p.Scheme != "http" && p.Scheme != "https") || p.Host == "" {
+		return domain.Subscription{}, fmt.Errorf("%w: url must be an absolute http(s) URL", domain.ErrInvalid)
+	}
+	if len(types) == 0 {
+		return domain.Subscription{}, fmt.Errorf("%w: at least one event type is required", domain.ErrInvalid)
+	}
+	for _, t := range types {
+		if !slices.Contains(domain.EventTypes, t) {
+			return domain.Subscription{}, fmt.Errorf("%w: unknown event type %q", domain.ErrInvalid, t)
+		}
+	}
+
+	id, err := randomString(8, hex.EncodeToString)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	if secret == "" {
+		if secret, err = randomString(32, base64.RawURLEncoding.EncodeToString); err != nil {
+			return domain.Subscription{}, err
+		}
+	}
+	return u.subs.CreateSubscription(ctx, domain.Subscription{
+		ID:         id,
+		URL:        rawURL,
+		Secret:     secret,
+		EventTypes: slices.Compact(slices.Sorted(slices.Values(types))),
+	})
+}
+
+func (u *SubscriptionUsecase) List(ctx context.Context) ([]domain.Subscription, error) {
+	return u.subs.ListSubscriptions(ctx)
+}
+
+func (u *SubscriptionUsecase) Delete(ctx context.Context, id string) error {
+	return u.subs.DeleteSubscription(ctx, id)
+}
+
+// Deliveries returns the latest deliveries of a subscription.
+func (u *SubscriptionUsecase) Deliveries(ctx context.Context, subscriptionID string, limit int) ([]domain.EventDelivery, error) {
+	if _, err := u.subs.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	return u.deliveries.ListEventDeliveries(ctx, subscriptionID, limit)
+}
+
+// Redeliver queues the payload of a past delivery again as a new delivery,
+// leaving the original in the log.
+func (u *SubscriptionUsecase) Redeliver(ctx context.Context, deliveryID int64) (domain.EventDelivery, error) {
+	d, err := u.deliveries.GetEventDelivery(ctx, deliveryID)
+	if err != nil {
+		return domain.EventDelivery{}, err
+	}
+	return u.deliveries.EnqueueEventDelivery(ctx, domain.EventDelivery{
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         domain.DeliveryPending,
+		NextAttemptAt:  u.now().UTC(),
+	})
+}
+
+// Publish queues e for every subscription of its organization that wants
+// it. If queueing fails for one, the relay publishes e here again, so the
+// subscriptions before it may see e twice and should deduplicate by its id.
+func (u *SubscriptionUsecase) Publish(ctx context.Context, e domain.Event) error {
+	subs, err := u.subs.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	var payload []byte
+	for _, s := range subs {
+		if !s.Wants(e.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(e); err != nil {
+				return err
+			}
+		}
+		if _, err := u.deliveries.EnqueueEventDelivery(ctx, domain.EventDelivery{
+			SubscriptionID: s.ID,
+			EventID:        e.ID,
+			EventType:      e.Type,
+			Payload:        payload,
+			Status:         domain.DeliveryPending,
+			NextAttemptAt:  u.now().UTC(),
+		}); err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("enqueue for subscription %s: %w", s.ID, err)
+		}
+	}
+	return nil
+}
+
+// RunOnce sends one batch of due deliveries and returns how many were tried.
+func (u *SubscriptionUsecase) RunOnce(ctx context.Context) (int, error) {
+	now := u.now().UTC()
+	batch, err := u.deliveries.ClaimEventDeliveries(ctx, now, now.Add(u.cfg.Lease), 20)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, d := range batch {
+		code, sendErr := u.send(ctx, d)
+		d.Attempts++
+		d.ResponseCode = code
+		switch {
+		case sendErr == nil:
+			d.Status, d.LastError = domain.DeliveryDelivered, ""
+		case errors.Is(sendErr, domain.ErrNotFound) || d.Attempts >= u.cfg.MaxAttempts:
+			d.Status, d.LastError = domain.DeliveryFailed, sendErr.Error()
+		default:
+			d.LastError = sendErr.Error()
+			d.NextAttemptAt = u.now().UTC().Add(u.cfg.backoff(d.Attempts))
+		}
+		if sendErr != nil {
+			u.log.Warn("subscriptions: delivery failed",
+				"id", d.ID, "subscription", d.SubscriptionID, "attempt", d.Attempts, "status", d.Status, "err", sendErr)
+		}
+		if err := u.deliveries.SaveEventDelivery(ctx, d); err != nil {
+			return 0, fmt.Errorf("save delivery %d: %w", d.ID, err)
+		}
+	}
+	return len(batch), nil
+}
+
+func (u *SubscriptionUsecase) send(ctx context.Context, d domain.EventDelivery) (int, error) {
+	sub, err := u.subs.GetSubscription(domain.WithOrg(ctx, d.OrgID), d.SubscriptionID)
+	if err != nil {
+		return 0, fmt.Errorf("subscription %s: %w", d.SubscriptionID, err)
+	}
+	return u.sender.Send(ctx, sub, d)
+}
+
+// Run sends due deliveries every interval until ctx is done.
+func (u *SubscriptionUsecase) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		for {
+			n, err := u.RunOnce(ctx)
+			if err != nil {
+				u.log.Error("subscriptions: batch failed", "err", err)
+			}
+			if err != nil || n == 0 {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
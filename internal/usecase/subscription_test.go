@@ -0,0 +1,255 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+type fakeSender struct {
+	codes []int // answered by successive sends, 200 once exhausted
+	sent  []domain.EventDelivery
+}
+
+func (f *fakeSender) Send(_ context.Context, _ domain.Subscription, d domain.EventDelivery) (int, error) {
+	f.sent = append(f.sent, d)
+	code := 200
+	if len(f.codes) > 0 {
+		code, f.codes = f.codes[0], f.codes[1:]
+	}
+	if code != 200 {
+		return code, errors.New("subscriber answered 500")
+	}
+	return code, nil
+}
+
+type subsEnv struct {
+	prs    *usecase.PRUsecase
+	users  *usecase.UserUsecase
+	subs   *usecase.SubscriptionUsecase
+	relay  *usecase.OutboxRelay
+	sender *fakeSender
+}
+
+func newSubsEnv(t *testing.T, codes ...int) subsEnv {
+	t.Helper()
+	ctx := context.Background()
+
+	s := memory.NewStore()
+	teams := memory.NewTeamRepo(s)
+	if err := teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{
+		{UserID: "u1", Username: "u1", IsActive: true},
+		{UserID: "u2", Username: "u2", IsActive: true},
+		{UserID: "u3", Username: "u3", IsActive: true},
+		{UserID: "u4", Username: "u4", IsActive: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	sender := &fakeSender{codes: codes}
+	subs := usecase.NewSubscriptionUsecase(memory.NewSubscriptionRepo(s), memory.NewEventDeliveryRepo(s), sender,
+		usecase.RetryConfig{MaxAttempts: 2, Backoff: time.Nanosecond}, logger)
+	relay := usecase.NewOutboxRelay(memory.NewOutboxRepo(s), usecase.RetryConfig{MaxAttempts: 3, Backoff: time.Nanosecond}, logger)
+	relay.AddSink("webhooks", subs)
+	return subsEnv{
+		prs:    usecase.NewPRUsecase(memory.NewUserRepo(s), memory.NewPRRepo(s)),
+		users:  usecase.NewUserUsecase(memory.NewUserRepo(s), memory.NewPRRepo(s)),
+		subs:   subs,
+		relay:  relay,
+		sender: sender,
+	}
+}
+
+// drain relays the outbox and then sends every due delivery.
+func drain(t *testing.T, env subsEnv) {
+	t.Helper()
+	for _, run := range []func(context.Context) (int, error){env.relay.RunOnce, env.subs.RunOnce} {
+		for {
+			time.Sleep(time.Millisecond)
+			n, err := run(context.Background())
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			if n == 0 {
+				break
+			}
+		}
+	}
+}
+
+func TestSubscriptionCreateValidates(t *testing.T) {
+	env := newSubsEnv(t)
+	ctx := context.Background()
+
+	for name, tc := range map[string]struct {
+		url   string
+		types []domain.EventType
+	}{
+		"relative url":   {"/hook", []domain.EventType{domain.EventPRCreated}},
+		"ftp url":        {"ftp://example.com", []domain.EventType{domain.EventPRCreated}},
+		"no event types": {"https://example.com", nil},
+		"unknown type":   {"https://example.com", []domain.EventType{"pr.deleted"}},
+	} {
+		if _, err := env.subs.Create(ctx, tc.url, "", tc.types); !errors.Is(err, domain.ErrInvalid) {
+			t.Errorf("%s: got %v, want %v", name, err, domain.ErrInvalid)
+		}
+	}
+
+	sub, err := env.subs.Create(ctx, "https://example.com/hook", "", []domain.EventType{domain.EventPRMerged, domain.EventPRCreated, domain.EventPRMerged})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Secret == "" || len(sub.EventTypes) != 2 {
+		t.Fatalf("created: %+v", sub)
+	}
+}
+
+func TestSubscriptionDeliversMatchingEvents(t *testing.T) {
+	env := newSubsEnv(t)
+	ctx := context.Background()
+
+	_, err := env.subs.Create(ctx, "https://example.com/prs", "k", []domain.EventType{domain.EventPRCreated, domain.EventPRReassigned})
+	if err != nil {
+		t.Fatal(err)
+	}
+	userSub, err := env.subs.Create(ctx, "https://example.com/users", "k", []domain.EventType{domain.EventUserActivityChanged})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := env.prs.CreatePR(ctx, "pr-1", "feat", "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := env.prs.Reassign(ctx, "pr-1", created.AssignedReviewers[0], "", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.prs.Merge(ctx, "pr-1", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.users.SetActive(ctx, "u4", false); err != nil {
+		t.Fatal(err)
+	}
+	// Already inactive: no second event.
+	if _, err := env.users.SetActive(ctx, "u4", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.users.SetActive(ctx, "u4", true); err != nil {
+		t.Fatal(err)
+	}
+	drain(t, env)
+
+	// Only events of the same PR or user are ordered.
+	var got []domain.EventType
+	byType := make(map[domain.EventType]domain.EventDelivery)
+	for _, d := range env.sender.sent {
+		got = append(got, d.EventType)
+		byType[d.EventType] = d
+	}
+	prEvents := slices.DeleteFunc(slices.Clone(got), func(t domain.EventType) bool { return t == domain.EventUserActivityChanged })
+	if !slices.Equal(prEvents, []domain.EventType{domain.EventPRCreated, domain.EventPRReassigned}) || len(got) != 4 {
+		t.Fatalf("sent %v, want pr.created, pr.reassigned in order and two user.activity_changed", got)
+	}
+
+	var e domain.Event
+	if err := json.Unmarshal(byType[domain.EventPRReassigned].Payload, &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != domain.EventPRReassigned || e.OrgID != domain.DefaultOrg || e.Data.PullRequest == nil ||
+		e.Data.OldReviewerID != created.AssignedReviewers[0] || e.Data.NewReviewerID == "" {
+		t.Fatalf("reassigned payload: %+v", e)
+	}
+
+	log, err := env.subs.Deliveries(ctx, userSub.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log) != 2 || log[0].Status != domain.DeliveryDelivered || log[0].ResponseCode != 200 {
+		t.Fatalf("user subscription log: %+v", log)
+	}
+	if _, err := env.subs.Deliveries(ctx, "nope", 0); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown subscription: got %v", err)
+	}
+}
+
+func TestSubscriptionNotRepeatedForFailingSink(t *testing.T) {
+	env := newSubsEnv(t)
+	ctx := context.Background()
+	flaky := &fakeSink{errs: []error{errors.New("broker down"), errors.New("broker down")}}
+	env.relay.AddSink("nats", flaky)
+
+	sub, err := env.subs.Create(ctx, "https://example.com/hook", "k", []domain.EventType{domain.EventPRCreated})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.prs.CreatePR(ctx, "pr-1", "feat", "u1"); err != nil {
+		t.Fatal(err)
+	}
+	drain(t, env)
+
+	if len(flaky.got) != 3 {
+		t.Fatalf("flaky sink got %d attempts, want 3", len(flaky.got))
+	}
+	log, err := env.subs.Deliveries(ctx, sub.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log) != 1 || len(env.sender.sent) != 1 {
+		t.Fatalf("webhook queued %d and sent %d deliveries, want 1", len(log), len(env.sender.sent))
+	}
+}
+
+func TestSubscriptionRetryAndRedeliver(t *testing.T) {
+	env := newSubsEnv(t, 500, 500)
+	ctx := context.Background()
+
+	sub, err := env.subs.Create(ctx, "https://example.com/hook", "k", []domain.EventType{domain.EventPRCreated})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.prs.CreatePR(ctx, "pr-1", "feat", "u1"); err != nil {
+		t.Fatal(err)
+	}
+	drain(t, env)
+
+	log, err := env.subs.Deliveries(ctx, sub.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log) != 1 || log[0].Status != domain.DeliveryFailed || log[0].Attempts != 2 || log[0].ResponseCode != 500 {
+		t.Fatalf("after retries: %+v", log)
+	}
+
+	again, err := env.subs.Redeliver(ctx, log[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drain(t, env)
+
+	log, err = env.subs.Deliveries(ctx, sub.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log) != 2 || log[0].ID != again.ID || log[0].Status != domain.DeliveryDelivered || log[1].Status != domain.DeliveryFailed {
+		t.Fatalf("after redeliver: %+v", log)
+	}
+	if string(env.sender.sent[2].Payload) != string(env.sender.sent[0].Payload) || log[0].EventID != log[1].EventID {
+		t.Fatal("redelivery changed the event")
+	}
+	if _, err := env.subs.Redeliver(ctx, 999); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("redeliver unknown: got %v", err)
+	}
+}
@@ -2,19 +2,31 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/beachrockhotel/pr-reviewer/internal/domain"
 )
 
 type TeamUsecase struct {
 	teams TeamRepo
+	users UserRepo
 }
 
-func NewTeamUsecase(teams TeamRepo) *TeamUsecase {
-	return &TeamUsecase{teams: teams}
+func NewTeamUsecase(teams TeamRepo, users UserRepo) *TeamUsecase {
+	return &TeamUsecase{teams: teams, users: users}
 }
 
 func (u *TeamUsecase) CreateTeam(ctx context.Context, teamName string, members []domain.User) (domain.Team, error) {
+	if err := authorizeTeamEdit(ctx, u.users, members); err != nil {
+		return domain.Team{}, err
+	}
+	for _, m := range members {
+		if _, err := m.Location(time.UTC); err != nil {
+			return domain.Team{}, err
+		}
+	}
+
 	if err := u.teams.CreateTeam(ctx, teamName); err != nil {
 		return domain.Team{}, err
 	}
@@ -40,3 +52,81 @@ func (u *TeamUsecase) GetTeam(ctx context.Context, teamName string) (domain.Team
 	team.Members = list
 	return team, nil
 }
+
+// SetFourEyes turns the four-eyes merge policy of the team on or off. It is
+// a compliance control rather than a membership change, so only admins and
+// tokens with the admin scope may change it.
+func (u *TeamUsecase) SetFourEyes(ctx context.Context, teamName string, on bool) (domain.Team, error) {
+	if err := authorizeAdminScope(ctx, u.users); err != nil {
+		return domain.Team{}, err
+	}
+
+	if _, err := u.teams.SetFourEyes(ctx, teamName, on); err != nil {
+		return domain.Team{}, err
+	}
+	return u.GetTeam(ctx, teamName)
+}
+
+// Import brings the organization in line with a snapshot of teams and their
+// members: missing teams are created, users are created, updated or moved to
+// the team that lists them. Teams and users the snapshot leaves out are kept.
+// Everything is applied in one transaction; a dry run only reports. Only
+// admins may import, since a snapshot can move anyone and grant any role.
+func (u *TeamUsecase) Import(ctx context.Context, teams []domain.Team, dryRun bool) (domain.ImportReport, error) {
+	if err := authorizeAdmin(ctx, u.users); err != nil {
+		return domain.ImportReport{}, err
+	}
+	if len(teams) == 0 {
+		return domain.ImportReport{}, fmt.Errorf("%w: the snapshot has no teams", domain.ErrInvalid)
+	}
+
+	seenTeams, seenUsers := make(map[string]bool), make(map[string]bool)
+	for _, t := range teams {
+		switch {
+		case t.TeamName == "":
+			return domain.ImportReport{}, fmt.Errorf("%w: team_name is required", domain.ErrInvalid)
+		case seenTeams[t.TeamName]:
+			return domain.ImportReport{}, fmt.Errorf("%w: team %q is listed twice", domain.ErrInvalid, t.TeamName)
+		}
+		seenTeams[t.TeamName] = true
+		for _, m := range t.Members {
+			switch {
+			case m.UserID == "" || m.Username == "":
+				return domain.ImportReport{}, fmt.Errorf("%w: team %q: user_id and username are required", domain.ErrInvalid, t.TeamName)
+			case seenUsers[m.UserID]:
+				return domain.ImportReport{}, fmt.Errorf("%w: user %q is listed twice", domain.ErrInvalid, m.UserID)
+			case m.Role != "" && m.Role != domain.RoleAdmin && m.Role != domain.RoleLead && m.Role != domain.RoleMember:
+				return domain.ImportReport{}, fmt.Errorf("%w: user %q: unknown role %q", domain.ErrInvalid, m.UserID, m.Role)
+			}
+			if _, err := m.Location(time.UTC); err != nil {
+				return domain.ImportReport{}, err
+			}
+			seenUsers[m.UserID] = true
+		}
+	}
+
+	return u.teams.ImportTeams(ctx, teams, dryRun)
+}
+
+// Export returns every team with its members, ordered by team name and user
+// id. Only admins may export, since it includes every user's email.
+func (u *TeamUsecase) Export(ctx context.Context) ([]domain.Team, error) {
+	if err := authorizeAdmin(ctx, u.users); err != nil {
+		return nil, err
+	}
+	return listTeamsWithMembers(ctx, u.teams)
+}
+
+func listTeamsWithMembers(ctx context.Context, repo TeamRepo) ([]domain.Team, error) {
+	teams, err := repo.ListTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range teams {
+		_, teams[i].Members, err = repo.GetTeamWithMembers(ctx, teams[i].TeamName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return teams, nil
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+)
+
+// tokenPrefix makes API tokens recognisable in logs and secret scanners.
+const tokenPrefix = "prr_"
+
+type TokenUsecase struct {
+	tokens TokenRepo
+}
+
+func NewTokenUsecase(tokens TokenRepo) *TokenUsecase {
+	return &TokenUsecase{tokens: tokens}
+}
+
+// Issue creates a token bound to orgID and returns its plaintext value. The
+// plaintext is never stored and cannot be recovered later. A cross-org token
+// must have the admin scope.
+func (u *TokenUsecase) Issue(ctx context.Context, orgID, name string, scopes []string, crossOrg bool) (string, domain.APIToken, error) {
+	if name == "" {
+		return "", domain.APIToken{}, errors.New("token name is required")
+	}
+	if len(scopes) == 0 {
+		return "", domain.APIToken{}, errors.New("at least one scope is required")
+	}
+	for _, s := range scopes {
+		if !slices.Contains(domain.Scopes, s) {
+			return "", domain.APIToken{}, fmt.Errorf("unknown scope %q", s)
+		}
+	}
+	if crossOrg && !slices.Contains(scopes, domain.ScopeAdmin) {
+		return "", domain.APIToken{}, errors.New("a cross-org token needs the admin scope")
+	}
+
+	id, err := randomString(8, hex.EncodeToString)
+	if err != nil {
+		return "", domain.APIToken{}, err
+	}
+	secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return "", domain.APIToken{}, err
+	}
+	plain := tokenPrefix + secret
+
+	tok, err := u.tokens.CreateToken(ctx, domain.APIToken{ID: id, OrgID: orgID, Name: name, Scopes: scopes, CrossOrg: crossOrg}, hashToken(plain))
+	if err != nil {
+		return "", domain.APIToken{}, err
+	}
+	return plain, tok, nil
+}
+
+func (u *TokenUsecase) Revoke(ctx context.Context, id string) error {
+	return u.tokens.RevokeToken(ctx, id)
+}
+
+func (u *TokenUsecase) List(ctx context.Context) ([]domain.APIToken, error) {
+	return u.tokens.ListTokens(ctx)
+}
+
+// Authenticate resolves a bearer value to a principal. Unknown, malformed and
+// revoked tokens all yield domain.ErrUnauthorized.
+func (u *TokenUsecase) Authenticate(ctx context.Context, plain string) (domain.Principal, error) {
+	if !strings.HasPrefix(plain, tokenPrefix) {
+		return domain.Principal{}, domain.ErrUnauthorized
+	}
+
+	tok, err := u.tokens.GetTokenByHash(ctx, hashToken(plain))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.Principal{}, domain.ErrUnauthorized
+		}
+		return domain.Principal{}, err
+	}
+	if tok.RevokedAt != nil {
+		return domain.Principal{}, domain.ErrUnauthorized
+	}
+
+	return domain.Principal{TokenID: tok.ID, OrgID: tok.OrgID, Name: tok.Name, Scopes: tok.Scopes, CrossOrg: tok.CrossOrg}, nil
+}
+
+func hashToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(n int, encode func([]byte) string) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encode(b), nil
+}
@@ -0,0 +1,121 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+type fakeTokenRepo struct {
+	byHash map[string]domain.APIToken
+}
+
+func (f *fakeTokenRepo) CreateToken(_ context.Context, t domain.APIToken, hash string) (domain.APIToken, error) {
+	t.CreatedAt = time.Now()
+	f.byHash[hash] = t
+	return t, nil
+}
+
+func (f *fakeTokenRepo) GetTokenByHash(_ context.Context, hash string) (domain.APIToken, error) {
+	t, ok := f.byHash[hash]
+	if !ok {
+		return domain.APIToken{}, domain.ErrNotFound
+	}
+	return t, nil
+}
+
+func (f *fakeTokenRepo) RevokeToken(_ context.Context, id string) error {
+	for h, t := range f.byHash {
+		if t.ID == id {
+			now := time.Now()
+			t.RevokedAt = &now
+			f.byHash[h] = t
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (f *fakeTokenRepo) ListTokens(context.Context) ([]domain.APIToken, error) {
+	var out []domain.APIToken
+	for _, t := range f.byHash {
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+func TestTokenLifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeTokenRepo{byHash: map[string]domain.APIToken{}}
+	uc := usecase.NewTokenUsecase(repo)
+
+	plain, tok, err := uc.Issue(ctx, "acme", "ci", []string{domain.ScopePRsWrite, domain.ScopeRead}, false)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if !strings.HasPrefix(plain, "prr_") {
+		t.Fatalf("token %q has no prefix", plain)
+	}
+	for hash := range repo.byHash {
+		if strings.Contains(hash, plain) || hash == plain {
+			t.Fatalf("plaintext token stored")
+		}
+	}
+
+	p, err := uc.Authenticate(ctx, plain)
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if p.TokenID != tok.ID || p.OrgID != "acme" || !p.HasScope(domain.ScopePRsWrite) || p.HasScope(domain.ScopeTeamsWrite) {
+		t.Fatalf("principal: got %+v", p)
+	}
+
+	if err := uc.Revoke(ctx, tok.ID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, err := uc.Authenticate(ctx, plain); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Fatalf("revoked token: got %v", err)
+	}
+}
+
+func TestTokenAuthenticateRejectsUnknown(t *testing.T) {
+	uc := usecase.NewTokenUsecase(&fakeTokenRepo{byHash: map[string]domain.APIToken{}})
+
+	for _, raw := range []string{"", "garbage", "prr_unknown"} {
+		if _, err := uc.Authenticate(context.Background(), raw); !errors.Is(err, domain.ErrUnauthorized) {
+			t.Fatalf("%q: got %v", raw, err)
+		}
+	}
+}
+
+func TestTokenIssueValidatesScopes(t *testing.T) {
+	uc := usecase.NewTokenUsecase(&fakeTokenRepo{byHash: map[string]domain.APIToken{}})
+	ctx := context.Background()
+
+	if _, _, err := uc.Issue(ctx, domain.DefaultOrg, "x", []string{"root"}, false); err == nil {
+		t.Fatal("unknown scope accepted")
+	}
+	if _, _, err := uc.Issue(ctx, domain.DefaultOrg, "x", nil, false); err == nil {
+		t.Fatal("empty scopes accepted")
+	}
+	if _, _, err := uc.Issue(ctx, domain.DefaultOrg, "", []string{domain.ScopeRead}, false); err == nil {
+		t.Fatal("empty name accepted")
+	}
+	if _, _, err := uc.Issue(ctx, domain.DefaultOrg, "x", []string{domain.ScopeTeamsWrite}, true); err == nil {
+		t.Fatal("cross-org token without the admin scope accepted")
+	}
+}
+
+func TestAdminScopeImpliesAll(t *testing.T) {
+	p := domain.Principal{Scopes: []string{domain.ScopeAdmin}}
+	for _, s := range domain.Scopes {
+		if !p.HasScope(s) {
+			t.Fatalf("admin lacks %s", s)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/beachrockhotel/pr-reviewer/internal/domain"
 )
@@ -16,7 +17,28 @@ func NewUserUsecase(users UserRepo, prs PRRepo) *UserUsecase {
 }
 
 func (u *UserUsecase) SetActive(ctx context.Context, id string, active bool) (domain.User, error) {
-	return u.users.SetActive(ctx, id, active)
+	target, err := u.users.GetByID(ctx, id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if err := authorizeUserChange(ctx, u.users, target); err != nil {
+		return domain.User{}, err
+	}
+
+	return u.users.SetActive(ctx, id, active, newEvent(ctx, domain.EventUserActivityChanged, domain.EventData{}))
+}
+
+// SetAway marks the user absent until the given time, or back when until
+// is nil. Absent users get no daily digest.
+func (u *UserUsecase) SetAway(ctx context.Context, id string, until *time.Time) (domain.User, error) {
+	target, err := u.users.GetByID(ctx, id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if err := authorizeUserChange(ctx, u.users, target); err != nil {
+		return domain.User{}, err
+	}
+	return u.users.SetAway(ctx, id, until)
 }
 
 func (u *UserUsecase) GetReviews(ctx context.Context, userID string) ([]domain.PullRequestShort, error) {
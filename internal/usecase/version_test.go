@@ -0,0 +1,58 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/beachrockhotel/pr-reviewer/internal/adapter/repo/memory"
+	"github.com/beachrockhotel/pr-reviewer/internal/domain"
+	"github.com/beachrockhotel/pr-reviewer/internal/usecase"
+)
+
+func TestStaleVersionIsRejected(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStore()
+	teams, users, prs := memory.NewTeamRepo(s), memory.NewUserRepo(s), memory.NewPRRepo(s)
+	if err := teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := teams.UpsertUsersToTeam(ctx, "backend", []domain.User{
+		{UserID: "u1", Username: "alice", IsActive: true},
+		{UserID: "u2", Username: "bob", IsActive: true},
+		{UserID: "u3", Username: "carol", IsActive: true},
+		{UserID: "u4", Username: "dave", IsActive: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	uc := usecase.NewPRUsecase(users, prs)
+
+	created, err := uc.CreatePR(ctx, "pr-1", "Fix login", "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reviewer := created.AssignedReviewers[0]
+	reassigned, next, err := uc.Reassign(ctx, "pr-1", reviewer, "", created.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reassigned.Version != created.Version+1 {
+		t.Fatalf("reassign: got version %d, want %d", reassigned.Version, created.Version+1)
+	}
+
+	// A client that still holds the created PR must not act on it.
+	if _, _, err := uc.Reassign(ctx, "pr-1", next, "", created.Version); !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Fatalf("stale reassign: got %v, want %v", err, domain.ErrVersionMismatch)
+	}
+	if _, err := uc.Review(ctx, "pr-1", next, domain.VerdictApproved, created.Version); !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Fatalf("stale review: got %v, want %v", err, domain.ErrVersionMismatch)
+	}
+	if _, err := uc.Merge(ctx, "pr-1", created.Version); !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Fatalf("stale merge: got %v, want %v", err, domain.ErrVersionMismatch)
+	}
+
+	merged, err := uc.Merge(ctx, "pr-1", reassigned.Version)
+	if err != nil || merged.Status != domain.StatusMerged || merged.Version != reassigned.Version+1 {
+		t.Fatalf("merge: got %+v, %v", merged, err)
+	}
+}
@@ -0,0 +1,76 @@
+-- Organizations partition teams, users and pull requests. Existing data moves
+-- to the 'default' organization and every key becomes (org_id, <id>), so the
+-- same user_id or pull_request_id may exist in several organizations.
+--
+-- The new keys are named as PostgreSQL would name them, and both the old and
+-- the new ones are dropped before they are added, so the migration can run
+-- again over its own result.
+
+CREATE TABLE IF NOT EXISTS organizations (
+    org_id     TEXT PRIMARY KEY,
+    name       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO organizations (org_id, name) VALUES ('default', 'Default')
+ON CONFLICT (org_id) DO NOTHING;
+
+ALTER TABLE pr_reviewers
+    DROP CONSTRAINT IF EXISTS pr_reviewers_pull_request_id_fkey,
+    DROP CONSTRAINT IF EXISTS pr_reviewers_reviewer_id_fkey,
+    DROP CONSTRAINT IF EXISTS pr_reviewers_org_id_pull_request_id_fkey,
+    DROP CONSTRAINT IF EXISTS pr_reviewers_org_id_reviewer_id_fkey,
+    DROP CONSTRAINT IF EXISTS pr_reviewers_pkey;
+ALTER TABLE pull_requests
+    DROP CONSTRAINT IF EXISTS pull_requests_author_id_fkey,
+    DROP CONSTRAINT IF EXISTS pull_requests_org_id_author_id_fkey,
+    DROP CONSTRAINT IF EXISTS pull_requests_pkey;
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_team_name_fkey,
+    DROP CONSTRAINT IF EXISTS users_org_id_team_name_fkey,
+    DROP CONSTRAINT IF EXISTS users_pkey;
+ALTER TABLE teams
+    DROP CONSTRAINT IF EXISTS teams_org_id_fkey,
+    DROP CONSTRAINT IF EXISTS teams_pkey;
+
+ALTER TABLE teams         ADD COLUMN IF NOT EXISTS org_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE users         ADD COLUMN IF NOT EXISTS org_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS org_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE pr_reviewers  ADD COLUMN IF NOT EXISTS org_id TEXT NOT NULL DEFAULT 'default';
+
+ALTER TABLE teams         ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE users         ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE pull_requests ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE pr_reviewers  ALTER COLUMN org_id DROP DEFAULT;
+
+ALTER TABLE teams
+    ADD CONSTRAINT teams_pkey PRIMARY KEY (org_id, team_name),
+    ADD CONSTRAINT teams_org_id_fkey FOREIGN KEY (org_id) REFERENCES organizations(org_id);
+ALTER TABLE users
+    ADD CONSTRAINT users_pkey PRIMARY KEY (org_id, user_id),
+    ADD CONSTRAINT users_org_id_team_name_fkey
+        FOREIGN KEY (org_id, team_name) REFERENCES teams(org_id, team_name) ON DELETE RESTRICT;
+ALTER TABLE pull_requests
+    ADD CONSTRAINT pull_requests_pkey PRIMARY KEY (org_id, pull_request_id),
+    ADD CONSTRAINT pull_requests_org_id_author_id_fkey
+        FOREIGN KEY (org_id, author_id) REFERENCES users(org_id, user_id);
+ALTER TABLE pr_reviewers
+    ADD CONSTRAINT pr_reviewers_pkey PRIMARY KEY (org_id, pull_request_id, reviewer_id),
+    ADD CONSTRAINT pr_reviewers_org_id_pull_request_id_fkey
+        FOREIGN KEY (org_id, pull_request_id) REFERENCES pull_requests(org_id, pull_request_id) ON DELETE CASCADE,
+    ADD CONSTRAINT pr_reviewers_org_id_reviewer_id_fkey
+        FOREIGN KEY (org_id, reviewer_id) REFERENCES users(org_id, user_id);
+
+DROP INDEX IF EXISTS idx_users_team_active;
+DROP INDEX IF EXISTS idx_pr_author;
+DROP INDEX IF EXISTS idx_pr_status;
+DROP INDEX IF EXISTS idx_pr_reviewer_reviewer;
+
+CREATE INDEX IF NOT EXISTS idx_users_team_active ON users(org_id, team_name, is_active);
+CREATE INDEX IF NOT EXISTS idx_pr_author ON pull_requests(org_id, author_id);
+CREATE INDEX IF NOT EXISTS idx_pr_status ON pull_requests(org_id, status);
+CREATE INDEX IF NOT EXISTS idx_pr_reviewer_reviewer ON pr_reviewers(org_id, reviewer_id);
+
+ALTER TABLE api_tokens
+    ADD COLUMN IF NOT EXISTS org_id TEXT NOT NULL DEFAULT 'default' REFERENCES organizations(org_id);
+ALTER TABLE api_tokens ALTER COLUMN org_id DROP DEFAULT;
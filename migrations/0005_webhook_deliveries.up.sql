@@ -0,0 +1,7 @@
+-- Delivery ids of processed incoming webhooks, used to ignore redeliveries.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    source      TEXT NOT NULL,
+    delivery_id TEXT NOT NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (source, delivery_id)
+);
@@ -0,0 +1,8 @@
+-- Routes GitLab merge request webhooks to a team; the project id is global
+-- on a GitLab instance, so it also determines the organization.
+CREATE TABLE IF NOT EXISTS gitlab_projects (
+    project_id BIGINT PRIMARY KEY,
+    org_id     TEXT NOT NULL,
+    team_name  TEXT NOT NULL,
+    FOREIGN KEY (org_id, team_name) REFERENCES teams(org_id, team_name) ON DELETE CASCADE
+);
@@ -0,0 +1,18 @@
+-- Reviewer changes to push to GitHub. add_reviewers and remove_reviewers hold
+-- user ids; logins are resolved at push time so a fixed mapping applies on retry.
+CREATE TABLE IF NOT EXISTS reviewer_syncs (
+    sync_id          BIGSERIAL PRIMARY KEY,
+    org_id           TEXT NOT NULL,
+    pull_request_id  TEXT NOT NULL,
+    add_reviewers    TEXT[] NOT NULL DEFAULT '{}',
+    remove_reviewers TEXT[] NOT NULL DEFAULT '{}',
+    status           TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'done', 'failed')),
+    attempts         INT NOT NULL DEFAULT 0,
+    last_error       TEXT NOT NULL DEFAULT '',
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (org_id, pull_request_id) REFERENCES pull_requests(org_id, pull_request_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviewer_syncs_due ON reviewer_syncs(status, next_attempt_at);
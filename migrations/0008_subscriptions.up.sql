@@ -0,0 +1,31 @@
+-- Outgoing webhooks. event_deliveries is the delivery log; payload is the
+-- exact body sent so a redelivery repeats it byte for byte.
+CREATE TABLE IF NOT EXISTS subscriptions (
+    subscription_id TEXT NOT NULL,
+    org_id          TEXT NOT NULL REFERENCES organizations(org_id),
+    url             TEXT NOT NULL,
+    secret          TEXT NOT NULL,
+    event_types     TEXT[] NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (org_id, subscription_id)
+);
+
+CREATE TABLE IF NOT EXISTS event_deliveries (
+    delivery_id     BIGSERIAL PRIMARY KEY,
+    org_id          TEXT NOT NULL,
+    subscription_id TEXT NOT NULL,
+    event_id        TEXT NOT NULL,
+    event_type      TEXT NOT NULL,
+    payload         BYTEA NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts        INT NOT NULL DEFAULT 0,
+    response_code   INT NOT NULL DEFAULT 0,
+    last_error      TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (org_id, subscription_id) REFERENCES subscriptions(org_id, subscription_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_deliveries_due ON event_deliveries(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_event_deliveries_subscription ON event_deliveries(org_id, subscription_id, delivery_id);
@@ -0,0 +1,20 @@
+-- Events written in the same transaction as the change they describe and
+-- relayed to the configured sinks. Rows with the same org_id and msg_key are
+-- published in outbox_id order.
+CREATE TABLE IF NOT EXISTS outbox (
+    outbox_id       BIGSERIAL PRIMARY KEY,
+    org_id          TEXT NOT NULL,
+    event_id        TEXT NOT NULL UNIQUE,
+    event_type      TEXT NOT NULL,
+    msg_key         TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'published', 'failed')),
+    attempts        INT NOT NULL DEFAULT 0,
+    last_error      TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_due ON outbox(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_key ON outbox(org_id, msg_key, outbox_id);
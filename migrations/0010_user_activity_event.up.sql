@@ -0,0 +1,5 @@
+-- user.deactivated became user.activity_changed, sent on every change of
+-- is_active; keep existing subscriptions receiving user events.
+UPDATE subscriptions
+SET event_types = array_replace(event_types, 'user.deactivated', 'user.activity_changed')
+WHERE 'user.deactivated' = ANY(event_types);
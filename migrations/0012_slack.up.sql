@@ -0,0 +1,17 @@
+-- Slack notifications: the incoming webhook of each team and the Slack
+-- member ids used to mention users.
+CREATE TABLE IF NOT EXISTS slack_channels (
+    org_id      TEXT NOT NULL,
+    team_name   TEXT NOT NULL,
+    webhook_url TEXT NOT NULL,
+    PRIMARY KEY (org_id, team_name),
+    FOREIGN KEY (org_id, team_name) REFERENCES teams(org_id, team_name) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS slack_users (
+    org_id   TEXT NOT NULL,
+    user_id  TEXT NOT NULL,
+    slack_id TEXT NOT NULL,
+    PRIMARY KEY (org_id, user_id),
+    FOREIGN KEY (org_id, user_id) REFERENCES users(org_id, user_id) ON DELETE CASCADE
+);
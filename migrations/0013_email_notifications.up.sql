@@ -0,0 +1,33 @@
+-- Email notifications. email_notifications queues the items of each user's
+-- next email; payload is the PR as it was when the item was queued.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS email_prefs (
+    org_id      TEXT NOT NULL,
+    user_id     TEXT NOT NULL,
+    assigned    BOOLEAN NOT NULL,
+    reassigned  BOOLEAN NOT NULL,
+    sla_breach  BOOLEAN NOT NULL,
+    PRIMARY KEY (org_id, user_id),
+    FOREIGN KEY (org_id, user_id) REFERENCES users(org_id, user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS email_notifications (
+    notification_id BIGSERIAL PRIMARY KEY,
+    org_id          TEXT NOT NULL,
+    user_id         TEXT NOT NULL,
+    kind            TEXT NOT NULL CHECK (kind IN ('assigned', 'reassigned', 'sla_breach')),
+    pull_request_id TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts        INT NOT NULL DEFAULT 0,
+    last_error      TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_notifications_pending ON email_notifications(status, org_id, user_id);
+-- A reviewer hears about a breach of a PR once.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_notifications_sla
+    ON email_notifications(org_id, user_id, pull_request_id) WHERE kind = 'sla_breach';
@@ -0,0 +1,14 @@
+-- Daily review digests. review_digests records the local day each user got
+-- (or is being sent) a digest, so that replicas send it once.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS away_until TIMESTAMPTZ;
+ALTER TABLE email_prefs ADD COLUMN IF NOT EXISTS daily_digest BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE TABLE IF NOT EXISTS review_digests (
+    org_id   TEXT NOT NULL,
+    user_id  TEXT NOT NULL,
+    day      TEXT NOT NULL,
+    sent_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (org_id, user_id, day),
+    FOREIGN KEY (org_id, user_id) REFERENCES users(org_id, user_id) ON DELETE CASCADE
+);
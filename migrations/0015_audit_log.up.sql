@@ -0,0 +1,29 @@
+-- Who changed what: one row per change of a team, user or pull request,
+-- written in the same transaction as the change. Rows are never updated or
+-- deleted; the trigger below rejects attempts to.
+CREATE TABLE IF NOT EXISTS audit_log (
+    audit_id    BIGSERIAL PRIMARY KEY,
+    org_id      TEXT NOT NULL,
+    action      TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id   TEXT NOT NULL,
+    actor       TEXT NOT NULL DEFAULT '',
+    request_id  TEXT NOT NULL DEFAULT '',
+    before      JSONB,
+    after       JSONB,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(org_id, entity_type, entity_id, audit_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(org_id, actor, audit_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_time ON audit_log(org_id, created_at);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,15 @@
+-- Responses to requests sent with an Idempotency-Key, replayed on retries.
+-- status is 0 while the first request is in progress.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    org_id          TEXT NOT NULL,
+    owner           TEXT NOT NULL,
+    idempotency_key TEXT NOT NULL,
+    request_hash    TEXT NOT NULL,
+    status          INTEGER NOT NULL DEFAULT 0,
+    content_type    TEXT NOT NULL DEFAULT '',
+    body            BYTEA,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (org_id, owner, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_created_at_idx ON idempotency_keys (created_at);
@@ -0,0 +1,2 @@
+-- Optimistic concurrency: every change of a PR increments its version.
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
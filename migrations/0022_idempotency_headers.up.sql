@@ -0,0 +1,3 @@
+-- Response headers replayed with a stored response, such as ETag and
+-- Location.
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS headers JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+-- Pull requests closed without a merge, or turned back into drafts.
+ALTER TYPE pr_status ADD VALUE IF NOT EXISTS 'CLOSED';
@@ -0,0 +1,3 @@
+-- Sinks that have published a message already. A retry after a failure
+-- goes only to the other sinks.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS published_to TEXT[] NOT NULL DEFAULT '{}';
@@ -9,15 +9,15 @@ import (
 	"time"
 
 	"github.com/go-faster/errors"
+	"github.com/ogen-go/ogen/conv"
+	ht "github.com/ogen-go/ogen/http"
+	"github.com/ogen-go/ogen/ogenerrors"
+	"github.com/ogen-go/ogen/uri"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/metric"
-	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 	"go.opentelemetry.io/otel/trace"
-
-	"github.com/ogen-go/ogen/conv"
-	ht "github.com/ogen-go/ogen/http"
-	"github.com/ogen-go/ogen/uri"
 )
 
 func trimTrailingSlashes(u *url.URL) {
@@ -27,6 +27,18 @@ func trimTrailingSlashes(u *url.URL) {
 
 // Invoker invokes operations described by OpenAPI v3 specification.
 type Invoker interface {
+	// AuditExportGet invokes GET /audit/export operation.
+	//
+	// Требует AUDIT_SIGNING_KEY, без него — 501.
+	//
+	// GET /audit/export
+	AuditExportGet(ctx context.Context, params AuditExportGetParams) (AuditExportGetRes, error)
+	// AuditListGet invokes GET /audit/list operation.
+	//
+	// Журнал изменений организации, новые первыми.
+	//
+	// GET /audit/list
+	AuditListGet(ctx context.Context, params AuditListGetParams) (AuditListGetRes, error)
 	// PullRequestCreatePost invokes POST /pullRequest/create operation.
 	//
 	// Создать PR и автоматически назначить до 2 ревьюверов
@@ -34,19 +46,64 @@ type Invoker interface {
 	//
 	// POST /pullRequest/create
 	PullRequestCreatePost(ctx context.Context, request *PullRequestCreatePostReq) (PullRequestCreatePostRes, error)
+	// PullRequestHistoryGet invokes GET /pullRequest/history operation.
+	//
+	// История PR — создание, переназначения, решения
+	// ревьюверов и merge по порядку.
+	//
+	// GET /pullRequest/history
+	PullRequestHistoryGet(ctx context.Context, params PullRequestHistoryGetParams) (PullRequestHistoryGetRes, error)
 	// PullRequestMergePost invokes POST /pullRequest/merge operation.
 	//
 	// Пометить PR как MERGED (идемпотентная операция).
 	//
 	// POST /pullRequest/merge
-	PullRequestMergePost(ctx context.Context, request *PullRequestMergePostReq) (PullRequestMergePostRes, error)
+	PullRequestMergePost(ctx context.Context, request *PullRequestMergePostReq, params PullRequestMergePostParams) (PullRequestMergePostRes, error)
 	// PullRequestReassignPost invokes POST /pullRequest/reassign operation.
 	//
 	// Переназначить конкретного ревьювера на другого из
 	// его команды.
 	//
 	// POST /pullRequest/reassign
-	PullRequestReassignPost(ctx context.Context, request *PullRequestReassignPostReq) (PullRequestReassignPostRes, error)
+	PullRequestReassignPost(ctx context.Context, request *PullRequestReassignPostReq, params PullRequestReassignPostParams) (PullRequestReassignPostRes, error)
+	// PullRequestReviewPost invokes POST /pullRequest/review operation.
+	//
+	// Записать решение назначенного ревьювера (последнее
+	// решение заменяет прежнее).
+	//
+	// POST /pullRequest/review
+	PullRequestReviewPost(ctx context.Context, request *PullRequestReviewPostReq, params PullRequestReviewPostParams) (PullRequestReviewPostRes, error)
+	// SubscriptionsCreatePost invokes POST /subscriptions/create operation.
+	//
+	// Подписать URL на события (POST с подписью HMAC-SHA256).
+	//
+	// POST /subscriptions/create
+	SubscriptionsCreatePost(ctx context.Context, request *SubscriptionsCreatePostReq) (SubscriptionsCreatePostRes, error)
+	// SubscriptionsDeletePost invokes POST /subscriptions/delete operation.
+	//
+	// Удалить подписку вместе с журналом доставок.
+	//
+	// POST /subscriptions/delete
+	SubscriptionsDeletePost(ctx context.Context, request *SubscriptionsDeletePostReq) (SubscriptionsDeletePostRes, error)
+	// SubscriptionsDeliveriesGet invokes GET /subscriptions/deliveries operation.
+	//
+	// Журнал доставок подписки, новые первыми.
+	//
+	// GET /subscriptions/deliveries
+	SubscriptionsDeliveriesGet(ctx context.Context, params SubscriptionsDeliveriesGetParams) (SubscriptionsDeliveriesGetRes, error)
+	// SubscriptionsListGet invokes GET /subscriptions/list operation.
+	//
+	// Список подписок организации (без ключей подписи).
+	//
+	// GET /subscriptions/list
+	SubscriptionsListGet(ctx context.Context) (*SubscriptionsListGetOK, error)
+	// SubscriptionsRedeliverPost invokes POST /subscriptions/redeliver operation.
+	//
+	// Отправить событие доставки повторно (создаёт новую
+	// доставку).
+	//
+	// POST /subscriptions/redeliver
+	SubscriptionsRedeliverPost(ctx context.Context, request *SubscriptionsRedeliverPostReq) (SubscriptionsRedeliverPostRes, error)
 	// TeamAddPost invokes POST /team/add operation.
 	//
 	// Создать команду с участниками (создаёт/обновляет
@@ -60,23 +117,50 @@ type Invoker interface {
 	//
 	// GET /team/get
 	TeamGetGet(ctx context.Context, params TeamGetGetParams) (TeamGetGetRes, error)
+	// TeamSetPolicyPost invokes POST /team/setPolicy operation.
+	//
+	// Включить или выключить правило четырёх глаз для
+	// мерджа PR авторов команды.
+	//
+	// POST /team/setPolicy
+	TeamSetPolicyPost(ctx context.Context, request *TeamSetPolicyPostReq) (TeamSetPolicyPostRes, error)
+	// UsersGetNotificationPrefsGet invokes GET /users/getNotificationPrefs operation.
+	//
+	// Получить настройки email-уведомлений пользователя.
+	//
+	// GET /users/getNotificationPrefs
+	UsersGetNotificationPrefsGet(ctx context.Context, params UsersGetNotificationPrefsGetParams) (UsersGetNotificationPrefsGetRes, error)
 	// UsersGetReviewGet invokes GET /users/getReview operation.
 	//
 	// Получить PR'ы, где пользователь назначен ревьювером.
 	//
 	// GET /users/getReview
 	UsersGetReviewGet(ctx context.Context, params UsersGetReviewGetParams) (*UsersGetReviewGetOK, error)
+	// UsersSetAwayPost invokes POST /users/setAway operation.
+	//
+	// Пока пользователь отсутствует, ежедневный дайджест
+	// ему не отправляется.
+	//
+	// POST /users/setAway
+	UsersSetAwayPost(ctx context.Context, request *UsersSetAwayPostReq) (UsersSetAwayPostRes, error)
 	// UsersSetIsActivePost invokes POST /users/setIsActive operation.
 	//
 	// Установить флаг активности пользователя.
 	//
 	// POST /users/setIsActive
 	UsersSetIsActivePost(ctx context.Context, request *UsersSetIsActivePostReq) (UsersSetIsActivePostRes, error)
+	// UsersSetNotificationPrefsPost invokes POST /users/setNotificationPrefs operation.
+	//
+	// Задать настройки email-уведомлений пользователя.
+	//
+	// POST /users/setNotificationPrefs
+	UsersSetNotificationPrefsPost(ctx context.Context, request *NotificationPrefs) (UsersSetNotificationPrefsPostRes, error)
 }
 
 // Client implements OAS client.
 type Client struct {
 	serverURL *url.URL
+	sec       SecuritySource
 	baseClient
 }
 
@@ -85,7 +169,7 @@ var _ Handler = struct {
 }{}
 
 // NewClient initializes new Client defined by OAS.
-func NewClient(serverURL string, opts ...ClientOption) (*Client, error) {
+func NewClient(serverURL string, sec SecuritySource, opts ...ClientOption) (*Client, error) {
 	u, err := url.Parse(serverURL)
 	if err != nil {
 		return nil, err
@@ -98,6 +182,7 @@ func NewClient(serverURL string, opts ...ClientOption) (*Client, error) {
 	}
 	return &Client{
 		serverURL:  u,
+		sec:        sec,
 		baseClient: c,
 	}, nil
 }
@@ -117,22 +202,1554 @@ func (c *Client) requestURL(ctx context.Context) *url.URL {
 	return u
 }
 
-// PullRequestCreatePost invokes POST /pullRequest/create operation.
+// AuditExportGet invokes GET /audit/export operation.
 //
-// Создать PR и автоматически назначить до 2 ревьюверов
-// из команды автора.
+// Требует AUDIT_SIGNING_KEY, без него — 501.
 //
-// POST /pullRequest/create
-func (c *Client) PullRequestCreatePost(ctx context.Context, request *PullRequestCreatePostReq) (PullRequestCreatePostRes, error) {
-	res, err := c.sendPullRequestCreatePost(ctx, request)
+// GET /audit/export
+func (c *Client) AuditExportGet(ctx context.Context, params AuditExportGetParams) (AuditExportGetRes, error) {
+	res, err := c.sendAuditExportGet(ctx, params)
+	return res, err
+}
+
+func (c *Client) sendAuditExportGet(ctx context.Context, params AuditExportGetParams) (res AuditExportGetRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/audit/export"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, AuditExportGetOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/audit/export"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeQueryParams"
+	q := uri.NewQueryEncoder()
+	{
+		// Encode "pull_request_id" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "pull_request_id",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			return e.EncodeValue(conv.StringToString(params.PullRequestID))
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	u.RawQuery = q.Values().Encode()
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "GET", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, AuditExportGetOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeAuditExportGetResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// AuditListGet invokes GET /audit/list operation.
+//
+// Журнал изменений организации, новые первыми.
+//
+// GET /audit/list
+func (c *Client) AuditListGet(ctx context.Context, params AuditListGetParams) (AuditListGetRes, error) {
+	res, err := c.sendAuditListGet(ctx, params)
+	return res, err
+}
+
+func (c *Client) sendAuditListGet(ctx context.Context, params AuditListGetParams) (res AuditListGetRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/audit/list"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, AuditListGetOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/audit/list"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeQueryParams"
+	q := uri.NewQueryEncoder()
+	{
+		// Encode "entity_type" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "entity_type",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.EntityType.Get(); ok {
+				return e.EncodeValue(conv.StringToString(string(val)))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	{
+		// Encode "entity_id" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "entity_id",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.EntityID.Get(); ok {
+				return e.EncodeValue(conv.StringToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	{
+		// Encode "actor" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "actor",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.Actor.Get(); ok {
+				return e.EncodeValue(conv.StringToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	{
+		// Encode "from" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "from",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.From.Get(); ok {
+				return e.EncodeValue(conv.DateTimeToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	{
+		// Encode "to" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "to",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.To.Get(); ok {
+				return e.EncodeValue(conv.DateTimeToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	{
+		// Encode "before_id" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "before_id",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.BeforeID.Get(); ok {
+				return e.EncodeValue(conv.Int64ToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	{
+		// Encode "limit" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "limit",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.Limit.Get(); ok {
+				return e.EncodeValue(conv.IntToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	u.RawQuery = q.Values().Encode()
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "GET", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, AuditListGetOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeAuditListGetResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// PullRequestCreatePost invokes POST /pullRequest/create operation.
+//
+// Создать PR и автоматически назначить до 2 ревьюверов
+// из команды автора.
+//
+// POST /pullRequest/create
+func (c *Client) PullRequestCreatePost(ctx context.Context, request *PullRequestCreatePostReq) (PullRequestCreatePostRes, error) {
+	res, err := c.sendPullRequestCreatePost(ctx, request)
+	return res, err
+}
+
+func (c *Client) sendPullRequestCreatePost(ctx context.Context, request *PullRequestCreatePostReq) (res PullRequestCreatePostRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLTemplateKey.String("/pullRequest/create"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, PullRequestCreatePostOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/pullRequest/create"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "POST", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+	if err := encodePullRequestCreatePostRequest(request, r); err != nil {
+		return res, errors.Wrap(err, "encode request")
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, PullRequestCreatePostOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodePullRequestCreatePostResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// PullRequestHistoryGet invokes GET /pullRequest/history operation.
+//
+// История PR — создание, переназначения, решения
+// ревьюверов и merge по порядку.
+//
+// GET /pullRequest/history
+func (c *Client) PullRequestHistoryGet(ctx context.Context, params PullRequestHistoryGetParams) (PullRequestHistoryGetRes, error) {
+	res, err := c.sendPullRequestHistoryGet(ctx, params)
+	return res, err
+}
+
+func (c *Client) sendPullRequestHistoryGet(ctx context.Context, params PullRequestHistoryGetParams) (res PullRequestHistoryGetRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/pullRequest/history"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, PullRequestHistoryGetOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/pullRequest/history"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeQueryParams"
+	q := uri.NewQueryEncoder()
+	{
+		// Encode "pull_request_id" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "pull_request_id",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			return e.EncodeValue(conv.StringToString(params.PullRequestID))
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	u.RawQuery = q.Values().Encode()
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "GET", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, PullRequestHistoryGetOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodePullRequestHistoryGetResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// PullRequestMergePost invokes POST /pullRequest/merge operation.
+//
+// Пометить PR как MERGED (идемпотентная операция).
+//
+// POST /pullRequest/merge
+func (c *Client) PullRequestMergePost(ctx context.Context, request *PullRequestMergePostReq, params PullRequestMergePostParams) (PullRequestMergePostRes, error) {
+	res, err := c.sendPullRequestMergePost(ctx, request, params)
+	return res, err
+}
+
+func (c *Client) sendPullRequestMergePost(ctx context.Context, request *PullRequestMergePostReq, params PullRequestMergePostParams) (res PullRequestMergePostRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLTemplateKey.String("/pullRequest/merge"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, PullRequestMergePostOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/pullRequest/merge"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "POST", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+	if err := encodePullRequestMergePostRequest(request, r); err != nil {
+		return res, errors.Wrap(err, "encode request")
+	}
+
+	stage = "EncodeHeaderParams"
+	h := uri.NewHeaderEncoder(r.Header)
+	{
+		cfg := uri.HeaderParameterEncodingConfig{
+			Name:    "If-Match",
+			Explode: false,
+		}
+		if err := h.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.IfMatch.Get(); ok {
+				return e.EncodeValue(conv.StringToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode header")
+		}
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, PullRequestMergePostOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodePullRequestMergePostResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// PullRequestReassignPost invokes POST /pullRequest/reassign operation.
+//
+// Переназначить конкретного ревьювера на другого из
+// его команды.
+//
+// POST /pullRequest/reassign
+func (c *Client) PullRequestReassignPost(ctx context.Context, request *PullRequestReassignPostReq, params PullRequestReassignPostParams) (PullRequestReassignPostRes, error) {
+	res, err := c.sendPullRequestReassignPost(ctx, request, params)
+	return res, err
+}
+
+func (c *Client) sendPullRequestReassignPost(ctx context.Context, request *PullRequestReassignPostReq, params PullRequestReassignPostParams) (res PullRequestReassignPostRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLTemplateKey.String("/pullRequest/reassign"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, PullRequestReassignPostOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/pullRequest/reassign"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "POST", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+	if err := encodePullRequestReassignPostRequest(request, r); err != nil {
+		return res, errors.Wrap(err, "encode request")
+	}
+
+	stage = "EncodeHeaderParams"
+	h := uri.NewHeaderEncoder(r.Header)
+	{
+		cfg := uri.HeaderParameterEncodingConfig{
+			Name:    "If-Match",
+			Explode: false,
+		}
+		if err := h.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.IfMatch.Get(); ok {
+				return e.EncodeValue(conv.StringToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode header")
+		}
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, PullRequestReassignPostOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodePullRequestReassignPostResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// PullRequestReviewPost invokes POST /pullRequest/review operation.
+//
+// Записать решение назначенного ревьювера (последнее
+// решение заменяет прежнее).
+//
+// POST /pullRequest/review
+func (c *Client) PullRequestReviewPost(ctx context.Context, request *PullRequestReviewPostReq, params PullRequestReviewPostParams) (PullRequestReviewPostRes, error) {
+	res, err := c.sendPullRequestReviewPost(ctx, request, params)
+	return res, err
+}
+
+func (c *Client) sendPullRequestReviewPost(ctx context.Context, request *PullRequestReviewPostReq, params PullRequestReviewPostParams) (res PullRequestReviewPostRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLTemplateKey.String("/pullRequest/review"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, PullRequestReviewPostOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/pullRequest/review"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "POST", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+	if err := encodePullRequestReviewPostRequest(request, r); err != nil {
+		return res, errors.Wrap(err, "encode request")
+	}
+
+	stage = "EncodeHeaderParams"
+	h := uri.NewHeaderEncoder(r.Header)
+	{
+		cfg := uri.HeaderParameterEncodingConfig{
+			Name:    "If-Match",
+			Explode: false,
+		}
+		if err := h.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.IfMatch.Get(); ok {
+				return e.EncodeValue(conv.StringToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode header")
+		}
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, PullRequestReviewPostOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodePullRequestReviewPostResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// SubscriptionsCreatePost invokes POST /subscriptions/create operation.
+//
+// Подписать URL на события (POST с подписью HMAC-SHA256).
+//
+// POST /subscriptions/create
+func (c *Client) SubscriptionsCreatePost(ctx context.Context, request *SubscriptionsCreatePostReq) (SubscriptionsCreatePostRes, error) {
+	res, err := c.sendSubscriptionsCreatePost(ctx, request)
+	return res, err
+}
+
+func (c *Client) sendSubscriptionsCreatePost(ctx context.Context, request *SubscriptionsCreatePostReq) (res SubscriptionsCreatePostRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLTemplateKey.String("/subscriptions/create"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, SubscriptionsCreatePostOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/subscriptions/create"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "POST", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+	if err := encodeSubscriptionsCreatePostRequest(request, r); err != nil {
+		return res, errors.Wrap(err, "encode request")
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, SubscriptionsCreatePostOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeSubscriptionsCreatePostResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// SubscriptionsDeletePost invokes POST /subscriptions/delete operation.
+//
+// Удалить подписку вместе с журналом доставок.
+//
+// POST /subscriptions/delete
+func (c *Client) SubscriptionsDeletePost(ctx context.Context, request *SubscriptionsDeletePostReq) (SubscriptionsDeletePostRes, error) {
+	res, err := c.sendSubscriptionsDeletePost(ctx, request)
+	return res, err
+}
+
+func (c *Client) sendSubscriptionsDeletePost(ctx context.Context, request *SubscriptionsDeletePostReq) (res SubscriptionsDeletePostRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLTemplateKey.String("/subscriptions/delete"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, SubscriptionsDeletePostOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/subscriptions/delete"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "POST", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+	if err := encodeSubscriptionsDeletePostRequest(request, r); err != nil {
+		return res, errors.Wrap(err, "encode request")
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, SubscriptionsDeletePostOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeSubscriptionsDeletePostResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// SubscriptionsDeliveriesGet invokes GET /subscriptions/deliveries operation.
+//
+// Журнал доставок подписки, новые первыми.
+//
+// GET /subscriptions/deliveries
+func (c *Client) SubscriptionsDeliveriesGet(ctx context.Context, params SubscriptionsDeliveriesGetParams) (SubscriptionsDeliveriesGetRes, error) {
+	res, err := c.sendSubscriptionsDeliveriesGet(ctx, params)
+	return res, err
+}
+
+func (c *Client) sendSubscriptionsDeliveriesGet(ctx context.Context, params SubscriptionsDeliveriesGetParams) (res SubscriptionsDeliveriesGetRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/subscriptions/deliveries"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, SubscriptionsDeliveriesGetOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/subscriptions/deliveries"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeQueryParams"
+	q := uri.NewQueryEncoder()
+	{
+		// Encode "subscription_id" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "subscription_id",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			return e.EncodeValue(conv.StringToString(params.SubscriptionID))
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	{
+		// Encode "limit" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "limit",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.Limit.Get(); ok {
+				return e.EncodeValue(conv.IntToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	u.RawQuery = q.Values().Encode()
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "GET", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, SubscriptionsDeliveriesGetOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeSubscriptionsDeliveriesGetResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// SubscriptionsListGet invokes GET /subscriptions/list operation.
+//
+// Список подписок организации (без ключей подписи).
+//
+// GET /subscriptions/list
+func (c *Client) SubscriptionsListGet(ctx context.Context) (*SubscriptionsListGetOK, error) {
+	res, err := c.sendSubscriptionsListGet(ctx)
+	return res, err
+}
+
+func (c *Client) sendSubscriptionsListGet(ctx context.Context) (res *SubscriptionsListGetOK, err error) {
+	otelAttrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/subscriptions/list"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, SubscriptionsListGetOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/subscriptions/list"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "GET", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, SubscriptionsListGetOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeSubscriptionsListGetResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// SubscriptionsRedeliverPost invokes POST /subscriptions/redeliver operation.
+//
+// Отправить событие доставки повторно (создаёт новую
+// доставку).
+//
+// POST /subscriptions/redeliver
+func (c *Client) SubscriptionsRedeliverPost(ctx context.Context, request *SubscriptionsRedeliverPostReq) (SubscriptionsRedeliverPostRes, error) {
+	res, err := c.sendSubscriptionsRedeliverPost(ctx, request)
+	return res, err
+}
+
+func (c *Client) sendSubscriptionsRedeliverPost(ctx context.Context, request *SubscriptionsRedeliverPostReq) (res SubscriptionsRedeliverPostRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLTemplateKey.String("/subscriptions/redeliver"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, SubscriptionsRedeliverPostOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/subscriptions/redeliver"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "POST", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+	if err := encodeSubscriptionsRedeliverPostRequest(request, r); err != nil {
+		return res, errors.Wrap(err, "encode request")
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, SubscriptionsRedeliverPostOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeSubscriptionsRedeliverPostResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// TeamAddPost invokes POST /team/add operation.
+//
+// Создать команду с участниками (создаёт/обновляет
+// пользователей).
+//
+// POST /team/add
+func (c *Client) TeamAddPost(ctx context.Context, request *Team) (TeamAddPostRes, error) {
+	res, err := c.sendTeamAddPost(ctx, request)
 	return res, err
 }
 
-func (c *Client) sendPullRequestCreatePost(ctx context.Context, request *PullRequestCreatePostReq) (res PullRequestCreatePostRes, err error) {
+func (c *Client) sendTeamAddPost(ctx context.Context, request *Team) (res TeamAddPostRes, err error) {
 	otelAttrs := []attribute.KeyValue{
 		semconv.HTTPRequestMethodKey.String("POST"),
-		semconv.HTTPRouteKey.String("/pullRequest/create"),
+		semconv.URLTemplateKey.String("/team/add"),
 	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
 	// Run stopwatch.
 	startTime := time.Now()
@@ -146,7 +1763,7 @@ func (c *Client) sendPullRequestCreatePost(ctx context.Context, request *PullReq
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, PullRequestCreatePostOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, TeamAddPostOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -164,7 +1781,7 @@ func (c *Client) sendPullRequestCreatePost(ctx context.Context, request *PullReq
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
 	var pathParts [1]string
-	pathParts[0] = "/pullRequest/create"
+	pathParts[0] = "/team/add"
 	uri.AddPathParts(u, pathParts[:]...)
 
 	stage = "EncodeRequest"
@@ -172,10 +1789,43 @@ func (c *Client) sendPullRequestCreatePost(ctx context.Context, request *PullReq
 	if err != nil {
 		return res, errors.Wrap(err, "create request")
 	}
-	if err := encodePullRequestCreatePostRequest(request, r); err != nil {
+	if err := encodeTeamAddPostRequest(request, r); err != nil {
 		return res, errors.Wrap(err, "encode request")
 	}
 
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, TeamAddPostOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
 	stage = "SendRequest"
 	resp, err := c.cfg.Client.Do(r)
 	if err != nil {
@@ -184,7 +1834,7 @@ func (c *Client) sendPullRequestCreatePost(ctx context.Context, request *PullReq
 	defer resp.Body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodePullRequestCreatePostResponse(resp)
+	result, err := decodeTeamAddPostResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -192,21 +1842,22 @@ func (c *Client) sendPullRequestCreatePost(ctx context.Context, request *PullReq
 	return result, nil
 }
 
-// PullRequestMergePost invokes POST /pullRequest/merge operation.
+// TeamGetGet invokes GET /team/get operation.
 //
-// Пометить PR как MERGED (идемпотентная операция).
+// Получить команду с участниками.
 //
-// POST /pullRequest/merge
-func (c *Client) PullRequestMergePost(ctx context.Context, request *PullRequestMergePostReq) (PullRequestMergePostRes, error) {
-	res, err := c.sendPullRequestMergePost(ctx, request)
+// GET /team/get
+func (c *Client) TeamGetGet(ctx context.Context, params TeamGetGetParams) (TeamGetGetRes, error) {
+	res, err := c.sendTeamGetGet(ctx, params)
 	return res, err
 }
 
-func (c *Client) sendPullRequestMergePost(ctx context.Context, request *PullRequestMergePostReq) (res PullRequestMergePostRes, err error) {
+func (c *Client) sendTeamGetGet(ctx context.Context, params TeamGetGetParams) (res TeamGetGetRes, err error) {
 	otelAttrs := []attribute.KeyValue{
-		semconv.HTTPRequestMethodKey.String("POST"),
-		semconv.HTTPRouteKey.String("/pullRequest/merge"),
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/team/get"),
 	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
 	// Run stopwatch.
 	startTime := time.Now()
@@ -220,7 +1871,7 @@ func (c *Client) sendPullRequestMergePost(ctx context.Context, request *PullRequ
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, PullRequestMergePostOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, TeamGetGetOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -238,16 +1889,64 @@ func (c *Client) sendPullRequestMergePost(ctx context.Context, request *PullRequ
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
 	var pathParts [1]string
-	pathParts[0] = "/pullRequest/merge"
+	pathParts[0] = "/team/get"
 	uri.AddPathParts(u, pathParts[:]...)
 
+	stage = "EncodeQueryParams"
+	q := uri.NewQueryEncoder()
+	{
+		// Encode "team_name" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "team_name",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			return e.EncodeValue(conv.StringToString(params.TeamName))
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	u.RawQuery = q.Values().Encode()
+
 	stage = "EncodeRequest"
-	r, err := ht.NewRequest(ctx, "POST", u)
+	r, err := ht.NewRequest(ctx, "GET", u)
 	if err != nil {
 		return res, errors.Wrap(err, "create request")
 	}
-	if err := encodePullRequestMergePostRequest(request, r); err != nil {
-		return res, errors.Wrap(err, "encode request")
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, TeamGetGetOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
 	}
 
 	stage = "SendRequest"
@@ -258,7 +1957,7 @@ func (c *Client) sendPullRequestMergePost(ctx context.Context, request *PullRequ
 	defer resp.Body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodePullRequestMergePostResponse(resp)
+	result, err := decodeTeamGetGetResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -266,22 +1965,23 @@ func (c *Client) sendPullRequestMergePost(ctx context.Context, request *PullRequ
 	return result, nil
 }
 
-// PullRequestReassignPost invokes POST /pullRequest/reassign operation.
+// TeamSetPolicyPost invokes POST /team/setPolicy operation.
 //
-// Переназначить конкретного ревьювера на другого из
-// его команды.
+// Включить или выключить правило четырёх глаз для
+// мерджа PR авторов команды.
 //
-// POST /pullRequest/reassign
-func (c *Client) PullRequestReassignPost(ctx context.Context, request *PullRequestReassignPostReq) (PullRequestReassignPostRes, error) {
-	res, err := c.sendPullRequestReassignPost(ctx, request)
+// POST /team/setPolicy
+func (c *Client) TeamSetPolicyPost(ctx context.Context, request *TeamSetPolicyPostReq) (TeamSetPolicyPostRes, error) {
+	res, err := c.sendTeamSetPolicyPost(ctx, request)
 	return res, err
 }
 
-func (c *Client) sendPullRequestReassignPost(ctx context.Context, request *PullRequestReassignPostReq) (res PullRequestReassignPostRes, err error) {
+func (c *Client) sendTeamSetPolicyPost(ctx context.Context, request *TeamSetPolicyPostReq) (res TeamSetPolicyPostRes, err error) {
 	otelAttrs := []attribute.KeyValue{
 		semconv.HTTPRequestMethodKey.String("POST"),
-		semconv.HTTPRouteKey.String("/pullRequest/reassign"),
+		semconv.URLTemplateKey.String("/team/setPolicy"),
 	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
 	// Run stopwatch.
 	startTime := time.Now()
@@ -295,7 +1995,7 @@ func (c *Client) sendPullRequestReassignPost(ctx context.Context, request *PullR
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, PullRequestReassignPostOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, TeamSetPolicyPostOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -313,7 +2013,7 @@ func (c *Client) sendPullRequestReassignPost(ctx context.Context, request *PullR
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
 	var pathParts [1]string
-	pathParts[0] = "/pullRequest/reassign"
+	pathParts[0] = "/team/setPolicy"
 	uri.AddPathParts(u, pathParts[:]...)
 
 	stage = "EncodeRequest"
@@ -321,10 +2021,43 @@ func (c *Client) sendPullRequestReassignPost(ctx context.Context, request *PullR
 	if err != nil {
 		return res, errors.Wrap(err, "create request")
 	}
-	if err := encodePullRequestReassignPostRequest(request, r); err != nil {
+	if err := encodeTeamSetPolicyPostRequest(request, r); err != nil {
 		return res, errors.Wrap(err, "encode request")
 	}
 
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, TeamSetPolicyPostOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
 	stage = "SendRequest"
 	resp, err := c.cfg.Client.Do(r)
 	if err != nil {
@@ -333,7 +2066,7 @@ func (c *Client) sendPullRequestReassignPost(ctx context.Context, request *PullR
 	defer resp.Body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodePullRequestReassignPostResponse(resp)
+	result, err := decodeTeamSetPolicyPostResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -341,22 +2074,22 @@ func (c *Client) sendPullRequestReassignPost(ctx context.Context, request *PullR
 	return result, nil
 }
 
-// TeamAddPost invokes POST /team/add operation.
+// UsersGetNotificationPrefsGet invokes GET /users/getNotificationPrefs operation.
 //
-// Создать команду с участниками (создаёт/обновляет
-// пользователей).
+// Получить настройки email-уведомлений пользователя.
 //
-// POST /team/add
-func (c *Client) TeamAddPost(ctx context.Context, request *Team) (TeamAddPostRes, error) {
-	res, err := c.sendTeamAddPost(ctx, request)
+// GET /users/getNotificationPrefs
+func (c *Client) UsersGetNotificationPrefsGet(ctx context.Context, params UsersGetNotificationPrefsGetParams) (UsersGetNotificationPrefsGetRes, error) {
+	res, err := c.sendUsersGetNotificationPrefsGet(ctx, params)
 	return res, err
 }
 
-func (c *Client) sendTeamAddPost(ctx context.Context, request *Team) (res TeamAddPostRes, err error) {
+func (c *Client) sendUsersGetNotificationPrefsGet(ctx context.Context, params UsersGetNotificationPrefsGetParams) (res UsersGetNotificationPrefsGetRes, err error) {
 	otelAttrs := []attribute.KeyValue{
-		semconv.HTTPRequestMethodKey.String("POST"),
-		semconv.HTTPRouteKey.String("/team/add"),
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/users/getNotificationPrefs"),
 	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
 	// Run stopwatch.
 	startTime := time.Now()
@@ -370,7 +2103,7 @@ func (c *Client) sendTeamAddPost(ctx context.Context, request *Team) (res TeamAd
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, TeamAddPostOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, UsersGetNotificationPrefsGetOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -388,16 +2121,64 @@ func (c *Client) sendTeamAddPost(ctx context.Context, request *Team) (res TeamAd
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
 	var pathParts [1]string
-	pathParts[0] = "/team/add"
+	pathParts[0] = "/users/getNotificationPrefs"
 	uri.AddPathParts(u, pathParts[:]...)
 
+	stage = "EncodeQueryParams"
+	q := uri.NewQueryEncoder()
+	{
+		// Encode "user_id" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "user_id",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			return e.EncodeValue(conv.StringToString(params.UserID))
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	u.RawQuery = q.Values().Encode()
+
 	stage = "EncodeRequest"
-	r, err := ht.NewRequest(ctx, "POST", u)
+	r, err := ht.NewRequest(ctx, "GET", u)
 	if err != nil {
 		return res, errors.Wrap(err, "create request")
 	}
-	if err := encodeTeamAddPostRequest(request, r); err != nil {
-		return res, errors.Wrap(err, "encode request")
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, UsersGetNotificationPrefsGetOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
 	}
 
 	stage = "SendRequest"
@@ -408,7 +2189,7 @@ func (c *Client) sendTeamAddPost(ctx context.Context, request *Team) (res TeamAd
 	defer resp.Body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodeTeamAddPostResponse(resp)
+	result, err := decodeUsersGetNotificationPrefsGetResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -416,21 +2197,22 @@ func (c *Client) sendTeamAddPost(ctx context.Context, request *Team) (res TeamAd
 	return result, nil
 }
 
-// TeamGetGet invokes GET /team/get operation.
+// UsersGetReviewGet invokes GET /users/getReview operation.
 //
-// Получить команду с участниками.
+// Получить PR'ы, где пользователь назначен ревьювером.
 //
-// GET /team/get
-func (c *Client) TeamGetGet(ctx context.Context, params TeamGetGetParams) (TeamGetGetRes, error) {
-	res, err := c.sendTeamGetGet(ctx, params)
+// GET /users/getReview
+func (c *Client) UsersGetReviewGet(ctx context.Context, params UsersGetReviewGetParams) (*UsersGetReviewGetOK, error) {
+	res, err := c.sendUsersGetReviewGet(ctx, params)
 	return res, err
 }
 
-func (c *Client) sendTeamGetGet(ctx context.Context, params TeamGetGetParams) (res TeamGetGetRes, err error) {
+func (c *Client) sendUsersGetReviewGet(ctx context.Context, params UsersGetReviewGetParams) (res *UsersGetReviewGetOK, err error) {
 	otelAttrs := []attribute.KeyValue{
 		semconv.HTTPRequestMethodKey.String("GET"),
-		semconv.HTTPRouteKey.String("/team/get"),
+		semconv.URLTemplateKey.String("/users/getReview"),
 	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
 	// Run stopwatch.
 	startTime := time.Now()
@@ -444,7 +2226,7 @@ func (c *Client) sendTeamGetGet(ctx context.Context, params TeamGetGetParams) (r
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, TeamGetGetOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, UsersGetReviewGetOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -462,21 +2244,21 @@ func (c *Client) sendTeamGetGet(ctx context.Context, params TeamGetGetParams) (r
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
 	var pathParts [1]string
-	pathParts[0] = "/team/get"
+	pathParts[0] = "/users/getReview"
 	uri.AddPathParts(u, pathParts[:]...)
 
 	stage = "EncodeQueryParams"
 	q := uri.NewQueryEncoder()
 	{
-		// Encode "team_name" parameter.
+		// Encode "user_id" parameter.
 		cfg := uri.QueryParameterEncodingConfig{
-			Name:    "team_name",
+			Name:    "user_id",
 			Style:   uri.QueryStyleForm,
 			Explode: true,
 		}
 
 		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
-			return e.EncodeValue(conv.StringToString(params.TeamName))
+			return e.EncodeValue(conv.StringToString(params.UserID))
 		}); err != nil {
 			return res, errors.Wrap(err, "encode query")
 		}
@@ -489,6 +2271,39 @@ func (c *Client) sendTeamGetGet(ctx context.Context, params TeamGetGetParams) (r
 		return res, errors.Wrap(err, "create request")
 	}
 
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, UsersGetReviewGetOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
 	stage = "SendRequest"
 	resp, err := c.cfg.Client.Do(r)
 	if err != nil {
@@ -497,7 +2312,7 @@ func (c *Client) sendTeamGetGet(ctx context.Context, params TeamGetGetParams) (r
 	defer resp.Body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodeTeamGetGetResponse(resp)
+	result, err := decodeUsersGetReviewGetResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -505,21 +2320,23 @@ func (c *Client) sendTeamGetGet(ctx context.Context, params TeamGetGetParams) (r
 	return result, nil
 }
 
-// UsersGetReviewGet invokes GET /users/getReview operation.
+// UsersSetAwayPost invokes POST /users/setAway operation.
 //
-// Получить PR'ы, где пользователь назначен ревьювером.
+// Пока пользователь отсутствует, ежедневный дайджест
+// ему не отправляется.
 //
-// GET /users/getReview
-func (c *Client) UsersGetReviewGet(ctx context.Context, params UsersGetReviewGetParams) (*UsersGetReviewGetOK, error) {
-	res, err := c.sendUsersGetReviewGet(ctx, params)
+// POST /users/setAway
+func (c *Client) UsersSetAwayPost(ctx context.Context, request *UsersSetAwayPostReq) (UsersSetAwayPostRes, error) {
+	res, err := c.sendUsersSetAwayPost(ctx, request)
 	return res, err
 }
 
-func (c *Client) sendUsersGetReviewGet(ctx context.Context, params UsersGetReviewGetParams) (res *UsersGetReviewGetOK, err error) {
+func (c *Client) sendUsersSetAwayPost(ctx context.Context, request *UsersSetAwayPostReq) (res UsersSetAwayPostRes, err error) {
 	otelAttrs := []attribute.KeyValue{
-		semconv.HTTPRequestMethodKey.String("GET"),
-		semconv.HTTPRouteKey.String("/users/getReview"),
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLTemplateKey.String("/users/setAway"),
 	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
 	// Run stopwatch.
 	startTime := time.Now()
@@ -533,7 +2350,7 @@ func (c *Client) sendUsersGetReviewGet(ctx context.Context, params UsersGetRevie
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, UsersGetReviewGetOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, UsersSetAwayPostOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -551,32 +2368,50 @@ func (c *Client) sendUsersGetReviewGet(ctx context.Context, params UsersGetRevie
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
 	var pathParts [1]string
-	pathParts[0] = "/users/getReview"
+	pathParts[0] = "/users/setAway"
 	uri.AddPathParts(u, pathParts[:]...)
 
-	stage = "EncodeQueryParams"
-	q := uri.NewQueryEncoder()
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "POST", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+	if err := encodeUsersSetAwayPostRequest(request, r); err != nil {
+		return res, errors.Wrap(err, "encode request")
+	}
+
 	{
-		// Encode "user_id" parameter.
-		cfg := uri.QueryParameterEncodingConfig{
-			Name:    "user_id",
-			Style:   uri.QueryStyleForm,
-			Explode: true,
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, UsersSetAwayPostOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
 		}
 
-		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
-			return e.EncodeValue(conv.StringToString(params.UserID))
-		}); err != nil {
-			return res, errors.Wrap(err, "encode query")
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
 		}
 	}
-	u.RawQuery = q.Values().Encode()
-
-	stage = "EncodeRequest"
-	r, err := ht.NewRequest(ctx, "GET", u)
-	if err != nil {
-		return res, errors.Wrap(err, "create request")
-	}
 
 	stage = "SendRequest"
 	resp, err := c.cfg.Client.Do(r)
@@ -586,7 +2421,7 @@ func (c *Client) sendUsersGetReviewGet(ctx context.Context, params UsersGetRevie
 	defer resp.Body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodeUsersGetReviewGetResponse(resp)
+	result, err := decodeUsersSetAwayPostResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -607,8 +2442,9 @@ func (c *Client) UsersSetIsActivePost(ctx context.Context, request *UsersSetIsAc
 func (c *Client) sendUsersSetIsActivePost(ctx context.Context, request *UsersSetIsActivePostReq) (res UsersSetIsActivePostRes, err error) {
 	otelAttrs := []attribute.KeyValue{
 		semconv.HTTPRequestMethodKey.String("POST"),
-		semconv.HTTPRouteKey.String("/users/setIsActive"),
+		semconv.URLTemplateKey.String("/users/setIsActive"),
 	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
 	// Run stopwatch.
 	startTime := time.Now()
@@ -652,6 +2488,39 @@ func (c *Client) sendUsersSetIsActivePost(ctx context.Context, request *UsersSet
 		return res, errors.Wrap(err, "encode request")
 	}
 
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, UsersSetIsActivePostOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
 	stage = "SendRequest"
 	resp, err := c.cfg.Client.Do(r)
 	if err != nil {
@@ -667,3 +2536,111 @@ func (c *Client) sendUsersSetIsActivePost(ctx context.Context, request *UsersSet
 
 	return result, nil
 }
+
+// UsersSetNotificationPrefsPost invokes POST /users/setNotificationPrefs operation.
+//
+// Задать настройки email-уведомлений пользователя.
+//
+// POST /users/setNotificationPrefs
+func (c *Client) UsersSetNotificationPrefsPost(ctx context.Context, request *NotificationPrefs) (UsersSetNotificationPrefsPostRes, error) {
+	res, err := c.sendUsersSetNotificationPrefsPost(ctx, request)
+	return res, err
+}
+
+func (c *Client) sendUsersSetNotificationPrefsPost(ctx context.Context, request *NotificationPrefs) (res UsersSetNotificationPrefsPostRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLTemplateKey.String("/users/setNotificationPrefs"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, UsersSetNotificationPrefsPostOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/users/setNotificationPrefs"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "POST", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+	if err := encodeUsersSetNotificationPrefsPostRequest(request, r); err != nil {
+		return res, errors.Wrap(err, "encode request")
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:BearerAuth"
+			switch err := c.securityBearerAuth(ctx, UsersSetNotificationPrefsPostOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"BearerAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeUsersSetNotificationPrefsPostResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
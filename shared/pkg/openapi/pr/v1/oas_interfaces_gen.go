@@ -1,10 +1,22 @@
 // Code generated by ogen, DO NOT EDIT.
 package pr
 
+type AuditExportGetRes interface {
+	auditExportGetRes()
+}
+
+type AuditListGetRes interface {
+	auditListGetRes()
+}
+
 type PullRequestCreatePostRes interface {
 	pullRequestCreatePostRes()
 }
 
+type PullRequestHistoryGetRes interface {
+	pullRequestHistoryGetRes()
+}
+
 type PullRequestMergePostRes interface {
 	pullRequestMergePostRes()
 }
@@ -13,6 +25,26 @@ type PullRequestReassignPostRes interface {
 	pullRequestReassignPostRes()
 }
 
+type PullRequestReviewPostRes interface {
+	pullRequestReviewPostRes()
+}
+
+type SubscriptionsCreatePostRes interface {
+	subscriptionsCreatePostRes()
+}
+
+type SubscriptionsDeletePostRes interface {
+	subscriptionsDeletePostRes()
+}
+
+type SubscriptionsDeliveriesGetRes interface {
+	subscriptionsDeliveriesGetRes()
+}
+
+type SubscriptionsRedeliverPostRes interface {
+	subscriptionsRedeliverPostRes()
+}
+
 type TeamAddPostRes interface {
 	teamAddPostRes()
 }
@@ -21,6 +53,22 @@ type TeamGetGetRes interface {
 	teamGetGetRes()
 }
 
+type TeamSetPolicyPostRes interface {
+	teamSetPolicyPostRes()
+}
+
+type UsersGetNotificationPrefsGetRes interface {
+	usersGetNotificationPrefsGetRes()
+}
+
+type UsersSetAwayPostRes interface {
+	usersSetAwayPostRes()
+}
+
 type UsersSetIsActivePostRes interface {
 	usersSetIsActivePostRes()
 }
+
+type UsersSetNotificationPrefsPostRes interface {
+	usersSetNotificationPrefsPostRes()
+}
@@ -6,11 +6,24 @@ package pr
 type OperationName = string
 
 const (
-	PullRequestCreatePostOperation   OperationName = "PullRequestCreatePost"
-	PullRequestMergePostOperation    OperationName = "PullRequestMergePost"
-	PullRequestReassignPostOperation OperationName = "PullRequestReassignPost"
-	TeamAddPostOperation             OperationName = "TeamAddPost"
-	TeamGetGetOperation              OperationName = "TeamGetGet"
-	UsersGetReviewGetOperation       OperationName = "UsersGetReviewGet"
-	UsersSetIsActivePostOperation    OperationName = "UsersSetIsActivePost"
+	AuditExportGetOperation                OperationName = "AuditExportGet"
+	AuditListGetOperation                  OperationName = "AuditListGet"
+	PullRequestCreatePostOperation         OperationName = "PullRequestCreatePost"
+	PullRequestHistoryGetOperation         OperationName = "PullRequestHistoryGet"
+	PullRequestMergePostOperation          OperationName = "PullRequestMergePost"
+	PullRequestReassignPostOperation       OperationName = "PullRequestReassignPost"
+	PullRequestReviewPostOperation         OperationName = "PullRequestReviewPost"
+	SubscriptionsCreatePostOperation       OperationName = "SubscriptionsCreatePost"
+	SubscriptionsDeletePostOperation       OperationName = "SubscriptionsDeletePost"
+	SubscriptionsDeliveriesGetOperation    OperationName = "SubscriptionsDeliveriesGet"
+	SubscriptionsListGetOperation          OperationName = "SubscriptionsListGet"
+	SubscriptionsRedeliverPostOperation    OperationName = "SubscriptionsRedeliverPost"
+	TeamAddPostOperation                   OperationName = "TeamAddPost"
+	TeamGetGetOperation                    OperationName = "TeamGetGet"
+	TeamSetPolicyPostOperation             OperationName = "TeamSetPolicyPost"
+	UsersGetNotificationPrefsGetOperation  OperationName = "UsersGetNotificationPrefsGet"
+	UsersGetReviewGetOperation             OperationName = "UsersGetReviewGet"
+	UsersSetAwayPostOperation              OperationName = "UsersSetAwayPost"
+	UsersSetIsActivePostOperation          OperationName = "UsersSetIsActivePost"
+	UsersSetNotificationPrefsPostOperation OperationName = "UsersSetNotificationPrefsPost"
 )
@@ -60,6 +60,60 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				break
 			}
 			switch elem[0] {
+			case 'a': // Prefix: "audit/"
+
+				if l := len("audit/"); len(elem) >= l && elem[0:l] == "audit/" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					break
+				}
+				switch elem[0] {
+				case 'e': // Prefix: "export"
+
+					if l := len("export"); len(elem) >= l && elem[0:l] == "export" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "GET":
+							s.handleAuditExportGetRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, "GET")
+						}
+
+						return
+					}
+
+				case 'l': // Prefix: "list"
+
+					if l := len("list"); len(elem) >= l && elem[0:l] == "list" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "GET":
+							s.handleAuditListGetRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, "GET")
+						}
+
+						return
+					}
+
+				}
+
 			case 'p': // Prefix: "pullRequest/"
 
 				if l := len("pullRequest/"); len(elem) >= l && elem[0:l] == "pullRequest/" {
@@ -92,6 +146,26 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 						return
 					}
 
+				case 'h': // Prefix: "history"
+
+					if l := len("history"); len(elem) >= l && elem[0:l] == "history" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "GET":
+							s.handlePullRequestHistoryGetRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, "GET")
+						}
+
+						return
+					}
+
 				case 'm': // Prefix: "merge"
 
 					if l := len("merge"); len(elem) >= l && elem[0:l] == "merge" {
@@ -112,9 +186,171 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 						return
 					}
 
-				case 'r': // Prefix: "reassign"
+				case 'r': // Prefix: "re"
+
+					if l := len("re"); len(elem) >= l && elem[0:l] == "re" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						break
+					}
+					switch elem[0] {
+					case 'a': // Prefix: "assign"
+
+						if l := len("assign"); len(elem) >= l && elem[0:l] == "assign" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch r.Method {
+							case "POST":
+								s.handlePullRequestReassignPostRequest([0]string{}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "POST")
+							}
+
+							return
+						}
+
+					case 'v': // Prefix: "view"
+
+						if l := len("view"); len(elem) >= l && elem[0:l] == "view" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch r.Method {
+							case "POST":
+								s.handlePullRequestReviewPostRequest([0]string{}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "POST")
+							}
+
+							return
+						}
+
+					}
+
+				}
+
+			case 's': // Prefix: "subscriptions/"
+
+				if l := len("subscriptions/"); len(elem) >= l && elem[0:l] == "subscriptions/" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					break
+				}
+				switch elem[0] {
+				case 'c': // Prefix: "create"
+
+					if l := len("create"); len(elem) >= l && elem[0:l] == "create" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "POST":
+							s.handleSubscriptionsCreatePostRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, "POST")
+						}
+
+						return
+					}
+
+				case 'd': // Prefix: "del"
 
-					if l := len("reassign"); len(elem) >= l && elem[0:l] == "reassign" {
+					if l := len("del"); len(elem) >= l && elem[0:l] == "del" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						break
+					}
+					switch elem[0] {
+					case 'e': // Prefix: "ete"
+
+						if l := len("ete"); len(elem) >= l && elem[0:l] == "ete" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch r.Method {
+							case "POST":
+								s.handleSubscriptionsDeletePostRequest([0]string{}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "POST")
+							}
+
+							return
+						}
+
+					case 'i': // Prefix: "iveries"
+
+						if l := len("iveries"); len(elem) >= l && elem[0:l] == "iveries" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch r.Method {
+							case "GET":
+								s.handleSubscriptionsDeliveriesGetRequest([0]string{}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "GET")
+							}
+
+							return
+						}
+
+					}
+
+				case 'l': // Prefix: "list"
+
+					if l := len("list"); len(elem) >= l && elem[0:l] == "list" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "GET":
+							s.handleSubscriptionsListGetRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, "GET")
+						}
+
+						return
+					}
+
+				case 'r': // Prefix: "redeliver"
+
+					if l := len("redeliver"); len(elem) >= l && elem[0:l] == "redeliver" {
 						elem = elem[l:]
 					} else {
 						break
@@ -124,7 +360,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 						// Leaf node.
 						switch r.Method {
 						case "POST":
-							s.handlePullRequestReassignPostRequest([0]string{}, elemIsEscaped, w, r)
+							s.handleSubscriptionsRedeliverPostRequest([0]string{}, elemIsEscaped, w, r)
 						default:
 							s.notAllowed(w, r, "POST")
 						}
@@ -186,6 +422,26 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 						return
 					}
 
+				case 's': // Prefix: "setPolicy"
+
+					if l := len("setPolicy"); len(elem) >= l && elem[0:l] == "setPolicy" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "POST":
+							s.handleTeamSetPolicyPostRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, "POST")
+						}
+
+						return
+					}
+
 				}
 
 			case 'u': // Prefix: "users/"
@@ -200,44 +456,132 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 					break
 				}
 				switch elem[0] {
-				case 'g': // Prefix: "getReview"
+				case 'g': // Prefix: "get"
 
-					if l := len("getReview"); len(elem) >= l && elem[0:l] == "getReview" {
+					if l := len("get"); len(elem) >= l && elem[0:l] == "get" {
 						elem = elem[l:]
 					} else {
 						break
 					}
 
 					if len(elem) == 0 {
-						// Leaf node.
-						switch r.Method {
-						case "GET":
-							s.handleUsersGetReviewGetRequest([0]string{}, elemIsEscaped, w, r)
-						default:
-							s.notAllowed(w, r, "GET")
+						break
+					}
+					switch elem[0] {
+					case 'N': // Prefix: "NotificationPrefs"
+
+						if l := len("NotificationPrefs"); len(elem) >= l && elem[0:l] == "NotificationPrefs" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch r.Method {
+							case "GET":
+								s.handleUsersGetNotificationPrefsGetRequest([0]string{}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "GET")
+							}
+
+							return
+						}
+
+					case 'R': // Prefix: "Review"
+
+						if l := len("Review"); len(elem) >= l && elem[0:l] == "Review" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch r.Method {
+							case "GET":
+								s.handleUsersGetReviewGetRequest([0]string{}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "GET")
+							}
+
+							return
 						}
 
-						return
 					}
 
-				case 's': // Prefix: "setIsActive"
+				case 's': // Prefix: "set"
 
-					if l := len("setIsActive"); len(elem) >= l && elem[0:l] == "setIsActive" {
+					if l := len("set"); len(elem) >= l && elem[0:l] == "set" {
 						elem = elem[l:]
 					} else {
 						break
 					}
 
 					if len(elem) == 0 {
-						// Leaf node.
-						switch r.Method {
-						case "POST":
-							s.handleUsersSetIsActivePostRequest([0]string{}, elemIsEscaped, w, r)
-						default:
-							s.notAllowed(w, r, "POST")
+						break
+					}
+					switch elem[0] {
+					case 'A': // Prefix: "Away"
+
+						if l := len("Away"); len(elem) >= l && elem[0:l] == "Away" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch r.Method {
+							case "POST":
+								s.handleUsersSetAwayPostRequest([0]string{}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "POST")
+							}
+
+							return
+						}
+
+					case 'I': // Prefix: "IsActive"
+
+						if l := len("IsActive"); len(elem) >= l && elem[0:l] == "IsActive" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch r.Method {
+							case "POST":
+								s.handleUsersSetIsActivePostRequest([0]string{}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "POST")
+							}
+
+							return
+						}
+
+					case 'N': // Prefix: "NotificationPrefs"
+
+						if l := len("NotificationPrefs"); len(elem) >= l && elem[0:l] == "NotificationPrefs" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch r.Method {
+							case "POST":
+								s.handleUsersSetNotificationPrefsPostRequest([0]string{}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "POST")
+							}
+
+							return
 						}
 
-						return
 					}
 
 				}
@@ -336,6 +680,68 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 				break
 			}
 			switch elem[0] {
+			case 'a': // Prefix: "audit/"
+
+				if l := len("audit/"); len(elem) >= l && elem[0:l] == "audit/" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					break
+				}
+				switch elem[0] {
+				case 'e': // Prefix: "export"
+
+					if l := len("export"); len(elem) >= l && elem[0:l] == "export" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "GET":
+							r.name = AuditExportGetOperation
+							r.summary = "Подписанная выгрузка истории ревью PR для аудиторов"
+							r.operationID = ""
+							r.pathPattern = "/audit/export"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
+
+				case 'l': // Prefix: "list"
+
+					if l := len("list"); len(elem) >= l && elem[0:l] == "list" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "GET":
+							r.name = AuditListGetOperation
+							r.summary = "Журнал изменений организации, новые первыми"
+							r.operationID = ""
+							r.pathPattern = "/audit/list"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
+
+				}
+
 			case 'p': // Prefix: "pullRequest/"
 
 				if l := len("pullRequest/"); len(elem) >= l && elem[0:l] == "pullRequest/" {
@@ -372,6 +778,30 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 						}
 					}
 
+				case 'h': // Prefix: "history"
+
+					if l := len("history"); len(elem) >= l && elem[0:l] == "history" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "GET":
+							r.name = PullRequestHistoryGetOperation
+							r.summary = "История PR — создание, переназначения, решения ревьюверов и merge по порядку"
+							r.operationID = ""
+							r.pathPattern = "/pullRequest/history"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
+
 				case 'm': // Prefix: "merge"
 
 					if l := len("merge"); len(elem) >= l && elem[0:l] == "merge" {
@@ -396,9 +826,195 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 						}
 					}
 
-				case 'r': // Prefix: "reassign"
+				case 'r': // Prefix: "re"
+
+					if l := len("re"); len(elem) >= l && elem[0:l] == "re" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						break
+					}
+					switch elem[0] {
+					case 'a': // Prefix: "assign"
+
+						if l := len("assign"); len(elem) >= l && elem[0:l] == "assign" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch method {
+							case "POST":
+								r.name = PullRequestReassignPostOperation
+								r.summary = "Переназначить конкретного ревьювера на другого из его команды"
+								r.operationID = ""
+								r.pathPattern = "/pullRequest/reassign"
+								r.args = args
+								r.count = 0
+								return r, true
+							default:
+								return
+							}
+						}
+
+					case 'v': // Prefix: "view"
+
+						if l := len("view"); len(elem) >= l && elem[0:l] == "view" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch method {
+							case "POST":
+								r.name = PullRequestReviewPostOperation
+								r.summary = "Записать решение назначенного ревьювера (последнее решение заменяет прежнее)"
+								r.operationID = ""
+								r.pathPattern = "/pullRequest/review"
+								r.args = args
+								r.count = 0
+								return r, true
+							default:
+								return
+							}
+						}
+
+					}
+
+				}
+
+			case 's': // Prefix: "subscriptions/"
+
+				if l := len("subscriptions/"); len(elem) >= l && elem[0:l] == "subscriptions/" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					break
+				}
+				switch elem[0] {
+				case 'c': // Prefix: "create"
+
+					if l := len("create"); len(elem) >= l && elem[0:l] == "create" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "POST":
+							r.name = SubscriptionsCreatePostOperation
+							r.summary = "Подписать URL на события (POST с подписью HMAC-SHA256)"
+							r.operationID = ""
+							r.pathPattern = "/subscriptions/create"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
+
+				case 'd': // Prefix: "del"
+
+					if l := len("del"); len(elem) >= l && elem[0:l] == "del" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						break
+					}
+					switch elem[0] {
+					case 'e': // Prefix: "ete"
+
+						if l := len("ete"); len(elem) >= l && elem[0:l] == "ete" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch method {
+							case "POST":
+								r.name = SubscriptionsDeletePostOperation
+								r.summary = "Удалить подписку вместе с журналом доставок"
+								r.operationID = ""
+								r.pathPattern = "/subscriptions/delete"
+								r.args = args
+								r.count = 0
+								return r, true
+							default:
+								return
+							}
+						}
+
+					case 'i': // Prefix: "iveries"
+
+						if l := len("iveries"); len(elem) >= l && elem[0:l] == "iveries" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch method {
+							case "GET":
+								r.name = SubscriptionsDeliveriesGetOperation
+								r.summary = "Журнал доставок подписки, новые первыми"
+								r.operationID = ""
+								r.pathPattern = "/subscriptions/deliveries"
+								r.args = args
+								r.count = 0
+								return r, true
+							default:
+								return
+							}
+						}
+
+					}
+
+				case 'l': // Prefix: "list"
 
-					if l := len("reassign"); len(elem) >= l && elem[0:l] == "reassign" {
+					if l := len("list"); len(elem) >= l && elem[0:l] == "list" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "GET":
+							r.name = SubscriptionsListGetOperation
+							r.summary = "Список подписок организации (без ключей подписи)"
+							r.operationID = ""
+							r.pathPattern = "/subscriptions/list"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
+
+				case 'r': // Prefix: "redeliver"
+
+					if l := len("redeliver"); len(elem) >= l && elem[0:l] == "redeliver" {
 						elem = elem[l:]
 					} else {
 						break
@@ -408,10 +1024,10 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 						// Leaf node.
 						switch method {
 						case "POST":
-							r.name = PullRequestReassignPostOperation
-							r.summary = "Переназначить конкретного ревьювера на другого из его команды"
+							r.name = SubscriptionsRedeliverPostOperation
+							r.summary = "Отправить событие доставки повторно (создаёт новую доставку)"
 							r.operationID = ""
-							r.pathPattern = "/pullRequest/reassign"
+							r.pathPattern = "/subscriptions/redeliver"
 							r.args = args
 							r.count = 0
 							return r, true
@@ -482,6 +1098,30 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 						}
 					}
 
+				case 's': // Prefix: "setPolicy"
+
+					if l := len("setPolicy"); len(elem) >= l && elem[0:l] == "setPolicy" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "POST":
+							r.name = TeamSetPolicyPostOperation
+							r.summary = "Включить или выключить правило четырёх глаз для мерджа PR авторов команды"
+							r.operationID = ""
+							r.pathPattern = "/team/setPolicy"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
+
 				}
 
 			case 'u': // Prefix: "users/"
@@ -496,52 +1136,152 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 					break
 				}
 				switch elem[0] {
-				case 'g': // Prefix: "getReview"
+				case 'g': // Prefix: "get"
 
-					if l := len("getReview"); len(elem) >= l && elem[0:l] == "getReview" {
+					if l := len("get"); len(elem) >= l && elem[0:l] == "get" {
 						elem = elem[l:]
 					} else {
 						break
 					}
 
 					if len(elem) == 0 {
-						// Leaf node.
-						switch method {
-						case "GET":
-							r.name = UsersGetReviewGetOperation
-							r.summary = "Получить PR'ы, где пользователь назначен ревьювером"
-							r.operationID = ""
-							r.pathPattern = "/users/getReview"
-							r.args = args
-							r.count = 0
-							return r, true
-						default:
-							return
+						break
+					}
+					switch elem[0] {
+					case 'N': // Prefix: "NotificationPrefs"
+
+						if l := len("NotificationPrefs"); len(elem) >= l && elem[0:l] == "NotificationPrefs" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch method {
+							case "GET":
+								r.name = UsersGetNotificationPrefsGetOperation
+								r.summary = "Получить настройки email-уведомлений пользователя"
+								r.operationID = ""
+								r.pathPattern = "/users/getNotificationPrefs"
+								r.args = args
+								r.count = 0
+								return r, true
+							default:
+								return
+							}
+						}
+
+					case 'R': // Prefix: "Review"
+
+						if l := len("Review"); len(elem) >= l && elem[0:l] == "Review" {
+							elem = elem[l:]
+						} else {
+							break
 						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch method {
+							case "GET":
+								r.name = UsersGetReviewGetOperation
+								r.summary = "Получить PR'ы, где пользователь назначен ревьювером"
+								r.operationID = ""
+								r.pathPattern = "/users/getReview"
+								r.args = args
+								r.count = 0
+								return r, true
+							default:
+								return
+							}
+						}
+
 					}
 
-				case 's': // Prefix: "setIsActive"
+				case 's': // Prefix: "set"
 
-					if l := len("setIsActive"); len(elem) >= l && elem[0:l] == "setIsActive" {
+					if l := len("set"); len(elem) >= l && elem[0:l] == "set" {
 						elem = elem[l:]
 					} else {
 						break
 					}
 
 					if len(elem) == 0 {
-						// Leaf node.
-						switch method {
-						case "POST":
-							r.name = UsersSetIsActivePostOperation
-							r.summary = "Установить флаг активности пользователя"
-							r.operationID = ""
-							r.pathPattern = "/users/setIsActive"
-							r.args = args
-							r.count = 0
-							return r, true
-						default:
-							return
+						break
+					}
+					switch elem[0] {
+					case 'A': // Prefix: "Away"
+
+						if l := len("Away"); len(elem) >= l && elem[0:l] == "Away" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch method {
+							case "POST":
+								r.name = UsersSetAwayPostOperation
+								r.summary = "Отметить отсутствие пользователя (отпуск, больничный)"
+								r.operationID = ""
+								r.pathPattern = "/users/setAway"
+								r.args = args
+								r.count = 0
+								return r, true
+							default:
+								return
+							}
+						}
+
+					case 'I': // Prefix: "IsActive"
+
+						if l := len("IsActive"); len(elem) >= l && elem[0:l] == "IsActive" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch method {
+							case "POST":
+								r.name = UsersSetIsActivePostOperation
+								r.summary = "Установить флаг активности пользователя"
+								r.operationID = ""
+								r.pathPattern = "/users/setIsActive"
+								r.args = args
+								r.count = 0
+								return r, true
+							default:
+								return
+							}
+						}
+
+					case 'N': // Prefix: "NotificationPrefs"
+
+						if l := len("NotificationPrefs"); len(elem) >= l && elem[0:l] == "NotificationPrefs" {
+							elem = elem[l:]
+						} else {
+							break
 						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch method {
+							case "POST":
+								r.name = UsersSetNotificationPrefsPostOperation
+								r.summary = "Задать настройки email-уведомлений пользователя"
+								r.operationID = ""
+								r.pathPattern = "/users/setNotificationPrefs"
+								r.args = args
+								r.count = 0
+								return r, true
+							default:
+								return
+							}
+						}
+
 					}
 
 				}
@@ -6,8 +6,340 @@ import (
 	"time"
 
 	"github.com/go-faster/errors"
+	"github.com/go-faster/jx"
 )
 
+// Ref: #/components/schemas/AuditEntityType
+type AuditEntityType string
+
+const (
+	AuditEntityTypeTeam AuditEntityType = "team"
+	AuditEntityTypeUser AuditEntityType = "user"
+	AuditEntityTypePr   AuditEntityType = "pr"
+)
+
+// AllValues returns all AuditEntityType values.
+func (AuditEntityType) AllValues() []AuditEntityType {
+	return []AuditEntityType{
+		AuditEntityTypeTeam,
+		AuditEntityTypeUser,
+		AuditEntityTypePr,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s AuditEntityType) MarshalText() ([]byte, error) {
+	switch s {
+	case AuditEntityTypeTeam:
+		return []byte(s), nil
+	case AuditEntityTypeUser:
+		return []byte(s), nil
+	case AuditEntityTypePr:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *AuditEntityType) UnmarshalText(data []byte) error {
+	switch AuditEntityType(data) {
+	case AuditEntityTypeTeam:
+		*s = AuditEntityTypeTeam
+		return nil
+	case AuditEntityTypeUser:
+		*s = AuditEntityTypeUser
+		return nil
+	case AuditEntityTypePr:
+		*s = AuditEntityTypePr
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// Одно изменение: кто, когда и в каком запросе его
+// сделал, состояние
+// сущности до и после. Записи не изменяются и не
+// удаляются.
+// Ref: #/components/schemas/AuditEntry
+type AuditEntry struct {
+	AuditID    int64            `json:"audit_id"`
+	Action     AuditEntryAction `json:"action"`
+	EntityType AuditEntityType  `json:"entity_type"`
+	EntityID   string           `json:"entity_id"`
+	// User_id для пользователей (JWT, чат-команды), `token:<id>` для
+	// API-токенов, `github`/`gitlab` для вебхуков; пусто для CLI.
+	Actor string `json:"actor"`
+	// X-Request-ID запроса, в котором сделано изменение.
+	RequestID string `json:"request_id"`
+	// Состояние до изменения; null, если сущность создана.
+	Before jx.Raw `json:"before"`
+	// Состояние после изменения.
+	After     jx.Raw    `json:"after"`
+	CreatedAt time.Time `json:"created_at"`
+	// Hash предыдущей записи организации.
+	PrevHash string `json:"prev_hash"`
+	// SHA-256 (hex) от prev_hash и полей записи; пусто у записей,
+	// сделанных до появления цепочки.
+	Hash string `json:"hash"`
+}
+
+// GetAuditID returns the value of AuditID.
+func (s *AuditEntry) GetAuditID() int64 {
+	return s.AuditID
+}
+
+// GetAction returns the value of Action.
+func (s *AuditEntry) GetAction() AuditEntryAction {
+	return s.Action
+}
+
+// GetEntityType returns the value of EntityType.
+func (s *AuditEntry) GetEntityType() AuditEntityType {
+	return s.EntityType
+}
+
+// GetEntityID returns the value of EntityID.
+func (s *AuditEntry) GetEntityID() string {
+	return s.EntityID
+}
+
+// GetActor returns the value of Actor.
+func (s *AuditEntry) GetActor() string {
+	return s.Actor
+}
+
+// GetRequestID returns the value of RequestID.
+func (s *AuditEntry) GetRequestID() string {
+	return s.RequestID
+}
+
+// GetBefore returns the value of Before.
+func (s *AuditEntry) GetBefore() jx.Raw {
+	return s.Before
+}
+
+// GetAfter returns the value of After.
+func (s *AuditEntry) GetAfter() jx.Raw {
+	return s.After
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *AuditEntry) GetCreatedAt() time.Time {
+	return s.CreatedAt
+}
+
+// GetPrevHash returns the value of PrevHash.
+func (s *AuditEntry) GetPrevHash() string {
+	return s.PrevHash
+}
+
+// GetHash returns the value of Hash.
+func (s *AuditEntry) GetHash() string {
+	return s.Hash
+}
+
+// SetAuditID sets the value of AuditID.
+func (s *AuditEntry) SetAuditID(val int64) {
+	s.AuditID = val
+}
+
+// SetAction sets the value of Action.
+func (s *AuditEntry) SetAction(val AuditEntryAction) {
+	s.Action = val
+}
+
+// SetEntityType sets the value of EntityType.
+func (s *AuditEntry) SetEntityType(val AuditEntityType) {
+	s.EntityType = val
+}
+
+// SetEntityID sets the value of EntityID.
+func (s *AuditEntry) SetEntityID(val string) {
+	s.EntityID = val
+}
+
+// SetActor sets the value of Actor.
+func (s *AuditEntry) SetActor(val string) {
+	s.Actor = val
+}
+
+// SetRequestID sets the value of RequestID.
+func (s *AuditEntry) SetRequestID(val string) {
+	s.RequestID = val
+}
+
+// SetBefore sets the value of Before.
+func (s *AuditEntry) SetBefore(val jx.Raw) {
+	s.Before = val
+}
+
+// SetAfter sets the value of After.
+func (s *AuditEntry) SetAfter(val jx.Raw) {
+	s.After = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *AuditEntry) SetCreatedAt(val time.Time) {
+	s.CreatedAt = val
+}
+
+// SetPrevHash sets the value of PrevHash.
+func (s *AuditEntry) SetPrevHash(val string) {
+	s.PrevHash = val
+}
+
+// SetHash sets the value of Hash.
+func (s *AuditEntry) SetHash(val string) {
+	s.Hash = val
+}
+
+type AuditEntryAction string
+
+const (
+	AuditEntryActionTeamCreated         AuditEntryAction = "team.created"
+	AuditEntryActionTeamPolicyChanged   AuditEntryAction = "team.policy_changed"
+	AuditEntryActionTeamDeleted         AuditEntryAction = "team.deleted"
+	AuditEntryActionUserUpserted        AuditEntryAction = "user.upserted"
+	AuditEntryActionUserActivityChanged AuditEntryAction = "user.activity_changed"
+	AuditEntryActionPrCreated           AuditEntryAction = "pr.created"
+	AuditEntryActionPrReassigned        AuditEntryAction = "pr.reassigned"
+	AuditEntryActionPrReviewed          AuditEntryAction = "pr.reviewed"
+	AuditEntryActionPrMerged            AuditEntryAction = "pr.merged"
+	AuditEntryActionPrClosed            AuditEntryAction = "pr.closed"
+	AuditEntryActionPrReopened          AuditEntryAction = "pr.reopened"
+)
+
+// AllValues returns all AuditEntryAction values.
+func (AuditEntryAction) AllValues() []AuditEntryAction {
+	return []AuditEntryAction{
+		AuditEntryActionTeamCreated,
+		AuditEntryActionTeamPolicyChanged,
+		AuditEntryActionTeamDeleted,
+		AuditEntryActionUserUpserted,
+		AuditEntryActionUserActivityChanged,
+		AuditEntryActionPrCreated,
+		AuditEntryActionPrReassigned,
+		AuditEntryActionPrReviewed,
+		AuditEntryActionPrMerged,
+		AuditEntryActionPrClosed,
+		AuditEntryActionPrReopened,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s AuditEntryAction) MarshalText() ([]byte, error) {
+	switch s {
+	case AuditEntryActionTeamCreated:
+		return []byte(s), nil
+	case AuditEntryActionTeamPolicyChanged:
+		return []byte(s), nil
+	case AuditEntryActionTeamDeleted:
+		return []byte(s), nil
+	case AuditEntryActionUserUpserted:
+		return []byte(s), nil
+	case AuditEntryActionUserActivityChanged:
+		return []byte(s), nil
+	case AuditEntryActionPrCreated:
+		return []byte(s), nil
+	case AuditEntryActionPrReassigned:
+		return []byte(s), nil
+	case AuditEntryActionPrReviewed:
+		return []byte(s), nil
+	case AuditEntryActionPrMerged:
+		return []byte(s), nil
+	case AuditEntryActionPrClosed:
+		return []byte(s), nil
+	case AuditEntryActionPrReopened:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *AuditEntryAction) UnmarshalText(data []byte) error {
+	switch AuditEntryAction(data) {
+	case AuditEntryActionTeamCreated:
+		*s = AuditEntryActionTeamCreated
+		return nil
+	case AuditEntryActionTeamPolicyChanged:
+		*s = AuditEntryActionTeamPolicyChanged
+		return nil
+	case AuditEntryActionTeamDeleted:
+		*s = AuditEntryActionTeamDeleted
+		return nil
+	case AuditEntryActionUserUpserted:
+		*s = AuditEntryActionUserUpserted
+		return nil
+	case AuditEntryActionUserActivityChanged:
+		*s = AuditEntryActionUserActivityChanged
+		return nil
+	case AuditEntryActionPrCreated:
+		*s = AuditEntryActionPrCreated
+		return nil
+	case AuditEntryActionPrReassigned:
+		*s = AuditEntryActionPrReassigned
+		return nil
+	case AuditEntryActionPrReviewed:
+		*s = AuditEntryActionPrReviewed
+		return nil
+	case AuditEntryActionPrMerged:
+		*s = AuditEntryActionPrMerged
+		return nil
+	case AuditEntryActionPrClosed:
+		*s = AuditEntryActionPrClosed
+		return nil
+	case AuditEntryActionPrReopened:
+		*s = AuditEntryActionPrReopened
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+type AuditListGetOK struct {
+	Entries []AuditEntry `json:"entries"`
+}
+
+// GetEntries returns the value of Entries.
+func (s *AuditListGetOK) GetEntries() []AuditEntry {
+	return s.Entries
+}
+
+// SetEntries sets the value of Entries.
+func (s *AuditListGetOK) SetEntries(val []AuditEntry) {
+	s.Entries = val
+}
+
+func (*AuditListGetOK) auditListGetRes() {}
+
+type BearerAuth struct {
+	Token string
+	Roles []string
+}
+
+// GetToken returns the value of Token.
+func (s *BearerAuth) GetToken() string {
+	return s.Token
+}
+
+// GetRoles returns the value of Roles.
+func (s *BearerAuth) GetRoles() []string {
+	return s.Roles
+}
+
+// SetToken sets the value of Token.
+func (s *BearerAuth) SetToken(val string) {
+	s.Token = val
+}
+
+// SetRoles sets the value of Roles.
+func (s *BearerAuth) SetRoles(val []string) {
+	s.Roles = val
+}
+
 // Ref: #/components/schemas/ErrorResponse
 type ErrorResponse struct {
 	Error ErrorResponseError `json:"error"`
@@ -23,10 +355,15 @@ func (s *ErrorResponse) SetError(val ErrorResponseError) {
 	s.Error = val
 }
 
-func (*ErrorResponse) pullRequestMergePostRes() {}
-func (*ErrorResponse) teamAddPostRes()          {}
-func (*ErrorResponse) teamGetGetRes()           {}
-func (*ErrorResponse) usersSetIsActivePostRes() {}
+func (*ErrorResponse) auditExportGetRes()               {}
+func (*ErrorResponse) auditListGetRes()                 {}
+func (*ErrorResponse) pullRequestHistoryGetRes()        {}
+func (*ErrorResponse) subscriptionsCreatePostRes()      {}
+func (*ErrorResponse) subscriptionsDeletePostRes()      {}
+func (*ErrorResponse) subscriptionsDeliveriesGetRes()   {}
+func (*ErrorResponse) subscriptionsRedeliverPostRes()   {}
+func (*ErrorResponse) teamGetGetRes()                   {}
+func (*ErrorResponse) usersGetNotificationPrefsGetRes() {}
 
 type ErrorResponseError struct {
 	Code    ErrorResponseErrorCode `json:"code"`
@@ -56,12 +393,19 @@ func (s *ErrorResponseError) SetMessage(val string) {
 type ErrorResponseErrorCode string
 
 const (
-	ErrorResponseErrorCodeTEAMEXISTS  ErrorResponseErrorCode = "TEAM_EXISTS"
-	ErrorResponseErrorCodePREXISTS    ErrorResponseErrorCode = "PR_EXISTS"
-	ErrorResponseErrorCodePRMERGED    ErrorResponseErrorCode = "PR_MERGED"
-	ErrorResponseErrorCodeNOTASSIGNED ErrorResponseErrorCode = "NOT_ASSIGNED"
-	ErrorResponseErrorCodeNOCANDIDATE ErrorResponseErrorCode = "NO_CANDIDATE"
-	ErrorResponseErrorCodeNOTFOUND    ErrorResponseErrorCode = "NOT_FOUND"
+	ErrorResponseErrorCodeTEAMEXISTS            ErrorResponseErrorCode = "TEAM_EXISTS"
+	ErrorResponseErrorCodePREXISTS              ErrorResponseErrorCode = "PR_EXISTS"
+	ErrorResponseErrorCodePRMERGED              ErrorResponseErrorCode = "PR_MERGED"
+	ErrorResponseErrorCodePRCLOSED              ErrorResponseErrorCode = "PR_CLOSED"
+	ErrorResponseErrorCodeNOTASSIGNED           ErrorResponseErrorCode = "NOT_ASSIGNED"
+	ErrorResponseErrorCodeNOCANDIDATE           ErrorResponseErrorCode = "NO_CANDIDATE"
+	ErrorResponseErrorCodeNOTFOUND              ErrorResponseErrorCode = "NOT_FOUND"
+	ErrorResponseErrorCodeFORBIDDEN             ErrorResponseErrorCode = "FORBIDDEN"
+	ErrorResponseErrorCodeINVALIDARGUMENT       ErrorResponseErrorCode = "INVALID_ARGUMENT"
+	ErrorResponseErrorCodeFOUREYESVIOLATION     ErrorResponseErrorCode = "FOUR_EYES_VIOLATION"
+	ErrorResponseErrorCodeIDEMPOTENCYKEYREUSED  ErrorResponseErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	ErrorResponseErrorCodeIDEMPOTENCYINPROGRESS ErrorResponseErrorCode = "IDEMPOTENCY_IN_PROGRESS"
+	ErrorResponseErrorCodeVERSIONMISMATCH       ErrorResponseErrorCode = "VERSION_MISMATCH"
 )
 
 // AllValues returns all ErrorResponseErrorCode values.
@@ -70,9 +414,16 @@ func (ErrorResponseErrorCode) AllValues() []ErrorResponseErrorCode {
 		ErrorResponseErrorCodeTEAMEXISTS,
 		ErrorResponseErrorCodePREXISTS,
 		ErrorResponseErrorCodePRMERGED,
+		ErrorResponseErrorCodePRCLOSED,
 		ErrorResponseErrorCodeNOTASSIGNED,
 		ErrorResponseErrorCodeNOCANDIDATE,
 		ErrorResponseErrorCodeNOTFOUND,
+		ErrorResponseErrorCodeFORBIDDEN,
+		ErrorResponseErrorCodeINVALIDARGUMENT,
+		ErrorResponseErrorCodeFOUREYESVIOLATION,
+		ErrorResponseErrorCodeIDEMPOTENCYKEYREUSED,
+		ErrorResponseErrorCodeIDEMPOTENCYINPROGRESS,
+		ErrorResponseErrorCodeVERSIONMISMATCH,
 	}
 }
 
@@ -85,12 +436,26 @@ func (s ErrorResponseErrorCode) MarshalText() ([]byte, error) {
 		return []byte(s), nil
 	case ErrorResponseErrorCodePRMERGED:
 		return []byte(s), nil
+	case ErrorResponseErrorCodePRCLOSED:
+		return []byte(s), nil
 	case ErrorResponseErrorCodeNOTASSIGNED:
 		return []byte(s), nil
 	case ErrorResponseErrorCodeNOCANDIDATE:
 		return []byte(s), nil
 	case ErrorResponseErrorCodeNOTFOUND:
 		return []byte(s), nil
+	case ErrorResponseErrorCodeFORBIDDEN:
+		return []byte(s), nil
+	case ErrorResponseErrorCodeINVALIDARGUMENT:
+		return []byte(s), nil
+	case ErrorResponseErrorCodeFOUREYESVIOLATION:
+		return []byte(s), nil
+	case ErrorResponseErrorCodeIDEMPOTENCYKEYREUSED:
+		return []byte(s), nil
+	case ErrorResponseErrorCodeIDEMPOTENCYINPROGRESS:
+		return []byte(s), nil
+	case ErrorResponseErrorCodeVERSIONMISMATCH:
+		return []byte(s), nil
 	default:
 		return nil, errors.Errorf("invalid value: %q", s)
 	}
@@ -108,6 +473,9 @@ func (s *ErrorResponseErrorCode) UnmarshalText(data []byte) error {
 	case ErrorResponseErrorCodePRMERGED:
 		*s = ErrorResponseErrorCodePRMERGED
 		return nil
+	case ErrorResponseErrorCodePRCLOSED:
+		*s = ErrorResponseErrorCodePRCLOSED
+		return nil
 	case ErrorResponseErrorCodeNOTASSIGNED:
 		*s = ErrorResponseErrorCodeNOTASSIGNED
 		return nil
@@ -117,152 +485,380 @@ func (s *ErrorResponseErrorCode) UnmarshalText(data []byte) error {
 	case ErrorResponseErrorCodeNOTFOUND:
 		*s = ErrorResponseErrorCodeNOTFOUND
 		return nil
+	case ErrorResponseErrorCodeFORBIDDEN:
+		*s = ErrorResponseErrorCodeFORBIDDEN
+		return nil
+	case ErrorResponseErrorCodeINVALIDARGUMENT:
+		*s = ErrorResponseErrorCodeINVALIDARGUMENT
+		return nil
+	case ErrorResponseErrorCodeFOUREYESVIOLATION:
+		*s = ErrorResponseErrorCodeFOUREYESVIOLATION
+		return nil
+	case ErrorResponseErrorCodeIDEMPOTENCYKEYREUSED:
+		*s = ErrorResponseErrorCodeIDEMPOTENCYKEYREUSED
+		return nil
+	case ErrorResponseErrorCodeIDEMPOTENCYINPROGRESS:
+		*s = ErrorResponseErrorCodeIDEMPOTENCYINPROGRESS
+		return nil
+	case ErrorResponseErrorCodeVERSIONMISMATCH:
+		*s = ErrorResponseErrorCodeVERSIONMISMATCH
+		return nil
 	default:
 		return errors.Errorf("invalid value: %q", data)
 	}
 }
 
-// NewOptNilDateTime returns new OptNilDateTime with value set to v.
-func NewOptNilDateTime(v time.Time) OptNilDateTime {
-	return OptNilDateTime{
-		Value: v,
-		Set:   true,
-	}
+// Ref: #/components/schemas/EventDelivery
+type EventDelivery struct {
+	DeliveryID     int64               `json:"delivery_id"`
+	SubscriptionID string              `json:"subscription_id"`
+	EventID        string              `json:"event_id"`
+	EventType      EventType           `json:"event_type"`
+	Status         EventDeliveryStatus `json:"status"`
+	Attempts       int                 `json:"attempts"`
+	// HTTP-статус последней попытки, 0 — ответа не было.
+	ResponseCode  int       `json:"response_code"`
+	LastError     string    `json:"last_error"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
-// OptNilDateTime is optional nullable time.Time.
-type OptNilDateTime struct {
-	Value time.Time
-	Set   bool
-	Null  bool
+// GetDeliveryID returns the value of DeliveryID.
+func (s *EventDelivery) GetDeliveryID() int64 {
+	return s.DeliveryID
 }
 
-// IsSet returns true if OptNilDateTime was set.
-func (o OptNilDateTime) IsSet() bool { return o.Set }
+// GetSubscriptionID returns the value of SubscriptionID.
+func (s *EventDelivery) GetSubscriptionID() string {
+	return s.SubscriptionID
+}
 
-// Reset unsets value.
-func (o *OptNilDateTime) Reset() {
-	var v time.Time
-	o.Value = v
-	o.Set = false
-	o.Null = false
+// GetEventID returns the value of EventID.
+func (s *EventDelivery) GetEventID() string {
+	return s.EventID
 }
 
-// SetTo sets value to v.
-func (o *OptNilDateTime) SetTo(v time.Time) {
-	o.Set = true
-	o.Null = false
-	o.Value = v
+// GetEventType returns the value of EventType.
+func (s *EventDelivery) GetEventType() EventType {
+	return s.EventType
 }
 
-// IsNull returns true if value is Null.
-func (o OptNilDateTime) IsNull() bool { return o.Null }
+// GetStatus returns the value of Status.
+func (s *EventDelivery) GetStatus() EventDeliveryStatus {
+	return s.Status
+}
 
-// SetToNull sets value to null.
-func (o *OptNilDateTime) SetToNull() {
-	o.Set = true
-	o.Null = true
-	var v time.Time
-	o.Value = v
+// GetAttempts returns the value of Attempts.
+func (s *EventDelivery) GetAttempts() int {
+	return s.Attempts
 }
 
-// Get returns value and boolean that denotes whether value was set.
-func (o OptNilDateTime) Get() (v time.Time, ok bool) {
-	if o.Null {
-		return v, false
-	}
-	if !o.Set {
-		return v, false
-	}
-	return o.Value, true
+// GetResponseCode returns the value of ResponseCode.
+func (s *EventDelivery) GetResponseCode() int {
+	return s.ResponseCode
 }
 
-// Or returns value if set, or given parameter if does not.
-func (o OptNilDateTime) Or(d time.Time) time.Time {
-	if v, ok := o.Get(); ok {
-		return v
-	}
-	return d
+// GetLastError returns the value of LastError.
+func (s *EventDelivery) GetLastError() string {
+	return s.LastError
 }
 
-// NewOptPullRequest returns new OptPullRequest with value set to v.
-func NewOptPullRequest(v PullRequest) OptPullRequest {
-	return OptPullRequest{
-		Value: v,
-		Set:   true,
-	}
+// GetNextAttemptAt returns the value of NextAttemptAt.
+func (s *EventDelivery) GetNextAttemptAt() time.Time {
+	return s.NextAttemptAt
 }
 
-// OptPullRequest is optional PullRequest.
-type OptPullRequest struct {
-	Value PullRequest
-	Set   bool
+// GetCreatedAt returns the value of CreatedAt.
+func (s *EventDelivery) GetCreatedAt() time.Time {
+	return s.CreatedAt
 }
 
-// IsSet returns true if OptPullRequest was set.
-func (o OptPullRequest) IsSet() bool { return o.Set }
+// GetUpdatedAt returns the value of UpdatedAt.
+func (s *EventDelivery) GetUpdatedAt() time.Time {
+	return s.UpdatedAt
+}
 
-// Reset unsets value.
-func (o *OptPullRequest) Reset() {
-	var v PullRequest
-	o.Value = v
-	o.Set = false
+// SetDeliveryID sets the value of DeliveryID.
+func (s *EventDelivery) SetDeliveryID(val int64) {
+	s.DeliveryID = val
 }
 
-// SetTo sets value to v.
-func (o *OptPullRequest) SetTo(v PullRequest) {
-	o.Set = true
-	o.Value = v
+// SetSubscriptionID sets the value of SubscriptionID.
+func (s *EventDelivery) SetSubscriptionID(val string) {
+	s.SubscriptionID = val
 }
 
-// Get returns value and boolean that denotes whether value was set.
-func (o OptPullRequest) Get() (v PullRequest, ok bool) {
-	if !o.Set {
-		return v, false
-	}
-	return o.Value, true
+// SetEventID sets the value of EventID.
+func (s *EventDelivery) SetEventID(val string) {
+	s.EventID = val
 }
 
-// Or returns value if set, or given parameter if does not.
-func (o OptPullRequest) Or(d PullRequest) PullRequest {
-	if v, ok := o.Get(); ok {
-		return v
-	}
-	return d
+// SetEventType sets the value of EventType.
+func (s *EventDelivery) SetEventType(val EventType) {
+	s.EventType = val
 }
 
-// NewOptTeam returns new OptTeam with value set to v.
-func NewOptTeam(v Team) OptTeam {
-	return OptTeam{
-		Value: v,
-		Set:   true,
-	}
+// SetStatus sets the value of Status.
+func (s *EventDelivery) SetStatus(val EventDeliveryStatus) {
+	s.Status = val
 }
 
-// OptTeam is optional Team.
-type OptTeam struct {
-	Value Team
-	Set   bool
+// SetAttempts sets the value of Attempts.
+func (s *EventDelivery) SetAttempts(val int) {
+	s.Attempts = val
 }
 
-// IsSet returns true if OptTeam was set.
-func (o OptTeam) IsSet() bool { return o.Set }
+// SetResponseCode sets the value of ResponseCode.
+func (s *EventDelivery) SetResponseCode(val int) {
+	s.ResponseCode = val
+}
 
-// Reset unsets value.
-func (o *OptTeam) Reset() {
-	var v Team
-	o.Value = v
-	o.Set = false
+// SetLastError sets the value of LastError.
+func (s *EventDelivery) SetLastError(val string) {
+	s.LastError = val
+}
+
+// SetNextAttemptAt sets the value of NextAttemptAt.
+func (s *EventDelivery) SetNextAttemptAt(val time.Time) {
+	s.NextAttemptAt = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *EventDelivery) SetCreatedAt(val time.Time) {
+	s.CreatedAt = val
+}
+
+// SetUpdatedAt sets the value of UpdatedAt.
+func (s *EventDelivery) SetUpdatedAt(val time.Time) {
+	s.UpdatedAt = val
+}
+
+type EventDeliveryStatus string
+
+const (
+	EventDeliveryStatusPending   EventDeliveryStatus = "pending"
+	EventDeliveryStatusDelivered EventDeliveryStatus = "delivered"
+	EventDeliveryStatusFailed    EventDeliveryStatus = "failed"
+)
+
+// AllValues returns all EventDeliveryStatus values.
+func (EventDeliveryStatus) AllValues() []EventDeliveryStatus {
+	return []EventDeliveryStatus{
+		EventDeliveryStatusPending,
+		EventDeliveryStatusDelivered,
+		EventDeliveryStatusFailed,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s EventDeliveryStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case EventDeliveryStatusPending:
+		return []byte(s), nil
+	case EventDeliveryStatusDelivered:
+		return []byte(s), nil
+	case EventDeliveryStatusFailed:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *EventDeliveryStatus) UnmarshalText(data []byte) error {
+	switch EventDeliveryStatus(data) {
+	case EventDeliveryStatusPending:
+		*s = EventDeliveryStatusPending
+		return nil
+	case EventDeliveryStatusDelivered:
+		*s = EventDeliveryStatusDelivered
+		return nil
+	case EventDeliveryStatusFailed:
+		*s = EventDeliveryStatusFailed
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// Ref: #/components/schemas/EventType
+type EventType string
+
+const (
+	EventTypePrCreated           EventType = "pr.created"
+	EventTypePrReassigned        EventType = "pr.reassigned"
+	EventTypePrReviewed          EventType = "pr.reviewed"
+	EventTypePrMerged            EventType = "pr.merged"
+	EventTypePrClosed            EventType = "pr.closed"
+	EventTypePrReopened          EventType = "pr.reopened"
+	EventTypeUserActivityChanged EventType = "user.activity_changed"
+)
+
+// AllValues returns all EventType values.
+func (EventType) AllValues() []EventType {
+	return []EventType{
+		EventTypePrCreated,
+		EventTypePrReassigned,
+		EventTypePrReviewed,
+		EventTypePrMerged,
+		EventTypePrClosed,
+		EventTypePrReopened,
+		EventTypeUserActivityChanged,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s EventType) MarshalText() ([]byte, error) {
+	switch s {
+	case EventTypePrCreated:
+		return []byte(s), nil
+	case EventTypePrReassigned:
+		return []byte(s), nil
+	case EventTypePrReviewed:
+		return []byte(s), nil
+	case EventTypePrMerged:
+		return []byte(s), nil
+	case EventTypePrClosed:
+		return []byte(s), nil
+	case EventTypePrReopened:
+		return []byte(s), nil
+	case EventTypeUserActivityChanged:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *EventType) UnmarshalText(data []byte) error {
+	switch EventType(data) {
+	case EventTypePrCreated:
+		*s = EventTypePrCreated
+		return nil
+	case EventTypePrReassigned:
+		*s = EventTypePrReassigned
+		return nil
+	case EventTypePrReviewed:
+		*s = EventTypePrReviewed
+		return nil
+	case EventTypePrMerged:
+		*s = EventTypePrMerged
+		return nil
+	case EventTypePrClosed:
+		*s = EventTypePrClosed
+		return nil
+	case EventTypePrReopened:
+		*s = EventTypePrReopened
+		return nil
+	case EventTypeUserActivityChanged:
+		*s = EventTypeUserActivityChanged
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// Какие письма получает пользователь; по умолчанию все.
+// Ref: #/components/schemas/NotificationPrefs
+type NotificationPrefs struct {
+	UserID string `json:"user_id"`
+	// Назначен ревьювером нового PR.
+	Assigned bool `json:"assigned"`
+	// Получил PR вместо другого ревьювера.
+	Reassigned bool `json:"reassigned"`
+	// PR открыт дольше EMAIL_SLA.
+	SLABreach bool `json:"sla_breach"`
+	// Ежедневный список открытых ревью; если не указано —
+	// true.
+	DailyDigest OptBool `json:"daily_digest"`
+}
+
+// GetUserID returns the value of UserID.
+func (s *NotificationPrefs) GetUserID() string {
+	return s.UserID
+}
+
+// GetAssigned returns the value of Assigned.
+func (s *NotificationPrefs) GetAssigned() bool {
+	return s.Assigned
+}
+
+// GetReassigned returns the value of Reassigned.
+func (s *NotificationPrefs) GetReassigned() bool {
+	return s.Reassigned
+}
+
+// GetSLABreach returns the value of SLABreach.
+func (s *NotificationPrefs) GetSLABreach() bool {
+	return s.SLABreach
+}
+
+// GetDailyDigest returns the value of DailyDigest.
+func (s *NotificationPrefs) GetDailyDigest() OptBool {
+	return s.DailyDigest
+}
+
+// SetUserID sets the value of UserID.
+func (s *NotificationPrefs) SetUserID(val string) {
+	s.UserID = val
+}
+
+// SetAssigned sets the value of Assigned.
+func (s *NotificationPrefs) SetAssigned(val bool) {
+	s.Assigned = val
+}
+
+// SetReassigned sets the value of Reassigned.
+func (s *NotificationPrefs) SetReassigned(val bool) {
+	s.Reassigned = val
+}
+
+// SetSLABreach sets the value of SLABreach.
+func (s *NotificationPrefs) SetSLABreach(val bool) {
+	s.SLABreach = val
+}
+
+// SetDailyDigest sets the value of DailyDigest.
+func (s *NotificationPrefs) SetDailyDigest(val OptBool) {
+	s.DailyDigest = val
+}
+
+func (*NotificationPrefs) usersGetNotificationPrefsGetRes()  {}
+func (*NotificationPrefs) usersSetNotificationPrefsPostRes() {}
+
+// NewOptAuditEntityType returns new OptAuditEntityType with value set to v.
+func NewOptAuditEntityType(v AuditEntityType) OptAuditEntityType {
+	return OptAuditEntityType{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptAuditEntityType is optional AuditEntityType.
+type OptAuditEntityType struct {
+	Value AuditEntityType
+	Set   bool
+}
+
+// IsSet returns true if OptAuditEntityType was set.
+func (o OptAuditEntityType) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptAuditEntityType) Reset() {
+	var v AuditEntityType
+	o.Value = v
+	o.Set = false
 }
 
 // SetTo sets value to v.
-func (o *OptTeam) SetTo(v Team) {
+func (o *OptAuditEntityType) SetTo(v AuditEntityType) {
 	o.Set = true
 	o.Value = v
 }
 
 // Get returns value and boolean that denotes whether value was set.
-func (o OptTeam) Get() (v Team, ok bool) {
+func (o OptAuditEntityType) Get() (v AuditEntityType, ok bool) {
 	if !o.Set {
 		return v, false
 	}
@@ -270,425 +866,1740 @@ func (o OptTeam) Get() (v Team, ok bool) {
 }
 
 // Or returns value if set, or given parameter if does not.
-func (o OptTeam) Or(d Team) Team {
+func (o OptAuditEntityType) Or(d AuditEntityType) AuditEntityType {
 	if v, ok := o.Get(); ok {
 		return v
 	}
 	return d
 }
 
-// NewOptUser returns new OptUser with value set to v.
-func NewOptUser(v User) OptUser {
-	return OptUser{
+// NewOptBool returns new OptBool with value set to v.
+func NewOptBool(v bool) OptBool {
+	return OptBool{
 		Value: v,
 		Set:   true,
 	}
 }
 
-// OptUser is optional User.
-type OptUser struct {
-	Value User
+// OptBool is optional bool.
+type OptBool struct {
+	Value bool
 	Set   bool
 }
 
-// IsSet returns true if OptUser was set.
-func (o OptUser) IsSet() bool { return o.Set }
+// IsSet returns true if OptBool was set.
+func (o OptBool) IsSet() bool { return o.Set }
 
 // Reset unsets value.
-func (o *OptUser) Reset() {
-	var v User
+func (o *OptBool) Reset() {
+	var v bool
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptBool) SetTo(v bool) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptBool) Get() (v bool, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptBool) Or(d bool) bool {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptDateTime returns new OptDateTime with value set to v.
+func NewOptDateTime(v time.Time) OptDateTime {
+	return OptDateTime{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptDateTime is optional time.Time.
+type OptDateTime struct {
+	Value time.Time
+	Set   bool
+}
+
+// IsSet returns true if OptDateTime was set.
+func (o OptDateTime) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptDateTime) Reset() {
+	var v time.Time
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptDateTime) SetTo(v time.Time) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptDateTime) Get() (v time.Time, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptDateTime) Or(d time.Time) time.Time {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptInt returns new OptInt with value set to v.
+func NewOptInt(v int) OptInt {
+	return OptInt{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptInt is optional int.
+type OptInt struct {
+	Value int
+	Set   bool
+}
+
+// IsSet returns true if OptInt was set.
+func (o OptInt) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptInt) Reset() {
+	var v int
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptInt) SetTo(v int) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptInt) Get() (v int, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptInt) Or(d int) int {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptInt64 returns new OptInt64 with value set to v.
+func NewOptInt64(v int64) OptInt64 {
+	return OptInt64{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptInt64 is optional int64.
+type OptInt64 struct {
+	Value int64
+	Set   bool
+}
+
+// IsSet returns true if OptInt64 was set.
+func (o OptInt64) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptInt64) Reset() {
+	var v int64
 	o.Value = v
 	o.Set = false
 }
 
-// SetTo sets value to v.
-func (o *OptUser) SetTo(v User) {
-	o.Set = true
-	o.Value = v
+// SetTo sets value to v.
+func (o *OptInt64) SetTo(v int64) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptInt64) Get() (v int64, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptInt64) Or(d int64) int64 {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptNilDateTime returns new OptNilDateTime with value set to v.
+func NewOptNilDateTime(v time.Time) OptNilDateTime {
+	return OptNilDateTime{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptNilDateTime is optional nullable time.Time.
+type OptNilDateTime struct {
+	Value time.Time
+	Set   bool
+	Null  bool
+}
+
+// IsSet returns true if OptNilDateTime was set.
+func (o OptNilDateTime) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptNilDateTime) Reset() {
+	var v time.Time
+	o.Value = v
+	o.Set = false
+	o.Null = false
+}
+
+// SetTo sets value to v.
+func (o *OptNilDateTime) SetTo(v time.Time) {
+	o.Set = true
+	o.Null = false
+	o.Value = v
+}
+
+// IsNull returns true if value is Null.
+func (o OptNilDateTime) IsNull() bool { return o.Null }
+
+// SetToNull sets value to null.
+func (o *OptNilDateTime) SetToNull() {
+	o.Set = true
+	o.Null = true
+	var v time.Time
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptNilDateTime) Get() (v time.Time, ok bool) {
+	if o.Null {
+		return v, false
+	}
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptNilDateTime) Or(d time.Time) time.Time {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptPullRequest returns new OptPullRequest with value set to v.
+func NewOptPullRequest(v PullRequest) OptPullRequest {
+	return OptPullRequest{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptPullRequest is optional PullRequest.
+type OptPullRequest struct {
+	Value PullRequest
+	Set   bool
+}
+
+// IsSet returns true if OptPullRequest was set.
+func (o OptPullRequest) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptPullRequest) Reset() {
+	var v PullRequest
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptPullRequest) SetTo(v PullRequest) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptPullRequest) Get() (v PullRequest, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptPullRequest) Or(d PullRequest) PullRequest {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptReviewVerdict returns new OptReviewVerdict with value set to v.
+func NewOptReviewVerdict(v ReviewVerdict) OptReviewVerdict {
+	return OptReviewVerdict{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptReviewVerdict is optional ReviewVerdict.
+type OptReviewVerdict struct {
+	Value ReviewVerdict
+	Set   bool
+}
+
+// IsSet returns true if OptReviewVerdict was set.
+func (o OptReviewVerdict) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptReviewVerdict) Reset() {
+	var v ReviewVerdict
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptReviewVerdict) SetTo(v ReviewVerdict) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptReviewVerdict) Get() (v ReviewVerdict, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptReviewVerdict) Or(d ReviewVerdict) ReviewVerdict {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptRole returns new OptRole with value set to v.
+func NewOptRole(v Role) OptRole {
+	return OptRole{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptRole is optional Role.
+type OptRole struct {
+	Value Role
+	Set   bool
+}
+
+// IsSet returns true if OptRole was set.
+func (o OptRole) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptRole) Reset() {
+	var v Role
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptRole) SetTo(v Role) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptRole) Get() (v Role, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptRole) Or(d Role) Role {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptString returns new OptString with value set to v.
+func NewOptString(v string) OptString {
+	return OptString{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptString is optional string.
+type OptString struct {
+	Value string
+	Set   bool
+}
+
+// IsSet returns true if OptString was set.
+func (o OptString) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptString) Reset() {
+	var v string
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptString) SetTo(v string) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptString) Get() (v string, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptString) Or(d string) string {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptTeam returns new OptTeam with value set to v.
+func NewOptTeam(v Team) OptTeam {
+	return OptTeam{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptTeam is optional Team.
+type OptTeam struct {
+	Value Team
+	Set   bool
+}
+
+// IsSet returns true if OptTeam was set.
+func (o OptTeam) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptTeam) Reset() {
+	var v Team
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptTeam) SetTo(v Team) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptTeam) Get() (v Team, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptTeam) Or(d Team) Team {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptUser returns new OptUser with value set to v.
+func NewOptUser(v User) OptUser {
+	return OptUser{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptUser is optional User.
+type OptUser struct {
+	Value User
+	Set   bool
+}
+
+// IsSet returns true if OptUser was set.
+func (o OptUser) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptUser) Reset() {
+	var v User
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptUser) SetTo(v User) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptUser) Get() (v User, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptUser) Or(d User) User {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// Событие PR. assigned_reviewers — ревьюверы после события; для
+// pr.reassigned заполнены old_reviewer_id, new_reviewer_id и reason,
+// если причину указали, для pr.reviewed — reviewer_id и verdict, для
+// pr.merged — reason, если merge на платформе нарушил правило
+// четырёх
+// глаз.
+// Ref: #/components/schemas/PRHistoryItem
+type PRHistoryItem struct {
+	EventID    string    `json:"event_id"`
+	Type       EventType `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	// Кто вызвал событие, как actor в журнале аудита.
+	Actor             string              `json:"actor"`
+	Status            PRHistoryItemStatus `json:"status"`
+	AssignedReviewers []string            `json:"assigned_reviewers"`
+	OldReviewerID     OptString           `json:"old_reviewer_id"`
+	NewReviewerID     OptString           `json:"new_reviewer_id"`
+	Reason            OptString           `json:"reason"`
+	ReviewerID        OptString           `json:"reviewer_id"`
+	Verdict           OptReviewVerdict    `json:"verdict"`
+}
+
+// GetEventID returns the value of EventID.
+func (s *PRHistoryItem) GetEventID() string {
+	return s.EventID
+}
+
+// GetType returns the value of Type.
+func (s *PRHistoryItem) GetType() EventType {
+	return s.Type
+}
+
+// GetOccurredAt returns the value of OccurredAt.
+func (s *PRHistoryItem) GetOccurredAt() time.Time {
+	return s.OccurredAt
+}
+
+// GetActor returns the value of Actor.
+func (s *PRHistoryItem) GetActor() string {
+	return s.Actor
+}
+
+// GetStatus returns the value of Status.
+func (s *PRHistoryItem) GetStatus() PRHistoryItemStatus {
+	return s.Status
+}
+
+// GetAssignedReviewers returns the value of AssignedReviewers.
+func (s *PRHistoryItem) GetAssignedReviewers() []string {
+	return s.AssignedReviewers
+}
+
+// GetOldReviewerID returns the value of OldReviewerID.
+func (s *PRHistoryItem) GetOldReviewerID() OptString {
+	return s.OldReviewerID
+}
+
+// GetNewReviewerID returns the value of NewReviewerID.
+func (s *PRHistoryItem) GetNewReviewerID() OptString {
+	return s.NewReviewerID
+}
+
+// GetReason returns the value of Reason.
+func (s *PRHistoryItem) GetReason() OptString {
+	return s.Reason
+}
+
+// GetReviewerID returns the value of ReviewerID.
+func (s *PRHistoryItem) GetReviewerID() OptString {
+	return s.ReviewerID
+}
+
+// GetVerdict returns the value of Verdict.
+func (s *PRHistoryItem) GetVerdict() OptReviewVerdict {
+	return s.Verdict
+}
+
+// SetEventID sets the value of EventID.
+func (s *PRHistoryItem) SetEventID(val string) {
+	s.EventID = val
+}
+
+// SetType sets the value of Type.
+func (s *PRHistoryItem) SetType(val EventType) {
+	s.Type = val
+}
+
+// SetOccurredAt sets the value of OccurredAt.
+func (s *PRHistoryItem) SetOccurredAt(val time.Time) {
+	s.OccurredAt = val
+}
+
+// SetActor sets the value of Actor.
+func (s *PRHistoryItem) SetActor(val string) {
+	s.Actor = val
+}
+
+// SetStatus sets the value of Status.
+func (s *PRHistoryItem) SetStatus(val PRHistoryItemStatus) {
+	s.Status = val
+}
+
+// SetAssignedReviewers sets the value of AssignedReviewers.
+func (s *PRHistoryItem) SetAssignedReviewers(val []string) {
+	s.AssignedReviewers = val
+}
+
+// SetOldReviewerID sets the value of OldReviewerID.
+func (s *PRHistoryItem) SetOldReviewerID(val OptString) {
+	s.OldReviewerID = val
+}
+
+// SetNewReviewerID sets the value of NewReviewerID.
+func (s *PRHistoryItem) SetNewReviewerID(val OptString) {
+	s.NewReviewerID = val
+}
+
+// SetReason sets the value of Reason.
+func (s *PRHistoryItem) SetReason(val OptString) {
+	s.Reason = val
+}
+
+// SetReviewerID sets the value of ReviewerID.
+func (s *PRHistoryItem) SetReviewerID(val OptString) {
+	s.ReviewerID = val
+}
+
+// SetVerdict sets the value of Verdict.
+func (s *PRHistoryItem) SetVerdict(val OptReviewVerdict) {
+	s.Verdict = val
+}
+
+type PRHistoryItemStatus string
+
+const (
+	PRHistoryItemStatusOPEN   PRHistoryItemStatus = "OPEN"
+	PRHistoryItemStatusMERGED PRHistoryItemStatus = "MERGED"
+	PRHistoryItemStatusCLOSED PRHistoryItemStatus = "CLOSED"
+)
+
+// AllValues returns all PRHistoryItemStatus values.
+func (PRHistoryItemStatus) AllValues() []PRHistoryItemStatus {
+	return []PRHistoryItemStatus{
+		PRHistoryItemStatusOPEN,
+		PRHistoryItemStatusMERGED,
+		PRHistoryItemStatusCLOSED,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s PRHistoryItemStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case PRHistoryItemStatusOPEN:
+		return []byte(s), nil
+	case PRHistoryItemStatusMERGED:
+		return []byte(s), nil
+	case PRHistoryItemStatusCLOSED:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *PRHistoryItemStatus) UnmarshalText(data []byte) error {
+	switch PRHistoryItemStatus(data) {
+	case PRHistoryItemStatusOPEN:
+		*s = PRHistoryItemStatusOPEN
+		return nil
+	case PRHistoryItemStatusMERGED:
+		*s = PRHistoryItemStatusMERGED
+		return nil
+	case PRHistoryItemStatusCLOSED:
+		*s = PRHistoryItemStatusCLOSED
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// Ref: #/components/schemas/PullRequest
+type PullRequest struct {
+	PullRequestID   string            `json:"pull_request_id"`
+	PullRequestName string            `json:"pull_request_name"`
+	AuthorID        string            `json:"author_id"`
+	Status          PullRequestStatus `json:"status"`
+	// User_id назначенных ревьюверов (0..2).
+	AssignedReviewers []string       `json:"assigned_reviewers"`
+	CreatedAt         OptNilDateTime `json:"createdAt"`
+	MergedAt          OptNilDateTime `json:"mergedAt"`
+	// Растёт на единицу при каждом изменении PR.
+	Version int64 `json:"version"`
+}
+
+// GetPullRequestID returns the value of PullRequestID.
+func (s *PullRequest) GetPullRequestID() string {
+	return s.PullRequestID
+}
+
+// GetPullRequestName returns the value of PullRequestName.
+func (s *PullRequest) GetPullRequestName() string {
+	return s.PullRequestName
+}
+
+// GetAuthorID returns the value of AuthorID.
+func (s *PullRequest) GetAuthorID() string {
+	return s.AuthorID
+}
+
+// GetStatus returns the value of Status.
+func (s *PullRequest) GetStatus() PullRequestStatus {
+	return s.Status
+}
+
+// GetAssignedReviewers returns the value of AssignedReviewers.
+func (s *PullRequest) GetAssignedReviewers() []string {
+	return s.AssignedReviewers
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *PullRequest) GetCreatedAt() OptNilDateTime {
+	return s.CreatedAt
+}
+
+// GetMergedAt returns the value of MergedAt.
+func (s *PullRequest) GetMergedAt() OptNilDateTime {
+	return s.MergedAt
+}
+
+// GetVersion returns the value of Version.
+func (s *PullRequest) GetVersion() int64 {
+	return s.Version
+}
+
+// SetPullRequestID sets the value of PullRequestID.
+func (s *PullRequest) SetPullRequestID(val string) {
+	s.PullRequestID = val
+}
+
+// SetPullRequestName sets the value of PullRequestName.
+func (s *PullRequest) SetPullRequestName(val string) {
+	s.PullRequestName = val
+}
+
+// SetAuthorID sets the value of AuthorID.
+func (s *PullRequest) SetAuthorID(val string) {
+	s.AuthorID = val
+}
+
+// SetStatus sets the value of Status.
+func (s *PullRequest) SetStatus(val PullRequestStatus) {
+	s.Status = val
+}
+
+// SetAssignedReviewers sets the value of AssignedReviewers.
+func (s *PullRequest) SetAssignedReviewers(val []string) {
+	s.AssignedReviewers = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *PullRequest) SetCreatedAt(val OptNilDateTime) {
+	s.CreatedAt = val
+}
+
+// SetMergedAt sets the value of MergedAt.
+func (s *PullRequest) SetMergedAt(val OptNilDateTime) {
+	s.MergedAt = val
+}
+
+// SetVersion sets the value of Version.
+func (s *PullRequest) SetVersion(val int64) {
+	s.Version = val
+}
+
+type PullRequestCreatePostConflict ErrorResponse
+
+func (*PullRequestCreatePostConflict) pullRequestCreatePostRes() {}
+
+type PullRequestCreatePostCreated struct {
+	Pr OptPullRequest `json:"pr"`
+}
+
+// GetPr returns the value of Pr.
+func (s *PullRequestCreatePostCreated) GetPr() OptPullRequest {
+	return s.Pr
+}
+
+// SetPr sets the value of Pr.
+func (s *PullRequestCreatePostCreated) SetPr(val OptPullRequest) {
+	s.Pr = val
+}
+
+// PullRequestCreatePostCreatedHeaders wraps PullRequestCreatePostCreated with response headers.
+type PullRequestCreatePostCreatedHeaders struct {
+	ETag     OptString
+	Response PullRequestCreatePostCreated
+}
+
+// GetETag returns the value of ETag.
+func (s *PullRequestCreatePostCreatedHeaders) GetETag() OptString {
+	return s.ETag
+}
+
+// GetResponse returns the value of Response.
+func (s *PullRequestCreatePostCreatedHeaders) GetResponse() PullRequestCreatePostCreated {
+	return s.Response
+}
+
+// SetETag sets the value of ETag.
+func (s *PullRequestCreatePostCreatedHeaders) SetETag(val OptString) {
+	s.ETag = val
+}
+
+// SetResponse sets the value of Response.
+func (s *PullRequestCreatePostCreatedHeaders) SetResponse(val PullRequestCreatePostCreated) {
+	s.Response = val
+}
+
+func (*PullRequestCreatePostCreatedHeaders) pullRequestCreatePostRes() {}
+
+type PullRequestCreatePostNotFound ErrorResponse
+
+func (*PullRequestCreatePostNotFound) pullRequestCreatePostRes() {}
+
+type PullRequestCreatePostReq struct {
+	PullRequestID   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	AuthorID        string `json:"author_id"`
+}
+
+// GetPullRequestID returns the value of PullRequestID.
+func (s *PullRequestCreatePostReq) GetPullRequestID() string {
+	return s.PullRequestID
+}
+
+// GetPullRequestName returns the value of PullRequestName.
+func (s *PullRequestCreatePostReq) GetPullRequestName() string {
+	return s.PullRequestName
+}
+
+// GetAuthorID returns the value of AuthorID.
+func (s *PullRequestCreatePostReq) GetAuthorID() string {
+	return s.AuthorID
+}
+
+// SetPullRequestID sets the value of PullRequestID.
+func (s *PullRequestCreatePostReq) SetPullRequestID(val string) {
+	s.PullRequestID = val
+}
+
+// SetPullRequestName sets the value of PullRequestName.
+func (s *PullRequestCreatePostReq) SetPullRequestName(val string) {
+	s.PullRequestName = val
+}
+
+// SetAuthorID sets the value of AuthorID.
+func (s *PullRequestCreatePostReq) SetAuthorID(val string) {
+	s.AuthorID = val
+}
+
+type PullRequestHistoryGetOK struct {
+	PullRequestID string          `json:"pull_request_id"`
+	Events        []PRHistoryItem `json:"events"`
+}
+
+// GetPullRequestID returns the value of PullRequestID.
+func (s *PullRequestHistoryGetOK) GetPullRequestID() string {
+	return s.PullRequestID
+}
+
+// GetEvents returns the value of Events.
+func (s *PullRequestHistoryGetOK) GetEvents() []PRHistoryItem {
+	return s.Events
+}
+
+// SetPullRequestID sets the value of PullRequestID.
+func (s *PullRequestHistoryGetOK) SetPullRequestID(val string) {
+	s.PullRequestID = val
+}
+
+// SetEvents sets the value of Events.
+func (s *PullRequestHistoryGetOK) SetEvents(val []PRHistoryItem) {
+	s.Events = val
+}
+
+func (*PullRequestHistoryGetOK) pullRequestHistoryGetRes() {}
+
+type PullRequestMergePostBadRequest ErrorResponse
+
+func (*PullRequestMergePostBadRequest) pullRequestMergePostRes() {}
+
+type PullRequestMergePostConflict ErrorResponse
+
+func (*PullRequestMergePostConflict) pullRequestMergePostRes() {}
+
+type PullRequestMergePostForbidden ErrorResponse
+
+func (*PullRequestMergePostForbidden) pullRequestMergePostRes() {}
+
+type PullRequestMergePostNotFound ErrorResponse
+
+func (*PullRequestMergePostNotFound) pullRequestMergePostRes() {}
+
+type PullRequestMergePostOK struct {
+	Pr OptPullRequest `json:"pr"`
+}
+
+// GetPr returns the value of Pr.
+func (s *PullRequestMergePostOK) GetPr() OptPullRequest {
+	return s.Pr
+}
+
+// SetPr sets the value of Pr.
+func (s *PullRequestMergePostOK) SetPr(val OptPullRequest) {
+	s.Pr = val
+}
+
+// PullRequestMergePostOKHeaders wraps PullRequestMergePostOK with response headers.
+type PullRequestMergePostOKHeaders struct {
+	ETag     OptString
+	Response PullRequestMergePostOK
+}
+
+// GetETag returns the value of ETag.
+func (s *PullRequestMergePostOKHeaders) GetETag() OptString {
+	return s.ETag
+}
+
+// GetResponse returns the value of Response.
+func (s *PullRequestMergePostOKHeaders) GetResponse() PullRequestMergePostOK {
+	return s.Response
+}
+
+// SetETag sets the value of ETag.
+func (s *PullRequestMergePostOKHeaders) SetETag(val OptString) {
+	s.ETag = val
+}
+
+// SetResponse sets the value of Response.
+func (s *PullRequestMergePostOKHeaders) SetResponse(val PullRequestMergePostOK) {
+	s.Response = val
+}
+
+func (*PullRequestMergePostOKHeaders) pullRequestMergePostRes() {}
+
+type PullRequestMergePostPreconditionFailed ErrorResponse
+
+func (*PullRequestMergePostPreconditionFailed) pullRequestMergePostRes() {}
+
+type PullRequestMergePostReq struct {
+	PullRequestID string `json:"pull_request_id"`
+	// Ожидаемая версия PR; при расхождении — 412.
+	Version OptInt64 `json:"version"`
+}
+
+// GetPullRequestID returns the value of PullRequestID.
+func (s *PullRequestMergePostReq) GetPullRequestID() string {
+	return s.PullRequestID
+}
+
+// GetVersion returns the value of Version.
+func (s *PullRequestMergePostReq) GetVersion() OptInt64 {
+	return s.Version
+}
+
+// SetPullRequestID sets the value of PullRequestID.
+func (s *PullRequestMergePostReq) SetPullRequestID(val string) {
+	s.PullRequestID = val
+}
+
+// SetVersion sets the value of Version.
+func (s *PullRequestMergePostReq) SetVersion(val OptInt64) {
+	s.Version = val
+}
+
+type PullRequestReassignPostBadRequest ErrorResponse
+
+func (*PullRequestReassignPostBadRequest) pullRequestReassignPostRes() {}
+
+type PullRequestReassignPostConflict ErrorResponse
+
+func (*PullRequestReassignPostConflict) pullRequestReassignPostRes() {}
+
+type PullRequestReassignPostForbidden ErrorResponse
+
+func (*PullRequestReassignPostForbidden) pullRequestReassignPostRes() {}
+
+type PullRequestReassignPostNotFound ErrorResponse
+
+func (*PullRequestReassignPostNotFound) pullRequestReassignPostRes() {}
+
+type PullRequestReassignPostOK struct {
+	Pr PullRequest `json:"pr"`
+	// User_id нового ревьювера.
+	ReplacedBy string `json:"replaced_by"`
+}
+
+// GetPr returns the value of Pr.
+func (s *PullRequestReassignPostOK) GetPr() PullRequest {
+	return s.Pr
+}
+
+// GetReplacedBy returns the value of ReplacedBy.
+func (s *PullRequestReassignPostOK) GetReplacedBy() string {
+	return s.ReplacedBy
+}
+
+// SetPr sets the value of Pr.
+func (s *PullRequestReassignPostOK) SetPr(val PullRequest) {
+	s.Pr = val
+}
+
+// SetReplacedBy sets the value of ReplacedBy.
+func (s *PullRequestReassignPostOK) SetReplacedBy(val string) {
+	s.ReplacedBy = val
+}
+
+// PullRequestReassignPostOKHeaders wraps PullRequestReassignPostOK with response headers.
+type PullRequestReassignPostOKHeaders struct {
+	ETag     OptString
+	Response PullRequestReassignPostOK
+}
+
+// GetETag returns the value of ETag.
+func (s *PullRequestReassignPostOKHeaders) GetETag() OptString {
+	return s.ETag
+}
+
+// GetResponse returns the value of Response.
+func (s *PullRequestReassignPostOKHeaders) GetResponse() PullRequestReassignPostOK {
+	return s.Response
+}
+
+// SetETag sets the value of ETag.
+func (s *PullRequestReassignPostOKHeaders) SetETag(val OptString) {
+	s.ETag = val
+}
+
+// SetResponse sets the value of Response.
+func (s *PullRequestReassignPostOKHeaders) SetResponse(val PullRequestReassignPostOK) {
+	s.Response = val
+}
+
+func (*PullRequestReassignPostOKHeaders) pullRequestReassignPostRes() {}
+
+type PullRequestReassignPostPreconditionFailed ErrorResponse
+
+func (*PullRequestReassignPostPreconditionFailed) pullRequestReassignPostRes() {}
+
+type PullRequestReassignPostReq struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldUserID     string `json:"old_user_id"`
+	// Почему ревьювера заменили; видна в истории PR.
+	Reason OptString `json:"reason"`
+	// Ожидаемая версия PR; при расхождении — 412.
+	Version OptInt64 `json:"version"`
+}
+
+// GetPullRequestID returns the value of PullRequestID.
+func (s *PullRequestReassignPostReq) GetPullRequestID() string {
+	return s.PullRequestID
+}
+
+// GetOldUserID returns the value of OldUserID.
+func (s *PullRequestReassignPostReq) GetOldUserID() string {
+	return s.OldUserID
+}
+
+// GetReason returns the value of Reason.
+func (s *PullRequestReassignPostReq) GetReason() OptString {
+	return s.Reason
+}
+
+// GetVersion returns the value of Version.
+func (s *PullRequestReassignPostReq) GetVersion() OptInt64 {
+	return s.Version
+}
+
+// SetPullRequestID sets the value of PullRequestID.
+func (s *PullRequestReassignPostReq) SetPullRequestID(val string) {
+	s.PullRequestID = val
+}
+
+// SetOldUserID sets the value of OldUserID.
+func (s *PullRequestReassignPostReq) SetOldUserID(val string) {
+	s.OldUserID = val
+}
+
+// SetReason sets the value of Reason.
+func (s *PullRequestReassignPostReq) SetReason(val OptString) {
+	s.Reason = val
+}
+
+// SetVersion sets the value of Version.
+func (s *PullRequestReassignPostReq) SetVersion(val OptInt64) {
+	s.Version = val
+}
+
+type PullRequestReviewPostBadRequest ErrorResponse
+
+func (*PullRequestReviewPostBadRequest) pullRequestReviewPostRes() {}
+
+type PullRequestReviewPostConflict ErrorResponse
+
+func (*PullRequestReviewPostConflict) pullRequestReviewPostRes() {}
+
+type PullRequestReviewPostForbidden ErrorResponse
+
+func (*PullRequestReviewPostForbidden) pullRequestReviewPostRes() {}
+
+type PullRequestReviewPostNotFound ErrorResponse
+
+func (*PullRequestReviewPostNotFound) pullRequestReviewPostRes() {}
+
+type PullRequestReviewPostOK struct {
+	Pr PullRequest `json:"pr"`
+}
+
+// GetPr returns the value of Pr.
+func (s *PullRequestReviewPostOK) GetPr() PullRequest {
+	return s.Pr
+}
+
+// SetPr sets the value of Pr.
+func (s *PullRequestReviewPostOK) SetPr(val PullRequest) {
+	s.Pr = val
+}
+
+// PullRequestReviewPostOKHeaders wraps PullRequestReviewPostOK with response headers.
+type PullRequestReviewPostOKHeaders struct {
+	ETag     OptString
+	Response PullRequestReviewPostOK
+}
+
+// GetETag returns the value of ETag.
+func (s *PullRequestReviewPostOKHeaders) GetETag() OptString {
+	return s.ETag
+}
+
+// GetResponse returns the value of Response.
+func (s *PullRequestReviewPostOKHeaders) GetResponse() PullRequestReviewPostOK {
+	return s.Response
+}
+
+// SetETag sets the value of ETag.
+func (s *PullRequestReviewPostOKHeaders) SetETag(val OptString) {
+	s.ETag = val
+}
+
+// SetResponse sets the value of Response.
+func (s *PullRequestReviewPostOKHeaders) SetResponse(val PullRequestReviewPostOK) {
+	s.Response = val
+}
+
+func (*PullRequestReviewPostOKHeaders) pullRequestReviewPostRes() {}
+
+type PullRequestReviewPostPreconditionFailed ErrorResponse
+
+func (*PullRequestReviewPostPreconditionFailed) pullRequestReviewPostRes() {}
+
+type PullRequestReviewPostReq struct {
+	PullRequestID string        `json:"pull_request_id"`
+	ReviewerID    string        `json:"reviewer_id"`
+	Verdict       ReviewVerdict `json:"verdict"`
+	// Ожидаемая версия PR; при расхождении — 412.
+	Version OptInt64 `json:"version"`
+}
+
+// GetPullRequestID returns the value of PullRequestID.
+func (s *PullRequestReviewPostReq) GetPullRequestID() string {
+	return s.PullRequestID
+}
+
+// GetReviewerID returns the value of ReviewerID.
+func (s *PullRequestReviewPostReq) GetReviewerID() string {
+	return s.ReviewerID
+}
+
+// GetVerdict returns the value of Verdict.
+func (s *PullRequestReviewPostReq) GetVerdict() ReviewVerdict {
+	return s.Verdict
+}
+
+// GetVersion returns the value of Version.
+func (s *PullRequestReviewPostReq) GetVersion() OptInt64 {
+	return s.Version
+}
+
+// SetPullRequestID sets the value of PullRequestID.
+func (s *PullRequestReviewPostReq) SetPullRequestID(val string) {
+	s.PullRequestID = val
 }
 
-// Get returns value and boolean that denotes whether value was set.
-func (o OptUser) Get() (v User, ok bool) {
-	if !o.Set {
-		return v, false
-	}
-	return o.Value, true
+// SetReviewerID sets the value of ReviewerID.
+func (s *PullRequestReviewPostReq) SetReviewerID(val string) {
+	s.ReviewerID = val
 }
 
-// Or returns value if set, or given parameter if does not.
-func (o OptUser) Or(d User) User {
-	if v, ok := o.Get(); ok {
-		return v
-	}
-	return d
+// SetVerdict sets the value of Verdict.
+func (s *PullRequestReviewPostReq) SetVerdict(val ReviewVerdict) {
+	s.Verdict = val
 }
 
-// Ref: #/components/schemas/PullRequest
-type PullRequest struct {
-	PullRequestID   string            `json:"pull_request_id"`
-	PullRequestName string            `json:"pull_request_name"`
-	AuthorID        string            `json:"author_id"`
-	Status          PullRequestStatus `json:"status"`
-	// User_id назначенных ревьюверов (0..2).
-	AssignedReviewers []string       `json:"assigned_reviewers"`
-	CreatedAt         OptNilDateTime `json:"createdAt"`
-	MergedAt          OptNilDateTime `json:"mergedAt"`
+// SetVersion sets the value of Version.
+func (s *PullRequestReviewPostReq) SetVersion(val OptInt64) {
+	s.Version = val
+}
+
+// Ref: #/components/schemas/PullRequestShort
+type PullRequestShort struct {
+	PullRequestID   string                 `json:"pull_request_id"`
+	PullRequestName string                 `json:"pull_request_name"`
+	AuthorID        string                 `json:"author_id"`
+	Status          PullRequestShortStatus `json:"status"`
 }
 
 // GetPullRequestID returns the value of PullRequestID.
-func (s *PullRequest) GetPullRequestID() string {
+func (s *PullRequestShort) GetPullRequestID() string {
 	return s.PullRequestID
 }
 
 // GetPullRequestName returns the value of PullRequestName.
-func (s *PullRequest) GetPullRequestName() string {
+func (s *PullRequestShort) GetPullRequestName() string {
 	return s.PullRequestName
 }
 
 // GetAuthorID returns the value of AuthorID.
-func (s *PullRequest) GetAuthorID() string {
+func (s *PullRequestShort) GetAuthorID() string {
 	return s.AuthorID
 }
 
 // GetStatus returns the value of Status.
-func (s *PullRequest) GetStatus() PullRequestStatus {
+func (s *PullRequestShort) GetStatus() PullRequestShortStatus {
 	return s.Status
 }
 
-// GetAssignedReviewers returns the value of AssignedReviewers.
-func (s *PullRequest) GetAssignedReviewers() []string {
-	return s.AssignedReviewers
-}
-
-// GetCreatedAt returns the value of CreatedAt.
-func (s *PullRequest) GetCreatedAt() OptNilDateTime {
-	return s.CreatedAt
-}
-
-// GetMergedAt returns the value of MergedAt.
-func (s *PullRequest) GetMergedAt() OptNilDateTime {
-	return s.MergedAt
-}
-
 // SetPullRequestID sets the value of PullRequestID.
-func (s *PullRequest) SetPullRequestID(val string) {
+func (s *PullRequestShort) SetPullRequestID(val string) {
 	s.PullRequestID = val
 }
 
 // SetPullRequestName sets the value of PullRequestName.
-func (s *PullRequest) SetPullRequestName(val string) {
+func (s *PullRequestShort) SetPullRequestName(val string) {
 	s.PullRequestName = val
 }
 
 // SetAuthorID sets the value of AuthorID.
-func (s *PullRequest) SetAuthorID(val string) {
+func (s *PullRequestShort) SetAuthorID(val string) {
 	s.AuthorID = val
 }
 
 // SetStatus sets the value of Status.
-func (s *PullRequest) SetStatus(val PullRequestStatus) {
+func (s *PullRequestShort) SetStatus(val PullRequestShortStatus) {
 	s.Status = val
 }
 
-// SetAssignedReviewers sets the value of AssignedReviewers.
-func (s *PullRequest) SetAssignedReviewers(val []string) {
-	s.AssignedReviewers = val
+type PullRequestShortStatus string
+
+const (
+	PullRequestShortStatusOPEN   PullRequestShortStatus = "OPEN"
+	PullRequestShortStatusMERGED PullRequestShortStatus = "MERGED"
+	PullRequestShortStatusCLOSED PullRequestShortStatus = "CLOSED"
+)
+
+// AllValues returns all PullRequestShortStatus values.
+func (PullRequestShortStatus) AllValues() []PullRequestShortStatus {
+	return []PullRequestShortStatus{
+		PullRequestShortStatusOPEN,
+		PullRequestShortStatusMERGED,
+		PullRequestShortStatusCLOSED,
+	}
 }
 
-// SetCreatedAt sets the value of CreatedAt.
-func (s *PullRequest) SetCreatedAt(val OptNilDateTime) {
-	s.CreatedAt = val
+// MarshalText implements encoding.TextMarshaler.
+func (s PullRequestShortStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case PullRequestShortStatusOPEN:
+		return []byte(s), nil
+	case PullRequestShortStatusMERGED:
+		return []byte(s), nil
+	case PullRequestShortStatusCLOSED:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
 }
 
-// SetMergedAt sets the value of MergedAt.
-func (s *PullRequest) SetMergedAt(val OptNilDateTime) {
-	s.MergedAt = val
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *PullRequestShortStatus) UnmarshalText(data []byte) error {
+	switch PullRequestShortStatus(data) {
+	case PullRequestShortStatusOPEN:
+		*s = PullRequestShortStatusOPEN
+		return nil
+	case PullRequestShortStatusMERGED:
+		*s = PullRequestShortStatusMERGED
+		return nil
+	case PullRequestShortStatusCLOSED:
+		*s = PullRequestShortStatusCLOSED
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
 }
 
-type PullRequestCreatePostConflict ErrorResponse
+type PullRequestStatus string
 
-func (*PullRequestCreatePostConflict) pullRequestCreatePostRes() {}
+const (
+	PullRequestStatusOPEN   PullRequestStatus = "OPEN"
+	PullRequestStatusMERGED PullRequestStatus = "MERGED"
+	PullRequestStatusCLOSED PullRequestStatus = "CLOSED"
+)
 
-type PullRequestCreatePostCreated struct {
-	Pr OptPullRequest `json:"pr"`
+// AllValues returns all PullRequestStatus values.
+func (PullRequestStatus) AllValues() []PullRequestStatus {
+	return []PullRequestStatus{
+		PullRequestStatusOPEN,
+		PullRequestStatusMERGED,
+		PullRequestStatusCLOSED,
+	}
 }
 
-// GetPr returns the value of Pr.
-func (s *PullRequestCreatePostCreated) GetPr() OptPullRequest {
-	return s.Pr
+// MarshalText implements encoding.TextMarshaler.
+func (s PullRequestStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case PullRequestStatusOPEN:
+		return []byte(s), nil
+	case PullRequestStatusMERGED:
+		return []byte(s), nil
+	case PullRequestStatusCLOSED:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
 }
 
-// SetPr sets the value of Pr.
-func (s *PullRequestCreatePostCreated) SetPr(val OptPullRequest) {
-	s.Pr = val
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *PullRequestStatus) UnmarshalText(data []byte) error {
+	switch PullRequestStatus(data) {
+	case PullRequestStatusOPEN:
+		*s = PullRequestStatusOPEN
+		return nil
+	case PullRequestStatusMERGED:
+		*s = PullRequestStatusMERGED
+		return nil
+	case PullRequestStatusCLOSED:
+		*s = PullRequestStatusCLOSED
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
 }
 
-func (*PullRequestCreatePostCreated) pullRequestCreatePostRes() {}
+// Ref: #/components/schemas/ReviewVerdict
+type ReviewVerdict string
 
-type PullRequestCreatePostNotFound ErrorResponse
+const (
+	ReviewVerdictApproved         ReviewVerdict = "approved"
+	ReviewVerdictChangesRequested ReviewVerdict = "changes_requested"
+)
 
-func (*PullRequestCreatePostNotFound) pullRequestCreatePostRes() {}
+// AllValues returns all ReviewVerdict values.
+func (ReviewVerdict) AllValues() []ReviewVerdict {
+	return []ReviewVerdict{
+		ReviewVerdictApproved,
+		ReviewVerdictChangesRequested,
+	}
+}
 
-type PullRequestCreatePostReq struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
+// MarshalText implements encoding.TextMarshaler.
+func (s ReviewVerdict) MarshalText() ([]byte, error) {
+	switch s {
+	case ReviewVerdictApproved:
+		return []byte(s), nil
+	case ReviewVerdictChangesRequested:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
 }
 
-// GetPullRequestID returns the value of PullRequestID.
-func (s *PullRequestCreatePostReq) GetPullRequestID() string {
-	return s.PullRequestID
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *ReviewVerdict) UnmarshalText(data []byte) error {
+	switch ReviewVerdict(data) {
+	case ReviewVerdictApproved:
+		*s = ReviewVerdictApproved
+		return nil
+	case ReviewVerdictChangesRequested:
+		*s = ReviewVerdictChangesRequested
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
 }
 
-// GetPullRequestName returns the value of PullRequestName.
-func (s *PullRequestCreatePostReq) GetPullRequestName() string {
-	return s.PullRequestName
+// Admin — любые действия; lead — руководитель своей команды;
+//
+//	member — участник.
+//
+// Ref: #/components/schemas/Role
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleLead   Role = "lead"
+	RoleMember Role = "member"
+)
+
+// AllValues returns all Role values.
+func (Role) AllValues() []Role {
+	return []Role{
+		RoleAdmin,
+		RoleLead,
+		RoleMember,
+	}
 }
 
-// GetAuthorID returns the value of AuthorID.
-func (s *PullRequestCreatePostReq) GetAuthorID() string {
-	return s.AuthorID
+// MarshalText implements encoding.TextMarshaler.
+func (s Role) MarshalText() ([]byte, error) {
+	switch s {
+	case RoleAdmin:
+		return []byte(s), nil
+	case RoleLead:
+		return []byte(s), nil
+	case RoleMember:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
 }
 
-// SetPullRequestID sets the value of PullRequestID.
-func (s *PullRequestCreatePostReq) SetPullRequestID(val string) {
-	s.PullRequestID = val
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Role) UnmarshalText(data []byte) error {
+	switch Role(data) {
+	case RoleAdmin:
+		*s = RoleAdmin
+		return nil
+	case RoleLead:
+		*s = RoleLead
+		return nil
+	case RoleMember:
+		*s = RoleMember
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
 }
 
-// SetPullRequestName sets the value of PullRequestName.
-func (s *PullRequestCreatePostReq) SetPullRequestName(val string) {
-	s.PullRequestName = val
+// Подписанная выгрузка ревью PR. signature — Ed25519 от record в
+// каноническом JSON (ключи по алфавиту, без пробелов).
+// Ref: #/components/schemas/SignedPRRecord
+type SignedPRRecord struct {
+	// Org_id, pull_request_id, exported_at, pull_request (состояние
+	// на момент выгрузки), audit_entries (записи журнала PR с prev_hash
+	// и hash, от старых к новым), history (события PR) и chain_head
+	// (последняя запись цепочки организации).
+	Record jx.Raw `json:"record"`
+	// Первые 8 байт SHA-256 публичного ключа, hex.
+	KeyID string `json:"key_id"`
+	// Публичный ключ Ed25519, base64.
+	PublicKey string `json:"public_key"`
+	// Подпись, base64.
+	Signature string `json:"signature"`
 }
 
-// SetAuthorID sets the value of AuthorID.
-func (s *PullRequestCreatePostReq) SetAuthorID(val string) {
-	s.AuthorID = val
+// GetRecord returns the value of Record.
+func (s *SignedPRRecord) GetRecord() jx.Raw {
+	return s.Record
 }
 
-type PullRequestMergePostOK struct {
-	Pr OptPullRequest `json:"pr"`
+// GetKeyID returns the value of KeyID.
+func (s *SignedPRRecord) GetKeyID() string {
+	return s.KeyID
 }
 
-// GetPr returns the value of Pr.
-func (s *PullRequestMergePostOK) GetPr() OptPullRequest {
-	return s.Pr
+// GetPublicKey returns the value of PublicKey.
+func (s *SignedPRRecord) GetPublicKey() string {
+	return s.PublicKey
 }
 
-// SetPr sets the value of Pr.
-func (s *PullRequestMergePostOK) SetPr(val OptPullRequest) {
-	s.Pr = val
+// GetSignature returns the value of Signature.
+func (s *SignedPRRecord) GetSignature() string {
+	return s.Signature
 }
 
-func (*PullRequestMergePostOK) pullRequestMergePostRes() {}
+// SetRecord sets the value of Record.
+func (s *SignedPRRecord) SetRecord(val jx.Raw) {
+	s.Record = val
+}
 
-type PullRequestMergePostReq struct {
-	PullRequestID string `json:"pull_request_id"`
+// SetKeyID sets the value of KeyID.
+func (s *SignedPRRecord) SetKeyID(val string) {
+	s.KeyID = val
 }
 
-// GetPullRequestID returns the value of PullRequestID.
-func (s *PullRequestMergePostReq) GetPullRequestID() string {
-	return s.PullRequestID
+// SetPublicKey sets the value of PublicKey.
+func (s *SignedPRRecord) SetPublicKey(val string) {
+	s.PublicKey = val
 }
 
-// SetPullRequestID sets the value of PullRequestID.
-func (s *PullRequestMergePostReq) SetPullRequestID(val string) {
-	s.PullRequestID = val
+// SetSignature sets the value of Signature.
+func (s *SignedPRRecord) SetSignature(val string) {
+	s.Signature = val
 }
 
-type PullRequestReassignPostConflict ErrorResponse
+func (*SignedPRRecord) auditExportGetRes() {}
 
-func (*PullRequestReassignPostConflict) pullRequestReassignPostRes() {}
+// Ref: #/components/schemas/Subscription
+type Subscription struct {
+	SubscriptionID string `json:"subscription_id"`
+	URL            string `json:"url"`
+	// Ключ подписи. Возвращается только при создании.
+	Secret     OptString   `json:"secret"`
+	EventTypes []EventType `json:"event_types"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
 
-type PullRequestReassignPostNotFound ErrorResponse
+// GetSubscriptionID returns the value of SubscriptionID.
+func (s *Subscription) GetSubscriptionID() string {
+	return s.SubscriptionID
+}
 
-func (*PullRequestReassignPostNotFound) pullRequestReassignPostRes() {}
+// GetURL returns the value of URL.
+func (s *Subscription) GetURL() string {
+	return s.URL
+}
 
-type PullRequestReassignPostOK struct {
-	Pr PullRequest `json:"pr"`
-	// User_id нового ревьювера.
-	ReplacedBy string `json:"replaced_by"`
+// GetSecret returns the value of Secret.
+func (s *Subscription) GetSecret() OptString {
+	return s.Secret
+}
+
+// GetEventTypes returns the value of EventTypes.
+func (s *Subscription) GetEventTypes() []EventType {
+	return s.EventTypes
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *Subscription) GetCreatedAt() time.Time {
+	return s.CreatedAt
+}
+
+// SetSubscriptionID sets the value of SubscriptionID.
+func (s *Subscription) SetSubscriptionID(val string) {
+	s.SubscriptionID = val
+}
+
+// SetURL sets the value of URL.
+func (s *Subscription) SetURL(val string) {
+	s.URL = val
 }
 
-// GetPr returns the value of Pr.
-func (s *PullRequestReassignPostOK) GetPr() PullRequest {
-	return s.Pr
+// SetSecret sets the value of Secret.
+func (s *Subscription) SetSecret(val OptString) {
+	s.Secret = val
 }
 
-// GetReplacedBy returns the value of ReplacedBy.
-func (s *PullRequestReassignPostOK) GetReplacedBy() string {
-	return s.ReplacedBy
+// SetEventTypes sets the value of EventTypes.
+func (s *Subscription) SetEventTypes(val []EventType) {
+	s.EventTypes = val
 }
 
-// SetPr sets the value of Pr.
-func (s *PullRequestReassignPostOK) SetPr(val PullRequest) {
-	s.Pr = val
+// SetCreatedAt sets the value of CreatedAt.
+func (s *Subscription) SetCreatedAt(val time.Time) {
+	s.CreatedAt = val
 }
 
-// SetReplacedBy sets the value of ReplacedBy.
-func (s *PullRequestReassignPostOK) SetReplacedBy(val string) {
-	s.ReplacedBy = val
+type SubscriptionsCreatePostCreated struct {
+	Subscription Subscription `json:"subscription"`
 }
 
-func (*PullRequestReassignPostOK) pullRequestReassignPostRes() {}
+// GetSubscription returns the value of Subscription.
+func (s *SubscriptionsCreatePostCreated) GetSubscription() Subscription {
+	return s.Subscription
+}
 
-type PullRequestReassignPostReq struct {
-	PullRequestID string `json:"pull_request_id"`
-	OldUserID     string `json:"old_user_id"`
+// SetSubscription sets the value of Subscription.
+func (s *SubscriptionsCreatePostCreated) SetSubscription(val Subscription) {
+	s.Subscription = val
 }
 
-// GetPullRequestID returns the value of PullRequestID.
-func (s *PullRequestReassignPostReq) GetPullRequestID() string {
-	return s.PullRequestID
+func (*SubscriptionsCreatePostCreated) subscriptionsCreatePostRes() {}
+
+type SubscriptionsCreatePostReq struct {
+	URL string `json:"url"`
+	// Ключ подписи; если не задан, будет сгенерирован.
+	Secret     OptString   `json:"secret"`
+	EventTypes []EventType `json:"event_types"`
 }
 
-// GetOldUserID returns the value of OldUserID.
-func (s *PullRequestReassignPostReq) GetOldUserID() string {
-	return s.OldUserID
+// GetURL returns the value of URL.
+func (s *SubscriptionsCreatePostReq) GetURL() string {
+	return s.URL
 }
 
-// SetPullRequestID sets the value of PullRequestID.
-func (s *PullRequestReassignPostReq) SetPullRequestID(val string) {
-	s.PullRequestID = val
+// GetSecret returns the value of Secret.
+func (s *SubscriptionsCreatePostReq) GetSecret() OptString {
+	return s.Secret
 }
 
-// SetOldUserID sets the value of OldUserID.
-func (s *PullRequestReassignPostReq) SetOldUserID(val string) {
-	s.OldUserID = val
+// GetEventTypes returns the value of EventTypes.
+func (s *SubscriptionsCreatePostReq) GetEventTypes() []EventType {
+	return s.EventTypes
 }
 
-// Ref: #/components/schemas/PullRequestShort
-type PullRequestShort struct {
-	PullRequestID   string                 `json:"pull_request_id"`
-	PullRequestName string                 `json:"pull_request_name"`
-	AuthorID        string                 `json:"author_id"`
-	Status          PullRequestShortStatus `json:"status"`
+// SetURL sets the value of URL.
+func (s *SubscriptionsCreatePostReq) SetURL(val string) {
+	s.URL = val
 }
 
-// GetPullRequestID returns the value of PullRequestID.
-func (s *PullRequestShort) GetPullRequestID() string {
-	return s.PullRequestID
+// SetSecret sets the value of Secret.
+func (s *SubscriptionsCreatePostReq) SetSecret(val OptString) {
+	s.Secret = val
 }
 
-// GetPullRequestName returns the value of PullRequestName.
-func (s *PullRequestShort) GetPullRequestName() string {
-	return s.PullRequestName
+// SetEventTypes sets the value of EventTypes.
+func (s *SubscriptionsCreatePostReq) SetEventTypes(val []EventType) {
+	s.EventTypes = val
 }
 
-// GetAuthorID returns the value of AuthorID.
-func (s *PullRequestShort) GetAuthorID() string {
-	return s.AuthorID
+// SubscriptionsDeletePostNoContent is response for SubscriptionsDeletePost operation.
+type SubscriptionsDeletePostNoContent struct{}
+
+func (*SubscriptionsDeletePostNoContent) subscriptionsDeletePostRes() {}
+
+type SubscriptionsDeletePostReq struct {
+	SubscriptionID string `json:"subscription_id"`
 }
 
-// GetStatus returns the value of Status.
-func (s *PullRequestShort) GetStatus() PullRequestShortStatus {
-	return s.Status
+// GetSubscriptionID returns the value of SubscriptionID.
+func (s *SubscriptionsDeletePostReq) GetSubscriptionID() string {
+	return s.SubscriptionID
 }
 
-// SetPullRequestID sets the value of PullRequestID.
-func (s *PullRequestShort) SetPullRequestID(val string) {
-	s.PullRequestID = val
+// SetSubscriptionID sets the value of SubscriptionID.
+func (s *SubscriptionsDeletePostReq) SetSubscriptionID(val string) {
+	s.SubscriptionID = val
 }
 
-// SetPullRequestName sets the value of PullRequestName.
-func (s *PullRequestShort) SetPullRequestName(val string) {
-	s.PullRequestName = val
+type SubscriptionsDeliveriesGetOK struct {
+	Deliveries []EventDelivery `json:"deliveries"`
 }
 
-// SetAuthorID sets the value of AuthorID.
-func (s *PullRequestShort) SetAuthorID(val string) {
-	s.AuthorID = val
+// GetDeliveries returns the value of Deliveries.
+func (s *SubscriptionsDeliveriesGetOK) GetDeliveries() []EventDelivery {
+	return s.Deliveries
 }
 
-// SetStatus sets the value of Status.
-func (s *PullRequestShort) SetStatus(val PullRequestShortStatus) {
-	s.Status = val
+// SetDeliveries sets the value of Deliveries.
+func (s *SubscriptionsDeliveriesGetOK) SetDeliveries(val []EventDelivery) {
+	s.Deliveries = val
 }
 
-type PullRequestShortStatus string
+func (*SubscriptionsDeliveriesGetOK) subscriptionsDeliveriesGetRes() {}
 
-const (
-	PullRequestShortStatusOPEN   PullRequestShortStatus = "OPEN"
-	PullRequestShortStatusMERGED PullRequestShortStatus = "MERGED"
-)
+type SubscriptionsListGetOK struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+}
 
-// AllValues returns all PullRequestShortStatus values.
-func (PullRequestShortStatus) AllValues() []PullRequestShortStatus {
-	return []PullRequestShortStatus{
-		PullRequestShortStatusOPEN,
-		PullRequestShortStatusMERGED,
-	}
+// GetSubscriptions returns the value of Subscriptions.
+func (s *SubscriptionsListGetOK) GetSubscriptions() []Subscription {
+	return s.Subscriptions
 }
 
-// MarshalText implements encoding.TextMarshaler.
-func (s PullRequestShortStatus) MarshalText() ([]byte, error) {
-	switch s {
-	case PullRequestShortStatusOPEN:
-		return []byte(s), nil
-	case PullRequestShortStatusMERGED:
-		return []byte(s), nil
-	default:
-		return nil, errors.Errorf("invalid value: %q", s)
-	}
+// SetSubscriptions sets the value of Subscriptions.
+func (s *SubscriptionsListGetOK) SetSubscriptions(val []Subscription) {
+	s.Subscriptions = val
 }
 
-// UnmarshalText implements encoding.TextUnmarshaler.
-func (s *PullRequestShortStatus) UnmarshalText(data []byte) error {
-	switch PullRequestShortStatus(data) {
-	case PullRequestShortStatusOPEN:
-		*s = PullRequestShortStatusOPEN
-		return nil
-	case PullRequestShortStatusMERGED:
-		*s = PullRequestShortStatusMERGED
-		return nil
-	default:
-		return errors.Errorf("invalid value: %q", data)
-	}
+type SubscriptionsRedeliverPostAccepted struct {
+	Delivery EventDelivery `json:"delivery"`
 }
 
-type PullRequestStatus string
+// GetDelivery returns the value of Delivery.
+func (s *SubscriptionsRedeliverPostAccepted) GetDelivery() EventDelivery {
+	return s.Delivery
+}
 
-const (
-	PullRequestStatusOPEN   PullRequestStatus = "OPEN"
-	PullRequestStatusMERGED PullRequestStatus = "MERGED"
-)
+// SetDelivery sets the value of Delivery.
+func (s *SubscriptionsRedeliverPostAccepted) SetDelivery(val EventDelivery) {
+	s.Delivery = val
+}
 
-// AllValues returns all PullRequestStatus values.
-func (PullRequestStatus) AllValues() []PullRequestStatus {
-	return []PullRequestStatus{
-		PullRequestStatusOPEN,
-		PullRequestStatusMERGED,
-	}
+func (*SubscriptionsRedeliverPostAccepted) subscriptionsRedeliverPostRes() {}
+
+type SubscriptionsRedeliverPostReq struct {
+	DeliveryID int64 `json:"delivery_id"`
 }
 
-// MarshalText implements encoding.TextMarshaler.
-func (s PullRequestStatus) MarshalText() ([]byte, error) {
-	switch s {
-	case PullRequestStatusOPEN:
-		return []byte(s), nil
-	case PullRequestStatusMERGED:
-		return []byte(s), nil
-	default:
-		return nil, errors.Errorf("invalid value: %q", s)
-	}
+// GetDeliveryID returns the value of DeliveryID.
+func (s *SubscriptionsRedeliverPostReq) GetDeliveryID() int64 {
+	return s.DeliveryID
 }
 
-// UnmarshalText implements encoding.TextUnmarshaler.
-func (s *PullRequestStatus) UnmarshalText(data []byte) error {
-	switch PullRequestStatus(data) {
-	case PullRequestStatusOPEN:
-		*s = PullRequestStatusOPEN
-		return nil
-	case PullRequestStatusMERGED:
-		*s = PullRequestStatusMERGED
-		return nil
-	default:
-		return errors.Errorf("invalid value: %q", data)
-	}
+// SetDeliveryID sets the value of DeliveryID.
+func (s *SubscriptionsRedeliverPostReq) SetDeliveryID(val int64) {
+	s.DeliveryID = val
 }
 
 // Ref: #/components/schemas/Team
 type Team struct {
-	TeamName string       `json:"team_name"`
+	TeamName string `json:"team_name"`
+	// Включено ли правило четырёх глаз для PR авторов
+	// команды; задаётся через /team/setPolicy.
+	FourEyes OptBool      `json:"four_eyes"`
 	Members  []TeamMember `json:"members"`
 }
 
@@ -697,6 +2608,11 @@ func (s *Team) GetTeamName() string {
 	return s.TeamName
 }
 
+// GetFourEyes returns the value of FourEyes.
+func (s *Team) GetFourEyes() OptBool {
+	return s.FourEyes
+}
+
 // GetMembers returns the value of Members.
 func (s *Team) GetMembers() []TeamMember {
 	return s.Members
@@ -707,6 +2623,11 @@ func (s *Team) SetTeamName(val string) {
 	s.TeamName = val
 }
 
+// SetFourEyes sets the value of FourEyes.
+func (s *Team) SetFourEyes(val OptBool) {
+	s.FourEyes = val
+}
+
 // SetMembers sets the value of Members.
 func (s *Team) SetMembers(val []TeamMember) {
 	s.Members = val
@@ -714,6 +2635,10 @@ func (s *Team) SetMembers(val []TeamMember) {
 
 func (*Team) teamGetGetRes() {}
 
+type TeamAddPostBadRequest ErrorResponse
+
+func (*TeamAddPostBadRequest) teamAddPostRes() {}
+
 type TeamAddPostCreated struct {
 	Team OptTeam `json:"team"`
 }
@@ -730,11 +2655,29 @@ func (s *TeamAddPostCreated) SetTeam(val OptTeam) {
 
 func (*TeamAddPostCreated) teamAddPostRes() {}
 
+type TeamAddPostForbidden ErrorResponse
+
+func (*TeamAddPostForbidden) teamAddPostRes() {}
+
 // Ref: #/components/schemas/TeamMember
 type TeamMember struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID   string  `json:"user_id"`
+	Username string  `json:"username"`
+	IsActive bool    `json:"is_active"`
+	Role     OptRole `json:"role"`
+	// Адрес для уведомлений; пустое значение при upsert
+	// сохраняет прежний.
+	Email OptString `json:"email"`
+	// Часовой пояс IANA (например, Europe/Berlin) для ежедневного
+	// дайджеста; пустое значение при upsert сохраняет прежний.
+	Timezone OptString `json:"timezone"`
+	// До какого момента пользователь отсутствует; задаётся
+	// через /users/setAway.
+	AwayUntil OptDateTime `json:"away_until"`
+	// User_id руководителя — задаёт линию подчинения для
+	// правила четырёх глаз; пустое значение при upsert
+	// сохраняет прежний.
+	ManagerID OptString `json:"manager_id"`
 }
 
 // GetUserID returns the value of UserID.
@@ -752,6 +2695,31 @@ func (s *TeamMember) GetIsActive() bool {
 	return s.IsActive
 }
 
+// GetRole returns the value of Role.
+func (s *TeamMember) GetRole() OptRole {
+	return s.Role
+}
+
+// GetEmail returns the value of Email.
+func (s *TeamMember) GetEmail() OptString {
+	return s.Email
+}
+
+// GetTimezone returns the value of Timezone.
+func (s *TeamMember) GetTimezone() OptString {
+	return s.Timezone
+}
+
+// GetAwayUntil returns the value of AwayUntil.
+func (s *TeamMember) GetAwayUntil() OptDateTime {
+	return s.AwayUntil
+}
+
+// GetManagerID returns the value of ManagerID.
+func (s *TeamMember) GetManagerID() OptString {
+	return s.ManagerID
+}
+
 // SetUserID sets the value of UserID.
 func (s *TeamMember) SetUserID(val string) {
 	s.UserID = val
@@ -767,12 +2735,91 @@ func (s *TeamMember) SetIsActive(val bool) {
 	s.IsActive = val
 }
 
+// SetRole sets the value of Role.
+func (s *TeamMember) SetRole(val OptRole) {
+	s.Role = val
+}
+
+// SetEmail sets the value of Email.
+func (s *TeamMember) SetEmail(val OptString) {
+	s.Email = val
+}
+
+// SetTimezone sets the value of Timezone.
+func (s *TeamMember) SetTimezone(val OptString) {
+	s.Timezone = val
+}
+
+// SetAwayUntil sets the value of AwayUntil.
+func (s *TeamMember) SetAwayUntil(val OptDateTime) {
+	s.AwayUntil = val
+}
+
+// SetManagerID sets the value of ManagerID.
+func (s *TeamMember) SetManagerID(val OptString) {
+	s.ManagerID = val
+}
+
+type TeamSetPolicyPostForbidden ErrorResponse
+
+func (*TeamSetPolicyPostForbidden) teamSetPolicyPostRes() {}
+
+type TeamSetPolicyPostNotFound ErrorResponse
+
+func (*TeamSetPolicyPostNotFound) teamSetPolicyPostRes() {}
+
+type TeamSetPolicyPostOK struct {
+	Team Team `json:"team"`
+}
+
+// GetTeam returns the value of Team.
+func (s *TeamSetPolicyPostOK) GetTeam() Team {
+	return s.Team
+}
+
+// SetTeam sets the value of Team.
+func (s *TeamSetPolicyPostOK) SetTeam(val Team) {
+	s.Team = val
+}
+
+func (*TeamSetPolicyPostOK) teamSetPolicyPostRes() {}
+
+type TeamSetPolicyPostReq struct {
+	TeamName string `json:"team_name"`
+	FourEyes bool   `json:"four_eyes"`
+}
+
+// GetTeamName returns the value of TeamName.
+func (s *TeamSetPolicyPostReq) GetTeamName() string {
+	return s.TeamName
+}
+
+// GetFourEyes returns the value of FourEyes.
+func (s *TeamSetPolicyPostReq) GetFourEyes() bool {
+	return s.FourEyes
+}
+
+// SetTeamName sets the value of TeamName.
+func (s *TeamSetPolicyPostReq) SetTeamName(val string) {
+	s.TeamName = val
+}
+
+// SetFourEyes sets the value of FourEyes.
+func (s *TeamSetPolicyPostReq) SetFourEyes(val bool) {
+	s.FourEyes = val
+}
+
 // Ref: #/components/schemas/User
 type User struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID    string      `json:"user_id"`
+	Username  string      `json:"username"`
+	TeamName  string      `json:"team_name"`
+	IsActive  bool        `json:"is_active"`
+	Role      OptRole     `json:"role"`
+	Email     OptString   `json:"email"`
+	Timezone  OptString   `json:"timezone"`
+	AwayUntil OptDateTime `json:"away_until"`
+	ManagerID OptString   `json:"manager_id"`
 }
 
 // GetUserID returns the value of UserID.
@@ -795,6 +2842,31 @@ func (s *User) GetIsActive() bool {
 	return s.IsActive
 }
 
+// GetRole returns the value of Role.
+func (s *User) GetRole() OptRole {
+	return s.Role
+}
+
+// GetEmail returns the value of Email.
+func (s *User) GetEmail() OptString {
+	return s.Email
+}
+
+// GetTimezone returns the value of Timezone.
+func (s *User) GetTimezone() OptString {
+	return s.Timezone
+}
+
+// GetAwayUntil returns the value of AwayUntil.
+func (s *User) GetAwayUntil() OptDateTime {
+	return s.AwayUntil
+}
+
+// GetManagerID returns the value of ManagerID.
+func (s *User) GetManagerID() OptString {
+	return s.ManagerID
+}
+
 // SetUserID sets the value of UserID.
 func (s *User) SetUserID(val string) {
 	s.UserID = val
@@ -815,6 +2887,31 @@ func (s *User) SetIsActive(val bool) {
 	s.IsActive = val
 }
 
+// SetRole sets the value of Role.
+func (s *User) SetRole(val OptRole) {
+	s.Role = val
+}
+
+// SetEmail sets the value of Email.
+func (s *User) SetEmail(val OptString) {
+	s.Email = val
+}
+
+// SetTimezone sets the value of Timezone.
+func (s *User) SetTimezone(val OptString) {
+	s.Timezone = val
+}
+
+// SetAwayUntil sets the value of AwayUntil.
+func (s *User) SetAwayUntil(val OptDateTime) {
+	s.AwayUntil = val
+}
+
+// SetManagerID sets the value of ManagerID.
+func (s *User) SetManagerID(val OptString) {
+	s.ManagerID = val
+}
+
 type UsersGetReviewGetOK struct {
 	UserID       string             `json:"user_id"`
 	PullRequests []PullRequestShort `json:"pull_requests"`
@@ -840,6 +2937,65 @@ func (s *UsersGetReviewGetOK) SetPullRequests(val []PullRequestShort) {
 	s.PullRequests = val
 }
 
+type UsersSetAwayPostForbidden ErrorResponse
+
+func (*UsersSetAwayPostForbidden) usersSetAwayPostRes() {}
+
+type UsersSetAwayPostNotFound ErrorResponse
+
+func (*UsersSetAwayPostNotFound) usersSetAwayPostRes() {}
+
+type UsersSetAwayPostOK struct {
+	User OptUser `json:"user"`
+}
+
+// GetUser returns the value of User.
+func (s *UsersSetAwayPostOK) GetUser() OptUser {
+	return s.User
+}
+
+// SetUser sets the value of User.
+func (s *UsersSetAwayPostOK) SetUser(val OptUser) {
+	s.User = val
+}
+
+func (*UsersSetAwayPostOK) usersSetAwayPostRes() {}
+
+type UsersSetAwayPostReq struct {
+	UserID string `json:"user_id"`
+	// До какого момента; null или отсутствие поля —
+	// пользователь на месте.
+	AwayUntil OptNilDateTime `json:"away_until"`
+}
+
+// GetUserID returns the value of UserID.
+func (s *UsersSetAwayPostReq) GetUserID() string {
+	return s.UserID
+}
+
+// GetAwayUntil returns the value of AwayUntil.
+func (s *UsersSetAwayPostReq) GetAwayUntil() OptNilDateTime {
+	return s.AwayUntil
+}
+
+// SetUserID sets the value of UserID.
+func (s *UsersSetAwayPostReq) SetUserID(val string) {
+	s.UserID = val
+}
+
+// SetAwayUntil sets the value of AwayUntil.
+func (s *UsersSetAwayPostReq) SetAwayUntil(val OptNilDateTime) {
+	s.AwayUntil = val
+}
+
+type UsersSetIsActivePostForbidden ErrorResponse
+
+func (*UsersSetIsActivePostForbidden) usersSetIsActivePostRes() {}
+
+type UsersSetIsActivePostNotFound ErrorResponse
+
+func (*UsersSetIsActivePostNotFound) usersSetIsActivePostRes() {}
+
 type UsersSetIsActivePostOK struct {
 	User OptUser `json:"user"`
 }
@@ -880,3 +3036,11 @@ func (s *UsersSetIsActivePostReq) SetUserID(val string) {
 func (s *UsersSetIsActivePostReq) SetIsActive(val bool) {
 	s.IsActive = val
 }
+
+type UsersSetNotificationPrefsPostForbidden ErrorResponse
+
+func (*UsersSetNotificationPrefsPostForbidden) usersSetNotificationPrefsPostRes() {}
+
+type UsersSetNotificationPrefsPostNotFound ErrorResponse
+
+func (*UsersSetNotificationPrefsPostNotFound) usersSetNotificationPrefsPostRes() {}
@@ -0,0 +1,140 @@
+// Code generated by ogen, DO NOT EDIT.
+
+package pr
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-faster/errors"
+	"github.com/ogen-go/ogen/ogenerrors"
+)
+
+// SecurityHandler is handler for security parameters.
+type SecurityHandler interface {
+	// HandleBearerAuth handles bearerAuth security.
+	// API-токен, выданный командой `pr-reviewer token issue`, либо JWT
+	// внешнего провайдера (если настроен JWKS, см. README).
+	// Список в требовании безопасности операции —
+	// необходимые scope'ы токена
+	// (`admin` включает все остальные). Без токена — 401, без
+	// нужного scope — 403.
+	HandleBearerAuth(ctx context.Context, operationName OperationName, t BearerAuth) (context.Context, error)
+}
+
+func findAuthorization(h http.Header, prefix string) (string, bool) {
+	v, ok := h["Authorization"]
+	if !ok {
+		return "", false
+	}
+	for _, vv := range v {
+		scheme, value, ok := strings.Cut(vv, " ")
+		if !ok || !strings.EqualFold(scheme, prefix) {
+			continue
+		}
+		return value, true
+	}
+	return "", false
+}
+
+var operationRolesBearerAuth = map[string][]string{
+	AuditExportGetOperation: []string{
+		"admin",
+	},
+	AuditListGetOperation: []string{
+		"admin",
+	},
+	PullRequestCreatePostOperation: []string{
+		"prs:write",
+	},
+	PullRequestHistoryGetOperation: []string{
+		"read",
+	},
+	PullRequestMergePostOperation: []string{
+		"prs:write",
+	},
+	PullRequestReassignPostOperation: []string{
+		"prs:write",
+	},
+	PullRequestReviewPostOperation: []string{
+		"prs:write",
+	},
+	SubscriptionsCreatePostOperation: []string{
+		"admin",
+	},
+	SubscriptionsDeletePostOperation: []string{
+		"admin",
+	},
+	SubscriptionsDeliveriesGetOperation: []string{
+		"admin",
+	},
+	SubscriptionsListGetOperation: []string{
+		"admin",
+	},
+	SubscriptionsRedeliverPostOperation: []string{
+		"admin",
+	},
+	TeamAddPostOperation: []string{
+		"teams:write",
+	},
+	TeamGetGetOperation: []string{
+		"read",
+	},
+	TeamSetPolicyPostOperation: []string{
+		"teams:write",
+	},
+	UsersGetNotificationPrefsGetOperation: []string{
+		"read",
+	},
+	UsersGetReviewGetOperation: []string{
+		"read",
+	},
+	UsersSetAwayPostOperation: []string{
+		"users:write",
+	},
+	UsersSetIsActivePostOperation: []string{
+		"users:write",
+	},
+	UsersSetNotificationPrefsPostOperation: []string{
+		"users:write",
+	},
+}
+
+func (s *Server) securityBearerAuth(ctx context.Context, operationName OperationName, req *http.Request) (context.Context, bool, error) {
+	var t BearerAuth
+	token, ok := findAuthorization(req.Header, "Bearer")
+	if !ok {
+		return ctx, false, nil
+	}
+	t.Token = token
+	t.Roles = operationRolesBearerAuth[operationName]
+	rctx, err := s.sec.HandleBearerAuth(ctx, operationName, t)
+	if errors.Is(err, ogenerrors.ErrSkipServerSecurity) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return rctx, true, err
+}
+
+// SecuritySource is provider of security values (tokens, passwords, etc.).
+type SecuritySource interface {
+	// BearerAuth provides bearerAuth security value.
+	// API-токен, выданный командой `pr-reviewer token issue`, либо JWT
+	// внешнего провайдера (если настроен JWKS, см. README).
+	// Список в требовании безопасности операции —
+	// необходимые scope'ы токена
+	// (`admin` включает все остальные). Без токена — 401, без
+	// нужного scope — 403.
+	BearerAuth(ctx context.Context, operationName OperationName) (BearerAuth, error)
+}
+
+func (s *Client) securityBearerAuth(ctx context.Context, operationName OperationName, req *http.Request) error {
+	t, err := s.sec.BearerAuth(ctx, operationName)
+	if err != nil {
+		return errors.Wrap(err, "security source \"BearerAuth\"")
+	}
+	req.Header.Set("Authorization", "Bearer "+t.Token)
+	return nil
+}
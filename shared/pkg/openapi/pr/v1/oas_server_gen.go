@@ -8,6 +8,18 @@ import (
 
 // Handler handles operations described by OpenAPI v3 specification.
 type Handler interface {
+	// AuditExportGet implements GET /audit/export operation.
+	//
+	// Требует AUDIT_SIGNING_KEY, без него — 501.
+	//
+	// GET /audit/export
+	AuditExportGet(ctx context.Context, params AuditExportGetParams) (AuditExportGetRes, error)
+	// AuditListGet implements GET /audit/list operation.
+	//
+	// Журнал изменений организации, новые первыми.
+	//
+	// GET /audit/list
+	AuditListGet(ctx context.Context, params AuditListGetParams) (AuditListGetRes, error)
 	// PullRequestCreatePost implements POST /pullRequest/create operation.
 	//
 	// Создать PR и автоматически назначить до 2 ревьюверов
@@ -15,19 +27,64 @@ type Handler interface {
 	//
 	// POST /pullRequest/create
 	PullRequestCreatePost(ctx context.Context, req *PullRequestCreatePostReq) (PullRequestCreatePostRes, error)
+	// PullRequestHistoryGet implements GET /pullRequest/history operation.
+	//
+	// История PR — создание, переназначения, решения
+	// ревьюверов и merge по порядку.
+	//
+	// GET /pullRequest/history
+	PullRequestHistoryGet(ctx context.Context, params PullRequestHistoryGetParams) (PullRequestHistoryGetRes, error)
 	// PullRequestMergePost implements POST /pullRequest/merge operation.
 	//
 	// Пометить PR как MERGED (идемпотентная операция).
 	//
 	// POST /pullRequest/merge
-	PullRequestMergePost(ctx context.Context, req *PullRequestMergePostReq) (PullRequestMergePostRes, error)
+	PullRequestMergePost(ctx context.Context, req *PullRequestMergePostReq, params PullRequestMergePostParams) (PullRequestMergePostRes, error)
 	// PullRequestReassignPost implements POST /pullRequest/reassign operation.
 	//
 	// Переназначить конкретного ревьювера на другого из
 	// его команды.
 	//
 	// POST /pullRequest/reassign
-	PullRequestReassignPost(ctx context.Context, req *PullRequestReassignPostReq) (PullRequestReassignPostRes, error)
+	PullRequestReassignPost(ctx context.Context, req *PullRequestReassignPostReq, params PullRequestReassignPostParams) (PullRequestReassignPostRes, error)
+	// PullRequestReviewPost implements POST /pullRequest/review operation.
+	//
+	// Записать решение назначенного ревьювера (последнее
+	// решение заменяет прежнее).
+	//
+	// POST /pullRequest/review
+	PullRequestReviewPost(ctx context.Context, req *PullRequestReviewPostReq, params PullRequestReviewPostParams) (PullRequestReviewPostRes, error)
+	// SubscriptionsCreatePost implements POST /subscriptions/create operation.
+	//
+	// Подписать URL на события (POST с подписью HMAC-SHA256).
+	//
+	// POST /subscriptions/create
+	SubscriptionsCreatePost(ctx context.Context, req *SubscriptionsCreatePostReq) (SubscriptionsCreatePostRes, error)
+	// SubscriptionsDeletePost implements POST /subscriptions/delete operation.
+	//
+	// Удалить подписку вместе с журналом доставок.
+	//
+	// POST /subscriptions/delete
+	SubscriptionsDeletePost(ctx context.Context, req *SubscriptionsDeletePostReq) (SubscriptionsDeletePostRes, error)
+	// SubscriptionsDeliveriesGet implements GET /subscriptions/deliveries operation.
+	//
+	// Журнал доставок подписки, новые первыми.
+	//
+	// GET /subscriptions/deliveries
+	SubscriptionsDeliveriesGet(ctx context.Context, params SubscriptionsDeliveriesGetParams) (SubscriptionsDeliveriesGetRes, error)
+	// SubscriptionsListGet implements GET /subscriptions/list operation.
+	//
+	// Список подписок организации (без ключей подписи).
+	//
+	// GET /subscriptions/list
+	SubscriptionsListGet(ctx context.Context) (*SubscriptionsListGetOK, error)
+	// SubscriptionsRedeliverPost implements POST /subscriptions/redeliver operation.
+	//
+	// Отправить событие доставки повторно (создаёт новую
+	// доставку).
+	//
+	// POST /subscriptions/redeliver
+	SubscriptionsRedeliverPost(ctx context.Context, req *SubscriptionsRedeliverPostReq) (SubscriptionsRedeliverPostRes, error)
 	// TeamAddPost implements POST /team/add operation.
 	//
 	// Создать команду с участниками (создаёт/обновляет
@@ -41,35 +98,63 @@ type Handler interface {
 	//
 	// GET /team/get
 	TeamGetGet(ctx context.Context, params TeamGetGetParams) (TeamGetGetRes, error)
+	// TeamSetPolicyPost implements POST /team/setPolicy operation.
+	//
+	// Включить или выключить правило четырёх глаз для
+	// мерджа PR авторов команды.
+	//
+	// POST /team/setPolicy
+	TeamSetPolicyPost(ctx context.Context, req *TeamSetPolicyPostReq) (TeamSetPolicyPostRes, error)
+	// UsersGetNotificationPrefsGet implements GET /users/getNotificationPrefs operation.
+	//
+	// Получить настройки email-уведомлений пользователя.
+	//
+	// GET /users/getNotificationPrefs
+	UsersGetNotificationPrefsGet(ctx context.Context, params UsersGetNotificationPrefsGetParams) (UsersGetNotificationPrefsGetRes, error)
 	// UsersGetReviewGet implements GET /users/getReview operation.
 	//
 	// Получить PR'ы, где пользователь назначен ревьювером.
 	//
 	// GET /users/getReview
 	UsersGetReviewGet(ctx context.Context, params UsersGetReviewGetParams) (*UsersGetReviewGetOK, error)
+	// UsersSetAwayPost implements POST /users/setAway operation.
+	//
+	// Пока пользователь отсутствует, ежедневный дайджест
+	// ему не отправляется.
+	//
+	// POST /users/setAway
+	UsersSetAwayPost(ctx context.Context, req *UsersSetAwayPostReq) (UsersSetAwayPostRes, error)
 	// UsersSetIsActivePost implements POST /users/setIsActive operation.
 	//
 	// Установить флаг активности пользователя.
 	//
 	// POST /users/setIsActive
 	UsersSetIsActivePost(ctx context.Context, req *UsersSetIsActivePostReq) (UsersSetIsActivePostRes, error)
+	// UsersSetNotificationPrefsPost implements POST /users/setNotificationPrefs operation.
+	//
+	// Задать настройки email-уведомлений пользователя.
+	//
+	// POST /users/setNotificationPrefs
+	UsersSetNotificationPrefsPost(ctx context.Context, req *NotificationPrefs) (UsersSetNotificationPrefsPostRes, error)
 }
 
 // Server implements http server based on OpenAPI v3 specification and
 // calls Handler to handle requests.
 type Server struct {
-	h Handler
+	h   Handler
+	sec SecurityHandler
 	baseServer
 }
 
 // NewServer creates new Server.
-func NewServer(h Handler, opts ...ServerOption) (*Server, error) {
+func NewServer(h Handler, sec SecurityHandler, opts ...ServerOption) (*Server, error) {
 	s, err := newServerConfig(opts...).baseServer()
 	if err != nil {
 		return nil, err
 	}
 	return &Server{
 		h:          h,
+		sec:        sec,
 		baseServer: s,
 	}, nil
 }
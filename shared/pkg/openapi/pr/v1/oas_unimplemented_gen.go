@@ -13,6 +13,24 @@ type UnimplementedHandler struct{}
 
 var _ Handler = UnimplementedHandler{}
 
+// AuditExportGet implements GET /audit/export operation.
+//
+// Требует AUDIT_SIGNING_KEY, без него — 501.
+//
+// GET /audit/export
+func (UnimplementedHandler) AuditExportGet(ctx context.Context, params AuditExportGetParams) (r AuditExportGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// AuditListGet implements GET /audit/list operation.
+//
+// Журнал изменений организации, новые первыми.
+//
+// GET /audit/list
+func (UnimplementedHandler) AuditListGet(ctx context.Context, params AuditListGetParams) (r AuditListGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // PullRequestCreatePost implements POST /pullRequest/create operation.
 //
 // Создать PR и автоматически назначить до 2 ревьюверов
@@ -23,12 +41,22 @@ func (UnimplementedHandler) PullRequestCreatePost(ctx context.Context, req *Pull
 	return r, ht.ErrNotImplemented
 }
 
+// PullRequestHistoryGet implements GET /pullRequest/history operation.
+//
+// История PR — создание, переназначения, решения
+// ревьюверов и merge по порядку.
+//
+// GET /pullRequest/history
+func (UnimplementedHandler) PullRequestHistoryGet(ctx context.Context, params PullRequestHistoryGetParams) (r PullRequestHistoryGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // PullRequestMergePost implements POST /pullRequest/merge operation.
 //
 // Пометить PR как MERGED (идемпотентная операция).
 //
 // POST /pullRequest/merge
-func (UnimplementedHandler) PullRequestMergePost(ctx context.Context, req *PullRequestMergePostReq) (r PullRequestMergePostRes, _ error) {
+func (UnimplementedHandler) PullRequestMergePost(ctx context.Context, req *PullRequestMergePostReq, params PullRequestMergePostParams) (r PullRequestMergePostRes, _ error) {
 	return r, ht.ErrNotImplemented
 }
 
@@ -38,7 +66,63 @@ func (UnimplementedHandler) PullRequestMergePost(ctx context.Context, req *PullR
 // его команды.
 //
 // POST /pullRequest/reassign
-func (UnimplementedHandler) PullRequestReassignPost(ctx context.Context, req *PullRequestReassignPostReq) (r PullRequestReassignPostRes, _ error) {
+func (UnimplementedHandler) PullRequestReassignPost(ctx context.Context, req *PullRequestReassignPostReq, params PullRequestReassignPostParams) (r PullRequestReassignPostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// PullRequestReviewPost implements POST /pullRequest/review operation.
+//
+// Записать решение назначенного ревьювера (последнее
+// решение заменяет прежнее).
+//
+// POST /pullRequest/review
+func (UnimplementedHandler) PullRequestReviewPost(ctx context.Context, req *PullRequestReviewPostReq, params PullRequestReviewPostParams) (r PullRequestReviewPostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// SubscriptionsCreatePost implements POST /subscriptions/create operation.
+//
+// Подписать URL на события (POST с подписью HMAC-SHA256).
+//
+// POST /subscriptions/create
+func (UnimplementedHandler) SubscriptionsCreatePost(ctx context.Context, req *SubscriptionsCreatePostReq) (r SubscriptionsCreatePostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// SubscriptionsDeletePost implements POST /subscriptions/delete operation.
+//
+// Удалить подписку вместе с журналом доставок.
+//
+// POST /subscriptions/delete
+func (UnimplementedHandler) SubscriptionsDeletePost(ctx context.Context, req *SubscriptionsDeletePostReq) (r SubscriptionsDeletePostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// SubscriptionsDeliveriesGet implements GET /subscriptions/deliveries operation.
+//
+// Журнал доставок подписки, новые первыми.
+//
+// GET /subscriptions/deliveries
+func (UnimplementedHandler) SubscriptionsDeliveriesGet(ctx context.Context, params SubscriptionsDeliveriesGetParams) (r SubscriptionsDeliveriesGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// SubscriptionsListGet implements GET /subscriptions/list operation.
+//
+// Список подписок организации (без ключей подписи).
+//
+// GET /subscriptions/list
+func (UnimplementedHandler) SubscriptionsListGet(ctx context.Context) (r *SubscriptionsListGetOK, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// SubscriptionsRedeliverPost implements POST /subscriptions/redeliver operation.
+//
+// Отправить событие доставки повторно (создаёт новую
+// доставку).
+//
+// POST /subscriptions/redeliver
+func (UnimplementedHandler) SubscriptionsRedeliverPost(ctx context.Context, req *SubscriptionsRedeliverPostReq) (r SubscriptionsRedeliverPostRes, _ error) {
 	return r, ht.ErrNotImplemented
 }
 
@@ -61,6 +145,25 @@ func (UnimplementedHandler) TeamGetGet(ctx context.Context, params TeamGetGetPar
 	return r, ht.ErrNotImplemented
 }
 
+// TeamSetPolicyPost implements POST /team/setPolicy operation.
+//
+// Включить или выключить правило четырёх глаз для
+// мерджа PR авторов команды.
+//
+// POST /team/setPolicy
+func (UnimplementedHandler) TeamSetPolicyPost(ctx context.Context, req *TeamSetPolicyPostReq) (r TeamSetPolicyPostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// UsersGetNotificationPrefsGet implements GET /users/getNotificationPrefs operation.
+//
+// Получить настройки email-уведомлений пользователя.
+//
+// GET /users/getNotificationPrefs
+func (UnimplementedHandler) UsersGetNotificationPrefsGet(ctx context.Context, params UsersGetNotificationPrefsGetParams) (r UsersGetNotificationPrefsGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // UsersGetReviewGet implements GET /users/getReview operation.
 //
 // Получить PR'ы, где пользователь назначен ревьювером.
@@ -70,6 +173,16 @@ func (UnimplementedHandler) UsersGetReviewGet(ctx context.Context, params UsersG
 	return r, ht.ErrNotImplemented
 }
 
+// UsersSetAwayPost implements POST /users/setAway operation.
+//
+// Пока пользователь отсутствует, ежедневный дайджест
+// ему не отправляется.
+//
+// POST /users/setAway
+func (UnimplementedHandler) UsersSetAwayPost(ctx context.Context, req *UsersSetAwayPostReq) (r UsersSetAwayPostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // UsersSetIsActivePost implements POST /users/setIsActive operation.
 //
 // Установить флаг активности пользователя.
@@ -78,3 +191,12 @@ func (UnimplementedHandler) UsersGetReviewGet(ctx context.Context, params UsersG
 func (UnimplementedHandler) UsersSetIsActivePost(ctx context.Context, req *UsersSetIsActivePostReq) (r UsersSetIsActivePostRes, _ error) {
 	return r, ht.ErrNotImplemented
 }
+
+// UsersSetNotificationPrefsPost implements POST /users/setNotificationPrefs operation.
+//
+// Задать настройки email-уведомлений пользователя.
+//
+// POST /users/setNotificationPrefs
+func (UnimplementedHandler) UsersSetNotificationPrefsPost(ctx context.Context, req *NotificationPrefs) (r UsersSetNotificationPrefsPostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}